@@ -0,0 +1,222 @@
+// Command eval 离线评估提示词和模型
+//
+// 用法：
+//
+//	go run ./cmd/eval run -dataset evals/task_breakdown.yaml -model gpt-4o-mini -out evals/runs/head.json
+//	go run ./cmd/eval run -dataset evals/task_breakdown.yaml -prompt-version 1.1.0 -templates ./drafts -baseline evals/runs/base.json
+//	go run ./cmd/eval compare -base evals/runs/base.json -head evals/runs/head.json
+//
+// 存在变差的用例时以退出码 1 结束（可用于 CI）。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/eval"
+	promptrepo "github.com/erweixin/go-genai-stack/backend/domains/prompt/repository"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/templates"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/bootstrap"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+)
+
+const usage = `Usage:
+  eval run     -dataset <file> [-prompt-version <v>] [-provider <name>] [-model <name>] [-out <file>] [-baseline <file>]
+  eval compare -base <file> -head <file> [-tolerance <n>]
+
+Run "eval <command> -h" for all flags.`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		regressed bool
+		err       error
+	)
+	switch os.Args[1] {
+	case "run":
+		regressed, err = runCommand(ctx, os.Args[2:])
+	case "compare":
+		regressed, err = compareCommand(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Printf("❌ %v", err)
+		os.Exit(2)
+	}
+	if regressed {
+		os.Exit(1)
+	}
+}
+
+// runCommand 运行数据集，返回是否比基线变差
+func runCommand(ctx context.Context, args []string) (bool, error) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	datasetPath := fs.String("dataset", "", "dataset file (YAML, required)")
+	promptVersion := fs.String("prompt-version", "", "prompt version to evaluate (default: active version)")
+	providerName := fs.String("provider", "", "provider to evaluate (default: APP_LLM_DEFAULT_PROVIDER)")
+	modelName := fs.String("model", "", "model to evaluate (default: the prompt template's model, then APP_LLM_DEFAULT_MODEL)")
+	judgeProvider := fs.String("judge-provider", "", "provider for judge checks (default: -provider)")
+	judgeModel := fs.String("judge-model", "", "model for judge checks (default: APP_LLM_DEFAULT_MODEL)")
+	templatesDir := fs.String("templates", "", "directory with draft prompt templates (*.yaml) to evaluate before creating the version")
+	useDB := fs.Bool("db", false, "read prompt versions and pins from the database")
+	concurrency := fs.Int("concurrency", eval.DefaultConcurrency, "cases to run at the same time")
+	out := fs.String("out", "", "write the JSON report to this file")
+	baseline := fs.String("baseline", "", "compare with this JSON report and exit 1 on regressions")
+	tolerance := fs.Float64("tolerance", eval.DefaultTolerance, "allowed score drop before a case counts as regressed")
+	_ = fs.Parse(args)
+
+	if *datasetPath == "" {
+		return false, fmt.Errorf("-dataset is required")
+	}
+	if *useDB && *templatesDir != "" {
+		return false, fmt.Errorf("-db and -templates cannot be used together")
+	}
+	ds, err := eval.LoadDataset(*datasetPath)
+	if err != nil {
+		return false, err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return false, err
+	}
+	registry, defaultProvider := bootstrap.InitLLMProviders(cfg.LLM)
+	if *providerName == "" {
+		*providerName = defaultProvider
+	}
+	p, err := registry.Get(*providerName)
+	if err != nil {
+		return false, err
+	}
+
+	renderer, closeDB, err := newRenderer(ctx, cfg, *useDB, *templatesDir)
+	if err != nil {
+		return false, err
+	}
+	defer closeDB()
+
+	model := *modelName
+	if model == "" && ds.Prompt == "" {
+		model = cfg.LLM.DefaultModel
+	}
+	runner := eval.NewRunner(p, model, renderer).WithConcurrency(*concurrency)
+	if ds.NeedsJudge() {
+		if *judgeProvider == "" {
+			*judgeProvider = *providerName
+		}
+		if *judgeModel == "" {
+			*judgeModel = cfg.LLM.DefaultModel
+		}
+		jp, err := registry.Get(*judgeProvider)
+		if err != nil {
+			return false, fmt.Errorf("judge: %w", err)
+		}
+		runner.WithJudge(&eval.Judge{Provider: jp, Model: *judgeModel})
+	}
+
+	log.Printf("🧪 Running dataset %s (%d cases) on %s", ds.Name, len(ds.Cases), *providerName)
+	report, err := runner.Run(ctx, ds, *promptVersion)
+	if err != nil {
+		return false, err
+	}
+	report.WriteText(os.Stdout)
+
+	if *out != "" {
+		if err := report.Save(*out); err != nil {
+			return false, err
+		}
+		log.Printf("✅ Report written to %s", *out)
+	}
+
+	if *baseline == "" {
+		return false, nil
+	}
+	base, err := eval.LoadReport(*baseline)
+	if err != nil {
+		return false, err
+	}
+	fmt.Println()
+	cmp := eval.Compare(base, report, *tolerance)
+	cmp.WriteText(os.Stdout)
+	return cmp.Regressed(), nil
+}
+
+// compareCommand 比较两份报告，返回是否变差
+func compareCommand(args []string) (bool, error) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	basePath := fs.String("base", "", "baseline JSON report (required)")
+	headPath := fs.String("head", "", "new JSON report (required)")
+	tolerance := fs.Float64("tolerance", eval.DefaultTolerance, "allowed score drop before a case counts as regressed")
+	_ = fs.Parse(args)
+
+	if *basePath == "" || *headPath == "" {
+		return false, fmt.Errorf("-base and -head are required")
+	}
+	base, err := eval.LoadReport(*basePath)
+	if err != nil {
+		return false, err
+	}
+	head, err := eval.LoadReport(*headPath)
+	if err != nil {
+		return false, err
+	}
+	if base.Dataset != head.Dataset {
+		log.Printf("⚠️  Comparing different datasets: %s vs %s", base.Dataset, head.Dataset)
+	}
+
+	cmp := eval.Compare(base, head, *tolerance)
+	cmp.WriteText(os.Stdout)
+	return cmp.Regressed(), nil
+}
+
+// newRenderer 创建提示词渲染器（内置模板 + 草稿目录，或内置模板 + 数据库）
+func newRenderer(ctx context.Context, cfg *config.Config, useDB bool, templatesDir string) (eval.Renderer, func(), error) {
+	embedded, err := promptservice.LoadTemplates(templates.FS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load embedded prompts: %w", err)
+	}
+
+	if !useDB {
+		repo, err := loadDrafts(templatesDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load draft prompts: %w", err)
+		}
+		prompts := promptservice.NewPromptService(repo, embedded, cfg.LLM.PromptCacheTTL)
+		return &promptRenderer{prompts: prompts, defaultModel: cfg.LLM.DefaultModel}, func() {}, nil
+	}
+
+	dbConn, err := bootstrap.InitDatabase(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to database: %w", err)
+	}
+	repo := promptrepo.NewPromptRepository(dbConn.DB(), dbConn.Type())
+	prompts := promptservice.NewPromptService(repo, embedded, cfg.LLM.PromptCacheTTL)
+	return &promptRenderer{prompts: prompts, defaultModel: cfg.LLM.DefaultModel}, func() { dbConn.Close() }, nil
+}
+
+// loadConfig 加载配置（与服务相同：先加载 docker/.env，失败时使用现有环境变量）
+func loadConfig() (*config.Config, error) {
+	_ = godotenv.Load("../docker/.env")
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+)
+
+// promptRenderer 通过提示词服务渲染（实现 eval.Renderer）
+type promptRenderer struct {
+	prompts      *promptservice.PromptService
+	defaultModel string // 模板没有指定模型时使用
+}
+
+func (r *promptRenderer) Render(ctx context.Context, name, version string, vars map[string]string) (*llmmodel.ChatRequest, string, error) {
+	rendered, err := r.prompts.Render(ctx, promptservice.RenderInput{Name: name, Version: version, Variables: vars})
+	if err != nil {
+		return nil, "", fmt.Errorf("render %s@%s: %w", name, version, err)
+	}
+	req := rendered.ChatRequest()
+	if req.Model == "" {
+		req.Model = r.defaultModel
+	}
+	return req, rendered.Version, nil
+}
+
+// memoryRepository 只读的内存提示词仓储（不连接数据库时使用）
+//
+// 保存 -templates 目录中的模板，用于在创建版本之前评估草稿。
+type memoryRepository struct {
+	templates []*model.PromptTemplate
+}
+
+// loadDrafts 加载目录中的提示词模板（dir 为空时没有草稿）
+func loadDrafts(dir string) (*memoryRepository, error) {
+	repo := &memoryRepository{}
+	if dir == "" {
+		return repo, nil
+	}
+	templates, err := promptservice.LoadTemplates(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	repo.templates = templates
+	return repo, nil
+}
+
+func (r *memoryRepository) Create(context.Context, *model.PromptTemplate) error {
+	return fmt.Errorf("read-only prompt repository")
+}
+
+func (r *memoryRepository) List(context.Context) ([]*model.PromptTemplate, error) {
+	return r.templates, nil
+}
+
+func (r *memoryRepository) ListPins(context.Context) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (r *memoryRepository) SetPin(context.Context, string, string) error {
+	return fmt.Errorf("read-only prompt repository")
+}
+
+func (r *memoryRepository) DeletePin(context.Context, string) error {
+	return fmt.Errorf("read-only prompt repository")
+}
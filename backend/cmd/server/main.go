@@ -77,6 +77,13 @@ func main() {
 	container := bootstrap.InitDependencies(cfg, dbConn, redisConn)
 	log.Println("✅ Domain services initialized")
 
+	// 4.1. 模型目录为空时写入内置模型
+	container.SeedModelCatalog(ctx)
+
+	// 4.5. 启动后台任务（推迟到期调度器等，随 ctx 取消而停止）
+	container.StartBackgroundJobs(ctx)
+	defer container.EventBus.Close()
+
 	// 5. 创建 HTTP 服务器
 	log.Println("🚀 Starting HTTP server...")
 	h := bootstrap.CreateServer(cfg)
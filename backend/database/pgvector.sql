@@ -0,0 +1,32 @@
+-- pgvector.sql
+-- 向量存储（APP_LLM_VECTOR_STORE=pgvector 时需要）
+--
+-- 依赖 pgvector 扩展（镜像 pgvector/pgvector:pg16，或在已有实例上安装扩展），
+-- 因此不放在 schema.sql 中：默认的 postgres 镜像没有该扩展，开发和测试环境使用内存存储。
+--
+-- 应用：
+--   psql "$DATABASE_URL" -f backend/database/pgvector.sql
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- embeddings 表：语义搜索的向量（由事件同步，可以随时删除后重建）
+CREATE TABLE IF NOT EXISTS embeddings (
+    collection VARCHAR(50) NOT NULL,
+    id VARCHAR(100) NOT NULL,
+    owner_id UUID NOT NULL,
+    model VARCHAR(150) NOT NULL,
+    embedding vector NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (collection, id)
+);
+
+-- 索引：查询总是限定集合和用户，在用户的记录中逐条比较
+CREATE INDEX IF NOT EXISTS idx_embeddings_owner ON embeddings(collection, owner_id, model);
+
+-- 注释
+COMMENT ON TABLE embeddings IS 'Vectors for semantic search, kept in sync from domain events (derived data)';
+COMMENT ON COLUMN embeddings.collection IS 'Source type, e.g. tasks';
+COMMENT ON COLUMN embeddings.id IS 'Source record ID (e.g. task ID)';
+COMMENT ON COLUMN embeddings.model IS 'Embedding model; vectors are only compared within the same model';
+COMMENT ON COLUMN embeddings.embedding IS 'Unconstrained dimension so the embedding model can change; add an HNSW index on a fixed-dimension cast if per-user scans get slow';
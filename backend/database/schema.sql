@@ -95,6 +95,8 @@ CREATE TABLE tasks (
     created_at TIMESTAMPTZ NOT NULL,
     updated_at TIMESTAMPTZ NOT NULL,
     completed_at TIMESTAMPTZ,
+    parent_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+    hidden_until TIMESTAMPTZ,
     
     -- 约束
     CONSTRAINT tasks_title_not_empty CHECK (LENGTH(TRIM(title)) > 0),
@@ -113,6 +115,8 @@ CREATE INDEX idx_tasks_due_date ON tasks(due_date) WHERE due_date IS NOT NULL;
 CREATE INDEX idx_tasks_created_at ON tasks(created_at DESC);
 CREATE INDEX idx_tasks_completed_at ON tasks(completed_at DESC) WHERE completed_at IS NOT NULL;
 CREATE INDEX idx_tasks_user_status ON tasks(user_id, status);
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX idx_tasks_hidden_until ON tasks(hidden_until) WHERE hidden_until IS NOT NULL;
 
 -- 注释
 COMMENT ON TABLE tasks IS 'Task domain - stores todo/task items';
@@ -126,6 +130,8 @@ COMMENT ON COLUMN tasks.due_date IS 'Due date (optional)';
 COMMENT ON COLUMN tasks.created_at IS 'Creation timestamp';
 COMMENT ON COLUMN tasks.updated_at IS 'Last update timestamp';
 COMMENT ON COLUMN tasks.completed_at IS 'Completion timestamp (only when status=completed)';
+COMMENT ON COLUMN tasks.parent_id IS 'Parent task ID (only for subtasks, one level deep)';
+COMMENT ON COLUMN tasks.hidden_until IS 'Snoozed until (hidden from default list; cleared by the scheduler when expired)';
 
 -- task_tags 表：存储任务标签（多对多关系）
 CREATE TABLE task_tags (
@@ -148,19 +154,294 @@ COMMENT ON COLUMN task_tags.task_id IS 'Task ID (foreign key)';
 COMMENT ON COLUMN task_tags.tag_name IS 'Tag name (max 50 chars)';
 COMMENT ON COLUMN task_tags.tag_color IS 'Tag color (hex code, e.g. #FF5733)';
 
+-- task_templates 表：存储用户的任务模板
+CREATE TABLE task_templates (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title_pattern VARCHAR(200) NOT NULL,
+    description TEXT,
+    priority VARCHAR(10) NOT NULL CHECK (priority IN ('low', 'medium', 'high')),
+    tags JSONB NOT NULL DEFAULT '[]',
+    due_offset_seconds BIGINT,
+    subtasks JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    
+    -- 约束
+    CONSTRAINT task_templates_name_not_empty CHECK (LENGTH(TRIM(name)) > 0),
+    CONSTRAINT task_templates_title_not_empty CHECK (LENGTH(TRIM(title_pattern)) > 0),
+    CONSTRAINT task_templates_due_offset_positive CHECK (due_offset_seconds IS NULL OR due_offset_seconds > 0)
+);
+
+-- 索引
+CREATE INDEX idx_task_templates_user_id ON task_templates(user_id, created_at DESC);
+
+-- 注释
+COMMENT ON TABLE task_templates IS 'Task templates - reusable task blueprints per user';
+COMMENT ON COLUMN task_templates.title_pattern IS 'Title pattern with {{variables}} (e.g. {{date}}, {{name}})';
+COMMENT ON COLUMN task_templates.tags IS 'Tag names (JSON array)';
+COMMENT ON COLUMN task_templates.due_offset_seconds IS 'Due date offset relative to instantiation time (seconds, optional)';
+COMMENT ON COLUMN task_templates.subtasks IS 'Subtask definitions (JSON array of {title_pattern, description, priority, due_offset_seconds})';
+
+-- 触发器：自动更新 updated_at
+CREATE TRIGGER update_task_templates_updated_at
+    BEFORE UPDATE ON task_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- urgency_settings 表：存储用户自定义的任务紧急度系数
+CREATE TABLE urgency_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    coefficients JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- 注释
+COMMENT ON TABLE urgency_settings IS 'Per-user urgency coefficients (missing row means defaults)';
+COMMENT ON COLUMN urgency_settings.coefficients IS 'Urgency coefficients (JSON object, e.g. {"priority_high": 6.0, "due": 12.0, "tag_boosts": {"next": 15.0}})';
+
+-- task_suggestions 表：AI 生成的标签和优先级建议（每个任务最多一条，用户接受或拒绝后保留用于统计准确率）
+CREATE TABLE task_suggestions (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL UNIQUE REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    tags JSONB NOT NULL DEFAULT '[]',
+    priority VARCHAR(10) NOT NULL CHECK (priority IN ('low', 'medium', 'high')),
+    original_priority VARCHAR(10) NOT NULL CHECK (original_priority IN ('low', 'medium', 'high')),
+    model VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'accepted', 'rejected')),
+    accepted_tags JSONB NOT NULL DEFAULT '[]',
+    priority_accepted BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+-- 索引
+CREATE INDEX idx_task_suggestions_user_status ON task_suggestions(user_id, status);
+
+-- 注释
+COMMENT ON TABLE task_suggestions IS 'LLM tag/priority suggestions for new tasks (pending until the user accepts or rejects)';
+COMMENT ON COLUMN task_suggestions.tags IS 'Suggested tag names (JSON array, drawn from the user''s existing tags)';
+COMMENT ON COLUMN task_suggestions.original_priority IS 'Task priority when the suggestion was generated';
+COMMENT ON COLUMN task_suggestions.accepted_tags IS 'Suggested tags the user kept (JSON array)';
+COMMENT ON COLUMN task_suggestions.priority_accepted IS 'Whether the suggested priority was applied';
+
 -- ============================================
--- Extension Points (commented out, for reference)
+-- Chat Domain Tables
 -- ============================================
 
--- LLM Domain (未实现)
--- CREATE TABLE llm_models (
---     id UUID PRIMARY KEY,
---     name VARCHAR(100) NOT NULL,
---     provider VARCHAR(50) NOT NULL,
---     pricing_input DECIMAL(10, 6),
---     pricing_output DECIMAL(10, 6),
---     created_at TIMESTAMPTZ NOT NULL
--- );
+-- conversations 表：存储用户的对话
+CREATE TABLE conversations (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    model VARCHAR(100),
+    provider VARCHAR(50),
+    message_count INTEGER NOT NULL DEFAULT 0,
+    context_strategy VARCHAR(20) CHECK (context_strategy IN ('truncate', 'summarize', 'sliding_window')),
+    context_window_size INTEGER,
+    summary TEXT,
+    summary_until UUID,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    
+    -- 约束
+    CONSTRAINT conversations_title_not_empty CHECK (LENGTH(TRIM(title)) > 0),
+    CONSTRAINT conversations_message_count_non_negative CHECK (message_count >= 0),
+    CONSTRAINT conversations_context_window_size_range CHECK (context_window_size BETWEEN 1 AND 50)
+);
+
+-- 索引
+CREATE INDEX idx_conversations_user_id ON conversations(user_id, updated_at DESC);
+
+-- 注释
+COMMENT ON TABLE conversations IS 'Chat conversations per user';
+COMMENT ON COLUMN conversations.model IS 'Model used for replies (NULL means the configured default)';
+COMMENT ON COLUMN conversations.provider IS 'LLM provider used for replies (NULL means the configured default)';
+COMMENT ON COLUMN conversations.message_count IS 'Number of messages in the conversation (denormalized)';
+COMMENT ON COLUMN conversations.context_strategy IS 'How history beyond the model context window is handled (NULL means truncate)';
+COMMENT ON COLUMN conversations.context_window_size IS 'Messages kept by the sliding_window strategy (NULL means the default of 20)';
+COMMENT ON COLUMN conversations.summary IS 'Running summary of earlier messages (summarize strategy)';
+COMMENT ON COLUMN conversations.summary_until IS 'Last message ID covered by the summary';
+
+-- 触发器：自动更新 updated_at
+CREATE TRIGGER update_conversations_updated_at
+    BEFORE UPDATE ON conversations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- messages 表：存储对话中的消息
+CREATE TABLE messages (
+    id UUID PRIMARY KEY,
+    conversation_id UUID NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('user', 'assistant', 'system', 'tool')),
+    content TEXT NOT NULL,
+    model VARCHAR(100),
+    provider VARCHAR(50),
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    tool_calls TEXT,
+    tool_call_id VARCHAR(100),
+    tool_name VARCHAR(64),
+    citations TEXT,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+-- 索引
+CREATE INDEX idx_messages_conversation_id ON messages(conversation_id, created_at);
+
+-- 注释
+COMMENT ON TABLE messages IS 'Chat messages (user prompts, system instructions, assistant replies and tool results)';
+COMMENT ON COLUMN messages.model IS 'Model that generated the message (assistant messages only)';
+COMMENT ON COLUMN messages.input_tokens IS 'Prompt tokens consumed to generate this message (assistant messages only)';
+COMMENT ON COLUMN messages.output_tokens IS 'Completion tokens of this message (assistant messages only)';
+COMMENT ON COLUMN messages.latency_ms IS 'Generation latency in milliseconds (assistant messages only)';
+COMMENT ON COLUMN messages.truncated IS 'Streamed reply was cut off before completion (client disconnect or upstream error)';
+COMMENT ON COLUMN messages.tool_calls IS 'JSON array of tool calls requested by the assistant (agent messages only)';
+COMMENT ON COLUMN messages.tool_call_id IS 'Tool call answered by this message (tool messages only)';
+COMMENT ON COLUMN messages.tool_name IS 'Name of the tool that produced this result (tool messages only)';
+COMMENT ON COLUMN messages.citations IS 'JSON array of sources cited by a grounded reply (assistant messages only)';
+
+-- ============================================
+-- Catalog Domain Tables
+-- ============================================
+
+-- llm_models 表：模型目录（路由器候选模型和参数校验的数据来源）
+CREATE TABLE llm_models (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    context_window INTEGER NOT NULL,
+    supports_tools BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_vision BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_json_mode BOOLEAN NOT NULL DEFAULT FALSE,
+    pricing_input DECIMAL(10, 6) NOT NULL DEFAULT 0,
+    pricing_output DECIMAL(10, 6) NOT NULL DEFAULT 0,
+    quality SMALLINT NOT NULL,
+    typical_latency_ms INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+
+    -- 约束
+    CONSTRAINT llm_models_provider_name_unique UNIQUE (provider, name),
+    CONSTRAINT llm_models_context_window_positive CHECK (context_window > 0),
+    CONSTRAINT llm_models_pricing_non_negative CHECK (pricing_input >= 0 AND pricing_output >= 0),
+    CONSTRAINT llm_models_quality_range CHECK (quality BETWEEN 1 AND 10),
+    CONSTRAINT llm_models_latency_non_negative CHECK (typical_latency_ms >= 0)
+);
+
+-- 注释
+COMMENT ON TABLE llm_models IS 'Model catalog managed by admins (router candidates, model/provider validation)';
+COMMENT ON COLUMN llm_models.pricing_input IS 'Input price in USD per 1M tokens';
+COMMENT ON COLUMN llm_models.pricing_output IS 'Output price in USD per 1M tokens';
+COMMENT ON COLUMN llm_models.quality IS 'Quality score 1-10 used by the quality routing strategy';
+COMMENT ON COLUMN llm_models.typical_latency_ms IS 'Estimated latency used before enough calls are observed';
+COMMENT ON COLUMN llm_models.enabled IS 'Disabled models are kept but never routed to or accepted by validation';
+
+-- 触发器：自动更新 updated_at
+CREATE TRIGGER update_llm_models_updated_at
+    BEFORE UPDATE ON llm_models
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- ============================================
+-- Prompt Domain Tables
+-- ============================================
+
+-- prompt_templates 表：管理员创建的提示词模板版本（内置模板随程序发布，不在此表中）
+CREATE TABLE prompt_templates (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    version VARCHAR(32) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    messages TEXT NOT NULL,
+    variables TEXT NOT NULL DEFAULT '[]',
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    model VARCHAR(100) NOT NULL DEFAULT '',
+    temperature DECIMAL(3, 2),
+    top_p DECIMAL(3, 2),
+    max_tokens INTEGER NOT NULL DEFAULT 0,
+    cache_ttl_seconds INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+
+    -- 约束
+    CONSTRAINT prompt_templates_name_version_unique UNIQUE (name, version),
+    CONSTRAINT prompt_templates_temperature_range CHECK (temperature IS NULL OR temperature BETWEEN 0 AND 2),
+    CONSTRAINT prompt_templates_top_p_range CHECK (top_p IS NULL OR top_p BETWEEN 0 AND 1),
+    CONSTRAINT prompt_templates_max_tokens_non_negative CHECK (max_tokens >= 0),
+    CONSTRAINT prompt_templates_cache_ttl_range CHECK (cache_ttl_seconds BETWEEN 0 AND 2592000)
+);
+
+-- 注释
+COMMENT ON TABLE prompt_templates IS 'Admin-created prompt template versions (immutable, embedded templates are not stored)';
+COMMENT ON COLUMN prompt_templates.version IS 'Semantic version MAJOR.MINOR.PATCH, unique per name';
+COMMENT ON COLUMN prompt_templates.messages IS 'JSON array of {role, content}, content may use {{variable}} placeholders';
+COMMENT ON COLUMN prompt_templates.variables IS 'JSON array of {name, description, required, default}';
+COMMENT ON COLUMN prompt_templates.model IS 'Default model (empty means decided by the caller or router)';
+COMMENT ON COLUMN prompt_templates.cache_ttl_seconds IS 'LLM response cache TTL for cacheable requests (0 means APP_LLM_CACHE_TTL)';
+
+-- prompt_pins 表：管理员固定的生效版本（没有记录时使用最新版本）
+CREATE TABLE prompt_pins (
+    name VARCHAR(100) PRIMARY KEY,
+    version VARCHAR(32) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- 注释
+COMMENT ON TABLE prompt_pins IS 'Pinned active version per prompt name (set by pin and rollback)';
+COMMENT ON COLUMN prompt_pins.version IS 'May refer to an embedded template version';
+
+-- ============================================
+-- Usage Domain Tables
+-- ============================================
+
+-- llm_usage 表：用量账本（每次 LLM 调用一条，只追加）
+CREATE TABLE llm_usage (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    request_id VARCHAR(64) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    input_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    cost DECIMAL(14, 8) NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+
+    -- 约束
+    CONSTRAINT llm_usage_tokens_non_negative CHECK (input_tokens >= 0 AND output_tokens >= 0),
+    CONSTRAINT llm_usage_cost_non_negative CHECK (cost >= 0)
+);
+
+-- 索引
+CREATE INDEX idx_llm_usage_user_created ON llm_usage(user_id, created_at);
+
+-- 注释
+COMMENT ON TABLE llm_usage IS 'Usage ledger: one row per LLM call (append-only)';
+COMMENT ON COLUMN llm_usage.user_id IS 'End user of the call (NULL for system calls)';
+COMMENT ON COLUMN llm_usage.request_id IS 'RequestID of the GenerationCompleted event';
+COMMENT ON COLUMN llm_usage.cost IS 'Cost in USD computed from catalog prices at call time';
+COMMENT ON COLUMN llm_usage.success IS 'FALSE for failed calls (tokens are usually 0)';
+
+-- user_plans 表：用户的额度套餐（未分配时使用 APP_QUOTA_DEFAULT_PLAN）
+CREATE TABLE user_plans (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    plan VARCHAR(50) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- 注释
+COMMENT ON TABLE user_plans IS 'Per-user quota plan (missing row means the default plan)';
+COMMENT ON COLUMN user_plans.plan IS 'Plan name, limits are configured via APP_QUOTA_PLANS_<NAME>';
+
+-- ============================================
+-- Extension Points (commented out, for reference)
+-- ============================================
 
 -- Monitoring Domain (未实现)
 -- CREATE TABLE metrics (
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+)
+
+// DTO 转换层
+//
+// 命名规范：
+// - toXxx:         HTTP DTO → Domain
+// - toXxxResponse: Domain → HTTP Response
+
+// toModelParams 将创建/更新请求转换为模型属性（enabled 未指定时为 true）
+func toModelParams(req dto.CreateModelRequest) model.ModelParams {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return model.ModelParams{
+		Name:          req.Name,
+		Provider:      req.Provider,
+		ContextWindow: req.ContextWindow,
+		Capabilities: model.Capabilities{
+			Tools:    req.Capabilities.Tools,
+			Vision:   req.Capabilities.Vision,
+			JSONMode: req.Capabilities.JSONMode,
+		},
+		InputPrice:       req.Pricing.Input,
+		OutputPrice:      req.Pricing.Output,
+		Quality:          req.Quality,
+		TypicalLatencyMs: req.TypicalLatencyMs,
+		Enabled:          enabled,
+	}
+}
+
+// toModelResponse 将模型转换为 HTTP 响应
+func toModelResponse(m *model.LLMModel) dto.ModelResponse {
+	return dto.ModelResponse{
+		ModelID:       m.ID,
+		Name:          m.Name,
+		Provider:      m.Provider,
+		ContextWindow: m.ContextWindow,
+		Capabilities: dto.CapabilitiesDTO{
+			Tools:    m.Capabilities.Tools,
+			Vision:   m.Capabilities.Vision,
+			JSONMode: m.Capabilities.JSONMode,
+		},
+		Pricing: dto.PricingDTO{
+			Input:  m.InputPrice,
+			Output: m.OutputPrice,
+		},
+		Quality:          m.Quality,
+		TypicalLatencyMs: m.TypicalLatencyMs,
+		Enabled:          m.Enabled,
+		CreatedAt:        m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toListModelsResponse 将模型列表转换为 HTTP 响应
+func toListModelsResponse(models []*model.LLMModel) dto.ListModelsResponse {
+	items := make([]dto.ModelResponse, 0, len(models))
+	for _, m := range models {
+		items = append(items, toModelResponse(m))
+	}
+	return dto.ListModelsResponse{Models: items, Total: len(items)}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/service"
+)
+
+// CreateModelHandler 创建模型（HTTP 适配层，管理员）
+//
+// 用例：CreateModel（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/admin/models
+//
+// 业务逻辑在 service.CatalogService.CreateModel() 中实现
+func (deps *HandlerDependencies) CreateModelHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 解析并验证请求体
+	var req dto.CreateModelRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	output, err := deps.catalogService.CreateModel(ctx, service.CreateModelInput{Params: toModelParams(req)})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	c.JSON(201, toModelResponse(output.Model))
+}
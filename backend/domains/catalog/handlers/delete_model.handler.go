@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+)
+
+// DeleteModelHandler 删除模型（HTTP 适配层，管理员）
+//
+// 用例：DeleteModel（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: DELETE
+//   - Path: /api/admin/models/:id
+//
+// 已保存的对话和消息中的模型名称不受影响。
+//
+// 业务逻辑在 service.CatalogService.DeleteModel() 中实现
+func (deps *HandlerDependencies) DeleteModelHandler(ctx context.Context, c *app.RequestContext) {
+	modelID, ok := requireModelID(c)
+	if !ok {
+		return
+	}
+
+	if err := deps.catalogService.DeleteModel(ctx, modelID); err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, dto.DeleteModelResponse{Success: true})
+}
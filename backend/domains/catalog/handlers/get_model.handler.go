@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetModelHandler 获取模型（HTTP 适配层，管理员）
+//
+// 用例：GetModel（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/admin/models/:id
+//
+// 业务逻辑在 service.CatalogService.GetModel() 中实现
+func (deps *HandlerDependencies) GetModelHandler(ctx context.Context, c *app.RequestContext) {
+	modelID, ok := requireModelID(c)
+	if !ok {
+		return
+	}
+
+	output, err := deps.catalogService.GetModel(ctx, modelID)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toModelResponse(output.Model))
+}
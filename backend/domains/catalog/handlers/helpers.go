@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+	pkgvalidator "github.com/erweixin/go-genai-stack/backend/pkg/validator"
+)
+
+// handleDomainError 统一处理领域错误，转换为 HTTP 响应
+func handleDomainError(c *app.RequestContext, err error) {
+	if err == nil {
+		return
+	}
+
+	errMsg := err.Error()
+	code := extractErrorCode(errMsg)
+	statusCode := getHTTPStatusCode(code)
+
+	if statusCode >= 500 {
+		log.Printf("Internal error: %v", err)
+	}
+	c.JSON(statusCode, dto.ErrorResponse{
+		Error:   code,
+		Message: extractErrorMessage(errMsg),
+	})
+}
+
+// requireModelID 获取路径参数中的模型 ID
+func requireModelID(c *app.RequestContext) (string, bool) {
+	modelID := c.Param("id")
+	if modelID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "模型 ID 不能为空",
+		})
+		return "", false
+	}
+	return modelID, true
+}
+
+// bindAndValidate 解析请求体并使用 pkg/validator 校验（validate 标签）
+//
+// 失败时直接写入 400 响应。
+func bindAndValidate(c *app.RequestContext, req interface{}) bool {
+	if err := c.Bind(req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return false
+	}
+	if err := pkgvalidator.Validate(req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// extractErrorCode 从错误消息中提取错误码（第一个大写下划线格式的片段）
+func extractErrorCode(errMsg string) string {
+	for _, part := range strings.Split(errMsg, ":") {
+		code := strings.TrimSpace(part)
+		if isUpperSnakeCase(code) && len(code) > 3 {
+			return code
+		}
+	}
+	return "UNKNOWN_ERROR"
+}
+
+// extractErrorMessage 从错误消息中提取用户友好的消息
+func extractErrorMessage(errMsg string) string {
+	// 格式：ERROR_CODE: message
+	if idx := strings.Index(errMsg, ":"); idx > 0 {
+		return strings.TrimSpace(errMsg[idx+1:])
+	}
+	return errMsg
+}
+
+// getHTTPStatusCode 根据错误码确定 HTTP 状态码
+func getHTTPStatusCode(code string) int {
+	switch code {
+	case "MODEL_NOT_FOUND":
+		return 404
+	case "MODEL_ALREADY_EXISTS":
+		return 409
+	}
+
+	if strings.HasSuffix(code, "_FAILED") {
+		return 500
+	}
+	if strings.Contains(code, "INVALID") {
+		return 400
+	}
+	return 500
+}
+
+// isUpperSnakeCase 判断字符串是否是大写下划线格式
+func isUpperSnakeCase(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'A' && c <= 'Z' || c == '_' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ListModelsHandler 列出模型（HTTP 适配层，管理员）
+//
+// 用例：ListModels（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/admin/models
+//
+// 返回所有模型（包括未启用的），不经过缓存。
+//
+// 业务逻辑在 service.CatalogService.ListModels() 中实现
+func (deps *HandlerDependencies) ListModelsHandler(ctx context.Context, c *app.RequestContext) {
+	output, err := deps.catalogService.ListModels(ctx)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toListModelsResponse(output.Models))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/service"
+)
+
+// HandlerDependencies Handler 依赖容器
+//
+// 只持有 Handler 需要的依赖，不包含业务逻辑；
+// 模型目录的业务逻辑在 service.CatalogService 中实现。
+type HandlerDependencies struct {
+	catalogService *service.CatalogService
+}
+
+// NewHandlerDependencies 创建新的依赖容器
+//
+// 参数：
+//   - catalogService: 模型目录服务
+//
+// 返回：
+//   - *HandlerDependencies: 依赖容器实例
+func NewHandlerDependencies(catalogService *service.CatalogService) *HandlerDependencies {
+	return &HandlerDependencies{
+		catalogService: catalogService,
+	}
+}
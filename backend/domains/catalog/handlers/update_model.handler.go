@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/service"
+)
+
+// UpdateModelHandler 更新模型（HTTP 适配层，管理员）
+//
+// 用例：UpdateModel（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/admin/models/:id
+//
+// 整体替换模型属性；enabled=false 时模型不再参与路由和参数校验。
+//
+// 业务逻辑在 service.CatalogService.UpdateModel() 中实现
+func (deps *HandlerDependencies) UpdateModelHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取模型 ID
+	modelID, ok := requireModelID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.UpdateModelRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.catalogService.UpdateModel(ctx, service.UpdateModelInput{
+		ID:     modelID,
+		Params: toModelParams(dto.CreateModelRequest(req)),
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toModelResponse(output.Model))
+}
@@ -0,0 +1,65 @@
+package dto
+
+// 验证规则使用 pkg/validator（validate 标签）。
+// 提供商不使用 provider 规则（该规则读取模型目录本身），格式由领域模型校验。
+
+// CapabilitiesDTO 模型能力
+type CapabilitiesDTO struct {
+	Tools    bool `json:"tools"`
+	Vision   bool `json:"vision"`
+	JSONMode bool `json:"json_mode"`
+}
+
+// PricingDTO 模型价格（USD / 1M tokens）
+type PricingDTO struct {
+	Input  float64 `json:"input" validate:"gte=0"`
+	Output float64 `json:"output" validate:"gte=0"`
+}
+
+// CreateModelRequest 创建模型请求
+type CreateModelRequest struct {
+	Name             string          `json:"name" validate:"required,max=100"`
+	Provider         string          `json:"provider" validate:"required,max=50"`
+	ContextWindow    int             `json:"context_window" validate:"required,gt=0"`
+	Capabilities     CapabilitiesDTO `json:"capabilities"`
+	Pricing          PricingDTO      `json:"pricing"`
+	Quality          int             `json:"quality" validate:"gte=1,lte=10"`
+	TypicalLatencyMs int64           `json:"typical_latency_ms" validate:"gte=0"`
+	Enabled          *bool           `json:"enabled"` // 默认 true
+}
+
+// UpdateModelRequest 更新模型请求（整体替换，字段与创建相同）
+type UpdateModelRequest CreateModelRequest
+
+// ModelResponse 模型响应
+type ModelResponse struct {
+	ModelID          string          `json:"model_id"`
+	Name             string          `json:"name"`
+	Provider         string          `json:"provider"`
+	ContextWindow    int             `json:"context_window"`
+	Capabilities     CapabilitiesDTO `json:"capabilities"`
+	Pricing          PricingDTO      `json:"pricing"`
+	Quality          int             `json:"quality"`
+	TypicalLatencyMs int64           `json:"typical_latency_ms"`
+	Enabled          bool            `json:"enabled"`
+	CreatedAt        string          `json:"created_at"`
+	UpdatedAt        string          `json:"updated_at"`
+}
+
+// ListModelsResponse 列出模型响应
+type ListModelsResponse struct {
+	Models []ModelResponse `json:"models"`
+	Total  int             `json:"total"`
+}
+
+// DeleteModelResponse 删除模型响应
+type DeleteModelResponse struct {
+	Success bool `json:"success"`
+}
+
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Error   string `json:"error"`             // 错误码
+	Message string `json:"message"`           // 错误消息
+	Details string `json:"details,omitempty"` // 详细信息（可选）
+}
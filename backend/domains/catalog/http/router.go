@@ -0,0 +1,33 @@
+package http
+
+import (
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/handlers"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// RegisterRoutes 注册模型目录领域的路由
+//
+// 所有路由都需要认证（AuthMiddleware）和管理员权限（AdminMiddleware）。
+//
+// 路由列表：
+//   - POST   /api/admin/models     - 创建模型
+//   - GET    /api/admin/models     - 列出模型（包括未启用的）
+//   - GET    /api/admin/models/:id - 获取模型
+//   - PUT    /api/admin/models/:id - 更新模型
+//   - DELETE /api/admin/models/:id - 删除模型
+func RegisterRoutes(
+	r *route.RouterGroup,
+	deps *handlers.HandlerDependencies,
+	authMiddleware *middleware.AuthMiddleware,
+	adminMiddleware *middleware.AdminMiddleware,
+) {
+	models := r.Group("/admin/models", authMiddleware.Handle(), adminMiddleware.Handle())
+	{
+		models.POST("", deps.CreateModelHandler)
+		models.GET("", deps.ListModelsHandler)
+		models.GET("/:id", deps.GetModelHandler)
+		models.PUT("/:id", deps.UpdateModelHandler)
+		models.DELETE("/:id", deps.DeleteModelHandler)
+	}
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/google/uuid"
+)
+
+// 字段限制（与 llm_models 表和 validator 的 model_name / provider 规则一致）
+const (
+	MaxModelNameLength = 100
+	MinQuality         = 1
+	MaxQuality         = 10
+)
+
+// providerNamePattern 提供商名称：小写字母、数字、-、_（与 APP_LLM_PROVIDERS_<NAME> 的键一致）
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
+
+// 模型目录领域错误定义
+var (
+	ErrInvalidModelName     = fmt.Errorf("INVALID_MODEL_NAME: 模型名称不能为空且不能超过 100 字符")
+	ErrInvalidProvider      = fmt.Errorf("INVALID_PROVIDER: 提供商名称只能包含小写字母、数字、- 和 _，且不能超过 50 字符")
+	ErrInvalidPrice         = fmt.Errorf("INVALID_PRICE: 价格不能为负数")
+	ErrInvalidQuality       = fmt.Errorf("INVALID_QUALITY: 质量评分必须在 1-10 之间")
+	ErrInvalidContextWindow = fmt.Errorf("INVALID_CONTEXT_WINDOW: 上下文窗口必须大于 0")
+	ErrInvalidLatency       = fmt.Errorf("INVALID_LATENCY: 预估延迟不能为负数")
+)
+
+// Capabilities 模型能力
+type Capabilities struct {
+	Tools    bool // 支持工具调用
+	Vision   bool // 支持图片输入
+	JSONMode bool // 支持 JSON 输出约束（json_object / json_schema）
+}
+
+// ModelParams 模型的可编辑属性（创建和更新共用）
+type ModelParams struct {
+	Name             string
+	Provider         string
+	ContextWindow    int
+	Capabilities     Capabilities
+	InputPrice       float64 // USD / 1M input tokens
+	OutputPrice      float64 // USD / 1M output tokens
+	Quality          int     // 1-10
+	TypicalLatencyMs int64   // 预估延迟（路由器在观测样本不足时使用）
+	Enabled          bool
+}
+
+// LLMModel 模型目录条目（聚合根）
+//
+// 同一提供商下模型名称唯一。只有启用的模型参与路由和参数校验。
+type LLMModel struct {
+	ID string
+	ModelParams
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewLLMModel 创建模型目录条目
+func NewLLMModel(params ModelParams) (*LLMModel, error) {
+	params.Name = strings.TrimSpace(params.Name)
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &LLMModel{
+		ID:          uuid.New().String(),
+		ModelParams: params,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Update 更新模型属性（整体替换）
+func (m *LLMModel) Update(params ModelParams) error {
+	params.Name = strings.TrimSpace(params.Name)
+	if err := params.validate(); err != nil {
+		return err
+	}
+	m.ModelParams = params
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+// Spec 转换为 LLM 领域路由器使用的模型描述
+func (m *LLMModel) Spec() llmmodel.ModelSpec {
+	return llmmodel.ModelSpec{
+		Provider:         m.Provider,
+		Model:            m.Name,
+		InputPrice:       m.InputPrice,
+		OutputPrice:      m.OutputPrice,
+		Quality:          m.Quality,
+		TypicalLatencyMs: m.TypicalLatencyMs,
+		ContextWindow:    m.ContextWindow,
+		SupportsTools:    m.Capabilities.Tools,
+		SupportsVision:   m.Capabilities.Vision,
+		SupportsJSONMode: m.Capabilities.JSONMode,
+	}
+}
+
+// validate 验证模型属性
+func (p ModelParams) validate() error {
+	if p.Name == "" || len(p.Name) > MaxModelNameLength {
+		return ErrInvalidModelName
+	}
+	if !IsValidProviderName(p.Provider) {
+		return ErrInvalidProvider
+	}
+	if p.ContextWindow <= 0 {
+		return ErrInvalidContextWindow
+	}
+	if p.InputPrice < 0 || p.OutputPrice < 0 {
+		return ErrInvalidPrice
+	}
+	if p.Quality < MinQuality || p.Quality > MaxQuality {
+		return ErrInvalidQuality
+	}
+	if p.TypicalLatencyMs < 0 {
+		return ErrInvalidLatency
+	}
+	return nil
+}
+
+// IsValidProviderName 判断提供商名称格式是否有效
+func IsValidProviderName(name string) bool {
+	return providerNamePattern.MatchString(name)
+}
+
+// DefaultModels 内置模型（模型目录为空时写入）
+//
+// 价格为公开的标价（USD / 1M tokens），质量评分和预估延迟是经验值，
+// 预估延迟会在有足够的观测数据后被实际 P95 取代。
+func DefaultModels() []ModelParams {
+	all := Capabilities{Tools: true, Vision: true, JSONMode: true}
+	return []ModelParams{
+		{Name: "gpt-4o", Provider: "openai", ContextWindow: 128000, Capabilities: all, InputPrice: 2.5, OutputPrice: 10, Quality: 9, TypicalLatencyMs: 2500, Enabled: true},
+		{Name: "gpt-4o-mini", Provider: "openai", ContextWindow: 128000, Capabilities: all, InputPrice: 0.15, OutputPrice: 0.6, Quality: 7, TypicalLatencyMs: 1500, Enabled: true},
+		{Name: "claude-3-5-sonnet-latest", Provider: "anthropic", ContextWindow: 200000, Capabilities: Capabilities{Tools: true, Vision: true}, InputPrice: 3, OutputPrice: 15, Quality: 9, TypicalLatencyMs: 3000, Enabled: true},
+		{Name: "claude-3-5-haiku-latest", Provider: "anthropic", ContextWindow: 200000, Capabilities: Capabilities{Tools: true}, InputPrice: 0.8, OutputPrice: 4, Quality: 7, TypicalLatencyMs: 1500, Enabled: true},
+		{Name: "llama3", Provider: "local", ContextWindow: 8192, Quality: 5, TypicalLatencyMs: 4000, Enabled: true},
+		{Name: "mock-model", Provider: "mock", ContextWindow: 1000000, Capabilities: all, Quality: 1, TypicalLatencyMs: 1, Enabled: true},
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validParams() ModelParams {
+	return ModelParams{
+		Name:          " gpt-4o-mini ",
+		Provider:      "openai",
+		ContextWindow: 128000,
+		Capabilities:  Capabilities{Tools: true},
+		InputPrice:    0.15,
+		OutputPrice:   0.6,
+		Quality:       7,
+		Enabled:       true,
+	}
+}
+
+// TestNewLLMModel 测试创建模型目录条目
+func TestNewLLMModel(t *testing.T) {
+	t.Run("有效参数", func(t *testing.T) {
+		m, err := NewLLMModel(validParams())
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, m.ID)
+		assert.Equal(t, "gpt-4o-mini", m.Name)
+		assert.False(t, m.CreatedAt.IsZero())
+	})
+
+	tests := []struct {
+		name   string
+		modify func(p *ModelParams)
+		want   error
+	}{
+		{"名称为空", func(p *ModelParams) { p.Name = "  " }, ErrInvalidModelName},
+		{"名称过长", func(p *ModelParams) { p.Name = strings.Repeat("a", MaxModelNameLength+1) }, ErrInvalidModelName},
+		{"提供商包含大写字母", func(p *ModelParams) { p.Provider = "OpenAI" }, ErrInvalidProvider},
+		{"上下文窗口为 0", func(p *ModelParams) { p.ContextWindow = 0 }, ErrInvalidContextWindow},
+		{"价格为负数", func(p *ModelParams) { p.OutputPrice = -1 }, ErrInvalidPrice},
+		{"质量评分超出范围", func(p *ModelParams) { p.Quality = 11 }, ErrInvalidQuality},
+		{"预估延迟为负数", func(p *ModelParams) { p.TypicalLatencyMs = -1 }, ErrInvalidLatency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validParams()
+			tt.modify(&params)
+
+			_, err := NewLLMModel(params)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+// TestLLMModel_Update 测试更新模型（验证失败时不修改）
+func TestLLMModel_Update(t *testing.T) {
+	m, _ := NewLLMModel(validParams())
+
+	params := validParams()
+	params.Quality = 0
+	assert.ErrorIs(t, m.Update(params), ErrInvalidQuality)
+	assert.Equal(t, 7, m.Quality)
+
+	params.Quality = 8
+	params.Enabled = false
+	require.NoError(t, m.Update(params))
+	assert.Equal(t, 8, m.Quality)
+	assert.False(t, m.Enabled)
+}
+
+// TestLLMModel_Spec 测试转换为路由器的模型描述
+func TestLLMModel_Spec(t *testing.T) {
+	m, _ := NewLLMModel(validParams())
+
+	spec := m.Spec()
+
+	assert.Equal(t, "openai", spec.Provider)
+	assert.Equal(t, "gpt-4o-mini", spec.Model)
+	assert.Equal(t, 0.6, spec.OutputPrice)
+	assert.True(t, spec.SupportsTools)
+	assert.False(t, spec.SupportsJSONMode)
+}
+
+// TestDefaultModels 测试内置模型都能通过验证
+func TestDefaultModels(t *testing.T) {
+	for _, params := range DefaultModels() {
+		_, err := NewLLMModel(params)
+		assert.NoError(t, err, params.Name)
+	}
+}
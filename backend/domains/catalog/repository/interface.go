@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+)
+
+// ModelRepository 定义模型目录仓储接口
+type ModelRepository interface {
+	// Create 保存一个新的模型
+	Create(ctx context.Context, m *model.LLMModel) error
+
+	// FindByID 根据 ID 查找模型
+	FindByID(ctx context.Context, id string) (*model.LLMModel, error)
+
+	// FindByName 根据提供商和模型名称查找模型
+	FindByName(ctx context.Context, provider, name string) (*model.LLMModel, error)
+
+	// Update 更新模型
+	Update(ctx context.Context, m *model.LLMModel) error
+
+	// Delete 删除模型
+	Delete(ctx context.Context, id string) error
+
+	// List 列出所有模型（包括未启用的，按提供商和名称排序）
+	List(ctx context.Context) ([]*model.LLMModel, error)
+}
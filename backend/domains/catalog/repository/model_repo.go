@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// 错误定义
+var (
+	ErrModelNotFound = errors.New("MODEL_NOT_FOUND: 模型不存在")
+)
+
+// rowScanner 抽象 *sql.Row 和 *sql.Rows 的 Scan 方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// newDialect 根据数据库类型选择 goqu 方言
+func newDialect(dbType string) goqu.DialectWrapper {
+	switch dbType {
+	case "mysql":
+		return goqu.Dialect("mysql")
+	case "sqlite":
+		return goqu.Dialect("sqlite3")
+	default:
+		return goqu.Dialect("postgres")
+	}
+}
+
+// ModelRepositoryImpl 模型目录仓储实现
+type ModelRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewModelRepository 创建模型目录仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewModelRepository(db *sql.DB, dbType string) *ModelRepositoryImpl {
+	return &ModelRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *ModelRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// modelColumns llm_models 表的查询/插入列（顺序与 scanModel 保持一致）
+var modelColumns = []interface{}{
+	"id", "name", "provider", "context_window",
+	"supports_tools", "supports_vision", "supports_json_mode",
+	"pricing_input", "pricing_output", "quality", "typical_latency_ms", "enabled",
+	"created_at", "updated_at",
+}
+
+// Create 创建模型
+func (r *ModelRepositoryImpl) Create(ctx context.Context, m *model.LLMModel) error {
+	query, args, err := r.dialect.Insert("llm_models").
+		Cols(modelColumns...).
+		Vals(goqu.Vals{
+			m.ID,
+			m.Name,
+			m.Provider,
+			m.ContextWindow,
+			m.Capabilities.Tools,
+			m.Capabilities.Vision,
+			m.Capabilities.JSONMode,
+			m.InputPrice,
+			m.OutputPrice,
+			m.Quality,
+			m.TypicalLatencyMs,
+			m.Enabled,
+			m.CreatedAt,
+			m.UpdatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert model query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create model failed: %w", err)
+	}
+	return nil
+}
+
+// FindByID 根据 ID 查找模型
+func (r *ModelRepositoryImpl) FindByID(ctx context.Context, id string) (*model.LLMModel, error) {
+	return r.findOne(ctx, goqu.C("id").Eq(id))
+}
+
+// FindByName 根据提供商和模型名称查找模型
+func (r *ModelRepositoryImpl) FindByName(ctx context.Context, provider, name string) (*model.LLMModel, error) {
+	return r.findOne(ctx, goqu.C("provider").Eq(provider), goqu.C("name").Eq(name))
+}
+
+// findOne 按条件查找一个模型
+func (r *ModelRepositoryImpl) findOne(ctx context.Context, where ...goqu.Expression) (*model.LLMModel, error) {
+	query, args, err := r.dialect.From("llm_models").
+		Select(modelColumns...).
+		Where(where...).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build select model query failed: %w", err)
+	}
+
+	m, err := scanModel(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrModelNotFound
+		}
+		return nil, fmt.Errorf("query model failed: %w", err)
+	}
+	return m, nil
+}
+
+// Update 更新模型
+func (r *ModelRepositoryImpl) Update(ctx context.Context, m *model.LLMModel) error {
+	query, args, err := r.dialect.Update("llm_models").
+		Set(goqu.Record{
+			"name":               m.Name,
+			"provider":           m.Provider,
+			"context_window":     m.ContextWindow,
+			"supports_tools":     m.Capabilities.Tools,
+			"supports_vision":    m.Capabilities.Vision,
+			"supports_json_mode": m.Capabilities.JSONMode,
+			"pricing_input":      m.InputPrice,
+			"pricing_output":     m.OutputPrice,
+			"quality":            m.Quality,
+			"typical_latency_ms": m.TypicalLatencyMs,
+			"enabled":            m.Enabled,
+			"updated_at":         m.UpdatedAt,
+		}).
+		Where(goqu.C("id").Eq(m.ID)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build update model query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update model failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrModelNotFound
+	}
+	return nil
+}
+
+// Delete 删除模型
+func (r *ModelRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query, args, err := r.dialect.Delete("llm_models").
+		Where(goqu.C("id").Eq(id)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build delete model query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete model failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrModelNotFound
+	}
+	return nil
+}
+
+// List 列出所有模型（按提供商和名称排序）
+func (r *ModelRepositoryImpl) List(ctx context.Context) ([]*model.LLMModel, error) {
+	query, args, err := r.dialect.From("llm_models").
+		Select(modelColumns...).
+		Order(goqu.C("provider").Asc(), goqu.C("name").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list models query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query models failed: %w", err)
+	}
+	defer rows.Close()
+
+	models := make([]*model.LLMModel, 0)
+	for rows.Next() {
+		m, err := scanModel(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan model failed: %w", err)
+		}
+		models = append(models, m)
+	}
+	return models, rows.Err()
+}
+
+// scanModel 按 modelColumns 的顺序扫描一行模型数据
+func scanModel(row rowScanner) (*model.LLMModel, error) {
+	m := &model.LLMModel{}
+	err := row.Scan(
+		&m.ID,
+		&m.Name,
+		&m.Provider,
+		&m.ContextWindow,
+		&m.Capabilities.Tools,
+		&m.Capabilities.Vision,
+		&m.Capabilities.JSONMode,
+		&m.InputPrice,
+		&m.OutputPrice,
+		&m.Quality,
+		&m.TypicalLatencyMs,
+		&m.Enabled,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
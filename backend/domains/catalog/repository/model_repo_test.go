@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testModelColumns = []string{
+	"id", "name", "provider", "context_window",
+	"supports_tools", "supports_vision", "supports_json_mode",
+	"pricing_input", "pricing_output", "quality", "typical_latency_ms", "enabled",
+	"created_at", "updated_at",
+}
+
+// TestModelRepository_Create 测试创建模型
+func TestModelRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewModelRepository(db, "postgres")
+	m, _ := model.NewLLMModel(model.ModelParams{
+		Name: "gpt-4o", Provider: "openai", ContextWindow: 128000,
+		Capabilities: model.Capabilities{Tools: true}, InputPrice: 2.5, OutputPrice: 10, Quality: 9, Enabled: true,
+	})
+
+	mock.ExpectExec(`INSERT INTO "llm_models" .+'gpt-4o', 'openai', 128000, TRUE, FALSE, FALSE, 2.5, 10, 9`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), m))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestModelRepository_FindByName 测试根据提供商和名称查找模型
+func TestModelRepository_FindByName(t *testing.T) {
+	t.Run("找到模型", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewModelRepository(db, "postgres")
+		now := time.Now()
+		mock.ExpectQuery(`SELECT .+ FROM "llm_models" WHERE \(\("provider" = 'openai'\) AND \("name" = 'gpt-4o'\)\)`).
+			WillReturnRows(sqlmock.NewRows(testModelColumns).
+				AddRow("model-1", "gpt-4o", "openai", 128000, true, true, true, 2.5, 10.0, 9, 2500, true, now, now))
+
+		m, err := repo.FindByName(context.Background(), "openai", "gpt-4o")
+
+		require.NoError(t, err)
+		assert.Equal(t, "model-1", m.ID)
+		assert.True(t, m.Capabilities.JSONMode)
+		assert.Equal(t, int64(2500), m.TypicalLatencyMs)
+	})
+
+	t.Run("模型不存在", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewModelRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT .+ FROM "llm_models"`).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindByName(context.Background(), "openai", "missing")
+
+		assert.ErrorIs(t, err, ErrModelNotFound)
+	})
+}
+
+// TestModelRepository_List 测试按提供商和名称排序列出模型
+func TestModelRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewModelRepository(db, "postgres")
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .+ FROM "llm_models" ORDER BY "provider" ASC, "name" ASC`).
+		WillReturnRows(sqlmock.NewRows(testModelColumns).
+			AddRow("model-1", "gpt-4o", "openai", 128000, true, true, true, 2.5, 10.0, 9, 2500, true, now, now).
+			AddRow("model-2", "mock-model", "mock", 1000000, false, false, false, 0.0, 0.0, 1, 1, false, now, now))
+
+	models, err := repo.List(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.False(t, models[1].Enabled)
+}
+
+// TestModelRepository_Update_NotFound 测试更新不存在的模型
+func TestModelRepository_Update_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewModelRepository(db, "postgres")
+	mock.ExpectExec(`UPDATE "llm_models"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(context.Background(), &model.LLMModel{ID: "missing"})
+
+	assert.ErrorIs(t, err, ErrModelNotFound)
+}
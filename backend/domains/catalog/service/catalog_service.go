@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/repository"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultCacheTTL 模型目录缓存的默认有效期
+//
+// 本实例的写操作会立即使缓存失效；TTL 用于获取其他实例的修改。
+const DefaultCacheTTL = time.Minute
+
+// 领域错误
+var (
+	ErrModelAlreadyExists = fmt.Errorf("MODEL_ALREADY_EXISTS: 该提供商下已存在同名模型")
+)
+
+// CatalogService 模型目录领域服务
+//
+// 职责：
+// - 管理模型目录（管理员 CRUD）
+// - 为 LLM 路由器提供启用的模型（实现 router.Catalog）
+// - 为参数校验提供模型和提供商查询（实现 validator.ModelCatalog）
+// - 为用量账本提供价格查询（实现 usage service.PriceLookup）
+//
+// 读取使用内存缓存：本实例的写操作后立即失效，否则在 cacheTTL 后重新加载。
+// 重新加载失败时继续使用旧的缓存（记录日志）。
+type CatalogService struct {
+	repo     repository.ModelRepository
+	cacheTTL time.Duration
+
+	mu       sync.RWMutex
+	cache    *catalogSnapshot
+	loadedAt time.Time
+}
+
+// catalogSnapshot 缓存的模型目录
+type catalogSnapshot struct {
+	specs     []llmmodel.ModelSpec          // 启用的模型
+	models    map[string]struct{}           // 启用的模型名称
+	providers map[string]struct{}           // 有启用模型的提供商名称
+	all       map[string]llmmodel.ModelSpec // 所有模型（包括未启用的），键为 specKey
+}
+
+// specKey 按提供商和模型名称查找的键
+func specKey(provider, name string) string {
+	return provider + "/" + name
+}
+
+// NewCatalogService 创建模型目录服务
+//
+// 参数：
+//   - repo: 模型目录仓储
+//   - cacheTTL: 缓存有效期（<= 0 时使用 DefaultCacheTTL）
+func NewCatalogService(repo repository.ModelRepository, cacheTTL time.Duration) *CatalogService {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	return &CatalogService{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// ModelOutput 模型输出
+type ModelOutput struct {
+	Model *model.LLMModel
+}
+
+// CreateModelInput 创建模型输入
+type CreateModelInput struct {
+	Params model.ModelParams
+}
+
+// CreateModel 创建模型（用例实现）
+//
+// 步骤：
+//  1. CreateModelEntity - 创建模型实体（含验证）
+//  2. CheckDuplicate - 同一提供商下名称唯一
+//  3. SaveModel - 保存并使缓存失效
+func (s *CatalogService) CreateModel(ctx context.Context, input CreateModelInput) (*ModelOutput, error) {
+	// Step 1: CreateModelEntity
+	m, err := model.NewLLMModel(input.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CheckDuplicate
+	if err := s.checkDuplicate(ctx, m); err != nil {
+		return nil, err
+	}
+
+	// Step 3: SaveModel
+	if err := s.repo.Create(ctx, m); err != nil {
+		return nil, fmt.Errorf("CREATE_FAILED: 创建模型失败: %w", err)
+	}
+	s.Invalidate()
+
+	return &ModelOutput{Model: m}, nil
+}
+
+// GetModel 获取模型
+func (s *CatalogService) GetModel(ctx context.Context, id string) (*ModelOutput, error) {
+	m, err := s.findModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelOutput{Model: m}, nil
+}
+
+// ListModelsOutput 列出模型输出
+type ListModelsOutput struct {
+	Models []*model.LLMModel
+}
+
+// ListModels 列出所有模型（包括未启用的，直接读取数据库）
+func (s *CatalogService) ListModels(ctx context.Context) (*ListModelsOutput, error) {
+	models, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询模型失败")
+	}
+	return &ListModelsOutput{Models: models}, nil
+}
+
+// UpdateModelInput 更新模型输入
+type UpdateModelInput struct {
+	ID     string
+	Params model.ModelParams
+}
+
+// UpdateModel 更新模型（用例实现，整体替换可编辑属性）
+func (s *CatalogService) UpdateModel(ctx context.Context, input UpdateModelInput) (*ModelOutput, error) {
+	m, err := s.findModel(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Update(input.Params); err != nil {
+		return nil, err
+	}
+	if err := s.checkDuplicate(ctx, m); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, m); err != nil {
+		if errors.Is(err, repository.ErrModelNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("UPDATE_FAILED: 更新模型失败: %w", err)
+	}
+	s.Invalidate()
+
+	return &ModelOutput{Model: m}, nil
+}
+
+// DeleteModel 删除模型（用例实现）
+func (s *CatalogService) DeleteModel(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrModelNotFound) {
+			return err
+		}
+		return fmt.Errorf("DELETE_FAILED: 删除模型失败: %w", err)
+	}
+	s.Invalidate()
+	return nil
+}
+
+// SeedDefaults 模型目录为空时写入内置模型（启动时调用）
+//
+// 返回写入的模型数量；目录不为空时不做任何修改。
+func (s *CatalogService) SeedDefaults(ctx context.Context, defaults []model.ModelParams) (int, error) {
+	existing, err := s.repo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("QUERY_FAILED: 查询模型失败: %w", err)
+	}
+	if len(existing) > 0 {
+		return 0, nil
+	}
+
+	for _, params := range defaults {
+		m, err := model.NewLLMModel(params)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.repo.Create(ctx, m); err != nil {
+			return 0, fmt.Errorf("CREATE_FAILED: 创建模型失败: %w", err)
+		}
+	}
+	s.Invalidate()
+	return len(defaults), nil
+}
+
+// Models 返回启用的模型（实现 router.Catalog，读取缓存）
+func (s *CatalogService) Models(ctx context.Context) ([]llmmodel.ModelSpec, error) {
+	snapshot, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]llmmodel.ModelSpec, len(snapshot.specs))
+	copy(specs, snapshot.specs)
+	return specs, nil
+}
+
+// HasModel 判断是否存在启用的同名模型（实现 validator.ModelCatalog）
+//
+// 读取失败时返回 false。
+func (s *CatalogService) HasModel(name string) bool {
+	snapshot, err := s.snapshot(context.Background())
+	if err != nil {
+		return false
+	}
+	_, ok := snapshot.models[name]
+	return ok
+}
+
+// HasProvider 判断提供商是否有启用的模型（实现 validator.ModelCatalog）
+//
+// 读取失败时返回 false。
+func (s *CatalogService) HasProvider(name string) bool {
+	snapshot, err := s.snapshot(context.Background())
+	if err != nil {
+		return false
+	}
+	_, ok := snapshot.providers[name]
+	return ok
+}
+
+// ModelSpec 按提供商和模型名称查找模型（包括未启用的，读取缓存）
+//
+// 读取失败或模型不存在时返回 false。
+func (s *CatalogService) ModelSpec(provider, name string) (llmmodel.ModelSpec, bool) {
+	snapshot, err := s.snapshot(context.Background())
+	if err != nil {
+		return llmmodel.ModelSpec{}, false
+	}
+	spec, ok := snapshot.all[specKey(provider, name)]
+	return spec, ok
+}
+
+// Invalidate 使缓存失效，下次读取时重新加载
+func (s *CatalogService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// snapshot 返回缓存的模型目录（过期时重新加载）
+func (s *CatalogService) snapshot(ctx context.Context) (*catalogSnapshot, error) {
+	s.mu.RLock()
+	if s.cache != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		cache := s.cache
+		s.mu.RUnlock()
+		return cache, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 等待锁期间可能已被其他调用重新加载
+	if s.cache != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		return s.cache, nil
+	}
+
+	models, err := s.repo.List(ctx)
+	if err != nil {
+		if s.cache != nil {
+			logger.Error("reload model catalog failed, using stale cache", zap.Error(err))
+			return s.cache, nil
+		}
+		return nil, fmt.Errorf("QUERY_FAILED: 读取模型目录失败: %w", err)
+	}
+
+	snapshot := &catalogSnapshot{
+		specs:     make([]llmmodel.ModelSpec, 0, len(models)),
+		models:    make(map[string]struct{}, len(models)),
+		providers: make(map[string]struct{}),
+		all:       make(map[string]llmmodel.ModelSpec, len(models)),
+	}
+	for _, m := range models {
+		snapshot.all[specKey(m.Provider, m.Name)] = m.Spec()
+		if !m.Enabled {
+			continue
+		}
+		snapshot.specs = append(snapshot.specs, m.Spec())
+		snapshot.models[m.Name] = struct{}{}
+		snapshot.providers[m.Provider] = struct{}{}
+	}
+
+	s.cache = snapshot
+	s.loadedAt = time.Now()
+	return snapshot, nil
+}
+
+// findModel 查找模型（不存在时返回 MODEL_NOT_FOUND）
+func (s *CatalogService) findModel(ctx context.Context, id string) (*model.LLMModel, error) {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrModelNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("QUERY_FAILED: 查询模型失败")
+	}
+	return m, nil
+}
+
+// checkDuplicate 检查同一提供商下是否已有同名的其他模型
+func (s *CatalogService) checkDuplicate(ctx context.Context, m *model.LLMModel) error {
+	existing, err := s.repo.FindByName(ctx, m.Provider, m.Name)
+	if err != nil {
+		if errors.Is(err, repository.ErrModelNotFound) {
+			return nil
+		}
+		return fmt.Errorf("QUERY_FAILED: 查询模型失败")
+	}
+	if existing.ID != m.ID {
+		return ErrModelAlreadyExists
+	}
+	return nil
+}
@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+	pkgvalidator "github.com/erweixin/go-genai-stack/backend/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateModel_Success 测试创建模型（enabled 默认为 true）
+func TestCreateModel_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByName(helper.Mock)
+	helper.Mock.ExpectExec(`INSERT INTO "llm_models" .+'gpt-4o', 'openai', 128000, TRUE, FALSE, TRUE, 2.5, 10, 9, 0, TRUE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := helper.PerformRequest("POST", "/api/admin/models", validCreateRequest())
+
+	require.Equal(t, consts.StatusCreated, w.Code, w.Body.String())
+	var resp dto.ModelResponse
+	DecodeResponse(t, w, &resp)
+	assert.NotEmpty(t, resp.ModelID)
+	assert.True(t, resp.Enabled)
+	assert.True(t, resp.Capabilities.JSONMode)
+	assert.Equal(t, 10.0, resp.Pricing.Output)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateModel_MODEL_ALREADY_EXISTS 测试同一提供商下重复的模型名称
+func TestCreateModel_MODEL_ALREADY_EXISTS(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByName(helper.Mock, CreateTestModel("openai", "gpt-4o", true))
+
+	w := helper.PerformRequest("POST", "/api/admin/models", validCreateRequest())
+
+	assert.Equal(t, consts.StatusConflict, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "MODEL_ALREADY_EXISTS", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateModel_INVALID_INPUT 测试无效的请求参数
+func TestCreateModel_INVALID_INPUT(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req map[string]interface{})
+		want   string
+	}{
+		{"质量评分超出范围", func(req map[string]interface{}) { req["quality"] = 11 }, "INVALID_INPUT"},
+		{"价格为负数", func(req map[string]interface{}) { req["pricing"] = map[string]float64{"input": -1} }, "INVALID_INPUT"},
+		{"缺少上下文窗口", func(req map[string]interface{}) { delete(req, "context_window") }, "INVALID_INPUT"},
+		{"提供商格式无效", func(req map[string]interface{}) { req["provider"] = "Open AI" }, "INVALID_PROVIDER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper(t)
+			defer helper.Close()
+
+			req := validCreateRequest()
+			tt.modify(req)
+			w := helper.PerformRequest("POST", "/api/admin/models", req)
+
+			assert.Equal(t, consts.StatusBadRequest, w.Code)
+			var resp dto.ErrorResponse
+			DecodeResponse(t, w, &resp)
+			assert.Equal(t, tt.want, resp.Error)
+
+			helper.AssertExpectations(t)
+		})
+	}
+}
+
+// TestCatalog_FORBIDDEN 测试非管理员不能访问模型目录接口
+func TestCatalog_FORBIDDEN(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	helper.AsUser()
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/api/admin/models"},
+		{"POST", "/api/admin/models"},
+		{"DELETE", "/api/admin/models/" + TestModelID},
+	} {
+		w := helper.PerformRequest(req.method, req.path, nil)
+		assert.Equal(t, consts.StatusForbidden, w.Code, req.method+" "+req.path)
+	}
+
+	helper.AssertExpectations(t)
+}
+
+// TestCatalog_UnverifiedAdmin 测试邮箱在白名单中但未验证（或已被封禁）的用户不是管理员
+func TestCatalog_UnverifiedAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(u *middleware.UserInfo)
+	}{
+		{"邮箱未验证", func(u *middleware.UserInfo) { u.EmailVerified = false }},
+		{"已被封禁", func(u *middleware.UserInfo) { u.Active = false }},
+		{"邮箱已修改", func(u *middleware.UserInfo) { u.Email = "someone@example.com" }},
+		{"用户不存在", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper(t)
+			defer helper.Close()
+			if tt.mutate != nil {
+				tt.mutate(helper.Users["admin-1"])
+			} else {
+				delete(helper.Users, "admin-1")
+			}
+
+			w := helper.PerformRequest("GET", "/api/admin/models", nil)
+
+			assert.Equal(t, consts.StatusForbidden, w.Code)
+			helper.AssertExpectations(t)
+		})
+	}
+}
+
+// TestListModels_Success 测试列出模型（包括未启用的）
+func TestListModels_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	disabled := CreateTestModel("local", "llama3", false)
+	disabled.ID = "model-456"
+	MockList(helper.Mock, disabled, CreateTestModel("openai", "gpt-4o", true))
+
+	w := helper.PerformRequest("GET", "/api/admin/models", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.ListModelsResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Models, 2)
+	assert.False(t, resp.Models[0].Enabled)
+
+	helper.AssertExpectations(t)
+}
+
+// TestGetModel_MODEL_NOT_FOUND 测试获取不存在的模型
+func TestGetModel_MODEL_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock)
+
+	w := helper.PerformRequest("GET", "/api/admin/models/missing", nil)
+
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestUpdateModel_Success 测试更新模型（整体替换）
+func TestUpdateModel_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	existing := CreateTestModel("openai", "gpt-4o", true)
+	MockFindByID(helper.Mock, existing)
+	MockFindByName(helper.Mock, existing)
+	helper.Mock.ExpectExec(`UPDATE "llm_models" SET .+"enabled"=FALSE.+"quality"=8`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := validCreateRequest()
+	req["quality"] = 8
+	req["enabled"] = false
+	w := helper.PerformRequest("PUT", "/api/admin/models/"+TestModelID, req)
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.ModelResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, 8, resp.Quality)
+	assert.False(t, resp.Enabled)
+
+	helper.AssertExpectations(t)
+}
+
+// TestDeleteModel_Success 测试删除模型
+func TestDeleteModel_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectExec(`DELETE FROM "llm_models"`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := helper.PerformRequest("DELETE", "/api/admin/models/"+TestModelID, nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.DeleteModelResponse
+	DecodeResponse(t, w, &resp)
+	assert.True(t, resp.Success)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCatalog_CachedLookups 测试路由器和参数校验读取缓存，写操作后重新加载
+func TestCatalog_CachedLookups(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	pkgvalidator.SetModelCatalog(helper.Service)
+	defer pkgvalidator.SetModelCatalog(nil)
+
+	// 只查询一次数据库：未启用的模型不参与路由和校验
+	MockList(helper.Mock, CreateTestModel("openai", "gpt-4o", true), CreateTestModel("local", "llama3", false))
+
+	specs, err := helper.Service.Models(context.Background())
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "gpt-4o", specs[0].Model)
+	assert.NoError(t, pkgvalidator.ValidateVar("gpt-4o", "model_name"))
+	assert.Error(t, pkgvalidator.ValidateVar("llama3", "model_name"))
+	assert.NoError(t, pkgvalidator.ValidateVar("openai", "provider"))
+	assert.Error(t, pkgvalidator.ValidateVar("local", "provider"))
+
+	// 价格查询包括未启用的模型（用量账本记录历史调用的费用）
+	spec, ok := helper.Service.ModelSpec("local", "llama3")
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, spec.OutputPrice)
+	_, ok = helper.Service.ModelSpec("openai", "llama3")
+	assert.False(t, ok)
+	helper.AssertExpectations(t)
+
+	// 删除后缓存失效，下次读取重新加载
+	helper.Mock.ExpectExec(`DELETE FROM "llm_models"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	w := helper.PerformRequest("DELETE", "/api/admin/models/"+TestModelID, nil)
+	require.Equal(t, consts.StatusOK, w.Code)
+
+	MockList(helper.Mock)
+	assert.Error(t, pkgvalidator.ValidateVar("gpt-4o", "model_name"))
+	helper.AssertExpectations(t)
+}
+
+// TestSeedDefaults 测试模型目录为空时写入内置模型，不为空时不修改
+func TestSeedDefaults(t *testing.T) {
+	t.Run("目录为空", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		defaults := model.DefaultModels()
+		MockList(helper.Mock)
+		for range defaults {
+			helper.Mock.ExpectExec(`INSERT INTO "llm_models"`).WillReturnResult(sqlmock.NewResult(1, 1))
+		}
+
+		n, err := helper.Service.SeedDefaults(context.Background(), defaults)
+
+		require.NoError(t, err)
+		assert.Equal(t, len(defaults), n)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("目录不为空", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		MockList(helper.Mock, CreateTestModel("openai", "gpt-4o", true))
+
+		n, err := helper.Service.SeedDefaults(context.Background(), model.DefaultModels())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		helper.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/handlers"
+	cataloghttp "github.com/erweixin/go-genai-stack/backend/domains/catalog/http"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/catalog/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// ========== 测试常量 ==========
+
+const (
+	TestAdminEmail = "admin@example.com"
+	TestUserEmail  = "user@example.com"
+	TestModelID    = "model-123"
+)
+
+// TestTime 测试时间常量
+var TestTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// modelColumns llm_models 表列（与 repository 保持一致）
+var modelColumns = []string{
+	"id", "name", "provider", "context_window",
+	"supports_tools", "supports_vision", "supports_json_mode",
+	"pricing_input", "pricing_output", "quality", "typical_latency_ms", "enabled",
+	"created_at", "updated_at",
+}
+
+// TestHelper 提供测试辅助方法
+//
+// 数据库使用 sqlmock。请求经过真实的路由、认证中间件和管理员中间件，
+// 默认使用管理员的 Token（AsUser 切换为普通用户）。
+// 管理员中间件查询的用户由 Users 提供（不经过 sqlmock），默认邮箱已验证。
+type TestHelper struct {
+	DB      *sql.DB
+	Mock    sqlmock.Sqlmock
+	Service *service.CatalogService
+	Server  *server.Hertz
+	Users   map[string]*middleware.UserInfo // 用户 ID → 用户信息
+
+	adminToken string
+	userToken  string
+	token      string
+}
+
+// NewTestHelper 创建测试辅助工具
+func NewTestHelper(t *testing.T) *TestHelper {
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	h := &TestHelper{DB: db, Mock: sqlMock, Users: map[string]*middleware.UserInfo{
+		"admin-1": {Email: TestAdminEmail, EmailVerified: true, Active: true},
+		"user-1":  {Email: TestUserEmail, EmailVerified: true, Active: true},
+	}}
+	h.Service = service.NewCatalogService(repository.NewModelRepository(db, "postgres"), time.Hour)
+
+	jwtService := authservice.NewJWTService("test-secret", time.Hour, time.Hour, "test")
+	h.adminToken, _, err = jwtService.GenerateAccessToken("admin-1", TestAdminEmail)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.userToken, _, err = jwtService.GenerateAccessToken("user-1", TestUserEmail)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.token = h.adminToken
+
+	h.Server = server.Default(
+		server.WithHostPorts("127.0.0.1:0"),
+		server.WithExitWaitTime(0),
+	)
+	cataloghttp.RegisterRoutes(
+		h.Server.Group("/api"),
+		handlers.NewHandlerDependencies(h.Service),
+		middleware.NewAuthMiddleware(jwtService),
+		middleware.NewAdminMiddleware([]string{"Admin@Example.com"}, middleware.UserLookupFunc(h.getUser)),
+	)
+	return h
+}
+
+// getUser 管理员中间件的用户查询
+func (h *TestHelper) getUser(_ context.Context, userID string) (*middleware.UserInfo, error) {
+	return h.Users[userID], nil
+}
+
+// AsUser 之后的请求使用普通用户的 Token
+func (h *TestHelper) AsUser() {
+	h.token = h.userToken
+}
+
+// Close 清理资源
+func (h *TestHelper) Close() error {
+	return h.DB.Close()
+}
+
+// AssertExpectations 验证所有 mock 期望都被满足
+func (h *TestHelper) AssertExpectations(t *testing.T) {
+	if err := h.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// PerformRequest 执行 HTTP 请求（body 为 nil 时不发送请求体）
+func (h *TestHelper) PerformRequest(method, path string, body interface{}) *ut.ResponseRecorder {
+	var bodyOpt *ut.Body
+	if body != nil {
+		data, _ := json.Marshal(body)
+		bodyOpt = &ut.Body{Body: bytes.NewReader(data), Len: len(data)}
+	}
+	return ut.PerformRequest(h.Server.Engine, method, path, bodyOpt,
+		ut.Header{Key: "Content-Type", Value: "application/json"},
+		ut.Header{Key: "Authorization", Value: "Bearer " + h.token})
+}
+
+// DecodeResponse 解析 JSON 响应
+func DecodeResponse(t *testing.T, w *ut.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v (body: %s)", err, w.Body.String())
+	}
+}
+
+// ========== Mock 辅助函数 ==========
+
+// modelRows 构造 llm_models 查询结果
+func modelRows(models ...*model.LLMModel) *sqlmock.Rows {
+	rows := sqlmock.NewRows(modelColumns)
+	for _, m := range models {
+		rows.AddRow(m.ID, m.Name, m.Provider, m.ContextWindow,
+			m.Capabilities.Tools, m.Capabilities.Vision, m.Capabilities.JSONMode,
+			m.InputPrice, m.OutputPrice, m.Quality, m.TypicalLatencyMs, m.Enabled,
+			m.CreatedAt, m.UpdatedAt)
+	}
+	return rows
+}
+
+// MockFindByID Mock 根据 ID 查询模型
+func MockFindByID(m sqlmock.Sqlmock, models ...*model.LLMModel) {
+	m.ExpectQuery(`SELECT .+ FROM "llm_models" WHERE \("id"`).WillReturnRows(modelRows(models...))
+}
+
+// MockFindByName Mock 根据提供商和名称查询模型（不传模型表示不存在）
+func MockFindByName(m sqlmock.Sqlmock, models ...*model.LLMModel) {
+	m.ExpectQuery(`SELECT .+ FROM "llm_models" WHERE \(\("provider"`).WillReturnRows(modelRows(models...))
+}
+
+// MockList Mock 列出所有模型
+func MockList(m sqlmock.Sqlmock, models ...*model.LLMModel) {
+	m.ExpectQuery(`SELECT .+ FROM "llm_models" ORDER BY`).WillReturnRows(modelRows(models...))
+}
+
+// CreateTestModel 创建测试模型
+func CreateTestModel(provider, name string, enabled bool) *model.LLMModel {
+	m, _ := model.NewLLMModel(model.ModelParams{
+		Name:          name,
+		Provider:      provider,
+		ContextWindow: 128000,
+		InputPrice:    1,
+		OutputPrice:   2,
+		Quality:       7,
+		Enabled:       enabled,
+	})
+	m.ID = TestModelID
+	m.CreatedAt = TestTime
+	m.UpdatedAt = TestTime
+	return m
+}
+
+// validCreateRequest 有效的创建模型请求
+func validCreateRequest() map[string]interface{} {
+	return map[string]interface{}{
+		"name":           "gpt-4o",
+		"provider":       "openai",
+		"context_window": 128000,
+		"capabilities":   map[string]bool{"tools": true, "json_mode": true},
+		"pricing":        map[string]float64{"input": 2.5, "output": 10},
+		"quality":        9,
+	}
+}
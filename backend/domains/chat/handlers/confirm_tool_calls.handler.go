@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// ConfirmToolCallsHandler 确认或拒绝等待确认的工具调用（HTTP 适配层）
+//
+// 用例：ConfirmToolCalls（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations/:id/agent/confirm
+//
+// approve=true 执行等待确认的调用，false 拒绝；之后任务助手继续运行并回复。
+//
+// 业务逻辑在 service.ChatService.ConfirmToolCalls() 中实现
+func (deps *HandlerDependencies) ConfirmToolCallsHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.ConfirmToolCallsRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.ConfirmToolCalls(ctx, toConfirmToolCallsInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toAgentResponse(output))
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// DTO 转换层
+//
+// 命名规范：
+// - toXxxInput:    HTTP DTO → Domain Input
+// - toXxxResponse: Domain Output → HTTP Response
+
+// toConversationResponse 将对话实体转换为 HTTP 响应
+func toConversationResponse(conv *model.Conversation) dto.ConversationResponse {
+	return dto.ConversationResponse{
+		ConversationID: conv.ID,
+		Title:          conv.Title,
+		Model:          conv.Model,
+		Provider:       conv.Provider,
+		MessageCount:   conv.MessageCount,
+		Context: dto.ContextSettingsResponse{
+			Strategy:   string(conv.ContextStrategy()),
+			WindowSize: conv.ContextWindowSize(),
+			HasSummary: conv.Summary != "",
+		},
+		CreatedAt: conv.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: conv.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toMessageResponse 将消息实体转换为 HTTP 响应
+func toMessageResponse(msg *model.Message) dto.MessageResponse {
+	return dto.MessageResponse{
+		MessageID:      msg.ID,
+		ConversationID: msg.ConversationID,
+		Role:           string(msg.Role),
+		Content:        msg.Content,
+		Model:          msg.Model,
+		Provider:       msg.Provider,
+		InputTokens:    msg.InputTokens,
+		OutputTokens:   msg.OutputTokens,
+		LatencyMs:      msg.LatencyMs,
+		Truncated:      msg.Truncated,
+		ToolCalls:      toToolCallResponses(msg.ToolCalls),
+		ToolCallID:     msg.ToolCallID,
+		ToolName:       msg.ToolName,
+		Citations:      toCitationResponses(msg.Citations),
+		CreatedAt:      msg.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toToolCallResponses 将工具调用转换为 HTTP 响应（没有调用时为 nil）
+func toToolCallResponses(calls []model.ToolCall) []dto.ToolCallResponse {
+	if len(calls) == 0 {
+		return nil
+	}
+	responses := make([]dto.ToolCallResponse, 0, len(calls))
+	for _, call := range calls {
+		responses = append(responses, dto.ToolCallResponse(call))
+	}
+	return responses
+}
+
+// toCitationResponses 将引用转换为 HTTP 响应（没有引用时为 nil）
+func toCitationResponses(citations []model.Citation) []dto.CitationResponse {
+	if len(citations) == 0 {
+		return nil
+	}
+	responses := make([]dto.CitationResponse, 0, len(citations))
+	for _, citation := range citations {
+		responses = append(responses, dto.CitationResponse(citation))
+	}
+	return responses
+}
+
+// ========================================
+// Conversation 转换
+// ========================================
+
+// toCreateConversationInput 将 HTTP 请求转换为 Domain Input
+func toCreateConversationInput(userID string, req dto.CreateConversationRequest) service.CreateConversationInput {
+	return service.CreateConversationInput{
+		UserID:   userID,
+		Title:    req.Title,
+		Model:    req.Model,
+		Provider: req.Provider,
+	}
+}
+
+// toListConversationsResponse 将 Domain Output 转换为 HTTP 响应
+func toListConversationsResponse(output *service.ListConversationsOutput, req dto.ListConversationsRequest) dto.ListConversationsResponse {
+	conversations := make([]dto.ConversationResponse, 0, len(output.Conversations))
+	for _, conv := range output.Conversations {
+		conversations = append(conversations, toConversationResponse(conv))
+	}
+	return dto.ListConversationsResponse{
+		Conversations: conversations,
+		Total:         output.Total,
+		Limit:         req.Limit,
+		Offset:        req.Offset,
+	}
+}
+
+// ========================================
+// Message 转换
+// ========================================
+
+// toSendMessageInput 将 HTTP 请求转换为 Domain Input
+func toSendMessageInput(userID, conversationID string, req dto.SendMessageRequest) service.SendMessageInput {
+	return service.SendMessageInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Role:           model.Role(req.Role),
+		Content:        req.Content,
+		Strategy:       llmmodel.Strategy(req.Strategy),
+	}
+}
+
+// toStreamMessageInput 将 HTTP 请求转换为 Domain Input
+func toStreamMessageInput(userID, conversationID string, req dto.StreamMessageRequest) service.StreamMessageInput {
+	return service.StreamMessageInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Content:        req.Content,
+		Strategy:       llmmodel.Strategy(req.Strategy),
+	}
+}
+
+// toSendMessageResponse 将 Domain Output 转换为 HTTP 响应
+func toSendMessageResponse(output *service.SendMessageOutput) dto.SendMessageResponse {
+	resp := dto.SendMessageResponse{
+		Message: toMessageResponse(output.Message),
+	}
+	if output.Reply != nil {
+		reply := toMessageResponse(output.Reply)
+		resp.Reply = &reply
+	}
+	return resp
+}
+
+// toSendGroundedMessageInput 将 HTTP 请求转换为 Domain Input
+func toSendGroundedMessageInput(userID, conversationID string, req dto.SendGroundedMessageRequest) service.SendGroundedMessageInput {
+	return service.SendGroundedMessageInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Content:        req.Content,
+		Strategy:       llmmodel.Strategy(req.Strategy),
+	}
+}
+
+// toSendGroundedMessageResponse 将 Domain Output 转换为 HTTP 响应
+func toSendGroundedMessageResponse(output *service.SendGroundedMessageOutput) dto.SendGroundedMessageResponse {
+	reply := toMessageResponse(output.Reply)
+	citations := reply.Citations
+	if citations == nil {
+		citations = []dto.CitationResponse{}
+	}
+	return dto.SendGroundedMessageResponse{
+		Message:   toMessageResponse(output.Message),
+		Reply:     reply,
+		Citations: citations,
+	}
+}
+
+// toStreamDoneEvent 将流式发送结果转换为 SSE 结束事件
+func toStreamDoneEvent(output *service.SendMessageOutput) dto.StreamDoneEvent {
+	reply := output.Reply
+	return dto.StreamDoneEvent{
+		Message: toMessageResponse(output.Message),
+		Reply:   toMessageResponse(reply),
+		Usage: dto.UsageResponse{
+			InputTokens:  reply.InputTokens,
+			OutputTokens: reply.OutputTokens,
+			TotalTokens:  reply.InputTokens + reply.OutputTokens,
+		},
+	}
+}
+
+// toListMessagesResponse 将 Domain Output 转换为 HTTP 响应
+func toListMessagesResponse(output *service.ListMessagesOutput, req dto.ListMessagesRequest) dto.ListMessagesResponse {
+	messages := make([]dto.MessageResponse, 0, len(output.Messages))
+	for _, msg := range output.Messages {
+		messages = append(messages, toMessageResponse(msg))
+	}
+	return dto.ListMessagesResponse{
+		Messages: messages,
+		Total:    output.Total,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+}
+
+// ========================================
+// Agent 转换
+// ========================================
+
+// toRunAgentInput 将 HTTP 请求转换为 Domain Input
+func toRunAgentInput(userID, conversationID string, req dto.RunAgentRequest) service.RunAgentInput {
+	return service.RunAgentInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Content:        req.Content,
+		Strategy:       llmmodel.Strategy(req.Strategy),
+	}
+}
+
+// toConfirmToolCallsInput 将 HTTP 请求转换为 Domain Input
+func toConfirmToolCallsInput(userID, conversationID string, req dto.ConfirmToolCallsRequest) service.ConfirmToolCallsInput {
+	return service.ConfirmToolCallsInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Approve:        *req.Approve,
+		Strategy:       llmmodel.Strategy(req.Strategy),
+	}
+}
+
+// toAgentResponse 将 Domain Output 转换为 HTTP 响应
+func toAgentResponse(output *service.AgentOutput) dto.AgentResponse {
+	messages := make([]dto.MessageResponse, 0, len(output.Messages))
+	for _, msg := range output.Messages {
+		messages = append(messages, toMessageResponse(msg))
+	}
+	resp := dto.AgentResponse{
+		Messages:         messages,
+		PendingToolCalls: toToolCallResponses(output.PendingToolCalls),
+		StepLimitReached: output.StepLimitReached,
+	}
+	if resp.PendingToolCalls == nil {
+		resp.PendingToolCalls = []dto.ToolCallResponse{}
+	}
+	if output.Reply != nil {
+		reply := toMessageResponse(output.Reply)
+		resp.Reply = &reply
+	}
+	return resp
+}
+
+// toUpdateContextSettingsInput 将修改上下文设置请求转换为领域输入
+func toUpdateContextSettingsInput(userID, conversationID string, req dto.UpdateContextSettingsRequest) service.UpdateContextSettingsInput {
+	return service.UpdateContextSettingsInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Strategy:       model.ContextStrategy(req.Strategy),
+		WindowSize:     req.WindowSize,
+	}
+}
+
+// toPreviewContextResponse 将组装的提示词转换为 HTTP 响应
+func toPreviewContextResponse(output *service.PreviewContextOutput) dto.PreviewContextResponse {
+	plan := output.Plan
+	messages := make([]dto.PromptMessageResponse, 0, len(output.Prompt))
+	for _, m := range output.Prompt {
+		resp := dto.PromptMessageResponse{
+			Role:       string(m.Message.Role),
+			Content:    m.Message.Content,
+			ToolCallID: m.Message.ToolCallID,
+			Tokens:     m.Tokens,
+		}
+		for _, call := range m.Message.ToolCalls {
+			resp.ToolCalls = append(resp.ToolCalls, dto.ToolCallResponse(call))
+		}
+		messages = append(messages, resp)
+	}
+	return dto.PreviewContextResponse{
+		Strategy:        string(plan.Strategy),
+		Provider:        plan.Provider,
+		Model:           plan.Model,
+		ContextWindow:   plan.Window,
+		Budget:          plan.Budget,
+		PromptTokens:    plan.Tokens,
+		DroppedMessages: len(plan.Dropped),
+		SummaryPending:  plan.Summarize,
+		Messages:        messages,
+	}
+}
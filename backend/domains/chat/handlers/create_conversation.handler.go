@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// CreateConversationHandler 创建对话（HTTP 适配层）
+//
+// 用例：CreateConversation（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations
+//
+// Handler 职责：
+//  1. 解析并验证 HTTP 请求
+//  2. 调用 Domain Service
+//  3. 返回 HTTP 响应
+//
+// 业务逻辑在 service.ChatService.CreateConversation() 中实现
+func (deps *HandlerDependencies) CreateConversationHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析请求体（所有字段可选）
+	var req dto.CreateConversationRequest
+	if len(c.Request.Body()) > 0 {
+		if err := c.Bind(&req); err != nil {
+			c.JSON(400, dto.ErrorResponse{
+				Error:   "INVALID_REQUEST",
+				Message: "请求格式错误",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.CreateConversation(ctx, toCreateConversationInput(userID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(201, toConversationResponse(output.Conversation))
+}
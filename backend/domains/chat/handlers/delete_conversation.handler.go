@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+)
+
+// DeleteConversationHandler 删除对话（HTTP 适配层）
+//
+// 用例：DeleteConversation（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: DELETE
+//   - Path: /api/conversations/:id
+//
+// 对话中的消息随对话一起删除。
+//
+// 业务逻辑在 service.ChatService.DeleteConversation() 中实现
+func (deps *HandlerDependencies) DeleteConversationHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	err := deps.chatService.DeleteConversation(ctx, service.GetConversationInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	c.JSON(200, dto.DeleteConversationResponse{Success: true})
+}
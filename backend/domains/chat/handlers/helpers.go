@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	pkgvalidator "github.com/erweixin/go-genai-stack/backend/pkg/validator"
+)
+
+// handleDomainError 统一处理领域错误，转换为 HTTP 响应
+func handleDomainError(c *app.RequestContext, err error) {
+	if err == nil {
+		return
+	}
+
+	statusCode, response := toErrorResponse(err)
+	c.JSON(statusCode, response)
+}
+
+// toErrorResponse 将领域错误转换为 HTTP 状态码和错误响应（记录 500 级别的错误）
+func toErrorResponse(err error) (int, dto.ErrorResponse) {
+	errMsg := err.Error()
+	code := extractErrorCode(errMsg)
+	statusCode := getHTTPStatusCode(code)
+
+	if statusCode >= 500 {
+		log.Printf("Internal error: %v", err)
+	}
+	return statusCode, dto.ErrorResponse{
+		Error:   code,
+		Message: extractErrorMessage(errMsg),
+	}
+}
+
+// requireUserID 获取 JWT 中间件注入的用户 ID
+//
+// 获取失败时直接写入错误响应，调用方只需判断 ok 并返回。
+func requireUserID(c *app.RequestContext) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, dto.ErrorResponse{
+			Error:   "UNAUTHORIZED",
+			Message: "未授权访问",
+		})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(500, dto.ErrorResponse{
+			Error:   "INTERNAL_ERROR",
+			Message: "用户 ID 类型错误",
+		})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+// requireConversationID 获取路径参数中的对话 ID
+func requireConversationID(c *app.RequestContext) (string, bool) {
+	conversationID := c.Param("id")
+	if conversationID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "对话 ID 不能为空",
+		})
+		return "", false
+	}
+	return conversationID, true
+}
+
+// validateRequest 使用 pkg/validator 校验请求 DTO（validate 标签）
+//
+// 校验失败时直接写入 400 响应。
+func validateRequest(c *app.RequestContext, req interface{}) bool {
+	if err := pkgvalidator.Validate(req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// extractErrorCode 从错误消息中提取错误码（第一个大写下划线格式的片段）
+func extractErrorCode(errMsg string) string {
+	for _, part := range strings.Split(errMsg, ":") {
+		code := strings.TrimSpace(part)
+		if isUpperSnakeCase(code) && len(code) > 3 {
+			return code
+		}
+	}
+	return "UNKNOWN_ERROR"
+}
+
+// extractErrorMessage 从错误消息中提取用户友好的消息
+func extractErrorMessage(errMsg string) string {
+	// 格式：ERROR_CODE: message
+	if idx := strings.Index(errMsg, ":"); idx > 0 {
+		return strings.TrimSpace(errMsg[idx+1:])
+	}
+	return errMsg
+}
+
+// getHTTPStatusCode 根据错误码确定 HTTP 状态码
+func getHTTPStatusCode(code string) int {
+	switch code {
+	case "INVALID_CONVERSATION_TITLE", "INVALID_MESSAGE_ROLE",
+		"MESSAGE_CONTENT_EMPTY", "MESSAGE_TOO_LONG", "USER_ID_REQUIRED",
+		"INVALID_CONTEXT_SETTINGS", "CONTEXT_WINDOW_EXCEEDED", "CONTENT_BLOCKED":
+		return 400
+	case "UNAUTHORIZED_ACCESS":
+		return 403
+	case "TOOL_CONFIRMATION_PENDING", "NO_PENDING_TOOL_CALLS":
+		return 409
+	case "CONVERSATION_NOT_FOUND":
+		return 404
+	case "QUOTA_EXCEEDED":
+		return 429
+	case "AGENT_DISABLED", "GROUNDING_DISABLED":
+		return 503
+	case "GENERATION_FAILED":
+		// 上游模型服务失败
+		return 502
+	}
+
+	if strings.HasSuffix(code, "_FAILED") {
+		return 500
+	}
+	if strings.Contains(code, "INVALID") {
+		return 400
+	}
+	return 500
+}
+
+// isUpperSnakeCase 判断字符串是否是大写下划线格式
+func isUpperSnakeCase(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'A' && c <= 'Z' || c == '_' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+)
+
+// ListConversationsHandler 列出对话（HTTP 适配层）
+//
+// 用例：ListConversations（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/conversations?limit=20&offset=0
+//
+// 按最近更新时间倒序返回当前用户的对话。
+//
+// 业务逻辑在 service.ChatService.ListConversations() 中实现
+func (deps *HandlerDependencies) ListConversationsHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证查询参数
+	var req dto.ListConversationsRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.ListConversations(ctx, service.ListConversationsInput{
+		UserID: userID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toListConversationsResponse(output, req))
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+)
+
+// ListMessagesHandler 列出对话中的消息（HTTP 适配层）
+//
+// 用例：ListMessages（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/conversations/:id/messages?limit=20&offset=0
+//
+// 按时间正序返回消息。
+//
+// 业务逻辑在 service.ChatService.ListMessages() 中实现
+func (deps *HandlerDependencies) ListMessagesHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证查询参数
+	var req dto.ListMessagesRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.ListMessages(ctx, service.ListMessagesInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toListMessagesResponse(output, req))
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+)
+
+// PreviewContextHandler 查看下一次发送消息时组装的提示词（HTTP 适配层，用于调试）
+//
+// 用例：PreviewContext（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/conversations/:id/context/prompt?content=...
+//
+// 返回按上下文策略放入窗口的消息（包括摘要）和每条消息的 Token 数，不调用模型。
+//
+// 业务逻辑在 service.ChatService.PreviewContext() 中实现
+func (deps *HandlerDependencies) PreviewContextHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证查询参数
+	var req dto.PreviewContextRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.PreviewContext(ctx, service.PreviewContextInput{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Content:        req.Content,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toPreviewContextResponse(output))
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// RunAgentHandler 向任务助手发送消息（HTTP 适配层）
+//
+// 用例：RunAgent（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations/:id/agent
+//
+// 任务助手可以调用工具查询和修改当前用户的任务，工具调用和结果都记录在对话中。
+// 修改或完成任务前暂停，返回 pending_tool_calls 等待用户确认。
+//
+// 业务逻辑在 service.ChatService.RunAgent() 中实现
+func (deps *HandlerDependencies) RunAgentHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.RunAgentRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.RunAgent(ctx, toRunAgentInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toAgentResponse(output))
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// SendGroundedMessageHandler 发送消息，根据用户的资料回答（HTTP 适配层）
+//
+// 用例：SendGroundedMessage（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations/:id/messages/grounded
+//
+// 按消息内容检索当前用户的任务（结构化条件 + 语义相似度），在 Token 预算内放入提示词，
+// 模型只根据这些任务回答；回复中引用的任务在 citations 中返回，客户端据此链接到任务。
+//
+// 业务逻辑在 service.ChatService.SendGroundedMessage() 中实现
+func (deps *HandlerDependencies) SendGroundedMessageHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.SendGroundedMessageRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.SendGroundedMessage(ctx, toSendGroundedMessageInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toSendGroundedMessageResponse(output))
+}
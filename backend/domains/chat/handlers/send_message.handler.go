@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// SendMessageHandler 发送消息并获取模型回复（HTTP 适配层）
+//
+// 用例：SendMessage（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations/:id/messages
+//
+// role=user（默认）时调用对话配置的 LLM 提供商生成回复；
+// role=system 时只保存消息，作为后续对话的上下文。
+//
+// 业务逻辑在 service.ChatService.SendMessage() 中实现
+func (deps *HandlerDependencies) SendMessageHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.SendMessageRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.SendMessage(ctx, toSendMessageInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toSendMessageResponse(output))
+}
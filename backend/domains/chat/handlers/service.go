@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+)
+
+// HandlerDependencies Handler 依赖容器
+//
+// 只持有 Handler 需要的依赖，不包含业务逻辑；
+// 对话与消息的业务逻辑在 service.ChatService 中实现。
+type HandlerDependencies struct {
+	chatService       *service.ChatService
+	heartbeatInterval time.Duration // SSE 心跳间隔
+}
+
+// NewHandlerDependencies 创建新的依赖容器
+//
+// 参数：
+//   - chatService: 对话领域服务
+//
+// 返回：
+//   - *HandlerDependencies: 依赖容器实例
+func NewHandlerDependencies(chatService *service.ChatService) *HandlerDependencies {
+	return &HandlerDependencies{
+		chatService:       chatService,
+		heartbeatInterval: DefaultHeartbeatInterval,
+	}
+}
+
+// WithHeartbeatInterval 设置 SSE 心跳间隔（主要用于测试）
+func (deps *HandlerDependencies) WithHeartbeatInterval(interval time.Duration) *HandlerDependencies {
+	deps.heartbeatInterval = interval
+	return deps
+}
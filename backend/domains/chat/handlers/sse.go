@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+)
+
+// DefaultHeartbeatInterval SSE 心跳间隔
+//
+// 心跳既防止代理因空闲断开连接，也用于发现客户端断开（写入失败）。
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// flushWriter SSE 输出目标（*app.RequestContext 实现该接口）
+type flushWriter interface {
+	Write(p []byte) (int, error)
+	Flush() error
+}
+
+// sseWriter Server-Sent Events 写入器
+//
+// 并发安全（心跳和事件在不同 goroutine 中写入）；
+// 任意一次写入失败后，后续写入都直接返回该错误。
+type sseWriter struct {
+	mu  sync.Mutex
+	w   flushWriter
+	err error
+}
+
+// startSSE 设置 SSE 响应头并切换为分块写出
+//
+// 调用后不能再使用 c.JSON 等方法写响应。
+func startSSE(c *app.RequestContext) *sseWriter {
+	c.SetStatusCode(200)
+	c.Response.Header.Set("Content-Type", "text/event-stream; charset=utf-8")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+	c.Response.Header.Set("X-Accel-Buffering", "no") // 禁用 Nginx 缓冲
+	c.Response.HijackWriter(resp.NewChunkedBodyWriter(&c.Response, c.GetWriter()))
+	return &sseWriter{w: c}
+}
+
+// Event 写入一个事件（data 序列化为单行 JSON）
+func (s *sseWriter) Event(name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal sse event failed: %w", err)
+	}
+	return s.write([]byte(fmt.Sprintf("event: %s\ndata: %s\n\n", name, payload)))
+}
+
+// Comment 写入注释行（客户端会忽略）
+func (s *sseWriter) Comment(text string) error {
+	return s.write([]byte(": " + text + "\n\n"))
+}
+
+// Heartbeat 按间隔写入心跳注释，写入失败时调用 onError 并停止
+//
+// 返回的 stop 函数停止心跳并等待 goroutine 退出。
+func (s *sseWriter) Heartbeat(interval time.Duration, onError func()) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := s.Comment("ping"); err != nil {
+					onError()
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+func (s *sseWriter) write(p []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	if _, err := s.w.Write(p); err != nil {
+		s.err = err
+		return err
+	}
+	// 立即发送，Flush 前 p 必须保持有效
+	if err := s.w.Flush(); err != nil {
+		s.err = err
+		return err
+	}
+	return nil
+}
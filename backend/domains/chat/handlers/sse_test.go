@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFlushWriter 记录写入内容，flush 次数超过 failAfter 后返回错误（failAfter <= 0 表示不失败）
+type fakeFlushWriter struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	flushes   int
+	failAfter int
+}
+
+func (w *fakeFlushWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *fakeFlushWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushes++
+	if w.failAfter > 0 && w.flushes > w.failAfter {
+		return errors.New("broken pipe")
+	}
+	return nil
+}
+
+func (w *fakeFlushWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// TestSSEWriter_Event 测试事件格式
+func TestSSEWriter_Event(t *testing.T) {
+	w := &fakeFlushWriter{}
+	sse := &sseWriter{w: w}
+
+	require.NoError(t, sse.Event("delta", map[string]string{"content": "a\nb"}))
+	require.NoError(t, sse.Comment("ping"))
+
+	// data 为单行 JSON，换行被转义
+	assert.Equal(t, "event: delta\ndata: {\"content\":\"a\\nb\"}\n\n: ping\n\n", w.String())
+	assert.Equal(t, 2, w.flushes)
+}
+
+// TestSSEWriter_StickyError 测试写入失败后后续写入直接返回错误
+func TestSSEWriter_StickyError(t *testing.T) {
+	w := &fakeFlushWriter{failAfter: 1}
+	sse := &sseWriter{w: w}
+
+	require.NoError(t, sse.Comment("first"))
+	assert.Error(t, sse.Comment("second"))
+	assert.Error(t, sse.Comment("third"))
+	assert.Equal(t, 2, w.flushes)
+}
+
+// TestSSEWriter_Heartbeat 测试心跳与写入失败回调
+func TestSSEWriter_Heartbeat(t *testing.T) {
+	t.Run("定期写入心跳", func(t *testing.T) {
+		w := &fakeFlushWriter{}
+		sse := &sseWriter{w: w}
+
+		stop := sse.Heartbeat(5*time.Millisecond, func() { t.Error("unexpected onError") })
+		time.Sleep(30 * time.Millisecond)
+		stop()
+		stop() // 可重复调用
+
+		assert.GreaterOrEqual(t, strings.Count(w.String(), ": ping\n\n"), 2)
+	})
+
+	t.Run("客户端断开时回调", func(t *testing.T) {
+		w := &fakeFlushWriter{failAfter: 1}
+		sse := &sseWriter{w: w}
+
+		failed := make(chan struct{})
+		stop := sse.Heartbeat(5*time.Millisecond, func() { close(failed) })
+		defer stop()
+
+		select {
+		case <-failed:
+		case <-time.After(time.Second):
+			t.Fatal("onError was not called")
+		}
+	})
+}
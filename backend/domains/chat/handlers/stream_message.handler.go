@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+)
+
+// StreamMessageHandler 发送消息并以 SSE 流式返回模型回复（HTTP 适配层）
+//
+// 用例：StreamMessage（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/conversations/:id/messages/stream
+//   - Response: text/event-stream（start → delta... → done，出错时 error）
+//
+// 开始输出前的错误（参数无效、对话不存在、模型不可用等）以普通 JSON 错误返回。
+// 客户端断开后（写入事件或心跳失败）立即取消上游生成，已生成的内容标记为 truncated 后保存。
+// delta 只包含审核后的内容；回复未通过内容审核时中止生成，以 CONTENT_BLOCKED 的 error 事件结束。
+//
+// 业务逻辑在 service.ChatService.StreamMessage() 和 service.MessageStream 中实现
+func (deps *HandlerDependencies) StreamMessageHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.StreamMessageRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 开始生成（取消 streamCtx 会中止上游请求）
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := deps.chatService.StreamMessage(streamCtx, toStreamMessageInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 切换为 SSE 输出，等待期间发送心跳
+	sse := startSSE(c)
+	stopHeartbeat := sse.Heartbeat(deps.heartbeatInterval, cancel)
+
+	if err := sse.Event("start", dto.StreamStartEvent{Message: toMessageResponse(stream.Message())}); err != nil {
+		cancel()
+	}
+
+	// 5. 转发增量内容，直到生成结束、上游出错或客户端断开
+	for streamCtx.Err() == nil {
+		delta, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		if err := sse.Event("delta", dto.StreamDeltaEvent{Content: delta}); err != nil {
+			cancel()
+		}
+	}
+	stopHeartbeat()
+
+	// 6. 保存消息（被中断的回复标记为 truncated）
+	output, err := stream.Finish(ctx)
+	if err == nil && stream.Blocked() {
+		// 回复已保存为 BlockedReplyContent，客户端应丢弃已显示的内容
+		err = moderation.ErrContentBlocked
+	}
+	if err != nil {
+		_, response := toErrorResponse(err)
+		_ = sse.Event("error", response)
+		return
+	}
+	if output.Reply.Truncated {
+		log.Printf("Message stream truncated: conversation=%s, reply=%s", output.Conversation.ID, output.Reply.ID)
+	}
+
+	// 7. 结束事件携带 Token 用量（客户端已断开时写入失败，忽略）
+	_ = sse.Event("done", toStreamDoneEvent(output))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+)
+
+// UpdateContextSettingsHandler 修改对话的上下文策略（HTTP 适配层）
+//
+// 用例：UpdateContextSettings（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/conversations/:id/context
+//
+// 业务逻辑在 service.ChatService.UpdateContextSettings() 中实现
+func (deps *HandlerDependencies) UpdateContextSettingsHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID 和对话 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	conversationID, ok := requireConversationID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.UpdateContextSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.chatService.UpdateContextSettings(ctx, toUpdateContextSettingsInput(userID, conversationID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toConversationResponse(output.Conversation))
+}
@@ -0,0 +1,233 @@
+package dto
+
+// 验证规则使用 pkg/validator（validate 标签），
+// conversation_title、message_role、strategy、context_strategy、model_name、provider、pagination_* 为自定义规则。
+// model_name 和 provider 查询模型目录（catalog 领域）。
+
+// CreateConversationRequest 创建对话请求
+type CreateConversationRequest struct {
+	Title    string `json:"title" validate:"omitempty,conversation_title"`
+	Model    string `json:"model" validate:"omitempty,model_name"`
+	Provider string `json:"provider" validate:"omitempty,provider"`
+}
+
+// RenameConversationRequest 重命名对话请求
+type RenameConversationRequest struct {
+	Title string `json:"title" validate:"conversation_title"`
+}
+
+// ListConversationsRequest 列出对话请求（查询参数）
+type ListConversationsRequest struct {
+	Limit  int `query:"limit" json:"limit" validate:"omitempty,pagination_limit"`
+	Offset int `query:"offset" json:"offset" validate:"pagination_offset"`
+}
+
+// ConversationResponse 对话响应
+type ConversationResponse struct {
+	ConversationID string                  `json:"conversation_id"`
+	Title          string                  `json:"title"`
+	Model          string                  `json:"model,omitempty"`
+	Provider       string                  `json:"provider,omitempty"`
+	MessageCount   int                     `json:"message_count"`
+	Context        ContextSettingsResponse `json:"context"`
+	CreatedAt      string                  `json:"created_at"`
+	UpdatedAt      string                  `json:"updated_at"`
+}
+
+// ContextSettingsResponse 对话的上下文设置
+type ContextSettingsResponse struct {
+	Strategy   string `json:"strategy"`    // truncate、summarize、sliding_window
+	WindowSize int    `json:"window_size"` // 滑动窗口的消息数（sliding_window 时使用）
+	HasSummary bool   `json:"has_summary"` // 是否已有较早消息的摘要
+}
+
+// UpdateContextSettingsRequest 修改上下文设置请求
+type UpdateContextSettingsRequest struct {
+	Strategy   string `json:"strategy" validate:"required,context_strategy"`
+	WindowSize int    `json:"window_size" validate:"omitempty,gte=1,lte=50"` // 0 使用默认值 20
+}
+
+// PreviewContextRequest 查看提示词请求（查询参数）
+type PreviewContextRequest struct {
+	Content string `query:"content" json:"content" validate:"omitempty,max=32000"` // 假设发送的消息（可选）
+}
+
+// PromptMessageResponse 提示词中的一条消息
+type PromptMessageResponse struct {
+	Role       string             `json:"role"`
+	Content    string             `json:"content"`
+	ToolCalls  []ToolCallResponse `json:"tool_calls,omitempty"`
+	ToolCallID string             `json:"tool_call_id,omitempty"`
+	Tokens     int                `json:"tokens"` // 估算的 Token 数
+}
+
+// PreviewContextResponse 查看提示词响应
+type PreviewContextResponse struct {
+	Strategy        string                  `json:"strategy"`
+	Provider        string                  `json:"provider"`
+	Model           string                  `json:"model"`
+	ContextWindow   int                     `json:"context_window"`   // 模型的上下文窗口
+	Budget          int                     `json:"budget"`           // 可用于提示词的 Token 数（窗口减去回复预留）
+	PromptTokens    int                     `json:"prompt_tokens"`    // 提示词的 Token 数
+	DroppedMessages int                     `json:"dropped_messages"` // 没有放入的较早消息数（不包括已在摘要中的消息）
+	SummaryPending  bool                    `json:"summary_pending"`  // 下次发送时会把没有放入的消息合并到摘要中
+	Messages        []PromptMessageResponse `json:"messages"`
+}
+
+// ListConversationsResponse 列出对话响应
+type ListConversationsResponse struct {
+	Conversations []ConversationResponse `json:"conversations"`
+	Total         int                    `json:"total"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+// DeleteConversationResponse 删除对话响应
+type DeleteConversationResponse struct {
+	Success bool `json:"success"`
+}
+
+// SendMessageRequest 发送消息请求
+type SendMessageRequest struct {
+	Content  string `json:"content" validate:"required"`
+	Role     string `json:"role" validate:"omitempty,message_role"` // 默认 user；system 只保存不生成回复
+	Strategy string `json:"strategy" validate:"omitempty,strategy"` // 本次回复的模型路由策略（对话未指定模型时生效）
+}
+
+// ToolCallResponse 工具调用
+type ToolCallResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON 字符串
+}
+
+// CitationResponse 回复引用的资料
+type CitationResponse struct {
+	Source string `json:"source"` // 资料类型（task）
+	ID     string `json:"id"`     // 资料 ID（如任务 ID）
+	Title  string `json:"title"`
+}
+
+// MessageResponse 消息响应
+type MessageResponse struct {
+	MessageID      string `json:"message_id"`
+	ConversationID string `json:"conversation_id"`
+	Role           string `json:"role"`
+	Content        string `json:"content"`
+	Model          string `json:"model,omitempty"`
+	Provider       string `json:"provider,omitempty"`
+	InputTokens    int    `json:"input_tokens,omitempty"`
+	OutputTokens   int    `json:"output_tokens,omitempty"`
+	LatencyMs      int64  `json:"latency_ms,omitempty"`
+	Truncated      bool   `json:"truncated"` // 流式回复在完成前被中断，内容不完整
+
+	ToolCalls  []ToolCallResponse `json:"tool_calls,omitempty"`   // 任务助手请求的工具调用（assistant 消息）
+	ToolCallID string             `json:"tool_call_id,omitempty"` // 对应的工具调用（tool 消息，内容为结果 JSON）
+	ToolName   string             `json:"tool_name,omitempty"`
+
+	Citations []CitationResponse `json:"citations,omitempty"` // 基于资料的回复引用的资料
+
+	CreatedAt string `json:"created_at"`
+}
+
+// SendMessageResponse 发送消息响应
+type SendMessageResponse struct {
+	Message MessageResponse  `json:"message"`
+	Reply   *MessageResponse `json:"reply"` // system 消息时为 null
+}
+
+// SendGroundedMessageRequest 发送基于资料回答的消息请求（只支持 user 消息）
+type SendGroundedMessageRequest struct {
+	Content  string `json:"content" validate:"required"`
+	Strategy string `json:"strategy" validate:"omitempty,strategy"` // 本次回复的模型路由策略（对话未指定模型时生效）
+}
+
+// SendGroundedMessageResponse 发送基于资料回答的消息响应
+type SendGroundedMessageResponse struct {
+	Message   MessageResponse    `json:"message"`
+	Reply     MessageResponse    `json:"reply"`
+	Citations []CitationResponse `json:"citations"` // 回复引用的资料（按第一次引用的顺序，同 reply.citations）
+}
+
+// StreamMessageRequest 流式发送消息请求（只支持 user 消息）
+type StreamMessageRequest struct {
+	Content  string `json:"content" validate:"required"`
+	Strategy string `json:"strategy" validate:"omitempty,strategy"` // 本次回复的模型路由策略（对话未指定模型时生效）
+}
+
+// 流式响应（SSE）事件
+//
+//	event: start  data: StreamStartEvent
+//	event: delta  data: StreamDeltaEvent（多次）
+//	event: done   data: StreamDoneEvent
+//	event: error  data: ErrorResponse（开始流式输出后出错时发送）
+//
+// 等待模型输出期间定期发送注释行（": ping"）作为心跳。
+
+// StreamStartEvent 开始事件
+type StreamStartEvent struct {
+	Message MessageResponse `json:"message"` // 用户消息（流结束时与回复一起保存）
+}
+
+// StreamDeltaEvent 增量内容事件
+type StreamDeltaEvent struct {
+	Content string `json:"content"`
+}
+
+// UsageResponse Token 用量
+type UsageResponse struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+// StreamDoneEvent 结束事件（回复已保存）
+type StreamDoneEvent struct {
+	Message MessageResponse `json:"message"`
+	Reply   MessageResponse `json:"reply"`
+	Usage   UsageResponse   `json:"usage"`
+}
+
+// ListMessagesRequest 列出消息请求（查询参数）
+type ListMessagesRequest struct {
+	Limit  int `query:"limit" json:"limit" validate:"omitempty,pagination_limit"`
+	Offset int `query:"offset" json:"offset" validate:"pagination_offset"`
+}
+
+// ListMessagesResponse 列出消息响应
+type ListMessagesResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Error   string `json:"error"`             // 错误码
+	Message string `json:"message"`           // 错误消息
+	Details string `json:"details,omitempty"` // 详细信息（可选）
+}
+
+// RunAgentRequest 向任务助手发送消息请求
+type RunAgentRequest struct {
+	Content  string `json:"content" validate:"required"`
+	Strategy string `json:"strategy" validate:"omitempty,strategy"`
+}
+
+// ConfirmToolCallsRequest 确认工具调用请求
+type ConfirmToolCallsRequest struct {
+	Approve  *bool  `json:"approve" validate:"required"` // true 执行，false 拒绝
+	Strategy string `json:"strategy" validate:"omitempty,strategy"`
+}
+
+// AgentResponse 任务助手运行结果
+//
+// reply 为 null 时：pending_tool_calls 不为空表示等待确认
+// （POST /api/conversations/:id/agent/confirm），否则达到了步数上限。
+type AgentResponse struct {
+	Messages         []MessageResponse  `json:"messages"` // 本次新增的消息（用户消息、工具调用和结果、回复）
+	Reply            *MessageResponse   `json:"reply"`
+	PendingToolCalls []ToolCallResponse `json:"pending_tool_calls"`
+	StepLimitReached bool               `json:"step_limit_reached"`
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/handlers"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// RegisterRoutes 注册对话领域的路由
+//
+// 所有对话路由都需要认证（使用 AuthMiddleware），且只能访问自己的对话。
+// generation 是调用模型的路由（发送消息）额外使用的中间件，例如剩余额度响应头。
+//
+// 路由列表：
+//   - POST   /api/conversations              - 创建对话
+//   - GET    /api/conversations              - 列出对话
+//   - PUT    /api/conversations/:id          - 重命名对话
+//   - DELETE /api/conversations/:id          - 删除对话（级联删除消息）
+//   - PUT    /api/conversations/:id/context  - 修改上下文策略（truncate、summarize、sliding_window）
+//   - GET    /api/conversations/:id/context/prompt - 查看下一次发送消息时组装的提示词（调试）
+//   - POST   /api/conversations/:id/messages - 发送消息并获取模型回复
+//   - POST   /api/conversations/:id/messages/stream - 发送消息并以 SSE 流式返回回复
+//   - POST   /api/conversations/:id/messages/grounded - 发送消息，根据用户的任务回答并返回引用
+//   - GET    /api/conversations/:id/messages - 列出消息
+//   - POST   /api/conversations/:id/agent    - 向任务助手发送消息（调用工具操作任务）
+//   - POST   /api/conversations/:id/agent/confirm - 确认或拒绝等待确认的工具调用
+func RegisterRoutes(
+	r *route.RouterGroup,
+	deps *handlers.HandlerDependencies,
+	authMiddleware *middleware.AuthMiddleware,
+	generation ...app.HandlerFunc,
+) {
+	conversations := r.Group("/conversations", authMiddleware.Handle())
+	{
+		// 创建对话
+		conversations.POST("", deps.CreateConversationHandler)
+
+		// 列出对话
+		conversations.GET("", deps.ListConversationsHandler)
+
+		// 重命名对话
+		conversations.PUT("/:id", deps.RenameConversationHandler)
+
+		// 删除对话
+		conversations.DELETE("/:id", deps.DeleteConversationHandler)
+
+		// 上下文设置 / 查看提示词
+		conversations.PUT("/:id/context", deps.UpdateContextSettingsHandler)
+		conversations.GET("/:id/context/prompt", deps.PreviewContextHandler)
+
+		// 发送消息 / 列出消息
+		conversations.POST("/:id/messages", withHandler(generation, deps.SendMessageHandler)...)
+		conversations.GET("/:id/messages", deps.ListMessagesHandler)
+
+		// 流式发送消息（SSE）
+		conversations.POST("/:id/messages/stream", withHandler(generation, deps.StreamMessageHandler)...)
+
+		// 基于资料（用户的任务）回答
+		conversations.POST("/:id/messages/grounded", withHandler(generation, deps.SendGroundedMessageHandler)...)
+
+		// 任务助手
+		conversations.POST("/:id/agent", withHandler(generation, deps.RunAgentHandler)...)
+		conversations.POST("/:id/agent/confirm", withHandler(generation, deps.ConfirmToolCallsHandler)...)
+	}
+}
+
+// withHandler 在中间件之后追加最终的 Handler
+func withHandler(middlewares []app.HandlerFunc, handler app.HandlerFunc) []app.HandlerFunc {
+	chain := make([]app.HandlerFunc, 0, len(middlewares)+1)
+	chain = append(chain, middlewares...)
+	return append(chain, handler)
+}
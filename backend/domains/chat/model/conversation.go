@@ -0,0 +1,183 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// DefaultConversationTitle 未指定标题时的默认标题
+//
+// 使用默认标题的对话在第一条用户消息后自动以消息内容命名。
+const DefaultConversationTitle = "新对话"
+
+// MaxTitleLength 对话标题最大长度（字节，与 validator 的 conversation_title 规则一致）
+const MaxTitleLength = 200
+
+// autoTitleRunes 自动命名时截取的最大字符数
+const autoTitleRunes = 50
+
+// ContextStrategy 对话历史超出模型上下文窗口时的处理方式
+type ContextStrategy string
+
+const (
+	// ContextTruncate 从最早的消息开始丢弃，直到放得下（默认）
+	ContextTruncate ContextStrategy = "truncate"
+	// ContextSummarize 较早的消息合并为摘要（保存在对话上），摘要和最近的消息一起发送
+	ContextSummarize ContextStrategy = "summarize"
+	// ContextSlidingWindow 只发送最近的 WindowSize 条消息（同时不超出上下文窗口）
+	ContextSlidingWindow ContextStrategy = "sliding_window"
+)
+
+// 滑动窗口的消息数
+const (
+	DefaultContextWindowSize = 20
+	MaxContextWindowSize     = 50 // 与发送消息时加载的历史消息数一致
+)
+
+// ContextSettings 对话的上下文设置
+type ContextSettings struct {
+	Strategy   ContextStrategy // 为空时使用 truncate
+	WindowSize int             // 滑动窗口的消息数（只在 sliding_window 时使用，为 0 时使用默认值）
+}
+
+// Conversation 对话聚合根
+//
+// 对话属于单个用户，Model/Provider 为空时使用系统默认配置。
+type Conversation struct {
+	ID           string
+	UserID       string // 所属用户 ID
+	Title        string
+	Model        string // 回复使用的模型（可选）
+	Provider     string // 回复使用的提供商（可选）
+	MessageCount int    // 消息数量（冗余字段，发送消息时更新）
+	Context      ContextSettings
+	Summary      string // 较早消息的摘要（summarize 策略）
+	SummaryUntil string // 摘要包含的最后一条消息 ID（之后的消息不在摘要中）
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// 对话领域错误定义
+var (
+	ErrInvalidConversationTitle = fmt.Errorf("INVALID_CONVERSATION_TITLE: 对话标题不能为空且不能超过 200 字符")
+	ErrInvalidContextSettings   = fmt.Errorf("INVALID_CONTEXT_SETTINGS: 上下文策略必须是 truncate、summarize 或 sliding_window，窗口大小为 1-50")
+)
+
+// NewConversation 创建一个新的对话
+//
+// title 为空时使用 DefaultConversationTitle。
+func NewConversation(userID, title, modelName, provider string) (*Conversation, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+	if title == "" {
+		title = DefaultConversationTitle
+	}
+	if err := validateTitle(title); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Conversation{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Title:     title,
+		Model:     modelName,
+		Provider:  provider,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Rename 重命名对话
+func (c *Conversation) Rename(title string) error {
+	if err := validateTitle(title); err != nil {
+		return err
+	}
+	c.Title = title
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateContext 修改上下文设置
+//
+// 已有的摘要保留（切换回 summarize 时继续使用）。
+func (c *Conversation) UpdateContext(settings ContextSettings) error {
+	if settings.Strategy == "" {
+		settings.Strategy = ContextTruncate
+	}
+	switch settings.Strategy {
+	case ContextTruncate, ContextSummarize, ContextSlidingWindow:
+	default:
+		return ErrInvalidContextSettings
+	}
+	if settings.WindowSize < 0 || settings.WindowSize > MaxContextWindowSize {
+		return ErrInvalidContextSettings
+	}
+	c.Context = settings
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// ContextStrategy 返回生效的上下文策略（未设置时为 truncate）
+func (c *Conversation) ContextStrategy() ContextStrategy {
+	if c.Context.Strategy == "" {
+		return ContextTruncate
+	}
+	return c.Context.Strategy
+}
+
+// ContextWindowSize 返回滑动窗口的消息数（未设置时为 DefaultContextWindowSize）
+func (c *Conversation) ContextWindowSize() int {
+	if c.Context.WindowSize <= 0 {
+		return DefaultContextWindowSize
+	}
+	return c.Context.WindowSize
+}
+
+// RecordSummary 记录新的摘要（包含到 untilMessageID 为止的消息）
+func (c *Conversation) RecordSummary(summary, untilMessageID string) {
+	c.Summary = summary
+	c.SummaryUntil = untilMessageID
+	c.UpdatedAt = time.Now()
+}
+
+// RecordMessages 记录新增的消息
+//
+// 仍使用默认标题的对话以第一条用户消息自动命名。
+func (c *Conversation) RecordMessages(firstUserContent string, count int) {
+	if c.Title == DefaultConversationTitle && c.MessageCount == 0 && firstUserContent != "" {
+		c.Title = autoTitle(firstUserContent)
+	}
+	c.MessageCount += count
+	c.UpdatedAt = time.Now()
+}
+
+// IsOwnedBy 判断对话是否属于指定用户
+func (c *Conversation) IsOwnedBy(userID string) bool {
+	return c.UserID == userID
+}
+
+// validateTitle 验证标题（规则与 validator 的 conversation_title 一致）
+func validateTitle(title string) error {
+	if strings.TrimSpace(title) == "" || len(title) > MaxTitleLength {
+		return ErrInvalidConversationTitle
+	}
+	return nil
+}
+
+// autoTitle 根据消息内容生成标题（单行，最多 autoTitleRunes 个字符）
+func autoTitle(content string) string {
+	title := strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(title) > autoTitleRunes {
+		title = string([]rune(title)[:autoTitleRunes]) + "…"
+	}
+	if len(title) > MaxTitleLength || strings.TrimSpace(title) == "" {
+		return DefaultConversationTitle
+	}
+	return title
+}
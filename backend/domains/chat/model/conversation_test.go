@@ -0,0 +1,149 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewConversation 测试创建对话
+func TestNewConversation(t *testing.T) {
+	t.Run("未指定标题时使用默认标题", func(t *testing.T) {
+		conv, err := NewConversation("user-1", "", "", "")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, conv.ID)
+		assert.Equal(t, DefaultConversationTitle, conv.Title)
+		assert.Equal(t, 0, conv.MessageCount)
+	})
+
+	t.Run("用户 ID 为空", func(t *testing.T) {
+		_, err := NewConversation("", "title", "", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("标题只包含空白", func(t *testing.T) {
+		_, err := NewConversation("user-1", "   ", "", "")
+		assert.ErrorIs(t, err, ErrInvalidConversationTitle)
+	})
+
+	t.Run("标题过长", func(t *testing.T) {
+		_, err := NewConversation("user-1", strings.Repeat("a", MaxTitleLength+1), "", "")
+		assert.ErrorIs(t, err, ErrInvalidConversationTitle)
+	})
+}
+
+// TestConversation_Rename 测试重命名对话
+func TestConversation_Rename(t *testing.T) {
+	conv, _ := NewConversation("user-1", "old", "", "")
+
+	require.NoError(t, conv.Rename("new"))
+	assert.Equal(t, "new", conv.Title)
+
+	assert.ErrorIs(t, conv.Rename(""), ErrInvalidConversationTitle)
+	assert.Equal(t, "new", conv.Title)
+}
+
+// TestConversation_RecordMessages 测试记录消息与自动命名
+func TestConversation_RecordMessages(t *testing.T) {
+	t.Run("默认标题以第一条用户消息命名", func(t *testing.T) {
+		conv, _ := NewConversation("user-1", "", "", "")
+
+		conv.RecordMessages("  如何\n学习 Go？ ", 2)
+
+		assert.Equal(t, "如何 学习 Go？", conv.Title)
+		assert.Equal(t, 2, conv.MessageCount)
+	})
+
+	t.Run("长消息截断为 50 个字符", func(t *testing.T) {
+		conv, _ := NewConversation("user-1", "", "", "")
+
+		conv.RecordMessages(strings.Repeat("长", 80), 2)
+
+		assert.Equal(t, strings.Repeat("长", 50)+"…", conv.Title)
+	})
+
+	t.Run("自定义标题不被覆盖", func(t *testing.T) {
+		conv, _ := NewConversation("user-1", "My chat", "", "")
+
+		conv.RecordMessages("hello", 2)
+
+		assert.Equal(t, "My chat", conv.Title)
+	})
+
+	t.Run("已有消息时不再自动命名", func(t *testing.T) {
+		conv, _ := NewConversation("user-1", "", "", "")
+		conv.RecordMessages("", 1) // system 消息
+
+		conv.RecordMessages("hello", 2)
+
+		assert.Equal(t, DefaultConversationTitle, conv.Title)
+		assert.Equal(t, 3, conv.MessageCount)
+	})
+}
+
+// TestNewMessage 测试创建消息
+func TestNewMessage(t *testing.T) {
+	msg, err := NewMessage("conv-1", RoleUser, "hi")
+	require.NoError(t, err)
+	assert.Equal(t, RoleUser, msg.Role)
+
+	_, err = NewMessage("conv-1", Role("tool"), "hi")
+	assert.ErrorIs(t, err, ErrInvalidMessageRole)
+
+	_, err = NewMessage("conv-1", RoleUser, " \n ")
+	assert.ErrorIs(t, err, ErrMessageContentEmpty)
+
+	_, err = NewMessage("conv-1", RoleUser, strings.Repeat("a", MaxMessageLength+1))
+	assert.ErrorIs(t, err, ErrMessageTooLong)
+}
+
+// TestPendingToolCalls 测试查找等待确认的工具调用
+func TestPendingToolCalls(t *testing.T) {
+	user, _ := NewMessage("conv-1", RoleUser, "把财务任务推迟到下周")
+	call := NewAssistantMessage("conv-1", "", "m", "p", 1, 1, 0)
+	call.ToolCalls = []ToolCall{
+		{ID: "call-1", Name: "list_tasks", Arguments: "{}"},
+		{ID: "call-2", Name: "update_task", Arguments: `{"task_id":"t1"}`},
+	}
+	listResult := NewToolResultMessage("conv-1", call.ToolCalls[0], `{"status":"ok"}`)
+
+	t.Run("没有结果的调用等待确认", func(t *testing.T) {
+		pending := PendingToolCalls([]*Message{user, call, listResult})
+		require.Len(t, pending, 1)
+		assert.Equal(t, "call-2", pending[0].ID)
+		assert.Equal(t, RoleTool, listResult.Role)
+		assert.Equal(t, "list_tasks", listResult.ToolName)
+	})
+
+	t.Run("所有调用都有结果", func(t *testing.T) {
+		updateResult := NewToolResultMessage("conv-1", call.ToolCalls[1], `{"status":"rejected"}`)
+		assert.Empty(t, PendingToolCalls([]*Message{user, call, listResult, updateResult}))
+	})
+
+	t.Run("最后的 assistant 消息没有工具调用", func(t *testing.T) {
+		reply := NewAssistantMessage("conv-1", "好的", "m", "p", 1, 1, 0)
+		assert.Empty(t, PendingToolCalls([]*Message{user, call, reply}))
+		assert.Empty(t, PendingToolCalls([]*Message{user}))
+	})
+}
+
+// TestConversation_UpdateContext 测试修改上下文设置
+func TestConversation_UpdateContext(t *testing.T) {
+	conv, _ := NewConversation("user-1", "", "", "")
+	assert.Equal(t, ContextTruncate, conv.ContextStrategy())
+	assert.Equal(t, DefaultContextWindowSize, conv.ContextWindowSize())
+
+	require.NoError(t, conv.UpdateContext(ContextSettings{Strategy: ContextSlidingWindow, WindowSize: 10}))
+	assert.Equal(t, ContextSlidingWindow, conv.ContextStrategy())
+	assert.Equal(t, 10, conv.ContextWindowSize())
+
+	require.NoError(t, conv.UpdateContext(ContextSettings{}))
+	assert.Equal(t, ContextTruncate, conv.Context.Strategy)
+
+	assert.ErrorIs(t, conv.UpdateContext(ContextSettings{Strategy: "forget"}), ErrInvalidContextSettings)
+	assert.ErrorIs(t, conv.UpdateContext(ContextSettings{Strategy: ContextSlidingWindow, WindowSize: MaxContextWindowSize + 1}), ErrInvalidContextSettings)
+	assert.Equal(t, ContextTruncate, conv.Context.Strategy)
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Role 消息角色
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleSystem    Role = "system"
+	RoleTool      Role = "tool" // 工具调用结果（只由任务助手生成）
+)
+
+// MaxMessageLength 单条消息最大长度（字节）
+const MaxMessageLength = 32000
+
+// 消息领域错误定义
+var (
+	ErrMessageContentEmpty = fmt.Errorf("MESSAGE_CONTENT_EMPTY: 消息内容不能为空")
+	ErrMessageTooLong      = fmt.Errorf("MESSAGE_TOO_LONG: 消息过长，最大 32000 字符")
+	ErrInvalidMessageRole  = fmt.Errorf("INVALID_MESSAGE_ROLE: 只能发送 user 或 system 消息")
+
+	ErrToolConfirmationPending = fmt.Errorf("TOOL_CONFIRMATION_PENDING: 有等待确认的操作，请先确认或拒绝")
+	ErrNoPendingToolCalls      = fmt.Errorf("NO_PENDING_TOOL_CALLS: 没有等待确认的操作")
+)
+
+// IsValid 验证角色是否可以由用户发送（tool 消息只由任务助手生成）
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleAssistant, RoleSystem:
+		return true
+	default:
+		return false
+	}
+}
+
+// ToolCall 模型请求的工具调用（记录在 assistant 消息上）
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON 字符串
+}
+
+// Citation 回复引用的资料（客户端据此链接到资料，如任务详情）
+type Citation struct {
+	Source string `json:"source"` // 资料类型（如 task）
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+}
+
+// Message 对话消息实体
+//
+// Model/Provider/Tokens/Latency/Truncated 只在 assistant 消息上记录。
+// 任务助手的工具调用记录为带 ToolCalls 的 assistant 消息，
+// 每个调用的结果记录为一条 tool 消息（ToolCallID 对应调用 ID）。
+// 基于资料的回复（SendGroundedMessage）在 Citations 中记录引用的资料。
+type Message struct {
+	ID             string
+	ConversationID string
+	Role           Role
+	Content        string
+	Model          string
+	Provider       string
+	InputTokens    int
+	OutputTokens   int
+	LatencyMs      int64
+	Truncated      bool       // 流式回复在完成前被中断（客户端断开或上游出错），内容不完整
+	ToolCalls      []ToolCall // assistant 消息请求的工具调用
+	ToolCallID     string     // tool 消息对应的调用 ID
+	ToolName       string     // tool 消息对应的工具名称
+	Citations      []Citation // assistant 消息引用的资料
+	CreatedAt      time.Time
+}
+
+// NewMessage 创建一条消息
+func NewMessage(conversationID string, role Role, content string) (*Message, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidMessageRole
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrMessageContentEmpty
+	}
+	if len(content) > MaxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+
+	return &Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Role:           role,
+		Content:        content,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// NewAssistantMessage 创建模型回复消息
+//
+// 模型输出不做长度和空内容校验（如被过滤时内容可能为空）。
+func NewAssistantMessage(conversationID, content, modelName, provider string, inputTokens, outputTokens int, latencyMs int64) *Message {
+	return &Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Role:           RoleAssistant,
+		Content:        content,
+		Model:          modelName,
+		Provider:       provider,
+		InputTokens:    inputTokens,
+		OutputTokens:   outputTokens,
+		LatencyMs:      latencyMs,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// NewToolResultMessage 创建工具调用结果消息（content 为结果 JSON）
+func NewToolResultMessage(conversationID string, call ToolCall, content string) *Message {
+	return &Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		Role:           RoleTool,
+		Content:        content,
+		ToolCallID:     call.ID,
+		ToolName:       call.Name,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// PendingToolCalls 返回等待用户确认的工具调用
+//
+// 即最后一条带工具调用的 assistant 消息中还没有结果消息的调用。
+// messages 按时间正序排列。
+func PendingToolCalls(messages []*Message) []ToolCall {
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		if msg.Role != RoleAssistant {
+			continue
+		}
+		if len(msg.ToolCalls) == 0 {
+			return nil
+		}
+
+		answered := make(map[string]bool)
+		for _, m := range messages[i+1:] {
+			if m.Role == RoleTool {
+				answered[m.ToolCallID] = true
+			}
+		}
+		var pending []ToolCall
+		for _, call := range msg.ToolCalls {
+			if !answered[call.ID] {
+				pending = append(pending, call)
+			}
+		}
+		return pending
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// 错误定义
+var (
+	ErrConversationNotFound = errors.New("CONVERSATION_NOT_FOUND: 对话不存在")
+)
+
+// rowScanner 抽象 *sql.Row 和 *sql.Rows 的 Scan 方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// newDialect 根据数据库类型选择 goqu 方言
+func newDialect(dbType string) goqu.DialectWrapper {
+	switch dbType {
+	case "mysql":
+		return goqu.Dialect("mysql")
+	case "sqlite":
+		return goqu.Dialect("sqlite3")
+	default:
+		return goqu.Dialect("postgres")
+	}
+}
+
+// nullString 空字符串存储为 NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// ConversationRepositoryImpl 对话仓储实现
+type ConversationRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewConversationRepository 创建对话仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewConversationRepository(db *sql.DB, dbType string) *ConversationRepositoryImpl {
+	return &ConversationRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *ConversationRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// conversationColumns conversations 表的查询/插入列（顺序与 scanConversation 保持一致）
+var conversationColumns = []interface{}{
+	"id", "user_id", "title", "model", "provider", "message_count",
+	"context_strategy", "context_window_size", "summary", "summary_until",
+	"created_at", "updated_at",
+}
+
+// nullInt 0 存储为 NULL
+func nullInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+// Create 创建对话
+func (r *ConversationRepositoryImpl) Create(ctx context.Context, conv *model.Conversation) error {
+	query, args, err := r.dialect.Insert("conversations").
+		Cols(conversationColumns...).
+		Vals(goqu.Vals{
+			conv.ID,
+			conv.UserID,
+			conv.Title,
+			nullString(conv.Model),
+			nullString(conv.Provider),
+			conv.MessageCount,
+			nullString(string(conv.Context.Strategy)),
+			nullInt(conv.Context.WindowSize),
+			nullString(conv.Summary),
+			nullString(conv.SummaryUntil),
+			conv.CreatedAt,
+			conv.UpdatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert conversation query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create conversation failed: %w", err)
+	}
+	return nil
+}
+
+// FindByID 根据 ID 查找对话
+func (r *ConversationRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Conversation, error) {
+	query, args, err := r.dialect.From("conversations").
+		Select(conversationColumns...).
+		Where(goqu.C("id").Eq(id)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build select conversation query failed: %w", err)
+	}
+
+	conv, err := scanConversation(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrConversationNotFound
+		}
+		return nil, fmt.Errorf("query conversation failed: %w", err)
+	}
+	return conv, nil
+}
+
+// Update 更新对话
+func (r *ConversationRepositoryImpl) Update(ctx context.Context, conv *model.Conversation) error {
+	query, args, err := r.dialect.Update("conversations").
+		Set(conversationRecord(conv)).
+		Where(goqu.C("id").Eq(conv.ID)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build update conversation query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update conversation failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+// UpdateIfMessageCount 仅当消息数量仍为 messageCount 时更新对话（条件更新）
+func (r *ConversationRepositoryImpl) UpdateIfMessageCount(ctx context.Context, conv *model.Conversation, messageCount int) (bool, error) {
+	query, args, err := r.dialect.Update("conversations").
+		Set(conversationRecord(conv)).
+		Where(
+			goqu.C("id").Eq(conv.ID),
+			goqu.C("message_count").Eq(messageCount),
+		).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("build update conversation query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("update conversation failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected failed: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// conversationRecord 更新对话时写入的列
+func conversationRecord(conv *model.Conversation) goqu.Record {
+	return goqu.Record{
+		"title":               conv.Title,
+		"model":               nullString(conv.Model),
+		"provider":            nullString(conv.Provider),
+		"message_count":       conv.MessageCount,
+		"context_strategy":    nullString(string(conv.Context.Strategy)),
+		"context_window_size": nullInt(conv.Context.WindowSize),
+		"summary":             nullString(conv.Summary),
+		"summary_until":       nullString(conv.SummaryUntil),
+		"updated_at":          conv.UpdatedAt,
+	}
+}
+
+// Delete 删除对话
+func (r *ConversationRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query, args, err := r.dialect.Delete("conversations").
+		Where(goqu.C("id").Eq(id)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build delete conversation query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete conversation failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+// ListByUser 列出用户的对话
+func (r *ConversationRepositoryImpl) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Conversation, int, error) {
+	where := goqu.C("user_id").Eq(userID)
+
+	// 1. 查询总数
+	countQuery, countArgs, err := r.dialect.From("conversations").
+		Select(goqu.COUNT("*")).
+		Where(where).
+		ToSQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count conversations query failed: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count conversations failed: %w", err)
+	}
+
+	// 2. 查询当前页
+	query, args, err := r.dialect.From("conversations").
+		Select(conversationColumns...).
+		Where(where).
+		Order(goqu.C("updated_at").Desc()).
+		Limit(uint(limit)).
+		Offset(uint(offset)).
+		ToSQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build list conversations query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query conversations failed: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := make([]*model.Conversation, 0)
+	for rows.Next() {
+		conv, err := scanConversation(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan conversation failed: %w", err)
+		}
+		conversations = append(conversations, conv)
+	}
+
+	return conversations, total, rows.Err()
+}
+
+// scanConversation 按 conversationColumns 的顺序扫描一行对话数据
+func scanConversation(row rowScanner) (*model.Conversation, error) {
+	conv := &model.Conversation{}
+	var modelName, provider, strategy, summary, summaryUntil sql.NullString
+	var windowSize sql.NullInt64
+	err := row.Scan(
+		&conv.ID,
+		&conv.UserID,
+		&conv.Title,
+		&modelName,
+		&provider,
+		&conv.MessageCount,
+		&strategy,
+		&windowSize,
+		&summary,
+		&summaryUntil,
+		&conv.CreatedAt,
+		&conv.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	conv.Model = modelName.String
+	conv.Provider = provider.String
+	conv.Context = model.ContextSettings{
+		Strategy:   model.ContextStrategy(strategy.String),
+		WindowSize: int(windowSize.Int64),
+	}
+	conv.Summary = summary.String
+	conv.SummaryUntil = summaryUntil.String
+	return conv, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConversationRepository_Create 测试创建对话（空的 model/provider 存储为 NULL）
+func TestConversationRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversationRepository(db, "postgres")
+	conv, _ := model.NewConversation("user-1", "Hello", "", "")
+
+	mock.ExpectExec(`INSERT INTO "conversations" .+'Hello', NULL, NULL, 0`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), conv))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestConversationRepository_FindByID 测试根据 ID 查找对话
+func TestConversationRepository_FindByID(t *testing.T) {
+	t.Run("找到对话", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewConversationRepository(db, "postgres")
+		now := time.Now()
+		rows := sqlmock.NewRows([]string{
+			"id", "user_id", "title", "model", "provider", "message_count",
+			"context_strategy", "context_window_size", "summary", "summary_until", "created_at", "updated_at",
+		}).AddRow("conv-1", "user-1", "Hello", "gpt-4o-mini", nil, 4, "sliding_window", 10, "Earlier we planned a trip", "msg-9", now, now)
+		mock.ExpectQuery(`SELECT .+ FROM "conversations" WHERE \("id" = 'conv-1'\)`).
+			WillReturnRows(rows)
+
+		conv, err := repo.FindByID(context.Background(), "conv-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, "gpt-4o-mini", conv.Model)
+		assert.Equal(t, "", conv.Provider)
+		assert.Equal(t, 4, conv.MessageCount)
+		assert.Equal(t, model.ContextSettings{Strategy: model.ContextSlidingWindow, WindowSize: 10}, conv.Context)
+		assert.Equal(t, "Earlier we planned a trip", conv.Summary)
+		assert.Equal(t, "msg-9", conv.SummaryUntil)
+	})
+
+	t.Run("对话不存在", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewConversationRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT .+ FROM "conversations"`).WillReturnError(sql.ErrNoRows)
+
+		_, err = repo.FindByID(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, ErrConversationNotFound)
+	})
+}
+
+// TestConversationRepository_Delete_NotFound 测试删除不存在的对话
+func TestConversationRepository_Delete_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversationRepository(db, "postgres")
+	mock.ExpectExec(`DELETE FROM "conversations"`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+}
+
+// TestConversationRepository_UpdateIfMessageCount 测试按消息数量条件更新对话
+func TestConversationRepository_UpdateIfMessageCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    int64
+		updated bool
+	}{
+		{"消息数量未变化", 1, true},
+		{"消息数量已变化", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewConversationRepository(db, "postgres")
+			conv, _ := model.NewConversation("user-1", "Hello", "", "")
+			conv.ID = "conv-1"
+			conv.MessageCount = 3
+			mock.ExpectExec(`UPDATE "conversations" SET .+"message_count"=3,.+WHERE \(\("id" = 'conv-1'\) AND \("message_count" = 2\)\)`).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+
+			updated, err := repo.UpdateIfMessageCount(context.Background(), conv, 2)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.updated, updated)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestConversationRepository_ListByUser 测试分页列出用户对话
+func TestConversationRepository_ListByUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewConversationRepository(db, "postgres")
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "conversations" WHERE \("user_id" = 'user-1'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT .+ FROM "conversations" .+ORDER BY "updated_at" DESC LIMIT 2 OFFSET 1`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "title", "model", "provider", "message_count",
+			"context_strategy", "context_window_size", "summary", "summary_until", "created_at", "updated_at",
+		}).
+			AddRow("conv-2", "user-1", "B", nil, nil, 0, nil, nil, nil, nil, now, now).
+			AddRow("conv-3", "user-1", "C", nil, nil, 2, nil, nil, nil, nil, now, now))
+
+	conversations, total, err := repo.ListByUser(context.Background(), "user-1", 2, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, conversations, 2)
+	assert.Equal(t, "conv-2", conversations[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+)
+
+// ConversationRepository 定义对话仓储接口
+type ConversationRepository interface {
+	// Create 保存一个新的对话
+	Create(ctx context.Context, conv *model.Conversation) error
+
+	// FindByID 根据 ID 查找对话
+	FindByID(ctx context.Context, id string) (*model.Conversation, error)
+
+	// Update 更新对话（标题、模型、消息数量、上下文设置和摘要）
+	Update(ctx context.Context, conv *model.Conversation) error
+
+	// UpdateIfMessageCount 仅当数据库中的消息数量仍为 messageCount 时更新对话（条件更新）
+	//
+	// 返回 false 表示读取对话后有其他请求保存了消息，没有更新。
+	// 在事务中调用时会锁定对话行，并发的条件更新等待提交后再判断条件。
+	UpdateIfMessageCount(ctx context.Context, conv *model.Conversation, messageCount int) (bool, error)
+
+	// Delete 删除对话（消息由外键级联删除）
+	Delete(ctx context.Context, id string) error
+
+	// ListByUser 列出用户的对话（按最近更新倒序）
+	// 返回对话列表、总数和错误
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]*model.Conversation, int, error)
+}
+
+// MessageRepository 定义消息仓储接口
+type MessageRepository interface {
+	// Create 保存一条消息
+	Create(ctx context.Context, msg *model.Message) error
+
+	// ListByConversation 按时间正序列出对话中的消息
+	// 返回消息列表、总数和错误
+	ListByConversation(ctx context.Context, conversationID string, limit, offset int) ([]*model.Message, int, error)
+
+	// ListRecent 返回对话中最近的 limit 条消息（按时间正序，用于构造模型上下文）
+	ListRecent(ctx context.Context, conversationID string, limit int) ([]*model.Message, error)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// MessageRepositoryImpl 消息仓储实现
+type MessageRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewMessageRepository 创建消息仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewMessageRepository(db *sql.DB, dbType string) *MessageRepositoryImpl {
+	return &MessageRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *MessageRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// messageColumns messages 表的查询/插入列（顺序与 scanMessage 保持一致）
+var messageColumns = []interface{}{
+	"id", "conversation_id", "role", "content", "model", "provider",
+	"input_tokens", "output_tokens", "latency_ms", "truncated",
+	"tool_calls", "tool_call_id", "tool_name", "citations", "created_at",
+}
+
+// Create 保存一条消息
+func (r *MessageRepositoryImpl) Create(ctx context.Context, msg *model.Message) error {
+	toolCalls, err := encodeJSONList(msg.ToolCalls)
+	if err != nil {
+		return fmt.Errorf("encode tool calls failed: %w", err)
+	}
+	citations, err := encodeJSONList(msg.Citations)
+	if err != nil {
+		return fmt.Errorf("encode citations failed: %w", err)
+	}
+
+	query, args, err := r.dialect.Insert("messages").
+		Cols(messageColumns...).
+		Vals(goqu.Vals{
+			msg.ID,
+			msg.ConversationID,
+			string(msg.Role),
+			msg.Content,
+			nullString(msg.Model),
+			nullString(msg.Provider),
+			msg.InputTokens,
+			msg.OutputTokens,
+			msg.LatencyMs,
+			msg.Truncated,
+			toolCalls,
+			nullString(msg.ToolCallID),
+			nullString(msg.ToolName),
+			citations,
+			msg.CreatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert message query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create message failed: %w", err)
+	}
+	return nil
+}
+
+// ListByConversation 按时间正序列出对话中的消息
+func (r *MessageRepositoryImpl) ListByConversation(ctx context.Context, conversationID string, limit, offset int) ([]*model.Message, int, error) {
+	where := goqu.C("conversation_id").Eq(conversationID)
+
+	// 1. 查询总数
+	countQuery, countArgs, err := r.dialect.From("messages").
+		Select(goqu.COUNT("*")).
+		Where(where).
+		ToSQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build count messages query failed: %w", err)
+	}
+
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count messages failed: %w", err)
+	}
+
+	// 2. 查询当前页
+	query, args, err := r.dialect.From("messages").
+		Select(messageColumns...).
+		Where(where).
+		Order(goqu.C("created_at").Asc(), goqu.C("id").Asc()).
+		Limit(uint(limit)).
+		Offset(uint(offset)).
+		ToSQL()
+	if err != nil {
+		return nil, 0, fmt.Errorf("build list messages query failed: %w", err)
+	}
+
+	messages, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// ListRecent 返回对话中最近的 limit 条消息（按时间正序）
+func (r *MessageRepositoryImpl) ListRecent(ctx context.Context, conversationID string, limit int) ([]*model.Message, error) {
+	query, args, err := r.dialect.From("messages").
+		Select(messageColumns...).
+		Where(goqu.C("conversation_id").Eq(conversationID)).
+		Order(goqu.C("created_at").Desc(), goqu.C("id").Desc()).
+		Limit(uint(limit)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list recent messages query failed: %w", err)
+	}
+
+	messages, err := r.query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// 倒序查询后反转为时间正序
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// query 执行查询并扫描消息列表
+func (r *MessageRepositoryImpl) query(ctx context.Context, query string, args []interface{}) ([]*model.Message, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query messages failed: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]*model.Message, 0)
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan message failed: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// scanMessage 按 messageColumns 的顺序扫描一行消息数据
+func scanMessage(row rowScanner) (*model.Message, error) {
+	msg := &model.Message{}
+	var (
+		role                 string
+		modelName, provider  sql.NullString
+		toolCalls, citations sql.NullString
+		toolCallID, toolName sql.NullString
+	)
+	err := row.Scan(
+		&msg.ID,
+		&msg.ConversationID,
+		&role,
+		&msg.Content,
+		&modelName,
+		&provider,
+		&msg.InputTokens,
+		&msg.OutputTokens,
+		&msg.LatencyMs,
+		&msg.Truncated,
+		&toolCalls,
+		&toolCallID,
+		&toolName,
+		&citations,
+		&msg.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	msg.Role = model.Role(role)
+	msg.Model = modelName.String
+	msg.Provider = provider.String
+	msg.ToolCallID = toolCallID.String
+	msg.ToolName = toolName.String
+	if toolCalls.Valid && toolCalls.String != "" {
+		if err := json.Unmarshal([]byte(toolCalls.String), &msg.ToolCalls); err != nil {
+			return nil, fmt.Errorf("decode tool calls failed: %w", err)
+		}
+	}
+	if citations.Valid && citations.String != "" {
+		if err := json.Unmarshal([]byte(citations.String), &msg.Citations); err != nil {
+			return nil, fmt.Errorf("decode citations failed: %w", err)
+		}
+	}
+	return msg, nil
+}
+
+// encodeJSONList 工具调用和引用以 JSON 数组存储（为空时为 NULL）
+func encodeJSONList[T any](items []T) (interface{}, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
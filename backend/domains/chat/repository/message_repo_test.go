@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessageColumns = []string{
+	"id", "conversation_id", "role", "content", "model", "provider",
+	"input_tokens", "output_tokens", "latency_ms", "truncated",
+	"tool_calls", "tool_call_id", "tool_name", "citations", "created_at",
+}
+
+// TestMessageRepository_Create 测试保存模型回复
+func TestMessageRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db, "postgres")
+	msg := model.NewAssistantMessage("conv-1", "Hi!", "mock-model", "mock", 3, 1, 12)
+
+	mock.ExpectExec(`INSERT INTO "messages" .+'assistant', 'Hi!', 'mock-model', 'mock', 3, 1, 12, FALSE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), msg))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMessageRepository_ListRecent 测试最近消息按时间正序返回
+func TestMessageRepository_ListRecent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db, "postgres")
+	now := time.Now()
+
+	// 数据库按时间倒序返回最近的消息
+	mock.ExpectQuery(`SELECT .+ FROM "messages" .+ORDER BY "created_at" DESC, "id" DESC LIMIT 2`).
+		WillReturnRows(sqlmock.NewRows(testMessageColumns).
+			AddRow("m3", "conv-1", "assistant", "third", "m", "mock", 1, 1, 5, false, nil, nil, nil, nil, now).
+			AddRow("m2", "conv-1", "user", "second", nil, nil, 0, 0, 0, false, nil, nil, nil, nil, now.Add(-time.Second)))
+
+	messages, err := repo.ListRecent(context.Background(), "conv-1", 2)
+
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "m2", messages[0].ID)
+	assert.Equal(t, model.RoleUser, messages[0].Role)
+	assert.Equal(t, "m3", messages[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMessageRepository_ToolCalls 测试工具调用以 JSON 存储并还原
+func TestMessageRepository_ToolCalls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db, "postgres")
+	call := model.NewAssistantMessage("conv-1", "", "mock-model", "mock", 3, 1, 12)
+	call.ToolCalls = []model.ToolCall{{ID: "call-1", Name: "complete_task", Arguments: `{"task_id":"t1"}`}}
+	result := model.NewToolResultMessage("conv-1", call.ToolCalls[0], `{"status":"ok"}`)
+
+	mock.ExpectExec(`INSERT INTO "messages" .+'assistant', '', .+FALSE, '\[{"id":"call-1","name":"complete_task","arguments":"{\\"task_id\\":\\"t1\\"}"}\]', NULL, NULL`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO "messages" .+'tool', '{"status":"ok"}', NULL, NULL, 0, 0, 0, FALSE, NULL, 'call-1', 'complete_task'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT .+ FROM "messages"`).
+		WillReturnRows(sqlmock.NewRows(testMessageColumns).
+			AddRow(result.ID, "conv-1", "tool", result.Content, nil, nil, 0, 0, 0, false, nil, "call-1", "complete_task", nil, result.CreatedAt).
+			AddRow(call.ID, "conv-1", "assistant", "", "m", "mock", 1, 1, 5, false, `[{"id":"call-1","name":"complete_task","arguments":"{}"}]`, nil, nil, nil, call.CreatedAt))
+
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, call))
+	require.NoError(t, repo.Create(ctx, result))
+	messages, err := repo.ListRecent(ctx, "conv-1", 10)
+
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, []model.ToolCall{{ID: "call-1", Name: "complete_task", Arguments: "{}"}}, messages[0].ToolCalls)
+	assert.Equal(t, model.RoleTool, messages[1].Role)
+	assert.Equal(t, "call-1", messages[1].ToolCallID)
+	assert.Equal(t, "complete_task", messages[1].ToolName)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestMessageRepository_Citations 测试引用以 JSON 存储并还原
+func TestMessageRepository_Citations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewMessageRepository(db, "postgres")
+	reply := model.NewAssistantMessage("conv-1", "Pay the bill [task:t1]", "mock-model", "mock", 3, 1, 12)
+	reply.Citations = []model.Citation{{Source: "task", ID: "t1", Title: "Pay bill"}}
+
+	mock.ExpectExec(`INSERT INTO "messages" .+FALSE, NULL, NULL, NULL, '\[{"source":"task","id":"t1","title":"Pay bill"}\]'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`SELECT .+ FROM "messages"`).
+		WillReturnRows(sqlmock.NewRows(testMessageColumns).
+			AddRow(reply.ID, "conv-1", "assistant", reply.Content, "m", "mock", 1, 1, 5, false, nil, nil, nil, `[{"source":"task","id":"t1","title":"Pay bill"}]`, reply.CreatedAt))
+
+	ctx := context.Background()
+	require.NoError(t, repo.Create(ctx, reply))
+	messages, err := repo.ListRecent(ctx, "conv-1", 10)
+
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, reply.Citations, messages[0].Citations)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
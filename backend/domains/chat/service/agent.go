@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tool"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultAgentMaxSteps 任务助手每次运行默认最多调用模型的次数
+const DefaultAgentMaxSteps = 8
+
+// agentInstructions 任务助手的系统提示（%s 为当前时间）
+const agentInstructions = `你是用户的任务助手，可以调用工具查询和修改用户的任务。
+当前时间：%s。
+- 需要任务 ID 等信息时先调用工具查询，不要猜测
+- 修改或完成任务需要用户确认；用户拒绝后不要重试同一操作
+- 完成后用一两句话告诉用户做了什么`
+
+// ErrAgentDisabled 未配置任务助手的工具集
+var ErrAgentDisabled = fmt.Errorf("AGENT_DISABLED: 未开启任务助手")
+
+// ToolsetFactory 为用户创建工具集
+//
+// 工具通过闭包绑定 userID，只能访问该用户的数据（模型无法指定用户）。
+type ToolsetFactory func(userID string) (*tool.Registry, error)
+
+// WithAgent 开启任务助手
+//
+// 参数：
+//   - tools: 工具集（按用户创建）
+//   - maxSteps: 每次运行最多调用模型的次数（<= 0 使用 DefaultAgentMaxSteps）
+func (s *ChatService) WithAgent(tools ToolsetFactory, maxSteps int) *ChatService {
+	if maxSteps <= 0 {
+		maxSteps = DefaultAgentMaxSteps
+	}
+	s.agentTools = tools
+	s.agentMaxSteps = maxSteps
+	return s
+}
+
+// RunAgentInput 向任务助手发送消息输入
+type RunAgentInput struct {
+	UserID         string // 用户 ID（从 JWT 获取，工具只能访问该用户的数据）
+	ConversationID string
+	Content        string
+	Strategy       llmmodel.Strategy // 模型路由策略（可选，对话指定了提供商和模型时不生效）
+}
+
+// ConfirmToolCallsInput 确认工具调用输入
+type ConfirmToolCallsInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Approve        bool // true 执行等待确认的调用，false 拒绝
+	Strategy       llmmodel.Strategy
+}
+
+// AgentOutput 任务助手运行结果
+//
+// Reply 为空时说明运行暂停：PendingToolCalls 不为空时等待用户确认，
+// 否则达到了步数上限（StepLimitReached）。
+type AgentOutput struct {
+	Conversation     *model.Conversation
+	Messages         []*model.Message // 本次运行新增的消息（按时间正序，包括工具调用和结果）
+	Reply            *model.Message   // 最终回复
+	PendingToolCalls []model.ToolCall // 等待用户确认的工具调用
+	StepLimitReached bool
+}
+
+// RunAgent 向任务助手发送消息（用例实现）
+//
+// 对应 usecases.yaml 中的 RunAgent
+//
+// 步骤：
+//  1. GetConversation - 获取对话并验证所有权
+//  2. CreateMessageEntity - 内容审核后创建用户消息（含验证）
+//  3. LoadHistory - 加载历史消息（有等待确认的调用时拒绝）
+//  4. SaveUserMessage - 保存用户消息
+//  5. AgentLoop - 调用模型并执行工具，直到模型给出回复、需要确认或达到步数上限
+//
+// 每一步的工具调用和结果都立即保存到对话中；模型调用失败时已保存的记录保留。
+func (s *ChatService) RunAgent(ctx context.Context, input RunAgentInput) (*AgentOutput, error) {
+	// Step 1: GetConversation
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := s.toolset(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CreateMessageEntity
+	content, err := s.moderateInput(ctx, conv, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := model.NewMessage(conv.ID, model.RoleUser, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: LoadHistory
+	history, err := s.loadHistory(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: SaveUserMessage
+	if err := s.saveMessages(ctx, conv, msg.Content, msg); err != nil {
+		return nil, err
+	}
+	s.publishMessageSent(ctx, conv, msg, conv.Model)
+
+	// Step 5: AgentLoop
+	output := &AgentOutput{Conversation: conv, Messages: []*model.Message{msg}}
+	if err := s.runAgentLoop(ctx, conv, tools, append(history, msg), input.Strategy, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ConfirmToolCalls 确认或拒绝等待确认的工具调用，然后继续运行任务助手（用例实现）
+//
+// 对应 usecases.yaml 中的 ConfirmToolCalls
+//
+// 拒绝的调用记录为 rejected 结果，模型据此回复用户。继续运行时步数重新计算。
+// 同一批调用只能确认一次：并发的确认中只有一个执行调用，其他返回 NO_PENDING_TOOL_CALLS。
+func (s *ChatService) ConfirmToolCalls(ctx context.Context, input ConfirmToolCallsInput) (*AgentOutput, error) {
+	// Step 1: GetConversation
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := s.toolset(input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: FindPendingToolCalls
+	history, err := s.messageRepo.ListRecent(ctx, conv.ID, contextMessageLimit)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询历史消息失败")
+	}
+	pending := model.PendingToolCalls(history)
+	if len(pending) == 0 {
+		return nil, model.ErrNoPendingToolCalls
+	}
+
+	// Step 3: ClaimAndExecute
+	results, err := s.claimToolCalls(ctx, conv, tools, pending, input.Approve)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: AgentLoop
+	output := &AgentOutput{Conversation: conv, Messages: results}
+	if err := s.runAgentLoop(ctx, conv, tools, append(history, results...), input.Strategy, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// claimToolCalls 认领等待确认的调用，执行或拒绝后保存结果
+//
+// 在同一事务中先按读取时的消息数量条件更新对话（认领），再执行调用并保存结果：
+// 条件更新锁定对话行，并发的确认等待提交后发现消息数量已变化，返回 NO_PENDING_TOOL_CALLS，
+// 不会重复执行调用。保存失败时回滚认领。
+func (s *ChatService) claimToolCalls(ctx context.Context, conv *model.Conversation, tools *tool.Registry, pending []model.ToolCall, approve bool) ([]*model.Message, error) {
+	expected := conv.MessageCount
+	conv.RecordMessages("", len(pending))
+
+	results := make([]*model.Message, 0, len(pending))
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := s.conversationRepo.UpdateIfMessageCount(ctx, conv, expected)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return model.ErrNoPendingToolCalls
+		}
+
+		for _, call := range pending {
+			if approve {
+				results = append(results, s.callTool(ctx, conv, tools, call))
+			} else {
+				results = append(results, model.NewToolResultMessage(conv.ID, call, tool.Rejected().JSON()))
+			}
+		}
+		for _, m := range results {
+			if err := s.messageRepo.Create(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, model.ErrNoPendingToolCalls) {
+		return nil, model.ErrNoPendingToolCalls
+	}
+	if err != nil {
+		return nil, fmt.Errorf("CREATION_FAILED: 保存消息失败: %w", err)
+	}
+	return results, nil
+}
+
+// toolset 创建当前用户的工具集
+func (s *ChatService) toolset(userID string) (*tool.Registry, error) {
+	if s.agentTools == nil {
+		return nil, ErrAgentDisabled
+	}
+	tools, err := s.agentTools(userID)
+	if err != nil {
+		return nil, fmt.Errorf("AGENT_FAILED: 创建工具集失败: %w", err)
+	}
+	return tools, nil
+}
+
+// runAgentLoop 运行任务助手
+//
+// 每一步调用一次模型：
+//   - 模型没有请求工具调用：保存回复并结束
+//   - 否则执行不需要确认的调用；有需要确认的调用时保存记录后暂停，等待 ConfirmToolCalls
+//
+// 达到步数上限时停止（StepLimitReached），已执行的调用都已保存。
+func (s *ChatService) runAgentLoop(ctx context.Context, conv *model.Conversation, tools *tool.Registry, history []*model.Message, strategy llmmodel.Strategy, output *AgentOutput) error {
+	for step := 0; step < s.agentMaxSteps; step++ {
+		// Generate（系统提示和工具定义占用的 Token 从上下文窗口中扣除）
+		instructions := fmt.Sprintf(agentInstructions, s.now().Format(time.RFC3339))
+		definitions := tools.Definitions()
+		reserved := s.contextManager.CountText(instructions) + s.contextManager.CountTools(definitions)
+		req, _, err := s.assemble(ctx, conv, history, reserved)
+		if err != nil {
+			return err
+		}
+		req.Messages = append([]llmmodel.Message{{Role: llmmodel.RoleSystem, Content: instructions}}, req.Messages...)
+		req.Tools = definitions
+		req.Strategy = strategy
+
+		start := time.Now()
+		resp, err := s.llmService.Complete(ctx, req)
+		if err != nil {
+			return generationError(err)
+		}
+		reply := model.NewAssistantMessage(conv.ID, resp.Message.Content, resp.Model, resp.Provider,
+			resp.Usage.InputTokens, resp.Usage.OutputTokens, time.Since(start).Milliseconds())
+		s.moderateReply(ctx, conv, reply)
+
+		// Reply：没有工具调用时结束
+		if len(resp.Message.ToolCalls) == 0 {
+			if err := s.saveMessages(ctx, conv, "", reply); err != nil {
+				return err
+			}
+			s.publishMessageReceived(ctx, conv, reply)
+			output.Messages = append(output.Messages, reply)
+			output.Reply = reply
+			return nil
+		}
+
+		// Act：执行不需要确认的调用
+		for _, call := range resp.Message.ToolCalls {
+			reply.ToolCalls = append(reply.ToolCalls, model.ToolCall(call))
+		}
+		messages := []*model.Message{reply}
+		var pending []model.ToolCall
+		for _, call := range reply.ToolCalls {
+			if tools.RequiresConfirmation(llmmodel.ToolCall(call)) {
+				pending = append(pending, call)
+				continue
+			}
+			messages = append(messages, s.callTool(ctx, conv, tools, call))
+		}
+		if err := s.saveMessages(ctx, conv, "", messages...); err != nil {
+			return err
+		}
+		output.Messages = append(output.Messages, messages...)
+		history = append(history, messages...)
+
+		if len(pending) > 0 {
+			output.PendingToolCalls = pending
+			return nil
+		}
+	}
+
+	output.StepLimitReached = true
+	logger.Warn("agent step limit reached",
+		zap.String("conversation_id", conv.ID),
+		zap.Int("max_steps", s.agentMaxSteps),
+	)
+	return nil
+}
+
+// callTool 执行工具调用并创建结果消息
+func (s *ChatService) callTool(ctx context.Context, conv *model.Conversation, tools *tool.Registry, call model.ToolCall) *model.Message {
+	result := tools.Call(ctx, llmmodel.ToolCall(call))
+	logger.Info("agent tool called",
+		zap.String("conversation_id", conv.ID),
+		zap.String("tool", call.Name),
+		zap.String("status", string(result.Status)),
+	)
+	return model.NewToolResultMessage(conv.ID, call, result.JSON())
+}
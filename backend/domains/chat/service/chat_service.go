@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/repository"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+	"go.uber.org/zap"
+)
+
+// contextMessageLimit 发送消息时带入模型上下文的历史消息数
+const contextMessageLimit = 50
+
+// 分页默认值
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// ChatService 对话领域服务
+//
+// 职责：
+// - 管理用户的对话（创建、列出、重命名、删除）
+// - 发送消息：调用 LLMService 生成回复，用户消息和回复在同一事务中保存
+// - 基于资料的回答：检索用户的资料放入提示词，回复引用资料（见 grounded.go）
+// - 上下文窗口管理：历史消息超出模型的上下文窗口时按对话的策略裁剪或摘要（见 context.go）
+// - 内容安全：用户消息在保存和发送前、模型回复在保存前经过内容审核（见 moderation.go）
+// - 发布 chat 领域事件（ConversationCreated、ConversationDeleted、MessageSent、MessageReceived）
+//
+// 模型调用失败时不保存任何消息，客户端可以直接重试。
+type ChatService struct {
+	conversationRepo repository.ConversationRepository
+	messageRepo      repository.MessageRepository
+	llmService       *llmservice.LLMService
+	txManager        persistence.TxManager
+	eventBus         sharedevents.EventBus
+
+	agentTools    ToolsetFactory // 任务助手的工具集（为 nil 时不支持任务助手）
+	agentMaxSteps int
+
+	retriever            retrieval.Retriever // 资料检索（为 nil 时不支持基于资料的回答）
+	retrievalTokenBudget int
+
+	contextManager *ContextManager // 按模型的上下文窗口选择历史消息（见 context_window.go）
+
+	moderation *moderation.Pipeline // 内容审核（为 nil 时不审核）
+
+	now func() time.Time // 当前时间（写入系统提示，测试时可替换）
+}
+
+// NewChatService 创建对话领域服务
+//
+// 参数：
+//   - conversationRepo: 对话仓储
+//   - messageRepo: 消息仓储
+//   - llmService: LLM 服务（生成回复）
+//   - txManager: 事务管理器（消息和对话计数在同一事务中更新）
+//   - eventBus: 事件总线（可为 nil，不发布事件）
+func NewChatService(
+	conversationRepo repository.ConversationRepository,
+	messageRepo repository.MessageRepository,
+	llmService *llmservice.LLMService,
+	txManager persistence.TxManager,
+	eventBus sharedevents.EventBus,
+) *ChatService {
+	return &ChatService{
+		conversationRepo: conversationRepo,
+		messageRepo:      messageRepo,
+		llmService:       llmService,
+		txManager:        txManager,
+		eventBus:         eventBus,
+		agentMaxSteps:    DefaultAgentMaxSteps,
+
+		retrievalTokenBudget: DefaultRetrievalTokenBudget,
+		contextManager:       NewContextManager(nil, nil, 0),
+		now:                  time.Now,
+	}
+}
+
+// WithClock 替换时间源（用于测试）
+func (s *ChatService) WithClock(now func() time.Time) *ChatService {
+	s.now = now
+	return s
+}
+
+// WithContextManager 设置上下文窗口管理（默认按字符估算 Token，所有模型使用 DefaultContextWindow）
+func (s *ChatService) WithContextManager(m *ContextManager) *ChatService {
+	s.contextManager = m
+	return s
+}
+
+// WithModeration 设置内容审核（用户消息和模型回复）
+func (s *ChatService) WithModeration(p *moderation.Pipeline) *ChatService {
+	s.moderation = p
+	return s
+}
+
+// CreateConversationInput 创建对话输入
+type CreateConversationInput struct {
+	UserID   string // 用户 ID（从 JWT 获取）
+	Title    string // 可选，为空时使用默认标题
+	Model    string // 可选，为空时使用默认模型
+	Provider string // 可选，为空时使用默认提供商
+}
+
+// ConversationOutput 对话输出
+type ConversationOutput struct {
+	Conversation *model.Conversation
+}
+
+// GetConversationInput 获取/删除对话输入
+type GetConversationInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+}
+
+// RenameConversationInput 重命名对话输入
+type RenameConversationInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Title          string
+}
+
+// ListConversationsInput 列出对话输入
+type ListConversationsInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	Limit  int
+	Offset int
+}
+
+// ListConversationsOutput 列出对话输出
+type ListConversationsOutput struct {
+	Conversations []*model.Conversation
+	Total         int
+}
+
+// SendMessageInput 发送消息输入
+type SendMessageInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Role           model.Role // user（默认，生成回复）或 system（只保存，作为后续上下文）
+	Content        string
+	Strategy       llmmodel.Strategy // 模型路由策略（可选，对话指定了提供商和模型时不生效）
+}
+
+// SendMessageOutput 发送消息输出
+type SendMessageOutput struct {
+	Conversation *model.Conversation
+	Message      *model.Message // 用户发送的消息
+	Reply        *model.Message // 模型回复（system 消息时为 nil）
+}
+
+// ListMessagesInput 列出消息输入
+type ListMessagesInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Limit          int
+	Offset         int
+}
+
+// ListMessagesOutput 列出消息输出
+type ListMessagesOutput struct {
+	Messages []*model.Message
+	Total    int
+}
+
+// CreateConversation 创建对话（用例实现）
+//
+// 对应 usecases.yaml 中的 CreateConversation
+func (s *ChatService) CreateConversation(ctx context.Context, input CreateConversationInput) (*ConversationOutput, error) {
+	// Step 1: CreateConversationEntity - 创建对话实体（含验证）
+	conv, err := model.NewConversation(input.UserID, input.Title, input.Model, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: SaveConversation
+	if err := s.conversationRepo.Create(ctx, conv); err != nil {
+		return nil, fmt.Errorf("CREATION_FAILED: 保存对话失败: %w", err)
+	}
+
+	// Step 3: PublishConversationCreatedEvent
+	s.publish(ctx, sharedevents.NewConversationCreatedEvent(sharedevents.ConversationCreatedPayload{
+		ConversationID: conv.ID,
+		UserID:         conv.UserID,
+		Title:          conv.Title,
+	}))
+
+	log.Printf("Conversation created: %s", conv.ID)
+	return &ConversationOutput{Conversation: conv}, nil
+}
+
+// ListConversations 列出当前用户的对话（用例实现，按最近更新倒序）
+func (s *ChatService) ListConversations(ctx context.Context, input ListConversationsInput) (*ListConversationsOutput, error) {
+	if input.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+	limit, offset := normalizePage(input.Limit, input.Offset)
+
+	conversations, total, err := s.conversationRepo.ListByUser(ctx, input.UserID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询对话失败")
+	}
+	return &ListConversationsOutput{Conversations: conversations, Total: total}, nil
+}
+
+// RenameConversation 重命名对话（用例实现）
+func (s *ChatService) RenameConversation(ctx context.Context, input RenameConversationInput) (*ConversationOutput, error) {
+	// Step 1: GetConversation + CheckOwnership
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Rename
+	if err := conv.Rename(input.Title); err != nil {
+		return nil, err
+	}
+
+	// Step 3: SaveConversation
+	if err := s.conversationRepo.Update(ctx, conv); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 更新对话失败")
+	}
+
+	return &ConversationOutput{Conversation: conv}, nil
+}
+
+// DeleteConversation 删除对话及其所有消息（用例实现）
+func (s *ChatService) DeleteConversation(ctx context.Context, input GetConversationInput) error {
+	// Step 1: GetConversation + CheckOwnership
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: DeleteConversation（消息由外键级联删除）
+	if err := s.conversationRepo.Delete(ctx, conv.ID); err != nil {
+		return fmt.Errorf("DELETION_FAILED: 删除对话失败")
+	}
+
+	// Step 3: PublishConversationDeletedEvent
+	s.publish(ctx, sharedevents.NewConversationDeletedEvent(sharedevents.ConversationDeletedPayload{
+		ConversationID: conv.ID,
+		UserID:         conv.UserID,
+		MessageCount:   conv.MessageCount,
+	}))
+
+	log.Printf("Conversation deleted: %s", conv.ID)
+	return nil
+}
+
+// SendMessage 发送消息（用例实现）
+//
+// 对应 usecases.yaml 中的 SendMessage
+//
+// 步骤：
+//  1. GetConversation - 获取对话并验证所有权
+//  2. CreateMessageEntity - 内容审核后创建用户消息（含验证）
+//  3. BuildContext - 加载最近的历史消息，按上下文策略放入模型的上下文窗口
+//  4. Generate - 调用 LLMService 生成回复（system 消息跳过），回复经过内容审核
+//  5. SaveMessages - 在同一事务中保存消息并更新对话
+//  6. PublishEvents - 发布 MessageSent / MessageReceived
+func (s *ChatService) SendMessage(ctx context.Context, input SendMessageInput) (*SendMessageOutput, error) {
+	// Step 1: GetConversation
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CreateMessageEntity
+	role := input.Role
+	if role == "" {
+		role = model.RoleUser
+	}
+	if role != model.RoleUser && role != model.RoleSystem {
+		return nil, model.ErrInvalidMessageRole
+	}
+	content, err := s.moderateInput(ctx, conv, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := model.NewMessage(conv.ID, role, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// system 消息只保存，不生成回复
+	if role == model.RoleSystem {
+		if err := s.saveMessages(ctx, conv, "", msg); err != nil {
+			return nil, err
+		}
+		s.publishMessageSent(ctx, conv, msg, conv.Model)
+		return &SendMessageOutput{Conversation: conv, Message: msg}, nil
+	}
+
+	// Step 3: BuildContext
+	req, err := s.buildContext(ctx, conv, msg, 0)
+	if err != nil {
+		return nil, err
+	}
+	req.Strategy = input.Strategy
+
+	// Step 4: Generate
+	start := time.Now()
+	resp, err := s.llmService.Complete(ctx, req)
+	if err != nil {
+		return nil, generationError(err)
+	}
+	reply := model.NewAssistantMessage(conv.ID, resp.Message.Content, resp.Model, resp.Provider,
+		resp.Usage.InputTokens, resp.Usage.OutputTokens, time.Since(start).Milliseconds())
+	s.moderateReply(ctx, conv, reply)
+
+	// Step 5: SaveMessages
+	if err := s.saveMessages(ctx, conv, msg.Content, msg, reply); err != nil {
+		return nil, err
+	}
+
+	// Step 6: PublishEvents
+	s.publishExchange(ctx, conv, msg, reply)
+
+	return &SendMessageOutput{Conversation: conv, Message: msg, Reply: reply}, nil
+}
+
+// ListMessages 列出对话中的消息（用例实现，按时间正序）
+func (s *ChatService) ListMessages(ctx context.Context, input ListMessagesInput) (*ListMessagesOutput, error) {
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset := normalizePage(input.Limit, input.Offset)
+
+	messages, total, err := s.messageRepo.ListByConversation(ctx, conv.ID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询消息失败")
+	}
+	return &ListMessagesOutput{Messages: messages, Total: total}, nil
+}
+
+// getOwnedConversation 获取对话并验证所有权
+func (s *ChatService) getOwnedConversation(ctx context.Context, userID, conversationID string) (*model.Conversation, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrConversationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("QUERY_FAILED: 查询对话失败")
+	}
+	if !conv.IsOwnedBy(userID) {
+		return nil, fmt.Errorf("UNAUTHORIZED_ACCESS: 无权访问此对话")
+	}
+	return conv, nil
+}
+
+// saveMessages 在同一事务中保存消息并更新对话（消息数量、自动标题）
+func (s *ChatService) saveMessages(ctx context.Context, conv *model.Conversation, firstUserContent string, messages ...*model.Message) error {
+	conv.RecordMessages(firstUserContent, len(messages))
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, m := range messages {
+			if err := s.messageRepo.Create(ctx, m); err != nil {
+				return err
+			}
+		}
+		return s.conversationRepo.Update(ctx, conv)
+	})
+	if err != nil {
+		return fmt.Errorf("CREATION_FAILED: 保存消息失败: %w", err)
+	}
+	return nil
+}
+
+// buildContext 加载最近的历史消息，与新消息一起组装为 LLM 请求
+//
+// extraTokens 为调用方之后加入的内容（系统提示、资料）占用的 Token 数。
+// 有等待确认的工具调用时返回 TOOL_CONFIRMATION_PENDING（未回答的调用会被提供商拒绝）。
+func (s *ChatService) buildContext(ctx context.Context, conv *model.Conversation, msg *model.Message, extraTokens int) (*llmmodel.ChatRequest, error) {
+	history, err := s.loadHistory(ctx, conv)
+	if err != nil {
+		return nil, err
+	}
+	req, _, err := s.assemble(ctx, conv, append(history, msg), extraTokens)
+	return req, err
+}
+
+// loadHistory 加载最近的历史消息，并检查是否有等待确认的工具调用
+func (s *ChatService) loadHistory(ctx context.Context, conv *model.Conversation) ([]*model.Message, error) {
+	history, err := s.messageRepo.ListRecent(ctx, conv.ID, contextMessageLimit)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询历史消息失败")
+	}
+	if len(model.PendingToolCalls(history)) > 0 {
+		return nil, model.ErrToolConfirmationPending
+	}
+	return history, nil
+}
+
+// buildChatRequest 将消息（按时间正序）转换为 LLM 请求，summary 不为空时作为系统提示放在最前面
+func (s *ChatService) buildChatRequest(conv *model.Conversation, summary string, history []*model.Message) *llmmodel.ChatRequest {
+	// 历史消息被截断时开头可能是没有对应调用的工具结果，提供商会拒绝这样的请求
+	for len(history) > 0 && history[0].Role == model.RoleTool {
+		history = history[1:]
+	}
+
+	messages := make([]llmmodel.Message, 0, len(history)+1)
+	if summary != "" {
+		messages = append(messages, llmmodel.Message{Role: llmmodel.RoleSystem, Content: summaryPrefix + summary})
+	}
+	for _, m := range history {
+		messages = append(messages, toLLMMessage(m))
+	}
+
+	return &llmmodel.ChatRequest{
+		Provider: conv.Provider,
+		Model:    conv.Model,
+		Messages: messages,
+		User:     conv.UserID,
+	}
+}
+
+// toLLMMessage 将对话消息转换为 LLM 消息
+func toLLMMessage(m *model.Message) llmmodel.Message {
+	msg := llmmodel.Message{Role: llmmodel.Role(m.Role), Content: m.Content, ToolCallID: m.ToolCallID}
+	for _, call := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, llmmodel.ToolCall(call))
+	}
+	return msg
+}
+
+// publishMessageSent 发布 MessageSent 事件（modelName 为处理该消息的模型）
+func (s *ChatService) publishMessageSent(ctx context.Context, conv *model.Conversation, msg *model.Message, modelName string) {
+	s.publish(ctx, sharedevents.NewMessageSentEvent(sharedevents.MessageSentPayload{
+		MessageID:      msg.ID,
+		ConversationID: conv.ID,
+		UserID:         conv.UserID,
+		Content:        msg.Content,
+		Role:           string(msg.Role),
+		Model:          modelName,
+	}))
+}
+
+// publishExchange 发布一问一答的 MessageSent / MessageReceived 事件
+func (s *ChatService) publishExchange(ctx context.Context, conv *model.Conversation, msg, reply *model.Message) {
+	s.publishMessageSent(ctx, conv, msg, reply.Model)
+	s.publishMessageReceived(ctx, conv, reply)
+}
+
+// publishMessageReceived 发布 MessageReceived 事件
+func (s *ChatService) publishMessageReceived(ctx context.Context, conv *model.Conversation, reply *model.Message) {
+	s.publish(ctx, sharedevents.NewMessageReceivedEvent(sharedevents.MessageReceivedPayload{
+		MessageID:      reply.ID,
+		ConversationID: conv.ID,
+		Content:        reply.Content,
+		Role:           string(reply.Role),
+		Model:          reply.Model,
+		Tokens:         reply.OutputTokens,
+		Latency:        reply.LatencyMs,
+	}))
+}
+
+// publish 发布事件（失败只记录日志）
+func (s *ChatService) publish(ctx context.Context, event sharedevents.Event) {
+	if s.eventBus == nil {
+		return
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		logger.Error("publish chat event failed",
+			zap.String("type", event.Type()),
+			zap.Error(err),
+		)
+	}
+}
+
+// generationError 包装模型生成错误（超出额度时原样返回 QUOTA_EXCEEDED）
+func generationError(err error) error {
+	if errors.Is(err, llmmodel.ErrQuotaExceeded) {
+		return err
+	}
+	return fmt.Errorf("GENERATION_FAILED: 模型生成失败: %w", err)
+}
+
+// normalizePage 规范化分页参数
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
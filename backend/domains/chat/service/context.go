@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// summaryMaxTokens 生成摘要时回复的 Token 上限（不超过上下文窗口的 1/4）
+const summaryMaxTokens = 512
+
+// summaryTranscriptRunes 生成摘要时每条消息最多带入的字符数
+const summaryTranscriptRunes = 2000
+
+// summarizeInstructions 生成摘要的系统提示
+const summarizeInstructions = `你负责压缩一段对话的历史，使之后的回复仍然能够衔接上下文。
+- 把已有摘要和新的对话记录合并为一份摘要，不超过 300 字
+- 保留用户的目标、偏好、做出的决定、提到的具体信息（名称、日期、数字）和尚未解决的问题
+- 省略寒暄和重复内容，不要添加对话中没有的信息
+- 只输出摘要本身`
+
+// UpdateContextSettingsInput 修改上下文设置输入
+type UpdateContextSettingsInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Strategy       model.ContextStrategy // 为空时使用 truncate
+	WindowSize     int                   // 滑动窗口的消息数（0 使用默认值）
+}
+
+// PreviewContextInput 查看提示词输入
+type PreviewContextInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Content        string // 假设发送的消息（可选）
+}
+
+// PromptMessage 提示词中的一条消息
+type PromptMessage struct {
+	Message llmmodel.Message
+	Tokens  int
+}
+
+// PreviewContextOutput 查看提示词输出
+type PreviewContextOutput struct {
+	Conversation *model.Conversation
+	Plan         *ContextPlan
+	Prompt       []PromptMessage // 发送给模型的消息（与 SendMessage 组装的请求一致）
+}
+
+// UpdateContextSettings 修改对话的上下文策略（用例实现）
+//
+// 对应 usecases.yaml 中的 UpdateContextSettings
+func (s *ChatService) UpdateContextSettings(ctx context.Context, input UpdateContextSettingsInput) (*ConversationOutput, error) {
+	// Step 1: GetConversation + CheckOwnership
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: UpdateContext
+	if err := conv.UpdateContext(model.ContextSettings{Strategy: input.Strategy, WindowSize: input.WindowSize}); err != nil {
+		return nil, err
+	}
+
+	// Step 3: SaveConversation
+	if err := s.conversationRepo.Update(ctx, conv); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 更新对话失败")
+	}
+
+	return &ConversationOutput{Conversation: conv}, nil
+}
+
+// PreviewContext 返回下一次发送消息时组装的提示词（用例实现，用于调试）
+//
+// 对应 usecases.yaml 中的 PreviewContext
+//
+// 只读：不调用模型，需要更新摘要时（Plan.Summarize）使用已保存的摘要，显示没有放入的消息数。
+// 有等待确认的工具调用时也可以查看。
+func (s *ChatService) PreviewContext(ctx context.Context, input PreviewContextInput) (*PreviewContextOutput, error) {
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.messageRepo.ListRecent(ctx, conv.ID, contextMessageLimit)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询历史消息失败")
+	}
+	if input.Content != "" {
+		msg, err := model.NewMessage(conv.ID, model.RoleUser, input.Content)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, msg)
+	}
+
+	plan, err := s.plan(conv, history, 0)
+	if err != nil {
+		return nil, err
+	}
+	req := s.buildChatRequest(conv, plan.Summary, plan.Messages)
+
+	prompt := make([]PromptMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		prompt = append(prompt, PromptMessage{Message: m, Tokens: tokenizer.CountMessage(s.contextManager.tokenizer, m)})
+	}
+	return &PreviewContextOutput{Conversation: conv, Plan: plan, Prompt: prompt}, nil
+}
+
+// assemble 按对话的上下文策略选出历史消息并组装 LLM 请求
+//
+// summarize 策略需要更新摘要时调用模型生成摘要，记录在 conv 上（随之后的 saveMessages 保存）；
+// 生成失败时只记录日志，较早的消息直接丢弃（下次发送时重试）。
+func (s *ChatService) assemble(ctx context.Context, conv *model.Conversation, history []*model.Message, extraTokens int) (*llmmodel.ChatRequest, *ContextPlan, error) {
+	plan, err := s.plan(conv, history, extraTokens)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if plan.Summarize {
+		summary, err := s.summarize(ctx, conv, plan)
+		if err != nil {
+			logger.Warn("conversation summarization failed",
+				zap.String("conversation_id", conv.ID),
+				zap.Error(err),
+			)
+		} else {
+			s.contextManager.ApplySummary(plan, summary)
+			conv.RecordSummary(summary, plan.Dropped[len(plan.Dropped)-1].ID)
+		}
+	}
+
+	if len(plan.Dropped) > 0 {
+		logger.Info("conversation context trimmed",
+			zap.String("conversation_id", conv.ID),
+			zap.String("strategy", string(plan.Strategy)),
+			zap.Int("window", plan.Window),
+			zap.Int("tokens", plan.Tokens),
+			zap.Int("kept", len(plan.Messages)),
+			zap.Int("dropped", len(plan.Dropped)),
+		)
+	}
+	return s.buildChatRequest(conv, plan.Summary, plan.Messages), plan, nil
+}
+
+// plan 按对话的模型（未指定时为默认模型）计算上下文
+func (s *ChatService) plan(conv *model.Conversation, history []*model.Message, extraTokens int) (*ContextPlan, error) {
+	provider, modelName := conv.Provider, conv.Model
+	if provider == "" {
+		provider = s.llmService.DefaultProvider()
+	}
+	if modelName == "" {
+		modelName = s.llmService.DefaultModel()
+	}
+	return s.contextManager.Plan(conv, provider, modelName, history, extraTokens)
+}
+
+// summarize 把较早的消息（plan.Dropped）合并到对话已有的摘要中
+func (s *ChatService) summarize(ctx context.Context, conv *model.Conversation, plan *ContextPlan) (string, error) {
+	var transcript strings.Builder
+	if conv.Summary != "" {
+		transcript.WriteString("已有摘要：\n" + conv.Summary + "\n\n")
+	}
+	transcript.WriteString("新的对话记录：\n")
+
+	// 摘要请求也不能超出上下文窗口：放不下时丢弃最早的记录
+	lines := make([]string, 0, len(plan.Dropped))
+	for _, msg := range plan.Dropped {
+		lines = append(lines, transcriptLine(msg))
+	}
+	maxTokens := min(summaryMaxTokens, plan.Window/4)
+	available := plan.Window - maxTokens - s.contextManager.CountText(summarizeInstructions) -
+		s.contextManager.CountText(transcript.String()) - tokenizer.ReplyPriming
+	start, used := len(lines), 0
+	for start > 0 {
+		cost := s.contextManager.tokenizer.Count(lines[start-1]) + 1
+		if used+cost > available {
+			break
+		}
+		start--
+		used += cost
+	}
+	if start > 0 {
+		logger.Warn("conversation summary transcript truncated",
+			zap.String("conversation_id", conv.ID),
+			zap.Int("skipped", start),
+		)
+	}
+	for _, line := range lines[start:] {
+		transcript.WriteString(line + "\n")
+	}
+
+	temperature := 0.0
+	resp, err := s.llmService.Complete(ctx, &llmmodel.ChatRequest{
+		Provider: conv.Provider,
+		Model:    conv.Model,
+		Messages: []llmmodel.Message{
+			{Role: llmmodel.RoleSystem, Content: summarizeInstructions},
+			{Role: llmmodel.RoleUser, Content: transcript.String()},
+		},
+		Temperature: &temperature,
+		MaxTokens:   maxTokens,
+		User:        conv.UserID,
+	})
+	if err != nil {
+		return "", err
+	}
+	summary := strings.TrimSpace(resp.Message.Content)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
+
+// transcriptLine 一条消息在摘要输入中的文本（过长的内容截断）
+func transcriptLine(msg *model.Message) string {
+	content := msg.Content
+	if utf8.RuneCountInString(content) > summaryTranscriptRunes {
+		content = string([]rune(content)[:summaryTranscriptRunes]) + "…"
+	}
+	switch {
+	case len(msg.ToolCalls) > 0:
+		calls := make([]string, 0, len(msg.ToolCalls))
+		for _, call := range msg.ToolCalls {
+			calls = append(calls, call.Name+call.Arguments)
+		}
+		return "assistant（调用工具）: " + strings.Join(calls, ", ")
+	case msg.Role == model.RoleTool:
+		return "tool " + msg.ToolName + ": " + content
+	default:
+		return string(msg.Role) + ": " + content
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+)
+
+// DefaultContextWindow 模型目录中没有该模型（或没有设置上下文窗口）时使用的窗口大小
+const DefaultContextWindow = 8192
+
+// DefaultReplyReserveTokens 上下文窗口中为回复预留的 Token 数
+const DefaultReplyReserveTokens = 1024
+
+// summarize 策略的阈值（相对于提示词预算）：
+// 摘要和未摘要的消息超过 summarizeTriggerRatio 时生成新的摘要，之后最近的消息最多占 summarizeKeepRatio。
+const (
+	summarizeTriggerRatio = 0.8
+	summarizeKeepRatio    = 0.5
+)
+
+// summaryPrefix 摘要作为系统提示发送时的前缀
+const summaryPrefix = "以下是这段对话较早内容的摘要：\n"
+
+// ErrContextWindowExceeded 新消息本身超出模型的上下文窗口
+var ErrContextWindowExceeded = fmt.Errorf("CONTEXT_WINDOW_EXCEEDED: 消息超出模型的上下文窗口")
+
+// ModelCatalog 查询模型的上下文窗口（由 catalog 领域的 CatalogService 实现）
+type ModelCatalog interface {
+	ModelSpec(provider, name string) (llmmodel.ModelSpec, bool)
+}
+
+// ContextManager 上下文窗口管理
+//
+// 按 Token 数（Tokenizer）和模型的上下文窗口（模型目录）决定哪些历史消息放入提示词：
+//   - truncate：从最早的消息开始丢弃
+//   - sliding_window：只保留最近的 N 条消息，仍超出时继续丢弃
+//   - summarize：接近窗口上限时较早的消息需要合并为摘要（由 ChatService 生成并保存）
+//
+// 只计算，不调用模型也不修改对话，实现是并发安全的。
+type ContextManager struct {
+	tokenizer     tokenizer.Tokenizer
+	catalog       ModelCatalog
+	reserveTokens int
+}
+
+// NewContextManager 创建上下文窗口管理
+//
+// 参数：
+//   - tok: Token 计数（为 nil 时使用 tokenizer.Estimator）
+//   - catalog: 模型目录（为 nil 时所有模型使用 DefaultContextWindow）
+//   - reserveTokens: 为回复预留的 Token 数（<= 0 使用 DefaultReplyReserveTokens）
+func NewContextManager(tok tokenizer.Tokenizer, catalog ModelCatalog, reserveTokens int) *ContextManager {
+	if tok == nil {
+		tok = tokenizer.Estimator{}
+	}
+	if reserveTokens <= 0 {
+		reserveTokens = DefaultReplyReserveTokens
+	}
+	return &ContextManager{tokenizer: tok, catalog: catalog, reserveTokens: reserveTokens}
+}
+
+// ContextPlan 组装提示词的结果
+type ContextPlan struct {
+	Strategy    model.ContextStrategy
+	Provider    string // 计算窗口使用的提供商和模型（对话未指定时为默认值）
+	Model       string
+	Window      int              // 模型的上下文窗口
+	Budget      int              // 可用于提示词的 Token 数（窗口减去回复预留和额外内容）
+	ExtraTokens int              // 消息之外的内容（系统提示、资料、工具定义）占用的 Token 数
+	Summary     string           // 放入提示词的摘要（summarize 策略）
+	Messages    []*model.Message // 放入提示词的消息（按时间正序）
+	Dropped     []*model.Message // 没有放入提示词的较早消息（按时间正序，不包括已在摘要中的消息）
+	Tokens      int              // 提示词的 Token 数（包括额外内容）
+	Summarize   bool             // 需要把 Dropped 合并到摘要中
+}
+
+// Window 返回模型的上下文窗口（模型目录中没有时为 DefaultContextWindow）
+func (m *ContextManager) Window(provider, modelName string) int {
+	if m.catalog != nil {
+		if spec, ok := m.catalog.ModelSpec(provider, modelName); ok && spec.ContextWindow > 0 {
+			return spec.ContextWindow
+		}
+	}
+	return DefaultContextWindow
+}
+
+// Count 计算一条消息作为提示词的 Token 数
+func (m *ContextManager) Count(msg *model.Message) int {
+	return tokenizer.CountMessage(m.tokenizer, toLLMMessage(msg))
+}
+
+// CountTools 计算工具定义的 Token 数
+func (m *ContextManager) CountTools(tools []llmmodel.ToolDefinition) int {
+	return tokenizer.CountTools(m.tokenizer, tools)
+}
+
+// CountText 计算文本（如系统提示）作为一条消息的 Token 数
+func (m *ContextManager) CountText(text string) int {
+	return tokenizer.CountMessage(m.tokenizer, llmmodel.Message{Role: llmmodel.RoleSystem, Content: text})
+}
+
+// Plan 按对话的上下文策略选出放入提示词的历史消息
+//
+// history 按时间正序，最后一条是本次的新消息（总是保留）；extraTokens 为消息之外的内容占用的 Token 数。
+// 工具结果总是和对应的调用一起保留或丢弃。新消息本身放不下时返回 CONTEXT_WINDOW_EXCEEDED。
+func (m *ContextManager) Plan(conv *model.Conversation, provider, modelName string, history []*model.Message, extraTokens int) (*ContextPlan, error) {
+	window := m.Window(provider, modelName)
+	plan := &ContextPlan{
+		Strategy:    conv.ContextStrategy(),
+		Provider:    provider,
+		Model:       modelName,
+		Window:      window,
+		Budget:      window - m.reserveTokens - extraTokens - tokenizer.ReplyPriming,
+		ExtraTokens: extraTokens,
+	}
+
+	var dropped []*model.Message
+	switch plan.Strategy {
+	case model.ContextSummarize:
+		// 已在摘要中的消息不再发送
+		for i, msg := range history {
+			if msg.ID == conv.SummaryUntil {
+				history = history[i+1:]
+				break
+			}
+		}
+		plan.Summary = conv.Summary
+	case model.ContextSlidingWindow:
+		if size := conv.ContextWindowSize(); len(history) > size {
+			dropped = history[:len(history)-size]
+			history = history[len(history)-size:]
+		}
+	}
+
+	counts := make([]int, len(history))
+	total := 0
+	for i, msg := range history {
+		counts[i] = m.Count(msg)
+		total += counts[i]
+	}
+	summaryTokens := m.summaryTokens(plan.Summary)
+
+	keep := plan.Budget - summaryTokens
+	maxMessages := len(history)
+	if plan.Strategy == model.ContextSummarize && len(history) > 1 &&
+		(float64(summaryTokens+total) > float64(plan.Budget)*summarizeTriggerRatio || len(history) >= model.MaxContextWindowSize) {
+		// 新摘要的长度未知，最近的消息只占一半预算；未摘要的消息数也减半，避免超出加载的历史消息数
+		plan.Summarize = true
+		keep = int(float64(plan.Budget) * summarizeKeepRatio)
+		maxMessages = model.MaxContextWindowSize / 2
+	}
+
+	// 从最新的消息开始放入
+	start, used := len(history), 0
+	for start > 0 && len(history)-start < maxMessages {
+		if start < len(history) && used+counts[start-1] > keep {
+			break
+		}
+		start--
+		used += counts[start]
+	}
+	// 保留的第一条是工具结果时带上对应的调用
+	for start > 0 && start < len(history) && history[start].Role == model.RoleTool {
+		start--
+		used += counts[start]
+	}
+
+	plan.Messages = history[start:]
+	plan.Dropped = append(dropped, history[:start]...)
+	if plan.Summarize && len(plan.Dropped) == 0 {
+		plan.Summarize = false
+	}
+	plan.Tokens = tokenizer.ReplyPriming + extraTokens + summaryTokens + used
+
+	if len(plan.Messages) == 1 && used > plan.Budget {
+		return nil, ErrContextWindowExceeded
+	}
+	return plan, nil
+}
+
+// ApplySummary 用新的摘要替换 plan 中的摘要（Dropped 已合并到摘要中）
+func (m *ContextManager) ApplySummary(plan *ContextPlan, summary string) {
+	plan.Tokens += m.summaryTokens(summary) - m.summaryTokens(plan.Summary)
+	plan.Summary = summary
+	plan.Summarize = false
+}
+
+// summaryTokens 摘要作为系统提示的 Token 数（没有摘要时为 0）
+func (m *ContextManager) summaryTokens(summary string) int {
+	if summary == "" {
+		return 0
+	}
+	return m.CountText(summaryPrefix + summary)
+}
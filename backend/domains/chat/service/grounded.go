@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultRetrievalTokenBudget 基于资料回答时资料部分默认最多占用的 Token 数
+const DefaultRetrievalTokenBudget = 2000
+
+// maxRetrievedDocuments 每次最多检索的资料数（放入提示词前再按 Token 预算筛选）
+const maxRetrievedDocuments = 30
+
+// groundedInstructions 基于资料回答的系统提示（%s 为当前时间，%s 为资料）
+const groundedInstructions = `你是用户的任务助手，只根据下面的资料回答用户的问题。
+当前时间：%s。
+- 每条资料以 [类型:ID] 开头；使用某条资料时，在句子末尾原样写出它的标记，如 [task:123]
+- 资料中没有答案时直接说明，不要编造任务或日期
+- 不要写出资料中不存在的标记
+
+资料：
+%s`
+
+// noDocuments 没有检索到资料时资料部分的内容
+const noDocuments = "（没有相关资料）"
+
+// ErrGroundingDisabled 未配置资料检索
+var ErrGroundingDisabled = fmt.Errorf("GROUNDING_DISABLED: 未开启基于资料的回答")
+
+// WithRetriever 开启基于资料的回答（SendGroundedMessage）
+//
+// 参数：
+//   - retriever: 资料检索（按用户检索，如任务）
+//   - tokenBudget: 资料部分最多占用的 Token 数（<= 0 使用 DefaultRetrievalTokenBudget）
+func (s *ChatService) WithRetriever(retriever retrieval.Retriever, tokenBudget int) *ChatService {
+	if tokenBudget <= 0 {
+		tokenBudget = DefaultRetrievalTokenBudget
+	}
+	s.retriever = retriever
+	s.retrievalTokenBudget = tokenBudget
+	return s
+}
+
+// SendGroundedMessageInput 发送基于资料回答的消息输入
+type SendGroundedMessageInput struct {
+	UserID         string // 用户 ID（从 JWT 获取，只检索该用户的资料）
+	ConversationID string
+	Content        string
+	Strategy       llmmodel.Strategy // 模型路由策略（可选，对话指定了提供商和模型时不生效）
+}
+
+// SendGroundedMessageOutput 发送基于资料回答的消息输出
+type SendGroundedMessageOutput struct {
+	Conversation *model.Conversation
+	Message      *model.Message // 用户发送的消息
+	Reply        *model.Message // 模型回复（Citations 为引用的资料）
+}
+
+// SendGroundedMessage 发送消息，模型只根据检索到的资料回答并引用资料（用例实现）
+//
+// 对应 usecases.yaml 中的 SendGroundedMessage
+//
+// 步骤：
+//  1. GetConversation - 获取对话并验证所有权
+//  2. CreateMessageEntity - 内容审核后创建用户消息（含验证）
+//  3. BuildContext - 加载最近的历史消息，按上下文策略放入模型的上下文窗口（预留资料的 Token 预算）
+//  4. Retrieve - 按消息内容检索用户的资料
+//  5. PackContext - 在 Token 预算内按相关度放入资料，作为系统提示（不保存）
+//  6. Generate - 调用 LLMService 生成回复，回复经过内容审核
+//  7. ExtractCitations - 从回复中提取引用的资料（忽略不在资料中的引用）
+//  8. SaveMessages - 在同一事务中保存消息并更新对话
+//  9. PublishEvents - 发布 MessageSent / MessageReceived
+func (s *ChatService) SendGroundedMessage(ctx context.Context, input SendGroundedMessageInput) (*SendGroundedMessageOutput, error) {
+	if s.retriever == nil {
+		return nil, ErrGroundingDisabled
+	}
+
+	// Step 1: GetConversation
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CreateMessageEntity
+	content, err := s.moderateInput(ctx, conv, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := model.NewMessage(conv.ID, model.RoleUser, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: BuildContext（为系统提示和资料预留 Token 预算）
+	reserved := s.contextManager.CountText(groundedInstructions) + s.retrievalTokenBudget
+	req, err := s.buildContext(ctx, conv, msg, reserved)
+	if err != nil {
+		return nil, err
+	}
+	req.Strategy = input.Strategy
+
+	// Step 4: Retrieve
+	docs, err := s.retriever.Retrieve(ctx, input.UserID, msg.Content, maxRetrievedDocuments)
+	if err != nil {
+		return nil, fmt.Errorf("RETRIEVAL_FAILED: 检索资料失败: %w", err)
+	}
+
+	// Step 5: PackContext
+	packed := retrieval.Pack(docs, s.retrievalTokenBudget)
+	logger.Info("grounded message context packed",
+		zap.String("conversation_id", conv.ID),
+		zap.Int("retrieved", len(docs)),
+		zap.Int("packed", len(packed)),
+	)
+	sources := noDocuments
+	if len(packed) > 0 {
+		sources = retrieval.Join(packed)
+	}
+	req.Messages = append([]llmmodel.Message{{
+		Role:    llmmodel.RoleSystem,
+		Content: fmt.Sprintf(groundedInstructions, s.now().Format(time.RFC3339), sources),
+	}}, req.Messages...)
+
+	// Step 6: Generate
+	start := time.Now()
+	resp, err := s.llmService.Complete(ctx, req)
+	if err != nil {
+		return nil, generationError(err)
+	}
+	reply := model.NewAssistantMessage(conv.ID, resp.Message.Content, resp.Model, resp.Provider,
+		resp.Usage.InputTokens, resp.Usage.OutputTokens, time.Since(start).Milliseconds())
+	s.moderateReply(ctx, conv, reply)
+
+	// Step 7: ExtractCitations（被拦截的回复没有引用）
+	for _, doc := range retrieval.Cited(reply.Content, packed) {
+		reply.Citations = append(reply.Citations, model.Citation{Source: doc.Source, ID: doc.ID, Title: doc.Title})
+	}
+
+	// Step 8: SaveMessages
+	if err := s.saveMessages(ctx, conv, msg.Content, msg, reply); err != nil {
+		return nil, err
+	}
+
+	// Step 9: PublishEvents
+	s.publishExchange(ctx, conv, msg, reply)
+
+	return &SendGroundedMessageOutput{Conversation: conv, Message: msg, Reply: reply}, nil
+}
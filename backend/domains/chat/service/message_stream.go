@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+)
+
+// StreamMessageInput 流式发送消息输入
+type StreamMessageInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	ConversationID string
+	Content        string
+	Strategy       llmmodel.Strategy // 模型路由策略（可选，对话指定了提供商和模型时不生效）
+}
+
+// MessageStream 一次流式回复
+//
+// 由 StreamMessage 创建，调用方循环 Recv 读取增量内容，最后必须调用 Finish 保存消息。
+// 取消 StreamMessage 的 ctx 会中止上游生成。
+//
+// Recv 返回的是经过内容审核的增量（见 moderation.Stream）：个人信息替换后返回，
+// 回复命中 block 的检查时中止上游生成并返回 CONTENT_BLOCKED，命中的内容不会返回。
+type MessageStream struct {
+	service *ChatService
+	conv    *model.Conversation
+	message *model.Message
+	stream  llmprovider.ChatStream
+
+	modelName string
+	provider  string
+	start     time.Time
+
+	content   strings.Builder    // 上游返回的原始内容（Finish 时审核后保存）
+	filter    *moderation.Stream // 增量内容的审核
+	chunks    int                // 非空增量片段数（上游未返回用量时用于估算输出 Token）
+	usage     *llmmodel.Usage
+	completed bool  // 已读到 io.EOF
+	err       error // 中断原因（上游错误或 ctx 取消）
+
+	finishOnce sync.Once
+	output     *SendMessageOutput
+	finishErr  error
+}
+
+// StreamMessage 流式发送消息（用例实现）
+//
+// 对应 usecases.yaml 中的 StreamMessage
+//
+// 步骤：
+//  1. GetConversation - 获取对话并验证所有权
+//  2. CreateMessageEntity - 内容审核后创建用户消息（含验证，只支持 user 消息）
+//  3. BuildContext - 加载最近的历史消息，按上下文策略放入模型的上下文窗口
+//  4. OpenStream - 调用 LLMService 开始流式生成
+//
+// 返回错误时没有开始生成，也没有保存任何内容；
+// 之后的 SaveMessages / PublishEvents 在 MessageStream.Finish 中完成。
+func (s *ChatService) StreamMessage(ctx context.Context, input StreamMessageInput) (*MessageStream, error) {
+	// Step 1: GetConversation
+	conv, err := s.getOwnedConversation(ctx, input.UserID, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CreateMessageEntity
+	content, err := s.moderateInput(ctx, conv, input.Content)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := model.NewMessage(conv.ID, model.RoleUser, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: BuildContext
+	req, err := s.buildContext(ctx, conv, msg, 0)
+	if err != nil {
+		return nil, err
+	}
+	req.Strategy = input.Strategy
+
+	// Step 4: OpenStream（LLMService 会选择模型或填充默认的提供商和模型）
+	start := time.Now()
+	stream, err := s.llmService.Stream(ctx, req)
+	if err != nil {
+		return nil, generationError(err)
+	}
+
+	return &MessageStream{
+		service:   s,
+		conv:      conv,
+		message:   msg,
+		stream:    stream,
+		modelName: req.Model,
+		provider:  req.Provider,
+		start:     start,
+		filter:    s.moderation.NewStream(),
+	}, nil
+}
+
+// Message 返回本次发送的用户消息（尚未保存）
+func (m *MessageStream) Message() *model.Message {
+	return m.message
+}
+
+// Recv 返回下一段增量内容（经过内容审核）
+//
+// 生成完成时返回 io.EOF；上游出错或 ctx 被取消时返回对应错误，流视为被中断。
+// 回复未通过内容审核时中止上游生成并返回 CONTENT_BLOCKED。
+func (m *MessageStream) Recv() (string, error) {
+	if m.completed {
+		return "", io.EOF
+	}
+	if m.err != nil {
+		return "", m.err
+	}
+
+	for {
+		chunk, err := m.stream.Recv()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				m.err = err
+				return "", err
+			}
+			m.completed = true
+			// 放行审核缓冲区中剩余的内容
+			rest, err := m.filter.Flush()
+			if err != nil {
+				return "", m.block(err)
+			}
+			if rest != "" {
+				return rest, nil
+			}
+			return "", io.EOF
+		}
+		if chunk.Usage != nil {
+			m.usage = chunk.Usage
+		}
+		if chunk.Delta == "" {
+			continue
+		}
+		m.content.WriteString(chunk.Delta)
+		m.chunks++
+
+		delta, err := m.filter.Write(chunk.Delta)
+		if err != nil {
+			return "", m.block(err)
+		}
+		if delta != "" {
+			return delta, nil
+		}
+		// 内容暂存在审核缓冲区中，继续读取
+	}
+}
+
+// Blocked 回复是否未通过内容审核（Recv 返回过 CONTENT_BLOCKED）
+func (m *MessageStream) Blocked() bool {
+	return errors.Is(m.err, moderation.ErrContentBlocked)
+}
+
+// block 回复未通过内容审核：中止上游生成，之后的 Recv 都返回 err
+func (m *MessageStream) block(err error) error {
+	m.stream.Close()
+	m.completed = false
+	m.err = err
+	return err
+}
+
+// Finish 结束流并保存消息（可重复调用，只执行一次）
+//
+// 步骤：
+//  5. SaveMessages - 回复经过内容审核后，在同一事务中保存用户消息和回复（未完成时标记 Truncated）
+//  6. PublishEvents - 发布 MessageSent / MessageReceived
+//
+// 生成未完成且没有任何内容时不保存消息，返回 GENERATION_FAILED。
+// 保存的回复（返回值）是完整内容审核后的结果；回复未通过审核时保存为 BlockedReplyContent。
+// 保存使用不可取消的 ctx，客户端断开后被截断的回复仍会保存。
+func (m *MessageStream) Finish(ctx context.Context) (*SendMessageOutput, error) {
+	m.finishOnce.Do(func() {
+		m.output, m.finishErr = m.finish(context.WithoutCancel(ctx))
+	})
+	return m.output, m.finishErr
+}
+
+func (m *MessageStream) finish(ctx context.Context) (*SendMessageOutput, error) {
+	// 未读到 io.EOF 时 Close 会中止上游生成
+	m.stream.Close()
+
+	truncated := !m.completed
+	if truncated && m.content.Len() == 0 {
+		cause := m.err
+		if cause == nil {
+			cause = context.Canceled
+		}
+		return nil, fmt.Errorf("GENERATION_FAILED: 模型生成失败: %w", cause)
+	}
+
+	// 上游未返回用量（如被中断）时，输出 Token 按增量片段数估算
+	var inputTokens, outputTokens int
+	if m.usage != nil {
+		inputTokens, outputTokens = m.usage.InputTokens, m.usage.OutputTokens
+	} else {
+		outputTokens = m.chunks
+	}
+
+	reply := model.NewAssistantMessage(m.conv.ID, m.content.String(), m.modelName, m.provider,
+		inputTokens, outputTokens, time.Since(m.start).Milliseconds())
+	reply.Truncated = truncated
+	m.service.moderateReply(ctx, m.conv, reply)
+
+	// Step 5: SaveMessages
+	if err := m.service.saveMessages(ctx, m.conv, m.message.Content, m.message, reply); err != nil {
+		return nil, err
+	}
+
+	// Step 6: PublishEvents
+	m.service.publishExchange(ctx, m.conv, m.message, reply)
+
+	return &SendMessageOutput{Conversation: m.conv, Message: m.message, Reply: reply}, nil
+}
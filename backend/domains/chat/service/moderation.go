@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+)
+
+// 审核内容的来源（记录在 AlertTriggered 事件中）
+const (
+	moderationSourceInput  = "chat.input"
+	moderationSourceOutput = "chat.output"
+)
+
+// BlockedReplyContent 模型回复未通过内容安全检查时保存的内容
+const BlockedReplyContent = "抱歉，这条回复未通过内容安全检查，已被隐藏。"
+
+// moderateInput 检查用户发送的内容
+//
+// block 时返回 CONTENT_BLOCKED（不保存也不调用模型），redact 时返回替换后的内容（保存和发送给模型的都是替换后的内容）。
+func (s *ChatService) moderateInput(ctx context.Context, conv *model.Conversation, content string) (string, error) {
+	decision := s.moderation.Moderate(ctx, moderation.Input{Source: moderationSourceInput, UserID: conv.UserID, Text: content})
+	if decision.Blocked() {
+		return "", moderation.ErrContentBlocked
+	}
+	return decision.Text, nil
+}
+
+// moderateReply 在保存前检查模型回复：block 时内容替换为 BlockedReplyContent，redact 时替换命中的内容
+func (s *ChatService) moderateReply(ctx context.Context, conv *model.Conversation, reply *model.Message) {
+	decision := s.moderation.Moderate(ctx, moderation.Input{Source: moderationSourceOutput, UserID: conv.UserID, Text: reply.Content})
+	if decision.Blocked() {
+		reply.Content = BlockedReplyContent
+		return
+	}
+	reply.Content = decision.Text
+}
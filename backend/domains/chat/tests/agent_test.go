@@ -0,0 +1,267 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSaveMessages Mock 在同一事务中保存消息并更新对话（rolePatterns 依次匹配每条消息）
+func mockSaveMessages(m sqlmock.Sqlmock, messageCount int, rolePatterns ...string) {
+	m.ExpectBegin()
+	for _, pattern := range rolePatterns {
+		m.ExpectExec(`INSERT INTO "messages" .+` + pattern).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	m.ExpectExec(`UPDATE "conversations" SET .+"message_count"=` + strconv.Itoa(messageCount) + `,`).WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+}
+
+// mockClaimToolCalls Mock 认领等待确认的调用（按消息数量条件更新对话）并保存结果（rolePatterns 依次匹配每条结果）
+func mockClaimToolCalls(m sqlmock.Sqlmock, messageCount int, rolePatterns ...string) {
+	m.ExpectBegin()
+	m.ExpectExec(`UPDATE "conversations" SET .+"message_count"=` + strconv.Itoa(messageCount+len(rolePatterns)) +
+		`,.+WHERE .+"message_count" = ` + strconv.Itoa(messageCount) + `\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, pattern := range rolePatterns {
+		m.ExpectExec(`INSERT INTO "messages" .+` + pattern).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	m.ExpectCommit()
+}
+
+// pendingHistory 等待确认 delete_item 调用的历史消息
+func pendingHistory(conv *model.Conversation) []*model.Message {
+	user, _ := model.NewMessage(conv.ID, model.RoleUser, "Delete apples")
+	user.CreatedAt = TestTime
+	call := model.NewAssistantMessage(conv.ID, "", TestModel, mock.Name, 5, 3, 10)
+	call.ToolCalls = []model.ToolCall{{ID: "call-1", Name: "delete_item", Arguments: `{"name":"apples"}`}}
+	call.CreatedAt = TestTime.Add(time.Second)
+	return []*model.Message{user, call}
+}
+
+// TestRunAgent_ToolLoop 测试任务助手执行工具后根据结果回复，调用和结果都记录在对话中
+func TestRunAgent_ToolLoop(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.LLM.Enqueue(
+		mock.Response{ToolCalls: []llmmodel.ToolCall{{ID: "call-1", Name: "list_items", Arguments: `{"name":"apples"}`}}},
+		mock.Response{Content: "You have apples."},
+	)
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 1, `'user', 'What do I have\?'`)
+	mockSaveMessages(helper.Mock, 3,
+		`'assistant', '', 'mock-model', 'mock', .+'\[{"id":"call-1","name":"list_items"`,
+		`'tool', '{"status":"ok","output":{"item":"apples"}}', .+'call-1', 'list_items'`)
+	mockSaveMessages(helper.Mock, 4, `'assistant', 'You have apples.'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent", map[string]string{"content": "What do I have?"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.AgentResponse
+	DecodeResponse(t, w, &resp)
+	require.Len(t, resp.Messages, 4)
+	assert.Equal(t, "list_items", resp.Messages[1].ToolCalls[0].Name)
+	assert.Equal(t, "call-1", resp.Messages[2].ToolCallID)
+	require.NotNil(t, resp.Reply)
+	assert.Equal(t, "You have apples.", resp.Reply.Content)
+	assert.Empty(t, resp.PendingToolCalls)
+	assert.False(t, resp.StepLimitReached)
+
+	// 工具绑定当前用户
+	assert.Equal(t, []string{TestUserID + ":list_items:apples"}, helper.ToolCalls())
+
+	// 模型收到工具定义、系统提示，以及第二步中的工具调用和结果
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[0].Tools, 2)
+	assert.Equal(t, llmmodel.RoleSystem, requests[0].Messages[0].Role)
+	second := requests[1].Messages
+	require.Len(t, second, 4)
+	assert.Equal(t, "call-1", second[2].ToolCalls[0].ID)
+	assert.Equal(t, llmmodel.RoleTool, second[3].Role)
+	assert.Equal(t, "call-1", second[3].ToolCallID)
+
+	assert.Equal(t, []string{"MessageSent", "GenerationCompleted", "GenerationCompleted", "MessageReceived"}, helper.EventTypes())
+	helper.AssertExpectations(t)
+}
+
+// TestRunAgent_RequiresConfirmation 测试需要确认的工具调用不执行，等待用户确认
+func TestRunAgent_RequiresConfirmation(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.LLM.Enqueue(mock.Response{ToolCalls: []llmmodel.ToolCall{
+		{ID: "call-1", Name: "delete_item", Arguments: `{"name":"apples"}`},
+		{ID: "call-2", Name: "list_items", Arguments: `{"name":"pears"}`},
+	}})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 1, `'user'`)
+	mockSaveMessages(helper.Mock, 3, `'assistant'`, `'tool', .+'call-2', 'list_items'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent", map[string]string{"content": "Delete apples"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.AgentResponse
+	DecodeResponse(t, w, &resp)
+	assert.Nil(t, resp.Reply)
+	require.Len(t, resp.PendingToolCalls, 1)
+	assert.Equal(t, "delete_item", resp.PendingToolCalls[0].Name)
+
+	// 只执行了不需要确认的调用
+	assert.Equal(t, []string{TestUserID + ":list_items:pears"}, helper.ToolCalls())
+	helper.AssertExpectations(t)
+}
+
+// TestRunAgent_TOOL_CONFIRMATION_PENDING 测试有等待确认的调用时不能发送新消息
+func TestRunAgent_TOOL_CONFIRMATION_PENDING(t *testing.T) {
+	for _, path := range []string{"/agent", "/messages"} {
+		t.Run(path, func(t *testing.T) {
+			helper := NewTestHelper(t)
+			defer helper.Close()
+
+			conv := CreateTestConversation("My chat")
+			MockFindConversation(helper.Mock, conv)
+			MockListRecent(helper.Mock, pendingHistory(conv)...)
+
+			w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+path, map[string]string{"content": "Hello"})
+
+			assert.Equal(t, consts.StatusConflict, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), "TOOL_CONFIRMATION_PENDING")
+			assert.Empty(t, helper.LLM.Requests())
+			helper.AssertExpectations(t)
+		})
+	}
+}
+
+// TestConfirmToolCalls_Approve 测试确认后执行调用并继续运行
+func TestConfirmToolCalls_Approve(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	conv.MessageCount = 2
+	helper.LLM.Enqueue(mock.Response{Content: "Deleted apples."})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, pendingHistory(conv)...)
+	mockClaimToolCalls(helper.Mock, 2, `'tool', '{"status":"ok","output":{"item":"apples"}}', .+'call-1', 'delete_item'`)
+	mockSaveMessages(helper.Mock, 4, `'assistant', 'Deleted apples.'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent/confirm", map[string]bool{"approve": true})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.AgentResponse
+	DecodeResponse(t, w, &resp)
+	require.Len(t, resp.Messages, 2)
+	require.NotNil(t, resp.Reply)
+	assert.Equal(t, "Deleted apples.", resp.Reply.Content)
+	assert.Equal(t, []string{TestUserID + ":delete_item:apples"}, helper.ToolCalls())
+	helper.AssertExpectations(t)
+}
+
+// TestConfirmToolCalls_Reject 测试拒绝后不执行调用，模型收到 rejected 结果
+func TestConfirmToolCalls_Reject(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	conv.MessageCount = 2
+	helper.LLM.Enqueue(mock.Response{Content: "OK, I kept them."})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, pendingHistory(conv)...)
+	mockClaimToolCalls(helper.Mock, 2, `'tool', '{"status":"rejected",.+'call-1', 'delete_item'`)
+	mockSaveMessages(helper.Mock, 4, `'assistant', 'OK, I kept them.'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent/confirm", map[string]bool{"approve": false})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, helper.ToolCalls())
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	last := requests[0].Messages[len(requests[0].Messages)-1]
+	assert.Equal(t, llmmodel.RoleTool, last.Role)
+	assert.Contains(t, last.Content, "rejected")
+	helper.AssertExpectations(t)
+}
+
+// TestConfirmToolCalls_NO_PENDING_TOOL_CALLS 测试没有等待确认的调用
+func TestConfirmToolCalls_NO_PENDING_TOOL_CALLS(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("My chat"))
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent/confirm", map[string]bool{"approve": true})
+
+	assert.Equal(t, consts.StatusConflict, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "NO_PENDING_TOOL_CALLS")
+	helper.AssertExpectations(t)
+}
+
+// TestConfirmToolCalls_AlreadyClaimed 测试调用已被另一个确认认领（消息数量已变化）时拒绝，不重复执行
+func TestConfirmToolCalls_AlreadyClaimed(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	conv.MessageCount = 2
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, pendingHistory(conv)...)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`UPDATE "conversations" SET .+WHERE .+"message_count" = 2\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	helper.Mock.ExpectRollback()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent/confirm", map[string]bool{"approve": true})
+
+	assert.Equal(t, consts.StatusConflict, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "NO_PENDING_TOOL_CALLS")
+	assert.Empty(t, helper.ToolCalls())
+	assert.Empty(t, helper.LLM.Requests())
+	helper.AssertExpectations(t)
+}
+
+// TestRunAgent_StepLimit 测试达到步数上限时停止
+func TestRunAgent_StepLimit(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.LLM.SetHandler(func(req *llmmodel.ChatRequest) mock.Response {
+		return mock.Response{ToolCalls: []llmmodel.ToolCall{{ID: "call", Name: "list_items", Arguments: `{"name":"x"}`}}}
+	})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 1, `'user'`)
+	for step := 1; step <= TestAgentMaxSteps; step++ {
+		mockSaveMessages(helper.Mock, 1+2*step, `'assistant'`, `'tool'`)
+	}
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent", map[string]string{"content": "Loop forever"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.AgentResponse
+	DecodeResponse(t, w, &resp)
+	assert.True(t, resp.StepLimitReached)
+	assert.Nil(t, resp.Reply)
+	assert.Len(t, helper.LLM.Requests(), TestAgentMaxSteps)
+	assert.Len(t, helper.ToolCalls(), TestAgentMaxSteps)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// longHistory 交替的 user/assistant 历史消息，内容为 "m<i> " 开头的 400 个字符（估算 104 Token）
+func longHistory(conv *model.Conversation, n int) []*model.Message {
+	messages := make([]*model.Message, 0, n)
+	for i := 0; i < n; i++ {
+		role := model.RoleUser
+		if i%2 == 1 {
+			role = model.RoleAssistant
+		}
+		prefix := fmt.Sprintf("m%d ", i)
+		msg, _ := model.NewMessage(conv.ID, role, prefix+strings.Repeat("a", 400-len(prefix)))
+		msg.CreatedAt = TestTime.Add(time.Duration(i) * time.Second)
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+// createSmallModelConversation 使用 TestSmallModel 的对话
+func createSmallModelConversation(strategy model.ContextStrategy) *model.Conversation {
+	conv := CreateTestConversation("My chat")
+	conv.Provider = mock.Name
+	conv.Model = TestSmallModel
+	conv.Context.Strategy = strategy
+	return conv
+}
+
+// contentPrefixes 返回请求中每条消息内容的前 3 个字符
+func contentPrefixes(messages []llmmodel.Message) []string {
+	prefixes := make([]string, 0, len(messages))
+	for _, m := range messages {
+		prefixes = append(prefixes, strings.SplitN(m.Content, " ", 2)[0])
+	}
+	return prefixes
+}
+
+// TestSendMessage_TruncatesToContextWindow 测试历史消息超出模型的上下文窗口时丢弃最早的消息
+func TestSendMessage_TruncatesToContextWindow(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := createSmallModelConversation("")
+	helper.LLM.Enqueue(mock.Response{Content: "Hi"})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, longHistory(conv, 10)...)
+	mockSaveMessages(helper.Mock, 2, `'user', 'Hello'`, `'assistant', 'Hi'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages",
+		map[string]string{"content": "Hello"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	// 预算 = 1000 - 50（回复预留）- 3 = 947：新消息 6 + 9 条历史 × 104 = 942
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, []string{"m1", "m2", "m3", "m4", "m5", "m6", "m7", "m8", "m9", "Hello"}, contentPrefixes(requests[0].Messages))
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_SlidingWindow 测试滑动窗口只发送最近的 N 条消息（包括新消息）
+func TestSendMessage_SlidingWindow(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	conv.Context = model.ContextSettings{Strategy: model.ContextSlidingWindow, WindowSize: 3}
+	helper.LLM.Enqueue(mock.Response{Content: "Hi"})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, longHistory(conv, 6)...)
+	mockSaveMessages(helper.Mock, 2, `'user', 'Hello'`, `'assistant', 'Hi'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages",
+		map[string]string{"content": "Hello"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"m4", "m5", "Hello"}, contentPrefixes(helper.LLM.Requests()[0].Messages))
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_Summarize 测试接近上下文窗口时较早的消息合并为摘要，摘要和消息一起保存
+func TestSendMessage_Summarize(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := createSmallModelConversation(model.ContextSummarize)
+	history := longHistory(conv, 8)
+	helper.LLM.Enqueue(mock.Response{Content: "User is planning a trip."}, mock.Response{Content: "Sure"})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, history...)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user', 'Hello'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', 'Sure'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations" SET .+"summary"='User is planning a trip.',"summary_until"='` + history[3].ID + `'`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages",
+		map[string]string{"content": "Hello"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 2)
+
+	// 摘要请求：已超过预算的 80%，最近的消息只保留预算的一半（m4-m7），m0-m3 合并为摘要
+	summarize := requests[0]
+	require.Len(t, summarize.Messages, 2)
+	assert.Equal(t, llmmodel.RoleSystem, summarize.Messages[0].Role)
+	assert.Contains(t, summarize.Messages[1].Content, "user: m0 ")
+	assert.Contains(t, summarize.Messages[1].Content, "assistant: m3 ")
+	assert.NotContains(t, summarize.Messages[1].Content, "m4 ")
+	assert.True(t, summarize.Deterministic())
+
+	// 回复请求：摘要作为系统提示放在最前面
+	reply := requests[1]
+	assert.Equal(t, llmmodel.RoleSystem, reply.Messages[0].Role)
+	assert.Contains(t, reply.Messages[0].Content, "User is planning a trip.")
+	assert.Equal(t, []string{"m4", "m5", "m6", "m7", "Hello"}, contentPrefixes(reply.Messages[1:]))
+
+	assert.Equal(t, []string{"GenerationCompleted", "GenerationCompleted", "MessageSent", "MessageReceived"}, helper.EventTypes())
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_ExistingSummary 测试已有摘要时不再发送摘要中的消息
+func TestSendMessage_ExistingSummary(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	history := longHistory(conv, 4)
+	conv.Context.Strategy = model.ContextSummarize
+	conv.RecordSummary("Earlier: planned a trip", history[1].ID)
+	helper.LLM.Enqueue(mock.Response{Content: "Hi"})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, history...)
+	mockSaveMessages(helper.Mock, 2, `'user', 'Hello'`, `'assistant', 'Hi'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages",
+		map[string]string{"content": "Hello"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Messages[0].Content, "Earlier: planned a trip")
+	assert.Equal(t, []string{"m2", "m3", "Hello"}, contentPrefixes(requests[0].Messages[1:]))
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_CONTEXT_WINDOW_EXCEEDED 测试新消息本身超出上下文窗口时不调用模型
+func TestSendMessage_CONTEXT_WINDOW_EXCEEDED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := createSmallModelConversation("")
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages",
+		map[string]string{"content": strings.Repeat("a", 4000)})
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "CONTEXT_WINDOW_EXCEEDED", resp.Error)
+	assert.Empty(t, helper.LLM.Requests())
+	helper.AssertExpectations(t)
+}
+
+// TestUpdateContextSettings 测试修改对话的上下文策略
+func TestUpdateContextSettings(t *testing.T) {
+	t.Run("滑动窗口", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		MockFindConversation(helper.Mock, CreateTestConversation("My chat"))
+		helper.Mock.ExpectExec(`UPDATE "conversations" SET "context_strategy"='sliding_window',"context_window_size"=10,`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		w := helper.PerformRequest("PUT", "/api/conversations/"+TestConversationID+"/context",
+			map[string]interface{}{"strategy": "sliding_window", "window_size": 10})
+
+		require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+		var resp dto.ConversationResponse
+		DecodeResponse(t, w, &resp)
+		assert.Equal(t, dto.ContextSettingsResponse{Strategy: "sliding_window", WindowSize: 10}, resp.Context)
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("无效的策略", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		w := helper.PerformRequest("PUT", "/api/conversations/"+TestConversationID+"/context",
+			map[string]interface{}{"strategy": "forget"})
+
+		assert.Equal(t, consts.StatusBadRequest, w.Code)
+		var resp dto.ErrorResponse
+		DecodeResponse(t, w, &resp)
+		assert.Equal(t, "INVALID_INPUT", resp.Error)
+		helper.AssertExpectations(t)
+	})
+}
+
+// TestPreviewContext 测试查看组装的提示词（不调用模型，不生成摘要）
+func TestPreviewContext(t *testing.T) {
+	t.Run("裁剪", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		conv := createSmallModelConversation("")
+		MockFindConversation(helper.Mock, conv)
+		MockListRecent(helper.Mock, longHistory(conv, 10)...)
+
+		w := helper.PerformRequest("GET", "/api/conversations/"+TestConversationID+"/context/prompt?content=Hello", nil)
+
+		require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+		var resp dto.PreviewContextResponse
+		DecodeResponse(t, w, &resp)
+		assert.Equal(t, "truncate", resp.Strategy)
+		assert.Equal(t, mock.Name, resp.Provider)
+		assert.Equal(t, TestSmallModel, resp.Model)
+		assert.Equal(t, TestSmallContextWindow, resp.ContextWindow)
+		assert.Equal(t, 947, resp.Budget)
+		assert.Equal(t, 3+6+9*104, resp.PromptTokens)
+		assert.Equal(t, 1, resp.DroppedMessages)
+		assert.False(t, resp.SummaryPending)
+		require.Len(t, resp.Messages, 10)
+		assert.Equal(t, "Hello", resp.Messages[9].Content)
+		assert.Equal(t, 6, resp.Messages[9].Tokens)
+		assert.Empty(t, helper.LLM.Requests())
+		helper.AssertExpectations(t)
+	})
+
+	t.Run("需要摘要", func(t *testing.T) {
+		helper := NewTestHelper(t)
+		defer helper.Close()
+
+		conv := createSmallModelConversation(model.ContextSummarize)
+		MockFindConversation(helper.Mock, conv)
+		MockListRecent(helper.Mock, longHistory(conv, 8)...)
+
+		w := helper.PerformRequest("GET", "/api/conversations/"+TestConversationID+"/context/prompt", nil)
+
+		require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+		var resp dto.PreviewContextResponse
+		DecodeResponse(t, w, &resp)
+		assert.True(t, resp.SummaryPending)
+		assert.Equal(t, 4, resp.DroppedMessages)
+		assert.Empty(t, helper.LLM.Requests())
+		helper.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateConversation_Success 测试创建对话（未指定标题时使用默认标题）
+func TestCreateConversation_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectExec(`INSERT INTO "conversations"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := helper.PerformRequest("POST", "/api/conversations", map[string]string{"model": "gpt-4o-mini"})
+
+	require.Equal(t, consts.StatusCreated, w.Code)
+	var resp dto.ConversationResponse
+	DecodeResponse(t, w, &resp)
+	assert.NotEmpty(t, resp.ConversationID)
+	assert.Equal(t, model.DefaultConversationTitle, resp.Title)
+	assert.Equal(t, "gpt-4o-mini", resp.Model)
+	assert.Equal(t, []string{"ConversationCreated"}, helper.EventTypes())
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateConversation_INVALID_INPUT 测试标题不满足 conversation_title 规则
+func TestCreateConversation_INVALID_INPUT(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	w := helper.PerformRequest("POST", "/api/conversations", map[string]string{"title": strings.Repeat("a", 201)})
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestListConversations_Success 测试列出对话
+func TestListConversations_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("Hello")
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "conversations"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "conversations" .+LIMIT 20`).
+		WillReturnRows(sqlmock.NewRows(conversationColumns).AddRow(
+			conv.ID, conv.UserID, conv.Title, nil, nil, 0, nil, nil, nil, nil, TestTime, TestTime))
+
+	w := helper.PerformRequest("GET", "/api/conversations", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.ListConversationsResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, 20, resp.Limit)
+	require.Len(t, resp.Conversations, 1)
+	assert.Equal(t, "Hello", resp.Conversations[0].Title)
+
+	helper.AssertExpectations(t)
+}
+
+// TestRenameConversation_Success 测试重命名对话
+func TestRenameConversation_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Old"))
+	helper.Mock.ExpectExec(`UPDATE "conversations" SET .+"title"='New'`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := helper.PerformRequest("PUT", "/api/conversations/"+TestConversationID, map[string]string{"title": "New"})
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.ConversationResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "New", resp.Title)
+
+	helper.AssertExpectations(t)
+}
+
+// TestRenameConversation_UNAUTHORIZED_ACCESS 测试重命名其他用户的对话
+func TestRenameConversation_UNAUTHORIZED_ACCESS(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("Old")
+	conv.UserID = "other-user"
+	MockFindConversation(helper.Mock, conv)
+
+	w := helper.PerformRequest("PUT", "/api/conversations/"+TestConversationID, map[string]string{"title": "New"})
+
+	assert.Equal(t, consts.StatusForbidden, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestDeleteConversation_Success 测试删除对话
+func TestDeleteConversation_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("Hello")
+	conv.MessageCount = 4
+	MockFindConversation(helper.Mock, conv)
+	helper.Mock.ExpectExec(`DELETE FROM "conversations" WHERE \("id"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := helper.PerformRequest("DELETE", "/api/conversations/"+TestConversationID, nil)
+
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.Equal(t, []string{"ConversationDeleted"}, helper.EventTypes())
+	helper.AssertExpectations(t)
+}
+
+// TestDeleteConversation_CONVERSATION_NOT_FOUND 测试删除不存在的对话
+func TestDeleteConversation_CONVERSATION_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "conversations"`).WillReturnError(sql.ErrNoRows)
+
+	w := helper.PerformRequest("DELETE", "/api/conversations/missing", nil)
+
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSendGroundedMessage_Citations 测试资料在 Token 预算内放入系统提示，回复只引用放入的资料
+func TestSendGroundedMessage_Citations(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.Retriever.Docs = []retrieval.Document{
+		{Source: "task", ID: "t1", Title: "Write report", Content: "状态：pending；截止：2026-10-16T18:00:00Z"},
+		{Source: "task", ID: "t2", Title: "Huge task", Content: strings.Repeat("很长的描述", 100)}, // 超出预算
+		{Source: "task", ID: "t3", Title: "Pay bill", Content: "状态：pending"},
+	}
+	helper.LLM.Enqueue(mock.Response{Content: "Write the report [task:t1], then pay the bill [task:t3]. See also [task:t2] and [task:nope] [task:t1]."})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 2,
+		`'user', 'What is on my plate this week\?'`,
+		`'assistant', 'Write the report .+'\[{"source":"task","id":"t1","title":"Write report"},{"source":"task","id":"t3","title":"Pay bill"}\]'`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages/grounded",
+		map[string]string{"content": "What is on my plate this week?"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.SendGroundedMessageResponse
+	DecodeResponse(t, w, &resp)
+	expected := []dto.CitationResponse{
+		{Source: "task", ID: "t1", Title: "Write report"},
+		{Source: "task", ID: "t3", Title: "Pay bill"},
+	}
+	assert.Equal(t, expected, resp.Citations)
+	assert.Equal(t, expected, resp.Reply.Citations)
+	assert.Equal(t, "user", resp.Message.Role)
+
+	// 按当前用户和问题检索
+	assert.Equal(t, []string{TestUserID + ":What is on my plate this week?"}, helper.Retriever.Queries())
+
+	// 资料作为系统提示放在最前面，超出预算的资料不放入
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	system := requests[0].Messages[0]
+	assert.Equal(t, llmmodel.RoleSystem, system.Role)
+	assert.Contains(t, system.Content, "[task:t1] Write report\n状态：pending")
+	assert.Contains(t, system.Content, "[task:t3] Pay bill")
+	assert.NotContains(t, system.Content, "[task:t2]")
+	assert.Equal(t, "What is on my plate this week?", requests[0].Messages[1].Content)
+
+	assert.Equal(t, []string{"GenerationCompleted", "MessageSent", "MessageReceived"}, helper.EventTypes())
+	helper.AssertExpectations(t)
+}
+
+// TestSendGroundedMessage_NoDocuments 测试没有资料时仍然回答（提示模型没有相关资料），引用为空数组
+func TestSendGroundedMessage_NoDocuments(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.LLM.Enqueue(mock.Response{Content: "You have no tasks due."})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 2, `'user', 'Anything due\?'`, `'assistant', 'You have no tasks due.', .+, NULL, NULL, NULL`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages/grounded",
+		map[string]string{"content": "Anything due?"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.SendGroundedMessageResponse
+	DecodeResponse(t, w, &resp)
+	assert.NotNil(t, resp.Citations)
+	assert.Empty(t, resp.Citations)
+	assert.Contains(t, helper.LLM.Requests()[0].Messages[0].Content, "（没有相关资料）")
+	helper.AssertExpectations(t)
+}
+
+// TestSendGroundedMessage_RETRIEVAL_FAILED 测试检索失败时不调用模型、不保存消息
+func TestSendGroundedMessage_RETRIEVAL_FAILED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("My chat")
+	helper.Retriever.Err = errors.New("database down")
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages/grounded",
+		map[string]string{"content": "Anything due?"})
+
+	assert.Equal(t, consts.StatusInternalServerError, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "RETRIEVAL_FAILED", resp.Error)
+	assert.Empty(t, helper.LLM.Requests())
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,423 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/handlers"
+	chathttp "github.com/erweixin/go-genai-stack/backend/domains/chat/http"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/cassette"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/openai"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tool"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	taskrepo "github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	taskservice "github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	tasktools "github.com/erweixin/go-genai-stack/backend/domains/task/tools"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// ========== 测试常量 ==========
+
+const (
+	TestUserID         = "test-user-123"
+	TestConversationID = "conv-123"
+	TestModel          = "mock-model"
+
+	// TestAgentMaxSteps 测试中任务助手的步数上限
+	TestAgentMaxSteps = 3
+
+	// TestRetrievalTokenBudget 测试中资料部分的 Token 预算
+	TestRetrievalTokenBudget = 200
+
+	// TestSmallModel 上下文窗口只有 TestSmallContextWindow 的模型（测试裁剪和摘要）
+	TestSmallModel         = "small-model"
+	TestSmallContextWindow = 1000
+
+	// TestReplyReserveTokens 测试中为回复预留的 Token 数
+	TestReplyReserveTokens = 50
+
+	// ReplayProvider / ReplayModel 录制回放测试使用的提供商和模型
+	ReplayProvider = "openai"
+	ReplayModel    = "gpt-4o-mini"
+)
+
+// TestTime 测试时间常量
+var TestTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// conversationColumns conversations 表列（与 repository 保持一致）
+var conversationColumns = []string{
+	"id", "user_id", "title", "model", "provider", "message_count",
+	"context_strategy", "context_window_size", "summary", "summary_until",
+	"created_at", "updated_at",
+}
+
+// messageColumns messages 表列（与 repository 保持一致）
+var messageColumns = []string{
+	"id", "conversation_id", "role", "content", "model", "provider",
+	"input_tokens", "output_tokens", "latency_ms", "truncated",
+	"tool_calls", "tool_call_id", "tool_name", "citations", "created_at",
+}
+
+// quotaStub 测试用额度守卫（Exhausted 为 true 时拒绝所有调用）
+type quotaStub struct {
+	Exhausted bool
+}
+
+func (q *quotaStub) Reserve(ctx context.Context, req *llmmodel.ChatRequest) (llmservice.QuotaReservation, error) {
+	if q.Exhausted {
+		return nil, fmt.Errorf("%w: 今日 Token 额度不足（free 套餐）", llmmodel.ErrQuotaExceeded)
+	}
+	return nil, nil
+}
+
+// retrieverStub 测试用资料检索（返回固定的资料，记录检索的用户和问题）
+type retrieverStub struct {
+	mu      sync.Mutex
+	Docs    []retrieval.Document
+	Err     error
+	queries []string // "用户 ID:问题"
+}
+
+func (r *retrieverStub) Retrieve(ctx context.Context, userID, query string, limit int) ([]retrieval.Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, userID+":"+query)
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return r.Docs, nil
+}
+
+// Queries 返回已执行的检索
+func (r *retrieverStub) Queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.queries...)
+}
+
+// catalogStub 测试用模型目录（只有 TestSmallModel 设置了上下文窗口）
+type catalogStub struct{}
+
+func (catalogStub) ModelSpec(provider, name string) (llmmodel.ModelSpec, bool) {
+	if provider == mock.Name && name == TestSmallModel {
+		return llmmodel.ModelSpec{Provider: provider, Model: name, ContextWindow: TestSmallContextWindow}, true
+	}
+	return llmmodel.ModelSpec{}, false
+}
+
+// TestHelper 提供测试辅助方法
+//
+// 数据库使用 sqlmock，LLM 使用 mock 提供商（或录制回放的真实客户端，见 NewReplayTestHelper），事件总线记录 chat 事件、GenerationCompleted 和 AlertTriggered。
+// LLMService 使用可切换的额度守卫（默认不限制）。
+// 任务助手使用测试工具集：list_items（直接执行）和 delete_item（需要确认），执行记录在 ToolCalls 中；
+// 录制回放测试使用 Task 领域真实的工具集（发送给模型的工具定义与生产环境一致）。
+// 基于资料的回答使用 Retriever（资料由测试设置，Token 预算为 TestRetrievalTokenBudget）。
+// 内容审核：屏蔽词 "shit*" 拒绝，个人信息替换，提示词注入只记录。
+// 上下文窗口按字符估算 Token，只有 TestSmallModel 的窗口较小（TestSmallContextWindow）。
+// 系统提示中的当前时间固定为 TestTime（录制的请求才能匹配）。
+// 请求经过真实的路由和认证中间件（使用测试用户的 Token）。
+type TestHelper struct {
+	DB          *sql.DB
+	Mock        sqlmock.Sqlmock
+	LLM         *mock.Provider // 录制回放测试中为 nil
+	Quota       *quotaStub
+	Retriever   *retrieverStub
+	HandlerDeps *handlers.HandlerDependencies
+	Server      *server.Hertz
+
+	token     string // 测试用户的 Access Token
+	mu        sync.Mutex
+	events    []sharedevents.Event
+	toolCalls []string // 已执行的工具调用（"用户 ID:工具名:参数"）
+}
+
+// NewTestHelper 创建测试辅助工具
+func NewTestHelper(t *testing.T) *TestHelper {
+	llm := mock.New()
+	h := newTestHelper(t, llm, TestModel, false)
+	h.LLM = llm
+	return h
+}
+
+// NewReplayTestHelper 创建使用录制回放的测试辅助工具
+//
+// LLM 使用真实的 OpenAI 兼容客户端（ReplayProvider / ReplayModel），HTTP 调用从
+// testdata/cassettes/<name>.json 回放，不访问网络；测试结束时检查录制是否全部被使用。
+// APP_LLM_CASSETTE=record 时调用真实接口重新录制：API Key 为 APP_LLM_PROVIDERS_OPENAI，
+// 地址为 APP_LLM_BASE_URLS_OPENAI（默认官方地址）。录制的文件不包含 API Key。
+// 任务助手使用 Task 领域的工具集（tasktools.NewRegistry），工具访问同一个 sqlmock 数据库。
+func NewReplayTestHelper(t *testing.T, name string) *TestHelper {
+	apiKey := os.Getenv("APP_LLM_PROVIDERS_OPENAI")
+	rec, err := cassette.New(filepath.Join("testdata", "cassettes", name+".json"), cassette.Options{
+		Mode:    cassette.ModeFromEnv(),
+		Secrets: []string{apiKey},
+	})
+	if err != nil {
+		t.Fatalf("failed to open cassette: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Close(); err != nil {
+			t.Errorf("failed to save cassette: %v", err)
+		}
+		if n := rec.Unused(); n > 0 && rec.Mode() == cassette.ModeReplay {
+			t.Errorf("cassette %s: %d recorded interactions were not used", name, n)
+		}
+	})
+
+	baseURL := os.Getenv("APP_LLM_BASE_URLS_OPENAI")
+	if rec.Mode() == cassette.ModeReplay {
+		baseURL = openai.DefaultBaseURL // 回放与地址无关，固定地址避免本地环境变量影响
+	}
+	return newTestHelper(t, openai.New(openai.Config{
+		Name:       ReplayProvider,
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		Timeout:    time.Minute,
+		HTTPClient: rec.Client(),
+	}), ReplayModel, true)
+}
+
+// newTestHelper 使用提供商 llm（默认模型为 defaultModel）创建测试辅助工具
+//
+// taskTools 为 true 时任务助手使用 Task 领域的工具集，否则使用测试工具集。
+func newTestHelper(t *testing.T, llm llmprovider.Provider, defaultModel string, taskTools bool) *TestHelper {
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	h := &TestHelper{DB: db, Mock: sqlMock, Quota: &quotaStub{}, Retriever: &retrieverStub{}}
+
+	eventBus := sharedevents.NewDefaultEventBus()
+	for _, eventType := range []string{"ConversationCreated", "ConversationDeleted", "MessageSent", "MessageReceived", "GenerationCompleted", "AlertTriggered"} {
+		_ = eventBus.Subscribe(eventType, h.recordEvent)
+	}
+
+	registry := llmprovider.NewRegistry()
+	registry.Register(llm)
+	llmService := llmservice.NewLLMService(registry, llm.Name(), defaultModel, eventBus).WithQuota(h.Quota)
+
+	toolset := h.newToolset
+	if taskTools {
+		taskService := taskservice.NewTaskService(taskrepo.NewTaskRepository(db, "postgres"))
+		toolset = func(userID string) (*tool.Registry, error) {
+			return tasktools.NewRegistry(taskService, userID)
+		}
+	}
+
+	chatService := service.NewChatService(
+		repository.NewConversationRepository(db, "postgres"),
+		repository.NewMessageRepository(db, "postgres"),
+		llmService,
+		persistence.NewTxManager(db),
+		eventBus,
+	).WithAgent(toolset, TestAgentMaxSteps).
+		WithRetriever(h.Retriever, TestRetrievalTokenBudget).
+		WithContextManager(service.NewContextManager(tokenizer.Estimator{}, catalogStub{}, TestReplyReserveTokens)).
+		WithModeration(newTestModeration(t, eventBus)).
+		WithClock(func() time.Time { return TestTime })
+	h.HandlerDeps = handlers.NewHandlerDependencies(chatService)
+
+	// 使用完整的 Server 注册真实路由（绑定器与生产环境一致），
+	// 认证中间件替换为直接注入测试用户 ID
+	h.Server = server.Default(
+		server.WithHostPorts("127.0.0.1:0"),
+		server.WithExitWaitTime(0),
+	)
+	jwtService := authservice.NewJWTService("test-secret", time.Hour, time.Hour, "test")
+	token, _, err := jwtService.GenerateAccessToken(TestUserID, "test@example.com")
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.token = token
+	chathttp.RegisterRoutes(h.Server.Group("/api"), h.HandlerDeps, middleware.NewAuthMiddleware(jwtService))
+	return h
+}
+
+// newTestModeration 测试用的内容审核
+func newTestModeration(t *testing.T, eventBus sharedevents.EventBus) *moderation.Pipeline {
+	wordlist, err := moderation.NewWordlist([]string{"shit*"})
+	if err != nil {
+		t.Fatalf("failed to create wordlist: %v", err)
+	}
+	return moderation.NewPipeline(eventBus,
+		moderation.Rule{Checker: wordlist, Action: moderation.ActionBlock},
+		moderation.Rule{Checker: moderation.PII{}, Action: moderation.ActionRedact},
+		moderation.Rule{Checker: moderation.Injection{}, Action: moderation.ActionFlag},
+	)
+}
+
+// Close 清理资源
+func (h *TestHelper) Close() error {
+	return h.DB.Close()
+}
+
+// AssertExpectations 验证所有 mock 期望都被满足
+func (h *TestHelper) AssertExpectations(t *testing.T) {
+	if err := h.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// EventTypes 返回已发布事件的类型（按发布顺序）
+func (h *TestHelper) EventTypes() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	types := make([]string, 0, len(h.events))
+	for _, e := range h.events {
+		types = append(types, e.Type())
+	}
+	return types
+}
+
+// Events 返回已发布的事件
+func (h *TestHelper) Events() []sharedevents.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]sharedevents.Event(nil), h.events...)
+}
+
+func (h *TestHelper) recordEvent(ctx context.Context, event sharedevents.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+	return nil
+}
+
+// ToolCalls 返回已执行的工具调用
+func (h *TestHelper) ToolCalls() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.toolCalls...)
+}
+
+// itemArgs 测试工具参数
+type itemArgs struct {
+	Name string `json:"name"`
+}
+
+// newToolset 测试工具集（记录调用的用户和参数）
+func (h *TestHelper) newToolset(userID string) (*tool.Registry, error) {
+	record := func(name string) func(ctx context.Context, args itemArgs) (any, error) {
+		return func(ctx context.Context, args itemArgs) (any, error) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.toolCalls = append(h.toolCalls, userID+":"+name+":"+args.Name)
+			return map[string]string{"item": args.Name}, nil
+		}
+	}
+	list, err := tool.New("list_items", "List items", record("list_items"))
+	if err != nil {
+		return nil, err
+	}
+	remove, err := tool.New("delete_item", "Delete an item", record("delete_item"))
+	if err != nil {
+		return nil, err
+	}
+	remove.Destructive = true
+
+	registry := tool.NewRegistry()
+	return registry, registry.Register(list, remove)
+}
+
+// ========== 请求构造 ==========
+
+// PerformRequest 执行 HTTP 请求（body 为 nil 时不发送请求体）
+func (h *TestHelper) PerformRequest(method, path string, body interface{}) *ut.ResponseRecorder {
+	var bodyOpt *ut.Body
+	if body != nil {
+		data, _ := json.Marshal(body)
+		bodyOpt = &ut.Body{Body: bytes.NewReader(data), Len: len(data)}
+	}
+	return ut.PerformRequest(h.Server.Engine, method, path, bodyOpt,
+		ut.Header{Key: "Content-Type", Value: "application/json"},
+		ut.Header{Key: "Authorization", Value: "Bearer " + h.token})
+}
+
+// DecodeResponse 解析 JSON 响应
+func DecodeResponse(t *testing.T, w *ut.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v (body: %s)", err, w.Body.String())
+	}
+}
+
+// ========== Mock 辅助函数 ==========
+
+// MockFindConversation Mock 查询对话
+func MockFindConversation(m sqlmock.Sqlmock, conv *model.Conversation) {
+	m.ExpectQuery(`SELECT .+ FROM "conversations" WHERE \("id"`).
+		WillReturnRows(sqlmock.NewRows(conversationColumns).AddRow(
+			conv.ID, conv.UserID, conv.Title, nullable(conv.Model), nullable(conv.Provider),
+			conv.MessageCount, nullable(string(conv.Context.Strategy)), nullableInt(conv.Context.WindowSize),
+			nullable(conv.Summary), nullable(conv.SummaryUntil), conv.CreatedAt, conv.UpdatedAt,
+		))
+}
+
+// MockListRecent Mock 加载最近的历史消息（按时间倒序返回）
+func MockListRecent(m sqlmock.Sqlmock, messages ...*model.Message) {
+	rows := sqlmock.NewRows(messageColumns)
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := messages[i]
+		var toolCalls, citations interface{}
+		if len(msg.ToolCalls) > 0 {
+			data, _ := json.Marshal(msg.ToolCalls)
+			toolCalls = string(data)
+		}
+		if len(msg.Citations) > 0 {
+			data, _ := json.Marshal(msg.Citations)
+			citations = string(data)
+		}
+		rows.AddRow(msg.ID, msg.ConversationID, string(msg.Role), msg.Content,
+			nullable(msg.Model), nullable(msg.Provider),
+			msg.InputTokens, msg.OutputTokens, msg.LatencyMs, msg.Truncated,
+			toolCalls, nullable(msg.ToolCallID), nullable(msg.ToolName), citations, msg.CreatedAt)
+	}
+	m.ExpectQuery(`SELECT .+ FROM "messages" .+ORDER BY "created_at" DESC`).WillReturnRows(rows)
+}
+
+// CreateTestConversation 创建属于测试用户的对话
+func CreateTestConversation(title string) *model.Conversation {
+	conv, _ := model.NewConversation(TestUserID, title, "", "")
+	conv.ID = TestConversationID
+	conv.CreatedAt = TestTime
+	conv.UpdatedAt = TestTime
+	return conv
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullableInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
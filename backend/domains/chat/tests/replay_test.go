@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 录制回放测试：真实的 OpenAI 兼容客户端 + testdata/cassettes 中录制的响应（见 NewReplayTestHelper）
+//
+// 重新录制：APP_LLM_CASSETTE=record APP_LLM_PROVIDERS_OPENAI=sk-... go test ./domains/chat/tests -run Replay
+// 修改发送给模型的内容（系统提示、工具定义等）后需要重新录制，否则回放找不到匹配的请求。
+// 当前的 cassette 是合成的（录制模式 + 本地替身服务），响应内容不是真实模型的输出（见 Chat 领域 README 的“测试”）。
+
+// generationPayloads 返回 GenerationCompleted 事件
+func generationPayloads(helper *TestHelper) []sharedevents.GenerationCompletedPayload {
+	var payloads []sharedevents.GenerationCompletedPayload
+	for _, e := range helper.Events() {
+		if p, ok := e.Payload().(sharedevents.GenerationCompletedPayload); ok {
+			payloads = append(payloads, p)
+		}
+	}
+	return payloads
+}
+
+// TestReplay_SendMessage 测试发送消息（非流式）
+func TestReplay_SendMessage(t *testing.T) {
+	helper := NewReplayTestHelper(t, "send_message")
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Planning"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user', 'What should I focus on today\?'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', 'Start with the quarterly report`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "What should I focus on today?"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.SendMessageResponse
+	DecodeResponse(t, w, &resp)
+	require.NotNil(t, resp.Reply)
+	assert.Equal(t, "Start with the quarterly report, then clear your inbox.", resp.Reply.Content)
+	assert.Equal(t, 12, resp.Reply.OutputTokens)
+
+	generations := generationPayloads(helper)
+	require.Len(t, generations, 1)
+	assert.True(t, generations[0].Success)
+	assert.Equal(t, ReplayProvider, generations[0].Provider)
+	assert.Equal(t, 24, generations[0].InputTokens)
+
+	helper.AssertExpectations(t)
+}
+
+// TestReplay_StreamMessage 测试流式发送：SSE 片段经过真实客户端解析后转发给前端
+func TestReplay_StreamMessage(t *testing.T) {
+	helper := NewReplayTestHelper(t, "stream_message")
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Greetings"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user', 'Say hi in three words'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', 'Hi there, friend!', .+, 14, 5, \d+, FALSE`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	response, events := helper.PerformStream(t, map[string]string{"content": "Say hi in three words"})
+
+	assert.Equal(t, consts.StatusOK, response.StatusCode())
+	var names []string
+	var content strings.Builder
+	for _, e := range events {
+		names = append(names, e.Name)
+		if e.Name == "delta" {
+			var delta dto.StreamDeltaEvent
+			require.NoError(t, json.Unmarshal([]byte(e.Data), &delta))
+			content.WriteString(delta.Content)
+		}
+	}
+	// 回复短于审核缓冲区，结束时一次转发
+	assert.Equal(t, []string{"start", "delta", "done"}, names)
+	assert.Equal(t, "Hi there, friend!", content.String())
+
+	var done dto.StreamDoneEvent
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1].Data), &done))
+	assert.Equal(t, dto.UsageResponse{InputTokens: 14, OutputTokens: 5, TotalTokens: 19}, done.Usage)
+
+	helper.AssertExpectations(t)
+}
+
+// TestReplay_AgentToolLoop 测试任务助手：模型调用 Task 领域的 list_tasks 工具，根据查询结果回复
+//
+// 请求中的工具定义来自 tasktools.NewRegistry，工具定义变化后回放找不到匹配的请求（需要重新录制）。
+func TestReplay_AgentToolLoop(t *testing.T) {
+	helper := NewReplayTestHelper(t, "agent_tool_loop")
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Shopping"))
+	MockListRecent(helper.Mock)
+	mockSaveMessages(helper.Mock, 1, `'user', 'What is on my groceries list\?'`)
+	// list_tasks 只查询当前用户带 groceries 标签的任务
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "tasks" WHERE .*"user_id" = 'test-user-123'.*"tag_name" = 'groceries'`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE .*"user_id" = 'test-user-123'.*"tag_name" = 'groceries'`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "title", "description", "status", "priority",
+			"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+		}).AddRow("task-milk", TestUserID, "Buy milk", "", "pending", "high", nil, TestTime, TestTime, nil, nil, nil))
+	helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags"`).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}).AddRow("task-milk", "groceries", ""))
+	mockSaveMessages(helper.Mock, 3,
+		`'assistant', '', .+'\[{"id":"call_synthetic_1","name":"list_tasks"`,
+		`'tool', '{"status":"ok","output":{"tasks":\[{"id":"task-milk","title":"Buy milk".+"total":1}}', .+'call_synthetic_1', 'list_tasks'`)
+	mockSaveMessages(helper.Mock, 4, `'assistant', 'Your groceries list has one task: Buy milk`)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/agent", map[string]string{"content": "What is on my groceries list?"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.AgentResponse
+	DecodeResponse(t, w, &resp)
+	require.Len(t, resp.Messages, 4)
+	require.Len(t, resp.Messages[1].ToolCalls, 1)
+	assert.Equal(t, "list_tasks", resp.Messages[1].ToolCalls[0].Name)
+	require.NotNil(t, resp.Reply)
+	assert.Equal(t, "Your groceries list has one task: Buy milk (high priority).", resp.Reply.Content)
+	assert.Len(t, generationPayloads(helper), 2)
+
+	helper.AssertExpectations(t)
+}
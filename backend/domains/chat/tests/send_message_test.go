@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/model"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSendMessage_Success 测试发送消息：带历史上下文调用模型，并在同一事务中保存消息
+func TestSendMessage_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	conv := CreateTestConversation("")
+	conv.MessageCount = 1
+	system, _ := model.NewMessage(conv.ID, model.RoleSystem, "You are terse.")
+	system.CreatedAt = TestTime
+
+	helper.LLM.Enqueue(mock.Response{
+		Content: "Hi there",
+		Usage:   &llmmodel.Usage{InputTokens: 7, OutputTokens: 2},
+	})
+
+	MockFindConversation(helper.Mock, conv)
+	MockListRecent(helper.Mock, system)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user', 'Hello'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', 'Hi there', 'mock-model', 'mock', 7, 2`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations" SET .+"message_count"=3,`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "Hello"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.SendMessageResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "user", resp.Message.Role)
+	require.NotNil(t, resp.Reply)
+	assert.Equal(t, "Hi there", resp.Reply.Content)
+	assert.Equal(t, 2, resp.Reply.OutputTokens)
+
+	// 模型收到历史消息 + 新消息
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	require.Len(t, requests[0].Messages, 2)
+	assert.Equal(t, llmmodel.RoleSystem, requests[0].Messages[0].Role)
+	assert.Equal(t, "Hello", requests[0].Messages[1].Content)
+
+	assert.Equal(t, []string{"GenerationCompleted", "MessageSent", "MessageReceived"}, helper.EventTypes())
+	for _, e := range helper.Events() {
+		if received, ok := e.Payload().(sharedevents.MessageReceivedPayload); ok {
+			assert.Equal(t, 2, received.Tokens)
+			assert.Equal(t, TestModel, received.Model)
+		}
+	}
+
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_AutoTitle 测试默认标题的对话以第一条消息命名
+func TestSendMessage_AutoTitle(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation(""))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations" SET .+"title"='Plan my week'`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "Plan my week"})
+
+	assert.Equal(t, consts.StatusOK, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_SystemRole 测试 system 消息只保存、不调用模型
+func TestSendMessage_SystemRole(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'system'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "Be concise", "role": "system"})
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.SendMessageResponse
+	DecodeResponse(t, w, &resp)
+	assert.Nil(t, resp.Reply)
+	assert.Empty(t, helper.LLM.Requests())
+	assert.Equal(t, []string{"MessageSent"}, helper.EventTypes())
+
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_INVALID_MESSAGE_ROLE 测试不能以 assistant 身份发送消息
+func TestSendMessage_INVALID_MESSAGE_ROLE(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "fake", "role": "assistant"})
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_INVALID_INPUT 测试内容为空、角色或路由策略不合法时由 validator 拒绝
+func TestSendMessage_INVALID_INPUT(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	for _, body := range []map[string]string{
+		{"content": ""},
+		{"content": "hi", "role": "tool"},
+		{"content": "hi", "strategy": "fastest"},
+	} {
+		w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", body)
+		assert.Equal(t, consts.StatusBadRequest, w.Code, body)
+	}
+	helper.AssertExpectations(t)
+}
+
+// TestSendMessage_GENERATION_FAILED 测试模型调用失败时不保存任何消息
+func TestSendMessage_GENERATION_FAILED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.LLM.Enqueue(mock.Response{Err: errors.New("upstream unavailable")})
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "Hello"})
+
+	assert.Equal(t, consts.StatusBadGateway, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "GENERATION_FAILED", resp.Error)
+	assert.NotContains(t, helper.EventTypes(), "MessageSent")
+
+	helper.AssertExpectations(t)
+}
+
+// TestListMessages_Success 测试列出消息
+func TestSendMessage_QUOTA_EXCEEDED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Quota.Exhausted = true
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", "/api/conversations/"+TestConversationID+"/messages", map[string]string{"content": "Hello"})
+
+	assert.Equal(t, consts.StatusTooManyRequests, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "QUOTA_EXCEEDED", resp.Error)
+	assert.Contains(t, resp.Message, "今日 Token 额度不足（free 套餐）")
+	assert.Empty(t, helper.LLM.Requests(), "超出额度时不调用模型")
+
+	helper.AssertExpectations(t)
+}
+
+func TestListMessages_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "messages"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "messages" .+ORDER BY "created_at" ASC`).
+		WillReturnRows(sqlmock.NewRows(messageColumns).
+			AddRow("m1", TestConversationID, "user", "Hello", nil, nil, 0, 0, 0, false, nil, nil, nil, nil, TestTime).
+			AddRow("m2", TestConversationID, "assistant", "Hi", TestModel, mock.Name, 3, 1, 10, false, nil, nil, nil, nil, TestTime))
+
+	w := helper.PerformRequest("GET", "/api/conversations/"+TestConversationID+"/messages", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.ListMessagesResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, 2, resp.Total)
+	require.Len(t, resp.Messages, 2)
+	assert.Equal(t, "assistant", resp.Messages[1].Role)
+	assert.Equal(t, TestModel, resp.Messages[1].Model)
+
+	helper.AssertExpectations(t)
+}
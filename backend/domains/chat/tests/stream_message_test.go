@@ -0,0 +1,296 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/common/test/mock"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/protocol"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	llmmock "github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamPath = "/api/conversations/" + TestConversationID + "/messages/stream"
+
+// sseEvent 解析后的 SSE 事件
+type sseEvent struct {
+	Name string
+	Data string
+}
+
+// flakyConn 模拟客户端断开：flush 次数超过 failAfter 后写入失败
+type flakyConn struct {
+	*mock.Conn
+	flushes   int
+	failAfter int
+}
+
+func (c *flakyConn) Flush() error {
+	c.flushes++
+	if c.failAfter > 0 && c.flushes > c.failAfter {
+		return errors.New("broken pipe")
+	}
+	return c.Conn.Flush()
+}
+
+// ServeStream 通过连接 conn 执行流式请求
+//
+// 流式响应直接写入连接，因此不能使用 ut.PerformRequest。
+func (h *TestHelper) ServeStream(conn network.Conn, body interface{}) {
+	data, _ := json.Marshal(body)
+	c := h.Server.Engine.NewContext()
+	protocol.NewRequest("POST", streamPath, nil).CopyTo(&c.Request)
+	c.Request.SetBody(data)
+	c.Request.Header.SetContentLength(len(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Authorization", "Bearer "+h.token)
+	c.SetConn(conn)
+
+	h.Server.Engine.ServeHTTP(context.Background(), c)
+	if w := c.Response.GetHijackWriter(); w != nil {
+		_ = w.Finalize() // 正常情况下由 Server 在 Handler 返回后调用
+	}
+}
+
+// PerformStream 执行流式请求，返回响应和解析出的 SSE 事件
+func (h *TestHelper) PerformStream(t *testing.T, body interface{}) (*protocol.Response, []sseEvent) {
+	t.Helper()
+
+	conn := mock.NewConn("")
+	h.ServeStream(conn, body)
+
+	response := protocol.AcquireResponse()
+	require.NoError(t, resp.Read(response, conn.WriterRecorder()))
+	return response, parseSSE(string(response.Body()))
+}
+
+// parseSSE 解析 SSE 文本（忽略注释行）
+func parseSSE(text string) []sseEvent {
+	var events []sseEvent
+	for _, block := range strings.Split(text, "\n\n") {
+		var e sseEvent
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				e.Name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				e.Data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		if e.Name != "" {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// streamedContent 拼接 delta 事件的内容
+func streamedContent(t *testing.T, events []sseEvent) string {
+	t.Helper()
+	var content strings.Builder
+	for _, e := range events {
+		if e.Name != "delta" {
+			continue
+		}
+		var delta dto.StreamDeltaEvent
+		require.NoError(t, json.Unmarshal([]byte(e.Data), &delta))
+		content.WriteString(delta.Content)
+	}
+	return content.String()
+}
+
+// TestStreamMessage_Success 测试流式发送：start → delta... → done，完成后保存消息
+func TestStreamMessage_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 回复长于审核缓冲区，生成期间即开始转发
+	reply := strings.Repeat("Hello there friend ", 20) + "bye"
+	helper.LLM.Enqueue(llmmock.Response{
+		Content: reply,
+		Usage:   &llmmodel.Usage{InputTokens: 5, OutputTokens: 3},
+	})
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user', 'Hi'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', '` + reply + `', 'mock-model', 'mock', 5, 3, \d+, FALSE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	response, events := helper.PerformStream(t, map[string]string{"content": "Hi"})
+
+	assert.Equal(t, consts.StatusOK, response.StatusCode())
+	assert.Equal(t, "text/event-stream; charset=utf-8", string(response.Header.ContentType()))
+	assert.Equal(t, "no-cache", response.Header.Get("Cache-Control"))
+
+	require.Greater(t, len(events), 3)
+	assert.Equal(t, "start", events[0].Name)
+	assert.Equal(t, "done", events[len(events)-1].Name)
+	for _, e := range events[1 : len(events)-1] {
+		assert.Equal(t, "delta", e.Name)
+	}
+	assert.Equal(t, reply, streamedContent(t, events))
+
+	var done dto.StreamDoneEvent
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1].Data), &done))
+	assert.Equal(t, reply, done.Reply.Content)
+	assert.False(t, done.Reply.Truncated)
+	assert.Equal(t, dto.UsageResponse{InputTokens: 5, OutputTokens: 3, TotalTokens: 8}, done.Usage)
+
+	helper.AssertExpectations(t)
+}
+
+// TestStreamMessage_BlockedReply 测试回复命中屏蔽词：中止生成并发送 error 事件，
+// 屏蔽词和个人信息都不会发送给客户端
+func TestStreamMessage_BlockedReply(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	padding := strings.Repeat("word ", 30)
+	helper.LLM.Enqueue(llmmock.Response{
+		Content: padding + "mail bob@example.com now " + padding + "this is shitty " + padding,
+		Usage:   &llmmodel.Usage{InputTokens: 5, OutputTokens: 100},
+	})
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	// 读到屏蔽词所在的第 66 个片段后中止上游（未返回用量）；保存的回复是 BlockedReplyContent
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', '` + service.BlockedReplyContent + `', 'mock-model', 'mock', 0, 66, \d+, TRUE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	response, events := helper.PerformStream(t, map[string]string{"content": "Hi"})
+
+	assert.Equal(t, consts.StatusOK, response.StatusCode())
+	body := string(response.Body())
+	assert.NotContains(t, body, "bob@example.com")
+	assert.NotContains(t, body, "shit")
+
+	// 屏蔽词之前的内容（个人信息已替换）已经发送
+	assert.Contains(t, streamedContent(t, events), "mail [EMAIL] now")
+
+	last := events[len(events)-1]
+	require.Equal(t, "error", last.Name)
+	var errResp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal([]byte(last.Data), &errResp))
+	assert.Equal(t, "CONTENT_BLOCKED", errResp.Error)
+	assert.Contains(t, helper.EventTypes(), "AlertTriggered")
+
+	helper.AssertExpectations(t)
+}
+
+// TestStreamMessage_ClientDisconnect 测试客户端断开：取消上游生成，已生成内容标记 truncated 后保存
+func TestStreamMessage_ClientDisconnect(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 每个片段 5 字节：第 26 个片段后审核缓冲区（128 字节）之前的内容才开始转发
+	helper.LLM.Enqueue(llmmock.Response{
+		Content: strings.Repeat("word ", 100),
+		Delay:   2 * time.Millisecond,
+	})
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'user'`).WillReturnResult(sqlmock.NewResult(1, 1))
+	// 第一次转发时写给客户端失败，上游未返回用量，输出 Token 按片段数估算
+	helper.Mock.ExpectExec(`INSERT INTO "messages" .+'assistant', '(word ){26}', 'mock-model', 'mock', 0, 26, \d+, TRUE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	// start 事件写入成功，第一个 delta 写入失败
+	conn := &flakyConn{Conn: mock.NewConn(""), failAfter: 1}
+	start := time.Now()
+	helper.ServeStream(conn, map[string]string{"content": "count"})
+
+	// 上游在第一次转发后即被取消，不会等待剩余片段
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+
+	var generation *sharedevents.GenerationCompletedPayload
+	for _, e := range helper.Events() {
+		if p, ok := e.Payload().(sharedevents.GenerationCompletedPayload); ok {
+			generation = &p
+		}
+	}
+	require.NotNil(t, generation)
+	assert.False(t, generation.Success)
+	assert.Contains(t, helper.EventTypes(), "MessageReceived")
+
+	helper.AssertExpectations(t)
+}
+
+// TestStreamMessage_Heartbeat 测试等待模型输出期间发送心跳注释
+func TestStreamMessage_Heartbeat(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	helper.HandlerDeps.WithHeartbeatInterval(5 * time.Millisecond)
+
+	helper.LLM.Enqueue(llmmock.Response{Content: "slow reply", Delay: 30 * time.Millisecond})
+
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "messages"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "messages"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`UPDATE "conversations"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	response, events := helper.PerformStream(t, map[string]string{"content": "Hi"})
+
+	assert.Contains(t, string(response.Body()), ": ping\n\n")
+	assert.Equal(t, "done", events[len(events)-1].Name)
+	helper.AssertExpectations(t)
+}
+
+// TestStreamMessage_GENERATION_FAILED 测试模型不可用时在开始输出前返回 JSON 错误，且不保存消息
+func TestStreamMessage_GENERATION_FAILED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.LLM.Enqueue(llmmock.Response{Err: errors.New("upstream unavailable")})
+	MockFindConversation(helper.Mock, CreateTestConversation("Hello"))
+	MockListRecent(helper.Mock)
+
+	w := helper.PerformRequest("POST", streamPath, map[string]string{"content": "Hi"})
+
+	assert.Equal(t, consts.StatusBadGateway, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "GENERATION_FAILED", resp.Error)
+	helper.AssertExpectations(t)
+}
+
+// TestStreamMessage_CONVERSATION_NOT_FOUND 测试对话不存在
+func TestStreamMessage_CONVERSATION_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "conversations"`).WillReturnError(sql.ErrNoRows)
+
+	w := helper.PerformRequest("POST", streamPath, map[string]string{"content": "Hi"})
+
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// MemoryStore 进程内响应缓存存储（测试和没有 Redis 的单实例开发环境）
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// memoryEntry 缓存条目
+type memoryEntry struct {
+	resp      model.ChatResponse
+	expiresAt time.Time
+}
+
+// NewMemoryStore 创建进程内响应缓存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Get 读取缓存的响应（未命中或已过期时返回 nil, nil）
+func (s *MemoryStore) Get(ctx context.Context, key string) (*model.ChatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	resp := entry.resp
+	resp.Message.ToolCalls = append([]model.ToolCall(nil), resp.Message.ToolCalls...)
+	return &resp, nil
+}
+
+// Set 写入响应（保存副本）
+func (s *MemoryStore) Set(ctx context.Context, key string, resp *model.ChatResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{resp: *resp, expiresAt: s.now().Add(ttl)}
+	return nil
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	resp, err := store.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+
+	require.NoError(t, store.Set(ctx, "k", &model.ChatResponse{Message: model.Message{Content: "hi"}}, time.Minute))
+	resp, err = store.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, "hi", resp.Message.Content)
+
+	// 过期
+	now = now.Add(time.Minute)
+	resp, err = store.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.Nil(t, resp)
+}
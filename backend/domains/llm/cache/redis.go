@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence/redis"
+)
+
+// RedisStore 基于 redis.Cache 的响应缓存存储（实现 service.ResponseCache）
+//
+// 响应以 JSON 存储，键由 service.CacheKey 计算（llm:cache:v1:<sha256>），
+// 过期由 Redis 的 TTL 处理；多个实例共享同一份缓存。
+type RedisStore struct {
+	cache *redis.Cache
+}
+
+// NewRedisStore 创建 Redis 响应缓存存储
+func NewRedisStore(cache *redis.Cache) *RedisStore {
+	return &RedisStore{cache: cache}
+}
+
+// Get 读取缓存的响应（未命中时返回 nil, nil）
+func (s *RedisStore) Get(ctx context.Context, key string) (*model.ChatResponse, error) {
+	var resp model.ChatResponse
+	if err := s.cache.Get(ctx, key, &resp); err != nil {
+		if errors.Is(err, redis.ErrCacheMiss) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Set 写入响应
+func (s *RedisStore) Set(ctx context.Context, key string, resp *model.ChatResponse, ttl time.Duration) error {
+	return s.cache.Set(ctx, key, resp, ttl)
+}
@@ -0,0 +1,151 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+)
+
+// DefaultHashDimensions 本地嵌入的默认向量维度
+const DefaultHashDimensions = 256
+
+// Embedder 文本向量嵌入
+//
+// 不同模型生成的向量不能比较，保存向量时需要同时保存 Model()。
+type Embedder interface {
+	// Embed 为每段文本生成向量（与 texts 一一对应）
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Model 模型标识（如 openai/text-embedding-3-small）
+	Model() string
+}
+
+// ============================================
+// HashEmbedder 本地确定性嵌入
+// ============================================
+
+// HashEmbedder 本地确定性嵌入（开发和测试）
+//
+// 每个词（中文按字）哈希到一个维度后归一化，共享词越多的文本余弦相似度越高。
+// 不理解语义（"dentist" 和 "teeth cleaning" 不相似），不调用模型、不产生费用。
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder 创建本地嵌入（dimensions <= 0 使用 DefaultHashDimensions）
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = DefaultHashDimensions
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Embed 为每段文本生成向量
+func (e *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+// Model 模型标识
+func (e *HashEmbedder) Model() string {
+	return fmt.Sprintf("local/hash-%d", e.dimensions)
+}
+
+// embed 生成单段文本的归一化向量（空文本为零向量）
+func (e *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, e.dimensions)
+	for _, token := range tokenize(text) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(token))
+		vector[h.Sum32()%uint32(e.dimensions)]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v * v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// tokenize 按字母和数字切分并转为小写，汉字单独成词
+func tokenize(text string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			tokens = append(tokens, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+// ============================================
+// ServiceEmbedder 模型提供商嵌入
+// ============================================
+
+// ServiceEmbedder 通过 LLMService 调用提供商的嵌入接口（如 OpenAI text-embedding-3-small）
+type ServiceEmbedder struct {
+	llmService *service.LLMService
+	provider   string
+	model      string
+}
+
+// NewServiceEmbedder 创建提供商嵌入
+//
+// 参数：
+//   - llmService: LLM 服务
+//   - provider: 提供商名称（为空时使用默认提供商）
+//   - model: 嵌入模型
+func NewServiceEmbedder(llmService *service.LLMService, provider, model string) *ServiceEmbedder {
+	return &ServiceEmbedder{llmService: llmService, provider: provider, model: model}
+}
+
+// Embed 为每段文本生成向量
+func (e *ServiceEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	resp, err := e.llmService.Embed(ctx, &model.EmbeddingRequest{
+		Provider: e.provider,
+		Model:    e.model,
+		Input:    texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Vectors) != len(texts) {
+		return nil, fmt.Errorf("embedding count mismatch: got %d, want %d", len(resp.Vectors), len(texts))
+	}
+	return resp.Vectors, nil
+}
+
+// Model 模型标识
+func (e *ServiceEmbedder) Model() string {
+	return e.provider + "/" + e.model
+}
@@ -0,0 +1,43 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashEmbedder(t *testing.T) {
+	e := NewHashEmbedder(0)
+	assert.Equal(t, "local/hash-256", e.Model())
+
+	vectors, err := e.Embed(context.Background(), []string{
+		"Book dentist appointment",
+		"book the DENTIST!",
+		"Pay electricity bill",
+		"预约牙医",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 5)
+	assert.Len(t, vectors[0], DefaultHashDimensions)
+
+	// 确定性
+	again, _ := e.Embed(context.Background(), []string{"Book dentist appointment"})
+	assert.Equal(t, vectors[0], again[0])
+
+	// 共享词越多越相似（大小写和标点不影响）
+	same := vectorstore.CosineSimilarity(vectors[0], vectors[1])
+	other := vectorstore.CosineSimilarity(vectors[0], vectors[2])
+	assert.Greater(t, same, other)
+	assert.InDelta(t, 1.0, vectorstore.CosineSimilarity(vectors[3], vectors[3]), 1e-6)
+
+	// 空文本为零向量
+	assert.Zero(t, vectorstore.CosineSimilarity(vectors[4], vectors[0]))
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"call", "mom", "at", "5pm", "给", "妈", "妈"}, tokenize("Call mom, at 5pm 给妈妈"))
+}
@@ -0,0 +1,118 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+)
+
+// CheckResult 一项检查的结果
+type CheckResult struct {
+	Type   CheckType `json:"type"`
+	Passed bool      `json:"passed"`
+	Score  float64   `json:"score"`            // 0-1（评审检查按 1-5 分换算，其他检查通过为 1）
+	Detail string    `json:"detail,omitempty"` // 未通过的原因或评审意见
+}
+
+// run 执行检查（input 为发送给模型的最后一条用户消息，评审时使用）
+func (c *Check) run(ctx context.Context, judge *Judge, input, output string) CheckResult {
+	result := CheckResult{Type: c.Type}
+	switch c.Type {
+	case CheckExact:
+		actual, expected := strings.TrimSpace(output), strings.TrimSpace(c.Expected)
+		result.Passed = actual == expected || (c.IgnoreCase && strings.EqualFold(actual, expected))
+		if !result.Passed {
+			result.Detail = fmt.Sprintf("expected %q", truncate(expected, 200))
+		}
+	case CheckRegex:
+		result.Passed = c.re.MatchString(output) != c.Negate
+		if !result.Passed && c.Negate {
+			result.Detail = fmt.Sprintf("matched %q", c.re.FindString(output))
+		} else if !result.Passed {
+			result.Detail = fmt.Sprintf("no match for %s", c.Pattern)
+		}
+	case CheckJSONSchema:
+		data := []byte(llmservice.ExtractJSON(output))
+		if !json.Valid(data) {
+			result.Detail = "output is not valid JSON"
+		} else if errs := c.schema.ValidateJSON(data); len(errs) > 0 {
+			result.Detail = errs.Error()
+		} else {
+			result.Passed = true
+		}
+	case CheckJudge:
+		return judge.grade(ctx, c, input, output)
+	}
+	if result.Passed {
+		result.Score = 1
+	}
+	return result
+}
+
+// Judge 评审模型（LLM-as-judge）
+type Judge struct {
+	Provider provider.Provider
+	Model    string
+}
+
+// judgeVerdict 评审模型的输出
+type judgeVerdict struct {
+	Score  int    `json:"score" jsonschema:"minimum=1,maximum=5"`
+	Reason string `json:"reason"`
+}
+
+var judgeSchema = schema.MustFor[judgeVerdict]()
+
+// judgeInstructions 评审的系统提示
+const judgeInstructions = `你是严格的评审，根据评分标准评价 AI 助手对用户输入的回复。
+按 1 到 5 打分：5 完全满足标准，4 基本满足、只有细微问题，3 部分满足，2 大部分不满足，1 完全不满足或答非所问。
+只输出 JSON：{"score": 分数, "reason": "一句话理由"}。`
+
+// grade 请评审模型打分（评审失败时检查不通过，Detail 为错误）
+func (j *Judge) grade(ctx context.Context, c *Check, input, output string) CheckResult {
+	result := CheckResult{Type: CheckJudge}
+	if j == nil || j.Provider == nil {
+		result.Detail = "no judge model configured"
+		return result
+	}
+
+	temperature := 0.0
+	resp, err := j.Provider.Chat(ctx, &model.ChatRequest{
+		Provider: j.Provider.Name(),
+		Model:    j.Model,
+		Messages: []model.Message{
+			{Role: model.RoleSystem, Content: judgeInstructions},
+			{Role: model.RoleUser, Content: fmt.Sprintf("评分标准：\n%s\n\n用户输入：\n%s\n\n助手回复：\n%s", c.Criteria, input, output)},
+		},
+		Temperature:    &temperature,
+		ResponseFormat: &model.ResponseFormat{Type: "json_schema", Name: "verdict", Schema: judgeSchema.JSON()},
+	})
+	if err != nil {
+		result.Detail = "judge failed: " + err.Error()
+		return result
+	}
+
+	var verdict judgeVerdict
+	data := []byte(llmservice.ExtractJSON(resp.Message.Content))
+	if err := json.Unmarshal(data, &verdict); err != nil || verdict.Score < 1 || verdict.Score > 5 {
+		result.Detail = "judge returned an invalid verdict: " + truncate(resp.Message.Content, 200)
+		return result
+	}
+	result.Score = float64(verdict.Score-1) / 4
+	result.Passed = verdict.Score >= c.MinScore
+	result.Detail = fmt.Sprintf("%d/5: %s", verdict.Score, verdict.Reason)
+	return result
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "..."
+	}
+	return s
+}
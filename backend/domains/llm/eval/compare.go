@@ -0,0 +1,104 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+)
+
+// DefaultTolerance 默认允许的分数下降（吸收评审打分的随机波动）
+const DefaultTolerance = 0.05
+
+// Comparison 两次运行的比较结果（base 为基线，head 为新的运行）
+type Comparison struct {
+	Base          *Report
+	Head          *Report
+	Tolerance     float64
+	PassRateDelta float64      // head - base
+	ScoreDelta    float64      // head - base
+	Regressions   []CaseChange // 变差的用例
+	Improvements  []CaseChange // 变好的用例
+	Missing       []string     // 基线中有、新的运行中没有的用例
+	Added         []string     // 新的运行中新增的用例
+}
+
+// CaseChange 一个用例在两次运行之间的变化
+type CaseChange struct {
+	ID         string
+	BasePassed bool
+	HeadPassed bool
+	BaseScore  float64
+	HeadScore  float64
+}
+
+// Compare 按用例 ID 比较两次运行
+//
+// 用例从通过变为未通过，或分数下降超过 tolerance，视为变差；反之视为变好。
+// 只比较两次运行都有的用例，数据集的增删单独列出。
+func Compare(base, head *Report, tolerance float64) *Comparison {
+	c := &Comparison{
+		Base:          base,
+		Head:          head,
+		Tolerance:     tolerance,
+		PassRateDelta: head.Summary.PassRate - base.Summary.PassRate,
+		ScoreDelta:    head.Summary.Score - base.Summary.Score,
+	}
+	for _, b := range base.Cases {
+		h, ok := head.Case(b.ID)
+		if !ok {
+			c.Missing = append(c.Missing, b.ID)
+			continue
+		}
+		change := CaseChange{ID: b.ID, BasePassed: b.Passed, HeadPassed: h.Passed, BaseScore: b.Score, HeadScore: h.Score}
+		switch {
+		case b.Passed && !h.Passed, h.Score < b.Score-tolerance:
+			c.Regressions = append(c.Regressions, change)
+		case !b.Passed && h.Passed, h.Score > b.Score+tolerance:
+			c.Improvements = append(c.Improvements, change)
+		}
+	}
+	for _, h := range head.Cases {
+		if _, ok := base.Case(h.ID); !ok {
+			c.Added = append(c.Added, h.ID)
+		}
+	}
+	return c
+}
+
+// Regressed 是否有用例变差，或总分下降超过 tolerance
+func (c *Comparison) Regressed() bool {
+	return len(c.Regressions) > 0 || c.ScoreDelta < -c.Tolerance
+}
+
+// WriteText 输出可读的比较结果
+func (c *Comparison) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Base: %s (%s)\n", c.Base.Label(), c.Base.StartedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "Head: %s (%s)\n", c.Head.Label(), c.Head.StartedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "Pass rate %.1f%% -> %.1f%% (%+.1f), score %.3f -> %.3f (%+.3f)\n",
+		c.Base.Summary.PassRate*100, c.Head.Summary.PassRate*100, c.PassRateDelta*100,
+		c.Base.Summary.Score, c.Head.Summary.Score, c.ScoreDelta)
+
+	for _, r := range c.Regressions {
+		fmt.Fprintf(w, "REGRESSED %s: %s -> %s\n", r.ID, status(r.BasePassed, r.BaseScore), status(r.HeadPassed, r.HeadScore))
+	}
+	for _, r := range c.Improvements {
+		fmt.Fprintf(w, "IMPROVED  %s: %s -> %s\n", r.ID, status(r.BasePassed, r.BaseScore), status(r.HeadPassed, r.HeadScore))
+	}
+	for _, id := range c.Missing {
+		fmt.Fprintf(w, "MISSING   %s\n", id)
+	}
+	for _, id := range c.Added {
+		fmt.Fprintf(w, "ADDED     %s\n", id)
+	}
+	if c.Regressed() {
+		fmt.Fprintf(w, "Result: regressed (%d cases)\n", len(c.Regressions))
+	} else {
+		fmt.Fprintln(w, "Result: no regressions")
+	}
+}
+
+func status(passed bool, score float64) string {
+	if passed {
+		return fmt.Sprintf("pass %.3f", score)
+	}
+	return fmt.Sprintf("fail %.3f", score)
+}
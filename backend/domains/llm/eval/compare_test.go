@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func report(cases ...CaseResult) *Report {
+	r := &Report{Dataset: "qa", Provider: "mock", Model: "m", Cases: cases}
+	r.summarize()
+	return r
+}
+
+func TestCompare(t *testing.T) {
+	base := report(
+		CaseResult{ID: "a", Passed: true, Score: 1},
+		CaseResult{ID: "b", Passed: true, Score: 1},
+		CaseResult{ID: "c", Passed: false, Score: 0.5},
+		CaseResult{ID: "d", Passed: true, Score: 0.75},
+		CaseResult{ID: "gone", Passed: true, Score: 1},
+	)
+	head := report(
+		CaseResult{ID: "a", Passed: true, Score: 1},
+		CaseResult{ID: "b", Passed: false, Score: 0},
+		CaseResult{ID: "c", Passed: true, Score: 1},
+		CaseResult{ID: "d", Passed: true, Score: 0.5},
+		CaseResult{ID: "new", Passed: true, Score: 1},
+	)
+
+	c := Compare(base, head, DefaultTolerance)
+
+	require.Len(t, c.Regressions, 2)
+	assert.Equal(t, "b", c.Regressions[0].ID)
+	assert.Equal(t, "d", c.Regressions[1].ID) // 通过但分数下降
+	require.Len(t, c.Improvements, 1)
+	assert.Equal(t, "c", c.Improvements[0].ID)
+	assert.Equal(t, []string{"gone"}, c.Missing)
+	assert.Equal(t, []string{"new"}, c.Added)
+	assert.True(t, c.Regressed())
+
+	var out bytes.Buffer
+	c.WriteText(&out)
+	assert.Contains(t, out.String(), "REGRESSED b: pass 1.000 -> fail 0.000")
+	assert.Contains(t, out.String(), "Result: regressed (2 cases)")
+}
+
+func TestCompare_WithinTolerance(t *testing.T) {
+	base := report(CaseResult{ID: "a", Passed: true, Score: 0.75})
+	head := report(CaseResult{ID: "a", Passed: true, Score: 0.72})
+
+	c := Compare(base, head, DefaultTolerance)
+
+	assert.Empty(t, c.Regressions)
+	assert.False(t, c.Regressed())
+	assert.True(t, Compare(base, head, 0).Regressed())
+}
+
+func TestReport_SaveLoad(t *testing.T) {
+	r := report(CaseResult{ID: "a", Passed: true, Score: 1, Checks: []CheckResult{{Type: CheckRegex, Passed: true, Score: 1}}})
+	path := filepath.Join(t.TempDir(), "runs", "base.json")
+
+	require.NoError(t, r.Save(path))
+	loaded, err := LoadReport(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, r.Summary, loaded.Summary)
+	assert.Equal(t, r.Cases, loaded.Cases)
+}
@@ -0,0 +1,205 @@
+// Package eval 离线评估提示词和模型
+//
+// 数据集（YAML）列出输入和期望的输出性质，Runner 通过 Provider 接口逐个调用模型并检查输出，
+// 生成带分数的报告；Compare 比较两次运行（例如修改提示词或切换模型前后），找出变差的用例。
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// CheckType 检查类型
+type CheckType string
+
+const (
+	CheckExact      CheckType = "exact"       // 输出（去掉首尾空白）与 expected 完全相同
+	CheckJSONSchema CheckType = "json_schema" // 输出是符合 schema 的 JSON（允许 ```json 代码块）
+	CheckRegex      CheckType = "regex"       // 输出匹配 pattern（negate 时不匹配）
+	CheckJudge      CheckType = "judge"       // 评审模型按 criteria 打分（1-5），不低于 min_score 通过
+)
+
+// DefaultJudgeMinScore 评审检查默认的及格分
+const DefaultJudgeMinScore = 4
+
+// Dataset 评估数据集
+//
+//	name: task-breakdown
+//	prompt: task.breakdown            # 提示词名称（为空时每个用例的 input 作为用户消息）
+//	response_schema: {type: object}   # 可选：以 json_schema 响应格式请求（与线上的结构化输出一致）
+//	checks:                           # 每个用例都执行的检查
+//	  - type: json_schema
+//	    schema: {type: object, required: [subtasks]}
+//	cases:
+//	  - id: launch
+//	    variables: {title: 发布新版本, today: "2025-01-01"}
+//	    checks:
+//	      - type: judge
+//	        criteria: 子任务覆盖测试、发布和回滚
+type Dataset struct {
+	Name           string  `yaml:"name"`
+	Description    string  `yaml:"description"`
+	Prompt         string  `yaml:"prompt"`
+	ResponseSchema any     `yaml:"response_schema"`
+	Checks         []Check `yaml:"checks"`
+	Cases          []Case  `yaml:"cases"`
+
+	responseSchema *schema.Schema
+}
+
+// Case 一个评估用例
+type Case struct {
+	ID        string            `yaml:"id"`
+	Variables map[string]string `yaml:"variables"` // 提示词变量
+	Input     string            `yaml:"input"`     // 数据集没有提示词时作为用户消息
+	Checks    []Check           `yaml:"checks"`    // 在数据集的检查之后执行
+}
+
+// Check 期望的输出性质
+type Check struct {
+	Type       CheckType `yaml:"type"`
+	Expected   string    `yaml:"expected"`    // exact
+	IgnoreCase bool      `yaml:"ignore_case"` // exact
+	Pattern    string    `yaml:"pattern"`     // regex
+	Negate     bool      `yaml:"negate"`      // regex：输出不应匹配
+	Schema     any       `yaml:"schema"`      // json_schema：YAML 或 JSON 写法的 JSON Schema
+	Criteria   string    `yaml:"criteria"`    // judge：评分标准
+	MinScore   int       `yaml:"min_score"`   // judge：及格分（1-5，默认 DefaultJudgeMinScore）
+
+	re     *regexp.Regexp
+	schema *schema.Schema
+}
+
+// LoadDataset 读取并验证数据集文件
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := ParseDataset(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ds, nil
+}
+
+// ParseDataset 解析并验证数据集（编译正则和 Schema）
+func ParseDataset(data []byte) (*Dataset, error) {
+	var ds Dataset
+	if err := yaml.Unmarshal(data, &ds); err != nil {
+		return nil, fmt.Errorf("invalid dataset: %w", err)
+	}
+	if ds.Name == "" {
+		return nil, fmt.Errorf("invalid dataset: name is required")
+	}
+	if len(ds.Cases) == 0 {
+		return nil, fmt.Errorf("invalid dataset: no cases")
+	}
+	if ds.ResponseSchema != nil {
+		s, err := compileSchema(ds.ResponseSchema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dataset: response_schema: %w", err)
+		}
+		ds.responseSchema = s
+	}
+	for i := range ds.Checks {
+		if err := ds.Checks[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid dataset: checks[%d]: %w", i, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(ds.Cases))
+	for i := range ds.Cases {
+		c := &ds.Cases[i]
+		if c.ID == "" {
+			return nil, fmt.Errorf("invalid dataset: cases[%d]: id is required", i)
+		}
+		if seen[c.ID] {
+			return nil, fmt.Errorf("invalid dataset: duplicate case id %q", c.ID)
+		}
+		seen[c.ID] = true
+		if ds.Prompt == "" && c.Input == "" {
+			return nil, fmt.Errorf("invalid dataset: case %q: input is required when the dataset has no prompt", c.ID)
+		}
+		if len(ds.Checks)+len(c.Checks) == 0 {
+			return nil, fmt.Errorf("invalid dataset: case %q has no checks", c.ID)
+		}
+		for j := range c.Checks {
+			if err := c.Checks[j].compile(); err != nil {
+				return nil, fmt.Errorf("invalid dataset: case %q: checks[%d]: %w", c.ID, j, err)
+			}
+		}
+	}
+	return &ds, nil
+}
+
+// checks 用例执行的所有检查
+func (ds *Dataset) checks(c Case) []Check {
+	checks := make([]Check, 0, len(ds.Checks)+len(c.Checks))
+	checks = append(checks, ds.Checks...)
+	return append(checks, c.Checks...)
+}
+
+// NeedsJudge 数据集是否包含评审检查
+func (ds *Dataset) NeedsJudge() bool {
+	for _, c := range ds.Cases {
+		for _, check := range ds.checks(c) {
+			if check.Type == CheckJudge {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compile 验证检查的参数
+func (c *Check) compile() error {
+	switch c.Type {
+	case CheckExact:
+		if c.Expected == "" {
+			return fmt.Errorf("exact: expected is required")
+		}
+	case CheckRegex:
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil || c.Pattern == "" {
+			return fmt.Errorf("regex: invalid pattern %q", c.Pattern)
+		}
+		c.re = re
+	case CheckJSONSchema:
+		if c.Schema == nil {
+			return fmt.Errorf("json_schema: schema is required")
+		}
+		s, err := compileSchema(c.Schema)
+		if err != nil {
+			return fmt.Errorf("json_schema: %w", err)
+		}
+		c.schema = s
+	case CheckJudge:
+		if c.Criteria == "" {
+			return fmt.Errorf("judge: criteria is required")
+		}
+		if c.MinScore == 0 {
+			c.MinScore = DefaultJudgeMinScore
+		}
+		if c.MinScore < 1 || c.MinScore > 5 {
+			return fmt.Errorf("judge: min_score must be between 1 and 5")
+		}
+	default:
+		return fmt.Errorf("unknown check type %q (exact, json_schema, regex, judge)", c.Type)
+	}
+	return nil
+}
+
+// compileSchema 解析 YAML 中的 JSON Schema
+func compileSchema(v any) (*schema.Schema, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Parse(data)
+}
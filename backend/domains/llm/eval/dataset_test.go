@@ -0,0 +1,63 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDataset(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: sample
+prompt: task.breakdown
+checks:
+  - type: json_schema
+    schema: {type: object, required: [subtasks]}
+cases:
+  - id: a
+    variables: {title: 发布}
+    checks:
+      - type: judge
+        criteria: 覆盖发布步骤
+  - id: b
+`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "sample", ds.Name)
+	require.Len(t, ds.Cases, 2)
+	assert.Equal(t, map[string]string{"title": "发布"}, ds.Cases[0].Variables)
+	assert.Equal(t, DefaultJudgeMinScore, ds.Cases[0].Checks[0].MinScore)
+
+	checks := ds.checks(ds.Cases[0])
+	require.Len(t, checks, 2)
+	assert.Equal(t, CheckJSONSchema, checks[0].Type)
+	assert.Equal(t, CheckJudge, checks[1].Type)
+	assert.Len(t, ds.checks(ds.Cases[1]), 1)
+	assert.True(t, ds.NeedsJudge())
+}
+
+func TestParseDataset_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"缺少名称", "cases: [{id: a, input: x, checks: [{type: regex, pattern: x}]}]", "name is required"},
+		{"没有用例", "name: x", "no cases"},
+		{"重复的用例", "name: x\ncases: [{id: a, input: x, checks: [{type: regex, pattern: x}]}, {id: a, input: y}]", `duplicate case id "a"`},
+		{"没有提示词时缺少输入", "name: x\ncases: [{id: a, checks: [{type: regex, pattern: x}]}]", "input is required"},
+		{"没有检查", "name: x\ncases: [{id: a, input: x}]", "has no checks"},
+		{"无效的正则", "name: x\ncases: [{id: a, input: x, checks: [{type: regex, pattern: '('}]}]", "invalid pattern"},
+		{"未知的检查类型", "name: x\ncases: [{id: a, input: x, checks: [{type: bleu}]}]", `unknown check type "bleu"`},
+		{"及格分超出范围", "name: x\ncases: [{id: a, input: x, checks: [{type: judge, criteria: c, min_score: 6}]}]", "min_score"},
+		{"缺少 schema", "name: x\ncases: [{id: a, input: x, checks: [{type: json_schema}]}]", "schema is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDataset([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report 一次评估运行的报告（JSON 保存，用于之后比较）
+type Report struct {
+	Dataset       string       `json:"dataset"`
+	Prompt        string       `json:"prompt,omitempty"`
+	PromptVersion string       `json:"prompt_version,omitempty"`
+	Provider      string       `json:"provider"`
+	Model         string       `json:"model"`
+	JudgeModel    string       `json:"judge_model,omitempty"`
+	StartedAt     time.Time    `json:"started_at"`
+	Duration      int64        `json:"duration_ms"`
+	Summary       Summary      `json:"summary"`
+	Cases         []CaseResult `json:"cases"`
+}
+
+// CaseResult 一个用例的结果
+type CaseResult struct {
+	ID           string        `json:"id"`
+	Passed       bool          `json:"passed"` // 所有检查都通过
+	Score        float64       `json:"score"`  // 各项检查分数的平均值（调用失败为 0）
+	Output       string        `json:"output,omitempty"`
+	Error        string        `json:"error,omitempty"` // 调用模型失败
+	Checks       []CheckResult `json:"checks,omitempty"`
+	LatencyMs    int64         `json:"latency_ms"`
+	InputTokens  int           `json:"input_tokens"`
+	OutputTokens int           `json:"output_tokens"`
+}
+
+// Summary 报告汇总
+type Summary struct {
+	Total        int                        `json:"total"`
+	Passed       int                        `json:"passed"`
+	Errors       int                        `json:"errors"`    // 调用模型失败的用例数
+	PassRate     float64                    `json:"pass_rate"` // 0-1
+	Score        float64                    `json:"score"`     // 用例分数的平均值（0-1）
+	Checks       map[CheckType]CheckSummary `json:"checks"`    // 按检查类型
+	InputTokens  int                        `json:"input_tokens"`
+	OutputTokens int                        `json:"output_tokens"`
+}
+
+// CheckSummary 某类检查的通过情况
+type CheckSummary struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+}
+
+// summarize 计算汇总
+func (r *Report) summarize() {
+	s := Summary{Total: len(r.Cases), Checks: make(map[CheckType]CheckSummary)}
+	for _, c := range r.Cases {
+		if c.Passed {
+			s.Passed++
+		}
+		if c.Error != "" {
+			s.Errors++
+		}
+		s.Score += c.Score
+		s.InputTokens += c.InputTokens
+		s.OutputTokens += c.OutputTokens
+		for _, check := range c.Checks {
+			cs := s.Checks[check.Type]
+			cs.Total++
+			if check.Passed {
+				cs.Passed++
+			}
+			s.Checks[check.Type] = cs
+		}
+	}
+	if s.Total > 0 {
+		s.PassRate = float64(s.Passed) / float64(s.Total)
+		s.Score /= float64(s.Total)
+	}
+	r.Summary = s
+}
+
+// Case 按 ID 查找用例结果
+func (r *Report) Case(id string) (CaseResult, bool) {
+	for _, c := range r.Cases {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return CaseResult{}, false
+}
+
+// Save 写入 JSON 报告（自动创建目录）
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadReport 读取 JSON 报告
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: invalid report: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Label 报告的简短描述（"提示词@版本 提供商/模型"）
+func (r *Report) Label() string {
+	label := r.Provider + "/" + r.Model
+	if r.Prompt != "" {
+		label = r.Prompt + "@" + r.PromptVersion + " " + label
+	}
+	return label
+}
+
+// WriteText 输出可读的报告（汇总和未通过的用例）
+func (r *Report) WriteText(w io.Writer) {
+	s := r.Summary
+	fmt.Fprintf(w, "Dataset %s: %s\n", r.Dataset, r.Label())
+	if r.JudgeModel != "" {
+		fmt.Fprintf(w, "Judge: %s\n", r.JudgeModel)
+	}
+	fmt.Fprintf(w, "Passed %d/%d (%.1f%%), score %.3f, errors %d, tokens %d in / %d out, %s\n",
+		s.Passed, s.Total, s.PassRate*100, s.Score, s.Errors, s.InputTokens, s.OutputTokens,
+		(time.Duration(r.Duration) * time.Millisecond).String())
+
+	types := make([]string, 0, len(s.Checks))
+	for t := range s.Checks {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		cs := s.Checks[CheckType(t)]
+		fmt.Fprintf(w, "  %-12s %d/%d\n", t, cs.Passed, cs.Total)
+	}
+
+	for _, c := range r.Cases {
+		if c.Passed {
+			continue
+		}
+		fmt.Fprintf(w, "FAIL %s (score %.3f)\n", c.ID, c.Score)
+		if c.Error != "" {
+			fmt.Fprintf(w, "  error: %s\n", c.Error)
+			continue
+		}
+		for _, check := range c.Checks {
+			if !check.Passed {
+				fmt.Fprintf(w, "  %s: %s\n", check.Type, oneLine(check.Detail))
+			}
+		}
+		fmt.Fprintf(w, "  output: %s\n", oneLine(truncate(c.Output, 300)))
+	}
+}
+
+// oneLine 把多行文本合并为一行
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,166 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+)
+
+// DefaultConcurrency 默认同时运行的用例数
+const DefaultConcurrency = 4
+
+// Renderer 渲染提示词（由调用方适配提示词注册表，version 为空时使用生效版本）
+//
+// 返回的请求带有模板的消息和生成参数，以及实际使用的版本。
+type Renderer interface {
+	Render(ctx context.Context, name, version string, vars map[string]string) (*model.ChatRequest, string, error)
+}
+
+// Runner 评估运行器
+//
+// 直接调用 Provider（不经过 LLMService 的缓存、额度和回退），保证评估的是指定的模型。
+type Runner struct {
+	provider    provider.Provider
+	model       string
+	renderer    Renderer
+	judge       *Judge
+	concurrency int
+}
+
+// NewRunner 创建评估运行器
+//
+// model 为空时使用提示词模板指定的模型；renderer 可以为 nil（只能运行没有提示词的数据集）。
+func NewRunner(p provider.Provider, model string, renderer Renderer) *Runner {
+	return &Runner{provider: p, model: model, renderer: renderer, concurrency: DefaultConcurrency}
+}
+
+// WithJudge 设置评审模型（数据集包含 judge 检查时需要）
+func (r *Runner) WithJudge(j *Judge) *Runner {
+	r.judge = j
+	return r
+}
+
+// WithConcurrency 设置同时运行的用例数（<= 0 时为 1）
+func (r *Runner) WithConcurrency(n int) *Runner {
+	if n <= 0 {
+		n = 1
+	}
+	r.concurrency = n
+	return r
+}
+
+// Run 以提示词的指定版本（为空时为生效版本）运行数据集
+//
+// 单个用例调用失败记录在用例结果中（计为未通过），不中断运行；
+// 提示词不存在、版本不存在等所有用例都会失败的错误直接返回。
+func (r *Runner) Run(ctx context.Context, ds *Dataset, promptVersion string) (*Report, error) {
+	report := &Report{
+		Dataset:   ds.Name,
+		Prompt:    ds.Prompt,
+		Provider:  r.provider.Name(),
+		Model:     r.model,
+		StartedAt: time.Now().UTC(),
+		Cases:     make([]CaseResult, len(ds.Cases)),
+	}
+	if r.judge != nil {
+		report.JudgeModel = r.judge.Model
+	}
+
+	// 先渲染所有用例，提示词和版本的错误在调用模型之前返回
+	requests := make([]*model.ChatRequest, len(ds.Cases))
+	for i, c := range ds.Cases {
+		req, version, err := r.request(ctx, ds, c, promptVersion)
+		if err != nil {
+			return nil, fmt.Errorf("case %q: %w", c.ID, err)
+		}
+		requests[i] = req
+		report.PromptVersion = version
+		if report.Model == "" {
+			report.Model = req.Model
+		}
+	}
+
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, c := range ds.Cases {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			report.Cases[i] = r.runCase(ctx, c, ds.checks(c), requests[i])
+		}()
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(report.StartedAt).Milliseconds()
+	report.summarize()
+	return report, ctx.Err()
+}
+
+// request 构造用例的请求
+func (r *Runner) request(ctx context.Context, ds *Dataset, c Case, promptVersion string) (*model.ChatRequest, string, error) {
+	req := &model.ChatRequest{Messages: []model.Message{{Role: model.RoleUser, Content: c.Input}}}
+	version := ""
+	if ds.Prompt != "" {
+		if r.renderer == nil {
+			return nil, "", fmt.Errorf("dataset uses prompt %q but no renderer is configured", ds.Prompt)
+		}
+		rendered, v, err := r.renderer.Render(ctx, ds.Prompt, promptVersion, c.Variables)
+		if err != nil {
+			return nil, "", err
+		}
+		req, version = rendered, v
+	}
+
+	req.Provider = r.provider.Name()
+	if ds.responseSchema != nil {
+		req.ResponseFormat = &model.ResponseFormat{Type: "json_schema", Name: ds.Name, Schema: ds.responseSchema.JSON()}
+	}
+	if r.model != "" {
+		req.Model = r.model
+	}
+	if req.Model == "" {
+		return nil, "", fmt.Errorf("no model: set one for the run or in the prompt template")
+	}
+	return req, version, nil
+}
+
+// runCase 调用模型并执行检查
+func (r *Runner) runCase(ctx context.Context, c Case, checks []Check, req *model.ChatRequest) CaseResult {
+	result := CaseResult{ID: c.ID}
+
+	start := time.Now()
+	resp, err := r.provider.Chat(ctx, req)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Output = resp.Message.Content
+	result.InputTokens = resp.Usage.InputTokens
+	result.OutputTokens = resp.Usage.OutputTokens
+
+	input := lastUserMessage(req.Messages)
+	result.Passed = true
+	for i := range checks {
+		check := checks[i].run(ctx, r.judge, input, result.Output)
+		result.Checks = append(result.Checks, check)
+		result.Score += check.Score
+		result.Passed = result.Passed && check.Passed
+	}
+	result.Score /= float64(len(checks))
+	return result
+}
+
+func lastUserMessage(messages []model.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == model.RoleUser {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRenderer 把变量 topic 渲染为用户消息
+type stubRenderer struct {
+	versions []string
+}
+
+func (r *stubRenderer) Render(_ context.Context, name, version string, vars map[string]string) (*model.ChatRequest, string, error) {
+	if name != "qa" {
+		return nil, "", errors.New("PROMPT_NOT_FOUND: " + name)
+	}
+	if version == "" {
+		version = "v1"
+	}
+	r.versions = append(r.versions, version)
+	return &model.ChatRequest{
+		Model:    "template-model",
+		Messages: []model.Message{{Role: model.RoleSystem, Content: "answer briefly"}, {Role: model.RoleUser, Content: vars["topic"]}},
+	}, version, nil
+}
+
+// answers 按最后一条用户消息返回固定回复（用例并发运行，不能依赖调用顺序）
+func answers(replies map[string]mock.Response) mock.Handler {
+	return func(req *model.ChatRequest) mock.Response {
+		return replies[lastUserMessage(req.Messages)]
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: qa
+prompt: qa
+cases:
+  - id: capital
+    variables: {topic: capital}
+    checks: [{type: exact, expected: paris, ignore_case: true}]
+  - id: json
+    variables: {topic: json}
+    checks: [{type: json_schema, schema: {type: object, required: [n], properties: {n: {type: integer}}}}]
+  - id: polite
+    variables: {topic: polite}
+    checks: [{type: regex, pattern: '(?i)sorry', negate: true}]
+  - id: broken
+    variables: {topic: broken}
+    checks: [{type: regex, pattern: x}]
+`))
+	require.NoError(t, err)
+
+	p := mock.New()
+	p.SetHandler(answers(map[string]mock.Response{
+		"capital": {Content: " Paris\n"},
+		"json":    {Content: "```json\n{\"n\": 3}\n```"},
+		"polite":  {Content: "Sorry, I cannot help."},
+		"broken":  {Err: errors.New("upstream unavailable")},
+	}))
+	renderer := &stubRenderer{}
+
+	report, err := NewRunner(p, "", renderer).WithConcurrency(2).Run(context.Background(), ds, "v2")
+
+	require.NoError(t, err)
+	assert.Equal(t, "qa", report.Prompt)
+	assert.Equal(t, "v2", report.PromptVersion)
+	assert.Equal(t, "template-model", report.Model)
+	assert.Equal(t, []string{"v2", "v2", "v2", "v2"}, renderer.versions)
+	for _, req := range p.Requests() {
+		assert.Equal(t, mock.Name, req.Provider)
+		assert.Equal(t, "template-model", req.Model)
+	}
+
+	require.Len(t, report.Cases, 4)
+	assert.True(t, report.Cases[0].Passed)
+	assert.True(t, report.Cases[1].Passed)
+	assert.False(t, report.Cases[2].Passed)
+	assert.Contains(t, report.Cases[2].Checks[0].Detail, "Sorry")
+	assert.False(t, report.Cases[3].Passed)
+	assert.Equal(t, "upstream unavailable", report.Cases[3].Error)
+
+	s := report.Summary
+	assert.Equal(t, 4, s.Total)
+	assert.Equal(t, 2, s.Passed)
+	assert.Equal(t, 1, s.Errors)
+	assert.InDelta(t, 0.5, s.PassRate, 1e-9)
+	assert.InDelta(t, 0.5, s.Score, 1e-9)
+	assert.Equal(t, CheckSummary{Total: 1, Passed: 0}, s.Checks[CheckRegex])
+	assert.Positive(t, s.InputTokens)
+}
+
+func TestRunner_Run_Judge(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: judged
+cases:
+  - id: good
+    input: explain retries
+    checks: [{type: judge, criteria: mentions backoff}]
+  - id: weak
+    input: explain breakers
+    checks: [{type: judge, criteria: mentions half-open, min_score: 3}]
+`))
+	require.NoError(t, err)
+
+	judge := mock.NewNamed("judge")
+	judge.SetHandler(func(req *model.ChatRequest) mock.Response {
+		if strings.Contains(lastUserMessage(req.Messages), "backoff") {
+			return mock.Response{Content: `{"score": 5, "reason": "covers backoff"}`}
+		}
+		return mock.Response{Content: `{"score": 2, "reason": "misses half-open"}`}
+	})
+
+	report, err := NewRunner(mock.New(), "gpt-4o-mini", nil).
+		WithJudge(&Judge{Provider: judge, Model: "gpt-4o"}).
+		Run(context.Background(), ds, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", report.JudgeModel)
+	assert.True(t, report.Cases[0].Passed)
+	assert.Equal(t, 1.0, report.Cases[0].Score)
+	assert.False(t, report.Cases[1].Passed)
+	assert.Equal(t, 0.25, report.Cases[1].Score)
+	assert.Equal(t, "2/5: misses half-open", report.Cases[1].Checks[0].Detail)
+
+	for _, req := range judge.Requests() {
+		assert.Equal(t, "gpt-4o", req.Model)
+		require.NotNil(t, req.ResponseFormat)
+		assert.Equal(t, 0.0, *req.Temperature)
+	}
+}
+
+func TestRunner_Run_JudgeMissing(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: judged
+cases: [{id: a, input: hi, checks: [{type: judge, criteria: friendly}]}]
+`))
+	require.NoError(t, err)
+
+	report, err := NewRunner(mock.New(), "gpt-4o-mini", nil).Run(context.Background(), ds, "")
+
+	require.NoError(t, err)
+	assert.False(t, report.Cases[0].Passed)
+	assert.Equal(t, "no judge model configured", report.Cases[0].Checks[0].Detail)
+}
+
+func TestRunner_Run_RenderError(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: missing
+prompt: unknown
+cases: [{id: a, checks: [{type: regex, pattern: x}]}]
+`))
+	require.NoError(t, err)
+	p := mock.New()
+
+	_, err = NewRunner(p, "gpt-4o-mini", &stubRenderer{}).Run(context.Background(), ds, "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PROMPT_NOT_FOUND")
+	assert.Empty(t, p.Requests())
+
+	_, err = NewRunner(p, "gpt-4o-mini", nil).Run(context.Background(), ds, "")
+	assert.ErrorContains(t, err, "no renderer")
+}
+
+func TestRunner_Run_ResponseSchema(t *testing.T) {
+	ds, err := ParseDataset([]byte(`
+name: structured
+response_schema: &out {type: object, required: [answer]}
+checks: [{type: json_schema, schema: *out}]
+cases: [{id: a, input: hi}]
+`))
+	require.NoError(t, err)
+	p := mock.New()
+	p.Enqueue(mock.Response{Content: `{"answer": "hello"}`})
+
+	report, err := NewRunner(p, "gpt-4o-mini", nil).Run(context.Background(), ds, "")
+
+	require.NoError(t, err)
+	assert.True(t, report.Cases[0].Passed)
+	format := p.Requests()[0].ResponseFormat
+	require.NotNil(t, format)
+	assert.Equal(t, "json_schema", format.Type)
+	assert.JSONEq(t, `{"type":"object","required":["answer"]}`, string(format.Schema))
+}
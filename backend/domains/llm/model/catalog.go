@@ -0,0 +1,60 @@
+package model
+
+import "fmt"
+
+// Strategy 模型路由策略
+type Strategy string
+
+const (
+	StrategyLatency Strategy = "latency" // 观测延迟（P95）最低
+	StrategyCost    Strategy = "cost"    // 预估费用最低
+	StrategyQuality Strategy = "quality" // 质量评分最高
+	StrategyRandom  Strategy = "random"  // 随机（A/B 测试、分流）
+)
+
+// 领域错误
+var (
+	ErrInvalidStrategy  = fmt.Errorf("INVALID_STRATEGY: 路由策略必须是 latency、cost、quality 或 random")
+	ErrNoModelAvailable = fmt.Errorf("NO_MODEL_AVAILABLE: 没有可用的模型")
+)
+
+// IsValid 判断策略是否有效
+func (s Strategy) IsValid() bool {
+	switch s {
+	case StrategyLatency, StrategyCost, StrategyQuality, StrategyRandom:
+		return true
+	}
+	return false
+}
+
+// ModelSpec 模型目录条目
+//
+// 价格单位为美元 / 百万 Token。TypicalLatencyMs 是还没有观测数据时使用的预估延迟。
+type ModelSpec struct {
+	Provider         string
+	Model            string
+	InputPrice       float64 // 输入价格（USD / 1M tokens）
+	OutputPrice      float64 // 输出价格（USD / 1M tokens）
+	Quality          int     // 质量评分（1-10，越高越好）
+	TypicalLatencyMs int64   // 预估延迟（毫秒）
+	ContextWindow    int     // 上下文窗口（Token）
+	SupportsTools    bool    // 支持工具调用
+	SupportsVision   bool    // 支持图片输入
+	SupportsJSONMode bool    // 支持 JSON 输出约束
+}
+
+// Supports 判断模型是否具备请求需要的能力（工具调用、JSON 输出）
+func (m ModelSpec) Supports(req *ChatRequest) bool {
+	if len(req.Tools) > 0 && !m.SupportsTools {
+		return false
+	}
+	if req.ResponseFormat != nil && req.ResponseFormat.Type != "" && req.ResponseFormat.Type != "text" && !m.SupportsJSONMode {
+		return false
+	}
+	return true
+}
+
+// EstimateCost 按 Token 数估算费用（美元）
+func (m ModelSpec) EstimateCost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*m.InputPrice + float64(outputTokens)*m.OutputPrice) / 1_000_000
+}
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Role 消息角色
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+)
+
+// FinishReason 生成结束原因
+type FinishReason string
+
+const (
+	FinishReasonStop      FinishReason = "stop"       // 自然结束
+	FinishReasonLength    FinishReason = "length"     // 达到 MaxTokens
+	FinishReasonToolCalls FinishReason = "tool_calls" // 模型请求调用工具
+)
+
+// 领域错误
+var (
+	ErrEmptyMessages    = fmt.Errorf("EMPTY_MESSAGES: 消息列表不能为空")
+	ErrEmptyInput       = fmt.Errorf("EMPTY_INPUT: 嵌入输入不能为空")
+	ErrProviderNotFound = fmt.Errorf("PROVIDER_NOT_FOUND: 模型提供商未注册")
+	ErrQuotaExceeded    = fmt.Errorf("QUOTA_EXCEEDED: 用量已超出额度")
+	ErrInvalidSchema    = fmt.Errorf("INVALID_SCHEMA: JSON Schema 无效")
+	ErrSchemaValidation = fmt.Errorf("SCHEMA_VALIDATION_FAILED: 模型输出不符合 JSON Schema")
+)
+
+// Message 对话消息
+type Message struct {
+	Role       Role       `json:"role"`
+	Content    string     `json:"content"`
+	Name       string     `json:"name,omitempty"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // assistant 消息请求的工具调用
+	ToolCallID string     `json:"tool_call_id,omitempty"` // tool 消息对应的调用 ID
+}
+
+// ToolCall 模型发起的工具调用
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON 字符串
+}
+
+// ToolDefinition 可供模型调用的工具
+type ToolDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"` // JSON Schema
+}
+
+// ResponseFormat 输出格式约束
+type ResponseFormat struct {
+	Type   string          `json:"type"`             // text, json_object, json_schema
+	Name   string          `json:"name,omitempty"`   // json_schema 时的 Schema 名称
+	Schema json.RawMessage `json:"schema,omitempty"` // json_schema 时的 Schema
+}
+
+// CacheOptions 响应缓存选项（零值：只缓存确定性请求，使用默认有效期）
+type CacheOptions struct {
+	Force  bool          // 非确定性请求（temperature 不为 0）也缓存
+	TTL    time.Duration // 缓存有效期，0 使用默认值（提示词模板可以单独设置）
+	Bypass bool          // 不读也不写缓存
+}
+
+// ChatRequest 对话补全请求
+//
+// Provider 和 Model 都指定时直接使用（覆盖路由）；否则由 LLMService 按 Strategy
+// （为空时使用路由器的默认策略）选择模型，未配置路由时填充默认提供商和模型。
+// Temperature/TopP 为 nil 表示使用提供商默认值。
+type ChatRequest struct {
+	Provider       string
+	Model          string
+	Strategy       Strategy // 本次请求的路由策略（可选）
+	Messages       []Message
+	Temperature    *float64
+	TopP           *float64
+	MaxTokens      int
+	Stop           []string
+	Tools          []ToolDefinition
+	ResponseFormat *ResponseFormat
+	User           string       // 终端用户标识（透传给提供商）
+	Cache          CacheOptions // 响应缓存（仅 Complete，配置了缓存时生效）
+}
+
+// Deterministic 判断请求是否是确定性的（temperature 为 0）
+func (r *ChatRequest) Deterministic() bool {
+	return r.Temperature != nil && *r.Temperature == 0
+}
+
+// Validate 验证请求
+func (r *ChatRequest) Validate() error {
+	if len(r.Messages) == 0 {
+		return ErrEmptyMessages
+	}
+	if r.Strategy != "" && !r.Strategy.IsValid() {
+		return ErrInvalidStrategy
+	}
+	return nil
+}
+
+// Usage Token 用量
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// TotalTokens 返回总 Token 数
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// ChatResponse 对话补全响应
+type ChatResponse struct {
+	ID           string
+	Provider     string
+	Model        string
+	Message      Message
+	FinishReason FinishReason
+	Usage        Usage
+	Cached       bool // 来自响应缓存（没有调用提供商）
+}
+
+// StreamChunk 流式响应片段
+//
+// Usage 只在最后一个片段中出现（提供商支持时）。
+type StreamChunk struct {
+	Delta        string
+	ToolCalls    []ToolCall
+	FinishReason FinishReason
+	Usage        *Usage
+}
+
+// EmbeddingRequest 向量嵌入请求
+type EmbeddingRequest struct {
+	Provider string
+	Model    string
+	Input    []string
+}
+
+// Validate 验证请求
+func (r *EmbeddingRequest) Validate() error {
+	if len(r.Input) == 0 {
+		return ErrEmptyInput
+	}
+	return nil
+}
+
+// EmbeddingResponse 向量嵌入响应
+//
+// Vectors 与 Input 一一对应。
+type EmbeddingResponse struct {
+	Provider string
+	Model    string
+	Vectors  [][]float32
+	Usage    Usage
+}
@@ -0,0 +1,64 @@
+package moderation
+
+import "regexp"
+
+// injectionPattern 一类提示词注入的特征
+type injectionPattern struct {
+	category string
+	pattern  *regexp.Regexp
+}
+
+// injectionPatterns 常见的提示词注入写法（中英文）
+var injectionPatterns = []injectionPattern{
+	{
+		// 要求忽略之前的指令
+		category: "instruction_override",
+		pattern: regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\s+(?:all\s+|any\s+|everything\s+)?(?:of\s+)?(?:the\s+|your\s+|my\s+)?(?:previous|prior|above|earlier|preceding|system|original)?\s*(?:instructions?|prompts?|rules|directions|guidelines)\b` +
+			`|(?:忽略|无视|忘记|忘掉)(?:掉)?(?:你)?(?:之前|以上|上面|前面|先前|原来|所有|一切)的?(?:所有的?)?(?:指令|指示|说明|提示词?|规则|设定)`),
+	},
+	{
+		// 要求泄露系统提示
+		category: "prompt_leak",
+		pattern: regexp.MustCompile(`(?i)\b(?:reveal|show|print|repeat|output|leak|tell\s+me)\s+(?:me\s+)?(?:your|the)\s+(?:system\s+prompt|initial\s+prompt|hidden\s+instructions|instructions)\b` +
+			`|(?:显示|输出|告诉我|泄露|打印|重复)(?:一下)?(?:你的)?(?:系统提示词?|初始指令|隐藏指令)`),
+	},
+	{
+		// 越狱：要求扮演不受限制的角色
+		category: "jailbreak",
+		pattern: regexp.MustCompile(`(?i)\b(?:developer|god|jailbreak|DAN)\s+mode\b|\byou\s+are\s+now\s+(?:an?\s+)?(?:unrestricted|unfiltered|uncensored|DAN)\b|\bdo\s+anything\s+now\b` +
+			`|你现在(?:是|扮演)?(?:一个)?(?:没有|不受)(?:任何)?(?:限制|约束|审查)`),
+	},
+	{
+		// 伪造的角色标记
+		category: "role_marker",
+		pattern:  regexp.MustCompile(`(?i)</?(?:system|assistant)>|<\|im_start\|>|\[/?INST\]|(?m:^\s*###\s*(?:system|instruction)s?\s*:?\s*$)`),
+	},
+}
+
+// Injection 提示词注入检查
+//
+// 按常见写法（要求忽略指令、泄露系统提示、越狱、伪造角色标记）启发式识别，
+// 会有漏报和误报，适合 flag（记录）或 block。redact 时命中的内容替换为 [REMOVED]。
+type Injection struct{}
+
+// Name 检查器名称
+func (Injection) Name() string {
+	return "prompt_injection"
+}
+
+// Check 查找提示词注入的特征
+func (i Injection) Check(text string) []Finding {
+	var findings []Finding
+	for _, p := range injectionPatterns {
+		for _, m := range p.pattern.FindAllStringIndex(text, -1) {
+			findings = append(findings, Finding{
+				Checker:     i.Name(),
+				Category:    p.category,
+				Start:       m[0],
+				End:         m[1],
+				Replacement: "[REMOVED]",
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,280 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// AlertName 审核决定记录为 AlertTriggered 事件时的告警名称
+const AlertName = "ContentModeration"
+
+// ErrContentBlocked 内容未通过安全检查（block）
+var ErrContentBlocked = fmt.Errorf("CONTENT_BLOCKED: 内容未通过安全检查")
+
+// Action 检查命中时的处理方式
+type Action string
+
+const (
+	ActionAllow  Action = "allow"  // 没有命中（只出现在 Decision 中）
+	ActionFlag   Action = "flag"   // 放行，只记录
+	ActionRedact Action = "redact" // 替换命中的内容后放行
+	ActionBlock  Action = "block"  // 拒绝
+)
+
+// ParseAction 解析配置中的处理方式（flag、redact、block）
+func ParseAction(s string) (Action, error) {
+	switch a := Action(s); a {
+	case ActionFlag, ActionRedact, ActionBlock:
+		return a, nil
+	}
+	return "", fmt.Errorf("unknown moderation action %q (must be flag, redact or block)", s)
+}
+
+// rank 处理方式的严格程度（多个检查命中时取最严格的）
+func (a Action) rank() int {
+	switch a {
+	case ActionFlag:
+		return 1
+	case ActionRedact:
+		return 2
+	case ActionBlock:
+		return 3
+	}
+	return 0
+}
+
+// Finding 一处命中
+type Finding struct {
+	Checker     string // 检查器名称（wordlist、pii、prompt_injection）
+	Category    string // 命中的类别（如 blocked_word、email、phone、card_number、instruction_override）
+	Start       int    // 命中内容在文本中的字节位置 [Start, End)
+	End         int
+	Replacement string // redact 时替换为的文本
+	Action      Action // 该检查配置的处理方式（由 Pipeline 填写）
+}
+
+// Checker 内容检查器
+//
+// 只查找命中的位置，处理方式由 Pipeline 的 Rule 决定。实现必须是并发安全的。
+type Checker interface {
+	Name() string
+	Check(text string) []Finding
+}
+
+// Rule 检查器和命中时的处理方式
+type Rule struct {
+	Checker Checker
+	Action  Action
+}
+
+// Input 待检查的内容
+type Input struct {
+	Source string // 内容来源（如 chat.input、chat.output、task），记录在事件中
+	UserID string
+	Text   string
+}
+
+// Decision 检查结果
+type Decision struct {
+	Action   Action // 所有命中中最严格的处理方式，没有命中时为 allow
+	Text     string // 处理后的文本（redact 的内容已替换；block 时为原文）
+	Findings []Finding
+}
+
+// Blocked 内容是否被拒绝
+func (d Decision) Blocked() bool {
+	return d.Action == ActionBlock
+}
+
+// Pipeline 内容审核流水线
+//
+// 依次运行所有检查器，合并命中结果：
+//   - 任一 block 的检查命中时拒绝
+//   - redact 的检查命中的内容被替换（命中位置重叠时保留先出现的）
+//   - flag 的检查只记录
+//
+// 有命中时发布 AlertTriggered 事件（不包含命中的原文，避免事件中出现个人信息）。
+// nil 的 *Pipeline 放行所有内容。
+type Pipeline struct {
+	rules    []Rule
+	eventBus sharedevents.EventBus
+}
+
+// NewPipeline 创建内容审核流水线
+//
+// 参数：
+//   - eventBus: 事件总线（可为 nil，不发布事件）
+//   - rules: 检查器和处理方式（按顺序运行）
+func NewPipeline(eventBus sharedevents.EventBus, rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules, eventBus: eventBus}
+}
+
+// Moderate 检查内容
+func (p *Pipeline) Moderate(ctx context.Context, input Input) Decision {
+	decision := p.check(input.Text)
+	if len(decision.Findings) == 0 {
+		return decision
+	}
+
+	if !decision.Blocked() {
+		decision.Text = redact(input.Text, redactions(decision.Findings))
+	}
+	p.publish(ctx, input, decision)
+	return decision
+}
+
+// check 运行所有检查器，合并命中结果（不替换内容，也不发布事件）
+func (p *Pipeline) check(text string) Decision {
+	decision := Decision{Action: ActionAllow, Text: text}
+	if p == nil || text == "" {
+		return decision
+	}
+
+	for _, rule := range p.rules {
+		findings := rule.Checker.Check(text)
+		for i := range findings {
+			findings[i].Action = rule.Action
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		decision.Findings = append(decision.Findings, findings...)
+		if rule.Action.rank() > decision.Action.rank() {
+			decision.Action = rule.Action
+		}
+	}
+	return decision
+}
+
+// redactions 需要替换的命中
+func redactions(findings []Finding) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if f.Action == ActionRedact {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// publish 发布 AlertTriggered 事件（失败只记录日志）
+func (p *Pipeline) publish(ctx context.Context, input Input, decision Decision) {
+	if p.eventBus == nil {
+		return
+	}
+
+	var checkers, categories, details []string
+	seen := make(map[string]bool)
+	for _, f := range decision.Findings {
+		key := f.Checker + "/" + f.Category
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		checkers = appendUnique(checkers, f.Checker)
+		categories = appendUnique(categories, f.Category)
+		details = append(details, fmt.Sprintf("%s(%s)", key, f.Action))
+	}
+
+	severity := "info"
+	if decision.Blocked() {
+		severity = "warning"
+	}
+	event := sharedevents.NewAlertTriggeredEvent(sharedevents.AlertTriggeredPayload{
+		Labels: map[string]string{
+			"source":     input.Source,
+			"user_id":    input.UserID,
+			"action":     string(decision.Action),
+			"checkers":   strings.Join(checkers, ","),
+			"categories": strings.Join(categories, ","),
+		},
+		AlertName: AlertName,
+		Severity:  severity,
+		Message:   fmt.Sprintf("%s %s: %s", input.Source, decision.Action, strings.Join(details, ", ")),
+	})
+	if err := p.eventBus.Publish(ctx, event); err != nil {
+		logger.Error("publish moderation alert failed", zap.Error(err))
+	}
+}
+
+// redact 替换命中的内容（位置重叠时保留先出现的）
+func redact(text string, findings []Finding) string {
+	if len(findings) == 0 {
+		return text
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Start < findings[j].Start })
+
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		if f.Start < last {
+			continue
+		}
+		b.WriteString(text[last:f.Start])
+		b.WriteString(f.Replacement)
+		last = f.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// findAll 查找 re 的所有匹配，accept 返回 false 的匹配跳过（从下一个字符继续查找）
+func findAll(re *regexp.Regexp, text string, accept func(start, end int) bool) [][2]int {
+	var matches [][2]int
+	for pos := 0; pos < len(text); {
+		loc := re.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if end > start && accept(start, end) {
+			matches = append(matches, [2]int{start, end})
+			pos = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + max(size, 1)
+	}
+	return matches
+}
+
+// isWordRune 字母、数字或下划线
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// runeBefore 返回 text[:i] 的最后一个字符（没有时为 0）
+func runeBefore(text string, i int) rune {
+	if i <= 0 {
+		return 0
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return r
+}
+
+// runeAt 返回 text[i:] 的第一个字符（没有时为 0）
+func runeAt(text string, i int) rune {
+	if i >= len(text) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return r
+}
+
+func appendUnique(items []string, item string) []string {
+	for _, existing := range items {
+		if existing == item {
+			return items
+		}
+	}
+	return append(items, item)
+}
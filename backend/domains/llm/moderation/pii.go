@@ -0,0 +1,128 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// piiPattern 一类个人信息
+type piiPattern struct {
+	category    string
+	replacement string
+	pattern     *regexp.Regexp
+	valid       func(match string) bool // 额外校验（可为 nil）
+}
+
+// piiPatterns 按顺序查找，与已命中位置重叠的匹配忽略（银行卡号先于电话号码）
+var piiPatterns = []piiPattern{
+	{
+		category:    "email",
+		replacement: "[EMAIL]",
+		pattern:     regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	},
+	{
+		category:    "card_number",
+		replacement: "[CARD]",
+		// 13-19 位数字，可以用空格或 - 分组，必须通过 Luhn 校验
+		pattern: regexp.MustCompile(`\d(?:[ \-]?\d){12,18}`),
+		valid:   luhnValid,
+	},
+	{
+		category:    "phone",
+		replacement: "[PHONE]",
+		pattern: regexp.MustCompile("(?:" + strings.Join([]string{
+			// 中国大陆手机号：138 1234 5678、+86 13812345678
+			`(?:\+?86[ \-]?)?1[3-9]\d(?:[ \-]?\d{4}){2}`,
+			// 中国大陆固话：010-12345678
+			`0\d{2,3}-\d{7,8}`,
+			// 北美：(415) 555-0100、415.555.0100、+1 415 555 0100
+			`(?:\+?1[ \-.]?)?(?:\(\d{3}\)[ \-.]?|\d{3}[ \-.])\d{3}[ \-.]\d{4}`,
+			// 其他国际号码：+44 20 7946 0958
+			`\+\d{1,3}(?:[ \-.]?\d{1,4}){2,5}`,
+		}, ")|(?:") + ")"),
+		valid: func(match string) bool {
+			n := countDigits(match)
+			return n >= 7 && n <= 15
+		},
+	},
+}
+
+// PII 个人信息检查：邮箱、电话号码和银行卡号
+//
+// 只识别常见格式（不依赖外部服务），日期等数字序列不会被当作电话号码。
+// redact 时替换为 [EMAIL]、[PHONE]、[CARD]。
+type PII struct{}
+
+// Name 检查器名称
+func (PII) Name() string {
+	return "pii"
+}
+
+// Check 查找个人信息
+func (p PII) Check(text string) []Finding {
+	var findings []Finding
+	for _, pii := range piiPatterns {
+		for _, m := range findAll(pii.pattern, text, func(start, end int) bool {
+			return alnumBoundary(text, start, end) &&
+				!overlaps(findings, start, end) &&
+				(pii.valid == nil || pii.valid(text[start:end]))
+		}) {
+			findings = append(findings, Finding{
+				Checker:     p.Name(),
+				Category:    pii.category,
+				Start:       m[0],
+				End:         m[1],
+				Replacement: pii.replacement,
+			})
+		}
+	}
+	return findings
+}
+
+// alnumBoundary 匹配的前后不是字母或数字（避免命中更长的编号中的一段）
+func alnumBoundary(text string, start, end int) bool {
+	before, after := runeBefore(text, start), runeAt(text, end)
+	return !unicode.IsLetter(before) && !unicode.IsDigit(before) &&
+		!unicode.IsLetter(after) && !unicode.IsDigit(after)
+}
+
+// overlaps 是否与已命中的位置重叠
+func overlaps(findings []Finding, start, end int) bool {
+	for _, f := range findings {
+		if start < f.End && f.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid 银行卡号的 Luhn 校验
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
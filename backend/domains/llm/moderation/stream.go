@@ -0,0 +1,103 @@
+package moderation
+
+import (
+	"unicode/utf8"
+)
+
+// streamWindow 流式审核的窗口大小（字节）
+//
+// 缓冲区末尾这么多字节暂不放行（命中可能跨越之后的增量，如被截断的邮箱或卡号），
+// 已放行内容的最后这么多字节保留为检查的上下文（单词边界等）。
+// 比窗口更长的命中可能在放行后才出现，只能由结束后的完整检查处理。
+const streamWindow = 128
+
+// Stream 流式内容的增量审核
+//
+// 增量内容先进入滚动缓冲区，经过检查后才放行：
+//   - 任一 block 的检查命中时返回 ErrContentBlocked，之后不再放行任何内容
+//   - redact 的检查命中的内容替换后放行，放行位置不会落在命中内容中间
+//
+// 增量检查不发布事件；流结束后仍应使用 Pipeline.Moderate 检查完整内容（记录审核决定、得到保存的内容）。
+// Stream 不是并发安全的。
+type Stream struct {
+	pipeline *Pipeline
+	buf      string // 已放行内容的末尾（上下文）+ 未放行的内容
+	sent     int    // buf 中已放行的字节数
+	blocked  bool
+}
+
+// NewStream 创建流式审核（nil 的 *Pipeline 直接放行所有增量）
+func (p *Pipeline) NewStream() *Stream {
+	return &Stream{pipeline: p}
+}
+
+// Write 写入增量内容，返回可以发送的内容（可能为空，内容暂存在缓冲区中）
+func (s *Stream) Write(delta string) (string, error) {
+	s.buf += delta
+	return s.release(false)
+}
+
+// Flush 内容结束，返回缓冲区中剩余的可以发送的内容
+func (s *Stream) Flush() (string, error) {
+	return s.release(true)
+}
+
+// release 检查缓冲区并放行安全的部分（final 时放行全部）
+func (s *Stream) release(final bool) (string, error) {
+	if s.blocked {
+		return "", ErrContentBlocked
+	}
+	if s.pipeline == nil {
+		out := s.buf[s.sent:]
+		s.buf, s.sent = "", 0
+		return out, nil
+	}
+
+	decision := s.pipeline.check(s.buf)
+	if decision.Blocked() {
+		s.blocked = true
+		return "", ErrContentBlocked
+	}
+
+	cut := len(s.buf)
+	if !final {
+		cut = runeStart(s.buf, cut-streamWindow)
+		// 不在命中内容中间放行（跨越已放行位置的命中已经无法处理）
+		for moved := true; moved; {
+			moved = false
+			for _, f := range decision.Findings {
+				if f.Start >= s.sent && f.Start < cut && cut < f.End {
+					cut, moved = f.Start, true
+				}
+			}
+		}
+	}
+	if cut <= s.sent {
+		return "", nil
+	}
+
+	var pending []Finding
+	for _, f := range redactions(decision.Findings) {
+		if f.Start >= s.sent && f.End <= cut {
+			f.Start -= s.sent
+			f.End -= s.sent
+			pending = append(pending, f)
+		}
+	}
+	out := redact(s.buf[s.sent:cut], pending)
+
+	keep := runeStart(s.buf, cut-streamWindow)
+	s.buf, s.sent = s.buf[keep:], cut-keep
+	return out, nil
+}
+
+// runeStart 返回不大于 i 的字符起始位置（i < 0 时为 0）
+func runeStart(text string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,114 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Wordlist 屏蔽词检查
+//
+// 不区分大小写，按词边界匹配（"Scunthorpe" 不会命中 "cunt"）：
+//   - 以 * 结尾的词匹配以其开头的词（"fuck*" 命中 "fucking"）
+//   - 词中的空白匹配任意空白（"go to hell" 命中 "go  to\nhell"）
+//   - 中日韩文字没有词边界，按子串匹配
+//
+// redact 时命中的内容替换为等长的 *。
+type Wordlist struct {
+	pattern *regexp.Regexp // 没有屏蔽词时为 nil
+}
+
+// NewWordlist 创建屏蔽词检查（空白和 # 开头的项忽略）
+func NewWordlist(words []string) (*Wordlist, error) {
+	var alternatives []string
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimSpace(strings.TrimSuffix(word, "*"))
+		if word == "" {
+			return nil, fmt.Errorf("invalid wordlist entry %q", "*")
+		}
+
+		parts := strings.Fields(word)
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		alt := strings.Join(parts, `\s+`)
+		if prefix {
+			alt += `[\p{L}\p{N}_]*`
+		}
+		alternatives = append(alternatives, alt)
+	}
+	if len(alternatives) == 0 {
+		return &Wordlist{}, nil
+	}
+
+	// 较长的词优先（"ass hat" 先于 "ass"）
+	sort.SliceStable(alternatives, func(i, j int) bool { return len(alternatives[i]) > len(alternatives[j]) })
+	pattern, err := regexp.Compile(`(?i)(?:` + strings.Join(alternatives, "|") + `)`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wordlist: %w", err)
+	}
+	return &Wordlist{pattern: pattern}, nil
+}
+
+// ReadWordlist 读取屏蔽词文件（每行一个，# 开头为注释）
+func ReadWordlist(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// Name 检查器名称
+func (w *Wordlist) Name() string {
+	return "wordlist"
+}
+
+// Check 查找屏蔽词
+func (w *Wordlist) Check(text string) []Finding {
+	if w.pattern == nil {
+		return nil
+	}
+	var findings []Finding
+	for _, m := range findAll(w.pattern, text, func(start, end int) bool {
+		return wordBoundary(text, start, end)
+	}) {
+		findings = append(findings, Finding{
+			Checker:     w.Name(),
+			Category:    "blocked_word",
+			Start:       m[0],
+			End:         m[1],
+			Replacement: strings.Repeat("*", utf8.RuneCountInString(text[m[0]:m[1]])),
+		})
+	}
+	return findings
+}
+
+// wordBoundary 匹配的两端是否为词边界（中日韩文字的一端不要求）
+func wordBoundary(text string, start, end int) bool {
+	if first := runeAt(text, start); needsBoundary(first) && isWordRune(runeBefore(text, start)) {
+		return false
+	}
+	if last := runeBefore(text, end); needsBoundary(last) && isWordRune(runeAt(text, end)) {
+		return false
+	}
+	return true
+}
+
+// needsBoundary 以该字符开头或结尾的词是否要求词边界
+func needsBoundary(r rune) bool {
+	return isWordRune(r) && !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
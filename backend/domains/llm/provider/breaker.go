@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 提供商的熔断器打开，请求未发送
+var ErrCircuitOpen = fmt.Errorf("CIRCUIT_OPEN: 提供商暂时不可用")
+
+// BreakerState 熔断器状态
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // 正常放行
+	BreakerOpen     BreakerState = "open"      // 熔断：请求直接失败，冷却后进入 half_open
+	BreakerHalfOpen BreakerState = "half_open" // 试探：只放行一个请求，成功后关闭，失败后重新熔断
+)
+
+// BreakerConfig 熔断器配置
+type BreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后熔断（<= 0 不熔断）
+	Cooldown         time.Duration // 熔断多久后放行试探请求
+}
+
+// BreakerStatus 某个提供商的熔断器状态
+type BreakerStatus struct {
+	Provider  string       `json:"provider"`
+	State     BreakerState `json:"state"`
+	Failures  int          `json:"failures"`             // 连续失败次数
+	RetryAt   time.Time    `json:"retry_at,omitempty"`   // 熔断时：放行试探请求的时间
+	LastError string       `json:"last_error,omitempty"` // 最近一次计入的失败
+}
+
+// Breakers 按提供商划分的熔断器
+//
+// 只有提供商不可用类的错误（Error.Retryable：超时、限流、5xx、网络错误，已经过客户端重试）计为失败；
+// 其他提供商错误（如 400）说明提供商可以访问，计为成功；ctx 取消等非提供商错误不计入。
+// nil *Breakers 放行所有请求。
+type Breakers struct {
+	cfg      BreakerConfig
+	mu       sync.Mutex
+	breakers map[string]*breaker
+	now      func() time.Time
+	onChange func(provider string, state BreakerState)
+}
+
+type breaker struct {
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool // half_open 时试探请求是否已放行
+	lastError string
+}
+
+// NewBreakers 创建熔断器
+func NewBreakers(cfg BreakerConfig) *Breakers {
+	return &Breakers{
+		cfg:      cfg,
+		breakers: make(map[string]*breaker),
+		now:      time.Now,
+	}
+}
+
+// OnStateChange 设置状态变化的回调（用于指标，在持有锁时调用，不能再调用 Breakers 的方法）
+func (b *Breakers) OnStateChange(fn func(provider string, state BreakerState)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = fn
+}
+
+// Allow 判断是否可以向提供商发送请求，熔断时返回 ErrCircuitOpen
+func (b *Breakers) Allow(provider string) error {
+	if b == nil || b.cfg.FailureThreshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.get(provider)
+	switch br.state {
+	case BreakerOpen:
+		if b.now().Sub(br.openedAt) < b.cfg.Cooldown {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, provider)
+		}
+		b.setState(provider, br, BreakerHalfOpen)
+		br.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if br.probing {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, provider)
+		}
+		br.probing = true
+	}
+	return nil
+}
+
+// Record 记录一次调用的结果（err 为 nil 表示成功）
+func (b *Breakers) Record(provider string, err error) {
+	if b == nil || b.cfg.FailureThreshold <= 0 || errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.get(provider)
+	br.probing = false
+
+	var perr *Error
+	switch {
+	case err == nil, errors.As(err, &perr) && !perr.Retryable:
+		br.failures = 0
+		b.setState(provider, br, BreakerClosed)
+	case perr != nil:
+		br.failures++
+		br.lastError = err.Error()
+		if br.state == BreakerHalfOpen || br.failures >= b.cfg.FailureThreshold {
+			br.openedAt = b.now()
+			b.setState(provider, br, BreakerOpen)
+		}
+	}
+}
+
+// Status 返回提供商的熔断器状态
+func (b *Breakers) Status(provider string) BreakerStatus {
+	status := BreakerStatus{Provider: provider, State: BreakerClosed}
+	if b == nil {
+		return status
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.breakers[provider]
+	if !ok {
+		return status
+	}
+	status.State = br.state
+	status.Failures = br.failures
+	status.LastError = br.lastError
+	if br.state == BreakerOpen {
+		status.RetryAt = br.openedAt.Add(b.cfg.Cooldown)
+	}
+	return status
+}
+
+// Statuses 返回所有调用过的提供商的熔断器状态（按名称排序）
+func (b *Breakers) Statuses() []BreakerStatus {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	names := make([]string, 0, len(b.breakers))
+	for name := range b.breakers {
+		names = append(names, name)
+	}
+	b.mu.Unlock()
+
+	sort.Strings(names)
+	statuses := make([]BreakerStatus, 0, len(names))
+	for _, name := range names {
+		statuses = append(statuses, b.Status(name))
+	}
+	return statuses
+}
+
+func (b *Breakers) get(provider string) *breaker {
+	br, ok := b.breakers[provider]
+	if !ok {
+		br = &breaker{state: BreakerClosed}
+		b.breakers[provider] = br
+	}
+	return br
+}
+
+func (b *Breakers) setState(provider string, br *breaker, state BreakerState) {
+	if br.state == state {
+		return
+	}
+	br.state = state
+	if b.onChange != nil {
+		b.onChange(provider, state)
+	}
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakers(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBreakers(BreakerConfig{FailureThreshold: 2, Cooldown: 30 * time.Second})
+	b.now = func() time.Time { return now }
+	var changes []BreakerState
+	b.OnStateChange(func(provider string, state BreakerState) { changes = append(changes, state) })
+
+	unavailable := &Error{Provider: "openai", StatusCode: 503, Retryable: true}
+
+	// 非提供商错误和不可重试的错误不计为失败
+	b.Record("openai", context.Canceled)
+	b.Record("openai", &Error{Provider: "openai", StatusCode: 400})
+	b.Record("openai", unavailable)
+	assert.Equal(t, BreakerClosed, b.Status("openai").State)
+	assert.Equal(t, 1, b.Status("openai").Failures)
+
+	// 连续失败达到阈值后熔断
+	b.Record("openai", unavailable)
+	status := b.Status("openai")
+	assert.Equal(t, BreakerOpen, status.State)
+	assert.Equal(t, now.Add(30*time.Second), status.RetryAt)
+	assert.ErrorIs(t, b.Allow("openai"), ErrCircuitOpen)
+	assert.NoError(t, b.Allow("anthropic"))
+
+	// 冷却后只放行一个试探请求，失败后重新熔断
+	now = now.Add(31 * time.Second)
+	require.NoError(t, b.Allow("openai"))
+	assert.Equal(t, BreakerHalfOpen, b.Status("openai").State)
+	assert.ErrorIs(t, b.Allow("openai"), ErrCircuitOpen)
+	b.Record("openai", unavailable)
+	assert.Equal(t, BreakerOpen, b.Status("openai").State)
+
+	// 试探成功后关闭
+	now = now.Add(31 * time.Second)
+	require.NoError(t, b.Allow("openai"))
+	b.Record("openai", nil)
+	assert.Equal(t, BreakerClosed, b.Status("openai").State)
+	assert.Equal(t, 0, b.Status("openai").Failures)
+
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, changes)
+	assert.Equal(t, []string{"anthropic", "openai"}, []string{b.Statuses()[0].Provider, b.Statuses()[1].Provider})
+}
+
+func TestBreakers_Disabled(t *testing.T) {
+	var none *Breakers
+	assert.NoError(t, none.Allow("openai"))
+	none.Record("openai", errors.New("boom"))
+	assert.Equal(t, BreakerClosed, none.Status("openai").State)
+
+	b := NewBreakers(BreakerConfig{})
+	for i := 0; i < 10; i++ {
+		b.Record("openai", &Error{Provider: "openai", Retryable: true})
+	}
+	assert.NoError(t, b.Allow("openai"))
+}
@@ -0,0 +1,185 @@
+// Package cassette 录制和回放提供商的 HTTP 调用
+//
+// 录制模式把真实的请求和响应（包括 SSE 流）保存到 JSON 文件（cassette），保存前去掉密钥；
+// 回放模式按请求内容从文件中返回响应，不访问网络。测试通过 openai.Config.HTTPClient
+// 注入 Recorder.Client()，即可离线运行真实的客户端代码（请求构造、SSE 解析、错误处理）。
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Redacted 替换密钥后的占位符
+const Redacted = "[REDACTED]"
+
+// Cassette 录制的调用（按录制顺序）
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求和对应的响应
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 录制的请求（不保存请求头，避免保存 Authorization）
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"` // URL 路径和查询参数（不含主机，回放与 BaseURL 无关）
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Text   string          `json:"text,omitempty"` // 请求体不是 JSON 时
+}
+
+// Response 录制的响应
+//
+// 请求体和响应体是 JSON 时按 JSON 保存（方便阅读和手工修改）；
+// SSE 响应按事件保存，每项是一个事件的原始文本（如 "data: {...}"）。
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"` // 只保存 keptHeaders 中的响应头
+	JSON    json.RawMessage   `json:"json,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Events  []string          `json:"events,omitempty"`
+}
+
+// keptHeaders 保存的响应头（其他响应头可能包含组织 ID、Cookie 等，且回放时不需要）
+var keptHeaders = []string{"Content-Type", "Retry-After"}
+
+// Load 读取 cassette 文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: parse %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Save 写入 cassette 文件（自动创建目录）
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// secretPatterns 常见的密钥格式（无论是否通过 NewScrubber 列出都会替换）
+var secretPatterns = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{16,}`), Redacted},                                        // OpenAI / Anthropic API Key
+	{regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/\-]+=*`), "Bearer " + Redacted},                  // Authorization 头的值
+	{regexp.MustCompile(`(?i)((?:api[_-]?key|access[_-]?token|secret)=)[^&\s"]+`), "${1}" + Redacted}, // 查询参数
+}
+
+// Scrubber 替换文本中的密钥
+type Scrubber struct {
+	secrets []string
+}
+
+// NewScrubber 创建 Scrubber，secrets 为需要额外替换的字面值（如测试使用的 API Key），空字符串忽略
+func NewScrubber(secrets ...string) *Scrubber {
+	s := &Scrubber{}
+	for _, secret := range secrets {
+		if secret != "" {
+			s.secrets = append(s.secrets, secret)
+		}
+	}
+	return s
+}
+
+// Scrub 返回替换密钥后的文本
+func (s *Scrubber) Scrub(text string) string {
+	for _, secret := range s.secrets {
+		text = strings.ReplaceAll(text, secret, Redacted)
+	}
+	for _, p := range secretPatterns {
+		text = p.re.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// newRequest 转换为录制的请求（已替换密钥）
+func newRequest(method, path string, body []byte, scrubber *Scrubber) Request {
+	req := Request{Method: method, Path: scrubber.Scrub(path)}
+	req.JSON, req.Text = splitBody(scrubber.Scrub(string(body)))
+	return req
+}
+
+// key 请求的匹配键：方法、路径和规范化的请求体（JSON 按键排序，与格式和字段顺序无关）
+func (r Request) key() string {
+	body := r.Text
+	if len(r.JSON) > 0 {
+		body = canonicalJSON(r.JSON)
+	}
+	return r.Method + " " + r.Path + "\n" + body
+}
+
+// splitBody 请求体或响应体是 JSON 时返回紧凑的 JSON，否则返回文本
+func splitBody(body string) (json.RawMessage, string) {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" {
+		return nil, ""
+	}
+	if json.Valid([]byte(trimmed)) && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(trimmed)); err == nil {
+			return json.RawMessage(buf.Bytes()), ""
+		}
+	}
+	return nil, body
+}
+
+// canonicalJSON 规范化 JSON（对象的键排序，数字保持原样）
+func canonicalJSON(raw json.RawMessage) string {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(raw)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(raw)
+	}
+	return string(data)
+}
+
+// splitEvents 把 SSE 响应体拆分为事件
+func splitEvents(body string) []string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	var events []string
+	for _, block := range strings.Split(body, "\n\n") {
+		if block = strings.Trim(block, "\n"); block != "" {
+			events = append(events, block)
+		}
+	}
+	return events
+}
+
+// body 回放的响应体
+func (r Response) body() []byte {
+	switch {
+	case len(r.Events) > 0:
+		return []byte(strings.Join(r.Events, "\n\n") + "\n\n")
+	case len(r.JSON) > 0:
+		return r.JSON
+	default:
+		return []byte(r.Text)
+	}
+}
@@ -0,0 +1,217 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode 录制或回放
+type Mode string
+
+const (
+	ModeReplay Mode = "replay" // 从文件返回响应，不访问网络（默认）
+	ModeRecord Mode = "record" // 转发到真实的提供商，Close 时覆盖写入文件
+)
+
+// EnvMode 选择模式的环境变量：APP_LLM_CASSETTE=record 时重新录制
+const EnvMode = "APP_LLM_CASSETTE"
+
+// ModeFromEnv 根据 APP_LLM_CASSETTE 返回模式（未设置或其他值为回放）
+func ModeFromEnv() Mode {
+	if Mode(os.Getenv(EnvMode)) == ModeRecord {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// ErrNoInteraction 回放时没有匹配的录制
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches the request")
+
+// Options Recorder 配置
+type Options struct {
+	Mode      Mode
+	Secrets   []string          // 录制时需要替换的字面值（如 API Key），常见的密钥格式总会替换
+	Transport http.RoundTripper // 录制时使用的真实传输（为空时使用 http.DefaultTransport）
+}
+
+// Recorder 录制或回放 HTTP 调用的 http.RoundTripper
+//
+// 回放时按方法、路径和规范化的请求体匹配，相同的请求按录制顺序依次返回响应
+// （例如重试：第一次返回 503，第二次返回 200）；每条录制只使用一次，没有匹配时返回 ErrNoInteraction。
+// 请求体在匹配前同样替换密钥，因此录制时替换掉的内容不影响匹配。
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubber  *Scrubber
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// New 创建 Recorder，回放模式下读取 path
+func New(path string, opts Options) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      opts.Mode,
+		transport: opts.Transport,
+		scrubber:  NewScrubber(opts.Secrets...),
+		cassette:  &Cassette{},
+	}
+	if r.mode == "" {
+		r.mode = ModeReplay
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	if r.mode == ModeReplay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w（使用 %s=record 录制）", err, EnvMode)
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// Client 返回使用 Recorder 的 HTTP 客户端
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode 返回当前模式
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	recorded := newRequest(req.Method, req.URL.RequestURI(), body, r.scrubber)
+
+	if r.mode == ModeRecord {
+		return r.record(req, body, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+// record 转发请求并保存响应（读取完整的响应体，SSE 流也一次读完）
+func (r *Recorder) record(req *http.Request, body []byte, recorded Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := Response{Status: resp.StatusCode, Headers: make(map[string]string)}
+	for _, name := range keptHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			response.Headers[name] = value
+		}
+	}
+	text := r.scrubber.Scrub(string(data))
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		response.Events = splitEvents(text)
+	} else {
+		response.JSON, response.Text = splitBody(text)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{Request: recorded, Response: response})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// replay 返回第一条未使用且匹配的录制
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	key := recorded.key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.key() != key {
+			continue
+		}
+		r.used[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s in %s (body: %s)", ErrNoInteraction, recorded.Method, recorded.Path, r.path, truncate(key, 300))
+}
+
+// Unused 返回回放时没有使用的录制数（测试可以断言录制全部被使用）
+func (r *Recorder) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// Close 录制模式下写入文件
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// toHTTP 转换为 HTTP 响应
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	body := r.body()
+	header := make(http.Header)
+	for name, value := range r.Headers {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,167 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/cassette"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAPIKey = "sk-live-0123456789abcdef"
+
+// fakeOpenAI 模拟 OpenAI 兼容接口：第一次非流式请求返回 503，之后返回回复；流式请求返回 SSE
+func fakeOpenAI(t *testing.T) *httptest.Server {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+testAPIKey, r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Openai-Organization", "org-secret")
+		if strings.Contains(string(body), `"stream":true`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"id\":\"c-2\",\"model\":\"gpt-4o\",\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"id\":\"c-2\",\"model\":\"gpt-4o\",\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":{"message":"overloaded"}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":"c-1","model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"Your key is %s"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":4}}`, testAPIKey)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newClient(baseURL string, rec *cassette.Recorder) *openai.Client {
+	return openai.New(openai.Config{
+		BaseURL:        baseURL,
+		APIKey:         testAPIKey,
+		Timeout:        time.Second,
+		MaxRetries:     1,
+		RetryBaseDelay: time.Millisecond,
+		HTTPClient:     rec.Client(),
+	})
+}
+
+func request(content string) *model.ChatRequest {
+	return &model.ChatRequest{Model: "gpt-4o", Messages: []model.Message{{Role: model.RoleUser, Content: content}}}
+}
+
+// exercise 依次执行一次非流式调用（经过一次重试）和一次流式调用
+func exercise(t *testing.T, client *openai.Client) (string, string) {
+	resp, err := client.Chat(context.Background(), request("Hello"))
+	require.NoError(t, err)
+
+	stream, err := client.ChatStream(context.Background(), request("Stream please"))
+	require.NoError(t, err)
+	defer stream.Close()
+	var streamed strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		streamed.WriteString(chunk.Delta)
+	}
+	return resp.Message.Content, streamed.String()
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.json")
+	server := fakeOpenAI(t)
+
+	// 录制
+	rec, err := cassette.New(path, cassette.Options{Mode: cassette.ModeRecord, Secrets: []string{"org-secret"}})
+	require.NoError(t, err)
+	reply, streamed := exercise(t, newClient(server.URL, rec))
+	require.NoError(t, rec.Close())
+	assert.Equal(t, "Your key is "+testAPIKey, reply)
+	assert.Equal(t, "Hello", streamed)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testAPIKey)
+	assert.NotContains(t, string(data), "org-secret")
+	assert.NotContains(t, string(data), "Authorization")
+
+	c, err := cassette.Load(path)
+	require.NoError(t, err)
+	require.Len(t, c.Interactions, 3)
+	assert.Equal(t, http.StatusServiceUnavailable, c.Interactions[0].Response.Status)
+	assert.Equal(t, "/chat/completions", c.Interactions[1].Request.Path)
+	assert.Equal(t, map[string]string{"Content-Type": "text/event-stream"}, c.Interactions[2].Response.Headers)
+	assert.Len(t, c.Interactions[2].Response.Events, 3)
+
+	// 回放：服务器关闭，不同的 BaseURL 主机也能匹配；重试的两次请求按顺序返回 503 和 200
+	server.Close()
+	replay, err := cassette.New(path, cassette.Options{})
+	require.NoError(t, err)
+	reply, streamed = exercise(t, newClient("http://replay.invalid", replay))
+	assert.Equal(t, "Your key is "+cassette.Redacted, reply)
+	assert.Equal(t, "Hello", streamed)
+	assert.Zero(t, replay.Unused())
+}
+
+func TestRecorder_ReplayNoMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chat.json")
+	c := &cassette.Cassette{Interactions: []cassette.Interaction{{
+		Request:  cassette.Request{Method: http.MethodPost, Path: "/v1/chat/completions", JSON: []byte(`{"model":"gpt-4o","messages":[]}`)},
+		Response: cassette.Response{Status: http.StatusOK, JSON: []byte(`{}`)},
+	}}}
+	require.NoError(t, c.Save(path))
+
+	rec, err := cassette.New(path, cassette.Options{})
+	require.NoError(t, err)
+	client := rec.Client()
+
+	// 键的顺序和空白不影响匹配
+	resp, err := client.Post("http://api.example.com/v1/chat/completions", "application/json", strings.NewReader(`{ "messages": [], "model": "gpt-4o" }`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// 每条录制只使用一次
+	_, err = client.Post("http://api.example.com/v1/chat/completions", "application/json", strings.NewReader(`{"model":"gpt-4o","messages":[]}`))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, cassette.ErrNoInteraction))
+
+	// 提供商客户端把回放失败当作网络错误
+	_, err = openai.New(openai.Config{BaseURL: "http://api.example.com/v1", HTTPClient: client}).Chat(context.Background(), request("Hi"))
+	var perr *provider.Error
+	require.ErrorAs(t, err, &perr)
+	assert.Contains(t, perr.Message, "no recorded interaction")
+}
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	_, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), cassette.EnvMode)
+}
+
+func TestScrubber(t *testing.T) {
+	s := cassette.NewScrubber("hunter2", "")
+
+	assert.Equal(t, "password [REDACTED]", s.Scrub("password hunter2"))
+	assert.Equal(t, "key [REDACTED]", s.Scrub("key sk-proj-abcdefgh12345678"))
+	assert.Equal(t, "Bearer [REDACTED]", s.Scrub("Bearer eyJhbGciOi.payload.sig"))
+	assert.Equal(t, "/v1/models?api_key=[REDACTED]&limit=5", s.Scrub("/v1/models?api_key=abc123&limit=5"))
+	assert.Equal(t, "ask-me-anything", s.Scrub("ask-me-anything"))
+}
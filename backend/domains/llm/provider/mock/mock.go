@@ -0,0 +1,296 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+)
+
+// Name Mock 提供商名称
+const Name = "mock"
+
+// EmbeddingDimensions Mock 嵌入向量维度
+const EmbeddingDimensions = 64
+
+// Response 脚本化响应
+//
+// Err 不为空时，调用直接返回该错误。
+// Usage 为空时按空白分词估算 Token 数。
+type Response struct {
+	Content      string
+	ToolCalls    []model.ToolCall
+	FinishReason model.FinishReason
+	Usage        *model.Usage
+	Err          error
+	Delay        time.Duration // 返回前（流式：每个片段前）的等待时间
+}
+
+// Handler 根据请求动态生成响应
+type Handler func(req *model.ChatRequest) Response
+
+// Provider 确定性的 Mock 提供商
+//
+// 用于离线开发和测试，不访问网络：
+//   - 按顺序返回 Enqueue 的脚本化响应
+//   - 脚本耗尽后调用 Handler（如果设置）
+//   - 否则回显最后一条用户消息
+//   - 嵌入向量由分词哈希生成，相同文本得到相同向量
+type Provider struct {
+	mu       sync.Mutex
+	name     string
+	script   []Response
+	handler  Handler
+	requests []model.ChatRequest
+}
+
+var _ provider.Provider = (*Provider)(nil)
+
+// New 创建 Mock 提供商
+func New() *Provider {
+	return &Provider{name: Name}
+}
+
+// NewNamed 创建指定名称的 Mock 提供商（用于模拟多个提供商）
+func NewNamed(name string) *Provider {
+	return &Provider{name: name}
+}
+
+// Name 返回提供商名称
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Enqueue 追加脚本化响应（按调用顺序消费）
+func (p *Provider) Enqueue(responses ...Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.script = append(p.script, responses...)
+}
+
+// SetHandler 设置动态响应处理器
+func (p *Provider) SetHandler(h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handler = h
+}
+
+// Requests 返回收到的所有对话请求（副本）
+func (p *Provider) Requests() []model.ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	requests := make([]model.ChatRequest, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+// Reset 清空脚本、处理器和请求记录
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.script = nil
+	p.handler = nil
+	p.requests = nil
+}
+
+// Chat 对话补全（非流式）
+func (p *Provider) Chat(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error) {
+	resp := p.next(req)
+	if err := sleep(ctx, resp.Delay); err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+
+	return &model.ChatResponse{
+		ID:       fmt.Sprintf("mock-%d", len(p.Requests())),
+		Provider: p.name,
+		Model:    req.Model,
+		Message: model.Message{
+			Role:      model.RoleAssistant,
+			Content:   resp.Content,
+			ToolCalls: resp.ToolCalls,
+		},
+		FinishReason: resp.FinishReason,
+		Usage:        *resp.Usage,
+	}, nil
+}
+
+// ChatStream 对话补全（流式，按单词切分片段）
+func (p *Provider) ChatStream(ctx context.Context, req *model.ChatRequest) (provider.ChatStream, error) {
+	resp := p.next(req)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+
+	var chunks []*model.StreamChunk
+	for _, piece := range splitWords(resp.Content) {
+		chunks = append(chunks, &model.StreamChunk{Delta: piece})
+	}
+	chunks = append(chunks, &model.StreamChunk{
+		ToolCalls:    resp.ToolCalls,
+		FinishReason: resp.FinishReason,
+		Usage:        resp.Usage,
+	})
+
+	return &stream{ctx: ctx, chunks: chunks, delay: resp.Delay}, nil
+}
+
+// Embed 向量嵌入（分词哈希，确定性）
+func (p *Provider) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	vectors := make([][]float32, len(req.Input))
+	tokens := 0
+	for i, text := range req.Input {
+		vectors[i] = Embedding(text)
+		tokens += CountTokens(text)
+	}
+
+	return &model.EmbeddingResponse{
+		Provider: p.name,
+		Model:    req.Model,
+		Vectors:  vectors,
+		Usage:    model.Usage{InputTokens: tokens},
+	}, nil
+}
+
+// next 记录请求并取出下一个响应（补全默认值）
+func (p *Provider) next(req *model.ChatRequest) Response {
+	p.mu.Lock()
+	p.requests = append(p.requests, *req)
+
+	var resp Response
+	switch {
+	case len(p.script) > 0:
+		resp = p.script[0]
+		p.script = p.script[1:]
+		p.mu.Unlock()
+	case p.handler != nil:
+		handler := p.handler
+		p.mu.Unlock()
+		resp = handler(req)
+	default:
+		p.mu.Unlock()
+		resp = Response{Content: "Mock reply: " + lastUserMessage(req.Messages)}
+	}
+
+	if resp.FinishReason == "" {
+		resp.FinishReason = model.FinishReasonStop
+		if len(resp.ToolCalls) > 0 {
+			resp.FinishReason = model.FinishReasonToolCalls
+		}
+	}
+	if resp.Usage == nil {
+		input := 0
+		for _, m := range req.Messages {
+			input += CountTokens(m.Content)
+		}
+		resp.Usage = &model.Usage{InputTokens: input, OutputTokens: CountTokens(resp.Content)}
+	}
+	return resp
+}
+
+// stream Mock 流式响应
+type stream struct {
+	ctx    context.Context
+	chunks []*model.StreamChunk
+	delay  time.Duration
+	closed bool
+}
+
+// Recv 返回下一个片段
+func (s *stream) Recv() (*model.StreamChunk, error) {
+	if s.closed || len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	if err := sleep(s.ctx, s.delay); err != nil {
+		return nil, err
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+// Close 关闭流
+func (s *stream) Close() error {
+	s.closed = true
+	return nil
+}
+
+// CountTokens 按空白分词估算 Token 数
+func CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+// Embedding 生成确定性的归一化向量
+//
+// 每个（小写）单词哈希到一个维度，因此共享单词越多的文本余弦相似度越高。
+func Embedding(text string) []float32 {
+	vector := make([]float32, EmbeddingDimensions)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(word))
+		vector[h.Sum32()%EmbeddingDimensions]++
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v * v)
+	}
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / norm)
+	}
+	return vector
+}
+
+// splitWords 按单词切分文本，保留单词后的空白（拼接后与原文一致）
+func splitWords(text string) []string {
+	var pieces []string
+	start := 0
+	for i := 1; i < len(text); i++ {
+		if text[i-1] == ' ' && text[i] != ' ' {
+			pieces = append(pieces, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// lastUserMessage 返回最后一条用户消息的内容
+func lastUserMessage(messages []model.Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == model.RoleUser {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// sleep 等待 d，ctx 取消时提前返回
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userRequest(content string) *model.ChatRequest {
+	return &model.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []model.Message{{Role: model.RoleUser, Content: content}},
+	}
+}
+
+func TestProvider_Chat_Echo(t *testing.T) {
+	p := New()
+
+	resp, err := p.Chat(context.Background(), userRequest("hello mock world"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mock reply: hello mock world", resp.Message.Content)
+	assert.Equal(t, model.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, model.Usage{InputTokens: 3, OutputTokens: 5}, resp.Usage)
+	require.Len(t, p.Requests(), 1)
+}
+
+func TestProvider_Chat_Script(t *testing.T) {
+	p := New()
+	failure := errors.New("boom")
+	p.Enqueue(
+		Response{Content: "first"},
+		Response{Err: failure},
+		Response{ToolCalls: []model.ToolCall{{ID: "call_1", Name: "list_tasks", Arguments: "{}"}}},
+	)
+	p.SetHandler(func(req *model.ChatRequest) Response {
+		return Response{Content: "from handler"}
+	})
+
+	resp, err := p.Chat(context.Background(), userRequest("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "first", resp.Message.Content)
+
+	_, err = p.Chat(context.Background(), userRequest("b"))
+	assert.ErrorIs(t, err, failure)
+
+	resp, err = p.Chat(context.Background(), userRequest("c"))
+	require.NoError(t, err)
+	assert.Equal(t, model.FinishReasonToolCalls, resp.FinishReason)
+
+	resp, err = p.Chat(context.Background(), userRequest("d"))
+	require.NoError(t, err)
+	assert.Equal(t, "from handler", resp.Message.Content)
+}
+
+func TestProvider_ChatStream(t *testing.T) {
+	p := New()
+	p.Enqueue(Response{Content: "one two  three"})
+
+	stream, err := p.ChatStream(context.Background(), userRequest("count"))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var deltas []string
+	var usage *model.Usage
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		if chunk.Delta != "" {
+			deltas = append(deltas, chunk.Delta)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+	}
+
+	assert.Equal(t, []string{"one ", "two  ", "three"}, deltas)
+	require.NotNil(t, usage)
+	assert.Equal(t, 3, usage.OutputTokens)
+}
+
+func TestProvider_ChatStream_Cancel(t *testing.T) {
+	p := New()
+	p.Enqueue(Response{Content: "slow stream", Delay: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := p.ChatStream(ctx, userRequest("x"))
+	require.NoError(t, err)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestProvider_Embed_Deterministic(t *testing.T) {
+	p := New()
+
+	resp, err := p.Embed(context.Background(), &model.EmbeddingRequest{
+		Input: []string{"buy milk", "Buy Milk", "write report"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Vectors, 3)
+	assert.Len(t, resp.Vectors[0], EmbeddingDimensions)
+	assert.Equal(t, resp.Vectors[0], resp.Vectors[1])
+	assert.NotEqual(t, resp.Vectors[0], resp.Vectors[2])
+}
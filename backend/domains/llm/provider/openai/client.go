@@ -0,0 +1,293 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+)
+
+// DefaultBaseURL OpenAI 官方 API 地址
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// Config OpenAI 兼容客户端配置
+type Config struct {
+	Name       string        // 提供商名称（默认 "openai"，兼容接口可使用其他名称，如 "local"）
+	BaseURL    string        // API 地址（默认 DefaultBaseURL）
+	APIKey     string        // API Key（本地模型可为空）
+	Timeout    time.Duration // 单次请求超时；流式请求为等待响应头的超时
+	MaxRetries int           // 可重试错误的最大重试次数（不含首次请求）
+	HTTPClient *http.Client  // 自定义 HTTP 客户端（为空时使用默认客户端）
+
+	RetryBaseDelay time.Duration // 第一次重试的退避上限（0 使用 provider.DefaultRetryBaseDelay）
+	RetryMaxDelay  time.Duration // 单次重试等待的上限（0 使用 provider.DefaultRetryMaxDelay）
+}
+
+// Client OpenAI 兼容接口客户端
+//
+// 支持 OpenAI 以及所有兼容 /chat/completions 和 /embeddings 接口的服务
+// （vLLM、Ollama、LM Studio 等）。
+type Client struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	timeout    time.Duration
+	retry      provider.RetryPolicy
+	httpClient *http.Client
+	backoff    func(attempt int) time.Duration // 第 attempt 次重试前的退避时间（测试时可替换）
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// New 创建 OpenAI 兼容客户端
+func New(cfg Config) *Client {
+	name := cfg.Name
+	if name == "" {
+		name = "openai"
+	}
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	maxRetries := cfg.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	retry := provider.RetryPolicy{MaxRetries: maxRetries, BaseDelay: cfg.RetryBaseDelay, MaxDelay: cfg.RetryMaxDelay}
+
+	return &Client{
+		name:       name,
+		baseURL:    baseURL,
+		apiKey:     cfg.APIKey,
+		timeout:    cfg.Timeout,
+		retry:      retry,
+		httpClient: httpClient,
+		backoff:    retry.Backoff,
+	}
+}
+
+// Name 返回提供商名称
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Chat 对话补全（非流式）
+func (c *Client) Chat(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error) {
+	body, err := json.Marshal(toWireChatRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp wireChatResponse
+	if err := c.doJSON(ctx, "/chat/completions", body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, &provider.Error{Provider: c.name, Message: "响应中没有 choices"}
+	}
+
+	choice := resp.Choices[0]
+	return &model.ChatResponse{
+		ID:           resp.ID,
+		Provider:     c.name,
+		Model:        resp.Model,
+		Message:      choice.Message.toModel(),
+		FinishReason: model.FinishReason(choice.FinishReason),
+		Usage:        resp.Usage.toModel(),
+	}, nil
+}
+
+// ChatStream 对话补全（流式，Server-Sent Events）
+//
+// Timeout 只约束等待响应头的时间；响应开始后流的生命周期由 ctx 控制。
+// 重试只发生在响应开始之前。
+func (c *Client) ChatStream(ctx context.Context, req *model.ChatRequest) (provider.ChatStream, error) {
+	body, err := json.Marshal(toWireChatRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt, lastErr); err != nil {
+				return nil, err
+			}
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		var timer *time.Timer
+		if c.timeout > 0 {
+			timer = time.AfterFunc(c.timeout, cancel)
+		}
+
+		httpResp, err := c.send(streamCtx, "/chat/completions", body)
+		timedOut := timer != nil && !timer.Stop()
+		if err == nil && timedOut {
+			// 响应头到达的同时超时触发，流已被取消
+			httpResp.Body.Close()
+			err = context.Canceled
+		}
+		if err != nil {
+			cancel()
+			if timedOut {
+				err = &provider.Error{Provider: c.name, Message: "等待响应超时", Retryable: true}
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		return newSSEStream(httpResp.Body, cancel), nil
+	}
+}
+
+// Embed 向量嵌入
+func (c *Client) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	body, err := json.Marshal(wireEmbeddingRequest{Model: req.Model, Input: req.Input})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp wireEmbeddingResponse
+	if err := c.doJSON(ctx, "/embeddings", body, &resp); err != nil {
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(req.Input))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+
+	return &model.EmbeddingResponse{
+		Provider: c.name,
+		Model:    resp.Model,
+		Vectors:  vectors,
+		Usage:    resp.Usage.toModel(),
+	}, nil
+}
+
+// doJSON 发送 JSON 请求并解析响应（带超时和重试）
+func (c *Client) doJSON(ctx context.Context, path string, body []byte, out interface{}) error {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt, lastErr); err != nil {
+				return err
+			}
+		}
+
+		lastErr = c.doOnce(ctx, path, body, out)
+		if lastErr == nil || ctx.Err() != nil {
+			return lastErr
+		}
+	}
+}
+
+// doOnce 发送单次请求（受 Timeout 约束）
+func (c *Client) doOnce(ctx context.Context, path string, body []byte, out interface{}) error {
+	reqCtx := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	httpResp, err := c.send(reqCtx, path, body)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return &provider.Error{Provider: c.name, Message: "请求超时", Retryable: true}
+		}
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if err := json.NewDecoder(httpResp.Body).Decode(out); err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return &provider.Error{Provider: c.name, Message: "请求超时", Retryable: true}
+		}
+		return &provider.Error{Provider: c.name, Message: fmt.Sprintf("解析响应失败: %v", err)}
+	}
+	return nil
+}
+
+// send 发送 HTTP 请求，非 2xx 响应转换为 *provider.Error
+func (c *Client) send(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &provider.Error{Provider: c.name, Message: err.Error(), Retryable: true}
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		defer httpResp.Body.Close()
+		return nil, c.statusError(httpResp)
+	}
+	return httpResp, nil
+}
+
+// statusError 将非 2xx 响应转换为 *provider.Error
+func (c *Client) statusError(resp *http.Response) *provider.Error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	message := strings.TrimSpace(string(data))
+	var wireErr wireErrorResponse
+	if json.Unmarshal(data, &wireErr) == nil && wireErr.Error.Message != "" {
+		message = wireErr.Error.Message
+	}
+
+	return &provider.Error{
+		Provider:   c.name,
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		Retryable:  provider.RetryableStatus(resp.StatusCode),
+		RetryAfter: provider.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// wait 在第 attempt 次重试前等待
+//
+// 按重试策略不应重试（次数用完、错误不可重试、Retry-After 过长）时返回 lastErr。
+func (c *Client) wait(ctx context.Context, attempt int, lastErr error) error {
+	delay, ok := c.retry.Delay(attempt, lastErr, c.backoff)
+	if !ok {
+		return lastErr
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient 创建指向测试服务器的客户端（重试不等待）
+func newTestClient(t *testing.T, handler http.HandlerFunc, timeout time.Duration, maxRetries int) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := New(Config{
+		BaseURL:    server.URL,
+		APIKey:     "sk-test",
+		Timeout:    timeout,
+		MaxRetries: maxRetries,
+	})
+	client.backoff = func(int) time.Duration { return 0 }
+	return client
+}
+
+func chatRequest() *model.ChatRequest {
+	return &model.ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []model.Message{{Role: model.RoleUser, Content: "Hello"}},
+	}
+}
+
+func TestClient_Chat(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+
+		var body wireChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "gpt-4o", body.Model)
+		assert.False(t, body.Stream)
+		require.Len(t, body.Messages, 1)
+		assert.Equal(t, "user", body.Messages[0].Role)
+
+		fmt.Fprint(w, `{"id":"chatcmpl-1","model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"Hi!"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`)
+	}, time.Second, 0)
+
+	resp, err := client.Chat(context.Background(), chatRequest())
+
+	require.NoError(t, err)
+	assert.Equal(t, "openai", resp.Provider)
+	assert.Equal(t, "Hi!", resp.Message.Content)
+	assert.Equal(t, model.RoleAssistant, resp.Message.Role)
+	assert.Equal(t, model.FinishReasonStop, resp.FinishReason)
+	assert.Equal(t, model.Usage{InputTokens: 5, OutputTokens: 2}, resp.Usage)
+}
+
+func TestClient_Chat_RetriesRetryableErrors(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"message":"rate limited"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	}, time.Second, 2)
+
+	resp, err := client.Chat(context.Background(), chatRequest())
+
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Message.Content)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Chat_MaxRetriesExhausted(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, time.Second, 1)
+
+	_, err := client.Chat(context.Background(), chatRequest())
+
+	var perr *provider.Error
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, http.StatusServiceUnavailable, perr.StatusCode)
+	assert.True(t, perr.Retryable)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Chat_RetryAfterTooLong(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, time.Second, 3)
+
+	_, err := client.Chat(context.Background(), chatRequest())
+
+	// 超过重试等待上限：不等待，直接返回（由 LLMService 回退到其他提供商）
+	var perr *provider.Error
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, time.Hour, perr.RetryAfter)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Chat_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key"}}`)
+	}, time.Second, 3)
+
+	_, err := client.Chat(context.Background(), chatRequest())
+
+	var perr *provider.Error
+	require.True(t, errors.As(err, &perr))
+	assert.False(t, perr.Retryable)
+	assert.Equal(t, "invalid api key", perr.Message)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Chat_Timeout(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	}, 50*time.Millisecond, 1)
+
+	_, err := client.Chat(context.Background(), chatRequest())
+
+	var perr *provider.Error
+	require.True(t, errors.As(err, &perr))
+	assert.Contains(t, perr.Message, "超时")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_ChatStream(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body wireChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.True(t, body.Stream)
+		require.NotNil(t, body.StreamOptions)
+		assert.True(t, body.StreamOptions.IncludeUsage)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"function\":{\"name\":\"list_tasks\",\"arguments\":\"{\\\"sta\"}}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"tus\\\":1}\"}}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"tool_calls\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":2}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}, time.Second, 0)
+
+	stream, err := client.ChatStream(context.Background(), chatRequest())
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content string
+	var last []*model.StreamChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content += chunk.Delta
+		last = append(last, chunk)
+	}
+
+	assert.Equal(t, "Hello", content)
+	require.Len(t, last, 4)
+	assert.Equal(t, model.FinishReasonToolCalls, last[2].FinishReason)
+	assert.Equal(t, []model.ToolCall{{ID: "call_1", Name: "list_tasks", Arguments: `{"status":1}`}}, last[2].ToolCalls)
+	require.NotNil(t, last[3].Usage)
+	assert.Equal(t, 5, last[3].Usage.InputTokens)
+}
+
+func TestClient_ChatStream_UnexpectedEOF(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n")
+	}, time.Second, 0)
+
+	stream, err := client.ChatStream(context.Background(), chatRequest())
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestClient_Embed(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		// 乱序返回，客户端按 index 还原
+		fmt.Fprint(w, `{"model":"text-embedding-3-small","data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":4}}`)
+	}, time.Second, 0)
+
+	resp, err := client.Embed(context.Background(), &model.EmbeddingRequest{
+		Model: "text-embedding-3-small",
+		Input: []string{"a", "b"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, resp.Vectors)
+	assert.Equal(t, 4, resp.Usage.InputTokens)
+}
@@ -0,0 +1,156 @@
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// maxSSELineSize 单行 SSE 数据的最大长度
+const maxSSELineSize = 1024 * 1024
+
+// sseStream 解析 OpenAI 兼容接口的 SSE 响应
+//
+// 格式：
+//
+//	data: {"choices":[{"delta":{"content":"Hel"}}]}
+//	data: {"choices":[{"delta":{},"finish_reason":"stop"}]}
+//	data: {"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":2}}
+//	data: [DONE]
+//
+// 工具调用的参数在多个片段中增量返回，stream 会按 index 累积，
+// 在 finish_reason 所在的片段中一次性返回完整的 ToolCalls。
+type sseStream struct {
+	body      io.ReadCloser
+	scanner   *bufio.Scanner
+	cancel    context.CancelFunc
+	toolCalls map[int]*model.ToolCall
+	done      bool
+}
+
+func newSSEStream(body io.ReadCloser, cancel context.CancelFunc) *sseStream {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+	return &sseStream{
+		body:      body,
+		scanner:   scanner,
+		cancel:    cancel,
+		toolCalls: make(map[int]*model.ToolCall),
+	}
+}
+
+// Recv 读取下一个片段，流结束时返回 io.EOF
+func (s *sseStream) Recv() (*model.StreamChunk, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	for s.scanner.Scan() {
+		line := s.scanner.Bytes()
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			// 空行、注释（": keep-alive"）和 event/id 字段忽略
+			continue
+		}
+		data := bytes.TrimSpace(line[len("data:"):])
+		if bytes.Equal(data, []byte("[DONE]")) {
+			s.done = true
+			return nil, io.EOF
+		}
+
+		var resp wireStreamResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, err
+		}
+
+		chunk := s.toChunk(&resp)
+		if chunk == nil {
+			continue
+		}
+		return chunk, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	// 连接在 [DONE] 之前关闭
+	s.done = true
+	return nil, io.ErrUnexpectedEOF
+}
+
+// toChunk wire 片段 → 领域片段（没有有效内容时返回 nil）
+func (s *sseStream) toChunk(resp *wireStreamResponse) *model.StreamChunk {
+	chunk := &model.StreamChunk{}
+	hasContent := false
+
+	if resp.Usage != nil {
+		usage := resp.Usage.toModel()
+		chunk.Usage = &usage
+		hasContent = true
+	}
+
+	for _, choice := range resp.Choices {
+		if choice.Delta.Content != "" {
+			chunk.Delta += choice.Delta.Content
+			hasContent = true
+		}
+		for i, tc := range choice.Delta.ToolCalls {
+			index := i
+			if tc.Index != nil {
+				index = *tc.Index
+			}
+			pending, ok := s.toolCalls[index]
+			if !ok {
+				pending = &model.ToolCall{}
+				s.toolCalls[index] = pending
+			}
+			if tc.ID != "" {
+				pending.ID = tc.ID
+			}
+			if tc.Function.Name != "" {
+				pending.Name = tc.Function.Name
+			}
+			pending.Arguments += tc.Function.Arguments
+		}
+		if choice.FinishReason != "" {
+			chunk.FinishReason = model.FinishReason(choice.FinishReason)
+			chunk.ToolCalls = s.flushToolCalls()
+			hasContent = true
+		}
+	}
+
+	if !hasContent {
+		return nil
+	}
+	return chunk
+}
+
+// flushToolCalls 按 index 顺序返回累积的工具调用
+func (s *sseStream) flushToolCalls() []model.ToolCall {
+	if len(s.toolCalls) == 0 {
+		return nil
+	}
+	indexes := make([]int, 0, len(s.toolCalls))
+	for index := range s.toolCalls {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	calls := make([]model.ToolCall, 0, len(indexes))
+	for _, index := range indexes {
+		calls = append(calls, *s.toolCalls[index])
+	}
+	s.toolCalls = make(map[int]*model.ToolCall)
+	return calls
+}
+
+// Close 关闭流并释放连接
+func (s *sseStream) Close() error {
+	s.done = true
+	s.cancel()
+	return s.body.Close()
+}
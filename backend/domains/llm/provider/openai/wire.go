@@ -0,0 +1,193 @@
+package openai
+
+import (
+	"encoding/json"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// 本文件定义 OpenAI 兼容接口的请求/响应结构（wire format），
+// 以及与领域模型之间的转换。
+
+type wireChatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []wireMessage       `json:"messages"`
+	Temperature    *float64            `json:"temperature,omitempty"`
+	TopP           *float64            `json:"top_p,omitempty"`
+	MaxTokens      int                 `json:"max_tokens,omitempty"`
+	Stop           []string            `json:"stop,omitempty"`
+	Tools          []wireTool          `json:"tools,omitempty"`
+	ResponseFormat *wireResponseFormat `json:"response_format,omitempty"`
+	User           string              `json:"user,omitempty"`
+	Stream         bool                `json:"stream,omitempty"`
+	StreamOptions  *wireStreamOptions  `json:"stream_options,omitempty"`
+}
+
+type wireStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type wireMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	Name       string         `json:"name,omitempty"`
+	ToolCalls  []wireToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type wireToolCall struct {
+	Index    *int             `json:"index,omitempty"` // 仅流式响应
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function wireFunctionCall `json:"function"`
+}
+
+type wireFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type wireTool struct {
+	Type     string          `json:"type"`
+	Function wireFunctionDef `json:"function"`
+}
+
+type wireFunctionDef struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type wireResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema *wireJSONSchema `json:"json_schema,omitempty"`
+}
+
+type wireJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+	Strict bool            `json:"strict"`
+}
+
+type wireChatResponse struct {
+	ID      string       `json:"id"`
+	Model   string       `json:"model"`
+	Choices []wireChoice `json:"choices"`
+	Usage   wireUsage    `json:"usage"`
+}
+
+type wireChoice struct {
+	Message      wireMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type wireStreamResponse struct {
+	Choices []wireStreamChoice `json:"choices"`
+	Usage   *wireUsage         `json:"usage"`
+}
+
+type wireStreamChoice struct {
+	Delta        wireMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type wireUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type wireEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type wireEmbeddingResponse struct {
+	Model string          `json:"model"`
+	Data  []wireEmbedding `json:"data"`
+	Usage wireUsage       `json:"usage"`
+}
+
+type wireEmbedding struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+type wireErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// toWireChatRequest 领域请求 → wire 请求
+func toWireChatRequest(req *model.ChatRequest, stream bool) *wireChatRequest {
+	wire := &wireChatRequest{
+		Model:       req.Model,
+		Messages:    make([]wireMessage, 0, len(req.Messages)),
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		MaxTokens:   req.MaxTokens,
+		Stop:        req.Stop,
+		User:        req.User,
+		Stream:      stream,
+	}
+	if stream {
+		wire.StreamOptions = &wireStreamOptions{IncludeUsage: true}
+	}
+
+	for _, m := range req.Messages {
+		wm := wireMessage{
+			Role:       string(m.Role),
+			Content:    m.Content,
+			Name:       m.Name,
+			ToolCallID: m.ToolCallID,
+		}
+		for _, tc := range m.ToolCalls {
+			wm.ToolCalls = append(wm.ToolCalls, wireToolCall{
+				ID:       tc.ID,
+				Type:     "function",
+				Function: wireFunctionCall{Name: tc.Name, Arguments: tc.Arguments},
+			})
+		}
+		wire.Messages = append(wire.Messages, wm)
+	}
+
+	for _, t := range req.Tools {
+		wire.Tools = append(wire.Tools, wireTool{
+			Type:     "function",
+			Function: wireFunctionDef{Name: t.Name, Description: t.Description, Parameters: t.Parameters},
+		})
+	}
+
+	if rf := req.ResponseFormat; rf != nil {
+		wire.ResponseFormat = &wireResponseFormat{Type: rf.Type}
+		if rf.Type == "json_schema" {
+			wire.ResponseFormat.JSONSchema = &wireJSONSchema{Name: rf.Name, Schema: rf.Schema, Strict: true}
+		}
+	}
+
+	return wire
+}
+
+// toModel wire 消息 → 领域消息
+func (m wireMessage) toModel() model.Message {
+	msg := model.Message{
+		Role:       model.Role(m.Role),
+		Content:    m.Content,
+		Name:       m.Name,
+		ToolCallID: m.ToolCallID,
+	}
+	for _, tc := range m.ToolCalls {
+		msg.ToolCalls = append(msg.ToolCalls, model.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+		})
+	}
+	return msg
+}
+
+// toModel wire 用量 → 领域用量
+func (u wireUsage) toModel() model.Usage {
+	return model.Usage{InputTokens: u.PromptTokens, OutputTokens: u.CompletionTokens}
+}
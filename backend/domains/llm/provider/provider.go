@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// Provider 模型提供商接口
+//
+// 每个实现对应一个后端（OpenAI 兼容接口、本地模型、Mock 等）。
+// 实现必须是并发安全的。
+type Provider interface {
+	// Name 返回提供商名称（如 "openai"、"mock"）
+	Name() string
+
+	// Chat 对话补全（非流式）
+	Chat(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error)
+
+	// ChatStream 对话补全（流式）
+	//
+	// 返回的 ChatStream 必须由调用方 Close。
+	ChatStream(ctx context.Context, req *model.ChatRequest) (ChatStream, error)
+
+	// Embed 向量嵌入
+	Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error)
+}
+
+// ChatStream 流式响应
+//
+// Recv 在流正常结束时返回 io.EOF；ctx 取消时返回 ctx.Err()。
+type ChatStream interface {
+	Recv() (*model.StreamChunk, error)
+	Close() error
+}
+
+// Error 提供商调用错误
+//
+// 用于区分可重试错误（限流、5xx、网络错误）和不可重试错误（4xx）。
+type Error struct {
+	Provider   string
+	StatusCode int           // HTTP 状态码（网络错误时为 0）
+	Message    string        // 提供商返回的错误信息
+	Retryable  bool          // 是否可以重试
+	RetryAfter time.Duration // 提供商建议的重试等待时间（0 表示未指定）
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.StatusCode > 0 {
+		return fmt.Sprintf("PROVIDER_ERROR: %s 调用失败 (status %d): %s", e.Provider, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("PROVIDER_ERROR: %s 调用失败: %s", e.Provider, e.Message)
+}
+
+// Registry 提供商注册表
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+// NewRegistry 创建提供商注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+	}
+}
+
+// Register 注册提供商（同名覆盖）
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+}
+
+// Get 获取提供商
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", model.ErrProviderNotFound, name)
+	}
+	return p, nil
+}
+
+// Has 判断提供商是否已注册
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.providers[name]
+	return ok
+}
+
+// Names 返回已注册的提供商名称（已排序）
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
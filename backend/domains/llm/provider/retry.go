@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 重试策略的默认值
+const (
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+)
+
+// RetryPolicy 提供商调用的重试策略
+//
+// 只重试可重试错误（Error.Retryable：超时、限流、网关和服务暂时不可用、网络错误），
+// 这些错误重发同一请求是安全的；4xx（408、425、429 除外）、501 等错误重试也不会成功。
+//
+// 等待时间为指数退避加随机抖动（full jitter）：第 n 次重试在 [0, min(MaxDelay, BaseDelay·2^(n-1))] 中随机，
+// 避免多个实例同时重试。提供商返回 Retry-After 时优先使用；Retry-After 超过 MaxDelay 时不再重试，
+// 由调用方尽快回退到其他提供商。
+type RetryPolicy struct {
+	MaxRetries int           // 最大重试次数（不含首次请求）
+	BaseDelay  time.Duration // 第一次重试的退避上限（0 使用 DefaultRetryBaseDelay）
+	MaxDelay   time.Duration // 单次等待的上限（0 使用 DefaultRetryMaxDelay）
+}
+
+// Backoff 第 attempt 次重试（从 1 开始）前的退避时间
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	base, limit := p.baseDelay(), p.maxDelay()
+	ceiling := limit
+	if attempt < 1 {
+		attempt = 1
+	}
+	if shift := attempt - 1; shift < 32 && base<<shift > 0 && base<<shift < limit {
+		ceiling = base << shift
+	}
+	return rand.N(ceiling + 1)
+}
+
+// Delay 返回第 attempt 次重试前的等待时间，不应重试时返回 false
+//
+// backoff 为退避函数（通常是 Backoff，测试时可替换）。
+func (p RetryPolicy) Delay(attempt int, err error, backoff func(attempt int) time.Duration) (time.Duration, bool) {
+	var perr *Error
+	if attempt > p.MaxRetries || !errors.As(err, &perr) || !perr.Retryable {
+		return 0, false
+	}
+	if perr.RetryAfter > 0 {
+		if perr.RetryAfter > p.maxDelay() {
+			return 0, false
+		}
+		return perr.RetryAfter, true
+	}
+	return backoff(attempt), true
+}
+
+func (p RetryPolicy) baseDelay() time.Duration {
+	if p.BaseDelay > 0 {
+		return p.BaseDelay
+	}
+	return DefaultRetryBaseDelay
+}
+
+func (p RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay > 0 {
+		return p.MaxDelay
+	}
+	return DefaultRetryMaxDelay
+}
+
+// RetryableStatus 判断 HTTP 状态码是否可重试
+//
+// 408 请求超时、425 Too Early、429 限流、500 / 502 / 503 / 504 服务端暂时错误。
+func RetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// ParseRetryAfter 解析 Retry-After 头（秒数或 HTTP 日期），无效或已过期时返回 0
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
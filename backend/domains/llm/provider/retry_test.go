@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, p.Backoff(1), 100*time.Millisecond)
+		assert.LessOrEqual(t, p.Backoff(3), 400*time.Millisecond)
+		assert.LessOrEqual(t, p.Backoff(60), time.Second)
+		assert.GreaterOrEqual(t, p.Backoff(2), time.Duration(0))
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 2, MaxDelay: 10 * time.Second}
+	backoff := func(attempt int) time.Duration { return time.Duration(attempt) * time.Millisecond }
+
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		delay   time.Duration
+		retry   bool
+	}{
+		{"可重试错误使用退避", 1, &Error{StatusCode: 503, Retryable: true}, time.Millisecond, true},
+		{"优先使用 Retry-After", 2, &Error{StatusCode: 429, Retryable: true, RetryAfter: 3 * time.Second}, 3 * time.Second, true},
+		{"Retry-After 过长时不重试", 1, &Error{StatusCode: 429, Retryable: true, RetryAfter: time.Minute}, 0, false},
+		{"次数用完", 3, &Error{StatusCode: 503, Retryable: true}, 0, false},
+		{"不可重试的错误", 1, &Error{StatusCode: 400}, 0, false},
+		{"非提供商错误", 1, errors.New("boom"), 0, false},
+	}
+	for _, tt := range tests {
+		delay, retry := p.Delay(tt.attempt, tt.err, backoff)
+		assert.Equal(t, tt.retry, retry, tt.name)
+		assert.Equal(t, tt.delay, delay, tt.name)
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	for _, code := range []int{408, 425, 429, 500, 502, 503, 504} {
+		assert.True(t, RetryableStatus(code), code)
+	}
+	for _, code := range []int{400, 401, 403, 404, 422, 501, 505} {
+		assert.False(t, RetryableStatus(code), code)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 5*time.Second, ParseRetryAfter("5", now))
+	assert.Equal(t, 90*time.Second, ParseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), ParseRetryAfter("", now))
+}
@@ -0,0 +1,99 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+)
+
+// Document 检索到的资料
+//
+// (Source, ID) 唯一标识一份资料，模型回答时以 [source:id] 引用。
+type Document struct {
+	Source  string  // 资料类型（如 task）
+	ID      string  // 资料 ID
+	Title   string  // 标题（返回给客户端用于显示引用）
+	Content string  // 放入提示词的内容
+	Score   float64 // 相关度（越大越相关，只用于排序和日志）
+}
+
+// Ref 引用标记（如 [task:0b5e...]）
+func (d Document) Ref() string {
+	return "[" + d.Source + ":" + d.ID + "]"
+}
+
+// Format 放入提示词的文本（第一行为引用标记和标题）
+func (d Document) Format() string {
+	text := d.Ref() + " " + d.Title
+	if d.Content != "" {
+		text += "\n" + d.Content
+	}
+	return text
+}
+
+// Retriever 按用户的问题检索资料
+//
+// 只返回 userID 的资料，按相关度降序，最多 limit 份。
+type Retriever interface {
+	Retrieve(ctx context.Context, userID, query string, limit int) ([]Document, error)
+}
+
+// EstimateTokens 估算文本的 Token 数（tokenizer.Estimator，偏保守）
+func EstimateTokens(text string) int {
+	return tokenizer.Estimator{}.Count(text)
+}
+
+// Pack 按顺序选出总 Token 数不超过 budget 的资料
+//
+// 放不下的资料跳过（后面更短的资料仍可能放下），返回的资料保持原顺序。
+func Pack(docs []Document, budget int) []Document {
+	packed := make([]Document, 0, len(docs))
+	used := 0
+	for _, doc := range docs {
+		cost := EstimateTokens(doc.Format()) + 1 // 分隔的换行
+		if used+cost > budget {
+			continue
+		}
+		packed = append(packed, doc)
+		used += cost
+	}
+	return packed
+}
+
+// Join 将资料拼接为提示词中的资料部分（每份资料之间空一行）
+func Join(docs []Document) string {
+	parts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		parts = append(parts, doc.Format())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// refPattern 回答中的引用标记
+var refPattern = regexp.MustCompile(`\[([a-z_]+):([A-Za-z0-9_-]+)\]`)
+
+// Cited 返回回答中引用的资料（按第一次引用的顺序，去重）
+//
+// 只返回 docs 中存在的资料，模型编造的引用被忽略。
+func Cited(answer string, docs []Document) []Document {
+	byRef := make(map[string]Document, len(docs))
+	for _, doc := range docs {
+		byRef[doc.Ref()] = doc
+	}
+
+	cited := make([]Document, 0)
+	seen := make(map[string]bool)
+	for _, m := range refPattern.FindAllStringSubmatch(answer, -1) {
+		ref := fmt.Sprintf("[%s:%s]", m[1], m[2])
+		doc, ok := byRef[ref]
+		if !ok || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		cited = append(cited, doc)
+	}
+	return cited
+}
@@ -0,0 +1,60 @@
+package retrieval
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 3, EstimateTokens("hello world"))
+	assert.Equal(t, 4, EstimateTokens("预约牙医"))
+	assert.Equal(t, 4, EstimateTokens("预约 dentist"))
+}
+
+func TestPack(t *testing.T) {
+	docs := []Document{
+		{Source: "task", ID: "a", Title: "短任务"},
+		{Source: "task", ID: "b", Title: strings.Repeat("长", 100)},
+		{Source: "task", ID: "c", Title: "另一个短任务"},
+	}
+
+	// 放不下的资料跳过，后面的短资料仍然放入
+	packed := Pack(docs, 40)
+	assert.Equal(t, []string{"a", "c"}, ids(packed))
+
+	assert.Len(t, Pack(docs, 1000), 3)
+	assert.Empty(t, Pack(docs, 0))
+}
+
+func TestCited(t *testing.T) {
+	docs := []Document{
+		{Source: "task", ID: "a", Title: "A"},
+		{Source: "task", ID: "b", Title: "B"},
+		{Source: "task", ID: "c", Title: "C"},
+	}
+
+	answer := "先做 B [task:b]，然后是 A [task:a]。B 最紧急 [task:b]，另外 [task:zzz] 和 [note:a] 不存在。"
+	assert.Equal(t, []string{"b", "a"}, ids(Cited(answer, docs)))
+
+	assert.Empty(t, Cited("没有引用", docs))
+}
+
+func TestJoin(t *testing.T) {
+	docs := []Document{
+		{Source: "task", ID: "a", Title: "写周报", Content: "截止：周五"},
+		{Source: "task", ID: "b", Title: "交电费"},
+	}
+	assert.Equal(t, "[task:a] 写周报\n截止：周五\n\n[task:b] 交电费", Join(docs))
+}
+
+func ids(docs []Document) []string {
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		result = append(result, doc.ID)
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package router
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// Catalog 模型目录
+//
+// 路由器每次选择模型时读取目录，实现必须是并发安全的。
+type Catalog interface {
+	// Models 返回所有可路由的模型
+	Models(ctx context.Context) ([]model.ModelSpec, error)
+}
+
+// StaticCatalog 固定的模型目录（测试和不使用数据库时使用）
+//
+// 生产环境使用 catalog 领域的 CatalogService（llm_models 表，带内存缓存）。
+type StaticCatalog struct {
+	models []model.ModelSpec
+}
+
+// NewStaticCatalog 创建固定的模型目录
+func NewStaticCatalog(models ...model.ModelSpec) *StaticCatalog {
+	return &StaticCatalog{models: models}
+}
+
+// Models 返回目录中的模型（副本）
+func (c *StaticCatalog) Models(ctx context.Context) ([]model.ModelSpec, error) {
+	models := make([]model.ModelSpec, len(c.models))
+	copy(models, c.models)
+	return models, nil
+}
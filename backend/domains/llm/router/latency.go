@@ -0,0 +1,101 @@
+package router
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyWindow 每个模型保留的最近延迟样本数
+const DefaultLatencyWindow = 100
+
+// MinLatencySamples 使用观测延迟所需的最少样本数（不足时使用目录中的预估延迟）
+const MinLatencySamples = 5
+
+// LatencyStats 延迟统计
+type LatencyStats struct {
+	P50     time.Duration
+	P95     time.Duration
+	Samples int
+}
+
+// LatencyTracker 记录每个模型最近的调用延迟
+//
+// 每个模型保留最近 window 个样本（环形缓冲），并发安全。
+type LatencyTracker struct {
+	mu      sync.RWMutex
+	window  int
+	samples map[string]*latencyWindow
+}
+
+type latencyWindow struct {
+	values []time.Duration
+	next   int
+}
+
+// NewLatencyTracker 创建延迟记录器（window <= 0 时使用 DefaultLatencyWindow）
+func NewLatencyTracker(window int) *LatencyTracker {
+	if window <= 0 {
+		window = DefaultLatencyWindow
+	}
+	return &LatencyTracker{
+		window:  window,
+		samples: make(map[string]*latencyWindow),
+	}
+}
+
+// Observe 记录一次调用延迟
+func (t *LatencyTracker) Observe(provider, modelName string, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := modelKey(provider, modelName)
+	w, ok := t.samples[key]
+	if !ok {
+		w = &latencyWindow{values: make([]time.Duration, 0, t.window)}
+		t.samples[key] = w
+	}
+	if len(w.values) < t.window {
+		w.values = append(w.values, latency)
+		return
+	}
+	w.values[w.next] = latency
+	w.next = (w.next + 1) % t.window
+}
+
+// Stats 返回模型的延迟统计（没有样本时为零值）
+func (t *LatencyTracker) Stats(provider, modelName string) LatencyStats {
+	t.mu.RLock()
+	w, ok := t.samples[modelKey(provider, modelName)]
+	if !ok {
+		t.mu.RUnlock()
+		return LatencyStats{}
+	}
+	values := make([]time.Duration, len(w.values))
+	copy(values, w.values)
+	t.mu.RUnlock()
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return LatencyStats{
+		P50:     percentile(values, 0.50),
+		P95:     percentile(values, 0.95),
+		Samples: len(values),
+	}
+}
+
+// percentile 最近秩法计算百分位（values 已排序且非空）
+func percentile(values []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(values) {
+		rank = len(values) - 1
+	}
+	return values[rank]
+}
+
+func modelKey(provider, modelName string) string {
+	return provider + "/" + modelName
+}
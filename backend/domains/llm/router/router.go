@@ -0,0 +1,277 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultOutputTokens 请求未指定 MaxTokens 时预估的输出 Token 数（用于 cost 策略）
+const DefaultOutputTokens = 512
+
+// ReasonOverride 请求指定了提供商和模型时的选择原因
+const ReasonOverride = "请求指定了模型"
+
+// Selection 路由结果
+type Selection struct {
+	Provider string
+	Model    string
+	Strategy model.Strategy // 覆盖时为请求的策略（可能为空）
+	Reason   string
+}
+
+// Router 模型路由器
+//
+// 按策略从模型目录中为每个请求选择提供商和模型：
+//   - latency: 观测延迟 P95 最低（样本不足时使用目录中的预估延迟）
+//   - cost:    按输入长度和 MaxTokens 预估的费用最低
+//   - quality: 质量评分最高
+//   - random:  在候选中均匀随机
+//
+// 只有已注册的提供商、且具备请求需要的能力（工具调用、JSON 输出）的模型才是候选；
+// 请求只指定提供商时在该提供商的模型中选择。
+// 每次选择（包括请求覆盖）都发布 ModelSelected 事件。
+type Router struct {
+	catalog         Catalog
+	registry        *provider.Registry
+	latency         *LatencyTracker
+	defaultStrategy model.Strategy
+	eventBus        sharedevents.EventBus
+	intn            func(n int) int // random 策略的随机源（测试时替换）
+}
+
+// NewRouter 创建模型路由器
+//
+// 参数：
+//   - catalog: 模型目录
+//   - registry: 提供商注册表（过滤未注册的提供商）
+//   - defaultStrategy: 请求未指定策略时使用（为空表示不路由，使用默认模型）
+//   - eventBus: 事件总线（可为 nil，不发布事件）
+func NewRouter(catalog Catalog, registry *provider.Registry, defaultStrategy model.Strategy, eventBus sharedevents.EventBus) *Router {
+	return &Router{
+		catalog:         catalog,
+		registry:        registry,
+		latency:         NewLatencyTracker(DefaultLatencyWindow),
+		defaultStrategy: defaultStrategy,
+		eventBus:        eventBus,
+		intn:            rand.IntN,
+	}
+}
+
+// DefaultStrategy 返回默认路由策略
+func (r *Router) DefaultStrategy() model.Strategy {
+	return r.defaultStrategy
+}
+
+// Observe 记录一次成功调用的延迟（由 LLMService 在每次生成结束后调用）
+func (r *Router) Observe(providerName, modelName string, latency time.Duration) {
+	r.latency.Observe(providerName, modelName, latency)
+}
+
+// Latency 返回模型的观测延迟
+func (r *Router) Latency(providerName, modelName string) LatencyStats {
+	return r.latency.Stats(providerName, modelName)
+}
+
+// Route 为请求选择模型
+//
+// 返回 nil 表示不路由（请求和路由器都没有指定策略），由调用方填充默认值。
+// 不修改 req。
+func (r *Router) Route(ctx context.Context, req *model.ChatRequest) (*Selection, error) {
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = r.defaultStrategy
+	}
+
+	// 请求覆盖：提供商和模型都已指定
+	if req.Provider != "" && req.Model != "" {
+		sel := &Selection{Provider: req.Provider, Model: req.Model, Strategy: strategy, Reason: ReasonOverride}
+		r.publish(ctx, sel)
+		return sel, nil
+	}
+	if strategy == "" {
+		return nil, nil
+	}
+	if !strategy.IsValid() {
+		return nil, model.ErrInvalidStrategy
+	}
+
+	candidates, err := r.candidates(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec model.ModelSpec
+	var reason string
+	switch strategy {
+	case model.StrategyLatency:
+		spec, reason = r.byLatency(candidates)
+	case model.StrategyCost:
+		spec, reason = byCost(candidates, req)
+	case model.StrategyQuality:
+		spec, reason = byQuality(candidates, req)
+	case model.StrategyRandom:
+		spec = candidates[r.intn(len(candidates))]
+		reason = fmt.Sprintf("随机选择（%d 个候选）", len(candidates))
+	}
+
+	sel := &Selection{Provider: spec.Provider, Model: spec.Model, Strategy: strategy, Reason: reason}
+	r.publish(ctx, sel)
+	return sel, nil
+}
+
+// candidates 返回已注册提供商中支持该请求的模型（请求指定了提供商时只保留该提供商）
+func (r *Router) candidates(ctx context.Context, req *model.ChatRequest) ([]model.ModelSpec, error) {
+	providerName := req.Provider
+	models, err := r.catalog.Models(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 读取模型目录失败: %w", err)
+	}
+
+	candidates := make([]model.ModelSpec, 0, len(models))
+	for _, m := range models {
+		if providerName != "" && m.Provider != providerName {
+			continue
+		}
+		if !r.registry.Has(m.Provider) || !m.Supports(req) {
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+	if len(candidates) == 0 {
+		if providerName != "" {
+			return nil, fmt.Errorf("%w: %s", model.ErrNoModelAvailable, providerName)
+		}
+		return nil, model.ErrNoModelAvailable
+	}
+	return candidates, nil
+}
+
+// byLatency 选择 P95 延迟最低的模型（相同时选择更便宜的）
+//
+// 样本数达到 MinLatencySamples 时使用观测 P95，否则使用目录中的预估延迟；
+// 两者都没有的模型排在最后。
+func (r *Router) byLatency(candidates []model.ModelSpec) (model.ModelSpec, string) {
+	type ranked struct {
+		spec     model.ModelSpec
+		latency  time.Duration
+		observed bool
+		known    bool
+		samples  int
+	}
+
+	items := make([]ranked, len(candidates))
+	for i, c := range candidates {
+		stats := r.latency.Stats(c.Provider, c.Model)
+		item := ranked{spec: c, samples: stats.Samples}
+		switch {
+		case stats.Samples >= MinLatencySamples:
+			item.latency, item.observed, item.known = stats.P95, true, true
+		case c.TypicalLatencyMs > 0:
+			item.latency, item.known = time.Duration(c.TypicalLatencyMs)*time.Millisecond, true
+		}
+		items[i] = item
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.known != b.known {
+			return a.known
+		}
+		if a.latency != b.latency {
+			return a.latency < b.latency
+		}
+		return blendedPrice(a.spec) < blendedPrice(b.spec)
+	})
+
+	best := items[0]
+	switch {
+	case best.observed:
+		return best.spec, fmt.Sprintf("P95 延迟 %dms（%d 次调用）", best.latency.Milliseconds(), best.samples)
+	case best.known:
+		return best.spec, fmt.Sprintf("预估延迟 %dms（观测样本不足）", best.latency.Milliseconds())
+	default:
+		return best.spec, "没有延迟数据"
+	}
+}
+
+// byCost 选择预估费用最低的模型（相同时选择质量更高的）
+func byCost(candidates []model.ModelSpec, req *model.ChatRequest) (model.ModelSpec, string) {
+	inputTokens, outputTokens := estimateTokens(req)
+
+	sorted := append([]model.ModelSpec(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci := sorted[i].EstimateCost(inputTokens, outputTokens)
+		cj := sorted[j].EstimateCost(inputTokens, outputTokens)
+		if ci != cj {
+			return ci < cj
+		}
+		return sorted[i].Quality > sorted[j].Quality
+	})
+
+	best := sorted[0]
+	return best, fmt.Sprintf("预估费用 $%.6f（输入约 %d / 输出 %d tokens）",
+		best.EstimateCost(inputTokens, outputTokens), inputTokens, outputTokens)
+}
+
+// byQuality 选择质量评分最高的模型（相同时选择更便宜的）
+func byQuality(candidates []model.ModelSpec, req *model.ChatRequest) (model.ModelSpec, string) {
+	inputTokens, outputTokens := estimateTokens(req)
+
+	sorted := append([]model.ModelSpec(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Quality != sorted[j].Quality {
+			return sorted[i].Quality > sorted[j].Quality
+		}
+		return sorted[i].EstimateCost(inputTokens, outputTokens) < sorted[j].EstimateCost(inputTokens, outputTokens)
+	})
+
+	return sorted[0], fmt.Sprintf("质量评分 %d", sorted[0].Quality)
+}
+
+// estimateTokens 粗略估算输入和输出 Token 数
+//
+// 输入使用 tokenizer.CountRequest（与额度预占的预估一致）；
+// 输出使用 MaxTokens，未指定时使用 DefaultOutputTokens。
+func estimateTokens(req *model.ChatRequest) (int, int) {
+	input := tokenizer.CountRequest(tokenizer.Estimator{}, req)
+	output := req.MaxTokens
+	if output <= 0 {
+		output = DefaultOutputTokens
+	}
+	return input, output
+}
+
+// blendedPrice 输入输出价格之和（延迟相同时的次级排序）
+func blendedPrice(m model.ModelSpec) float64 {
+	return m.InputPrice + m.OutputPrice
+}
+
+// publish 发布 ModelSelected（失败只记录日志）
+func (r *Router) publish(ctx context.Context, sel *Selection) {
+	if r.eventBus == nil {
+		return
+	}
+	event := sharedevents.NewModelSelectedEvent(sharedevents.ModelSelectedPayload{
+		Model:    sel.Model,
+		Provider: sel.Provider,
+		Strategy: string(sel.Strategy),
+		Reason:   sel.Reason,
+	})
+	if err := r.eventBus.Publish(ctx, event); err != nil {
+		logger.Error("publish ModelSelected failed",
+			zap.String("provider", sel.Provider),
+			zap.String("model", sel.Model),
+			zap.Error(err),
+		)
+	}
+}
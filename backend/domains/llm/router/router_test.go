@@ -0,0 +1,235 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModels 测试用模型目录：fast 最快、cheap 最便宜、smart 质量最高，offline 的提供商未注册
+var testModels = []model.ModelSpec{
+	{Provider: "alpha", Model: "smart", InputPrice: 10, OutputPrice: 30, Quality: 9, TypicalLatencyMs: 3000},
+	{Provider: "alpha", Model: "cheap", InputPrice: 0.1, OutputPrice: 0.4, Quality: 5, TypicalLatencyMs: 2000},
+	{Provider: "beta", Model: "fast", InputPrice: 1, OutputPrice: 2, Quality: 6, TypicalLatencyMs: 500},
+	{Provider: "offline", Model: "best", InputPrice: 0, OutputPrice: 0, Quality: 10, TypicalLatencyMs: 1},
+}
+
+// newTestRouter 创建路由器，并收集 ModelSelected 事件
+func newTestRouter(t *testing.T, strategy model.Strategy) (*Router, *[]sharedevents.ModelSelectedPayload) {
+	registry := provider.NewRegistry()
+	registry.Register(mock.NewNamed("alpha"))
+	registry.Register(mock.NewNamed("beta"))
+
+	bus := sharedevents.NewDefaultEventBus()
+	var selected []sharedevents.ModelSelectedPayload
+	require.NoError(t, bus.Subscribe("ModelSelected", func(ctx context.Context, e sharedevents.Event) error {
+		selected = append(selected, e.Payload().(sharedevents.ModelSelectedPayload))
+		return nil
+	}))
+
+	return NewRouter(NewStaticCatalog(testModels...), registry, strategy, bus), &selected
+}
+
+func newRequest(strategy model.Strategy) *model.ChatRequest {
+	return &model.ChatRequest{
+		Strategy: strategy,
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hello"}},
+	}
+}
+
+func TestRouter_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy model.Strategy
+		want     string
+	}{
+		{model.StrategyLatency, "fast"},
+		{model.StrategyCost, "cheap"},
+		{model.StrategyQuality, "smart"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			r, selected := newTestRouter(t, "")
+
+			sel, err := r.Route(context.Background(), newRequest(tt.strategy))
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sel.Model)
+			assert.Equal(t, tt.strategy, sel.Strategy)
+			assert.NotEmpty(t, sel.Reason)
+
+			require.Len(t, *selected, 1)
+			assert.Equal(t, tt.want, (*selected)[0].Model)
+			assert.Equal(t, string(tt.strategy), (*selected)[0].Strategy)
+			assert.Equal(t, sel.Reason, (*selected)[0].Reason)
+		})
+	}
+}
+
+func TestRouter_Random(t *testing.T) {
+	r, _ := newTestRouter(t, model.StrategyRandom)
+	r.intn = func(n int) int {
+		assert.Equal(t, 3, n, "未注册提供商的模型不是候选")
+		return 2
+	}
+
+	sel, err := r.Route(context.Background(), newRequest(""))
+
+	require.NoError(t, err)
+	assert.Equal(t, "fast", sel.Model)
+	assert.Equal(t, model.StrategyRandom, sel.Strategy)
+}
+
+func TestRouter_LatencyUsesObservedP95(t *testing.T) {
+	r, _ := newTestRouter(t, model.StrategyLatency)
+
+	// 样本不足时使用预估延迟
+	for i := 0; i < MinLatencySamples-1; i++ {
+		r.Observe("alpha", "cheap", 10*time.Millisecond)
+	}
+	sel, err := r.Route(context.Background(), newRequest(""))
+	require.NoError(t, err)
+	assert.Equal(t, "fast", sel.Model)
+
+	// 样本足够后使用观测 P95
+	r.Observe("alpha", "cheap", 20*time.Millisecond)
+	sel, err = r.Route(context.Background(), newRequest(""))
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", sel.Model)
+	assert.Contains(t, sel.Reason, "P95 延迟 20ms")
+
+	// 真实调用变慢后切换
+	for i := 0; i < DefaultLatencyWindow; i++ {
+		r.Observe("alpha", "cheap", time.Second)
+	}
+	sel, err = r.Route(context.Background(), newRequest(""))
+	require.NoError(t, err)
+	assert.Equal(t, "fast", sel.Model)
+}
+
+func TestRouter_CostUsesMaxTokens(t *testing.T) {
+	r, _ := newTestRouter(t, "")
+	req := newRequest(model.StrategyCost)
+	req.MaxTokens = 100
+
+	sel, err := r.Route(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", sel.Model)
+	assert.Contains(t, sel.Reason, "输出 100 tokens")
+}
+
+func TestRouter_Overrides(t *testing.T) {
+	t.Run("提供商和模型都指定时直接使用", func(t *testing.T) {
+		r, selected := newTestRouter(t, model.StrategyCost)
+		req := newRequest("")
+		req.Provider, req.Model = "beta", "custom"
+
+		sel, err := r.Route(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "beta", sel.Provider)
+		assert.Equal(t, "custom", sel.Model)
+		assert.Equal(t, ReasonOverride, sel.Reason)
+		require.Len(t, *selected, 1)
+		assert.Equal(t, ReasonOverride, (*selected)[0].Reason)
+	})
+
+	t.Run("只指定提供商时在该提供商的模型中选择", func(t *testing.T) {
+		r, _ := newTestRouter(t, "")
+		req := newRequest(model.StrategyLatency)
+		req.Provider = "alpha"
+
+		sel, err := r.Route(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, "alpha", sel.Provider)
+		assert.Equal(t, "cheap", sel.Model)
+	})
+
+	t.Run("请求策略优先于默认策略", func(t *testing.T) {
+		r, _ := newTestRouter(t, model.StrategyCost)
+
+		sel, err := r.Route(context.Background(), newRequest(model.StrategyQuality))
+
+		require.NoError(t, err)
+		assert.Equal(t, "smart", sel.Model)
+	})
+
+	t.Run("没有策略时不路由", func(t *testing.T) {
+		r, selected := newTestRouter(t, "")
+
+		sel, err := r.Route(context.Background(), newRequest(""))
+
+		require.NoError(t, err)
+		assert.Nil(t, sel)
+		assert.Empty(t, *selected)
+	})
+}
+
+func TestRouter_FiltersByCapabilities(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(mock.NewNamed("alpha"))
+	catalog := NewStaticCatalog(
+		model.ModelSpec{Provider: "alpha", Model: "plain", Quality: 9},
+		model.ModelSpec{Provider: "alpha", Model: "tools", Quality: 7, SupportsTools: true},
+		model.ModelSpec{Provider: "alpha", Model: "json", Quality: 5, SupportsJSONMode: true},
+	)
+	r := NewRouter(catalog, registry, model.StrategyQuality, nil)
+
+	sel, err := r.Route(context.Background(), newRequest(""))
+	require.NoError(t, err)
+	assert.Equal(t, "plain", sel.Model)
+
+	req := newRequest("")
+	req.Tools = []model.ToolDefinition{{Name: "search"}}
+	sel, err = r.Route(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "tools", sel.Model)
+
+	req = newRequest("")
+	req.ResponseFormat = &model.ResponseFormat{Type: "json_object"}
+	sel, err = r.Route(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "json", sel.Model)
+
+	req.Tools = []model.ToolDefinition{{Name: "search"}}
+	_, err = r.Route(context.Background(), req)
+	assert.ErrorIs(t, err, model.ErrNoModelAvailable)
+}
+
+func TestRouter_Errors(t *testing.T) {
+	r, selected := newTestRouter(t, "")
+
+	_, err := r.Route(context.Background(), newRequest("fastest"))
+	assert.ErrorIs(t, err, model.ErrInvalidStrategy)
+
+	req := newRequest(model.StrategyQuality)
+	req.Provider = "offline"
+	_, err = r.Route(context.Background(), req)
+	assert.ErrorIs(t, err, model.ErrNoModelAvailable)
+
+	assert.Empty(t, *selected)
+}
+
+func TestLatencyTracker(t *testing.T) {
+	tracker := NewLatencyTracker(10)
+	assert.Equal(t, LatencyStats{}, tracker.Stats("alpha", "cheap"))
+
+	for i := 1; i <= 20; i++ {
+		tracker.Observe("alpha", "cheap", time.Duration(i)*time.Millisecond)
+	}
+
+	// 只保留最近 10 个样本（11ms..20ms）
+	stats := tracker.Stats("alpha", "cheap")
+	assert.Equal(t, 10, stats.Samples)
+	assert.Equal(t, 15*time.Millisecond, stats.P50)
+	assert.Equal(t, 20*time.Millisecond, stats.P95)
+}
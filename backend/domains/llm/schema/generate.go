@@ -0,0 +1,222 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// For 由 Go 类型生成 JSON Schema
+//
+// 规则（与 OpenAI strict 模式兼容）：
+//   - 字段名取 json 标签，json:"-" 和未导出字段跳过，匿名嵌入的结构体字段展开
+//   - 所有字段都是必填的，对象不允许额外字段；指针字段允许 null
+//   - time.Time 为 date-time 格式的字符串，json.RawMessage 和接口不限制类型
+//   - description 标签为字段说明
+//   - jsonschema 标签设置约束，逗号分隔：enum=low|medium|high、minimum=1、maximum=5、
+//     minLength=1、maxLength=200、minItems=1、maxItems=10
+//
+// 不支持递归类型和非字符串键的 map。
+func For[T any]() (*Schema, error) {
+	return Generate(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// MustFor 同 For，失败时 panic（用于包级变量）
+func MustFor[T any]() *Schema {
+	s, err := For[T]()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Generate 由反射类型生成 JSON Schema（规则见 For）
+func Generate(t reflect.Type) (*Schema, error) {
+	return generate(t, map[reflect.Type]bool{})
+}
+
+func generate(t reflect.Type, visiting map[reflect.Type]bool) (*Schema, error) {
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{TypeString}, Format: FormatDateTime}, nil
+	case rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s, err := generate(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.Type) > 0 {
+			s.Type = append(s.Type, TypeNull)
+		}
+		return s, nil
+	case reflect.String:
+		return &Schema{Type: Types{TypeString}}, nil
+	case reflect.Bool:
+		return &Schema{Type: Types{TypeBoolean}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{TypeInteger}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{TypeNumber}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{TypeString}}, nil // []byte 编码为 base64 字符串
+		}
+		items, err := generate(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{TypeArray}, Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("不支持非字符串键的 map: %s", t)
+		}
+		return &Schema{Type: Types{TypeObject}}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("不支持递归类型: %s", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: Types{TypeObject}, Properties: map[string]*Schema{}, AdditionalProperties: new(bool)}
+		if err := addFields(s, t, visiting); err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("不支持的类型: %s", t)
+	}
+}
+
+// addFields 将结构体字段加入对象 Schema（匿名嵌入的结构体展开）
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addFields(s, embedded, visiting); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := generate(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		prop.Description = field.Tag.Get("description")
+		if err := applyConstraints(prop, field.Type, field.Tag.Get("jsonschema")); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		s.Properties[name] = prop
+		s.Required = append(s.Required, name)
+	}
+	return nil
+}
+
+// applyConstraints 解析 jsonschema 标签中的约束
+func applyConstraints(s *Schema, t reflect.Type, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("无效的 jsonschema 约束 %q", part)
+		}
+
+		var err error
+		switch key {
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				s.Enum = append(s.Enum, enumValue(t, v))
+			}
+			if t.Kind() == reflect.Pointer {
+				s.Enum = append(s.Enum, nil) // 指针字段允许 null
+			}
+		case "minimum":
+			s.Minimum, err = parseFloat(value)
+		case "maximum":
+			s.Maximum, err = parseFloat(value)
+		case "minLength":
+			s.MinLength, err = parseInt(value)
+		case "maxLength":
+			s.MaxLength, err = parseInt(value)
+		case "minItems":
+			s.MinItems, err = parseInt(value)
+		case "maxItems":
+			s.MaxItems, err = parseInt(value)
+		default:
+			return fmt.Errorf("未知的 jsonschema 约束 %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("jsonschema 约束 %s 的值无效: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// enumValue 按字段类型转换枚举值（数字字段的枚举为数字）
+func enumValue(t reflect.Type, v string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+func parseFloat(v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseInt(v string) (*int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,309 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 类型名称
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+// FormatDateTime RFC 3339 时间格式
+const FormatDateTime = "date-time"
+
+// Schema JSON Schema（支持的子集）
+//
+// 支持的关键字：type（字符串或数组）、description、properties、required、
+// additionalProperties（布尔值）、items、enum、minimum、maximum、minLength、maxLength、
+// minItems、maxItems、format（仅校验 date-time）。其他关键字解析时忽略。
+type Schema struct {
+	Type                 Types              `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+// Types 允许的类型（JSON 中为字符串或字符串数组）
+type Types []string
+
+// MarshalJSON 单个类型输出为字符串
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON 接受字符串或字符串数组
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("type 必须是字符串或字符串数组")
+	}
+	*t = multiple
+	return nil
+}
+
+// Parse 解析 JSON Schema
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("解析 JSON Schema 失败: %w", err)
+	}
+	if err := s.check("$"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// MustParse 解析 JSON Schema，失败时 panic（用于包级变量）
+func MustParse(data string) *Schema {
+	s, err := Parse([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// JSON 返回 Schema 的 JSON 表示
+func (s *Schema) JSON() json.RawMessage {
+	data, _ := json.Marshal(s) // 字段都是可序列化的基础类型，不会失败
+	return data
+}
+
+// check 检查 Schema 本身是否合法（类型名称）
+func (s *Schema) check(path string) error {
+	for _, t := range s.Type {
+		switch t {
+		case TypeObject, TypeArray, TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeNull:
+		default:
+			return fmt.Errorf("%s: 未知的类型 %q", path, t)
+		}
+	}
+	for name, prop := range s.Properties {
+		if prop == nil {
+			return fmt.Errorf("%s.%s: 属性定义不能为空", path, name)
+		}
+		if err := prop.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+// ValidationError 一条校验错误
+type ValidationError struct {
+	Path    string // 如 $.items[0].title
+	Message string
+}
+
+// Error 实现 error 接口
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors 校验错误列表
+type ValidationErrors []ValidationError
+
+// Error 实现 error 接口（多条错误以分号分隔）
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValidateJSON 解析并校验 JSON 文本
+//
+// 文本不是合法 JSON 时返回一条路径为 $ 的错误。
+func (s *Schema) ValidateJSON(data []byte) ValidationErrors {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return ValidationErrors{{Path: "$", Message: "不是合法的 JSON: " + err.Error()}}
+	}
+	return s.Validate(value)
+}
+
+// Validate 校验已解码的 JSON 值（json.Unmarshal 到 any 的结果）
+func (s *Schema) Validate(value any) ValidationErrors {
+	var errs ValidationErrors
+	s.validate("$", value, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, value any, errs *ValidationErrors) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	actual := typeOf(value)
+	if len(s.Type) > 0 && !s.allows(actual, value) {
+		fail("类型应为 %s，实际为 %s", strings.Join(s.Type, " 或 "), actual)
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		fail("值应为 %s 之一", formatEnum(s.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, ValidationError{Path: path + "." + name, Message: "缺少必填字段"})
+			}
+		}
+		for _, name := range sortedKeys(v) {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*errs = append(*errs, ValidationError{Path: path + "." + name, Message: "不允许的字段"})
+				}
+				continue
+			}
+			prop.validate(path+"."+name, v[name], errs)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("元素个数应不少于 %d，实际为 %d", *s.MinItems, len(v))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("元素个数应不超过 %d，实际为 %d", *s.MaxItems, len(v))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case string:
+		length := len([]rune(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("长度应不少于 %d，实际为 %d", *s.MinLength, length)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("长度应不超过 %d，实际为 %d", *s.MaxLength, length)
+		}
+		if s.Format == FormatDateTime {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				fail("应为 RFC 3339 格式的时间")
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("值应不小于 %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("值应不大于 %v", *s.Maximum)
+		}
+	}
+}
+
+// allows 类型是否允许（integer 要求没有小数部分，number 也接受整数）
+func (s *Schema) allows(actual string, value any) bool {
+	for _, t := range s.Type {
+		switch {
+		case t == actual:
+			return true
+		case t == TypeInteger && actual == TypeNumber:
+			if f := value.(float64); f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inEnum 值是否在枚举中
+func (s *Schema) inEnum(value any) bool {
+	for _, candidate := range s.Enum {
+		if reflect.DeepEqual(normalize(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf 返回 JSON 值的类型名称
+func typeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return TypeObject
+	case []any:
+		return TypeArray
+	case string:
+		return TypeString
+	case float64:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	case nil:
+		return TypeNull
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// normalize 将枚举值转换为 json.Unmarshal 到 any 时的表示（数字统一为 float64）
+func normalize(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return value
+	}
+	return out
+}
+
+// formatEnum 格式化枚举值列表，如 ["low", "high"]
+func formatEnum(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		data, _ := json.Marshal(v)
+		parts[i] = string(data)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// sortedKeys 按字典序返回对象的键（保证错误顺序稳定）
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,176 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskSchema = `{
+	"type": "object",
+	"properties": {
+		"title":    {"type": "string", "minLength": 1, "maxLength": 10},
+		"priority": {"type": "string", "enum": ["low", "medium", "high"]},
+		"estimate": {"type": "integer", "minimum": 1, "maximum": 8},
+		"tags":     {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"due":      {"type": ["string", "null"], "format": "date-time"}
+	},
+	"required": ["title", "priority"],
+	"additionalProperties": false
+}`
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(taskSchema))
+	require.NoError(t, err)
+	assert.Equal(t, Types{TypeString, TypeNull}, s.Properties["due"].Type)
+	assert.JSONEq(t, `{"type":"string","enum":["low","medium","high"]}`, string(s.Properties["priority"].JSON()))
+
+	_, err = Parse([]byte(`{"type": "text"}`))
+	assert.ErrorContains(t, err, `$: 未知的类型 "text"`)
+
+	_, err = Parse([]byte(`{"type": 1}`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestValidateJSON(t *testing.T) {
+	s := MustParse(taskSchema)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "valid",
+			input: `{"title": "写周报", "priority": "high", "estimate": 2, "tags": ["work"], "due": null}`,
+		},
+		{
+			name:  "not json",
+			input: `好的，这是结果`,
+			want:  []string{"$"},
+		},
+		{
+			name:  "wrong root type",
+			input: `[]`,
+			want:  []string{"$: 类型应为 object，实际为 array"},
+		},
+		{
+			name:  "missing required and unknown field",
+			input: `{"name": "x"}`,
+			want:  []string{"$.title: 缺少必填字段", "$.priority: 缺少必填字段", "$.name: 不允许的字段"},
+		},
+		{
+			name:  "constraints",
+			input: `{"title": "", "priority": "urgent", "estimate": 1.5, "tags": ["a", 1, "c"], "due": "tomorrow"}`,
+			want: []string{
+				"$.due: 应为 RFC 3339 格式的时间",
+				"$.estimate: 类型应为 integer，实际为 number",
+				`$.priority: 值应为 ["low", "medium", "high"] 之一`,
+				"$.tags: 元素个数应不超过 2，实际为 3",
+				"$.tags[1]: 类型应为 string，实际为 number",
+				"$.title: 长度应不少于 1，实际为 0",
+			},
+		},
+		{
+			name:  "range",
+			input: `{"title": "一二三四五六七八九十", "priority": "low", "estimate": 9}`,
+			want:  []string{"$.estimate: 值应不大于 8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := s.ValidateJSON([]byte(tt.input))
+			if tt.want == nil {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, len(tt.want), errs.Error())
+			for i, want := range tt.want {
+				assert.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
+
+type subtask struct {
+	Title    string `json:"title" description:"子任务标题" jsonschema:"minLength=1"`
+	Priority string `json:"priority" jsonschema:"enum=low|medium|high"`
+}
+
+type breakdown struct {
+	Meta
+	Subtasks  []subtask       `json:"subtasks" jsonschema:"minItems=1,maxItems=5"`
+	Points    *int            `json:"points,omitempty" jsonschema:"enum=1|2|3"`
+	Due       time.Time       `json:"due"`
+	Extra     json.RawMessage `json:"extra"`
+	Internal  string          `json:"-"`
+	unexposed string
+}
+
+type Meta struct {
+	Version int `json:"version"`
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestFor(t *testing.T) {
+	s, err := For[breakdown]()
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"version": {"type": "integer"},
+			"subtasks": {
+				"type": "array",
+				"minItems": 1,
+				"maxItems": 5,
+				"items": {
+					"type": "object",
+					"properties": {
+						"title":    {"type": "string", "description": "子任务标题", "minLength": 1},
+						"priority": {"type": "string", "enum": ["low", "medium", "high"]}
+					},
+					"required": ["title", "priority"],
+					"additionalProperties": false
+				}
+			},
+			"points": {"type": ["integer", "null"], "enum": [1, 2, 3, null]},
+			"due": {"type": "string", "format": "date-time"},
+			"extra": {}
+		},
+		"required": ["version", "subtasks", "points", "due", "extra"],
+		"additionalProperties": false
+	}`, string(s.JSON()))
+
+	// 生成的 Schema 可以校验 Go 值的 JSON 编码
+	points := 2
+	data, err := json.Marshal(breakdown{
+		Subtasks: []subtask{{Title: "设计", Priority: "high"}},
+		Points:   &points,
+		Extra:    json.RawMessage(`{"any": true}`),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, s.ValidateJSON(data))
+	assert.NotEmpty(t, s.ValidateJSON([]byte(`{"version": 1, "subtasks": [], "points": 4, "due": "2026-10-18T00:00:00Z", "extra": 1}`)))
+
+	_, err = For[node]()
+	assert.ErrorContains(t, err, "不支持递归类型")
+
+	_, err = For[map[int]string]()
+	assert.ErrorContains(t, err, "不支持非字符串键的 map")
+
+	_, err = For[struct {
+		N int `json:"n" jsonschema:"maximum=big"`
+	}]()
+	assert.ErrorContains(t, err, "jsonschema 约束 maximum 的值无效")
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DefaultCacheTTL 响应缓存的默认有效期
+const DefaultCacheTTL = time.Hour
+
+// cacheKeyPrefix 缓存键前缀（请求的规范化格式变化时修改版本号）
+const cacheKeyPrefix = "llm:cache:v1:"
+
+// ResponseCache 响应缓存存储（由 llm/cache 的 RedisStore 实现）
+type ResponseCache interface {
+	// Get 读取缓存的响应，未命中时返回 nil, nil
+	Get(ctx context.Context, key string) (*model.ChatResponse, error)
+	// Set 写入响应
+	Set(ctx context.Context, key string, resp *model.ChatResponse, ttl time.Duration) error
+}
+
+// WithCache 设置响应缓存
+//
+// 设置后 Complete 在选定模型之后按请求的规范化哈希查找缓存，命中时直接返回
+// （不调用提供商、不预占额度、不发布 GenerationCompleted）。只缓存：
+//   - 确定性请求（temperature 为 0），或 Cache.Force 的请求
+//   - 成功的响应（结构化输出只缓存通过 Schema 校验的响应）
+//
+// 参数：
+//   - c: 缓存存储
+//   - defaultTTL: 请求未指定 Cache.TTL 时使用（<= 0 时使用 DefaultCacheTTL）
+//   - m: 命中/未命中指标（可为 nil，不采集）
+func (s *LLMService) WithCache(c ResponseCache, defaultTTL time.Duration, m *metrics.Metrics) *LLMService {
+	if defaultTTL <= 0 {
+		defaultTTL = DefaultCacheTTL
+	}
+	s.cache = c
+	s.cacheTTL = defaultTTL
+	s.cacheMetrics = newCacheMetrics(m)
+	return s
+}
+
+// cacheBypassKey ctx 中跳过缓存的标记
+type cacheBypassKey struct{}
+
+// WithCacheBypass 返回跳过响应缓存的 ctx（HTTP 请求头 X-LLM-Cache: bypass 时由中间件设置）
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// CacheBypassed 判断 ctx 是否要求跳过响应缓存
+func CacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// cacheable 判断请求是否使用缓存（在 prepare 之后调用，此时模型已确定）
+func (s *LLMService) cacheable(ctx context.Context, req *model.ChatRequest) bool {
+	if s.cache == nil || req.Cache.Bypass || CacheBypassed(ctx) {
+		return false
+	}
+	return req.Deterministic() || req.Cache.Force
+}
+
+// lookupCache 查找缓存的响应（读取失败视为未命中，只记录日志）
+func (s *LLMService) lookupCache(ctx context.Context, key string, req *model.ChatRequest) *model.ChatResponse {
+	resp, err := s.cache.Get(ctx, key)
+	if err != nil {
+		logger.Error("read llm cache failed", zap.String("model", req.Model), zap.Error(err))
+		resp = nil
+	}
+	s.cacheMetrics.record(req.Provider, req.Model, resp != nil)
+	if resp == nil {
+		return nil
+	}
+
+	resp.Cached = true
+	resp.Usage = model.Usage{} // 本次调用没有消耗 Token
+	return resp
+}
+
+// storeCache 写入响应（失败只记录日志）
+func (s *LLMService) storeCache(ctx context.Context, key string, req *model.ChatRequest, resp *model.ChatResponse) {
+	ttl := req.Cache.TTL
+	if ttl <= 0 {
+		ttl = s.cacheTTL
+	}
+	if err := s.cache.Set(context.WithoutCancel(ctx), key, resp, ttl); err != nil {
+		logger.Error("write llm cache failed", zap.String("model", req.Model), zap.Error(err))
+	}
+}
+
+// cacheRequest 参与缓存键计算的请求字段
+//
+// 不包括 User、Strategy 和 Cache：相同的模型和输入对所有用户返回相同的响应。
+type cacheRequest struct {
+	Provider       string                 `json:"provider"`
+	Model          string                 `json:"model"`
+	Messages       []model.Message        `json:"messages"`
+	Temperature    *float64               `json:"temperature"`
+	TopP           *float64               `json:"top_p"`
+	MaxTokens      int                    `json:"max_tokens"`
+	Stop           []string               `json:"stop"`
+	Tools          []model.ToolDefinition `json:"tools"`
+	ResponseFormat *model.ResponseFormat  `json:"response_format"`
+}
+
+// CacheKey 计算请求的缓存键：模型、消息、生成参数和工具的规范化 JSON 的 SHA-256
+//
+// JSON Schema（工具参数、输出格式）按键排序后参与计算，
+// 因此只有键顺序或空白不同的 Schema 得到相同的键。
+func CacheKey(req *model.ChatRequest) (string, error) {
+	canonical := cacheRequest{
+		Provider:    req.Provider,
+		Model:       req.Model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		MaxTokens:   req.MaxTokens,
+		Stop:        req.Stop,
+	}
+	for _, tool := range req.Tools {
+		params, err := canonicalJSON(tool.Parameters)
+		if err != nil {
+			return "", err
+		}
+		tool.Parameters = params
+		canonical.Tools = append(canonical.Tools, tool)
+	}
+	if req.ResponseFormat != nil {
+		format := *req.ResponseFormat
+		schema, err := canonicalJSON(format.Schema)
+		if err != nil {
+			return "", err
+		}
+		format.Schema = schema
+		canonical.ResponseFormat = &format
+	}
+
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return cacheKeyPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON 重新编码 JSON（对象的键按字典序排列，去掉空白）
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// cacheMetrics 响应缓存的 Prometheus 指标
+type cacheMetrics struct {
+	requests *prometheus.CounterVec // llm_cache_requests_total{provider, model, result}
+}
+
+// newCacheMetrics 注册缓存指标（m 为 nil 时不采集，返回 nil）
+func newCacheMetrics(m *metrics.Metrics) *cacheMetrics {
+	if m == nil {
+		return nil
+	}
+	return &cacheMetrics{
+		requests: m.NewCounterVec("llm_cache_requests_total", "LLM response cache lookups by model and result (hit/miss)", []string{"provider", "model", "result"}),
+	}
+}
+
+// record 记录一次缓存查找
+func (m *cacheMetrics) record(provider, model string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.requests.WithLabelValues(provider, model, result).Inc()
+}
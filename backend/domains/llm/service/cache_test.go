@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/cache"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+// recordingCache 记录写入的有效期，可以模拟读写失败
+type recordingCache struct {
+	*cache.MemoryStore
+	ttls   []time.Duration
+	getErr error
+}
+
+func (c *recordingCache) Get(ctx context.Context, key string) (*model.ChatResponse, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	return c.MemoryStore.Get(ctx, key)
+}
+
+func (c *recordingCache) Set(ctx context.Context, key string, resp *model.ChatResponse, ttl time.Duration) error {
+	c.ttls = append(c.ttls, ttl)
+	return c.MemoryStore.Set(ctx, key, resp, ttl)
+}
+
+func newSummaryRequest(temperature float64) *model.ChatRequest {
+	return &model.ChatRequest{
+		Messages:    []model.Message{{Role: model.RoleUser, Content: "总结这个任务"}},
+		Temperature: floatPtr(temperature),
+		User:        "user-1",
+	}
+}
+
+func TestLLMService_WithCache(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+	store := &recordingCache{MemoryStore: cache.NewMemoryStore()}
+	quota := &fakeQuota{}
+	svc.WithCache(store, 0, nil).WithQuota(quota)
+	ctx := context.Background()
+
+	// 未命中：调用提供商并写入缓存（默认有效期）
+	first, err := svc.Complete(ctx, newSummaryRequest(0))
+	require.NoError(t, err)
+	assert.False(t, first.Cached)
+	assert.Equal(t, []time.Duration{DefaultCacheTTL}, store.ttls)
+
+	// 命中：不调用提供商、不预占额度、不发布 GenerationCompleted，用量为 0
+	second, err := svc.Complete(ctx, newSummaryRequest(0))
+	require.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.Message.Content, second.Message.Content)
+	assert.Equal(t, model.Usage{}, second.Usage)
+	assert.Len(t, mockProvider.Requests(), 1)
+	assert.Equal(t, 1, quota.reserved)
+	assert.Len(t, *completed, 1)
+
+	// 非确定性请求不缓存
+	_, err = svc.Complete(ctx, newSummaryRequest(0.7))
+	require.NoError(t, err)
+	_, err = svc.Complete(ctx, newSummaryRequest(0.7))
+	require.NoError(t, err)
+	assert.Len(t, mockProvider.Requests(), 3)
+
+	// 显式开启：按请求的有效期缓存
+	forced := newSummaryRequest(0.7)
+	forced.Cache = model.CacheOptions{Force: true, TTL: 24 * time.Hour}
+	_, err = svc.Complete(ctx, forced)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, store.ttls[len(store.ttls)-1])
+	forced = newSummaryRequest(0.7)
+	forced.Cache.Force = true
+	resp, err := svc.Complete(ctx, forced)
+	require.NoError(t, err)
+	assert.True(t, resp.Cached)
+	assert.Len(t, mockProvider.Requests(), 4)
+
+	// 跳过缓存：请求选项或 ctx
+	bypass := newSummaryRequest(0)
+	bypass.Cache.Bypass = true
+	resp, err = svc.Complete(ctx, bypass)
+	require.NoError(t, err)
+	assert.False(t, resp.Cached)
+	resp, err = svc.Complete(WithCacheBypass(ctx), newSummaryRequest(0))
+	require.NoError(t, err)
+	assert.False(t, resp.Cached)
+	assert.Len(t, mockProvider.Requests(), 6)
+}
+
+func TestLLMService_WithCache_Structured(t *testing.T) {
+	svc, mockProvider, _ := newTestService(t)
+	store := &recordingCache{MemoryStore: cache.NewMemoryStore()}
+	svc.WithCache(store, 0, nil)
+	ctx := context.Background()
+	newRequest := func() *model.ChatRequest {
+		req := newStructuredRequest()
+		req.Temperature = floatPtr(0)
+		return req
+	}
+	invalid := mock.Response{Content: `{"category": "defect", "confidence": 2}`}
+	valid := mock.Response{Content: `{"category": "bug", "confidence": 0.8}`}
+
+	// 未通过校验的首次输出不缓存，只缓存修正后的输出
+	mockProvider.Enqueue(invalid, valid)
+	result, err := svc.CompleteStructured(ctx, newRequest(), schema.MustFor[classification](), StructuredOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Attempts)
+	assert.Len(t, store.ttls, 1)
+
+	// 相同的请求重新调用提供商；修正请求命中缓存，得到通过校验的输出
+	mockProvider.Enqueue(invalid)
+	result, err = svc.CompleteStructured(ctx, newRequest(), schema.MustFor[classification](), StructuredOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Attempts)
+	assert.True(t, result.Response.Cached)
+	assert.JSONEq(t, valid.Content, string(result.Output))
+	assert.Len(t, mockProvider.Requests(), 3)
+	assert.Len(t, store.ttls, 1)
+}
+
+func TestLLMService_WithCache_Failures(t *testing.T) {
+	svc, mockProvider, _ := newTestService(t)
+	store := &recordingCache{MemoryStore: cache.NewMemoryStore()}
+	svc.WithCache(store, time.Minute, nil)
+	ctx := context.Background()
+
+	// 失败的响应不缓存
+	mockProvider.Enqueue(mock.Response{Err: errors.New("upstream down")})
+	_, err := svc.Complete(ctx, newSummaryRequest(0))
+	require.Error(t, err)
+	assert.Empty(t, store.ttls)
+
+	// 读取缓存失败时调用提供商
+	store.getErr = errors.New("redis down")
+	resp, err := svc.Complete(ctx, newSummaryRequest(0))
+	require.NoError(t, err)
+	assert.False(t, resp.Cached)
+	assert.Len(t, mockProvider.Requests(), 2)
+}
+
+func TestCacheKey(t *testing.T) {
+	base := func() *model.ChatRequest {
+		req := newSummaryRequest(0)
+		req.Provider, req.Model = "openai", "gpt-4o"
+		req.Tools = []model.ToolDefinition{{Name: "search", Parameters: []byte(`{"type": "object", "properties": {"q": {"type": "string"}}}`)}}
+		return req
+	}
+	key := func(req *model.ChatRequest) string {
+		k, err := CacheKey(req)
+		require.NoError(t, err)
+		return k
+	}
+	want := key(base())
+	assert.Regexp(t, `^llm:cache:v1:[0-9a-f]{64}$`, want)
+
+	// 用户、策略、缓存选项和 Schema 的键顺序不影响缓存键
+	same := base()
+	same.User = "user-2"
+	same.Strategy = model.StrategyCost
+	same.Cache.Force = true
+	same.Tools[0].Parameters = []byte(`{"properties":{"q":{"type":"string"}},"type":"object"}`)
+	assert.Equal(t, want, key(same))
+
+	// 模型、消息、参数和工具都参与计算
+	for name, modify := range map[string]func(r *model.ChatRequest){
+		"model":      func(r *model.ChatRequest) { r.Model = "gpt-4o-mini" },
+		"message":    func(r *model.ChatRequest) { r.Messages[0].Content = "总结这个项目" },
+		"max_tokens": func(r *model.ChatRequest) { r.MaxTokens = 100 },
+		"top_p":      func(r *model.ChatRequest) { r.TopP = floatPtr(0.9) },
+		"tools":      func(r *model.ChatRequest) { r.Tools = nil },
+	} {
+		req := base()
+		modify(req)
+		assert.NotEqual(t, want, key(req), name)
+	}
+
+	_, err := CacheKey(&model.ChatRequest{Tools: []model.ToolDefinition{{Parameters: []byte(`{`)}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// 回退原因（记录在 GenerationCompleted.FallbackReason 和 llm_fallbacks_total 中）
+const (
+	FallbackCircuitOpen = "circuit_open" // 熔断器打开，请求未发送
+	FallbackRateLimited = "rate_limited" // 429（重试后仍然限流）
+	FallbackServerError = "server_error" // 5xx 等可重试的状态码（重试后仍然失败）
+	FallbackUnavailable = "unavailable"  // 超时或网络错误（重试后仍然失败）
+)
+
+// FallbackTarget 回退链中的一个提供商
+type FallbackTarget struct {
+	Provider string
+	Model    string // 为空时沿用请求的模型
+}
+
+// String 返回 "提供商:模型"（没有模型时只有提供商）
+func (t FallbackTarget) String() string {
+	if t.Model == "" {
+		return t.Provider
+	}
+	return t.Provider + ":" + t.Model
+}
+
+// apply 把请求切换到回退的提供商
+func (t FallbackTarget) apply(req *model.ChatRequest) {
+	req.Provider = t.Provider
+	if t.Model != "" {
+		req.Model = t.Model
+	}
+}
+
+// ParseFallbackTarget 解析 "provider" 或 "provider:model"
+func ParseFallbackTarget(s string) (FallbackTarget, error) {
+	providerName, modelName, _ := strings.Cut(strings.TrimSpace(s), ":")
+	target := FallbackTarget{Provider: strings.TrimSpace(providerName), Model: strings.TrimSpace(modelName)}
+	if target.Provider == "" {
+		return FallbackTarget{}, fmt.Errorf("invalid fallback target %q: provider is required", s)
+	}
+	return target, nil
+}
+
+// WithBreakers 设置按提供商划分的熔断器
+//
+// 熔断的提供商不发送请求，直接按回退链改用下一个提供商（没有可用的回退时返回 CIRCUIT_OPEN）。
+func (s *LLMService) WithBreakers(b *provider.Breakers) *LLMService {
+	s.breakers = b
+	return s
+}
+
+// WithFallback 设置有序的回退链（例如 openai → anthropic → local）
+//
+// 调用因提供商不可用（客户端重试后仍然失败，或熔断）失败时，按顺序改用回退链中
+// 位于当前提供商之后的提供商（当前提供商不在链中时从头开始），未注册的提供商跳过。
+// 参数错误等不可重试的失败不回退。m 为 nil 时不采集指标。
+func (s *LLMService) WithFallback(chain []FallbackTarget, m *metrics.Metrics) *LLMService {
+	s.fallback = chain
+	s.fallbackMetrics = newFallbackMetrics(m)
+	return s
+}
+
+// Breakers 返回熔断器（未设置时为 nil）
+func (s *LLMService) Breakers() *provider.Breakers {
+	return s.breakers
+}
+
+// fallbackState 一次请求的回退记录
+type fallbackState struct {
+	from   string              // 最初选择的 "提供商:模型"
+	reason string              // 最近一次回退的原因
+	tried  map[string]struct{} // 已尝试的提供商
+}
+
+// next 判断 err 是否应该回退，应该回退时返回回退链中的下一个提供商（由调用方通过 apply 切换）
+func (s *LLMService) next(req *model.ChatRequest, err error, state *fallbackState) (FallbackTarget, provider.Provider, bool) {
+	reason := fallbackReason(err)
+	if reason == "" || len(s.fallback) == 0 {
+		return FallbackTarget{}, nil, false
+	}
+	if state.tried == nil {
+		state.tried = make(map[string]struct{})
+	}
+	state.tried[req.Provider] = struct{}{}
+
+	start := 0
+	for i, target := range s.fallback {
+		if target.Provider == req.Provider {
+			start = i + 1
+			break
+		}
+	}
+	for _, target := range s.fallback[start:] {
+		if _, ok := state.tried[target.Provider]; ok {
+			continue
+		}
+		p, getErr := s.registry.Get(target.Provider)
+		if getErr != nil {
+			continue
+		}
+
+		current := FallbackTarget{Provider: req.Provider, Model: req.Model}
+		if state.from == "" {
+			state.from = current.String()
+		}
+		state.reason = reason
+		logger.Warn("llm fallback",
+			zap.String("from", current.String()),
+			zap.String("to", target.String()),
+			zap.String("reason", reason),
+			zap.Error(err),
+		)
+		s.fallbackMetrics.record(req.Provider, target.Provider, reason)
+		return target, p, true
+	}
+	return FallbackTarget{}, nil, false
+}
+
+// fallbackReason 返回回退原因，不应回退的错误返回空字符串
+func fallbackReason(err error) string {
+	if errors.Is(err, provider.ErrCircuitOpen) {
+		return FallbackCircuitOpen
+	}
+	var perr *provider.Error
+	if !errors.As(err, &perr) || !perr.Retryable {
+		return ""
+	}
+	switch {
+	case perr.StatusCode == http.StatusTooManyRequests:
+		return FallbackRateLimited
+	case perr.StatusCode > 0:
+		return FallbackServerError
+	default:
+		return FallbackUnavailable
+	}
+}
+
+// chat 通过熔断器调用提供商
+func (s *LLMService) chat(ctx context.Context, p provider.Provider, req *model.ChatRequest) (*model.ChatResponse, error) {
+	if err := s.breakers.Allow(req.Provider); err != nil {
+		return nil, err
+	}
+	resp, err := p.Chat(ctx, req)
+	s.breakers.Record(req.Provider, err)
+	return resp, err
+}
+
+// chatStream 通过熔断器开始流式调用（只记录流是否成功开始）
+func (s *LLMService) chatStream(ctx context.Context, p provider.Provider, req *model.ChatRequest) (provider.ChatStream, error) {
+	if err := s.breakers.Allow(req.Provider); err != nil {
+		return nil, err
+	}
+	stream, err := p.ChatStream(ctx, req)
+	s.breakers.Record(req.Provider, err)
+	return stream, err
+}
+
+// fallbackMetrics 回退的 Prometheus 指标
+type fallbackMetrics struct {
+	fallbacks *prometheus.CounterVec // llm_fallbacks_total{from, to, reason}
+}
+
+// newFallbackMetrics 注册回退指标（m 为 nil 时不采集，返回 nil）
+func newFallbackMetrics(m *metrics.Metrics) *fallbackMetrics {
+	if m == nil {
+		return nil
+	}
+	return &fallbackMetrics{
+		fallbacks: m.NewCounterVec("llm_fallbacks_total", "LLM requests moved to the next provider in the fallback chain", []string{"from", "to", "reason"}),
+	}
+}
+
+// record 记录一次回退
+func (m *fallbackMetrics) record(from, to, reason string) {
+	if m == nil {
+		return
+	}
+	m.fallbacks.WithLabelValues(from, to, reason).Inc()
+}
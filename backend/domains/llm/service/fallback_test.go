@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFallbackService 创建 openai → anthropic → local 回退链的服务（三个提供商都是 mock）
+func newFallbackService(t *testing.T, breakers *provider.Breakers) (*LLMService, map[string]*mock.Provider, *[]sharedevents.GenerationCompletedPayload) {
+	registry := provider.NewRegistry()
+	providers := make(map[string]*mock.Provider)
+	for _, name := range []string{"openai", "anthropic", "local"} {
+		providers[name] = mock.NewNamed(name)
+		registry.Register(providers[name])
+	}
+
+	bus := sharedevents.NewDefaultEventBus()
+	var completed []sharedevents.GenerationCompletedPayload
+	require.NoError(t, bus.Subscribe("GenerationCompleted", func(ctx context.Context, e sharedevents.Event) error {
+		completed = append(completed, e.Payload().(sharedevents.GenerationCompletedPayload))
+		return nil
+	}))
+
+	chain := []FallbackTarget{{Provider: "openai"}, {Provider: "anthropic", Model: "claude-3-5-sonnet"}, {Provider: "local", Model: "llama3"}}
+	svc := NewLLMService(registry, "openai", "gpt-4o", bus).WithBreakers(breakers).WithFallback(chain, nil)
+	return svc, providers, &completed
+}
+
+func fallbackRequest() *model.ChatRequest {
+	return &model.ChatRequest{Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}}}
+}
+
+func TestLLMService_Fallback(t *testing.T) {
+	svc, providers, completed := newFallbackService(t, nil)
+	providers["openai"].Enqueue(mock.Response{Err: &provider.Error{Provider: "openai", StatusCode: 503, Retryable: true}})
+	providers["anthropic"].Enqueue(mock.Response{Err: &provider.Error{Provider: "anthropic", StatusCode: 429, Retryable: true}})
+
+	resp, err := svc.Complete(context.Background(), fallbackRequest())
+
+	require.NoError(t, err)
+	assert.Equal(t, "local", resp.Provider)
+	assert.Equal(t, "llama3", providers["local"].Requests()[0].Model)
+	assert.Equal(t, "claude-3-5-sonnet", providers["anthropic"].Requests()[0].Model)
+
+	// 每次调用各发布一次，成功的事件记录最初的提供商和最近一次回退的原因
+	require.Len(t, *completed, 3)
+	assert.False(t, (*completed)[0].Success)
+	assert.Empty(t, (*completed)[0].FallbackFrom)
+	assert.Equal(t, "anthropic", (*completed)[1].Provider)
+	assert.Equal(t, FallbackServerError, (*completed)[1].FallbackReason)
+	final := (*completed)[2]
+	assert.True(t, final.Success)
+	assert.Equal(t, "local", final.Provider)
+	assert.Equal(t, "openai:gpt-4o", final.FallbackFrom)
+	assert.Equal(t, FallbackRateLimited, final.FallbackReason)
+}
+
+func TestLLMService_Fallback_NotForClientErrors(t *testing.T) {
+	svc, providers, completed := newFallbackService(t, nil)
+	providers["openai"].Enqueue(mock.Response{Err: &provider.Error{Provider: "openai", StatusCode: 400, Message: "bad request"}})
+
+	_, err := svc.Complete(context.Background(), fallbackRequest())
+
+	require.Error(t, err)
+	assert.Empty(t, providers["anthropic"].Requests())
+	require.Len(t, *completed, 1)
+	assert.Empty(t, (*completed)[0].FallbackReason)
+}
+
+func TestLLMService_Fallback_CircuitOpen(t *testing.T) {
+	breakers := provider.NewBreakers(provider.BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute})
+	svc, providers, completed := newFallbackService(t, breakers)
+	providers["openai"].Enqueue(mock.Response{Err: &provider.Error{Provider: "openai", Message: "connection refused", Retryable: true}})
+
+	_, err := svc.Complete(context.Background(), fallbackRequest())
+	require.NoError(t, err)
+	assert.Equal(t, provider.BreakerOpen, breakers.Status("openai").State)
+	assert.Equal(t, FallbackUnavailable, (*completed)[1].FallbackReason)
+
+	// 熔断后不再调用 openai，直接回退（不发布未发送请求的事件）
+	*completed = nil
+	resp, err := svc.Complete(context.Background(), fallbackRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "anthropic", resp.Provider)
+	assert.Len(t, providers["openai"].Requests(), 1)
+	require.Len(t, *completed, 1)
+	assert.Equal(t, FallbackCircuitOpen, (*completed)[0].FallbackReason)
+}
+
+func TestLLMService_Stream_Fallback(t *testing.T) {
+	svc, providers, completed := newFallbackService(t, nil)
+	providers["openai"].Enqueue(mock.Response{Err: &provider.Error{Provider: "openai", StatusCode: 502, Retryable: true}})
+
+	stream, err := svc.Stream(context.Background(), fallbackRequest())
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, stream.Close())
+
+	require.Len(t, *completed, 2)
+	assert.False(t, (*completed)[0].Success)
+	final := (*completed)[1]
+	assert.True(t, final.Success)
+	assert.Equal(t, "anthropic", final.Provider)
+	assert.Equal(t, "openai:gpt-4o", final.FallbackFrom)
+	assert.Equal(t, FallbackServerError, final.FallbackReason)
+}
+
+func TestParseFallbackTarget(t *testing.T) {
+	target, err := ParseFallbackTarget(" anthropic:claude-3-5-sonnet ")
+	require.NoError(t, err)
+	assert.Equal(t, FallbackTarget{Provider: "anthropic", Model: "claude-3-5-sonnet"}, target)
+
+	target, err = ParseFallbackTarget("local")
+	require.NoError(t, err)
+	assert.Equal(t, "local", target.String())
+
+	_, err = ParseFallbackTarget(":gpt-4o")
+	assert.Error(t, err)
+}
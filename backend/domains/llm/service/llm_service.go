@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/router"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LLMService LLM 领域服务
+//
+// 职责：
+// - 通过 Router 按策略选择模型（配置了路由器时），否则填充默认提供商和模型
+// - 确定性请求优先读取响应缓存（配置了 ResponseCache 时）
+// - 带有 User 的请求在调用前预占额度，调用后按实际用量结算（配置了 QuotaGuard 时）
+// - 将请求分发给对应的 Provider，熔断的提供商不发送请求（配置了 Breakers 时）
+// - 提供商不可用时按回退链改用下一个提供商（配置了回退链时，见 fallback.go）
+// - 每次调用提供商结束后发布 GenerationCompleted 事件（成功或失败，回退时记录原因）
+//
+// 其他领域（chat、task 等）只依赖 LLMService，不直接依赖具体 Provider。
+type LLMService struct {
+	registry        *provider.Registry
+	defaultProvider string
+	defaultModel    string
+	eventBus        sharedevents.EventBus
+	router          *router.Router // 可选
+	quota           QuotaGuard     // 可选
+	cache           ResponseCache  // 可选
+	cacheTTL        time.Duration
+	cacheMetrics    *cacheMetrics
+	breakers        *provider.Breakers // 可选
+	fallback        []FallbackTarget   // 可选
+	fallbackMetrics *fallbackMetrics
+}
+
+// NewLLMService 创建 LLM 服务
+//
+// 参数：
+//   - registry: 提供商注册表
+//   - defaultProvider: 请求未指定提供商时使用
+//   - defaultModel: 请求未指定模型时使用
+//   - eventBus: 事件总线（可为 nil，不发布事件）
+func NewLLMService(registry *provider.Registry, defaultProvider, defaultModel string, eventBus sharedevents.EventBus) *LLMService {
+	return &LLMService{
+		registry:        registry,
+		defaultProvider: defaultProvider,
+		defaultModel:    defaultModel,
+		eventBus:        eventBus,
+	}
+}
+
+// WithRouter 设置模型路由器
+//
+// 设置后未同时指定提供商和模型的请求由路由器选择模型，
+// 每次成功调用的延迟会回报给路由器（latency 策略使用）。
+func (s *LLMService) WithRouter(r *router.Router) *LLMService {
+	s.router = r
+	return s
+}
+
+// DefaultProvider 返回默认提供商
+func (s *LLMService) DefaultProvider() string {
+	return s.defaultProvider
+}
+
+// DefaultModel 返回默认模型
+func (s *LLMService) DefaultModel() string {
+	return s.defaultModel
+}
+
+// Complete 对话补全（非流式）
+func (s *LLMService) Complete(ctx context.Context, req *model.ChatRequest) (*model.ChatResponse, error) {
+	return s.complete(ctx, req, nil)
+}
+
+// complete 对话补全，cacheValid 不为 nil 时只缓存通过检查的响应（结构化输出只缓存通过 Schema 校验的响应）
+func (s *LLMService) complete(ctx context.Context, req *model.ChatRequest, cacheValid func(*model.ChatResponse) bool) (*model.ChatResponse, error) {
+	// Step 1: 验证、选择模型并填充默认值
+	p, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: 查找响应缓存（命中时不调用提供商）
+	var cacheKey string
+	if s.cacheable(ctx, req) {
+		if key, err := CacheKey(req); err != nil {
+			logger.Error("build llm cache key failed", zap.Error(err))
+		} else if cached := s.lookupCache(ctx, key, req); cached != nil {
+			return cached, nil
+		} else {
+			cacheKey = key
+		}
+	}
+
+	// Step 3: 预占额度
+	reservation, err := s.reserve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: 调用提供商（不可用时按回退链改用下一个提供商，失败的调用各发布一次 GenerationCompleted）
+	var fb fallbackState
+	var resp *model.ChatResponse
+	var latency time.Duration
+	for {
+		start := time.Now()
+		resp, err = s.chat(ctx, p, req)
+		latency = time.Since(start)
+
+		failed := newGenerationPayload(req, &fb)
+		target, next, ok := s.next(req, err, &fb)
+		if !ok {
+			break
+		}
+		if !errors.Is(err, provider.ErrCircuitOpen) {
+			failed.Error = err.Error()
+			failed.Latency = latency.Milliseconds()
+			s.publish(ctx, failed)
+		}
+		target.apply(req)
+		p = next
+	}
+
+	// Step 5: 按实际用量结算额度，发布 GenerationCompleted，写入缓存（回退的响应不缓存）
+	payload := newGenerationPayload(req, &fb)
+	payload.Latency = latency.Milliseconds()
+	payload.Success = err == nil
+	if err != nil {
+		payload.Error = err.Error()
+		settle(ctx, reservation, req.Provider, req.Model, model.Usage{})
+	} else {
+		settle(ctx, reservation, req.Provider, req.Model, resp.Usage)
+		payload.InputTokens = resp.Usage.InputTokens
+		payload.OutputTokens = resp.Usage.OutputTokens
+		if resp.Provider == "" {
+			resp.Provider = req.Provider
+		}
+		if resp.Model == "" {
+			resp.Model = req.Model
+		}
+		s.observe(req.Provider, req.Model, latency)
+		if cacheKey != "" && fb.from == "" && (cacheValid == nil || cacheValid(resp)) {
+			s.storeCache(ctx, cacheKey, req, resp)
+		}
+	}
+	s.publish(ctx, payload)
+
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Stream 对话补全（流式）
+//
+// 回退只发生在流开始之前。返回的流结束（io.EOF）、出错或被 Close 时结算额度并发布一次 GenerationCompleted。
+func (s *LLMService) Stream(ctx context.Context, req *model.ChatRequest) (provider.ChatStream, error) {
+	p, err := s.prepare(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.reserve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var fb fallbackState
+	for {
+		start := time.Now()
+		stream, err := s.chatStream(ctx, p, req)
+		if err == nil {
+			return &trackedStream{
+				ChatStream:  stream,
+				service:     s,
+				ctx:         ctx,
+				start:       start,
+				reservation: reservation,
+				payload:     newGenerationPayload(req, &fb),
+			}, nil
+		}
+
+		failed := newGenerationPayload(req, &fb)
+		failed.Error = err.Error()
+		failed.Latency = time.Since(start).Milliseconds()
+		target, next, ok := s.next(req, err, &fb)
+		if !ok {
+			settle(ctx, reservation, req.Provider, req.Model, model.Usage{})
+			s.publish(ctx, failed)
+			return nil, err
+		}
+		if !errors.Is(err, provider.ErrCircuitOpen) {
+			s.publish(ctx, failed)
+		}
+		target.apply(req)
+		p = next
+	}
+}
+
+// Embed 向量嵌入
+func (s *LLMService) Embed(ctx context.Context, req *model.EmbeddingRequest) (*model.EmbeddingResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Provider == "" {
+		req.Provider = s.defaultProvider
+	}
+
+	p, err := s.registry.Get(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.breakers.Allow(req.Provider); err != nil {
+		return nil, err
+	}
+	resp, err := p.Embed(ctx, req)
+	s.breakers.Record(req.Provider, err)
+	return resp, err
+}
+
+// prepare 验证请求，选择模型（或填充默认提供商/模型）并查找提供商
+func (s *LLMService) prepare(ctx context.Context, req *model.ChatRequest) (provider.Provider, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if s.router != nil {
+		sel, err := s.router.Route(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if sel != nil {
+			req.Provider, req.Model = sel.Provider, sel.Model
+		}
+	}
+	if req.Provider == "" {
+		req.Provider = s.defaultProvider
+	}
+	if req.Model == "" {
+		req.Model = s.defaultModel
+	}
+	return s.registry.Get(req.Provider)
+}
+
+// newGenerationPayload 创建当前调用的 GenerationCompleted 负载（带上已发生的回退）
+func newGenerationPayload(req *model.ChatRequest, fb *fallbackState) sharedevents.GenerationCompletedPayload {
+	return sharedevents.GenerationCompletedPayload{
+		RequestID:      uuid.New().String(),
+		UserID:         req.User,
+		Model:          req.Model,
+		Provider:       req.Provider,
+		FallbackFrom:   fb.from,
+		FallbackReason: fb.reason,
+	}
+}
+
+// observe 将成功调用的延迟回报给路由器
+func (s *LLMService) observe(providerName, modelName string, latency time.Duration) {
+	if s.router != nil {
+		s.router.Observe(providerName, modelName, latency)
+	}
+}
+
+// publish 发布 GenerationCompleted（失败只记录日志）
+func (s *LLMService) publish(ctx context.Context, payload sharedevents.GenerationCompletedPayload) {
+	if s.eventBus == nil {
+		return
+	}
+	if err := s.eventBus.Publish(ctx, sharedevents.NewGenerationCompletedEvent(payload)); err != nil {
+		logger.Error("publish GenerationCompleted failed",
+			zap.String("request_id", payload.RequestID),
+			zap.Error(err),
+		)
+	}
+}
+
+// trackedStream 包装 ChatStream，在流结束时结算额度并发布 GenerationCompleted
+type trackedStream struct {
+	provider.ChatStream
+	service     *LLMService
+	ctx         context.Context
+	start       time.Time
+	reservation QuotaReservation
+	received    bool // 是否收到过片段
+	payload     sharedevents.GenerationCompletedPayload
+	once        sync.Once
+}
+
+// Recv 读取片段并累积用量
+func (t *trackedStream) Recv() (*model.StreamChunk, error) {
+	chunk, err := t.ChatStream.Recv()
+	if err != nil {
+		t.finish(err)
+		return nil, err
+	}
+	t.received = true
+	if chunk.Usage != nil {
+		t.payload.InputTokens = chunk.Usage.InputTokens
+		t.payload.OutputTokens = chunk.Usage.OutputTokens
+	}
+	return chunk, nil
+}
+
+// Close 关闭流（提前关闭视为取消）
+func (t *trackedStream) Close() error {
+	t.finish(context.Canceled)
+	return t.ChatStream.Close()
+}
+
+// finish 结算额度并发布一次 GenerationCompleted
+//
+// 提供商通常只在最后一个片段返回用量：已输出内容但没有用量（提前关闭或中途出错）时
+// 保留预占的额度，避免通过中断生成绕过额度。
+func (t *trackedStream) finish(err error) {
+	t.once.Do(func() {
+		usage := model.Usage{InputTokens: t.payload.InputTokens, OutputTokens: t.payload.OutputTokens}
+		if !t.received || usage.TotalTokens() > 0 {
+			settle(t.ctx, t.reservation, t.payload.Provider, t.payload.Model, usage)
+		}
+		latency := time.Since(t.start)
+		t.payload.Latency = latency.Milliseconds()
+		t.payload.Success = errors.Is(err, io.EOF)
+		if t.payload.Success {
+			t.service.observe(t.payload.Provider, t.payload.Model, latency)
+		} else {
+			t.payload.Error = err.Error()
+		}
+		// 流可能因 ctx 取消而结束，事件发布不应受其影响
+		t.service.publish(context.WithoutCancel(t.ctx), t.payload)
+	})
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/router"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestService 创建使用 mock 提供商的服务，并收集 GenerationCompleted 事件
+func newTestService(t *testing.T) (*LLMService, *mock.Provider, *[]sharedevents.GenerationCompletedPayload) {
+	registry := provider.NewRegistry()
+	mockProvider := mock.New()
+	registry.Register(mockProvider)
+
+	bus := sharedevents.NewDefaultEventBus()
+	var completed []sharedevents.GenerationCompletedPayload
+	require.NoError(t, bus.Subscribe("GenerationCompleted", func(ctx context.Context, e sharedevents.Event) error {
+		completed = append(completed, e.Payload().(sharedevents.GenerationCompletedPayload))
+		return nil
+	}))
+
+	return NewLLMService(registry, mock.Name, "gpt-4o", bus), mockProvider, &completed
+}
+
+func TestLLMService_Complete(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+
+	resp, err := svc.Complete(context.Background(), &model.ChatRequest{
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mock reply: hi", resp.Message.Content)
+	assert.Equal(t, "gpt-4o", mockProvider.Requests()[0].Model)
+
+	require.Len(t, *completed, 1)
+	event := (*completed)[0]
+	assert.True(t, event.Success)
+	assert.Equal(t, "mock", event.Provider)
+	assert.Equal(t, "gpt-4o", event.Model)
+	assert.Equal(t, 1, event.InputTokens)
+	assert.Equal(t, 3, event.OutputTokens)
+}
+
+func TestLLMService_Complete_Errors(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+
+	// 空消息：不调用提供商，不发布事件
+	_, err := svc.Complete(context.Background(), &model.ChatRequest{})
+	assert.ErrorIs(t, err, model.ErrEmptyMessages)
+
+	// 未注册的提供商
+	_, err = svc.Complete(context.Background(), &model.ChatRequest{
+		Provider: "unknown",
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}},
+	})
+	assert.ErrorIs(t, err, model.ErrProviderNotFound)
+	assert.Empty(t, *completed)
+
+	// 提供商返回错误：发布失败事件
+	mockProvider.Enqueue(mock.Response{Err: errors.New("upstream down")})
+	_, err = svc.Complete(context.Background(), &model.ChatRequest{
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}},
+	})
+	require.Error(t, err)
+	require.Len(t, *completed, 1)
+	assert.False(t, (*completed)[0].Success)
+	assert.Equal(t, "upstream down", (*completed)[0].Error)
+}
+
+func TestLLMService_Stream(t *testing.T) {
+	svc, _, completed := newTestService(t)
+
+	stream, err := svc.Stream(context.Background(), &model.ChatRequest{
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+
+	var content string
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content += chunk.Delta
+	}
+	require.NoError(t, stream.Close())
+
+	assert.Equal(t, "Mock reply: hi", content)
+	require.Len(t, *completed, 1, "流结束和 Close 只发布一次事件")
+	assert.True(t, (*completed)[0].Success)
+	assert.Equal(t, 3, (*completed)[0].OutputTokens)
+}
+
+func TestLLMService_Stream_ClosedEarly(t *testing.T) {
+	svc, _, completed := newTestService(t)
+
+	stream, err := svc.Stream(context.Background(), &model.ChatRequest{
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+
+	require.Len(t, *completed, 1)
+	assert.False(t, (*completed)[0].Success)
+}
+
+func TestLLMService_WithRouter(t *testing.T) {
+	registry := provider.NewRegistry()
+	registry.Register(mock.New())
+	catalog := router.NewStaticCatalog(
+		model.ModelSpec{Provider: mock.Name, Model: "big", InputPrice: 5, OutputPrice: 15, Quality: 9},
+		model.ModelSpec{Provider: mock.Name, Model: "small", InputPrice: 0.1, OutputPrice: 0.4, Quality: 5},
+	)
+	r := router.NewRouter(catalog, registry, model.StrategyCost, nil)
+	svc := NewLLMService(registry, mock.Name, "gpt-4o", nil).WithRouter(r)
+	newReq := func() *model.ChatRequest {
+		return &model.ChatRequest{Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}}}
+	}
+
+	// 默认策略
+	resp, err := svc.Complete(context.Background(), newReq())
+	require.NoError(t, err)
+	assert.Equal(t, "small", resp.Model)
+	assert.Equal(t, 1, r.Latency(mock.Name, "small").Samples, "成功调用的延迟回报给路由器")
+
+	// 请求策略
+	req := newReq()
+	req.Strategy = model.StrategyQuality
+	resp, err = svc.Complete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "big", resp.Model)
+
+	// 请求指定模型
+	req = newReq()
+	req.Provider, req.Model = mock.Name, "pinned"
+	resp, err = svc.Complete(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "pinned", resp.Model)
+
+	// 流式调用同样路由并回报延迟
+	stream, err := svc.Stream(context.Background(), newReq())
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	require.NoError(t, stream.Close())
+	assert.Equal(t, 2, r.Latency(mock.Name, "small").Samples)
+
+	// 无效策略
+	req = newReq()
+	req.Strategy = "fastest"
+	_, err = svc.Complete(context.Background(), req)
+	assert.ErrorIs(t, err, model.ErrInvalidStrategy)
+}
+
+// fakeQuota 记录预占和结算的额度守卫
+type fakeQuota struct {
+	reject   bool
+	reserved int
+	settled  []model.Usage
+}
+
+func (q *fakeQuota) Reserve(ctx context.Context, req *model.ChatRequest) (QuotaReservation, error) {
+	if q.reject {
+		return nil, fmt.Errorf("%w: 今日 Token 额度已用完", model.ErrQuotaExceeded)
+	}
+	q.reserved++
+	return q, nil
+}
+
+func (q *fakeQuota) Settle(ctx context.Context, providerName, modelName string, usage model.Usage) {
+	q.settled = append(q.settled, usage)
+}
+
+func TestLLMService_WithQuota(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+	quota := &fakeQuota{}
+	svc.WithQuota(quota)
+	newReq := func(user string) *model.ChatRequest {
+		return &model.ChatRequest{Messages: []model.Message{{Role: model.RoleUser, Content: "hi"}}, User: user}
+	}
+
+	// 系统调用（没有 User）不检查额度
+	_, err := svc.Complete(context.Background(), newReq(""))
+	require.NoError(t, err)
+	assert.Equal(t, 0, quota.reserved)
+
+	// 成功调用按实际用量结算
+	_, err = svc.Complete(context.Background(), newReq("user-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, quota.reserved)
+	assert.Equal(t, []model.Usage{{InputTokens: 1, OutputTokens: 3}}, quota.settled)
+
+	// 失败的调用退回额度
+	mockProvider.Enqueue(mock.Response{Err: errors.New("upstream down")})
+	_, err = svc.Complete(context.Background(), newReq("user-1"))
+	require.Error(t, err)
+	assert.Equal(t, model.Usage{}, quota.settled[1])
+
+	// 流结束时结算一次
+	stream, err := svc.Stream(context.Background(), newReq("user-1"))
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	require.NoError(t, stream.Close())
+	require.Len(t, quota.settled, 3)
+	assert.Equal(t, 3, quota.settled[2].OutputTokens)
+
+	// 超出额度：不调用提供商，不发布事件
+	quota.reject = true
+	calls, events := len(mockProvider.Requests()), len(*completed)
+	_, err = svc.Complete(context.Background(), newReq("user-1"))
+	assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+	_, err = svc.Stream(context.Background(), newReq("user-1"))
+	assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+	assert.Len(t, mockProvider.Requests(), calls)
+	assert.Len(t, *completed, events)
+}
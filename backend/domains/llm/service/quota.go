@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// QuotaGuard 调用额度守卫（由 usage 领域的 QuotaService 实现）
+//
+// LLMService 在选定模型之后、调用提供商之前预占额度，
+// 调用结束后按实际用量结算（失败的调用会退回预占的额度）。
+type QuotaGuard interface {
+	// Reserve 按预估用量预占额度，超出额度时返回包装了 model.ErrQuotaExceeded 的错误
+	Reserve(ctx context.Context, req *model.ChatRequest) (QuotaReservation, error)
+}
+
+// QuotaReservation 一次预占的额度
+type QuotaReservation interface {
+	// Settle 按实际用量结算（只调用一次）
+	//
+	// providerName / modelName 是实际完成请求的提供商和模型：
+	// 发生回退时与预占时不同，费用按实际的模型计算。
+	Settle(ctx context.Context, providerName, modelName string, usage model.Usage)
+}
+
+// WithQuota 设置额度守卫
+//
+// 设置后带有 User 的请求在调用前检查并预占额度，超出时返回 QUOTA_EXCEEDED。
+func (s *LLMService) WithQuota(q QuotaGuard) *LLMService {
+	s.quota = q
+	return s
+}
+
+// reserve 预占额度（未设置守卫或系统调用时返回 nil）
+func (s *LLMService) reserve(ctx context.Context, req *model.ChatRequest) (QuotaReservation, error) {
+	if s.quota == nil || req.User == "" {
+		return nil, nil
+	}
+	return s.quota.Reserve(ctx, req)
+}
+
+// settle 结算额度（结算不应受请求取消影响）
+func settle(ctx context.Context, res QuotaReservation, providerName, modelName string, usage model.Usage) {
+	if res != nil {
+		res.Settle(context.WithoutCancel(ctx), providerName, modelName, usage)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultStructuredRetries 结构化输出校验失败后的默认重试次数
+const DefaultStructuredRetries = 2
+
+// DefaultSchemaName 未指定名称时使用的 Schema 名称（json_schema 输出约束需要名称）
+const DefaultSchemaName = "response"
+
+// StructuredOptions 结构化输出选项
+type StructuredOptions struct {
+	Name       string // Schema 名称（默认 response）
+	MaxRetries int    // 校验失败后的重试次数：0 使用默认值，负数不重试
+}
+
+// StructuredResult 结构化输出结果
+type StructuredResult struct {
+	Output   json.RawMessage     // 通过校验的 JSON
+	Response *model.ChatResponse // 最后一次调用的响应
+	Attempts int                 // 调用次数（包括重试）
+	Usage    model.Usage         // 所有调用的用量之和
+}
+
+// CompleteStructured 结构化输出（用例实现）
+//
+// 步骤：
+//  1. Instruct - 要求模型只输出符合 Schema 的 JSON（系统提示 + json_schema 输出约束）
+//  2. Complete - 调用模型（经过路由、额度和 GenerationCompleted，与 Complete 相同；只缓存通过校验的响应）
+//  3. Validate - 解析并校验输出
+//  4. Repair - 校验失败时把输出和校验错误发回模型，要求修正，最多重试 MaxRetries 次
+//
+// 重试用尽后发布 SchemaValidationFailed 并返回 SCHEMA_VALIDATION_FAILED。
+// 调用模型失败（提供商错误、超出额度等）时直接返回，不重试。
+// 不修改传入的请求。
+func (s *LLMService) CompleteStructured(ctx context.Context, req *model.ChatRequest, sch *schema.Schema, opts StructuredOptions) (*StructuredResult, error) {
+	if sch == nil {
+		return nil, model.ErrInvalidSchema
+	}
+	name := opts.Name
+	if name == "" {
+		name = DefaultSchemaName
+	}
+	retries := opts.MaxRetries
+	if retries == 0 {
+		retries = DefaultStructuredRetries
+	}
+
+	// Step 1: Instruct
+	schemaJSON := sch.JSON()
+	call := *req
+	call.Messages = withSchemaInstruction(req.Messages, schemaJSON)
+	if call.ResponseFormat == nil {
+		call.ResponseFormat = &model.ResponseFormat{Type: "json_schema", Name: name, Schema: schemaJSON}
+	}
+
+	// 未通过校验的响应不写入缓存，否则相同的请求（以及修正请求）会一直读到无效的输出
+	valid := func(resp *model.ChatResponse) bool {
+		return len(sch.ValidateJSON([]byte(ExtractJSON(resp.Message.Content)))) == 0
+	}
+
+	result := &StructuredResult{}
+	for {
+		// Step 2: Complete（首次调用选定的模型在重试时沿用）
+		resp, err := s.complete(ctx, &call, valid)
+		if err != nil {
+			return nil, err
+		}
+		result.Attempts++
+		result.Response = resp
+		result.Usage.InputTokens += resp.Usage.InputTokens
+		result.Usage.OutputTokens += resp.Usage.OutputTokens
+
+		// Step 3: Validate
+		output := ExtractJSON(resp.Message.Content)
+		errs := sch.ValidateJSON([]byte(output))
+		if len(errs) == 0 {
+			result.Output = json.RawMessage(output)
+			return result, nil
+		}
+
+		// Step 4: Repair
+		if result.Attempts > retries {
+			s.publishSchemaValidationFailed(ctx, sharedevents.SchemaValidationFailedPayload{
+				RequestID: uuid.New().String(),
+				Model:     call.Model,
+				Schema:    string(schemaJSON),
+				Output:    resp.Message.Content,
+				Error:     errs.Error(),
+			})
+			return nil, fmt.Errorf("%w: %s", model.ErrSchemaValidation, errs.Error())
+		}
+		call.Messages = append(call.Messages,
+			model.Message{Role: model.RoleAssistant, Content: resp.Message.Content},
+			model.Message{Role: model.RoleUser, Content: repairPrompt(errs)},
+		)
+	}
+}
+
+// CompleteAs 结构化输出并解码为 T
+//
+// Schema 由 T 生成（规则见 schema.For）。
+func CompleteAs[T any](ctx context.Context, s *LLMService, req *model.ChatRequest, opts StructuredOptions) (*T, *StructuredResult, error) {
+	sch, err := schema.For[T]()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", model.ErrInvalidSchema, err.Error())
+	}
+	result, err := s.CompleteStructured(ctx, req, sch, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out T
+	if err := json.Unmarshal(result.Output, &out); err != nil {
+		// 通过校验的输出仍可能超出 Go 类型的表示范围（如整数溢出）
+		return nil, result, fmt.Errorf("%w: %s", model.ErrSchemaValidation, err.Error())
+	}
+	return &out, result, nil
+}
+
+// withSchemaInstruction 在开头的系统消息之后插入输出格式说明（返回新切片）
+func withSchemaInstruction(messages []model.Message, schemaJSON json.RawMessage) []model.Message {
+	instruction := model.Message{
+		Role:    model.RoleSystem,
+		Content: "只输出一个符合以下 JSON Schema 的 JSON 值，不要输出 Markdown 代码块或其他文字。\nJSON Schema:\n" + string(schemaJSON),
+	}
+
+	at := 0
+	for at < len(messages) && messages[at].Role == model.RoleSystem {
+		at++
+	}
+	out := make([]model.Message, 0, len(messages)+1)
+	out = append(out, messages[:at]...)
+	out = append(out, instruction)
+	return append(out, messages[at:]...)
+}
+
+// repairPrompt 生成要求模型修正输出的提示
+func repairPrompt(errs schema.ValidationErrors) string {
+	var b strings.Builder
+	b.WriteString("上一次输出未通过 JSON Schema 校验：\n")
+	for _, e := range errs {
+		b.WriteString("- " + e.Error() + "\n")
+	}
+	b.WriteString("请修正以上问题，只输出符合 Schema 的 JSON。")
+	return b.String()
+}
+
+// ExtractJSON 去掉模型输出中的空白和 Markdown 代码块（```json ... ```）
+//
+// 结构化输出和离线评估（eval 的 json_schema 检查）使用相同的规则。
+func ExtractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	if newline := strings.IndexByte(content, '\n'); newline >= 0 {
+		content = content[newline+1:] // 语言标记，如 json
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(content), "```"))
+}
+
+// publishSchemaValidationFailed 发布 SchemaValidationFailed（失败只记录日志）
+func (s *LLMService) publishSchemaValidationFailed(ctx context.Context, payload sharedevents.SchemaValidationFailedPayload) {
+	if s.eventBus == nil {
+		return
+	}
+	if err := s.eventBus.Publish(ctx, sharedevents.NewSchemaValidationFailedEvent(payload)); err != nil {
+		logger.Error("publish SchemaValidationFailed failed",
+			zap.String("request_id", payload.RequestID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type classification struct {
+	Category   string  `json:"category" jsonschema:"enum=bug|feature|question"`
+	Confidence float64 `json:"confidence" jsonschema:"minimum=0,maximum=1"`
+}
+
+func newStructuredRequest() *model.ChatRequest {
+	return &model.ChatRequest{
+		Messages: []model.Message{
+			{Role: model.RoleSystem, Content: "你是一个工单分类助手"},
+			{Role: model.RoleUser, Content: "登录页面白屏"},
+		},
+	}
+}
+
+func TestCompleteAs(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+	mockProvider.Enqueue(mock.Response{Content: "```json\n{\"category\": \"bug\", \"confidence\": 0.9}\n```"})
+
+	req := newStructuredRequest()
+	out, result, err := CompleteAs[classification](context.Background(), svc, req, StructuredOptions{Name: "classification"})
+
+	require.NoError(t, err)
+	assert.Equal(t, classification{Category: "bug", Confidence: 0.9}, *out)
+	assert.Equal(t, 1, result.Attempts)
+	assert.JSONEq(t, `{"category": "bug", "confidence": 0.9}`, string(result.Output))
+	assert.Len(t, *completed, 1)
+
+	// 系统提示之后插入格式说明，并设置 json_schema 输出约束
+	sent := mockProvider.Requests()[0]
+	require.Len(t, sent.Messages, 3)
+	assert.Equal(t, "你是一个工单分类助手", sent.Messages[0].Content)
+	assert.Equal(t, model.RoleSystem, sent.Messages[1].Role)
+	assert.Contains(t, sent.Messages[1].Content, `"enum":["bug","feature","question"]`)
+	require.NotNil(t, sent.ResponseFormat)
+	assert.Equal(t, "json_schema", sent.ResponseFormat.Type)
+	assert.Equal(t, "classification", sent.ResponseFormat.Name)
+
+	// 不修改传入的请求
+	assert.Len(t, req.Messages, 2)
+	assert.Nil(t, req.ResponseFormat)
+}
+
+func TestCompleteStructured_Repair(t *testing.T) {
+	svc, mockProvider, completed := newTestService(t)
+	mockProvider.Enqueue(
+		mock.Response{Content: "这是一个 bug", Usage: &model.Usage{InputTokens: 10, OutputTokens: 5}},
+		mock.Response{Content: `{"category": "defect", "confidence": 2}`, Usage: &model.Usage{InputTokens: 20, OutputTokens: 8}},
+		mock.Response{Content: `{"category": "bug", "confidence": 0.8}`, Usage: &model.Usage{InputTokens: 30, OutputTokens: 8}},
+	)
+
+	result, err := svc.CompleteStructured(context.Background(), newStructuredRequest(), schema.MustFor[classification](), StructuredOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, model.Usage{InputTokens: 60, OutputTokens: 21}, result.Usage)
+	assert.Len(t, *completed, 3)
+
+	// 重试时带上上一次的输出和校验错误
+	requests := mockProvider.Requests()
+	require.Len(t, requests, 3)
+	last := requests[2].Messages
+	require.Len(t, last, 7)
+	assert.Equal(t, model.RoleAssistant, last[5].Role)
+	assert.Equal(t, `{"category": "defect", "confidence": 2}`, last[5].Content)
+	assert.Equal(t, model.RoleUser, last[6].Role)
+	assert.Contains(t, last[6].Content, `$.category: 值应为 ["bug", "feature", "question"] 之一`)
+	assert.Contains(t, last[6].Content, "$.confidence: 值应不大于 1")
+}
+
+func TestCompleteStructured_Failed(t *testing.T) {
+	svc, mockProvider, _ := newTestService(t)
+	var failed []sharedevents.SchemaValidationFailedPayload
+	require.NoError(t, svc.eventBus.Subscribe("SchemaValidationFailed", func(ctx context.Context, e sharedevents.Event) error {
+		failed = append(failed, e.Payload().(sharedevents.SchemaValidationFailedPayload))
+		return nil
+	}))
+	sch := schema.MustParse(`{"type": "object", "properties": {"answer": {"type": "string"}}, "required": ["answer"]}`)
+
+	// 重试用尽：发布 SchemaValidationFailed
+	mockProvider.SetHandler(func(req *model.ChatRequest) mock.Response {
+		return mock.Response{Content: `{"reply": "hi"}`}
+	})
+	_, err := svc.CompleteStructured(context.Background(), newStructuredRequest(), sch, StructuredOptions{MaxRetries: 1})
+	assert.ErrorIs(t, err, model.ErrSchemaValidation)
+	assert.ErrorContains(t, err, "$.answer: 缺少必填字段")
+	assert.Len(t, mockProvider.Requests(), 2)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "gpt-4o", failed[0].Model)
+	assert.Equal(t, `{"reply": "hi"}`, failed[0].Output)
+	assert.Equal(t, "$.answer: 缺少必填字段", failed[0].Error)
+	assert.JSONEq(t, string(sch.JSON()), failed[0].Schema)
+
+	// 负数不重试
+	mockProvider.Reset()
+	mockProvider.Enqueue(mock.Response{Content: "null"})
+	_, err = svc.CompleteStructured(context.Background(), newStructuredRequest(), sch, StructuredOptions{MaxRetries: -1})
+	assert.ErrorIs(t, err, model.ErrSchemaValidation)
+	assert.Len(t, mockProvider.Requests(), 1)
+	assert.Len(t, failed, 2)
+
+	// 调用失败直接返回，不重试也不发布 SchemaValidationFailed
+	mockProvider.Reset()
+	mockProvider.Enqueue(mock.Response{Err: errors.New("upstream down")})
+	_, err = svc.CompleteStructured(context.Background(), newStructuredRequest(), sch, StructuredOptions{})
+	assert.EqualError(t, err, "upstream down")
+	assert.Len(t, mockProvider.Requests(), 1)
+	assert.Len(t, failed, 2)
+
+	// Schema 为空
+	_, err = svc.CompleteStructured(context.Background(), newStructuredRequest(), nil, StructuredOptions{})
+	assert.ErrorIs(t, err, model.ErrInvalidSchema)
+}
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{`  {"n": 1}` + "\n", `{"n": 1}`},
+		{"```json\n{\"n\": 1}\n```", `{"n": 1}`},
+		{"```\n{\"n\": 1}\n```  ", `{"n": 1}`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ExtractJSON(tt.content))
+	}
+}
@@ -0,0 +1,77 @@
+package tokenizer
+
+import (
+	"unicode/utf8"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// MessageOverhead 每条消息除内容外的 Token 数（角色、分隔符等，参考 OpenAI 的计算方式）
+const MessageOverhead = 4
+
+// ReplyPriming 请求末尾为回复预留的 Token 数（assistant 前缀）
+const ReplyPriming = 3
+
+// Tokenizer 计算文本的 Token 数
+//
+// 不同模型的分词方式不同，实现可以是精确的（模型的词表）或估算的（Estimator）。
+// 实现必须是并发安全的。
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// Estimator 按字符估算 Token 数（不依赖词表）
+//
+// ASCII 字符按 4 个一个 Token，其他字符（如中文）每个字符一个 Token，
+// 对中英文混合的文本偏保守（宁可多估也不超出上下文窗口）。
+type Estimator struct{}
+
+// Count 估算文本的 Token 数
+func (Estimator) Count(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// CountMessage 计算一条消息的 Token 数（内容、工具调用和固定开销）
+func CountMessage(t Tokenizer, msg model.Message) int {
+	tokens := MessageOverhead + t.Count(msg.Content)
+	for _, call := range msg.ToolCalls {
+		tokens += t.Count(call.Name) + t.Count(call.Arguments)
+	}
+	if msg.ToolCallID != "" {
+		tokens += t.Count(msg.ToolCallID)
+	}
+	return tokens
+}
+
+// CountMessages 计算一组消息作为请求时的 Token 数（包括 ReplyPriming）
+func CountMessages(t Tokenizer, messages []model.Message) int {
+	tokens := ReplyPriming
+	for _, msg := range messages {
+		tokens += CountMessage(t, msg)
+	}
+	return tokens
+}
+
+// CountTools 计算工具定义的 Token 数（名称、描述和参数 Schema）
+func CountTools(t Tokenizer, tools []model.ToolDefinition) int {
+	tokens := 0
+	for _, def := range tools {
+		tokens += t.Count(def.Name) + t.Count(def.Description) + t.Count(string(def.Parameters))
+	}
+	return tokens
+}
+
+// CountRequest 计算请求的输入 Token 数（消息和工具定义）
+//
+// 调用模型前的预估（额度预占、cost 路由策略）都使用这个函数，保证同一请求的预估一致。
+func CountRequest(t Tokenizer, req *model.ChatRequest) int {
+	return CountMessages(t, req.Messages) + CountTools(t, req.Tools)
+}
@@ -0,0 +1,45 @@
+package tokenizer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimator_Count(t *testing.T) {
+	var e Estimator
+	assert.Equal(t, 0, e.Count(""))
+	assert.Equal(t, 1, e.Count("abc"))
+	assert.Equal(t, 3, e.Count("hello world"))
+	assert.Equal(t, 4, e.Count("预约牙医"))
+	assert.Equal(t, 4, e.Count("预约 dentist"))
+}
+
+func TestCountMessages(t *testing.T) {
+	var e Estimator
+	messages := []model.Message{
+		{Role: model.RoleUser, Content: "hello world"},
+		{Role: model.RoleAssistant, ToolCalls: []model.ToolCall{{ID: "call_1", Name: "list", Arguments: `{}`}}},
+		{Role: model.RoleTool, Content: `{"ok":true}`, ToolCallID: "call_1"},
+	}
+
+	assert.Equal(t, MessageOverhead+3, CountMessage(e, messages[0]))
+	assert.Equal(t, MessageOverhead+1+1, CountMessage(e, messages[1]))
+	assert.Equal(t, MessageOverhead+3+2, CountMessage(e, messages[2]))
+	assert.Equal(t, ReplyPriming+7+6+9, CountMessages(e, messages))
+}
+
+func TestCountTools(t *testing.T) {
+	tools := []model.ToolDefinition{{Name: "list", Description: "List items", Parameters: json.RawMessage(`{"type":"object"}`)}}
+	assert.Equal(t, 1+3+5, CountTools(Estimator{}, tools))
+}
+
+func TestCountRequest(t *testing.T) {
+	req := &model.ChatRequest{
+		Messages: []model.Message{{Role: model.RoleUser, Content: "hello world"}},
+		Tools:    []model.ToolDefinition{{Name: "list", Description: "List items", Parameters: json.RawMessage(`{"type":"object"}`)}},
+	}
+	assert.Equal(t, ReplyPriming+MessageOverhead+3+9, CountRequest(Estimator{}, req))
+}
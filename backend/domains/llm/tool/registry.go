@@ -0,0 +1,99 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+)
+
+// Registry 工具注册表
+//
+// 通常按请求创建（工具通过闭包绑定当前用户），注册完成后只读，可以并发调用。
+type Registry struct {
+	tools map[string]*Tool
+	order []string // 注册顺序（工具定义按此顺序发送给模型）
+}
+
+// NewRegistry 创建工具注册表
+func NewRegistry() *Registry {
+	return &Registry{tools: make(map[string]*Tool)}
+}
+
+// Register 注册工具（名称重复或定义无效时返回错误）
+func (r *Registry) Register(tools ...*Tool) error {
+	for _, t := range tools {
+		if err := t.validate(); err != nil {
+			return err
+		}
+		if _, ok := r.tools[t.Name]; ok {
+			return fmt.Errorf("tool %s already registered", t.Name)
+		}
+		r.tools[t.Name] = t
+		r.order = append(r.order, t.Name)
+	}
+	return nil
+}
+
+// Get 获取工具
+func (r *Registry) Get(name string) (*Tool, bool) {
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// Len 返回工具数量
+func (r *Registry) Len() int {
+	return len(r.order)
+}
+
+// Definitions 返回所有工具的定义（按注册顺序）
+func (r *Registry) Definitions() []model.ToolDefinition {
+	defs := make([]model.ToolDefinition, 0, len(r.order))
+	for _, name := range r.order {
+		defs = append(defs, r.tools[name].Definition())
+	}
+	return defs
+}
+
+// RequiresConfirmation 判断调用是否需要用户确认（未知工具不需要，调用时返回错误结果）
+func (r *Registry) RequiresConfirmation(call model.ToolCall) bool {
+	t, ok := r.tools[call.Name]
+	return ok && t.Destructive
+}
+
+// Call 执行工具调用
+//
+// 步骤：
+//  1. Lookup - 查找工具（不存在时返回 TOOL_NOT_FOUND 错误结果）
+//  2. Validate - 按参数 Schema 校验模型给出的参数（无效时返回 INVALID_TOOL_ARGUMENTS 错误结果）
+//  3. Execute - 调用 Handler，返回值序列化为 JSON
+//
+// 错误不会中断对话，而是作为结果发回模型，由模型决定如何处理。
+func (r *Registry) Call(ctx context.Context, call model.ToolCall) Result {
+	// Step 1: Lookup
+	t, ok := r.tools[call.Name]
+	if !ok {
+		return Failed(fmt.Errorf("%w: %s", ErrToolNotFound, call.Name))
+	}
+
+	// Step 2: Validate
+	args := json.RawMessage(call.Arguments)
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	if errs := t.Parameters.ValidateJSON(args); len(errs) > 0 {
+		return Failed(fmt.Errorf("%w: %s", ErrInvalidArguments, errs.Error()))
+	}
+
+	// Step 3: Execute
+	output, err := t.Handler(ctx, args)
+	if err != nil {
+		return Failed(err)
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return Failed(fmt.Errorf("encode tool output failed: %w", err))
+	}
+	return Result{Status: StatusOK, Output: data}
+}
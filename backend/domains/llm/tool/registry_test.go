@@ -0,0 +1,115 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetArgs struct {
+	Name  string  `json:"name" jsonschema:"minLength=1"`
+	Title *string `json:"title" jsonschema:"enum=mr|ms"`
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	greet, err := New("greet", "Greet someone", func(ctx context.Context, args greetArgs) (any, error) {
+		if args.Name == "error" {
+			return nil, errors.New("TASK_NOT_FOUND: 任务不存在")
+		}
+		greeting := "hello " + args.Name
+		if args.Title != nil {
+			greeting = "hello " + *args.Title + " " + args.Name
+		}
+		return map[string]string{"greeting": greeting}, nil
+	})
+	require.NoError(t, err)
+
+	remove, err := New("remove", "Remove something", func(ctx context.Context, args struct{}) (any, error) {
+		return true, nil
+	})
+	require.NoError(t, err)
+	remove.Destructive = true
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(greet, remove))
+	return registry
+}
+
+func TestNew_GeneratesSchema(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	defs := registry.Definitions()
+	require.Len(t, defs, 2)
+	assert.Equal(t, "greet", defs[0].Name)
+	assert.Equal(t, "remove", defs[1].Name)
+
+	var params map[string]any
+	require.NoError(t, json.Unmarshal(defs[0].Parameters, &params))
+	assert.Equal(t, "object", params["type"])
+	assert.ElementsMatch(t, []any{"name", "title"}, params["required"])
+	assert.Equal(t, false, params["additionalProperties"])
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	duplicate, _ := New("greet", "again", func(ctx context.Context, args struct{}) (any, error) { return nil, nil })
+	assert.Error(t, registry.Register(duplicate))
+
+	invalid, _ := New("bad name", "spaces", func(ctx context.Context, args struct{}) (any, error) { return nil, nil })
+	assert.Error(t, registry.Register(invalid))
+
+	assert.Error(t, registry.Register(&Tool{Name: "raw", Handler: func(ctx context.Context, args json.RawMessage) (any, error) { return nil, nil }}))
+	assert.Equal(t, 2, registry.Len())
+}
+
+func TestRegistry_Call(t *testing.T) {
+	registry := newTestRegistry(t)
+	ctx := context.Background()
+
+	t.Run("成功", func(t *testing.T) {
+		result := registry.Call(ctx, model.ToolCall{ID: "1", Name: "greet", Arguments: `{"name":"bob","title":"mr"}`})
+		assert.Equal(t, StatusOK, result.Status)
+		assert.JSONEq(t, `{"greeting":"hello mr bob"}`, string(result.Output))
+		assert.JSONEq(t, `{"status":"ok","output":{"greeting":"hello mr bob"}}`, result.JSON())
+	})
+
+	t.Run("工具不存在", func(t *testing.T) {
+		result := registry.Call(ctx, model.ToolCall{ID: "1", Name: "missing", Arguments: `{}`})
+		assert.Equal(t, StatusError, result.Status)
+		assert.Contains(t, result.Error, "TOOL_NOT_FOUND")
+	})
+
+	t.Run("参数不符合 Schema", func(t *testing.T) {
+		result := registry.Call(ctx, model.ToolCall{ID: "1", Name: "greet", Arguments: `{"name":"","title":"dr"}`})
+		assert.Equal(t, StatusError, result.Status)
+		assert.Contains(t, result.Error, "INVALID_TOOL_ARGUMENTS")
+		assert.Contains(t, result.Error, "$.name")
+		assert.Contains(t, result.Error, "$.title")
+	})
+
+	t.Run("参数不是 JSON", func(t *testing.T) {
+		result := registry.Call(ctx, model.ToolCall{ID: "1", Name: "greet", Arguments: `name=bob`})
+		assert.Contains(t, result.Error, "INVALID_TOOL_ARGUMENTS")
+	})
+
+	t.Run("执行失败", func(t *testing.T) {
+		result := registry.Call(ctx, model.ToolCall{ID: "1", Name: "greet", Arguments: `{"name":"error","title":null}`})
+		assert.Equal(t, StatusError, result.Status)
+		assert.Equal(t, "TASK_NOT_FOUND: 任务不存在", result.Error)
+	})
+}
+
+func TestRegistry_RequiresConfirmation(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	assert.True(t, registry.RequiresConfirmation(model.ToolCall{Name: "remove"}))
+	assert.False(t, registry.RequiresConfirmation(model.ToolCall{Name: "greet"}))
+	assert.False(t, registry.RequiresConfirmation(model.ToolCall{Name: "missing"}))
+	assert.JSONEq(t, `{"status":"rejected","error":"用户拒绝执行此操作"}`, Rejected().JSON())
+}
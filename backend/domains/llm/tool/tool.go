@@ -0,0 +1,114 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+)
+
+// 工具调用错误
+var (
+	ErrToolNotFound     = fmt.Errorf("TOOL_NOT_FOUND: 工具不存在")
+	ErrInvalidArguments = fmt.Errorf("INVALID_TOOL_ARGUMENTS: 工具参数无效")
+)
+
+// namePattern 工具名称规则（与 OpenAI function name 一致）
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Handler 工具实现
+//
+// args 是已通过参数 Schema 校验的 JSON，返回值序列化为 JSON 后发送给模型。
+type Handler func(ctx context.Context, args json.RawMessage) (any, error)
+
+// Tool 可供模型调用的 Go 函数
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  *schema.Schema // 参数的 JSON Schema（必须是对象）
+	Destructive bool           // 修改或删除已有数据，执行前需要用户确认
+	Handler     Handler
+}
+
+// New 由参数类型创建工具
+//
+// 参数 Schema 由 schema.For[T] 生成（规则见 schema.For），
+// 调用时参数解码为 T 后传给 fn。
+func New[T any](name, description string, fn func(ctx context.Context, args T) (any, error)) (*Tool, error) {
+	params, err := schema.For[T]()
+	if err != nil {
+		return nil, fmt.Errorf("generate parameters schema for %s failed: %w", name, err)
+	}
+	return &Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  params,
+		Handler: func(ctx context.Context, raw json.RawMessage) (any, error) {
+			var args T
+			if err := json.Unmarshal(raw, &args); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+			}
+			return fn(ctx, args)
+		},
+	}, nil
+}
+
+// Definition 返回发送给模型的工具定义
+func (t *Tool) Definition() model.ToolDefinition {
+	return model.ToolDefinition{
+		Name:        t.Name,
+		Description: t.Description,
+		Parameters:  t.Parameters.JSON(),
+	}
+}
+
+// validate 验证工具定义
+func (t *Tool) validate() error {
+	if !namePattern.MatchString(t.Name) {
+		return fmt.Errorf("invalid tool name %q", t.Name)
+	}
+	if t.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", t.Name)
+	}
+	if t.Parameters == nil || len(t.Parameters.Type) != 1 || t.Parameters.Type[0] != schema.TypeObject {
+		return fmt.Errorf("tool %s parameters must be an object schema", t.Name)
+	}
+	return nil
+}
+
+// Status 工具调用结果状态
+type Status string
+
+const (
+	StatusOK       Status = "ok"       // 执行成功
+	StatusError    Status = "error"    // 参数无效或执行失败
+	StatusRejected Status = "rejected" // 用户拒绝执行
+)
+
+// Result 工具调用结果
+//
+// 序列化后作为 tool 消息的内容发送给模型，模型据此决定下一步。
+type Result struct {
+	Status Status          `json:"status"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// Rejected 用户拒绝执行的结果
+func Rejected() Result {
+	return Result{Status: StatusRejected, Error: "用户拒绝执行此操作"}
+}
+
+// Failed 执行失败的结果
+func Failed(err error) Result {
+	return Result{Status: StatusError, Error: err.Error()}
+}
+
+// JSON 返回结果的 JSON 文本
+func (r Result) JSON() string {
+	data, _ := json.Marshal(r)
+	return string(data)
+}
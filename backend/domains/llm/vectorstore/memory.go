@@ -0,0 +1,83 @@
+package vectorstore
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore 进程内向量存储（测试和单实例开发环境）
+//
+// 查询时逐条计算相似度（暴力搜索），适合少量数据；重启后数据丢失。
+type MemoryStore struct {
+	mu          sync.RWMutex
+	collections map[string]map[string]Record
+}
+
+// NewMemoryStore 创建进程内向量存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]map[string]Record)}
+}
+
+// Upsert 保存记录（保存向量副本）
+func (s *MemoryStore) Upsert(ctx context.Context, collection string, records ...Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collection]
+	if !ok {
+		c = make(map[string]Record)
+		s.collections[collection] = c
+	}
+	for _, rec := range records {
+		rec.Vector = append([]float32(nil), rec.Vector...)
+		c[rec.ID] = rec
+	}
+	return nil
+}
+
+// Delete 删除记录
+func (s *MemoryStore) Delete(ctx context.Context, collection string, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		delete(s.collections[collection], id)
+	}
+	return nil
+}
+
+// Search 按余弦相似度降序返回用户的记录（相似度相同时按 ID）
+func (s *MemoryStore) Search(ctx context.Context, collection string, query Query) ([]Match, error) {
+	if err := query.validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	matches := make([]Match, 0)
+	for _, rec := range s.collections[collection] {
+		if rec.OwnerID != query.OwnerID || rec.Model != query.Model {
+			continue
+		}
+		matches = append(matches, Match{ID: rec.ID, Score: CosineSimilarity(rec.Vector, query.Vector)})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > query.Limit {
+		matches = matches[:query.Limit]
+	}
+	return matches, nil
+}
+
+// Len 返回集合中的记录数
+func (s *MemoryStore) Len(collection string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.collections[collection])
+}
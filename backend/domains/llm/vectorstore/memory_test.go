@@ -0,0 +1,52 @@
+package vectorstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	require.NoError(t, store.Upsert(ctx, "tasks",
+		Record{ID: "a", OwnerID: "u1", Model: "m", Vector: []float32{1, 0}},
+		Record{ID: "b", OwnerID: "u1", Model: "m", Vector: []float32{0.6, 0.8}},
+		Record{ID: "c", OwnerID: "u1", Model: "m", Vector: []float32{0, 1}},
+		Record{ID: "d", OwnerID: "u2", Model: "m", Vector: []float32{1, 0}},
+		Record{ID: "e", OwnerID: "u1", Model: "other", Vector: []float32{1, 0}},
+	))
+	require.NoError(t, store.Upsert(ctx, "notes", Record{ID: "a", OwnerID: "u1", Model: "m", Vector: []float32{1, 0}}))
+
+	// 按相似度降序，只返回同一用户、同一模型的记录
+	matches, err := store.Search(ctx, "tasks", Query{OwnerID: "u1", Model: "m", Vector: []float32{1, 0}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "a", matches[0].ID)
+	assert.InDelta(t, 1.0, matches[0].Score, 1e-6)
+	assert.Equal(t, "b", matches[1].ID)
+	assert.InDelta(t, 0.6, matches[1].Score, 1e-6)
+
+	// 覆盖和删除
+	require.NoError(t, store.Upsert(ctx, "tasks", Record{ID: "c", OwnerID: "u1", Model: "m", Vector: []float32{1, 0.1}}))
+	require.NoError(t, store.Delete(ctx, "tasks", "a", "missing"))
+	matches, err = store.Search(ctx, "tasks", Query{OwnerID: "u1", Model: "m", Vector: []float32{1, 0}, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "c", matches[0].ID)
+	assert.Equal(t, 4, store.Len("tasks"))
+	assert.Equal(t, 1, store.Len("notes"))
+
+	_, err = store.Search(ctx, "tasks", Query{Model: "m", Vector: []float32{1, 0}, Limit: 10})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-6)
+	assert.InDelta(t, -1.0, CosineSimilarity([]float32{1, 0}, []float32{-1, 0}), 1e-6)
+	assert.Zero(t, CosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}))
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 0}))
+}
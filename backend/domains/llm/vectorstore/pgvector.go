@@ -0,0 +1,140 @@
+package vectorstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// PGStore 基于 pgvector 的向量存储
+//
+// 表结构见 database/pgvector.sql（需要 vector 扩展）。相似度由 <=> 运算符（余弦距离）计算，
+// 只与同一模型、同一维度的向量比较。
+type PGStore struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+	now     func() time.Time
+}
+
+// NewPGStore 创建 pgvector 向量存储
+func NewPGStore(db *sql.DB) *PGStore {
+	return &PGStore{
+		db:      db,
+		dialect: goqu.Dialect("postgres"),
+		now:     time.Now,
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (s *PGStore) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, s.db)
+}
+
+// Upsert 保存记录（已存在时覆盖）
+func (s *PGStore) Upsert(ctx context.Context, collection string, records ...Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	ds := s.dialect.Insert("embeddings").
+		Cols("collection", "id", "owner_id", "model", "embedding", "updated_at")
+	for _, rec := range records {
+		ds = ds.Vals(goqu.Vals{collection, rec.ID, rec.OwnerID, rec.Model, vectorLiteral(rec.Vector), now})
+	}
+	query, args, err := ds.OnConflict(goqu.DoUpdate("collection, id", goqu.Record{
+		"owner_id":   goqu.I("excluded.owner_id"),
+		"model":      goqu.I("excluded.model"),
+		"embedding":  goqu.I("excluded.embedding"),
+		"updated_at": goqu.I("excluded.updated_at"),
+	})).ToSQL()
+	if err != nil {
+		return fmt.Errorf("build upsert embeddings query failed: %w", err)
+	}
+
+	if _, err := s.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("upsert embeddings failed: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除记录
+func (s *PGStore) Delete(ctx context.Context, collection string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := s.dialect.Delete("embeddings").
+		Where(
+			goqu.C("collection").Eq(collection),
+			goqu.C("id").In(ids),
+		).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build delete embeddings query failed: %w", err)
+	}
+
+	if _, err := s.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("delete embeddings failed: %w", err)
+	}
+	return nil
+}
+
+// Search 按余弦相似度降序返回用户的记录
+func (s *PGStore) Search(ctx context.Context, collection string, q Query) ([]Match, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	vector := vectorLiteral(q.Vector)
+	query, args, err := s.dialect.From("embeddings").
+		Select(
+			goqu.C("id"),
+			goqu.L("1 - (embedding <=> ?)", vector).As("score"),
+		).
+		Where(
+			goqu.C("collection").Eq(collection),
+			goqu.C("owner_id").Eq(q.OwnerID),
+			goqu.C("model").Eq(q.Model),
+			goqu.L("vector_dims(embedding) = ?", len(q.Vector)),
+		).
+		Order(goqu.L("embedding <=> ?", vector).Asc(), goqu.C("id").Asc()).
+		Limit(uint(q.Limit)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build search embeddings query failed: %w", err)
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search embeddings failed: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]Match, 0)
+	for rows.Next() {
+		var m Match
+		var score sql.NullFloat64 // 零向量的余弦距离为 NaN
+		if err := rows.Scan(&m.ID, &score); err != nil {
+			return nil, fmt.Errorf("scan embedding match failed: %w", err)
+		}
+		m.Score = score.Float64
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// vectorLiteral 将向量转换为 pgvector 文本格式（'[0.1,0.2]'::vector）
+func vectorLiteral(v []float32) goqu.Expression {
+	parts := make([]string, len(v))
+	for i, x := range v {
+		parts[i] = strconv.FormatFloat(float64(x), 'g', -1, 32)
+	}
+	return goqu.Cast(goqu.V("["+strings.Join(parts, ",")+"]"), "vector")
+}
@@ -0,0 +1,62 @@
+package vectorstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPGStore(t *testing.T) (*PGStore, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	store := NewPGStore(db)
+	store.now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	return store, mock
+}
+
+func TestPGStore_Upsert(t *testing.T) {
+	store, mock := newTestPGStore(t)
+
+	mock.ExpectExec(`INSERT INTO "embeddings" \("collection", "id", "owner_id", "model", "embedding", "updated_at"\) VALUES ` +
+		`\('tasks', 'a', 'u1', 'm', CAST\('\[1,0.5\]' AS vector\), '2026-10-18T12:00:00Z'\), \('tasks', 'b', .+\) ` +
+		`ON CONFLICT \(collection, id\) DO UPDATE SET "embedding"="excluded"."embedding",.+"owner_id"="excluded"."owner_id"`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := store.Upsert(context.Background(), "tasks",
+		Record{ID: "a", OwnerID: "u1", Model: "m", Vector: []float32{1, 0.5}},
+		Record{ID: "b", OwnerID: "u1", Model: "m", Vector: []float32{0, 1}},
+	)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPGStore_Delete(t *testing.T) {
+	store, mock := newTestPGStore(t)
+
+	mock.ExpectExec(`DELETE FROM "embeddings" WHERE \(\("collection" = 'tasks'\) AND \("id" IN \('a', 'b'\)\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, store.Delete(context.Background(), "tasks", "a", "b"))
+	require.NoError(t, store.Delete(context.Background(), "tasks"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPGStore_Search(t *testing.T) {
+	store, mock := newTestPGStore(t)
+
+	mock.ExpectQuery(`SELECT "id", 1 - \(embedding <=> CAST\('\[1,0\]' AS vector\)\) AS "score" FROM "embeddings" ` +
+		`WHERE \(\("collection" = 'tasks'\) AND \("owner_id" = 'u1'\) AND \("model" = 'm'\) AND vector_dims\(embedding\) = 2\) ` +
+		`ORDER BY embedding <=> CAST\('\[1,0\]' AS vector\) ASC, "id" ASC LIMIT 5`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "score"}).AddRow("a", 0.9).AddRow("b", nil))
+
+	matches, err := store.Search(context.Background(), "tasks", Query{OwnerID: "u1", Model: "m", Vector: []float32{1, 0}, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []Match{{ID: "a", Score: 0.9}, {ID: "b", Score: 0}}, matches)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
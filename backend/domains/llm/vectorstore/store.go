@@ -0,0 +1,71 @@
+package vectorstore
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// ErrInvalidQuery 查询缺少必需的条件
+var ErrInvalidQuery = errors.New("INVALID_VECTOR_QUERY: 向量查询无效")
+
+// Record 向量记录
+type Record struct {
+	ID      string    // 源数据 ID（如任务 ID），在集合内唯一
+	OwnerID string    // 所属用户（查询时必须指定，不同用户的记录互不可见）
+	Model   string    // 生成向量的模型（只与同一模型的向量比较）
+	Vector  []float32 // 向量
+}
+
+// Query 相似度查询
+type Query struct {
+	OwnerID string    // 所属用户（必需）
+	Model   string    // 生成查询向量的模型（必需）
+	Vector  []float32 // 查询向量
+	Limit   int       // 最多返回的记录数（必需）
+}
+
+// validate 验证查询
+func (q Query) validate() error {
+	if q.OwnerID == "" || q.Model == "" || len(q.Vector) == 0 || q.Limit <= 0 {
+		return ErrInvalidQuery
+	}
+	return nil
+}
+
+// Match 查询结果
+type Match struct {
+	ID    string
+	Score float64 // 余弦相似度（-1 ~ 1，越大越相似）
+}
+
+// Store 向量存储
+//
+// 集合（collection）区分不同类型的源数据（如 tasks），记录按 (collection, ID) 唯一。
+type Store interface {
+	// Upsert 保存记录（已存在时覆盖）
+	Upsert(ctx context.Context, collection string, records ...Record) error
+
+	// Delete 删除记录（不存在的 ID 忽略）
+	Delete(ctx context.Context, collection string, ids ...string) error
+
+	// Search 按余弦相似度降序返回用户的记录
+	Search(ctx context.Context, collection string, query Query) ([]Match, error)
+}
+
+// CosineSimilarity 计算余弦相似度（维度不同或有零向量时为 0）
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+)
+
+// DTO 转换层
+//
+// 命名规范：
+// - toXxx:         HTTP DTO → Domain
+// - toXxxResponse: Domain → HTTP Response
+
+// toTemplateParams 将发布版本请求转换为模板内容
+func toTemplateParams(req dto.CreateVersionRequest) model.TemplateParams {
+	params := model.TemplateParams{
+		Name:        req.Name,
+		Version:     req.Version,
+		Description: req.Description,
+		Parameters: model.Parameters{
+			Provider:    req.Parameters.Provider,
+			Model:       req.Parameters.Model,
+			Temperature: req.Parameters.Temperature,
+			TopP:        req.Parameters.TopP,
+			MaxTokens:   req.Parameters.MaxTokens,
+			CacheTTL:    time.Duration(req.Parameters.CacheTTLSeconds) * time.Second,
+		},
+	}
+	for _, m := range req.Messages {
+		params.Messages = append(params.Messages, model.PromptMessage{Role: llmmodel.Role(m.Role), Content: m.Content})
+	}
+	for _, v := range req.Variables {
+		params.Variables = append(params.Variables, model.Variable{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		})
+	}
+	return params
+}
+
+// toParametersDTO 将生成参数转换为 DTO
+func toParametersDTO(p model.Parameters) dto.ParametersDTO {
+	return dto.ParametersDTO{
+		Provider:        p.Provider,
+		Model:           p.Model,
+		Temperature:     p.Temperature,
+		TopP:            p.TopP,
+		MaxTokens:       p.MaxTokens,
+		CacheTTLSeconds: int(p.CacheTTL / time.Second),
+	}
+}
+
+// toVersionResponse 将模板版本转换为 HTTP 响应
+func toVersionResponse(t *model.PromptTemplate) dto.PromptVersionResponse {
+	messages := make([]dto.PromptMessageDTO, 0, len(t.Messages))
+	for _, m := range t.Messages {
+		messages = append(messages, dto.PromptMessageDTO{Role: string(m.Role), Content: m.Content})
+	}
+	variables := make([]dto.VariableDTO, 0, len(t.Variables))
+	for _, v := range t.Variables {
+		variables = append(variables, dto.VariableDTO{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		})
+	}
+	return dto.PromptVersionResponse{
+		PromptID:    t.ID,
+		Name:        t.Name,
+		Version:     t.Version,
+		Description: t.Description,
+		Source:      string(t.Source),
+		Messages:    messages,
+		Variables:   variables,
+		Parameters:  toParametersDTO(t.Parameters),
+		CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toPromptResponse 将提示词转换为 HTTP 响应
+func toPromptResponse(p *model.Prompt) dto.PromptResponse {
+	versions := make([]dto.VersionSummaryDTO, 0, len(p.Versions))
+	for _, t := range p.Versions {
+		versions = append(versions, dto.VersionSummaryDTO{
+			Version:     t.Version,
+			Description: t.Description,
+			Source:      string(t.Source),
+			CreatedAt:   t.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	resp := dto.PromptResponse{
+		Name:          p.Name,
+		PinnedVersion: p.PinnedVersion,
+		Versions:      versions,
+	}
+	if active := p.Active(); active != nil {
+		resp.ActiveVersion = active.Version
+	}
+	return resp
+}
+
+// toListPromptsResponse 将提示词列表转换为 HTTP 响应
+func toListPromptsResponse(prompts []*model.Prompt) dto.ListPromptsResponse {
+	items := make([]dto.PromptResponse, 0, len(prompts))
+	for _, p := range prompts {
+		items = append(items, toPromptResponse(p))
+	}
+	return dto.ListPromptsResponse{Prompts: items, Total: len(items)}
+}
+
+// toRenderPromptResponse 将渲染结果转换为 HTTP 响应
+func toRenderPromptResponse(r *model.RenderedPrompt) dto.RenderPromptResponse {
+	messages := make([]dto.PromptMessageDTO, 0, len(r.Messages))
+	for _, m := range r.Messages {
+		messages = append(messages, dto.PromptMessageDTO{Role: string(m.Role), Content: m.Content})
+	}
+	return dto.RenderPromptResponse{
+		Name:       r.Name,
+		Version:    r.Version,
+		Messages:   messages,
+		Parameters: toParametersDTO(r.Parameters),
+	}
+}
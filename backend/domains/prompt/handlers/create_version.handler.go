@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+)
+
+// CreateVersionHandler 发布提示词的新版本（HTTP 适配层，管理员）
+//
+// 用例：CreateVersion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/admin/prompts
+//
+// 名称不存在时创建新的提示词；版本创建后不可修改。
+//
+// 业务逻辑在 service.PromptService.CreateVersion() 中实现
+func (deps *HandlerDependencies) CreateVersionHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 解析并验证请求体
+	var req dto.CreateVersionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	output, err := deps.promptService.CreateVersion(ctx, service.CreateVersionInput{Params: toTemplateParams(req)})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	c.JSON(201, toVersionResponse(output.Template))
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetPromptHandler 获取提示词（HTTP 适配层，管理员）
+//
+// 用例：GetPrompt（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/admin/prompts/:name
+//
+// 业务逻辑在 service.PromptService.GetPrompt() 中实现
+func (deps *HandlerDependencies) GetPromptHandler(ctx context.Context, c *app.RequestContext) {
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	output, err := deps.promptService.GetPrompt(ctx, name)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toPromptResponse(output.Prompt))
+}
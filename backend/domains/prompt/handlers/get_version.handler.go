@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetVersionHandler 获取提示词的指定版本（HTTP 适配层，管理员）
+//
+// 用例：GetVersion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/admin/prompts/:name/versions/:version
+//
+// 业务逻辑在 service.PromptService.GetVersion() 中实现
+func (deps *HandlerDependencies) GetVersionHandler(ctx context.Context, c *app.RequestContext) {
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	output, err := deps.promptService.GetVersion(ctx, name, c.Param("version"))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toVersionResponse(output.Template))
+}
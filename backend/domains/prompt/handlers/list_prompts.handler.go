@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ListPromptsHandler 列出提示词（HTTP 适配层，管理员）
+//
+// 用例：ListPrompts（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/admin/prompts
+//
+// 返回所有提示词（内置和管理员创建的）、版本列表和生效版本，不经过缓存。
+//
+// 业务逻辑在 service.PromptService.ListPrompts() 中实现
+func (deps *HandlerDependencies) ListPromptsHandler(ctx context.Context, c *app.RequestContext) {
+	output, err := deps.promptService.ListPrompts(ctx)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toListPromptsResponse(output.Prompts))
+}
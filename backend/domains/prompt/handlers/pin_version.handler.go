@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+)
+
+// PinVersionHandler 固定生效的版本（HTTP 适配层，管理员）
+//
+// 用例：PinVersion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/admin/prompts/:name/pin
+//
+// 固定后发布的新版本不会自动生效。
+//
+// 业务逻辑在 service.PromptService.PinVersion() 中实现
+func (deps *HandlerDependencies) PinVersionHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取提示词名称
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.PinVersionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.promptService.PinVersion(ctx, service.PinVersionInput{Name: name, Version: req.Version})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toPromptResponse(output.Prompt))
+}
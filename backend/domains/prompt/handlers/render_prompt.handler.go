@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+)
+
+// RenderPromptHandler 渲染预览（HTTP 适配层，管理员）
+//
+// 用例：RenderPrompt（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/admin/prompts/:name/render
+//
+// 使用与业务调用相同的渲染逻辑，必填变量缺失时返回 400 PROMPT_VARIABLE_MISSING。
+//
+// 业务逻辑在 service.PromptService.Render() 中实现
+func (deps *HandlerDependencies) RenderPromptHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取提示词名称
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.RenderPromptRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	rendered, err := deps.promptService.Render(ctx, service.RenderInput{
+		Name:      name,
+		Version:   req.Version,
+		Variables: req.Variables,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toRenderPromptResponse(rendered))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// RollbackHandler 回滚到上一个版本（HTTP 适配层，管理员）
+//
+// 用例：Rollback（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/admin/prompts/:name/rollback
+//
+// 固定比当前生效版本更早的最高版本；没有更早的版本时返回 409 NO_PREVIOUS_VERSION。
+//
+// 业务逻辑在 service.PromptService.Rollback() 中实现
+func (deps *HandlerDependencies) RollbackHandler(ctx context.Context, c *app.RequestContext) {
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	output, err := deps.promptService.Rollback(ctx, name)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toPromptResponse(output.Prompt))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+)
+
+// HandlerDependencies Handler 依赖容器
+//
+// 只持有 Handler 需要的依赖，不包含业务逻辑；
+// 提示词注册表的业务逻辑在 service.PromptService 中实现。
+type HandlerDependencies struct {
+	promptService *service.PromptService
+}
+
+// NewHandlerDependencies 创建新的依赖容器
+//
+// 参数：
+//   - promptService: 提示词服务
+//
+// 返回：
+//   - *HandlerDependencies: 依赖容器实例
+func NewHandlerDependencies(promptService *service.PromptService) *HandlerDependencies {
+	return &HandlerDependencies{
+		promptService: promptService,
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// UnpinVersionHandler 取消固定，恢复跟随最新版本（HTTP 适配层，管理员）
+//
+// 用例：UnpinVersion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: DELETE
+//   - Path: /api/admin/prompts/:name/pin
+//
+// 业务逻辑在 service.PromptService.Unpin() 中实现
+func (deps *HandlerDependencies) UnpinVersionHandler(ctx context.Context, c *app.RequestContext) {
+	name, ok := requirePromptName(c)
+	if !ok {
+		return
+	}
+
+	output, err := deps.promptService.Unpin(ctx, name)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	c.JSON(200, toPromptResponse(output.Prompt))
+}
@@ -0,0 +1,100 @@
+package dto
+
+// 验证规则使用 pkg/validator（validate 标签）：
+// 生成参数使用 temperature、top_p、token_count 规则，默认模型和提供商使用 model_name、provider 规则（读取模型目录）。
+
+// PromptMessageDTO 模板消息（内容中可以使用 {{name}} 占位符）
+type PromptMessageDTO struct {
+	Role    string `json:"role" validate:"required,message_role"`
+	Content string `json:"content" validate:"required"`
+}
+
+// VariableDTO 模板变量
+type VariableDTO struct {
+	Name        string `json:"name" validate:"required,max=50"`
+	Description string `json:"description,omitempty" validate:"max=200"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+}
+
+// ParametersDTO 默认模型和生成参数（都可选）
+type ParametersDTO struct {
+	Provider    string   `json:"provider,omitempty" validate:"omitempty,provider"`
+	Model       string   `json:"model,omitempty" validate:"omitempty,model_name"`
+	Temperature *float64 `json:"temperature,omitempty" validate:"omitempty,temperature"`
+	TopP        *float64 `json:"top_p,omitempty" validate:"omitempty,top_p"`
+	MaxTokens   int      `json:"max_tokens,omitempty" validate:"omitempty,token_count"`
+	// 响应缓存有效期（秒，0 使用默认值，最多 30 天）
+	CacheTTLSeconds int `json:"cache_ttl_seconds,omitempty" validate:"omitempty,min=0,max=2592000"`
+}
+
+// CreateVersionRequest 发布新版本请求
+type CreateVersionRequest struct {
+	Name        string             `json:"name" validate:"required,max=100"`
+	Version     string             `json:"version" validate:"required,max=32"`
+	Description string             `json:"description" validate:"max=500"`
+	Messages    []PromptMessageDTO `json:"messages" validate:"required,min=1,max=20,dive"`
+	Variables   []VariableDTO      `json:"variables" validate:"max=30,dive"`
+	Parameters  ParametersDTO      `json:"parameters"`
+}
+
+// PinVersionRequest 固定版本请求
+type PinVersionRequest struct {
+	Version string `json:"version" validate:"required,max=32"`
+}
+
+// RenderPromptRequest 渲染预览请求
+type RenderPromptRequest struct {
+	Version   string            `json:"version,omitempty"` // 为空时使用生效版本
+	Variables map[string]string `json:"variables"`
+}
+
+// PromptVersionResponse 提示词版本响应
+type PromptVersionResponse struct {
+	PromptID    string             `json:"prompt_id"`
+	Name        string             `json:"name"`
+	Version     string             `json:"version"`
+	Description string             `json:"description"`
+	Source      string             `json:"source"` // embedded, db
+	Messages    []PromptMessageDTO `json:"messages"`
+	Variables   []VariableDTO      `json:"variables"`
+	Parameters  ParametersDTO      `json:"parameters"`
+	CreatedAt   string             `json:"created_at"`
+}
+
+// VersionSummaryDTO 版本摘要
+type VersionSummaryDTO struct {
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// PromptResponse 提示词响应（所有版本和生效版本）
+type PromptResponse struct {
+	Name          string              `json:"name"`
+	ActiveVersion string              `json:"active_version"`
+	PinnedVersion string              `json:"pinned_version,omitempty"` // 为空表示跟随最新版本
+	Versions      []VersionSummaryDTO `json:"versions"`                 // 从新到旧
+}
+
+// ListPromptsResponse 列出提示词响应
+type ListPromptsResponse struct {
+	Prompts []PromptResponse `json:"prompts"`
+	Total   int              `json:"total"`
+}
+
+// RenderPromptResponse 渲染预览响应
+type RenderPromptResponse struct {
+	Name       string             `json:"name"`
+	Version    string             `json:"version"`
+	Messages   []PromptMessageDTO `json:"messages"`
+	Parameters ParametersDTO      `json:"parameters"`
+}
+
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Error   string `json:"error"`             // 错误码
+	Message string `json:"message"`           // 错误消息
+	Details string `json:"details,omitempty"` // 详细信息（可选）
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/handlers"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// RegisterRoutes 注册提示词领域的路由
+//
+// 所有路由都需要认证（AuthMiddleware）和管理员权限（AdminMiddleware）。
+//
+// 路由列表：
+//   - GET    /api/admin/prompts                          - 列出提示词
+//   - POST   /api/admin/prompts                          - 发布新版本
+//   - GET    /api/admin/prompts/:name                    - 获取提示词（版本列表和生效版本）
+//   - GET    /api/admin/prompts/:name/versions/:version  - 获取指定版本
+//   - PUT    /api/admin/prompts/:name/pin                - 固定生效的版本
+//   - DELETE /api/admin/prompts/:name/pin                - 取消固定（跟随最新版本）
+//   - POST   /api/admin/prompts/:name/rollback           - 回滚到上一个版本
+//   - POST   /api/admin/prompts/:name/render             - 渲染预览
+func RegisterRoutes(
+	r *route.RouterGroup,
+	deps *handlers.HandlerDependencies,
+	authMiddleware *middleware.AuthMiddleware,
+	adminMiddleware *middleware.AdminMiddleware,
+) {
+	prompts := r.Group("/admin/prompts", authMiddleware.Handle(), adminMiddleware.Handle())
+	{
+		prompts.GET("", deps.ListPromptsHandler)
+		prompts.POST("", deps.CreateVersionHandler)
+		prompts.GET("/:name", deps.GetPromptHandler)
+		prompts.GET("/:name/versions/:version", deps.GetVersionHandler)
+		prompts.PUT("/:name/pin", deps.PinVersionHandler)
+		prompts.DELETE("/:name/pin", deps.UnpinVersionHandler)
+		prompts.POST("/:name/rollback", deps.RollbackHandler)
+		prompts.POST("/:name/render", deps.RenderPromptHandler)
+	}
+}
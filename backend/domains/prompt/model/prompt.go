@@ -0,0 +1,371 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	pkgvalidator "github.com/erweixin/go-genai-stack/backend/pkg/validator"
+	"github.com/google/uuid"
+)
+
+// 字段限制（与 prompt_templates 表一致）
+const (
+	MaxPromptNameLength   = 100
+	MaxDescriptionLength  = 500
+	MaxPromptMessages     = 20
+	MaxPromptVariables    = 30
+	MaxVariableNameLength = 50
+	MaxCacheTTL           = 30 * 24 * time.Hour
+)
+
+// Source 模板来源
+type Source string
+
+const (
+	SourceEmbedded Source = "embedded" // 随程序发布的模板文件（只读）
+	SourceDB       Source = "db"       // 管理员通过 API 创建
+)
+
+// 提示词领域错误定义
+var (
+	ErrInvalidPromptName     = fmt.Errorf("INVALID_PROMPT_NAME: 名称只能包含小写字母、数字、.、- 和 _，且不能超过 100 字符")
+	ErrInvalidVersion        = fmt.Errorf("INVALID_VERSION: 版本号必须是语义化版本（如 1.2.0）")
+	ErrInvalidDescription    = fmt.Errorf("INVALID_DESCRIPTION: 描述不能超过 500 字符")
+	ErrInvalidMessages       = fmt.Errorf("INVALID_MESSAGES: 模板消息不能为空（最多 20 条），角色只能是 system、user、assistant")
+	ErrInvalidVariable       = fmt.Errorf("INVALID_VARIABLE: 变量名只能包含字母、数字和下划线，且不能重复")
+	ErrUndeclaredVariable    = fmt.Errorf("UNDECLARED_VARIABLE: 模板使用了未声明的变量")
+	ErrInvalidTemperature    = fmt.Errorf("INVALID_TEMPERATURE: temperature 必须在 0-2 之间")
+	ErrInvalidTopP           = fmt.Errorf("INVALID_TOP_P: top_p 必须在 0-1 之间")
+	ErrInvalidMaxTokens      = fmt.Errorf("INVALID_MAX_TOKENS: max_tokens 不能为负数或超过 1000000")
+	ErrPromptVariableMissing = fmt.Errorf("PROMPT_VARIABLE_MISSING: 缺少必填的模板变量")
+	ErrInvalidModel          = fmt.Errorf("INVALID_MODEL: 提供商或模型名称过长")
+	ErrInvalidCacheTTL       = fmt.Errorf("INVALID_CACHE_TTL: 缓存有效期不能为负数或超过 30 天")
+)
+
+var (
+	// promptNamePattern 模板名称，如 task.breakdown、chat-title
+	promptNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+	// versionPattern 语义化版本 MAJOR.MINOR.PATCH（不支持预发布和构建元数据）
+	versionPattern = regexp.MustCompile(`^(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)$`)
+
+	// variableNamePattern 变量名
+	variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// placeholderPattern 匹配 {{name}} 形式的占位符（允许两侧空格，与任务模板一致）
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+)
+
+// Variable 模板变量
+type Variable struct {
+	Name        string
+	Description string
+	Required    bool   // 必填变量渲染时必须提供
+	Default     string // 非必填变量未提供时使用
+}
+
+// PromptMessage 模板消息（内容中可以使用 {{name}} 占位符）
+type PromptMessage struct {
+	Role    llmmodel.Role
+	Content string
+}
+
+// Parameters 默认模型和生成参数（零值表示不指定，由调用方或路由器决定）
+type Parameters struct {
+	Provider    string
+	Model       string
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   int
+	CacheTTL    time.Duration // 响应缓存有效期（0 使用 LLM 服务的默认值，只影响可缓存的请求）
+}
+
+// TemplateParams 模板版本的内容（创建时使用）
+type TemplateParams struct {
+	Name        string
+	Version     string
+	Description string
+	Messages    []PromptMessage
+	Variables   []Variable
+	Parameters  Parameters
+}
+
+// PromptTemplate 提示词模板的一个版本（聚合根）
+//
+// 同一名称下版本号唯一；版本创建后不可修改，修改提示词需要发布新版本。
+type PromptTemplate struct {
+	ID string
+	TemplateParams
+	Source    Source
+	CreatedAt time.Time
+}
+
+// NewPromptTemplate 创建模板版本（含验证）
+func NewPromptTemplate(params TemplateParams, source Source) (*PromptTemplate, error) {
+	params.Name = strings.TrimSpace(params.Name)
+	params.Version = strings.TrimSpace(params.Version)
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	return &PromptTemplate{
+		ID:             uuid.New().String(),
+		TemplateParams: params,
+		Source:         source,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// validate 验证模板内容
+func (p TemplateParams) validate() error {
+	if !IsValidPromptName(p.Name) {
+		return ErrInvalidPromptName
+	}
+	if !IsValidVersion(p.Version) {
+		return ErrInvalidVersion
+	}
+	if len(p.Description) > MaxDescriptionLength {
+		return ErrInvalidDescription
+	}
+
+	if len(p.Messages) == 0 || len(p.Messages) > MaxPromptMessages {
+		return ErrInvalidMessages
+	}
+	for _, m := range p.Messages {
+		switch m.Role {
+		case llmmodel.RoleSystem, llmmodel.RoleUser, llmmodel.RoleAssistant:
+		default:
+			return ErrInvalidMessages
+		}
+		if strings.TrimSpace(m.Content) == "" {
+			return ErrInvalidMessages
+		}
+	}
+
+	if len(p.Variables) > MaxPromptVariables {
+		return ErrInvalidVariable
+	}
+	declared := make(map[string]bool, len(p.Variables))
+	for _, v := range p.Variables {
+		if !variableNamePattern.MatchString(v.Name) || len(v.Name) > MaxVariableNameLength || declared[v.Name] {
+			return ErrInvalidVariable
+		}
+		declared[v.Name] = true
+	}
+	var undeclared []string
+	for _, name := range p.placeholders() {
+		if !declared[name] {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		return fmt.Errorf("%w: %s", ErrUndeclaredVariable, strings.Join(undeclared, ", "))
+	}
+
+	return p.Parameters.validate()
+}
+
+// placeholders 返回消息中使用的占位符名称（去重，按出现顺序）
+func (p TemplateParams) placeholders() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range p.Messages {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(m.Content, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// validate 使用 pkg/validator 的 temperature、top_p、token_count 规则验证生成参数
+func (p Parameters) validate() error {
+	if len(p.Provider) > 50 || len(p.Model) > 100 {
+		return ErrInvalidModel
+	}
+	if p.Temperature != nil && pkgvalidator.ValidateVar(*p.Temperature, "temperature") != nil {
+		return ErrInvalidTemperature
+	}
+	if p.TopP != nil && pkgvalidator.ValidateVar(*p.TopP, "top_p") != nil {
+		return ErrInvalidTopP
+	}
+	if pkgvalidator.ValidateVar(p.MaxTokens, "token_count") != nil {
+		return ErrInvalidMaxTokens
+	}
+	if p.CacheTTL < 0 || p.CacheTTL > MaxCacheTTL {
+		return ErrInvalidCacheTTL
+	}
+	return nil
+}
+
+// Prompt 同一名称的所有版本
+//
+// 生效版本：管理员固定（pin）了版本且该版本存在时使用固定的版本，否则使用最新版本。
+type Prompt struct {
+	Name          string
+	Versions      []*PromptTemplate // 按版本号从新到旧
+	PinnedVersion string            // 为空表示跟随最新版本
+}
+
+// Active 返回生效的版本
+func (p *Prompt) Active() *PromptTemplate {
+	if p.PinnedVersion != "" {
+		if t := p.Version(p.PinnedVersion); t != nil {
+			return t
+		}
+	}
+	if len(p.Versions) == 0 {
+		return nil
+	}
+	return p.Versions[0]
+}
+
+// Version 返回指定版本（不存在时返回 nil）
+func (p *Prompt) Version(version string) *PromptTemplate {
+	for _, t := range p.Versions {
+		if t.Version == version {
+			return t
+		}
+	}
+	return nil
+}
+
+// Previous 返回比指定版本低的最高版本（不存在时返回 nil）
+func (p *Prompt) Previous(version string) *PromptTemplate {
+	for _, t := range p.Versions {
+		if CompareVersions(t.Version, version) < 0 {
+			return t
+		}
+	}
+	return nil
+}
+
+// RenderedPrompt 渲染后的提示词
+type RenderedPrompt struct {
+	Name       string
+	Version    string
+	Messages   []llmmodel.Message
+	Parameters Parameters
+}
+
+// Render 使用变量渲染模板
+//
+// 必填变量未提供时返回 PROMPT_VARIABLE_MISSING（列出所有缺少的变量）；
+// 非必填变量未提供时使用默认值。未声明的变量被忽略，
+// 这样回滚到旧版本时调用方不需要修改。
+func (t *PromptTemplate) Render(vars map[string]string) (*RenderedPrompt, error) {
+	values := make(map[string]string, len(t.Variables))
+	var missing []string
+	for _, v := range t.Variables {
+		value, ok := vars[v.Name]
+		switch {
+		case ok:
+			values[v.Name] = value
+		case v.Required:
+			missing = append(missing, v.Name)
+		default:
+			values[v.Name] = v.Default
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPromptVariableMissing, strings.Join(missing, ", "))
+	}
+
+	messages := make([]llmmodel.Message, len(t.Messages))
+	for i, m := range t.Messages {
+		messages[i] = llmmodel.Message{
+			Role: m.Role,
+			Content: placeholderPattern.ReplaceAllStringFunc(m.Content, func(match string) string {
+				return values[placeholderPattern.FindStringSubmatch(match)[1]]
+			}),
+		}
+	}
+
+	return &RenderedPrompt{
+		Name:       t.Name,
+		Version:    t.Version,
+		Messages:   messages,
+		Parameters: t.Parameters,
+	}, nil
+}
+
+// ChatRequest 转换为对话补全请求（带上模板的默认模型、生成参数和缓存有效期）
+func (r *RenderedPrompt) ChatRequest() *llmmodel.ChatRequest {
+	messages := make([]llmmodel.Message, len(r.Messages))
+	copy(messages, r.Messages)
+	return &llmmodel.ChatRequest{
+		Provider:    r.Parameters.Provider,
+		Model:       r.Parameters.Model,
+		Messages:    messages,
+		Temperature: r.Parameters.Temperature,
+		TopP:        r.Parameters.TopP,
+		MaxTokens:   r.Parameters.MaxTokens,
+		Cache:       llmmodel.CacheOptions{TTL: r.Parameters.CacheTTL},
+	}
+}
+
+// IsValidPromptName 判断模板名称格式是否有效
+func IsValidPromptName(name string) bool {
+	return len(name) <= MaxPromptNameLength && promptNamePattern.MatchString(name)
+}
+
+// IsValidVersion 判断版本号是否是语义化版本
+func IsValidVersion(version string) bool {
+	return versionPattern.MatchString(version)
+}
+
+// CompareVersions 比较两个语义化版本：a < b 返回 -1，相等返回 0，a > b 返回 1
+//
+// 无效的版本号视为小于任何有效版本。
+func CompareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return strings.Compare(a, b)
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	for i := range pa {
+		if pa[i] != pb[i] {
+			if pa[i] < pb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// SortVersions 按版本号从新到旧排序
+func SortVersions(templates []*PromptTemplate) {
+	sort.SliceStable(templates, func(i, j int) bool {
+		return CompareVersions(templates[i].Version, templates[j].Version) > 0
+	})
+}
+
+// parseVersion 解析 MAJOR.MINOR.PATCH
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return parts, false
+	}
+	for i := range parts {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
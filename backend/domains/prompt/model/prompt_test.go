@@ -0,0 +1,151 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func validParams() TemplateParams {
+	return TemplateParams{
+		Name:    " task.breakdown ",
+		Version: "1.0.0",
+		Messages: []PromptMessage{
+			{Role: llmmodel.RoleSystem, Content: "你是{{ user_name }}的任务助手"},
+			{Role: llmmodel.RoleUser, Content: "拆分任务：{{title}}（{{title}}）"},
+		},
+		Variables: []Variable{
+			{Name: "title", Required: true},
+			{Name: "user_name", Default: "用户"},
+		},
+		Parameters: Parameters{Model: "gpt-4o", Temperature: floatPtr(0.2), MaxTokens: 1024, CacheTTL: time.Hour},
+	}
+}
+
+// TestNewPromptTemplate 测试创建模板版本
+func TestNewPromptTemplate(t *testing.T) {
+	t.Run("有效参数", func(t *testing.T) {
+		tmpl, err := NewPromptTemplate(validParams(), SourceDB)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, tmpl.ID)
+		assert.Equal(t, "task.breakdown", tmpl.Name)
+		assert.Equal(t, SourceDB, tmpl.Source)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(p *TemplateParams)
+		want   error
+	}{
+		{"名称包含大写字母", func(p *TemplateParams) { p.Name = "Task" }, ErrInvalidPromptName},
+		{"名称过长", func(p *TemplateParams) { p.Name = strings.Repeat("a", MaxPromptNameLength+1) }, ErrInvalidPromptName},
+		{"版本号不是语义化版本", func(p *TemplateParams) { p.Version = "v1" }, ErrInvalidVersion},
+		{"版本号有前导零", func(p *TemplateParams) { p.Version = "1.01.0" }, ErrInvalidVersion},
+		{"没有消息", func(p *TemplateParams) { p.Messages = nil }, ErrInvalidMessages},
+		{"角色无效", func(p *TemplateParams) { p.Messages[0].Role = "tool" }, ErrInvalidMessages},
+		{"消息内容为空", func(p *TemplateParams) { p.Messages[1].Content = " " }, ErrInvalidMessages},
+		{"变量名无效", func(p *TemplateParams) { p.Variables[0].Name = "1title" }, ErrInvalidVariable},
+		{"变量名重复", func(p *TemplateParams) { p.Variables[1].Name = "title" }, ErrInvalidVariable},
+		{"使用未声明的变量", func(p *TemplateParams) { p.Variables = p.Variables[:1] }, ErrUndeclaredVariable},
+		{"temperature 超出范围", func(p *TemplateParams) { p.Parameters.Temperature = floatPtr(2.5) }, ErrInvalidTemperature},
+		{"top_p 超出范围", func(p *TemplateParams) { p.Parameters.TopP = floatPtr(1.5) }, ErrInvalidTopP},
+		{"max_tokens 为负数", func(p *TemplateParams) { p.Parameters.MaxTokens = -1 }, ErrInvalidMaxTokens},
+		{"缓存有效期超过 30 天", func(p *TemplateParams) { p.Parameters.CacheTTL = MaxCacheTTL + time.Second }, ErrInvalidCacheTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := validParams()
+			tt.modify(&params)
+
+			_, err := NewPromptTemplate(params, SourceDB)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	t.Run("错误信息列出未声明的变量", func(t *testing.T) {
+		params := validParams()
+		params.Variables = nil
+
+		_, err := NewPromptTemplate(params, SourceDB)
+
+		assert.EqualError(t, err, "UNDECLARED_VARIABLE: 模板使用了未声明的变量: user_name, title")
+	})
+}
+
+// TestPromptTemplate_Render 测试渲染模板
+func TestPromptTemplate_Render(t *testing.T) {
+	tmpl, err := NewPromptTemplate(validParams(), SourceEmbedded)
+	require.NoError(t, err)
+
+	t.Run("使用默认值并忽略未声明的变量", func(t *testing.T) {
+		rendered, err := tmpl.Render(map[string]string{"title": "发布 v2", "unused": "x"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", rendered.Version)
+		assert.Equal(t, "你是用户的任务助手", rendered.Messages[0].Content)
+		assert.Equal(t, "拆分任务：发布 v2（发布 v2）", rendered.Messages[1].Content)
+
+		req := rendered.ChatRequest()
+		assert.Equal(t, "gpt-4o", req.Model)
+		assert.Equal(t, 0.2, *req.Temperature)
+		assert.Equal(t, 1024, req.MaxTokens)
+		assert.Equal(t, time.Hour, req.Cache.TTL)
+		assert.Len(t, req.Messages, 2)
+	})
+
+	t.Run("提供的空字符串不使用默认值", func(t *testing.T) {
+		rendered, err := tmpl.Render(map[string]string{"title": "x", "user_name": ""})
+
+		require.NoError(t, err)
+		assert.Equal(t, "你是的任务助手", rendered.Messages[0].Content)
+	})
+
+	t.Run("缺少必填变量", func(t *testing.T) {
+		_, err := tmpl.Render(nil)
+
+		assert.ErrorIs(t, err, ErrPromptVariableMissing)
+		assert.ErrorContains(t, err, ": title")
+	})
+}
+
+// TestPrompt_Active 测试生效版本和上一个版本
+func TestPrompt_Active(t *testing.T) {
+	versions := make([]*PromptTemplate, 0, 3)
+	for _, v := range []string{"1.2.0", "1.10.0", "1.0.0"} {
+		params := validParams()
+		params.Version = v
+		tmpl, err := NewPromptTemplate(params, SourceDB)
+		require.NoError(t, err)
+		versions = append(versions, tmpl)
+	}
+	SortVersions(versions)
+	p := &Prompt{Name: "task.breakdown", Versions: versions}
+
+	assert.Equal(t, "1.10.0", p.Active().Version)
+	assert.Equal(t, "1.2.0", p.Previous("1.10.0").Version)
+	assert.Equal(t, "1.0.0", p.Previous("1.2.0").Version)
+	assert.Nil(t, p.Previous("1.0.0"))
+
+	p.PinnedVersion = "1.2.0"
+	assert.Equal(t, "1.2.0", p.Active().Version)
+
+	// 固定的版本不存在时使用最新版本
+	p.PinnedVersion = "9.9.9"
+	assert.Equal(t, "1.10.0", p.Active().Version)
+}
+
+// TestCompareVersions 测试比较语义化版本
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, -1, CompareVersions("1.9.0", "1.10.0"))
+	assert.Equal(t, 1, CompareVersions("2.0.0", "1.99.99"))
+	assert.Equal(t, 0, CompareVersions("1.0.0", "1.0.0"))
+	assert.Equal(t, -1, CompareVersions("latest", "0.0.1"))
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+)
+
+// PromptRepository 定义提示词模板仓储接口（只保存管理员创建的版本，内置模板不入库）
+type PromptRepository interface {
+	// Create 保存一个新的模板版本
+	Create(ctx context.Context, t *model.PromptTemplate) error
+
+	// List 列出所有模板版本
+	List(ctx context.Context) ([]*model.PromptTemplate, error)
+
+	// ListPins 列出管理员固定的版本（名称 → 版本）
+	ListPins(ctx context.Context) (map[string]string, error)
+
+	// SetPin 固定生效的版本（覆盖）
+	SetPin(ctx context.Context, name, version string) error
+
+	// DeletePin 取消固定（恢复跟随最新版本）
+	DeletePin(ctx context.Context, name string) error
+}
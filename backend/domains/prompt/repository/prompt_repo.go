@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// rowScanner 抽象 *sql.Row 和 *sql.Rows 的 Scan 方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// newDialect 根据数据库类型选择 goqu 方言
+func newDialect(dbType string) goqu.DialectWrapper {
+	switch dbType {
+	case "mysql":
+		return goqu.Dialect("mysql")
+	case "sqlite":
+		return goqu.Dialect("sqlite3")
+	default:
+		return goqu.Dialect("postgres")
+	}
+}
+
+// PromptRepositoryImpl 提示词模板仓储实现
+//
+// 消息和变量以 JSON 文本存储在 prompt_templates 表中，版本作为一个整体读写；
+// 固定的版本保存在 prompt_pins 表中（每个名称最多一行）。
+type PromptRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewPromptRepository 创建提示词模板仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewPromptRepository(db *sql.DB, dbType string) *PromptRepositoryImpl {
+	return &PromptRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *PromptRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// promptColumns prompt_templates 表的查询/插入列（顺序与 scanPrompt 保持一致）
+var promptColumns = []interface{}{
+	"id", "name", "version", "description", "messages", "variables",
+	"provider", "model", "temperature", "top_p", "max_tokens", "cache_ttl_seconds", "created_at",
+}
+
+// messageRecord 消息的 JSON 存储格式
+type messageRecord struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// variableRecord 变量的 JSON 存储格式
+type variableRecord struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+}
+
+// Create 创建模板版本
+func (r *PromptRepositoryImpl) Create(ctx context.Context, t *model.PromptTemplate) error {
+	messagesJSON, variablesJSON, err := encodePromptJSON(t)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := r.dialect.Insert("prompt_templates").
+		Cols(promptColumns...).
+		Vals(goqu.Vals{
+			t.ID,
+			t.Name,
+			t.Version,
+			t.Description,
+			messagesJSON,
+			variablesJSON,
+			t.Parameters.Provider,
+			t.Parameters.Model,
+			t.Parameters.Temperature,
+			t.Parameters.TopP,
+			t.Parameters.MaxTokens,
+			int64(t.Parameters.CacheTTL / time.Second),
+			t.CreatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert prompt query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create prompt failed: %w", err)
+	}
+	return nil
+}
+
+// List 列出所有模板版本（按名称和创建时间排序）
+func (r *PromptRepositoryImpl) List(ctx context.Context) ([]*model.PromptTemplate, error) {
+	query, args, err := r.dialect.From("prompt_templates").
+		Select(promptColumns...).
+		Order(goqu.C("name").Asc(), goqu.C("created_at").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list prompts query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query prompts failed: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*model.PromptTemplate, 0)
+	for rows.Next() {
+		t, err := scanPrompt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan prompt failed: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// ListPins 列出固定的版本
+func (r *PromptRepositoryImpl) ListPins(ctx context.Context) (map[string]string, error) {
+	query, args, err := r.dialect.From("prompt_pins").
+		Select("name", "version").
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list prompt pins query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query prompt pins failed: %w", err)
+	}
+	defer rows.Close()
+
+	pins := make(map[string]string)
+	for rows.Next() {
+		var name, version string
+		if err := rows.Scan(&name, &version); err != nil {
+			return nil, fmt.Errorf("scan prompt pin failed: %w", err)
+		}
+		pins[name] = version
+	}
+	return pins, rows.Err()
+}
+
+// SetPin 固定生效的版本（存在时覆盖）
+func (r *PromptRepositoryImpl) SetPin(ctx context.Context, name, version string) error {
+	now := time.Now()
+	query, args, err := r.dialect.Insert("prompt_pins").
+		Rows(goqu.Record{
+			"name":       name,
+			"version":    version,
+			"updated_at": now,
+		}).
+		OnConflict(goqu.DoUpdate("name", goqu.Record{
+			"version":    version,
+			"updated_at": now,
+		})).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build upsert prompt pin query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("save prompt pin failed: %w", err)
+	}
+	return nil
+}
+
+// DeletePin 取消固定（没有固定时不报错）
+func (r *PromptRepositoryImpl) DeletePin(ctx context.Context, name string) error {
+	query, args, err := r.dialect.Delete("prompt_pins").
+		Where(goqu.C("name").Eq(name)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build delete prompt pin query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("delete prompt pin failed: %w", err)
+	}
+	return nil
+}
+
+// scanPrompt 按 promptColumns 的顺序扫描一行模板数据
+func scanPrompt(row rowScanner) (*model.PromptTemplate, error) {
+	t := &model.PromptTemplate{Source: model.SourceDB}
+	var (
+		messagesJSON  string
+		variablesJSON string
+		temperature   sql.NullFloat64
+		topP          sql.NullFloat64
+		cacheTTL      int64
+	)
+	err := row.Scan(
+		&t.ID,
+		&t.Name,
+		&t.Version,
+		&t.Description,
+		&messagesJSON,
+		&variablesJSON,
+		&t.Parameters.Provider,
+		&t.Parameters.Model,
+		&temperature,
+		&topP,
+		&t.Parameters.MaxTokens,
+		&cacheTTL,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Parameters.CacheTTL = time.Duration(cacheTTL) * time.Second
+	if temperature.Valid {
+		t.Parameters.Temperature = &temperature.Float64
+	}
+	if topP.Valid {
+		t.Parameters.TopP = &topP.Float64
+	}
+
+	var messages []messageRecord
+	if err := json.Unmarshal([]byte(messagesJSON), &messages); err != nil {
+		return nil, fmt.Errorf("decode prompt messages failed: %w", err)
+	}
+	for _, m := range messages {
+		t.Messages = append(t.Messages, model.PromptMessage{Role: llmmodel.Role(m.Role), Content: m.Content})
+	}
+
+	var variables []variableRecord
+	if variablesJSON != "" {
+		if err := json.Unmarshal([]byte(variablesJSON), &variables); err != nil {
+			return nil, fmt.Errorf("decode prompt variables failed: %w", err)
+		}
+	}
+	for _, v := range variables {
+		t.Variables = append(t.Variables, model.Variable(v))
+	}
+	return t, nil
+}
+
+// encodePromptJSON 将消息和变量编码为 JSON 文本
+func encodePromptJSON(t *model.PromptTemplate) (string, string, error) {
+	messages := make([]messageRecord, len(t.Messages))
+	for i, m := range t.Messages {
+		messages[i] = messageRecord{Role: string(m.Role), Content: m.Content}
+	}
+	messagesJSON, err := json.Marshal(messages)
+	if err != nil {
+		return "", "", fmt.Errorf("encode prompt messages failed: %w", err)
+	}
+
+	variables := make([]variableRecord, len(t.Variables))
+	for i, v := range t.Variables {
+		variables[i] = variableRecord(v)
+	}
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return "", "", fmt.Errorf("encode prompt variables failed: %w", err)
+	}
+
+	return string(messagesJSON), string(variablesJSON), nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPromptColumns = []string{
+	"id", "name", "version", "description", "messages", "variables",
+	"provider", "model", "temperature", "top_p", "max_tokens", "cache_ttl_seconds", "created_at",
+}
+
+// TestPromptRepository_Create 测试创建模板版本（消息和变量以 JSON 存储）
+func TestPromptRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPromptRepository(db, "postgres")
+	tmpl, err := model.NewPromptTemplate(model.TemplateParams{
+		Name:      "chat.title",
+		Version:   "1.0.0",
+		Messages:  []model.PromptMessage{{Role: llmmodel.RoleUser, Content: "{{text}}"}},
+		Variables: []model.Variable{{Name: "text", Required: true}},
+	}, model.SourceDB)
+	require.NoError(t, err)
+
+	mock.ExpectExec(`INSERT INTO "prompt_templates" .+'chat.title', '1.0.0', '', '\[\{"role":"user","content":"\{\{text\}\}"\}\]', '\[\{"name":"text","required":true\}\]', '', '', NULL, NULL, 0, 0`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), tmpl))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPromptRepository_List 测试列出模板版本
+func TestPromptRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPromptRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT .+ FROM "prompt_templates" ORDER BY "name" ASC, "created_at" ASC`).
+		WillReturnRows(sqlmock.NewRows(testPromptColumns).
+			AddRow("p-1", "chat.title", "1.0.0", "", `[{"role":"user","content":"hi"}]`, `[]`, "openai", "gpt-4o", 0.3, nil, 256, 600, time.Now()))
+
+	templates, err := repo.List(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, model.SourceDB, templates[0].Source)
+	assert.Equal(t, llmmodel.RoleUser, templates[0].Messages[0].Role)
+	assert.Equal(t, 0.3, *templates[0].Parameters.Temperature)
+	assert.Nil(t, templates[0].Parameters.TopP)
+	assert.Equal(t, 256, templates[0].Parameters.MaxTokens)
+	assert.Equal(t, 10*time.Minute, templates[0].Parameters.CacheTTL)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPromptRepository_Pins 测试固定版本的读写
+func TestPromptRepository_Pins(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPromptRepository(db, "postgres")
+	ctx := context.Background()
+
+	mock.ExpectExec(`INSERT INTO "prompt_pins" .+ ON CONFLICT \(name\) DO UPDATE SET "updated_at"=.+"version"='1.0.0'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, repo.SetPin(ctx, "chat.title", "1.0.0"))
+
+	mock.ExpectQuery(`SELECT "name", "version" FROM "prompt_pins"`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("chat.title", "1.0.0"))
+	pins, err := repo.ListPins(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"chat.title": "1.0.0"}, pins)
+
+	mock.ExpectExec(`DELETE FROM "prompt_pins" WHERE \("name" = 'chat.title'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.DeletePin(ctx, "chat.title"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
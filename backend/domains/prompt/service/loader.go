@@ -0,0 +1,126 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	"gopkg.in/yaml.v3"
+)
+
+// templateFile 模板文件格式（YAML，每个文件一个版本）
+//
+//	name: task.breakdown
+//	version: 1.0.0
+//	description: 将任务拆分为子任务
+//	model: gpt-4o-mini
+//	temperature: 0
+//	cache_ttl_seconds: 86400
+//	variables:
+//	  - name: title
+//	    required: true
+//	messages:
+//	  - role: system
+//	    content: 你是一个任务规划助手
+//	  - role: user
+//	    content: "任务：{{title}}"
+type templateFile struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description"`
+	Provider    string   `yaml:"provider"`
+	Model       string   `yaml:"model"`
+	Temperature *float64 `yaml:"temperature"`
+	TopP        *float64 `yaml:"top_p"`
+	MaxTokens   int      `yaml:"max_tokens"`
+	CacheTTL    int      `yaml:"cache_ttl_seconds"`
+	Variables   []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+		Required    bool   `yaml:"required"`
+		Default     string `yaml:"default"`
+	} `yaml:"variables"`
+	Messages []struct {
+		Role    string `yaml:"role"`
+		Content string `yaml:"content"`
+	} `yaml:"messages"`
+}
+
+// LoadTemplates 从文件系统（通常是 embed.FS）加载所有 *.yaml / *.yml 模板
+//
+// 任意文件格式错误、验证失败或同一名称下版本号重复时返回错误（包含文件名）。
+func LoadTemplates(fsys fs.FS) ([]*model.PromptTemplate, error) {
+	var templates []*model.PromptTemplate
+	seen := make(map[string]string) // name@version → 文件名
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (path.Ext(name) != ".yaml" && path.Ext(name) != ".yml") {
+			return nil
+		}
+
+		t, err := loadTemplate(fsys, name)
+		if err != nil {
+			return fmt.Errorf("load prompt template %s: %w", name, err)
+		}
+		key := t.Name + "@" + t.Version
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("load prompt template %s: %s 已在 %s 中定义", name, key, other)
+		}
+		seen[key] = name
+		templates = append(templates, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// loadTemplate 解析并验证一个模板文件
+func loadTemplate(fsys fs.FS, name string) (*model.PromptTemplate, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var file templateFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	params := model.TemplateParams{
+		Name:        file.Name,
+		Version:     file.Version,
+		Description: file.Description,
+		Parameters: model.Parameters{
+			Provider:    file.Provider,
+			Model:       file.Model,
+			Temperature: file.Temperature,
+			TopP:        file.TopP,
+			MaxTokens:   file.MaxTokens,
+			CacheTTL:    time.Duration(file.CacheTTL) * time.Second,
+		},
+	}
+	for _, v := range file.Variables {
+		params.Variables = append(params.Variables, model.Variable{
+			Name:        v.Name,
+			Description: v.Description,
+			Required:    v.Required,
+			Default:     v.Default,
+		})
+	}
+	for _, m := range file.Messages {
+		params.Messages = append(params.Messages, model.PromptMessage{Role: llmmodel.Role(m.Role), Content: m.Content})
+	}
+
+	return model.NewPromptTemplate(params, model.SourceEmbedded)
+}
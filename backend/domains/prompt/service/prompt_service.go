@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultCacheTTL 提示词缓存的默认有效期
+//
+// 本实例的写操作会立即使缓存失效；TTL 用于获取其他实例的修改。
+const DefaultCacheTTL = time.Minute
+
+// 领域错误
+var (
+	ErrPromptNotFound       = fmt.Errorf("PROMPT_NOT_FOUND: 提示词不存在")
+	ErrVersionNotFound      = fmt.Errorf("PROMPT_VERSION_NOT_FOUND: 提示词版本不存在")
+	ErrVersionAlreadyExists = fmt.Errorf("PROMPT_VERSION_EXISTS: 该提示词版本已存在")
+	ErrNoPreviousVersion    = fmt.Errorf("NO_PREVIOUS_VERSION: 没有比当前生效版本更早的版本")
+)
+
+// PromptService 提示词注册表领域服务
+//
+// 职责：
+// - 合并内置模板（随程序发布，只读）和管理员创建的版本（数据库）
+// - 按名称解析生效版本并渲染（其他领域通过 Render 获取提示词，不在代码中拼接字符串）
+// - 管理员创建新版本、固定（pin）生效版本、回滚到上一个版本、取消固定
+//
+// 渲染读取内存缓存：本实例的写操作后立即失效，否则在 cacheTTL 后重新加载。
+// 重新加载失败时继续使用旧的缓存（记录日志）。管理接口直接读取数据库。
+type PromptService struct {
+	repo     repository.PromptRepository
+	embedded []*model.PromptTemplate
+	cacheTTL time.Duration
+
+	mu       sync.RWMutex
+	cache    map[string]*model.Prompt
+	loadedAt time.Time
+}
+
+// NewPromptService 创建提示词服务
+//
+// 参数：
+//   - repo: 提示词仓储
+//   - embedded: 内置模板（LoadTemplates 的结果）
+//   - cacheTTL: 缓存有效期（<= 0 时使用 DefaultCacheTTL）
+func NewPromptService(repo repository.PromptRepository, embedded []*model.PromptTemplate, cacheTTL time.Duration) *PromptService {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	return &PromptService{
+		repo:     repo,
+		embedded: embedded,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// PromptOutput 提示词输出
+type PromptOutput struct {
+	Prompt *model.Prompt
+}
+
+// VersionOutput 提示词版本输出
+type VersionOutput struct {
+	Template *model.PromptTemplate
+}
+
+// CreateVersionInput 创建版本输入
+type CreateVersionInput struct {
+	Params model.TemplateParams
+}
+
+// CreateVersion 发布提示词的新版本（用例实现）
+//
+// 步骤：
+//  1. CreateTemplateEntity - 创建模板实体（含变量和生成参数验证）
+//  2. CheckDuplicate - 同一名称下版本号唯一（包括内置模板）
+//  3. SaveTemplate - 保存并使缓存失效
+//
+// 未固定版本的提示词在新版本的版本号最高时立即生效。
+func (s *PromptService) CreateVersion(ctx context.Context, input CreateVersionInput) (*VersionOutput, error) {
+	// Step 1: CreateTemplateEntity
+	t, err := model.NewPromptTemplate(input.Params, model.SourceDB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: CheckDuplicate
+	prompts, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := prompts[t.Name]; ok && p.Version(t.Version) != nil {
+		return nil, ErrVersionAlreadyExists
+	}
+
+	// Step 3: SaveTemplate
+	if err := s.repo.Create(ctx, t); err != nil {
+		return nil, fmt.Errorf("CREATE_FAILED: 创建提示词版本失败: %w", err)
+	}
+	s.Invalidate()
+
+	return &VersionOutput{Template: t}, nil
+}
+
+// ListPromptsOutput 列出提示词输出
+type ListPromptsOutput struct {
+	Prompts []*model.Prompt // 按名称排序
+}
+
+// ListPrompts 列出所有提示词及其版本（直接读取数据库）
+func (s *PromptService) ListPrompts(ctx context.Context) (*ListPromptsOutput, error) {
+	prompts, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.Prompt, 0, len(prompts))
+	for _, p := range prompts {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return &ListPromptsOutput{Prompts: out}, nil
+}
+
+// GetPrompt 获取提示词及其版本（直接读取数据库）
+func (s *PromptService) GetPrompt(ctx context.Context, name string) (*PromptOutput, error) {
+	p, err := s.findPrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &PromptOutput{Prompt: p}, nil
+}
+
+// GetVersion 获取提示词的指定版本（直接读取数据库）
+func (s *PromptService) GetVersion(ctx context.Context, name, version string) (*VersionOutput, error) {
+	p, err := s.findPrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	t := p.Version(version)
+	if t == nil {
+		return nil, ErrVersionNotFound
+	}
+	return &VersionOutput{Template: t}, nil
+}
+
+// PinVersionInput 固定版本输入
+type PinVersionInput struct {
+	Name    string
+	Version string
+}
+
+// PinVersion 固定生效的版本（用例实现，管理员操作）
+//
+// 固定后发布的新版本不会自动生效，直到取消固定或固定到新版本。
+func (s *PromptService) PinVersion(ctx context.Context, input PinVersionInput) (*PromptOutput, error) {
+	p, err := s.findPrompt(ctx, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version(input.Version) == nil {
+		return nil, ErrVersionNotFound
+	}
+	return s.setPin(ctx, p, input.Version)
+}
+
+// Rollback 回滚到比当前生效版本更早的最高版本（用例实现，管理员操作）
+//
+// 回滚通过固定版本实现，因此之后发布的新版本不会自动生效。
+func (s *PromptService) Rollback(ctx context.Context, name string) (*PromptOutput, error) {
+	p, err := s.findPrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	previous := p.Previous(p.Active().Version)
+	if previous == nil {
+		return nil, ErrNoPreviousVersion
+	}
+	return s.setPin(ctx, p, previous.Version)
+}
+
+// Unpin 取消固定，恢复跟随最新版本（用例实现，管理员操作）
+func (s *PromptService) Unpin(ctx context.Context, name string) (*PromptOutput, error) {
+	p, err := s.findPrompt(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeletePin(ctx, name); err != nil {
+		return nil, fmt.Errorf("SAVE_FAILED: 取消固定版本失败: %w", err)
+	}
+	s.Invalidate()
+
+	p.PinnedVersion = ""
+	return &PromptOutput{Prompt: p}, nil
+}
+
+// RenderInput 渲染输入
+type RenderInput struct {
+	Name      string
+	Version   string // 为空时使用生效版本
+	Variables map[string]string
+}
+
+// Render 渲染提示词（读取缓存）
+//
+// 必填变量缺失时返回 PROMPT_VARIABLE_MISSING。
+// 返回值的 ChatRequest() 可以直接传给 LLMService。
+func (s *PromptService) Render(ctx context.Context, input RenderInput) (*model.RenderedPrompt, error) {
+	prompts, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := prompts[input.Name]
+	if !ok {
+		return nil, ErrPromptNotFound
+	}
+
+	t := p.Active()
+	if input.Version != "" {
+		if t = p.Version(input.Version); t == nil {
+			return nil, ErrVersionNotFound
+		}
+	}
+	return t.Render(input.Variables)
+}
+
+// Invalidate 使缓存失效，下次读取时重新加载
+func (s *PromptService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// snapshot 返回缓存的提示词（过期时重新加载）
+func (s *PromptService) snapshot(ctx context.Context) (map[string]*model.Prompt, error) {
+	s.mu.RLock()
+	if s.cache != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		cache := s.cache
+		s.mu.RUnlock()
+		return cache, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 等待锁期间可能已被其他调用重新加载
+	if s.cache != nil && time.Since(s.loadedAt) < s.cacheTTL {
+		return s.cache, nil
+	}
+
+	prompts, err := s.load(ctx)
+	if err != nil {
+		if s.cache != nil {
+			logger.Error("reload prompts failed, using stale cache", zap.Error(err))
+			return s.cache, nil
+		}
+		return nil, err
+	}
+
+	s.cache = prompts
+	s.loadedAt = time.Now()
+	return prompts, nil
+}
+
+// load 合并内置模板、数据库中的版本和固定的版本
+func (s *PromptService) load(ctx context.Context) (map[string]*model.Prompt, error) {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 读取提示词失败: %w", err)
+	}
+	pins, err := s.repo.ListPins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 读取固定版本失败: %w", err)
+	}
+
+	prompts := make(map[string]*model.Prompt)
+	add := func(t *model.PromptTemplate) {
+		p, ok := prompts[t.Name]
+		if !ok {
+			p = &model.Prompt{Name: t.Name}
+			prompts[t.Name] = p
+		}
+		if p.Version(t.Version) == nil { // 内置模板优先，忽略数据库中同名同版本的记录
+			p.Versions = append(p.Versions, t)
+		}
+	}
+	for _, t := range s.embedded {
+		add(t)
+	}
+	for _, t := range stored {
+		add(t)
+	}
+
+	for name, p := range prompts {
+		model.SortVersions(p.Versions)
+		p.PinnedVersion = pins[name]
+	}
+	return prompts, nil
+}
+
+// findPrompt 查找提示词（直接读取数据库，不存在时返回 PROMPT_NOT_FOUND）
+func (s *PromptService) findPrompt(ctx context.Context, name string) (*model.Prompt, error) {
+	prompts, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := prompts[name]
+	if !ok {
+		return nil, ErrPromptNotFound
+	}
+	return p, nil
+}
+
+// setPin 保存固定的版本并使缓存失效
+func (s *PromptService) setPin(ctx context.Context, p *model.Prompt, version string) (*PromptOutput, error) {
+	if err := s.repo.SetPin(ctx, p.Name, version); err != nil {
+		return nil, fmt.Errorf("SAVE_FAILED: 固定版本失败: %w", err)
+	}
+	s.Invalidate()
+
+	p.PinnedVersion = version
+	return &PromptOutput{Prompt: p}, nil
+}
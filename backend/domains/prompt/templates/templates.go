@@ -0,0 +1,12 @@
+// Package templates 内置的提示词模板
+//
+// 每个 *.yaml 文件是一个模板版本（格式见 service.LoadTemplates），随程序一起发布。
+// 修改内置模板时提升版本号并保留旧文件，管理员才能回滚。
+package templates
+
+import "embed"
+
+// FS 内置模板文件
+//
+//go:embed *.yaml
+var FS embed.FS
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/handlers"
+	prompthttp "github.com/erweixin/go-genai-stack/backend/domains/prompt/http"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// ========== 测试常量 ==========
+
+const (
+	TestAdminEmail = "admin@example.com"
+	TestUserEmail  = "user@example.com"
+	TestPromptName = "chat.title"
+)
+
+// TestTime 测试时间常量
+var TestTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// promptColumns prompt_templates 表列（与 repository 保持一致）
+var promptColumns = []string{
+	"id", "name", "version", "description", "messages", "variables",
+	"provider", "model", "temperature", "top_p", "max_tokens", "cache_ttl_seconds", "created_at",
+}
+
+// embeddedTemplates 测试用的内置模板
+var embeddedTemplates = fstest.MapFS{
+	"chat.title@1.0.0.yaml": &fstest.MapFile{Data: []byte(`
+name: chat.title
+version: 1.0.0
+description: 为对话生成标题
+temperature: 0.3
+variables:
+  - name: text
+    required: true
+  - name: language
+    default: 中文
+messages:
+  - role: system
+    content: 用{{language}}为对话生成不超过 20 字的标题
+  - role: user
+    content: "{{text}}"
+`)},
+}
+
+// TestHelper 提供测试辅助方法
+//
+// 数据库使用 sqlmock，内置模板使用 embeddedTemplates。请求经过真实的路由、
+// 认证中间件和管理员中间件，默认使用管理员的 Token（AsUser 切换为普通用户）。
+type TestHelper struct {
+	DB      *sql.DB
+	Mock    sqlmock.Sqlmock
+	Service *service.PromptService
+	Server  *server.Hertz
+
+	adminToken string
+	userToken  string
+	token      string
+}
+
+// NewTestHelper 创建测试辅助工具
+func NewTestHelper(t *testing.T) *TestHelper {
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	embedded, err := service.LoadTemplates(embeddedTemplates)
+	if err != nil {
+		t.Fatalf("failed to load templates: %v", err)
+	}
+
+	h := &TestHelper{DB: db, Mock: sqlMock}
+	h.Service = service.NewPromptService(repository.NewPromptRepository(db, "postgres"), embedded, time.Hour)
+
+	jwtService := authservice.NewJWTService("test-secret", time.Hour, time.Hour, "test")
+	h.adminToken, _, err = jwtService.GenerateAccessToken("admin-1", TestAdminEmail)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.userToken, _, err = jwtService.GenerateAccessToken("user-1", TestUserEmail)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.token = h.adminToken
+
+	h.Server = server.Default(
+		server.WithHostPorts("127.0.0.1:0"),
+		server.WithExitWaitTime(0),
+	)
+	prompthttp.RegisterRoutes(
+		h.Server.Group("/api"),
+		handlers.NewHandlerDependencies(h.Service),
+		middleware.NewAuthMiddleware(jwtService),
+		middleware.NewAdminMiddleware([]string{TestAdminEmail}, adminUsers),
+	)
+	return h
+}
+
+// adminUsers 管理员中间件的用户查询（管理员邮箱已验证）
+var adminUsers = middleware.UserLookupFunc(func(_ context.Context, userID string) (*middleware.UserInfo, error) {
+	if userID != "admin-1" {
+		return nil, nil
+	}
+	return &middleware.UserInfo{Email: TestAdminEmail, EmailVerified: true, Active: true}, nil
+})
+
+// AsUser 之后的请求使用普通用户的 Token
+func (h *TestHelper) AsUser() {
+	h.token = h.userToken
+}
+
+// Close 清理资源
+func (h *TestHelper) Close() error {
+	return h.DB.Close()
+}
+
+// AssertExpectations 验证所有 mock 期望都被满足
+func (h *TestHelper) AssertExpectations(t *testing.T) {
+	if err := h.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// PerformRequest 执行 HTTP 请求（body 为 nil 时不发送请求体）
+func (h *TestHelper) PerformRequest(method, path string, body interface{}) *ut.ResponseRecorder {
+	var bodyOpt *ut.Body
+	if body != nil {
+		data, _ := json.Marshal(body)
+		bodyOpt = &ut.Body{Body: bytes.NewReader(data), Len: len(data)}
+	}
+	return ut.PerformRequest(h.Server.Engine, method, path, bodyOpt,
+		ut.Header{Key: "Content-Type", Value: "application/json"},
+		ut.Header{Key: "Authorization", Value: "Bearer " + h.token})
+}
+
+// DecodeResponse 解析 JSON 响应
+func DecodeResponse(t *testing.T, w *ut.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v (body: %s)", err, w.Body.String())
+	}
+}
+
+// ========== Mock 辅助函数 ==========
+
+// StoredVersion 数据库中 chat.title 的一个版本（变量与内置版本相同）
+func StoredVersion(version string) []driver.Value {
+	return []driver.Value{
+		"prompt-" + version, TestPromptName, version, "",
+		`[{"role":"system","content":"用{{language}}生成简短的标题"},{"role":"user","content":"{{text}}"}]`,
+		`[{"name":"text","required":true},{"name":"language","required":false,"default":"中文"}]`,
+		"", "gpt-4o-mini", nil, nil, 0, 3600, TestTime,
+	}
+}
+
+// MockLoad Mock 读取数据库中的版本和固定的版本（pins 为 name, version 交替）
+func MockLoad(m sqlmock.Sqlmock, versions [][]driver.Value, pins ...string) {
+	rows := sqlmock.NewRows(promptColumns)
+	for _, v := range versions {
+		rows.AddRow(v...)
+	}
+	m.ExpectQuery(`SELECT .+ FROM "prompt_templates" ORDER BY`).WillReturnRows(rows)
+
+	pinRows := sqlmock.NewRows([]string{"name", "version"})
+	for i := 0; i+1 < len(pins); i += 2 {
+		pinRows.AddRow(pins[i], pins[i+1])
+	}
+	m.ExpectQuery(`SELECT "name", "version" FROM "prompt_pins"`).WillReturnRows(pinRows)
+}
+
+// validCreateRequest 有效的创建版本请求
+func validCreateRequest() map[string]interface{} {
+	return map[string]interface{}{
+		"name":    TestPromptName,
+		"version": "1.1.0",
+		"messages": []map[string]string{
+			{"role": "system", "content": "用{{language}}生成标题"},
+			{"role": "user", "content": "{{text}}"},
+		},
+		"variables": []map[string]interface{}{
+			{"name": "text", "required": true},
+			{"name": "language", "default": "中文"},
+		},
+		"parameters": map[string]interface{}{"model": "gpt-4o-mini", "temperature": 0.2},
+	}
+}
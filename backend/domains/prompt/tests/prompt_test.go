@@ -0,0 +1,297 @@
+package tests
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateVersion_Success 测试发布新版本（未固定时立即生效）
+func TestCreateVersion_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, nil)
+	helper.Mock.ExpectExec(`INSERT INTO "prompt_templates" .+'chat.title', '1.1.0', '',.+'gpt-4o-mini', 0.2, NULL, 0`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := helper.PerformRequest("POST", "/api/admin/prompts", validCreateRequest())
+
+	require.Equal(t, consts.StatusCreated, w.Code, w.Body.String())
+	var resp dto.PromptVersionResponse
+	DecodeResponse(t, w, &resp)
+	assert.NotEmpty(t, resp.PromptID)
+	assert.Equal(t, "db", resp.Source)
+	assert.Len(t, resp.Variables, 2)
+	assert.Equal(t, 0.2, *resp.Parameters.Temperature)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateVersion_PROMPT_VERSION_EXISTS 测试版本号与内置模板重复
+func TestCreateVersion_PROMPT_VERSION_EXISTS(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, nil)
+
+	req := validCreateRequest()
+	req["version"] = "1.0.0"
+	w := helper.PerformRequest("POST", "/api/admin/prompts", req)
+
+	assert.Equal(t, consts.StatusConflict, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "PROMPT_VERSION_EXISTS", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateVersion_INVALID_INPUT 测试无效的模板
+func TestCreateVersion_INVALID_INPUT(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(req map[string]interface{})
+		want   string
+	}{
+		{"使用未声明的变量", func(req map[string]interface{}) { req["variables"] = []map[string]interface{}{{"name": "text"}} }, "UNDECLARED_VARIABLE"},
+		{"版本号无效", func(req map[string]interface{}) { req["version"] = "v2" }, "INVALID_VERSION"},
+		{"temperature 超出范围", func(req map[string]interface{}) {
+			req["parameters"] = map[string]interface{}{"temperature": 3}
+		}, "INVALID_INPUT"},
+		{"角色无效", func(req map[string]interface{}) {
+			req["messages"] = []map[string]string{{"role": "tool", "content": "x"}}
+		}, "INVALID_INPUT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper(t)
+			defer helper.Close()
+
+			req := validCreateRequest()
+			tt.modify(req)
+			w := helper.PerformRequest("POST", "/api/admin/prompts", req)
+
+			assert.Equal(t, consts.StatusBadRequest, w.Code)
+			var resp dto.ErrorResponse
+			DecodeResponse(t, w, &resp)
+			assert.Equal(t, tt.want, resp.Error)
+
+			helper.AssertExpectations(t)
+		})
+	}
+}
+
+// TestPrompts_FORBIDDEN 测试非管理员不能访问提示词接口
+func TestPrompts_FORBIDDEN(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	helper.AsUser()
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/api/admin/prompts"},
+		{"POST", "/api/admin/prompts"},
+		{"PUT", "/api/admin/prompts/" + TestPromptName + "/pin"},
+		{"POST", "/api/admin/prompts/" + TestPromptName + "/rollback"},
+	} {
+		w := helper.PerformRequest(req.method, req.path, nil)
+		assert.Equal(t, consts.StatusForbidden, w.Code, req.method+" "+req.path)
+	}
+
+	helper.AssertExpectations(t)
+}
+
+// TestListPrompts_Success 测试列出提示词（合并内置模板和数据库中的版本）
+func TestListPrompts_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")})
+
+	w := helper.PerformRequest("GET", "/api/admin/prompts", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.ListPromptsResponse
+	DecodeResponse(t, w, &resp)
+	require.Equal(t, 1, resp.Total)
+	p := resp.Prompts[0]
+	assert.Equal(t, "1.1.0", p.ActiveVersion)
+	assert.Empty(t, p.PinnedVersion)
+	require.Len(t, p.Versions, 2)
+	assert.Equal(t, "db", p.Versions[0].Source)
+	assert.Equal(t, "embedded", p.Versions[1].Source)
+
+	helper.AssertExpectations(t)
+}
+
+// TestGetVersion_PROMPT_VERSION_NOT_FOUND 测试获取不存在的提示词和版本
+func TestGetVersion_PROMPT_VERSION_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, nil)
+	w := helper.PerformRequest("GET", "/api/admin/prompts/missing", nil)
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+
+	MockLoad(helper.Mock, nil)
+	w = helper.PerformRequest("GET", "/api/admin/prompts/"+TestPromptName+"/versions/2.0.0", nil)
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "PROMPT_VERSION_NOT_FOUND", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestPinVersion_Success 测试固定旧版本
+func TestPinVersion_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")})
+	helper.Mock.ExpectExec(`INSERT INTO "prompt_pins" .+'chat.title', .+'1.0.0'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := helper.PerformRequest("PUT", "/api/admin/prompts/"+TestPromptName+"/pin", map[string]string{"version": "1.0.0"})
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.PromptResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "1.0.0", resp.PinnedVersion)
+	assert.Equal(t, "1.0.0", resp.ActiveVersion)
+
+	// 不存在的版本
+	MockLoad(helper.Mock, nil)
+	w = helper.PerformRequest("PUT", "/api/admin/prompts/"+TestPromptName+"/pin", map[string]string{"version": "3.0.0"})
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+
+	helper.AssertExpectations(t)
+}
+
+// TestRollback 测试回滚到上一个版本
+func TestRollback(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0"), StoredVersion("1.2.0")}, TestPromptName, "1.2.0")
+	helper.Mock.ExpectExec(`INSERT INTO "prompt_pins" .+'1.1.0'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	w := helper.PerformRequest("POST", "/api/admin/prompts/"+TestPromptName+"/rollback", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.PromptResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "1.1.0", resp.ActiveVersion)
+
+	// 已经是最早的版本
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")}, TestPromptName, "1.0.0")
+	w = helper.PerformRequest("POST", "/api/admin/prompts/"+TestPromptName+"/rollback", nil)
+	assert.Equal(t, consts.StatusConflict, w.Code)
+	var errResp dto.ErrorResponse
+	DecodeResponse(t, w, &errResp)
+	assert.Equal(t, "NO_PREVIOUS_VERSION", errResp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestUnpinVersion_Success 测试取消固定（恢复跟随最新版本）
+func TestUnpinVersion_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")}, TestPromptName, "1.0.0")
+	helper.Mock.ExpectExec(`DELETE FROM "prompt_pins" WHERE \("name" = 'chat.title'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	w := helper.PerformRequest("DELETE", "/api/admin/prompts/"+TestPromptName+"/pin", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.PromptResponse
+	DecodeResponse(t, w, &resp)
+	assert.Empty(t, resp.PinnedVersion)
+	assert.Equal(t, "1.1.0", resp.ActiveVersion)
+
+	helper.AssertExpectations(t)
+}
+
+// TestRenderPrompt 测试渲染预览
+func TestRenderPrompt(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 缓存：加载一次后连续渲染不再读取数据库
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")}, TestPromptName, "1.0.0")
+
+	w := helper.PerformRequest("POST", "/api/admin/prompts/"+TestPromptName+"/render",
+		map[string]interface{}{"variables": map[string]string{"text": "周末去哪玩", "language": "English"}})
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.RenderPromptResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "1.0.0", resp.Version)
+	assert.Equal(t, "用English为对话生成不超过 20 字的标题", resp.Messages[0].Content)
+	assert.Equal(t, "周末去哪玩", resp.Messages[1].Content)
+	assert.Equal(t, 0.3, *resp.Parameters.Temperature)
+
+	// 指定版本
+	w = helper.PerformRequest("POST", "/api/admin/prompts/"+TestPromptName+"/render",
+		map[string]interface{}{"version": "1.1.0", "variables": map[string]string{"text": "x"}})
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "用中文生成简短的标题", resp.Messages[0].Content)
+	assert.Equal(t, "gpt-4o-mini", resp.Parameters.Model)
+	assert.Equal(t, 3600, resp.Parameters.CacheTTLSeconds)
+
+	// 缺少必填变量
+	w = helper.PerformRequest("POST", "/api/admin/prompts/"+TestPromptName+"/render",
+		map[string]interface{}{"variables": map[string]string{"language": "English"}})
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	var errResp dto.ErrorResponse
+	DecodeResponse(t, w, &errResp)
+	assert.Equal(t, "PROMPT_VARIABLE_MISSING", errResp.Error)
+	assert.Contains(t, errResp.Message, "text")
+
+	helper.AssertExpectations(t)
+}
+
+// TestRender_InvalidatedOnPin 测试固定版本后缓存立即失效
+func TestRender_InvalidatedOnPin(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	ctx := context.Background()
+	input := service.RenderInput{Name: TestPromptName, Variables: map[string]string{"text": "x"}}
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")})
+	rendered, err := helper.Service.Render(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", rendered.Version)
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")})
+	helper.Mock.ExpectExec(`INSERT INTO "prompt_pins"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err = helper.Service.PinVersion(ctx, service.PinVersionInput{Name: TestPromptName, Version: "1.0.0"})
+	require.NoError(t, err)
+
+	MockLoad(helper.Mock, [][]driver.Value{StoredVersion("1.1.0")}, TestPromptName, "1.0.0")
+	rendered, err = helper.Service.Render(ctx, input)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", rendered.Version)
+
+	helper.AssertExpectations(t)
+}
+
+// TestEmbeddedTemplates 测试随程序发布的模板都能加载
+func TestEmbeddedTemplates(t *testing.T) {
+	loaded, err := service.LoadTemplates(templates.FS)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+}
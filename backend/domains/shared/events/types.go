@@ -149,7 +149,7 @@ func NewConversationDeletedEvent(payload ConversationDeletedPayload) *Conversati
 type ModelSelectedPayload struct {
 	Model    string
 	Provider string
-	Strategy string // latency, cost, quality
+	Strategy string // latency, cost, quality, random（请求指定模型时可能为空）
 	Reason   string
 }
 
@@ -168,6 +168,7 @@ func NewModelSelectedEvent(payload ModelSelectedPayload) *ModelSelectedEvent {
 // GenerationCompletedPayload 生成完成事件负载
 type GenerationCompletedPayload struct {
 	RequestID    string
+	UserID       string // 终端用户（ChatRequest.User，系统调用时为空）
 	Model        string
 	Provider     string
 	Error        string
@@ -175,6 +176,10 @@ type GenerationCompletedPayload struct {
 	OutputTokens int
 	Latency      int64
 	Success      bool
+
+	// 回退：最初选择的提供商调用失败（或熔断）后改用回退链中的提供商时记录
+	FallbackFrom   string // 最初选择的 "提供商:模型"（没有回退时为空）
+	FallbackReason string // 回退原因：circuit_open、rate_limited、server_error、unavailable
 }
 
 // GenerationCompletedEvent 生成完成事件
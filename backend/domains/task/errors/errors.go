@@ -43,6 +43,11 @@ var (
 	// 场景: AddTag
 	ErrDuplicateTag = errors.New("DUPLICATE_TAG", "标签名称重复", 400)
 
+	// ErrContentBlocked 任务文本未通过内容安全检查
+	// 规则: R1.6
+	// 场景: CreateTask, UpdateTask
+	ErrContentBlocked = errors.New("CONTENT_BLOCKED", "内容未通过安全检查", 400)
+
 	// ErrInvalidFilter 筛选参数无效
 	// 规则: R5.2
 	// 场景: ListTasks
@@ -53,6 +58,51 @@ var (
 	// 场景: ListTasks
 	ErrInvalidPagination = errors.New("INVALID_PAGINATION", "分页参数无效", 400)
 
+	// ErrInvalidParentTask 父任务无效
+	// 规则: R7.4
+	// 场景: CreateTask, InstantiateTemplate
+	ErrInvalidParentTask = errors.New("INVALID_PARENT_TASK", "父任务无效", 400)
+
+	// ErrTemplateNameEmpty 模板名称不能为空
+	// 规则: R7.1
+	// 场景: CreateTemplate, UpdateTemplate
+	ErrTemplateNameEmpty = errors.New("TEMPLATE_NAME_EMPTY", "模板名称不能为空", 400)
+
+	// ErrTemplateTitleEmpty 模板标题不能为空
+	// 规则: R7.1
+	// 场景: CreateTemplate, UpdateTemplate
+	ErrTemplateTitleEmpty = errors.New("TEMPLATE_TITLE_EMPTY", "模板标题不能为空", 400)
+
+	// ErrInvalidDueOffset 截止偏移无效
+	// 规则: R7.1
+	// 场景: CreateTemplate, UpdateTemplate
+	ErrInvalidDueOffset = errors.New("INVALID_DUE_OFFSET", "截止偏移必须大于 0", 400)
+
+	// ErrTooManySubtasks 子任务过多
+	// 规则: R7.1
+	// 场景: CreateTemplate, UpdateTemplate
+	ErrTooManySubtasks = errors.New("TOO_MANY_SUBTASKS", "子任务过多，最多 20 个", 400)
+
+	// ErrTemplateVariableMissing 缺少模板变量
+	// 规则: R7.2
+	// 场景: InstantiateTemplate
+	ErrTemplateVariableMissing = errors.New("TEMPLATE_VARIABLE_MISSING", "缺少模板变量", 400)
+
+	// ErrInvalidUrgencyCoefficient 紧急度系数无效
+	// 规则: R8.2
+	// 场景: UpdateUrgencyCoefficients
+	ErrInvalidUrgencyCoefficient = errors.New("INVALID_URGENCY_COEFFICIENT", "紧急度系数无效", 400)
+
+	// ErrInvalidSnoozeTime 推迟时间无效
+	// 规则: R9.1
+	// 场景: SnoozeTask
+	ErrInvalidSnoozeTime = errors.New("INVALID_SNOOZE_TIME", "推迟时间必须晚于当前时间", 400)
+
+	// ErrTaskNotSnoozed 任务未被推迟
+	// 规则: R9.2
+	// 场景: UnsnoozeTask
+	ErrTaskNotSnoozed = errors.New("TASK_NOT_SNOOZED", "任务未被推迟", 400)
+
 	// ========== 状态错误 (400) ==========
 
 	// ErrTaskAlreadyCompleted 任务已完成
@@ -83,6 +133,15 @@ var (
 	// 场景: GetTask, UpdateTask, DeleteTask, CompleteTask
 	ErrTaskNotFound = errors.New("TASK_NOT_FOUND", "任务不存在", 404)
 
+	// ErrParentTaskNotFound 父任务不存在
+	// 规则: R7.4
+	// 场景: CreateTask
+	ErrParentTaskNotFound = errors.New("PARENT_TASK_NOT_FOUND", "父任务不存在", 404)
+
+	// ErrTemplateNotFound 模板不存在
+	// 场景: GetTemplate, UpdateTemplate, DeleteTemplate, InstantiateTemplate
+	ErrTemplateNotFound = errors.New("TEMPLATE_NOT_FOUND", "模板不存在", 404)
+
 	// ========== 服务器错误 (500) ==========
 
 	// ErrCreationFailed 创建任务失败
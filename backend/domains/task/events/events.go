@@ -3,6 +3,7 @@ package events
 import (
 	"time"
 
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
 	"github.com/google/uuid"
 )
@@ -49,17 +50,19 @@ func (e *BaseEvent) OccurredAt() time.Time {
 //
 // 对应 events.md 中的 TaskCreated
 //
-// 触发时机：任务成功创建后
-// 消费者：Analytics, Notification
+// 触发时机：任务成功创建后（TaskService 设置了事件总线时发布）
+// 消费者：Analytics, Notification, Enrichment（自动建议标签和优先级）
 type TaskCreatedEvent struct {
 	BaseEvent
-	TaskID    string    `json:"task_id"`    // 任务 ID
-	UserID    string    `json:"user_id"`    // 创建者 ID
-	Title     string    `json:"title"`      // 任务标题
-	Priority  string    `json:"priority"`   // 优先级
-	DueDate   *string   `json:"due_date"`   // 截止日期 (ISO 8601)
-	Tags      []string  `json:"tags"`       // 标签列表
-	CreatedAt time.Time `json:"created_at"` // 创建时间
+	TaskID      string    `json:"task_id"`     // 任务 ID
+	UserID      string    `json:"user_id"`     // 创建者 ID
+	Title       string    `json:"title"`       // 任务标题
+	Description string    `json:"description"` // 任务描述
+	Priority    string    `json:"priority"`    // 优先级
+	DueDate     *string   `json:"due_date"`    // 截止日期 (ISO 8601)
+	Tags        []string  `json:"tags"`        // 标签列表
+	ParentID    *string   `json:"parent_id"`   // 父任务 ID（子任务）
+	CreatedAt   time.Time `json:"created_at"`  // 创建时间
 }
 
 // Payload 返回事件负载
@@ -75,11 +78,6 @@ func NewTaskCreatedEvent(task *model.Task) *TaskCreatedEvent {
 		dueDate = &d
 	}
 
-	tags := make([]string, len(task.Tags))
-	for i, tag := range task.Tags {
-		tags[i] = tag.Name
-	}
-
 	return &TaskCreatedEvent{
 		BaseEvent: BaseEvent{
 			EventID:   uuid.New().String(),
@@ -87,13 +85,15 @@ func NewTaskCreatedEvent(task *model.Task) *TaskCreatedEvent {
 			Source:    "task",
 			Timestamp: time.Now(),
 		},
-		TaskID:    task.ID,
-		UserID:    task.UserID,
-		Title:     task.Title,
-		Priority:  string(task.Priority),
-		DueDate:   dueDate,
-		Tags:      tags,
-		CreatedAt: task.CreatedAt,
+		TaskID:      task.ID,
+		UserID:      task.UserID,
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    string(task.Priority),
+		DueDate:     dueDate,
+		Tags:        tagNames(task),
+		ParentID:    task.ParentID,
+		CreatedAt:   task.CreatedAt,
 	}
 }
 
@@ -105,12 +105,15 @@ func NewTaskCreatedEvent(task *model.Task) *TaskCreatedEvent {
 //
 // 对应 events.md 中的 TaskUpdated
 //
-// 触发时机：任务字段更新后
-// 消费者：Analytics
+// 触发时机：任务字段更新后（TaskService 设置了事件总线时发布）
+// 消费者：Analytics, SemanticSearch（重新生成向量）
 type TaskUpdatedEvent struct {
 	BaseEvent
 	TaskID        string                 `json:"task_id"`        // 任务 ID
 	UserID        string                 `json:"user_id"`        // 更新者 ID
+	Title         string                 `json:"title"`          // 更新后的标题
+	Description   string                 `json:"description"`    // 更新后的描述
+	Tags          []string               `json:"tags"`           // 更新后的标签
 	UpdatedFields map[string]interface{} `json:"updated_fields"` // 变更的字段
 	UpdatedAt     time.Time              `json:"updated_at"`     // 更新时间
 }
@@ -131,6 +134,9 @@ func NewTaskUpdatedEvent(task *model.Task, updatedFields map[string]interface{})
 		},
 		TaskID:        task.ID,
 		UserID:        task.UserID,
+		Title:         task.Title,
+		Description:   task.Description,
+		Tags:          tagNames(task),
 		UpdatedFields: updatedFields,
 		UpdatedAt:     task.UpdatedAt,
 	}
@@ -199,8 +205,8 @@ func NewTaskCompletedEvent(task *model.Task) *TaskCompletedEvent {
 //
 // 对应 events.md 中的 TaskDeleted
 //
-// 触发时机：任务删除后
-// 消费者：Analytics, Cleanup
+// 触发时机：任务删除后（TaskService 设置了事件总线时发布）
+// 消费者：Analytics, Cleanup, SemanticSearch（删除向量）
 type TaskDeletedEvent struct {
 	BaseEvent
 	TaskID    string    `json:"task_id"`    // 任务 ID
@@ -233,3 +239,102 @@ func NewTaskDeletedEvent(task *model.Task, deletedAt time.Time) *TaskDeletedEven
 		Reason:    "", // 可以扩展为支持删除原因
 	}
 }
+
+// ========================================
+// TaskResurfacedEvent 任务重新出现事件
+// ========================================
+
+// TaskResurfacedEvent 任务重新出现事件
+//
+// 对应 events.md 中的 TaskResurfaced
+//
+// 触发时机：推迟（snooze）到期，SnoozeScheduler 清除 hidden_until 后
+// 消费者：Notification
+type TaskResurfacedEvent struct {
+	BaseEvent
+	TaskID       string    `json:"task_id"`       // 任务 ID
+	UserID       string    `json:"user_id"`       // 用户 ID
+	Title        string    `json:"title"`         // 任务标题
+	Priority     string    `json:"priority"`      // 优先级
+	DueDate      *string   `json:"due_date"`      // 截止日期 (ISO 8601)
+	HiddenUntil  time.Time `json:"hidden_until"`  // 推迟到的时间
+	ResurfacedAt time.Time `json:"resurfaced_at"` // 实际重新出现的时间
+}
+
+// Payload 返回事件负载
+func (e *TaskResurfacedEvent) Payload() interface{} {
+	return e
+}
+
+// NewTaskResurfacedEvent 创建任务重新出现事件
+func NewTaskResurfacedEvent(task *model.Task, hiddenUntil, resurfacedAt time.Time) *TaskResurfacedEvent {
+	var dueDate *string
+	if task.DueDate != nil {
+		d := task.DueDate.Format(time.RFC3339)
+		dueDate = &d
+	}
+
+	return &TaskResurfacedEvent{
+		BaseEvent: BaseEvent{
+			EventID:   uuid.New().String(),
+			EventType: "task.resurfaced",
+			Source:    "task",
+			Timestamp: time.Now(),
+		},
+		TaskID:       task.ID,
+		UserID:       task.UserID,
+		Title:        task.Title,
+		Priority:     string(task.Priority),
+		DueDate:      dueDate,
+		HiddenUntil:  hiddenUntil,
+		ResurfacedAt: resurfacedAt,
+	}
+}
+
+// tagNames 返回任务的标签名称
+func tagNames(task *model.Task) []string {
+	tags := make([]string, len(task.Tags))
+	for i, tag := range task.Tags {
+		tags[i] = tag.Name
+	}
+	return tags
+}
+
+// ========================================
+// 事件总线适配
+// ========================================
+
+// DomainEvent Task 领域事件的公共方法
+type DomainEvent interface {
+	Type() string
+	ID() string
+	SourceDomain() string
+	OccurredAt() time.Time
+	Payload() interface{}
+}
+
+// busEvent 将 Task 领域事件适配为 shared/events.Event
+type busEvent struct {
+	DomainEvent
+}
+
+// Timestamp 返回事件时间
+func (e busEvent) Timestamp() time.Time {
+	return e.OccurredAt()
+}
+
+// Source 返回来源领域
+func (e busEvent) Source() string {
+	return e.SourceDomain()
+}
+
+// ToBusEvent 将 Task 领域事件转换为可以发布到事件总线的事件
+//
+// 订阅者通过 event.Payload() 拿到原始事件（如 *TaskResurfacedEvent）。
+//
+// Example:
+//
+//	bus.Publish(ctx, events.ToBusEvent(events.NewTaskResurfacedEvent(task, until, now)))
+func ToBusEvent(e DomainEvent) sharedevents.Event {
+	return busEvent{DomainEvent: e}
+}
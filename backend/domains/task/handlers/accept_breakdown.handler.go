@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+)
+
+// AcceptBreakdownHandler 保存用户确认的子任务（HTTP 适配层）
+//
+// 用例：AcceptBreakdown（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/breakdown/accept
+//
+// 请求体中的子任务通常来自 BreakdownTask 的建议（可以修改、删除或新增），
+// 所有子任务在同一事务中创建。
+//
+// 业务逻辑在 service.BreakdownService.AcceptBreakdown() 中实现
+func (deps *HandlerDependencies) AcceptBreakdownHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 解析请求体
+	var req dto.AcceptBreakdownRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 4. 转换为 Domain Input（使用转换层）
+	input, err := toAcceptBreakdownInput(userID, taskID, req)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 调用 Domain Service
+	output, err := deps.breakdownService.AcceptBreakdown(ctx, input)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 6. 转换为 HTTP 响应
+	c.JSON(200, toAcceptBreakdownResponse(output))
+}
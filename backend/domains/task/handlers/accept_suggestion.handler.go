@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// AcceptSuggestionHandler 接受任务的标签和优先级建议（HTTP 适配层）
+//
+// 用例：AcceptSuggestion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/suggestion/accept
+//
+// 请求体可为空（全部接受）；tags 可以只包含部分建议的标签，
+// apply_priority=false 时不修改优先级。
+//
+// 业务逻辑在 service.EnrichmentService.AcceptSuggestion() 中实现
+func (deps *HandlerDependencies) AcceptSuggestionHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 解析 HTTP 请求（请求体可为空）
+	var req dto.AcceptSuggestionRequest
+	if len(c.Request.Body()) > 0 {
+		if err := c.BindAndValidate(&req); err != nil {
+			c.JSON(400, dto.ErrorResponse{
+				Error:   "INVALID_INPUT",
+				Message: "请求参数无效",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	// 4. 调用 Domain Service
+	output, err := deps.enrichmentService.AcceptSuggestion(ctx, service.AcceptSuggestionInput{
+		UserID:        userID,
+		TaskID:        taskID,
+		Tags:          req.Tags,
+		ApplyPriority: req.ApplyPriority,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 转换为 HTTP 响应
+	c.JSON(200, toAcceptSuggestionResponse(output))
+}
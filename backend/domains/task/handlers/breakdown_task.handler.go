@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// BreakdownTaskHandler 生成子任务建议（HTTP 适配层）
+//
+// 用例：BreakdownTask（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/breakdown
+//
+// 返回的子任务只是建议，不会保存；用户修改后通过
+// POST /api/tasks/:id/breakdown/accept 保存。
+//
+// 业务逻辑在 service.BreakdownService.BreakdownTask() 中实现
+func (deps *HandlerDependencies) BreakdownTaskHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.breakdownService.BreakdownTask(ctx, service.BreakdownTaskInput{
+		UserID: userID,
+		TaskID: taskID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toBreakdownTaskResponse(output))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
@@ -35,6 +36,10 @@ func toCreateTaskInput(userID string, req dto.CreateTaskRequest) (service.Create
 		Tags:        req.Tags,
 	}
 
+	if req.ParentID != "" {
+		input.ParentID = &req.ParentID
+	}
+
 	// 解析截止日期
 	if req.DueDate != "" {
 		dueDate, err := time.Parse(time.RFC3339, req.DueDate)
@@ -162,8 +167,11 @@ func toGetTaskInput(userID, taskID string) service.GetTaskInput {
 
 // toGetTaskResponse 将 Domain Output 转换为 HTTP 响应
 func toGetTaskResponse(output *service.GetTaskOutput) dto.GetTaskResponse {
-	task := output.Task
+	return toTaskDetail(output.Task)
+}
 
+// toTaskDetail 将任务实体转换为详情响应
+func toTaskDetail(task *model.Task) dto.GetTaskResponse {
 	resp := dto.GetTaskResponse{
 		TaskID:      task.ID,
 		Title:       task.Title,
@@ -185,6 +193,13 @@ func toGetTaskResponse(output *service.GetTaskOutput) dto.GetTaskResponse {
 		resp.CompletedAt = &completedAt
 	}
 
+	resp.ParentID = task.ParentID
+
+	if task.HiddenUntil != nil {
+		hiddenUntil := task.HiddenUntil.Format(time.RFC3339)
+		resp.HiddenUntil = &hiddenUntil
+	}
+
 	// 转换标签
 	tags := make([]string, len(task.Tags))
 	for i, tag := range task.Tags {
@@ -203,6 +218,11 @@ func toGetTaskResponse(output *service.GetTaskOutput) dto.GetTaskResponse {
 //
 // 注意：查询参数已经通过 Hertz 的 BindAndValidate 绑定到 req 中
 func toListTasksInput(userID string, req dto.ListTasksRequest) service.ListTasksInput {
+	// sort 是 sort_by 的简写，两者同时提供时以 sort_by 为准
+	if req.SortBy == "" {
+		req.SortBy = req.Sort
+	}
+
 	// 构建筛选条件
 	filter := repository.TaskFilter{
 		UserID:    &userID,
@@ -210,6 +230,8 @@ func toListTasksInput(userID string, req dto.ListTasksRequest) service.ListTasks
 		Limit:     req.Limit,
 		SortBy:    req.SortBy,
 		SortOrder: req.SortOrder,
+
+		IncludeSnoozed: req.IncludeSnoozed,
 	}
 
 	// 设置可选的筛选条件
@@ -246,9 +268,20 @@ func toListTasksInput(userID string, req dto.ListTasksRequest) service.ListTasks
 
 // toListTasksResponse 将 Domain Output 转换为 HTTP 响应
 func toListTasksResponse(output *service.ListTasksOutput) dto.ListTasksResponse {
-	// 转换任务列表
-	tasks := make([]dto.TaskItem, len(output.Tasks))
-	for i, task := range output.Tasks {
+	return dto.ListTasksResponse{
+		Tasks:      toTaskItems(output.Tasks, output.Urgency),
+		TotalCount: output.TotalCount,
+		Page:       output.Page,
+		Limit:      output.Limit,
+		HasMore:    output.HasMore,
+		Truncated:  output.Truncated,
+	}
+}
+
+// toTaskItems 将任务实体列表转换为列表项（urgency 为 nil 时不返回紧急度）
+func toTaskItems(taskList []*model.Task, urgency map[string]float64) []dto.TaskItem {
+	tasks := make([]dto.TaskItem, len(taskList))
+	for i, task := range taskList {
 		summary := dto.TaskItem{
 			TaskID:    task.ID,
 			Title:     task.Title,
@@ -262,6 +295,10 @@ func toListTasksResponse(output *service.ListTasksOutput) dto.ListTasksResponse
 			dueDate := task.DueDate.Format(time.RFC3339)
 			summary.DueDate = &dueDate
 		}
+		if task.HiddenUntil != nil {
+			hiddenUntil := task.HiddenUntil.Format(time.RFC3339)
+			summary.HiddenUntil = &hiddenUntil
+		}
 
 		// 标签
 		tags := make([]string, len(task.Tags))
@@ -270,14 +307,319 @@ func toListTasksResponse(output *service.ListTasksOutput) dto.ListTasksResponse
 		}
 		summary.Tags = tags
 
+		// 紧急度
+		if score, ok := urgency[task.ID]; ok {
+			rounded := math.Round(score*1000) / 1000
+			summary.Urgency = &rounded
+		}
+
 		tasks[i] = summary
 	}
 
-	return dto.ListTasksResponse{
-		Tasks:      tasks,
-		TotalCount: output.TotalCount,
-		Page:       output.Page,
-		Limit:      output.Limit,
-		HasMore:    output.HasMore,
+	return tasks
+}
+
+// ========================================
+// SnoozeTask / UnsnoozeTask 转换
+// ========================================
+
+// toSnoozeTaskInput 将 HTTP 请求转换为 Domain Input
+func toSnoozeTaskInput(userID, taskID string, req dto.SnoozeTaskRequest) (service.SnoozeTaskInput, error) {
+	input := service.SnoozeTaskInput{
+		UserID: userID,
+		TaskID: taskID,
+	}
+
+	if req.Until == "" {
+		return input, fmt.Errorf("INVALID_SNOOZE_TIME: 推迟时间不能为空")
+	}
+	until, err := time.Parse(time.RFC3339, req.Until)
+	if err != nil {
+		return input, fmt.Errorf("INVALID_SNOOZE_TIME: 推迟时间格式无效")
+	}
+	input.Until = until
+
+	return input, nil
+}
+
+// toUnsnoozeTaskInput 将请求参数转换为 Domain Input
+func toUnsnoozeTaskInput(userID, taskID string) service.UnsnoozeTaskInput {
+	return service.UnsnoozeTaskInput{
+		UserID: userID,
+		TaskID: taskID,
+	}
+}
+
+// ========================================
+// TaskTemplate 转换
+// ========================================
+
+// toTemplateInput 将 HTTP 请求转换为 Domain Input
+func toTemplateInput(userID, templateID string, req dto.TemplateRequest) (service.TemplateInput, error) {
+	input := service.TemplateInput{
+		UserID:       userID,
+		TemplateID:   templateID,
+		Name:         req.Name,
+		TitlePattern: req.TitlePattern,
+		Description:  req.Description,
+		Priority:     model.Priority(req.Priority),
+		Tags:         req.Tags,
+	}
+
+	dueOffset, err := parseDueOffset(req.DueOffset)
+	if err != nil {
+		return input, err
+	}
+	input.DueOffset = dueOffset
+
+	input.Subtasks = make([]model.SubtaskTemplate, len(req.Subtasks))
+	for i, sub := range req.Subtasks {
+		subOffset, err := parseDueOffset(sub.DueOffset)
+		if err != nil {
+			return input, err
+		}
+		input.Subtasks[i] = model.SubtaskTemplate{
+			TitlePattern: sub.TitlePattern,
+			Description:  sub.Description,
+			Priority:     model.Priority(sub.Priority),
+			DueOffset:    subOffset,
+		}
+	}
+
+	return input, nil
+}
+
+// toTemplateResponse 将模板实体转换为 HTTP 响应
+func toTemplateResponse(tpl *model.TaskTemplate) dto.TemplateResponse {
+	resp := dto.TemplateResponse{
+		TemplateID:   tpl.ID,
+		Name:         tpl.Name,
+		TitlePattern: tpl.TitlePattern,
+		Description:  tpl.Description,
+		Priority:     string(tpl.Priority),
+		Tags:         tpl.Tags,
+		DueOffset:    formatDueOffset(tpl.DueOffset),
+		Subtasks:     make([]dto.SubtaskTemplateItem, len(tpl.Subtasks)),
+		CreatedAt:    tpl.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    tpl.UpdatedAt.Format(time.RFC3339),
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+
+	for i, sub := range tpl.Subtasks {
+		item := dto.SubtaskTemplateItem{
+			TitlePattern: sub.TitlePattern,
+			Description:  sub.Description,
+			Priority:     string(sub.Priority),
+		}
+		if offset := formatDueOffset(sub.DueOffset); offset != nil {
+			item.DueOffset = *offset
+		}
+		resp.Subtasks[i] = item
+	}
+
+	return resp
+}
+
+// toListTemplatesResponse 将 Domain Output 转换为 HTTP 响应
+func toListTemplatesResponse(output *service.ListTemplatesOutput) dto.ListTemplatesResponse {
+	templates := make([]dto.TemplateResponse, len(output.Templates))
+	for i, tpl := range output.Templates {
+		templates[i] = toTemplateResponse(tpl)
+	}
+	return dto.ListTemplatesResponse{Templates: templates}
+}
+
+// toInstantiateTemplateResponse 将 Domain Output 转换为 HTTP 响应
+func toInstantiateTemplateResponse(output *service.InstantiateTemplateOutput) dto.InstantiateTemplateResponse {
+	subtasks := make([]dto.GetTaskResponse, len(output.Subtasks))
+	for i, sub := range output.Subtasks {
+		subtasks[i] = toTaskDetail(sub)
+	}
+	return dto.InstantiateTemplateResponse{
+		Task:     toTaskDetail(output.Task),
+		Subtasks: subtasks,
+	}
+}
+
+// parseDueOffset 解析截止偏移（Go duration 格式，空字符串表示不设置）
+func parseDueOffset(value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	offset, err := time.ParseDuration(value)
+	if err != nil || offset <= 0 {
+		return nil, fmt.Errorf("INVALID_DUE_OFFSET: 截止偏移格式无效，示例：24h、90m")
+	}
+	return &offset, nil
+}
+
+// formatDueOffset 格式化截止偏移
+func formatDueOffset(offset *time.Duration) *string {
+	if offset == nil {
+		return nil
+	}
+	value := offset.String()
+	return &value
+}
+
+// ========================================
+// Urgency 转换
+// ========================================
+
+// toNextTasksResponse 将 Domain Output 转换为 HTTP 响应
+func toNextTasksResponse(output *service.NextTasksOutput) dto.NextTasksResponse {
+	return dto.NextTasksResponse{
+		Tasks: toTaskItems(output.Tasks, output.Urgency),
+	}
+}
+
+// toSemanticSearchResponse 将 Domain Output 转换为 HTTP 响应
+func toSemanticSearchResponse(output *service.SemanticSearchOutput) dto.SemanticSearchResponse {
+	tasks := make([]*model.Task, len(output.Results))
+	for i, result := range output.Results {
+		tasks[i] = result.Task
+	}
+	items := toTaskItems(tasks, nil)
+	for i, result := range output.Results {
+		score := math.Round(result.Score*1000) / 1000
+		items[i].Score = &score
+	}
+	return dto.SemanticSearchResponse{Tasks: items}
+}
+
+// toUpdateUrgencyCoefficientsInput 将 HTTP 请求转换为 Domain Input
+func toUpdateUrgencyCoefficientsInput(userID string, req dto.UpdateUrgencyCoefficientsRequest) service.UpdateUrgencyCoefficientsInput {
+	return service.UpdateUrgencyCoefficientsInput{
+		UserID:         userID,
+		PriorityHigh:   req.PriorityHigh,
+		PriorityMedium: req.PriorityMedium,
+		PriorityLow:    req.PriorityLow,
+		Due:            req.Due,
+		Age:            req.Age,
+		AgeMaxDays:     req.AgeMaxDays,
+		Blocked:        req.Blocked,
+		Active:         req.Active,
+		Tags:           req.Tags,
+		TagBoosts:      req.TagBoosts,
+		Reset:          req.Reset,
+	}
+}
+
+// toUrgencyCoefficientsResponse 将 Domain Output 转换为 HTTP 响应
+func toUrgencyCoefficientsResponse(output *service.GetUrgencyCoefficientsOutput) dto.UrgencyCoefficientsResponse {
+	c := output.Coefficients
+	tagBoosts := c.TagBoosts
+	if tagBoosts == nil {
+		tagBoosts = map[string]float64{}
+	}
+	return dto.UrgencyCoefficientsResponse{
+		PriorityHigh:   c.PriorityHigh,
+		PriorityMedium: c.PriorityMedium,
+		PriorityLow:    c.PriorityLow,
+		Due:            c.Due,
+		Age:            c.Age,
+		AgeMaxDays:     c.AgeMaxDays,
+		Blocked:        c.Blocked,
+		Active:         c.Active,
+		Tags:           c.Tags,
+		TagBoosts:      tagBoosts,
+		IsDefault:      output.IsDefault,
+	}
+}
+
+// ========================================
+// Breakdown 转换
+// ========================================
+
+// toBreakdownTaskResponse 将 Domain Output 转换为 HTTP 响应
+func toBreakdownTaskResponse(output *service.BreakdownTaskOutput) dto.BreakdownTaskResponse {
+	subtasks := make([]dto.SubtaskProposalItem, len(output.Subtasks))
+	for i, p := range output.Subtasks {
+		item := dto.SubtaskProposalItem{
+			Title:       p.Title,
+			Description: p.Description,
+			Priority:    string(p.Priority),
+			DueInDays:   p.DueInDays,
+		}
+		if p.DueDate != nil {
+			dueDate := p.DueDate.Format(time.RFC3339)
+			item.DueDate = &dueDate
+		}
+		subtasks[i] = item
+	}
+	return dto.BreakdownTaskResponse{
+		TaskID:      output.Task.ID,
+		Subtasks:    subtasks,
+		Model:       output.Model,
+		GeneratedAt: output.GeneratedAt.Format(time.RFC3339),
+	}
+}
+
+// toAcceptBreakdownInput 将 HTTP 请求转换为 Domain Input
+func toAcceptBreakdownInput(userID, taskID string, req dto.AcceptBreakdownRequest) (service.AcceptBreakdownInput, error) {
+	input := service.AcceptBreakdownInput{
+		UserID:   userID,
+		TaskID:   taskID,
+		Subtasks: make([]service.CreateTaskInput, len(req.Subtasks)),
+	}
+	for i, item := range req.Subtasks {
+		sub, err := toCreateTaskInput(userID, dto.CreateTaskRequest{
+			Title:       item.Title,
+			Description: item.Description,
+			Priority:    item.Priority,
+			DueDate:     item.DueDate,
+			Tags:        item.Tags,
+		})
+		if err != nil {
+			return input, err
+		}
+		input.Subtasks[i] = sub
+	}
+	return input, nil
+}
+
+// toAcceptBreakdownResponse 将 Domain Output 转换为 HTTP 响应
+func toAcceptBreakdownResponse(output *service.AcceptBreakdownOutput) dto.AcceptBreakdownResponse {
+	subtasks := make([]dto.GetTaskResponse, len(output.Subtasks))
+	for i, sub := range output.Subtasks {
+		subtasks[i] = toTaskDetail(sub)
+	}
+	return dto.AcceptBreakdownResponse{
+		Task:     toTaskDetail(output.Task),
+		Subtasks: subtasks,
+	}
+}
+
+// ========================================
+// Suggestion 转换
+// ========================================
+
+// toSuggestionResponse 将领域模型转换为 HTTP 响应
+func toSuggestionResponse(s *model.TaskSuggestion) dto.SuggestionResponse {
+	resp := dto.SuggestionResponse{
+		TaskID:           s.TaskID,
+		Tags:             s.Tags,
+		Priority:         string(s.Priority),
+		OriginalPriority: string(s.OriginalPriority),
+		Model:            s.Model,
+		Status:           string(s.Status),
+		AcceptedTags:     s.AcceptedTags,
+		PriorityAccepted: s.PriorityAccepted,
+		CreatedAt:        s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.ResolvedAt != nil {
+		resolvedAt := s.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &resolvedAt
+	}
+	return resp
+}
+
+// toAcceptSuggestionResponse 将 Domain Output 转换为 HTTP 响应
+func toAcceptSuggestionResponse(output *service.AcceptSuggestionOutput) dto.AcceptSuggestionResponse {
+	return dto.AcceptSuggestionResponse{
+		Task:       toTaskDetail(output.Task),
+		Suggestion: toSuggestionResponse(output.Suggestion),
 	}
 }
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+)
+
+// CreateTemplateHandler 创建任务模板（HTTP 适配层）
+//
+// 用例：CreateTemplate（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/templates
+//
+// 业务逻辑在 service.TemplateService.CreateTemplate() 中实现
+func (deps *HandlerDependencies) CreateTemplateHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析 HTTP 请求
+	var req dto.TemplateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 3. 转换为 Domain Input
+	input, err := toTemplateInput(userID, "", req)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 调用 Domain Service
+	output, err := deps.templateService.CreateTemplate(ctx, input)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 转换为 HTTP 响应
+	c.JSON(200, toTemplateResponse(output.Template))
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// DeleteTemplateHandler 删除任务模板（HTTP 适配层）
+//
+// 用例：DeleteTemplate（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: DELETE
+//   - Path: /api/templates/:id
+//
+// 删除模板不影响已由该模板创建的任务。
+func (deps *HandlerDependencies) DeleteTemplateHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	templateID := c.Param("id")
+	if templateID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "模板 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	err := deps.templateService.DeleteTemplate(ctx, service.GetTemplateInput{
+		UserID:     userID,
+		TemplateID: templateID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回响应
+	c.JSON(200, dto.DeleteTemplateResponse{Success: true})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// GetSuggestionHandler 获取任务的标签和优先级建议（HTTP 适配层）
+//
+// 用例：GetSuggestion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/tasks/:id/suggestion
+//
+// 建议在创建任务后异步生成（APP_LLM_TASK_ENRICHMENT=true），
+// 还没有生成或没有新内容时返回 404 SUGGESTION_NOT_FOUND。
+//
+// 业务逻辑在 service.EnrichmentService.GetSuggestion() 中实现
+func (deps *HandlerDependencies) GetSuggestionHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	suggestion, err := deps.enrichmentService.GetSuggestion(ctx, service.GetSuggestionInput{
+		UserID: userID,
+		TaskID: taskID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toSuggestionResponse(suggestion))
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// GetTemplateHandler 获取任务模板详情（HTTP 适配层）
+//
+// 用例：GetTemplate（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/templates/:id
+func (deps *HandlerDependencies) GetTemplateHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	templateID := c.Param("id")
+	if templateID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "模板 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.templateService.GetTemplate(ctx, service.GetTemplateInput{
+		UserID:     userID,
+		TemplateID: templateID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toTemplateResponse(output.Template))
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetUrgencyCoefficientsHandler 获取当前用户的紧急度系数（HTTP 适配层）
+//
+// 用例：GetUrgencyCoefficients（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/tasks/urgency-coefficients
+func (deps *HandlerDependencies) GetUrgencyCoefficientsHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	output, err := deps.urgencyService.GetUrgencyCoefficients(ctx, userID)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 转换为 HTTP 响应
+	c.JSON(200, toUrgencyCoefficientsResponse(output))
+}
@@ -37,6 +37,29 @@ func handleDomainError(c *app.RequestContext, err error) {
 	}
 }
 
+// requireUserID 获取 JWT 中间件注入的用户 ID
+//
+// 获取失败时直接写入错误响应，调用方只需判断 ok 并返回。
+func requireUserID(c *app.RequestContext) (string, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(401, dto.ErrorResponse{
+			Error:   "UNAUTHORIZED",
+			Message: "未授权访问",
+		})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok {
+		c.JSON(500, dto.ErrorResponse{
+			Error:   "INTERNAL_ERROR",
+			Message: "用户 ID 类型错误",
+		})
+		return "", false
+	}
+	return userIDStr, true
+}
+
 // extractErrorCode 从错误消息中提取错误码
 //
 // 支持格式：
@@ -70,23 +93,41 @@ func extractErrorMessage(errMsg string) string {
 func getHTTPStatusCode(code string, err error) int {
 	// 已知的业务错误（400）
 	businessErrors := map[string]bool{
-		"TASK_TITLE_EMPTY":          true,
-		"TASK_DESCRIPTION_TOO_LONG": true,
-		"TASK_ALREADY_COMPLETED":    true,
-		"INVALID_DUE_DATE":          true,
-		"INVALID_PRIORITY":          true,
-		"TOO_MANY_TAGS":             true,
-		"TAG_NAME_EMPTY":            true,
-		"DUPLICATE_TAG":             true,
-		"INVALID_INPUT":             true,
-		"INVALID_QUERY":             true,
-		"INVALID_FILTER":            true,
-		"INVALID_PAGINATION":        true,
+		"TASK_TITLE_EMPTY":            true,
+		"TASK_DESCRIPTION_TOO_LONG":   true,
+		"TASK_ALREADY_COMPLETED":      true,
+		"INVALID_DUE_DATE":            true,
+		"INVALID_PRIORITY":            true,
+		"TOO_MANY_TAGS":               true,
+		"TAG_NAME_EMPTY":              true,
+		"DUPLICATE_TAG":               true,
+		"INVALID_INPUT":               true,
+		"INVALID_QUERY":               true,
+		"INVALID_FILTER":              true,
+		"INVALID_PAGINATION":          true,
+		"INVALID_PARENT_TASK":         true,
+		"TEMPLATE_NAME_EMPTY":         true,
+		"TEMPLATE_NAME_TOO_LONG":      true,
+		"TEMPLATE_TITLE_EMPTY":        true,
+		"TEMPLATE_VARIABLE_MISSING":   true,
+		"TOO_MANY_SUBTASKS":           true,
+		"INVALID_DUE_OFFSET":          true,
+		"TASK_TITLE_TOO_LONG":         true,
+		"INVALID_SNOOZE_TIME":         true,
+		"TASK_NOT_SNOOZED":            true,
+		"NO_SUBTASKS":                 true,
+		"TAG_NOT_SUGGESTED":           true,
+		"EMPTY_SUGGESTION":            true,
+		"SUGGESTION_ALREADY_RESOLVED": true,
+		"CONTENT_BLOCKED":             true,
 	}
 
 	// 资源不存在错误（404）
 	notFoundErrors := map[string]bool{
-		"TASK_NOT_FOUND": true,
+		"TASK_NOT_FOUND":        true,
+		"TEMPLATE_NOT_FOUND":    true,
+		"PARENT_TASK_NOT_FOUND": true,
+		"SUGGESTION_NOT_FOUND":  true,
 	}
 
 	if businessErrors[code] {
@@ -97,6 +138,14 @@ func getHTTPStatusCode(code string, err error) int {
 		return 404
 	}
 
+	switch code {
+	case "QUOTA_EXCEEDED":
+		return 429
+	case "BREAKDOWN_FAILED", "EMBEDDING_FAILED":
+		// 上游模型服务失败或输出无法通过校验
+		return 502
+	}
+
 	// 系统错误（500）
 	if strings.HasSuffix(code, "_FAILED") {
 		return 500
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// InstantiateTemplateHandler 根据模板创建任务（HTTP 适配层）
+//
+// 用例：InstantiateTemplate（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/templates/:id/instantiate
+//
+// 请求体可选，variables 用于替换模板中的自定义变量（如 {{name}}），
+// 内置变量 {{date}}、{{time}} 由服务端按实例化时间填充。
+//
+// 业务逻辑在 service.TemplateService.InstantiateTemplate() 中实现
+func (deps *HandlerDependencies) InstantiateTemplateHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	templateID := c.Param("id")
+	if templateID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "模板 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 解析 HTTP 请求（请求体可为空）
+	var req dto.InstantiateTemplateRequest
+	if len(c.Request.Body()) > 0 {
+		if err := c.BindAndValidate(&req); err != nil {
+			c.JSON(400, dto.ErrorResponse{
+				Error:   "INVALID_INPUT",
+				Message: "请求参数无效",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	// 4. 调用 Domain Service
+	output, err := deps.templateService.InstantiateTemplate(ctx, service.InstantiateTemplateInput{
+		UserID:     userID,
+		TemplateID: templateID,
+		Variables:  req.Variables,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 转换为 HTTP 响应
+	c.JSON(200, toInstantiateTemplateResponse(output))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
 )
 
 // ListTasksHandler 列出任务（HTTP 适配层）
@@ -15,6 +16,8 @@ import (
 //   - Method: GET
 //   - Path: /api/tasks
 //
+// 支持 sort_by=urgency（或 sort=urgency）按紧急度排序，响应中包含每个任务的 urgency。
+//
 // Handler 职责（瘦层）：
 //  1. 解析 HTTP 查询参数
 //  2. 转换 DTO（使用转换层）
@@ -52,6 +55,16 @@ func (deps *HandlerDependencies) ListTasksHandler(ctx context.Context, c *app.Re
 		return
 	}
 
+	// sort_by / sort 通过 query 标签绑定，需要手动校验取值
+	if !isValidSortField(req.SortBy) || !isValidSortField(req.Sort) ||
+		(req.SortOrder != "" && req.SortOrder != "asc" && req.SortOrder != "desc") {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "排序参数无效",
+		})
+		return
+	}
+
 	// 3. 设置默认值
 	if req.Page == 0 {
 		req.Page = 1
@@ -59,7 +72,7 @@ func (deps *HandlerDependencies) ListTasksHandler(ctx context.Context, c *app.Re
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
-	if req.SortBy == "" {
+	if req.SortBy == "" && req.Sort == "" {
 		req.SortBy = "created_at"
 	}
 	if req.SortOrder == "" {
@@ -69,8 +82,14 @@ func (deps *HandlerDependencies) ListTasksHandler(ctx context.Context, c *app.Re
 	// 4. 转换为 Domain Input（使用转换层）
 	input := toListTasksInput(userIDStr, req)
 
-	// 5. 调用 Domain Service
-	output, err := deps.taskService.ListTasks(ctx, input)
+	// 5. 调用 Domain Service（紧急度无法在数据库中排序，由 UrgencyService 处理）
+	var output *service.ListTasksOutput
+	var err error
+	if input.Filter.SortBy == "urgency" {
+		output, err = deps.urgencyService.ListTasksByUrgency(ctx, input)
+	} else {
+		output, err = deps.taskService.ListTasks(ctx, input)
+	}
 	if err != nil {
 		handleDomainError(c, err)
 		return
@@ -79,3 +98,12 @@ func (deps *HandlerDependencies) ListTasksHandler(ctx context.Context, c *app.Re
 	// 6. 转换为 HTTP 响应（使用转换层）
 	c.JSON(200, toListTasksResponse(output))
 }
+
+// isValidSortField 检查排序字段是否合法（空值表示未指定）
+func isValidSortField(field string) bool {
+	switch field {
+	case "", "created_at", "due_date", "priority", "urgency":
+		return true
+	}
+	return false
+}
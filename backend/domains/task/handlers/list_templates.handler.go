@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// ListTemplatesHandler 列出当前用户的任务模板（HTTP 适配层）
+//
+// 用例：ListTemplates（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/templates
+func (deps *HandlerDependencies) ListTemplatesHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	output, err := deps.templateService.ListTemplates(ctx, userID)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 转换为 HTTP 响应
+	c.JSON(200, toListTemplatesResponse(output))
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// NextTasksHandler 推荐下一步要做的任务（HTTP 适配层）
+//
+// 用例：NextTasks（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/tasks/next?limit=5
+//
+// 业务逻辑在 service.UrgencyService.NextTasks() 中实现
+func (deps *HandlerDependencies) NextTasksHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析查询参数
+	var req dto.NextTasksRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.urgencyService.NextTasks(ctx, service.NextTasksInput{
+		UserID: userID,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toNextTasksResponse(output))
+}
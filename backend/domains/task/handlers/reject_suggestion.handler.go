@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// RejectSuggestionHandler 拒绝任务的标签和优先级建议（HTTP 适配层）
+//
+// 用例：RejectSuggestion（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/suggestion/reject
+//
+// 任务不变，拒绝结果用于统计建议的准确率。
+//
+// 业务逻辑在 service.EnrichmentService.RejectSuggestion() 中实现
+func (deps *HandlerDependencies) RejectSuggestionHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	suggestion, err := deps.enrichmentService.RejectSuggestion(ctx, service.RejectSuggestionInput{
+		UserID: userID,
+		TaskID: taskID,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toSuggestionResponse(suggestion))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// SemanticSearchHandler 按语义搜索任务（HTTP 适配层）
+//
+// 用例：SemanticSearchTasks（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/tasks/semantic-search?q=牙医&limit=10
+//
+// 业务逻辑在 service.SemanticSearchService.SemanticSearch() 中实现
+func (deps *HandlerDependencies) SemanticSearchHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析查询参数
+	var req dto.SemanticSearchRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.semanticSearch.SemanticSearch(ctx, service.SemanticSearchInput{
+		UserID: userID,
+		Query:  req.Q,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toSemanticSearchResponse(output))
+}
@@ -17,7 +17,12 @@ import (
 // - 构造 HTTP 响应
 // - 处理错误转换
 type HandlerDependencies struct {
-	taskService *service.TaskService
+	taskService       *service.TaskService
+	templateService   *service.TemplateService
+	urgencyService    *service.UrgencyService
+	breakdownService  *service.BreakdownService
+	enrichmentService *service.EnrichmentService
+	semanticSearch    *service.SemanticSearchService
 	// Extension point: 添加更多依赖
 	// eventBus events.EventBus
 	// cache    cache.Cache
@@ -27,11 +32,21 @@ type HandlerDependencies struct {
 //
 // 参数：
 //   - taskService: 任务领域服务
+//   - templateService: 任务模板领域服务
+//   - urgencyService: 任务紧急度领域服务
+//   - breakdownService: 任务拆解领域服务（AI 生成子任务建议）
+//   - enrichmentService: 自动建议领域服务（新任务的标签和优先级建议）
+//   - semanticSearch: 语义搜索领域服务
 //
 // 返回：
 //   - *HandlerDependencies: 依赖容器实例
-func NewHandlerDependencies(taskService *service.TaskService) *HandlerDependencies {
+func NewHandlerDependencies(taskService *service.TaskService, templateService *service.TemplateService, urgencyService *service.UrgencyService, breakdownService *service.BreakdownService, enrichmentService *service.EnrichmentService, semanticSearch *service.SemanticSearchService) *HandlerDependencies {
 	return &HandlerDependencies{
-		taskService: taskService,
+		taskService:       taskService,
+		templateService:   templateService,
+		urgencyService:    urgencyService,
+		breakdownService:  breakdownService,
+		enrichmentService: enrichmentService,
+		semanticSearch:    semanticSearch,
 	}
 }
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+)
+
+// SnoozeTaskHandler 推迟任务（HTTP 适配层）
+//
+// 用例：SnoozeTask（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/snooze
+//
+// 推迟期间任务不出现在默认列表中（include_snoozed=true 时显示），
+// 截止日期保持不变。
+//
+// 业务逻辑在 service.TaskService.SnoozeTask() 中实现
+func (deps *HandlerDependencies) SnoozeTaskHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 解析请求体
+	var req dto.SnoozeTaskRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 4. 转换为 Domain Input（使用转换层）
+	input, err := toSnoozeTaskInput(userID, taskID, req)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 调用 Domain Service
+	output, err := deps.taskService.SnoozeTask(ctx, input)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 6. 转换为 HTTP 响应
+	c.JSON(200, toTaskDetail(output.Task))
+}
+
+// UnsnoozeTaskHandler 取消推迟（HTTP 适配层）
+//
+// 用例：UnsnoozeTask（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: POST
+//   - Path: /api/tasks/:id/unsnooze
+//
+// 业务逻辑在 service.TaskService.UnsnoozeTask() 中实现
+func (deps *HandlerDependencies) UnsnoozeTaskHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	taskID := c.Param("id")
+	if taskID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "任务 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.taskService.UnsnoozeTask(ctx, toUnsnoozeTaskInput(userID, taskID))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toTaskDetail(output.Task))
+}
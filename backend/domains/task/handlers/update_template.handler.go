@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+)
+
+// UpdateTemplateHandler 更新任务模板（HTTP 适配层）
+//
+// 用例：UpdateTemplate（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/templates/:id
+//
+// 业务逻辑在 service.TemplateService.UpdateTemplate() 中实现
+func (deps *HandlerDependencies) UpdateTemplateHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 获取路径参数
+	templateID := c.Param("id")
+	if templateID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "模板 ID 不能为空",
+		})
+		return
+	}
+
+	// 3. 解析 HTTP 请求
+	var req dto.TemplateRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 4. 转换为 Domain Input
+	input, err := toTemplateInput(userID, templateID, req)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 5. 调用 Domain Service
+	output, err := deps.templateService.UpdateTemplate(ctx, input)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 6. 转换为 HTTP 响应
+	c.JSON(200, toTemplateResponse(output.Template))
+}
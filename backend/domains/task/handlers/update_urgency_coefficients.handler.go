@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+)
+
+// UpdateUrgencyCoefficientsHandler 更新当前用户的紧急度系数（HTTP 适配层）
+//
+// 用例：UpdateUrgencyCoefficients（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/tasks/urgency-coefficients
+//
+// 只覆盖请求中提供的字段；reset=true 时恢复默认系数。
+func (deps *HandlerDependencies) UpdateUrgencyCoefficientsHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析 HTTP 请求
+	var req dto.UpdateUrgencyCoefficientsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_INPUT",
+			Message: "请求参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.urgencyService.UpdateUrgencyCoefficients(ctx, toUpdateUrgencyCoefficientsInput(userID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 转换为 HTTP 响应
+	c.JSON(200, toUrgencyCoefficientsResponse(output))
+}
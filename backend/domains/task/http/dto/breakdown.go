@@ -0,0 +1,40 @@
+package dto
+
+// SubtaskProposalItem 拆解建议中的子任务（未保存）
+type SubtaskProposalItem struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Priority    string  `json:"priority"`
+	DueInDays   *int    `json:"due_in_days"` // 相对生成当天的天数（null 表示不设置截止日期）
+	DueDate     *string `json:"due_date"`    // 根据 due_in_days 计算，不晚于父任务的截止日期
+}
+
+// BreakdownTaskResponse 拆解任务响应
+//
+// subtasks 只是建议，修改后通过 POST /api/tasks/:id/breakdown/accept 保存。
+type BreakdownTaskResponse struct {
+	TaskID      string                `json:"task_id"`
+	Subtasks    []SubtaskProposalItem `json:"subtasks"`
+	Model       string                `json:"model"`
+	GeneratedAt string                `json:"generated_at"`
+}
+
+// AcceptSubtaskItem 用户确认的子任务
+type AcceptSubtaskItem struct {
+	Title       string   `json:"title" binding:"required,min=1,max=200"`
+	Description string   `json:"description" binding:"max=5000"`
+	Priority    string   `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueDate     string   `json:"due_date" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Tags        []string `json:"tags" binding:"omitempty,max=10,dive,max=50"`
+}
+
+// AcceptBreakdownRequest 接受拆解建议请求
+type AcceptBreakdownRequest struct {
+	Subtasks []AcceptSubtaskItem `json:"subtasks" binding:"required,min=1,max=20"`
+}
+
+// AcceptBreakdownResponse 接受拆解建议响应
+type AcceptBreakdownResponse struct {
+	Task     GetTaskResponse   `json:"task"`
+	Subtasks []GetTaskResponse `json:"subtasks"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// SuggestionResponse 任务的标签和优先级建议
+type SuggestionResponse struct {
+	TaskID           string   `json:"task_id"`
+	Tags             []string `json:"tags"`              // 建议的标签（来自用户已有的标签）
+	Priority         string   `json:"priority"`          // 建议的优先级
+	OriginalPriority string   `json:"original_priority"` // 生成建议时任务的优先级
+	Model            string   `json:"model"`
+	Status           string   `json:"status"`            // pending, accepted, rejected
+	AcceptedTags     []string `json:"accepted_tags"`     // 用户接受的标签
+	PriorityAccepted bool     `json:"priority_accepted"` // 是否接受了建议的优先级
+	CreatedAt        string   `json:"created_at"`
+	ResolvedAt       *string  `json:"resolved_at"`
+}
+
+// AcceptSuggestionRequest 接受建议请求（请求体可为空，表示全部接受）
+type AcceptSuggestionRequest struct {
+	Tags          []string `json:"tags" binding:"omitempty,max=10,dive,max=50"` // 接受的标签（省略表示全部接受，[] 表示不接受标签）
+	ApplyPriority *bool    `json:"apply_priority"`                              // 是否接受建议的优先级（省略表示接受）
+}
+
+// AcceptSuggestionResponse 接受建议响应
+type AcceptSuggestionResponse struct {
+	Task       GetTaskResponse    `json:"task"`
+	Suggestion SuggestionResponse `json:"suggestion"`
+}
@@ -7,6 +7,7 @@ type CreateTaskRequest struct {
 	Priority    string   `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     string   `json:"due_date" binding:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	Tags        []string `json:"tags" binding:"omitempty,max=10,dive,max=50"`
+	ParentID    string   `json:"parent_id"` // 父任务 ID（可选，创建子任务）
 }
 
 // CreateTaskResponse 创建任务响应
@@ -59,6 +60,8 @@ type GetTaskResponse struct {
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
 	CompletedAt *string  `json:"completed_at"`
+	ParentID    *string  `json:"parent_id"`
+	HiddenUntil *string  `json:"hidden_until"` // 推迟到的时间（未推迟时为 null）
 }
 
 // ListTasksRequest 列出任务请求
@@ -71,9 +74,13 @@ type ListTasksRequest struct {
 	DueDateTo   string `form:"due_date_to" binding:"omitempty,datetime=2006-01-02"`
 	Keyword     string `form:"keyword" binding:"omitempty,max=100"`
 
+	// 是否包含推迟中的任务（默认不包含）
+	IncludeSnoozed bool `form:"include_snoozed" query:"include_snoozed"`
+
 	// 排序参数
-	SortBy    string `form:"sort_by" binding:"omitempty,oneof=created_at due_date priority"`
-	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	SortBy    string `form:"sort_by" query:"sort_by" binding:"omitempty,oneof=created_at due_date priority urgency"`
+	Sort      string `form:"sort" query:"sort" binding:"omitempty,oneof=created_at due_date priority urgency"` // sort_by 的简写
+	SortOrder string `form:"sort_order" query:"sort_order" binding:"omitempty,oneof=asc desc"`
 
 	// 分页参数
 	Page  int `form:"page" binding:"omitempty,min=1"`
@@ -89,6 +96,10 @@ type TaskItem struct {
 	DueDate   *string  `json:"due_date"`
 	Tags      []string `json:"tags"`
 	CreatedAt string   `json:"created_at"`
+	Urgency   *float64 `json:"urgency,omitempty"` // 紧急度（仅按紧急度排序或推荐时返回）
+	Score     *float64 `json:"score,omitempty"`   // 与查询的相似度（仅语义搜索返回）
+
+	HiddenUntil *string `json:"hidden_until,omitempty"` // 推迟到的时间（仅推迟中的任务返回）
 }
 
 // ListTasksResponse 列出任务响应
@@ -98,6 +109,63 @@ type ListTasksResponse struct {
 	Page       int        `json:"page"`
 	Limit      int        `json:"limit"`
 	HasMore    bool       `json:"has_more"`
+	Truncated  bool       `json:"truncated,omitempty"` // 按紧急度排序时任务过多，只有部分任务参与排序（total_count 仍为全部任务数）
+}
+
+// NextTasksRequest 获取推荐任务请求
+type NextTasksRequest struct {
+	Limit int `form:"limit" query:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// NextTasksResponse 获取推荐任务响应（按紧急度从高到低）
+type NextTasksResponse struct {
+	Tasks []TaskItem `json:"tasks"`
+}
+
+// SemanticSearchRequest 语义搜索请求
+type SemanticSearchRequest struct {
+	Q     string `form:"q" query:"q" binding:"required"`
+	Limit int    `form:"limit" query:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// SemanticSearchResponse 语义搜索响应（按相似度从高到低）
+type SemanticSearchResponse struct {
+	Tasks []TaskItem `json:"tasks"`
+}
+
+// UrgencyCoefficientsResponse 紧急度系数响应
+type UrgencyCoefficientsResponse struct {
+	PriorityHigh   float64            `json:"priority_high"`
+	PriorityMedium float64            `json:"priority_medium"`
+	PriorityLow    float64            `json:"priority_low"`
+	Due            float64            `json:"due"`
+	Age            float64            `json:"age"`
+	AgeMaxDays     int                `json:"age_max_days"`
+	Blocked        float64            `json:"blocked"`
+	Active         float64            `json:"active"`
+	Tags           float64            `json:"tags"`
+	TagBoosts      map[string]float64 `json:"tag_boosts"`
+	IsDefault      bool               `json:"is_default"`
+}
+
+// UpdateUrgencyCoefficientsRequest 更新紧急度系数请求（所有字段可选）
+type UpdateUrgencyCoefficientsRequest struct {
+	PriorityHigh   *float64           `json:"priority_high"`
+	PriorityMedium *float64           `json:"priority_medium"`
+	PriorityLow    *float64           `json:"priority_low"`
+	Due            *float64           `json:"due"`
+	Age            *float64           `json:"age"`
+	AgeMaxDays     *int               `json:"age_max_days"`
+	Blocked        *float64           `json:"blocked"`
+	Active         *float64           `json:"active"`
+	Tags           *float64           `json:"tags"`
+	TagBoosts      map[string]float64 `json:"tag_boosts"` // 提供时整体替换
+	Reset          bool               `json:"reset"`      // 恢复默认系数
+}
+
+// SnoozeTaskRequest 推迟任务请求
+type SnoozeTaskRequest struct {
+	Until string `json:"until" binding:"required,datetime=2006-01-02T15:04:05Z07:00"` // 推迟到的时间 (RFC3339)
 }
 
 // ErrorResponse 错误响应
@@ -0,0 +1,56 @@
+package dto
+
+// SubtaskTemplateItem 模板中的子任务定义
+type SubtaskTemplateItem struct {
+	TitlePattern string `json:"title_pattern" binding:"required,min=1,max=200"`
+	Description  string `json:"description" binding:"max=5000"`
+	Priority     string `json:"priority" binding:"omitempty,oneof=low medium high"`
+	DueOffset    string `json:"due_offset"` // Go duration 格式，如 "24h"、"90m"
+}
+
+// TemplateRequest 创建/更新模板请求
+type TemplateRequest struct {
+	Name         string                `json:"name" binding:"required,min=1,max=100"`
+	TitlePattern string                `json:"title_pattern" binding:"required,min=1,max=200"`
+	Description  string                `json:"description" binding:"max=5000"`
+	Priority     string                `json:"priority" binding:"omitempty,oneof=low medium high"`
+	Tags         []string              `json:"tags" binding:"omitempty,max=10,dive,max=50"`
+	DueOffset    string                `json:"due_offset"` // Go duration 格式，如 "72h"
+	Subtasks     []SubtaskTemplateItem `json:"subtasks" binding:"omitempty,max=20"`
+}
+
+// TemplateResponse 模板详情响应
+type TemplateResponse struct {
+	TemplateID   string                `json:"template_id"`
+	Name         string                `json:"name"`
+	TitlePattern string                `json:"title_pattern"`
+	Description  string                `json:"description"`
+	Priority     string                `json:"priority"`
+	Tags         []string              `json:"tags"`
+	DueOffset    *string               `json:"due_offset"`
+	Subtasks     []SubtaskTemplateItem `json:"subtasks"`
+	CreatedAt    string                `json:"created_at"`
+	UpdatedAt    string                `json:"updated_at"`
+}
+
+// ListTemplatesResponse 列出模板响应
+type ListTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+// DeleteTemplateResponse 删除模板响应
+type DeleteTemplateResponse struct {
+	Success bool `json:"success"`
+}
+
+// InstantiateTemplateRequest 实例化模板请求
+type InstantiateTemplateRequest struct {
+	// Variables 自定义模板变量，如 {"name": "Alice"} 用于替换 {{name}}
+	Variables map[string]string `json:"variables"`
+}
+
+// InstantiateTemplateResponse 实例化模板响应
+type InstantiateTemplateResponse struct {
+	Task     GetTaskResponse   `json:"task"`
+	Subtasks []GetTaskResponse `json:"subtasks"`
+}
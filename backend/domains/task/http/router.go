@@ -1,6 +1,7 @@
 package http
 
 import (
+	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/route"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/handlers"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
@@ -15,15 +16,38 @@ import (
 // - handlers.HandlerDependencies 包含 Domain Service
 // - 每个 Handler 是一个薄适配层（HTTP → Domain → HTTP）
 // - 所有任务路由都需要认证（使用 AuthMiddleware）
+// - generation 是调用模型的路由（AI 拆解子任务）额外使用的中间件，例如剩余额度响应头
 //
 // 路由列表：
 //   - POST   /api/tasks          - 创建任务（需要认证）
-//   - GET    /api/tasks          - 列出任务（需要认证）
+//   - GET    /api/tasks          - 列出任务（需要认证，支持 sort=urgency、include_snoozed）
+//   - GET    /api/tasks/next     - 按紧急度推荐下一步任务（需要认证）
+//   - GET    /api/tasks/semantic-search?q= - 按语义搜索任务（需要认证）
+//   - GET    /api/tasks/urgency-coefficients - 获取紧急度系数（需要认证）
+//   - PUT    /api/tasks/urgency-coefficients - 更新紧急度系数（需要认证）
 //   - GET    /api/tasks/:id      - 获取任务详情（需要认证）
 //   - PUT    /api/tasks/:id      - 更新任务（需要认证）
 //   - DELETE /api/tasks/:id      - 删除任务（需要认证）
 //   - POST   /api/tasks/:id/complete - 完成任务（需要认证）
-func RegisterRoutes(r *route.RouterGroup, deps *handlers.HandlerDependencies, authMiddleware *middleware.AuthMiddleware) {
+//   - POST   /api/tasks/:id/snooze   - 推迟任务（需要认证）
+//   - POST   /api/tasks/:id/unsnooze - 取消推迟（需要认证）
+//   - POST   /api/tasks/:id/breakdown        - AI 生成子任务建议（需要认证，不保存）
+//   - POST   /api/tasks/:id/breakdown/accept - 保存确认的子任务（需要认证）
+//   - GET    /api/tasks/:id/suggestion        - 获取自动生成的标签和优先级建议（需要认证）
+//   - POST   /api/tasks/:id/suggestion/accept - 接受建议（需要认证，可以只接受部分标签）
+//   - POST   /api/tasks/:id/suggestion/reject - 拒绝建议（需要认证）
+//   - POST   /api/templates      - 创建任务模板（需要认证）
+//   - GET    /api/templates      - 列出任务模板（需要认证）
+//   - GET    /api/templates/:id  - 获取模板详情（需要认证）
+//   - PUT    /api/templates/:id  - 更新模板（需要认证）
+//   - DELETE /api/templates/:id  - 删除模板（需要认证）
+//   - POST   /api/templates/:id/instantiate - 根据模板创建任务（需要认证）
+func RegisterRoutes(
+	r *route.RouterGroup,
+	deps *handlers.HandlerDependencies,
+	authMiddleware *middleware.AuthMiddleware,
+	generation ...app.HandlerFunc,
+) {
 	// 所有任务路由都需要认证
 	tasks := r.Group("/tasks", authMiddleware.Handle())
 	{
@@ -33,6 +57,16 @@ func RegisterRoutes(r *route.RouterGroup, deps *handlers.HandlerDependencies, au
 		// 列出任务
 		tasks.GET("", deps.ListTasksHandler)
 
+		// 推荐下一步任务（静态路由优先于 /:id）
+		tasks.GET("/next", deps.NextTasksHandler)
+
+		// 语义搜索（按向量相似度排序）
+		tasks.GET("/semantic-search", deps.SemanticSearchHandler)
+
+		// 紧急度系数
+		tasks.GET("/urgency-coefficients", deps.GetUrgencyCoefficientsHandler)
+		tasks.PUT("/urgency-coefficients", deps.UpdateUrgencyCoefficientsHandler)
+
 		// 获取任务详情
 		tasks.GET("/:id", deps.GetTaskHandler)
 
@@ -44,5 +78,47 @@ func RegisterRoutes(r *route.RouterGroup, deps *handlers.HandlerDependencies, au
 
 		// 完成任务
 		tasks.POST("/:id/complete", deps.CompleteTaskHandler)
+
+		// 推迟 / 取消推迟
+		tasks.POST("/:id/snooze", deps.SnoozeTaskHandler)
+		tasks.POST("/:id/unsnooze", deps.UnsnoozeTaskHandler)
+
+		// AI 拆解子任务：先生成建议，用户确认后保存
+		tasks.POST("/:id/breakdown", withHandler(generation, deps.BreakdownTaskHandler)...)
+		tasks.POST("/:id/breakdown/accept", deps.AcceptBreakdownHandler)
+
+		// 自动建议的标签和优先级：接受或拒绝后才修改任务
+		tasks.GET("/:id/suggestion", deps.GetSuggestionHandler)
+		tasks.POST("/:id/suggestion/accept", deps.AcceptSuggestionHandler)
+		tasks.POST("/:id/suggestion/reject", deps.RejectSuggestionHandler)
+	}
+
+	// 任务模板路由（同样需要认证）
+	templates := r.Group("/templates", authMiddleware.Handle())
+	{
+		// 创建模板
+		templates.POST("", deps.CreateTemplateHandler)
+
+		// 列出模板
+		templates.GET("", deps.ListTemplatesHandler)
+
+		// 获取模板详情
+		templates.GET("/:id", deps.GetTemplateHandler)
+
+		// 更新模板
+		templates.PUT("/:id", deps.UpdateTemplateHandler)
+
+		// 删除模板
+		templates.DELETE("/:id", deps.DeleteTemplateHandler)
+
+		// 根据模板创建任务
+		templates.POST("/:id/instantiate", deps.InstantiateTemplateHandler)
 	}
 }
+
+// withHandler 在中间件之后追加最终的 Handler
+func withHandler(middlewares []app.HandlerFunc, handler app.HandlerFunc) []app.HandlerFunc {
+	chain := make([]app.HandlerFunc, 0, len(middlewares)+1)
+	chain = append(chain, middlewares...)
+	return append(chain, handler)
+}
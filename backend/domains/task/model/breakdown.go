@@ -0,0 +1,32 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrNoSubtasks 接受拆解时至少需要一个子任务
+var ErrNoSubtasks = fmt.Errorf("NO_SUBTASKS: 至少需要一个子任务")
+
+// SubtaskProposal 任务拆解建议中的子任务（未保存）
+//
+// 由模型生成，用户可以修改或删除后再接受。
+type SubtaskProposal struct {
+	Title       string
+	Description string
+	Priority    Priority
+	DueInDays   *int       // 相对今天的天数（nil 表示不设置截止日期）
+	DueDate     *time.Time // 根据 DueInDays 计算（见 BreakdownDueDate）
+}
+
+// BreakdownDueDate 计算建议的截止日期
+//
+// 截止日期为今天之后第 days 天的当天结束（23:59:59，now 的时区），
+// 0 表示今天结束前。父任务设置了未过期的截止日期时，不晚于父任务的截止日期。
+func BreakdownDueDate(now time.Time, days int, parentDue *time.Time) time.Time {
+	due := time.Date(now.Year(), now.Month(), now.Day()+days, 23, 59, 59, 0, now.Location())
+	if parentDue != nil && parentDue.After(now) && parentDue.Before(due) {
+		return *parentDue
+	}
+	return due
+}
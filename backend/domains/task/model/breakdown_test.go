@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBreakdownDueDate 测试拆解建议的截止日期计算
+func TestBreakdownDueDate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+	// 当天结束，跨月正常进位
+	assert.Equal(t, time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC), BreakdownDueDate(now, 0, nil))
+	assert.Equal(t, time.Date(2026, 11, 2, 23, 59, 59, 0, time.UTC), BreakdownDueDate(now, 15, nil))
+
+	// 不晚于父任务的截止日期
+	parentDue := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, parentDue, BreakdownDueDate(now, 5, &parentDue))
+	assert.Equal(t, time.Date(2026, 10, 19, 23, 59, 59, 0, time.UTC), BreakdownDueDate(now, 1, &parentDue))
+
+	// 父任务已过期时不限制
+	overdue := now.Add(-time.Hour)
+	assert.Equal(t, time.Date(2026, 10, 21, 23, 59, 59, 0, time.UTC), BreakdownDueDate(now, 3, &overdue))
+}
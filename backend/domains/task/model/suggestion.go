@@ -0,0 +1,137 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuggestionStatus 建议状态
+type SuggestionStatus string
+
+const (
+	SuggestionPending  SuggestionStatus = "pending"  // 等待用户处理
+	SuggestionAccepted SuggestionStatus = "accepted" // 已接受（全部或部分）
+	SuggestionRejected SuggestionStatus = "rejected" // 已拒绝
+)
+
+// 建议相关错误
+var (
+	ErrSuggestionResolved = fmt.Errorf("SUGGESTION_ALREADY_RESOLVED: 建议已处理")
+	ErrTagNotSuggested    = fmt.Errorf("TAG_NOT_SUGGESTED: 只能接受建议中的标签")
+	ErrEmptySuggestion    = fmt.Errorf("EMPTY_SUGGESTION: 没有可接受的建议")
+)
+
+// TaskSuggestion 任务标签和优先级建议
+//
+// 创建任务后由模型生成，状态为 pending，不直接修改任务。
+// 用户接受（可只接受部分标签或不接受优先级）或拒绝后记录结果，用于统计建议的准确率。
+type TaskSuggestion struct {
+	ID               string
+	TaskID           string
+	UserID           string
+	Tags             []string // 建议的标签（来自用户已有的标签）
+	Priority         Priority // 建议的优先级
+	OriginalPriority Priority // 生成建议时任务的优先级
+	Model            string   // 生成建议的模型
+	Status           SuggestionStatus
+	AcceptedTags     []string // 用户接受的标签
+	PriorityAccepted bool     // 是否接受了建议的优先级
+	CreatedAt        time.Time
+	ResolvedAt       *time.Time
+}
+
+// NewTaskSuggestion 创建待处理的建议
+//
+// 建议的优先级与任务当前优先级相同且没有建议标签时返回 nil（没有需要用户处理的内容）。
+func NewTaskSuggestion(task *Task, tags []string, priority Priority, model string) (*TaskSuggestion, error) {
+	if !priority.IsValid() {
+		return nil, ErrInvalidPriority
+	}
+
+	// 去掉任务已有的标签和重复的标签
+	existing := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		existing[tag.Name] = true
+	}
+	suggested := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" || existing[tag] {
+			continue
+		}
+		existing[tag] = true
+		suggested = append(suggested, tag)
+	}
+
+	if len(suggested) == 0 && priority == task.Priority {
+		return nil, nil
+	}
+
+	return &TaskSuggestion{
+		ID:               uuid.New().String(),
+		TaskID:           task.ID,
+		UserID:           task.UserID,
+		Tags:             suggested,
+		Priority:         priority,
+		OriginalPriority: task.Priority,
+		Model:            model,
+		Status:           SuggestionPending,
+		AcceptedTags:     []string{},
+		CreatedAt:        time.Now(),
+	}, nil
+}
+
+// PrioritySuggested 建议的优先级是否与生成建议时的优先级不同
+func (s *TaskSuggestion) PrioritySuggested() bool {
+	return s.Priority != s.OriginalPriority
+}
+
+// Accept 接受建议
+//
+// tags 为接受的标签（必须是建议中的标签），applyPriority 表示是否接受建议的优先级。
+// 既没有接受标签也没有接受优先级时返回 EMPTY_SUGGESTION（应使用 Reject）。
+func (s *TaskSuggestion) Accept(tags []string, applyPriority bool, now time.Time) error {
+	if s.Status != SuggestionPending {
+		return ErrSuggestionResolved
+	}
+
+	suggested := make(map[string]bool, len(s.Tags))
+	for _, tag := range s.Tags {
+		suggested[tag] = true
+	}
+	accepted := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if !suggested[tag] {
+			return ErrTagNotSuggested
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			accepted = append(accepted, tag)
+		}
+	}
+
+	applyPriority = applyPriority && s.PrioritySuggested()
+	if len(accepted) == 0 && !applyPriority {
+		return ErrEmptySuggestion
+	}
+
+	s.Status = SuggestionAccepted
+	s.AcceptedTags = accepted
+	s.PriorityAccepted = applyPriority
+	s.ResolvedAt = &now
+	return nil
+}
+
+// Reject 拒绝建议
+func (s *TaskSuggestion) Reject(now time.Time) error {
+	if s.Status != SuggestionPending {
+		return ErrSuggestionResolved
+	}
+	s.Status = SuggestionRejected
+	s.AcceptedTags = []string{}
+	s.PriorityAccepted = false
+	s.ResolvedAt = &now
+	return nil
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTaskSuggestion 测试创建建议（去掉已有标签，没有内容时返回 nil）
+func TestNewTaskSuggestion(t *testing.T) {
+	task, err := NewTask("user-123", "写周报", "", PriorityMedium)
+	require.NoError(t, err)
+	require.NoError(t, task.AddTag(Tag{Name: "work"}))
+
+	s, err := NewTaskSuggestion(task, []string{"work", "writing", "writing", ""}, PriorityHigh, "mock-model")
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.Equal(t, []string{"writing"}, s.Tags)
+	assert.Equal(t, PriorityMedium, s.OriginalPriority)
+	assert.Equal(t, SuggestionPending, s.Status)
+	assert.True(t, s.PrioritySuggested())
+
+	// 只有已有标签且优先级不变：没有需要处理的内容
+	s, err = NewTaskSuggestion(task, []string{"work"}, PriorityMedium, "mock-model")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	_, err = NewTaskSuggestion(task, nil, Priority("urgent"), "mock-model")
+	assert.ErrorIs(t, err, ErrInvalidPriority)
+}
+
+// TestTaskSuggestion_Accept 测试接受建议
+func TestTaskSuggestion_Accept(t *testing.T) {
+	newSuggestion := func() *TaskSuggestion {
+		return &TaskSuggestion{
+			Tags:             []string{"work", "writing"},
+			Priority:         PriorityHigh,
+			OriginalPriority: PriorityMedium,
+			Status:           SuggestionPending,
+		}
+	}
+	now := time.Now()
+
+	t.Run("部分接受", func(t *testing.T) {
+		s := newSuggestion()
+		require.NoError(t, s.Accept([]string{"writing", "writing"}, false, now))
+		assert.Equal(t, SuggestionAccepted, s.Status)
+		assert.Equal(t, []string{"writing"}, s.AcceptedTags)
+		assert.False(t, s.PriorityAccepted)
+		assert.Equal(t, &now, s.ResolvedAt)
+	})
+
+	t.Run("不能接受建议之外的标签", func(t *testing.T) {
+		assert.ErrorIs(t, newSuggestion().Accept([]string{"home"}, true, now), ErrTagNotSuggested)
+	})
+
+	t.Run("什么都不接受", func(t *testing.T) {
+		s := newSuggestion()
+		s.Priority = PriorityMedium
+		assert.ErrorIs(t, s.Accept(nil, true, now), ErrEmptySuggestion)
+	})
+
+	t.Run("已处理", func(t *testing.T) {
+		s := newSuggestion()
+		require.NoError(t, s.Reject(now))
+		assert.ErrorIs(t, s.Accept([]string{"work"}, true, now), ErrSuggestionResolved)
+		assert.ErrorIs(t, s.Reject(now), ErrSuggestionResolved)
+	})
+}
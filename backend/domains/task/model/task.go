@@ -63,6 +63,8 @@ type Task struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	CompletedAt *time.Time // 完成时间
+	ParentID    *string    // 父任务 ID（子任务时非空）
+	HiddenUntil *time.Time // 推迟（snooze）到该时间前不在默认列表中显示
 }
 
 // 领域错误定义
@@ -74,6 +76,9 @@ var (
 	ErrTooManyTags          = fmt.Errorf("TOO_MANY_TAGS: 标签过多，最多 10 个")
 	ErrDuplicateTag         = fmt.Errorf("DUPLICATE_TAG: 标签重复")
 	ErrInvalidPriority      = fmt.Errorf("INVALID_PRIORITY: 优先级无效")
+	ErrInvalidParentTask    = fmt.Errorf("INVALID_PARENT_TASK: 父任务无效")
+	ErrInvalidSnoozeTime    = fmt.Errorf("INVALID_SNOOZE_TIME: 推迟时间必须晚于当前时间")
+	ErrTaskNotSnoozed       = fmt.Errorf("TASK_NOT_SNOOZED: 任务未被推迟")
 )
 
 // NewTask 创建一个新的任务
@@ -152,6 +157,7 @@ func (t *Task) Complete() error {
 	t.Status = StatusCompleted
 	now := time.Now()
 	t.CompletedAt = &now
+	t.HiddenUntil = nil // 已完成的任务不再需要重新出现
 	t.UpdatedAt = now
 	return nil
 }
@@ -186,3 +192,68 @@ func (t *Task) RemoveTag(tagName string) {
 	t.Tags = newTags
 	t.UpdatedAt = time.Now()
 }
+
+// SetParent 将任务设置为 parent 的子任务
+//
+// 规则：
+//   - 父任务必须属于同一用户
+//   - 父任务不能是已完成的任务
+//   - 只支持一层子任务（子任务不能再作为父任务）
+func (t *Task) SetParent(parent *Task) error {
+	if parent == nil || parent.ID == t.ID || parent.UserID != t.UserID {
+		return ErrInvalidParentTask
+	}
+	if err := parent.CanBeParent(); err != nil {
+		return err
+	}
+	parentID := parent.ID
+	t.ParentID = &parentID
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// CanBeParent 判断任务能否作为父任务（未完成，且本身不是子任务）
+func (t *Task) CanBeParent() error {
+	if t.Status == StatusCompleted || t.IsSubtask() {
+		return ErrInvalidParentTask
+	}
+	return nil
+}
+
+// IsSubtask 判断任务是否是子任务
+func (t *Task) IsSubtask() bool {
+	return t.ParentID != nil && *t.ParentID != ""
+}
+
+// Snooze 推迟任务到 until（不修改截止日期）
+//
+// 规则：
+//   - 已完成的任务不能推迟
+//   - until 必须晚于 now
+//   - 已推迟的任务可以再次推迟，以最新的时间为准
+func (t *Task) Snooze(until, now time.Time) error {
+	if t.Status == StatusCompleted {
+		return ErrTaskAlreadyCompleted
+	}
+	if !until.After(now) {
+		return ErrInvalidSnoozeTime
+	}
+	t.HiddenUntil = &until
+	t.UpdatedAt = now
+	return nil
+}
+
+// Unsnooze 取消推迟，任务立即重新出现在默认列表中
+func (t *Task) Unsnooze(now time.Time) error {
+	if !t.IsSnoozed(now) {
+		return ErrTaskNotSnoozed
+	}
+	t.HiddenUntil = nil
+	t.UpdatedAt = now
+	return nil
+}
+
+// IsSnoozed 判断任务在 now 时刻是否处于推迟状态
+func (t *Task) IsSnoozed(now time.Time) bool {
+	return t.HiddenUntil != nil && t.HiddenUntil.After(now)
+}
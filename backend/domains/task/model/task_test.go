@@ -530,3 +530,73 @@ func TestTask_UpdatePreservesStatus(t *testing.T) {
 	assert.Equal(t, StatusInProgress, task.Status, "状态应该保持为 in_progress")
 	assert.Equal(t, "Updated", task.Title)
 }
+
+// TestTask_Snooze 测试推迟任务
+func TestTask_Snooze(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) // 周五
+	monday := now.Add(72 * time.Hour)
+
+	t.Run("推迟到未来时间", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+		dueDate := now.Add(24 * time.Hour)
+		task.DueDate = &dueDate
+
+		err := task.Snooze(monday, now)
+
+		require.NoError(t, err)
+		require.NotNil(t, task.HiddenUntil)
+		assert.Equal(t, monday, *task.HiddenUntil)
+		assert.Equal(t, dueDate, *task.DueDate, "截止日期应该保持不变")
+		assert.True(t, task.IsSnoozed(now))
+		assert.False(t, task.IsSnoozed(monday), "到期后不再处于推迟状态")
+	})
+
+	t.Run("推迟时间不能早于当前时间", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+
+		assert.Equal(t, ErrInvalidSnoozeTime, task.Snooze(now, now))
+		assert.Equal(t, ErrInvalidSnoozeTime, task.Snooze(now.Add(-time.Hour), now))
+		assert.Nil(t, task.HiddenUntil)
+	})
+
+	t.Run("已完成的任务不能推迟", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+		require.NoError(t, task.Complete())
+
+		assert.Equal(t, ErrTaskAlreadyCompleted, task.Snooze(monday, now))
+	})
+
+	t.Run("完成任务时清除推迟", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+		require.NoError(t, task.Snooze(monday, now))
+
+		require.NoError(t, task.Complete())
+		assert.Nil(t, task.HiddenUntil)
+	})
+}
+
+// TestTask_Unsnooze 测试取消推迟
+func TestTask_Unsnooze(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	t.Run("取消推迟", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+		require.NoError(t, task.Snooze(now.Add(time.Hour), now))
+
+		err := task.Unsnooze(now)
+
+		require.NoError(t, err)
+		assert.Nil(t, task.HiddenUntil)
+		assert.False(t, task.IsSnoozed(now))
+	})
+
+	t.Run("未推迟的任务返回错误", func(t *testing.T) {
+		task, _ := NewTask("test-user-id", "Test", "", PriorityMedium)
+		assert.Equal(t, ErrTaskNotSnoozed, task.Unsnooze(now))
+
+		// 推迟已到期也视为未推迟
+		past := now.Add(-time.Minute)
+		task.HiddenUntil = &past
+		assert.Equal(t, ErrTaskNotSnoozed, task.Unsnooze(now))
+	})
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubtaskTemplate 模板中的子任务定义
+type SubtaskTemplate struct {
+	TitlePattern string         // 子任务标题模式（支持变量）
+	Description  string         // 子任务描述（支持变量）
+	Priority     Priority       // 空值表示继承模板优先级
+	DueOffset    *time.Duration // 相对实例化时间的截止偏移（可选）
+}
+
+// TaskTemplate 任务模板聚合根
+//
+// 模板属于单个用户，实例化时通过变量替换生成具体任务：
+//   - 内置变量：{{date}}（实例化日期，YYYY-MM-DD）、{{time}}（HH:MM）
+//   - 自定义变量：由实例化请求提供，如 {{name}}
+type TaskTemplate struct {
+	ID           string
+	UserID       string // 所属用户 ID
+	Name         string // 模板名称
+	TitlePattern string // 任务标题模式
+	Description  string // 任务描述（支持变量）
+	Priority     Priority
+	Tags         []string
+	DueOffset    *time.Duration // 相对实例化时间的截止偏移（可选）
+	Subtasks     []SubtaskTemplate
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// 模板领域错误定义
+var (
+	ErrTemplateNameEmpty       = fmt.Errorf("TEMPLATE_NAME_EMPTY: 模板名称不能为空")
+	ErrTemplateTitleEmpty      = fmt.Errorf("TEMPLATE_TITLE_EMPTY: 模板标题不能为空")
+	ErrTooManySubtasks         = fmt.Errorf("TOO_MANY_SUBTASKS: 子任务过多，最多 20 个")
+	ErrInvalidDueOffset        = fmt.Errorf("INVALID_DUE_OFFSET: 截止偏移必须大于 0")
+	ErrTemplateVariableMissing = fmt.Errorf("TEMPLATE_VARIABLE_MISSING: 缺少模板变量")
+)
+
+// MaxTemplateSubtasks 单个模板最多包含的子任务数
+const MaxTemplateSubtasks = 20
+
+// templateVarPattern 匹配 {{name}} 形式的变量（允许两侧空格）
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// NewTaskTemplate 创建一个新的任务模板
+func NewTaskTemplate(userID, name, titlePattern, description string, priority Priority) (*TaskTemplate, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	now := time.Now()
+	tpl := &TaskTemplate{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Tags:      []string{},
+		Subtasks:  []SubtaskTemplate{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := tpl.Update(name, titlePattern, description, priority); err != nil {
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// Update 更新模板基本信息
+func (t *TaskTemplate) Update(name, titlePattern, description string, priority Priority) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrTemplateNameEmpty
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("TEMPLATE_NAME_TOO_LONG: 模板名称过长，最大 100 字符")
+	}
+	if strings.TrimSpace(titlePattern) == "" {
+		return ErrTemplateTitleEmpty
+	}
+	if len(titlePattern) > 200 {
+		return fmt.Errorf("TASK_TITLE_TOO_LONG: 标题过长，最大 200 字符")
+	}
+	if len(description) > 5000 {
+		return fmt.Errorf("TASK_DESCRIPTION_TOO_LONG: 描述过长，最大 5000 字符")
+	}
+	if priority == "" {
+		priority = PriorityMedium
+	}
+	if !priority.IsValid() {
+		return ErrInvalidPriority
+	}
+
+	t.Name = name
+	t.TitlePattern = titlePattern
+	t.Description = description
+	t.Priority = priority
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetTags 设置模板标签（规则与任务标签一致）
+func (t *TaskTemplate) SetTags(tags []string) error {
+	if len(tags) > 10 {
+		return ErrTooManyTags
+	}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if tag == "" {
+			return ErrTagNameEmpty
+		}
+		if seen[tag] {
+			return ErrDuplicateTag
+		}
+		seen[tag] = true
+	}
+
+	t.Tags = append([]string{}, tags...)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetDueOffset 设置截止偏移（nil 表示不设置截止日期）
+func (t *TaskTemplate) SetDueOffset(offset *time.Duration) error {
+	if offset != nil && *offset <= 0 {
+		return ErrInvalidDueOffset
+	}
+	t.DueOffset = offset
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetSubtasks 设置子任务列表
+func (t *TaskTemplate) SetSubtasks(subtasks []SubtaskTemplate) error {
+	if len(subtasks) > MaxTemplateSubtasks {
+		return ErrTooManySubtasks
+	}
+	for _, sub := range subtasks {
+		if strings.TrimSpace(sub.TitlePattern) == "" {
+			return ErrTemplateTitleEmpty
+		}
+		if len(sub.TitlePattern) > 200 {
+			return fmt.Errorf("TASK_TITLE_TOO_LONG: 标题过长，最大 200 字符")
+		}
+		if sub.Priority != "" && !sub.Priority.IsValid() {
+			return ErrInvalidPriority
+		}
+		if sub.DueOffset != nil && *sub.DueOffset <= 0 {
+			return ErrInvalidDueOffset
+		}
+	}
+
+	t.Subtasks = append([]SubtaskTemplate{}, subtasks...)
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// TemplateVariables 返回实例化时可用的变量集合
+//
+// 内置变量基于实例化时间 now，自定义变量可覆盖内置变量。
+func TemplateVariables(now time.Time, custom map[string]string) map[string]string {
+	vars := map[string]string{
+		"date": now.Format("2006-01-02"),
+		"time": now.Format("15:04"),
+	}
+	for k, v := range custom {
+		vars[k] = v
+	}
+	return vars
+}
+
+// RenderPattern 使用变量替换模式中的 {{name}} 占位符
+//
+// 任意占位符没有对应变量时返回 ErrTemplateVariableMissing，
+// 避免生成包含未替换占位符的任务。
+func RenderPattern(pattern string, vars map[string]string) (string, error) {
+	var missing []string
+	rendered := templateVarPattern.ReplaceAllStringFunc(pattern, func(match string) string {
+		name := templateVarPattern.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrTemplateVariableMissing, strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewTaskTemplate 测试模板创建
+func TestNewTaskTemplate(t *testing.T) {
+	t.Run("创建有效模板（默认优先级）", func(t *testing.T) {
+		tpl, err := NewTaskTemplate("user-1", "Weekly Review", "Review {{date}}", "", "")
+		require.NoError(t, err)
+		assert.NotEmpty(t, tpl.ID)
+		assert.Equal(t, PriorityMedium, tpl.Priority)
+		assert.Empty(t, tpl.Tags)
+		assert.Empty(t, tpl.Subtasks)
+	})
+
+	t.Run("名称为空", func(t *testing.T) {
+		_, err := NewTaskTemplate("user-1", " ", "Title", "", PriorityLow)
+		assert.ErrorIs(t, err, ErrTemplateNameEmpty)
+	})
+
+	t.Run("标题模式为空", func(t *testing.T) {
+		_, err := NewTaskTemplate("user-1", "Name", "", "", PriorityLow)
+		assert.ErrorIs(t, err, ErrTemplateTitleEmpty)
+	})
+
+	t.Run("优先级无效", func(t *testing.T) {
+		_, err := NewTaskTemplate("user-1", "Name", "Title", "", Priority("urgent"))
+		assert.ErrorIs(t, err, ErrInvalidPriority)
+	})
+}
+
+// TestTaskTemplate_SetDetails 测试标签、截止偏移和子任务设置
+func TestTaskTemplate_SetDetails(t *testing.T) {
+	tpl, err := NewTaskTemplate("user-1", "Onboarding", "Onboard {{name}}", "", PriorityHigh)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, tpl.SetTags([]string{"a", "a"}), ErrDuplicateTag)
+	assert.ErrorIs(t, tpl.SetTags(make([]string, 11)), ErrTooManyTags)
+	assert.NoError(t, tpl.SetTags([]string{"hr", "onboarding"}))
+
+	negative := -time.Hour
+	assert.ErrorIs(t, tpl.SetDueOffset(&negative), ErrInvalidDueOffset)
+	week := 7 * 24 * time.Hour
+	assert.NoError(t, tpl.SetDueOffset(&week))
+
+	tooMany := make([]SubtaskTemplate, MaxTemplateSubtasks+1)
+	for i := range tooMany {
+		tooMany[i] = SubtaskTemplate{TitlePattern: "sub"}
+	}
+	assert.ErrorIs(t, tpl.SetSubtasks(tooMany), ErrTooManySubtasks)
+	assert.ErrorIs(t, tpl.SetSubtasks([]SubtaskTemplate{{TitlePattern: ""}}), ErrTemplateTitleEmpty)
+	assert.NoError(t, tpl.SetSubtasks([]SubtaskTemplate{{TitlePattern: "Create account for {{name}}"}}))
+	assert.Len(t, tpl.Subtasks, 1)
+}
+
+// TestRenderPattern 测试变量替换
+func TestRenderPattern(t *testing.T) {
+	now := time.Date(2026, 3, 9, 14, 30, 0, 0, time.UTC)
+	vars := TemplateVariables(now, map[string]string{"name": "Alice"})
+
+	t.Run("替换内置和自定义变量", func(t *testing.T) {
+		out, err := RenderPattern("Onboard {{name}} on {{ date }} at {{time}}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "Onboard Alice on 2026-03-09 at 14:30", out)
+	})
+
+	t.Run("自定义变量覆盖内置变量", func(t *testing.T) {
+		out, err := RenderPattern("{{date}}", TemplateVariables(now, map[string]string{"date": "tomorrow"}))
+		require.NoError(t, err)
+		assert.Equal(t, "tomorrow", out)
+	})
+
+	t.Run("缺少变量", func(t *testing.T) {
+		_, err := RenderPattern("Hello {{name}} from {{team}}", vars)
+		require.ErrorIs(t, err, ErrTemplateVariableMissing)
+		assert.True(t, strings.HasSuffix(err.Error(), "team"))
+	})
+
+	t.Run("没有占位符", func(t *testing.T) {
+		out, err := RenderPattern("Plain {title}", vars)
+		require.NoError(t, err)
+		assert.Equal(t, "Plain {title}", out)
+	})
+}
+
+// TestTask_SetParent 测试设置父任务
+func TestTask_SetParent(t *testing.T) {
+	parent, _ := NewTask("user-1", "Parent", "", PriorityMedium)
+	child, _ := NewTask("user-1", "Child", "", PriorityMedium)
+	other, _ := NewTask("user-2", "Other", "", PriorityMedium)
+
+	assert.ErrorIs(t, child.SetParent(other), ErrInvalidParentTask)
+	assert.ErrorIs(t, child.SetParent(child), ErrInvalidParentTask)
+
+	require.NoError(t, child.SetParent(parent))
+	assert.True(t, child.IsSubtask())
+	assert.Equal(t, parent.ID, *child.ParentID)
+
+	// 只支持一层子任务
+	grandchild, _ := NewTask("user-1", "Grandchild", "", PriorityMedium)
+	assert.ErrorIs(t, grandchild.SetParent(child), ErrInvalidParentTask)
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// UrgencyCoefficients 紧急度系数（值对象）
+//
+// 紧急度 = Σ 系数 × 因子，参考 Taskwarrior 的 urgency 计算方式：
+//   - 优先级：按 high/medium/low 直接取对应系数
+//   - 截止日期：逾期 7 天及以上为 1.0，14 天以后到期为 0.2，之间线性变化；无截止日期为 0
+//   - 年龄：创建天数 / AgeMaxDays，最大 1.0
+//   - 阻塞：存在未完成的子任务时为 1.0（系数通常为负数）
+//   - 进行中：状态为 in_progress 时为 1.0
+//   - 标签：0 个为 0，1 个为 0.8，2 个为 0.9，3 个及以上为 1.0
+//   - 标签加权：任务每包含一个 TagBoosts 中的标签，累加对应系数
+//
+// 每个用户可以保存自己的系数，未保存时使用 DefaultUrgencyCoefficients。
+type UrgencyCoefficients struct {
+	PriorityHigh   float64
+	PriorityMedium float64
+	PriorityLow    float64
+	Due            float64
+	Age            float64
+	AgeMaxDays     int
+	Blocked        float64
+	Active         float64
+	Tags           float64
+	TagBoosts      map[string]float64
+}
+
+// 紧急度相关错误定义
+var (
+	ErrInvalidUrgencyCoefficient = fmt.Errorf("INVALID_URGENCY_COEFFICIENT: 紧急度系数无效")
+)
+
+const (
+	// maxUrgencyCoefficient 单个系数的绝对值上限
+	maxUrgencyCoefficient = 100.0
+	// maxTagBoosts 标签加权最多配置的标签数
+	maxTagBoosts = 20
+)
+
+// DefaultUrgencyCoefficients 返回默认紧急度系数（与 Taskwarrior 默认值一致）
+func DefaultUrgencyCoefficients() UrgencyCoefficients {
+	return UrgencyCoefficients{
+		PriorityHigh:   6.0,
+		PriorityMedium: 3.9,
+		PriorityLow:    1.8,
+		Due:            12.0,
+		Age:            2.0,
+		AgeMaxDays:     365,
+		Blocked:        -5.0,
+		Active:         4.0,
+		Tags:           1.0,
+		TagBoosts:      map[string]float64{"next": 15.0},
+	}
+}
+
+// Validate 验证系数是否在允许范围内
+func (c UrgencyCoefficients) Validate() error {
+	values := []float64{
+		c.PriorityHigh, c.PriorityMedium, c.PriorityLow,
+		c.Due, c.Age, c.Blocked, c.Active, c.Tags,
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.Abs(v) > maxUrgencyCoefficient {
+			return fmt.Errorf("%w: 系数绝对值不能超过 %.0f", ErrInvalidUrgencyCoefficient, maxUrgencyCoefficient)
+		}
+	}
+	if c.AgeMaxDays < 1 || c.AgeMaxDays > 3650 {
+		return fmt.Errorf("%w: age_max_days 必须在 1 到 3650 之间", ErrInvalidUrgencyCoefficient)
+	}
+	if len(c.TagBoosts) > maxTagBoosts {
+		return fmt.Errorf("%w: 标签加权最多 %d 个", ErrInvalidUrgencyCoefficient, maxTagBoosts)
+	}
+	for tag, v := range c.TagBoosts {
+		if tag == "" {
+			return ErrTagNameEmpty
+		}
+		if math.IsNaN(v) || math.Abs(v) > maxUrgencyCoefficient {
+			return fmt.Errorf("%w: 标签 %s 的系数绝对值不能超过 %.0f", ErrInvalidUrgencyCoefficient, tag, maxUrgencyCoefficient)
+		}
+	}
+	return nil
+}
+
+// Urgency 计算任务的紧急度
+//
+// 参数：
+//   - c: 紧急度系数
+//   - blocked: 任务是否被阻塞（存在未完成的子任务）
+//   - now: 计算时间
+//
+// 已完成的任务紧急度恒为 0。
+func (t *Task) Urgency(c UrgencyCoefficients, blocked bool, now time.Time) float64 {
+	if t.Status == StatusCompleted {
+		return 0
+	}
+
+	var score float64
+
+	switch t.Priority {
+	case PriorityHigh:
+		score += c.PriorityHigh
+	case PriorityMedium:
+		score += c.PriorityMedium
+	case PriorityLow:
+		score += c.PriorityLow
+	}
+
+	score += c.Due * dueFactor(t.DueDate, now)
+	score += c.Age * ageFactor(t.CreatedAt, now, c.AgeMaxDays)
+
+	if blocked {
+		score += c.Blocked
+	}
+	if t.Status == StatusInProgress {
+		score += c.Active
+	}
+
+	score += c.Tags * tagsFactor(len(t.Tags))
+	for _, tag := range t.Tags {
+		score += c.TagBoosts[tag.Name]
+	}
+
+	return score
+}
+
+// dueFactor 截止日期因子（0.2 ~ 1.0，无截止日期为 0）
+func dueFactor(dueDate *time.Time, now time.Time) float64 {
+	if dueDate == nil {
+		return 0
+	}
+
+	daysOverdue := now.Sub(*dueDate).Hours() / 24
+	switch {
+	case daysOverdue >= 7:
+		return 1.0
+	case daysOverdue >= -14:
+		return ((daysOverdue+14)*0.8)/21 + 0.2
+	default:
+		return 0.2
+	}
+}
+
+// ageFactor 年龄因子（0 ~ 1.0）
+func ageFactor(createdAt, now time.Time, maxDays int) float64 {
+	if maxDays <= 0 || createdAt.IsZero() {
+		return 0
+	}
+
+	ageDays := now.Sub(createdAt).Hours() / 24
+	if ageDays <= 0 {
+		return 0
+	}
+	return math.Min(ageDays/float64(maxDays), 1.0)
+}
+
+// tagsFactor 标签数量因子
+func tagsFactor(count int) float64 {
+	switch {
+	case count <= 0:
+		return 0
+	case count == 1:
+		return 0.8
+	case count == 2:
+		return 0.9
+	default:
+		return 1.0
+	}
+}
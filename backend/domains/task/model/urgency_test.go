@@ -0,0 +1,130 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTask_Urgency 测试紧急度计算
+func TestTask_Urgency(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := DefaultUrgencyCoefficients()
+
+	newTask := func(priority Priority) *Task {
+		task, _ := NewTask("user-1", "Task", "", priority)
+		task.CreatedAt = now
+		return task
+	}
+	daysFromNow := func(days float64) *time.Time {
+		d := now.Add(time.Duration(days * 24 * float64(time.Hour)))
+		return &d
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(*Task)
+		blocked bool
+		want    float64
+	}{
+		{
+			name: "只有优先级",
+			want: c.PriorityMedium,
+		},
+		{
+			name:  "逾期 7 天以上取满分",
+			setup: func(task *Task) { task.DueDate = daysFromNow(-10) },
+			want:  c.PriorityMedium + c.Due,
+		},
+		{
+			name:  "14 天以后到期取 0.2",
+			setup: func(task *Task) { task.DueDate = daysFromNow(30) },
+			want:  c.PriorityMedium + c.Due*0.2,
+		},
+		{
+			name:  "今天到期线性插值",
+			setup: func(task *Task) { task.DueDate = daysFromNow(0) },
+			want:  c.PriorityMedium + c.Due*(14*0.8/21+0.2),
+		},
+		{
+			name:  "年龄达到上限",
+			setup: func(task *Task) { task.CreatedAt = now.AddDate(-2, 0, 0) },
+			want:  c.PriorityMedium + c.Age,
+		},
+		{
+			name:    "被阻塞",
+			blocked: true,
+			want:    c.PriorityMedium + c.Blocked,
+		},
+		{
+			name:  "进行中",
+			setup: func(task *Task) { task.Status = StatusInProgress },
+			want:  c.PriorityMedium + c.Active,
+		},
+		{
+			name: "标签和标签加权",
+			setup: func(task *Task) {
+				task.Tags = []Tag{{Name: "next"}, {Name: "work"}}
+			},
+			want: c.PriorityMedium + c.Tags*0.9 + c.TagBoosts["next"],
+		},
+		{
+			name:  "已完成任务为 0",
+			setup: func(task *Task) { _ = task.Complete() },
+			want:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := newTask(PriorityMedium)
+			if tt.setup != nil {
+				tt.setup(task)
+			}
+			got := task.Urgency(c, tt.blocked, now)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+// TestTask_Urgency_Ordering 测试不同任务的相对紧急度
+func TestTask_Urgency_Ordering(t *testing.T) {
+	now := time.Now()
+	c := DefaultUrgencyCoefficients()
+
+	overdueLow, _ := NewTask("user-1", "overdue low", "", PriorityLow)
+	overdue := now.AddDate(0, 0, -3)
+	overdueLow.DueDate = &overdue
+
+	high, _ := NewTask("user-1", "high", "", PriorityHigh)
+
+	// 逾期任务比没有截止日期的高优先级任务更紧急
+	assert.Greater(t, overdueLow.Urgency(c, false, now), high.Urgency(c, false, now))
+
+	// 自定义系数：忽略截止日期后顺序反转
+	c.Due = 0
+	assert.Less(t, overdueLow.Urgency(c, false, now), high.Urgency(c, false, now))
+}
+
+// TestUrgencyCoefficients_Validate 测试系数验证
+func TestUrgencyCoefficients_Validate(t *testing.T) {
+	assert.NoError(t, DefaultUrgencyCoefficients().Validate())
+
+	c := DefaultUrgencyCoefficients()
+	c.Due = 101
+	assert.ErrorIs(t, c.Validate(), ErrInvalidUrgencyCoefficient)
+
+	c = DefaultUrgencyCoefficients()
+	c.Age = math.NaN()
+	assert.ErrorIs(t, c.Validate(), ErrInvalidUrgencyCoefficient)
+
+	c = DefaultUrgencyCoefficients()
+	c.AgeMaxDays = 0
+	assert.ErrorIs(t, c.Validate(), ErrInvalidUrgencyCoefficient)
+
+	c = DefaultUrgencyCoefficients()
+	c.TagBoosts = map[string]float64{"": 1}
+	assert.ErrorIs(t, c.Validate(), ErrTagNameEmpty)
+}
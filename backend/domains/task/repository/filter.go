@@ -14,16 +14,30 @@ type TaskFilter struct {
 	DueDateFrom *string
 	DueDateTo   *string
 	Keyword     *string
+	ParentID    *string  // 父任务 ID（列出子任务）
+	IDs         []string // 只返回这些任务（nil 表示不限制，语义搜索按向量结果加载任务）
+
+	// IncludeSnoozed 是否包含推迟中的任务（默认 false：排除 hidden_until 在未来的任务）
+	IncludeSnoozed bool
+
+	// ExcludeCompleted 排除已完成的任务（Status 为空时生效）
+	ExcludeCompleted bool
 
 	// 排序
-	SortBy    string // created_at, due_date, priority
+	SortBy    string // created_at, due_date, priority, urgency（见 SortByUrgencyCandidates）
 	SortOrder string // asc, desc
 
 	// 分页
 	Page  int
-	Limit int
+	Limit int // <= 0 表示不分页
 }
 
+// SortByUrgencyCandidates 按紧急度的主要因子预排序，用于选取紧急度计算的候选任务
+//
+// 依次按：未完成在前、截止日期（逾期和临近的在前，无截止日期的在后）、优先级（高在前）、
+// 创建时间（早的在前）。SortOrder 不生效；精确的紧急度由 Service 层在内存中计算和排序。
+const SortByUrgencyCandidates = "urgency"
+
 // NewTaskFilter 创建默认筛选条件
 func NewTaskFilter() *TaskFilter {
 	return &TaskFilter{
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
 )
@@ -28,4 +29,62 @@ type TaskRepository interface {
 
 	// Exists 检查任务是否存在
 	Exists(ctx context.Context, taskID string) (bool, error)
+
+	// CountOpenSubtasks 统计每个父任务未完成的子任务数量
+	// 返回 父任务 ID → 未完成子任务数，没有未完成子任务的父任务不出现在结果中
+	CountOpenSubtasks(ctx context.Context, parentIDs []string) (map[string]int, error)
+
+	// FindExpiredSnoozes 查找推迟已到期（hidden_until <= now）但尚未清除的任务
+	// 按 hidden_until 升序，最多返回 limit 个（<= 0 表示不限制）
+	FindExpiredSnoozes(ctx context.Context, now time.Time, limit int) ([]*model.Task, error)
+
+	// ClearSnooze 在 hidden_until 仍等于 hiddenUntil 时将其清空
+	// 返回是否清除成功（false 表示已被重新推迟、取消推迟或由其他实例处理）
+	ClearSnooze(ctx context.Context, taskID string, hiddenUntil time.Time) (bool, error)
+
+	// ListTagNames 列出用户使用过的标签名称
+	// 按使用次数降序（次数相同时按名称），最多返回 limit 个（<= 0 表示不限制）
+	ListTagNames(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
+// TemplateRepository 定义任务模板仓储接口
+//
+// 提供对 TaskTemplate 聚合根的持久化操作
+type TemplateRepository interface {
+	// Create 保存一个新的模板
+	Create(ctx context.Context, tpl *model.TaskTemplate) error
+
+	// FindByID 根据 ID 查找模板
+	FindByID(ctx context.Context, templateID string) (*model.TaskTemplate, error)
+
+	// Update 更新一个现有模板
+	Update(ctx context.Context, tpl *model.TaskTemplate) error
+
+	// Delete 根据 ID 删除模板
+	Delete(ctx context.Context, templateID string) error
+
+	// ListByUser 列出用户的所有模板（按创建时间倒序）
+	ListByUser(ctx context.Context, userID string) ([]*model.TaskTemplate, error)
+}
+
+// UrgencySettingsRepository 定义用户紧急度系数仓储接口
+type UrgencySettingsRepository interface {
+	// Get 获取用户的紧急度系数
+	// 用户未保存过系数时返回 (nil, nil)
+	Get(ctx context.Context, userID string) (*model.UrgencyCoefficients, error)
+
+	// Save 保存（覆盖）用户的紧急度系数
+	Save(ctx context.Context, userID string, coefficients model.UrgencyCoefficients) error
+}
+
+// SuggestionRepository 定义任务建议仓储接口
+type SuggestionRepository interface {
+	// Create 保存建议（任务已有建议时不覆盖，返回是否保存）
+	Create(ctx context.Context, suggestion *model.TaskSuggestion) (bool, error)
+
+	// FindByTaskID 查找任务的建议
+	FindByTaskID(ctx context.Context, taskID string) (*model.TaskSuggestion, error)
+
+	// Resolve 保存用户的处理结果（状态、接受的标签和优先级）
+	Resolve(ctx context.Context, suggestion *model.TaskSuggestion) error
 }
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// ErrSuggestionNotFound 任务没有建议（未开启自动建议、还在生成或模型没有给出建议）
+var ErrSuggestionNotFound = errors.New("SUGGESTION_NOT_FOUND: 任务没有建议")
+
+// SuggestionRepositoryImpl 任务建议仓储实现
+//
+// 每个任务最多一条建议（task_id 唯一），标签以 JSON 数组存储。
+type SuggestionRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewSuggestionRepository 创建任务建议仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewSuggestionRepository(db *sql.DB, dbType string) *SuggestionRepositoryImpl {
+	var dialect goqu.DialectWrapper
+	switch dbType {
+	case "mysql":
+		dialect = goqu.Dialect("mysql")
+	case "sqlite":
+		dialect = goqu.Dialect("sqlite3")
+	default:
+		dialect = goqu.Dialect("postgres")
+	}
+
+	return &SuggestionRepositoryImpl{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *SuggestionRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// suggestionColumns 查询建议时选择的列（顺序与 FindByTaskID 中的 Scan 一致）
+var suggestionColumns = []interface{}{
+	"id", "task_id", "user_id", "tags", "priority", "original_priority", "model",
+	"status", "accepted_tags", "priority_accepted", "created_at", "resolved_at",
+}
+
+// Create 保存建议
+//
+// 任务已有建议时不覆盖（事件重复投递时只保留第一条），返回 false。
+func (r *SuggestionRepositoryImpl) Create(ctx context.Context, s *model.TaskSuggestion) (bool, error) {
+	tags, err := json.Marshal(s.Tags)
+	if err != nil {
+		return false, fmt.Errorf("encode suggested tags failed: %w", err)
+	}
+	accepted, err := json.Marshal(s.AcceptedTags)
+	if err != nil {
+		return false, fmt.Errorf("encode accepted tags failed: %w", err)
+	}
+
+	query, args, err := r.dialect.Insert("task_suggestions").
+		Rows(goqu.Record{
+			"id":                s.ID,
+			"task_id":           s.TaskID,
+			"user_id":           s.UserID,
+			"tags":              string(tags),
+			"priority":          string(s.Priority),
+			"original_priority": string(s.OriginalPriority),
+			"model":             s.Model,
+			"status":            string(s.Status),
+			"accepted_tags":     string(accepted),
+			"priority_accepted": s.PriorityAccepted,
+			"created_at":        s.CreatedAt,
+		}).
+		OnConflict(goqu.DoNothing()).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("build insert suggestion query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("insert suggestion failed: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected failed: %w", err)
+	}
+	return n > 0, nil
+}
+
+// FindByTaskID 查找任务的建议
+func (r *SuggestionRepositoryImpl) FindByTaskID(ctx context.Context, taskID string) (*model.TaskSuggestion, error) {
+	query, args, err := r.dialect.From("task_suggestions").
+		Select(suggestionColumns...).
+		Where(goqu.C("task_id").Eq(taskID)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build select suggestion query failed: %w", err)
+	}
+
+	var (
+		s                      model.TaskSuggestion
+		tags, accepted         string
+		priority, originalPrio string
+		status                 string
+		resolvedAt             sql.NullTime
+	)
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&s.ID, &s.TaskID, &s.UserID, &tags, &priority, &originalPrio, &s.Model,
+		&status, &accepted, &s.PriorityAccepted, &s.CreatedAt, &resolvedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSuggestionNotFound
+		}
+		return nil, fmt.Errorf("query suggestion failed: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(tags), &s.Tags); err != nil {
+		return nil, fmt.Errorf("decode suggested tags failed: %w", err)
+	}
+	if err := json.Unmarshal([]byte(accepted), &s.AcceptedTags); err != nil {
+		return nil, fmt.Errorf("decode accepted tags failed: %w", err)
+	}
+	s.Priority = model.Priority(priority)
+	s.OriginalPriority = model.Priority(originalPrio)
+	s.Status = model.SuggestionStatus(status)
+	if resolvedAt.Valid {
+		s.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &s, nil
+}
+
+// Resolve 保存用户的处理结果
+//
+// 只更新仍为 pending 的建议，并发处理时后到的请求返回 SUGGESTION_ALREADY_RESOLVED。
+func (r *SuggestionRepositoryImpl) Resolve(ctx context.Context, s *model.TaskSuggestion) error {
+	accepted, err := json.Marshal(s.AcceptedTags)
+	if err != nil {
+		return fmt.Errorf("encode accepted tags failed: %w", err)
+	}
+
+	query, args, err := r.dialect.Update("task_suggestions").
+		Set(goqu.Record{
+			"status":            string(s.Status),
+			"accepted_tags":     string(accepted),
+			"priority_accepted": s.PriorityAccepted,
+			"resolved_at":       s.ResolvedAt,
+		}).
+		Where(
+			goqu.C("id").Eq(s.ID),
+			goqu.C("status").Eq(string(model.SuggestionPending)),
+		).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build update suggestion query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update suggestion failed: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if n == 0 {
+		return model.ErrSuggestionResolved
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSuggestionRepository_Create 测试保存建议（已有建议时不覆盖）
+func TestSuggestionRepository_Create(t *testing.T) {
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{"保存新建议", 1, true},
+		{"任务已有建议时跳过", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewSuggestionRepository(db, "postgres")
+			mock.ExpectExec(`INSERT INTO "task_suggestions" .+'\["work","urgent"\]'.+ ON CONFLICT DO NOTHING`).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			created, err := repo.Create(context.Background(), &model.TaskSuggestion{
+				ID:               "sug-1",
+				TaskID:           "task-1",
+				UserID:           "user-123",
+				Tags:             []string{"work", "urgent"},
+				Priority:         model.PriorityHigh,
+				OriginalPriority: model.PriorityMedium,
+				Model:            "mock-model",
+				Status:           model.SuggestionPending,
+				AcceptedTags:     []string{},
+				CreatedAt:        time.Now(),
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, created)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestSuggestionRepository_FindByTaskID 测试查找任务的建议
+func TestSuggestionRepository_FindByTaskID(t *testing.T) {
+	t.Run("解析标签和状态", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewSuggestionRepository(db, "postgres")
+		now := time.Now()
+		mock.ExpectQuery(`SELECT .+ FROM "task_suggestions" WHERE \("task_id" = 'task-1'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "task_id", "user_id", "tags", "priority", "original_priority", "model",
+				"status", "accepted_tags", "priority_accepted", "created_at", "resolved_at",
+			}).AddRow("sug-1", "task-1", "user-123", `["work","urgent"]`, "high", "medium", "mock-model",
+				"accepted", `["work"]`, true, now, now))
+
+		s, err := repo.FindByTaskID(context.Background(), "task-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"work", "urgent"}, s.Tags)
+		assert.Equal(t, []string{"work"}, s.AcceptedTags)
+		assert.Equal(t, model.PriorityHigh, s.Priority)
+		assert.Equal(t, model.SuggestionAccepted, s.Status)
+		assert.True(t, s.PriorityAccepted)
+		assert.NotNil(t, s.ResolvedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("没有建议", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewSuggestionRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT .+ FROM "task_suggestions"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err = repo.FindByTaskID(context.Background(), "task-1")
+
+		assert.ErrorIs(t, err, ErrSuggestionNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestSuggestionRepository_Resolve 测试只更新待处理的建议
+func TestSuggestionRepository_Resolve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewSuggestionRepository(db, "postgres")
+	mock.ExpectExec(`UPDATE "task_suggestions" SET .+ WHERE \(\("id" = 'sug-1'\) AND \("status" = 'pending'\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	now := time.Now()
+	err = repo.Resolve(context.Background(), &model.TaskSuggestion{
+		ID:           "sug-1",
+		Status:       model.SuggestionRejected,
+		AcceptedTags: []string{},
+		ResolvedAt:   &now,
+	})
+
+	assert.ErrorIs(t, err, model.ErrSuggestionResolved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
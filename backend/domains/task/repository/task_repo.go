@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 )
 
 // TaskRepositoryImpl 任务仓储实现
@@ -15,9 +18,13 @@ import (
 // 使用 database/sql + goqu 实现任务数据访问。
 // 使用 goqu 作为 SQL 构建器，支持多数据库方言（PostgreSQL、MySQL、SQLite）。
 // 不使用 ORM，使用原生 SQL 保证透明度和性能。
+//
+// 所有 SQL 通过 persistence.Conn 执行：ctx 中携带事务（TxManager.WithinTx）时
+// 自动加入该事务，Service 可以把多次仓储调用组合成一个工作单元。
 type TaskRepositoryImpl struct {
-	db      *sql.DB
-	dialect goqu.DialectWrapper
+	db        *sql.DB
+	dialect   goqu.DialectWrapper
+	txManager persistence.TxManager
 }
 
 // NewTaskRepository 创建任务仓储实例
@@ -44,22 +51,62 @@ func NewTaskRepository(db *sql.DB, dbType string) *TaskRepositoryImpl {
 	}
 
 	return &TaskRepositoryImpl{
-		db:      db,
-		dialect: dialect,
+		db:        db,
+		dialect:   dialect,
+		txManager: persistence.NewTxManager(db),
 	}
 }
 
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *TaskRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
 // 错误定义
 var (
 	ErrTaskNotFound = errors.New("TASK_NOT_FOUND: 任务不存在")
 )
 
+// taskColumns tasks 表的查询/插入列（顺序与 scanTask 保持一致）
+var taskColumns = []interface{}{
+	"id", "user_id", "title", "description", "status", "priority",
+	"due_date", "created_at", "updated_at", "completed_at", "parent_id",
+	"hidden_until",
+}
+
+// rowScanner 抽象 *sql.Row 和 *sql.Rows 的 Scan 方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask 按 taskColumns 的顺序扫描一行任务数据
+func scanTask(row rowScanner) (*model.Task, error) {
+	task := &model.Task{}
+	err := row.Scan(
+		&task.ID,
+		&task.UserID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.DueDate,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.CompletedAt,
+		&task.ParentID,
+		&task.HiddenUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
 // Create 创建任务
 func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error {
 	// 使用 goqu 构建 INSERT 语句
 	query, args, err := r.dialect.Insert("tasks").
-		Cols("id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at").
+		Cols(taskColumns...).
 		Vals(goqu.Vals{
 			task.ID,
 			task.UserID,
@@ -71,26 +118,30 @@ func (r *TaskRepositoryImpl) Create(ctx context.Context, task *model.Task) error
 			task.CreatedAt,
 			task.UpdatedAt,
 			task.CompletedAt,
+			task.ParentID,
+			task.HiddenUntil,
 		}).
 		ToSQL()
 	if err != nil {
 		return fmt.Errorf("build insert query failed: %w", err)
 	}
 
-	// 执行插入
-	_, err = r.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("create task failed: %w", err)
-	}
+	// 任务和标签在同一事务中写入，避免只保存一半
+	return r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 执行插入
+		if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("create task failed: %w", err)
+		}
 
-	// 保存标签
-	if len(task.Tags) > 0 {
-		if err := r.saveTags(ctx, task.ID, task.Tags); err != nil {
-			return fmt.Errorf("save tags failed: %w", err)
+		// 保存标签
+		if len(task.Tags) > 0 {
+			if err := r.saveTags(ctx, task.ID, task.Tags); err != nil {
+				return fmt.Errorf("save tags failed: %w", err)
+			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 // Update 更新任务
@@ -105,6 +156,8 @@ func (r *TaskRepositoryImpl) Update(ctx context.Context, task *model.Task) error
 			"due_date":     task.DueDate,
 			"updated_at":   task.UpdatedAt,
 			"completed_at": task.CompletedAt,
+			"parent_id":    task.ParentID,
+			"hidden_until": task.HiddenUntil,
 		}).
 		Where(goqu.C("id").Eq(task.ID)).
 		ToSQL()
@@ -112,40 +165,42 @@ func (r *TaskRepositoryImpl) Update(ctx context.Context, task *model.Task) error
 		return fmt.Errorf("build update query failed: %w", err)
 	}
 
-	// 执行更新
-	result, err := r.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("update task failed: %w", err)
-	}
+	// 任务和标签在同一事务中更新，避免只保存一半
+	return r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 执行更新
+		result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("update task failed: %w", err)
+		}
 
-	// 检查是否更新了记录
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("get rows affected failed: %w", err)
-	}
-	if rowsAffected == 0 {
-		return ErrTaskNotFound
-	}
+		// 检查是否更新了记录
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected failed: %w", err)
+		}
+		if rowsAffected == 0 {
+			return ErrTaskNotFound
+		}
 
-	// 更新标签（先删除旧的，再插入新的）
-	if err := r.deleteTags(ctx, task.ID); err != nil {
-		return fmt.Errorf("delete old tags failed: %w", err)
-	}
-	if len(task.Tags) > 0 {
-		if err := r.saveTags(ctx, task.ID, task.Tags); err != nil {
-			return fmt.Errorf("save new tags failed: %w", err)
+		// 更新标签（先删除旧的，再插入新的）
+		if err := r.deleteTags(ctx, task.ID); err != nil {
+			return fmt.Errorf("delete old tags failed: %w", err)
+		}
+		if len(task.Tags) > 0 {
+			if err := r.saveTags(ctx, task.ID, task.Tags); err != nil {
+				return fmt.Errorf("save new tags failed: %w", err)
+			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 // FindByID 根据 ID 查找任务
 func (r *TaskRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Task, error) {
 	// 使用 goqu 构建 SELECT 语句
 	query, args, err := r.dialect.From("tasks").
-		Select("id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at").
+		Select(taskColumns...).
 		Where(goqu.C("id").Eq(id)).
 		ToSQL()
 	if err != nil {
@@ -153,19 +208,7 @@ func (r *TaskRepositoryImpl) FindByID(ctx context.Context, id string) (*model.Ta
 	}
 
 	// 查询任务
-	task := &model.Task{}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&task.ID,
-		&task.UserID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.Priority,
-		&task.DueDate,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-		&task.CompletedAt,
-	)
+	task, err := scanTask(r.conn(ctx).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrTaskNotFound
@@ -194,7 +237,7 @@ func (r *TaskRepositoryImpl) Delete(ctx context.Context, id string) error {
 	}
 
 	// 执行删除
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("delete task failed: %w", err)
 	}
@@ -226,25 +269,30 @@ func (r *TaskRepositoryImpl) List(ctx context.Context, filter *TaskFilter) ([]*m
 	}
 
 	var totalCount int
-	err = r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&totalCount)
+	err = r.conn(ctx).QueryRowContext(ctx, countSQL, countArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("count tasks failed: %w", err)
 	}
 
 	// 构建 SELECT 查询
-	offset := (filter.Page - 1) * filter.Limit
 	selectQuery := baseQuery.
-		Select("id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at")
+		Select(taskColumns...)
 
 	// 排序
-	if filter.SortOrder == "asc" {
+	switch {
+	case filter.SortBy == SortByUrgencyCandidates:
+		selectQuery = selectQuery.Order(urgencyCandidateOrder()...)
+	case filter.SortOrder == "asc":
 		selectQuery = selectQuery.Order(goqu.C(filter.SortBy).Asc())
-	} else {
+	default:
 		selectQuery = selectQuery.Order(goqu.C(filter.SortBy).Desc())
 	}
 
-	selectQuery = selectQuery.Limit(uint(filter.Limit)).Offset(uint(offset))
+	// 分页（Limit <= 0 表示不分页，返回全部结果）
+	if filter.Limit > 0 {
+		offset := (filter.Page - 1) * filter.Limit
+		selectQuery = selectQuery.Limit(uint(filter.Limit)).Offset(uint(offset))
+	}
 
 	query, args, err := selectQuery.ToSQL()
 	if err != nil {
@@ -252,7 +300,7 @@ func (r *TaskRepositoryImpl) List(ctx context.Context, filter *TaskFilter) ([]*m
 	}
 
 	// 执行查询
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("query tasks failed: %w", err)
 	}
@@ -261,40 +309,41 @@ func (r *TaskRepositoryImpl) List(ctx context.Context, filter *TaskFilter) ([]*m
 	// 扫描结果
 	tasks := make([]*model.Task, 0)
 	for rows.Next() {
-		task := &model.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.CompletedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("scan task failed: %w", err)
 		}
-
-		// 加载标签
-		tags, err := r.loadTags(ctx, task.ID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("load tags failed: %w", err)
-		}
-		task.Tags = tags
-
 		tasks = append(tasks, task)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, 0, fmt.Errorf("rows iteration failed: %w", err)
 	}
+	rows.Close()
+
+	// 一次查询加载整页任务的标签（避免 N+1）
+	if err := r.loadTagsForTasks(ctx, tasks); err != nil {
+		return nil, 0, fmt.Errorf("load tags failed: %w", err)
+	}
 
 	return tasks, totalCount, nil
 }
 
+// urgencyCandidateOrder 紧急度候选任务的预排序（见 SortByUrgencyCandidates）
+func urgencyCandidateOrder() []exp.OrderedExpression {
+	return []exp.OrderedExpression{
+		goqu.Case().When(goqu.C("status").Eq(string(model.StatusCompleted)), 1).Else(0).Asc(),
+		// 无截止日期的在后（不使用 NULLS LAST，MySQL 不支持）
+		goqu.Case().When(goqu.C("due_date").IsNull(), 1).Else(0).Asc(),
+		goqu.C("due_date").Asc(),
+		goqu.Case().
+			When(goqu.C("priority").Eq(string(model.PriorityHigh)), 0).
+			When(goqu.C("priority").Eq(string(model.PriorityMedium)), 1).
+			Else(2).Asc(),
+		goqu.C("created_at").Asc(),
+	}
+}
+
 // Exists 检查任务是否存在
 func (r *TaskRepositoryImpl) Exists(ctx context.Context, id string) (bool, error) {
 	// 使用 goqu 构建 EXISTS 查询
@@ -310,7 +359,7 @@ func (r *TaskRepositoryImpl) Exists(ctx context.Context, id string) (bool, error
 	}
 
 	var exists bool
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exists)
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("check task existence failed: %w", err)
 	}
@@ -318,6 +367,158 @@ func (r *TaskRepositoryImpl) Exists(ctx context.Context, id string) (bool, error
 	return exists, nil
 }
 
+// CountOpenSubtasks 统计每个父任务未完成的子任务数量
+//
+// 使用一次 GROUP BY 查询完成，不随父任务数量增加查询次数。
+func (r *TaskRepositoryImpl) CountOpenSubtasks(ctx context.Context, parentIDs []string) (map[string]int, error) {
+	result := make(map[string]int)
+	if len(parentIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := r.dialect.From("tasks").
+		Select("parent_id", goqu.COUNT(goqu.Star())).
+		Where(
+			goqu.C("parent_id").In(parentIDs),
+			goqu.C("status").Neq(model.StatusCompleted),
+		).
+		GroupBy("parent_id").
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build count subtasks query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count subtasks failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var parentID string
+		var count int
+		if err := rows.Scan(&parentID, &count); err != nil {
+			return nil, fmt.Errorf("scan subtask count failed: %w", err)
+		}
+		result[parentID] = count
+	}
+
+	return result, rows.Err()
+}
+
+// FindExpiredSnoozes 查找推迟已到期（hidden_until <= now）但尚未清除的任务
+//
+// 按 hidden_until 升序返回，最多 limit 个（<= 0 表示不限制），标签批量加载。
+func (r *TaskRepositoryImpl) FindExpiredSnoozes(ctx context.Context, now time.Time, limit int) ([]*model.Task, error) {
+	selectQuery := r.dialect.From("tasks").
+		Select(taskColumns...).
+		Where(
+			goqu.C("hidden_until").IsNotNull(),
+			goqu.C("hidden_until").Lte(now),
+		).
+		Order(goqu.C("hidden_until").Asc())
+	if limit > 0 {
+		selectQuery = selectQuery.Limit(uint(limit))
+	}
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build find expired snoozes query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query expired snoozes failed: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]*model.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan task failed: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+	rows.Close()
+
+	if err := r.loadTagsForTasks(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("load tags failed: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// ClearSnooze 清除已到期的推迟
+//
+// 只有 hidden_until 仍等于 hiddenUntil 时才清除（条件更新）：
+// 期间用户重新推迟或取消推迟，或者其他实例已经处理过，都返回 false。
+func (r *TaskRepositoryImpl) ClearSnooze(ctx context.Context, taskID string, hiddenUntil time.Time) (bool, error) {
+	query, args, err := r.dialect.Update("tasks").
+		Set(goqu.Record{"hidden_until": nil}).
+		Where(
+			goqu.C("id").Eq(taskID),
+			goqu.C("hidden_until").Eq(hiddenUntil),
+		).
+		ToSQL()
+	if err != nil {
+		return false, fmt.Errorf("build clear snooze query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("clear snooze failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected failed: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ListTagNames 列出用户使用过的标签名称
+//
+// 按使用次数降序（次数相同时按名称），一次 GROUP BY 查询完成。
+func (r *TaskRepositoryImpl) ListTagNames(ctx context.Context, userID string, limit int) ([]string, error) {
+	selectQuery := r.dialect.From(goqu.T("task_tags").As("tt")).
+		Join(goqu.T("tasks").As("t"), goqu.On(goqu.I("t.id").Eq(goqu.I("tt.task_id")))).
+		Select(goqu.I("tt.tag_name")).
+		Where(goqu.I("t.user_id").Eq(userID)).
+		GroupBy(goqu.I("tt.tag_name")).
+		Order(goqu.COUNT(goqu.Star()).Desc(), goqu.I("tt.tag_name").Asc())
+	if limit > 0 {
+		selectQuery = selectQuery.Limit(uint(limit))
+	}
+
+	query, args, err := selectQuery.ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list tag names query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query tag names failed: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan tag name failed: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
 // ============================================
 // 私有辅助方法
 // ============================================
@@ -328,20 +529,23 @@ func (r *TaskRepositoryImpl) saveTags(ctx context.Context, taskID string, tags [
 		return nil
 	}
 
-	// 使用 goqu 批量插入标签
+	// 使用 goqu 生成一条多行 INSERT 批量插入标签
+	vals := make([][]interface{}, 0, len(tags))
 	for _, tag := range tags {
-		query, args, err := r.dialect.Insert("task_tags").
-			Cols("task_id", "tag_name", "tag_color").
-			Vals(goqu.Vals{taskID, tag.Name, tag.Color}).
-			ToSQL()
-		if err != nil {
-			return fmt.Errorf("build insert tag query failed: %w", err)
-		}
+		vals = append(vals, goqu.Vals{taskID, tag.Name, tag.Color})
+	}
 
-		_, err = r.db.ExecContext(ctx, query, args...)
-		if err != nil {
-			return fmt.Errorf("insert tag failed: %w", err)
-		}
+	query, args, err := r.dialect.Insert("task_tags").
+		Cols("task_id", "tag_name", "tag_color").
+		Vals(vals...).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert tag query failed: %w", err)
+	}
+
+	_, err = r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("insert tag failed: %w", err)
 	}
 
 	return nil
@@ -356,7 +560,7 @@ func (r *TaskRepositoryImpl) deleteTags(ctx context.Context, taskID string) erro
 		return fmt.Errorf("build delete tags query failed: %w", err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = r.conn(ctx).ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -365,12 +569,13 @@ func (r *TaskRepositoryImpl) loadTags(ctx context.Context, taskID string) ([]mod
 	query, args, err := r.dialect.From("task_tags").
 		Select("tag_name", "tag_color").
 		Where(goqu.C("task_id").Eq(taskID)).
+		Order(goqu.C("tag_name").Asc()). // 标签顺序稳定（与批量加载一致）
 		ToSQL()
 	if err != nil {
 		return nil, fmt.Errorf("build select tags query failed: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query tags failed: %w", err)
 	}
@@ -388,6 +593,51 @@ func (r *TaskRepositoryImpl) loadTags(ctx context.Context, taskID string) ([]mod
 	return tags, rows.Err()
 }
 
+// loadTagsForTasks 批量加载多个任务的标签
+//
+// 使用一条 WHERE task_id IN (...) 查询，查询次数与任务数量无关。
+func (r *TaskRepositoryImpl) loadTagsForTasks(ctx context.Context, tasks []*model.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*model.Task, len(tasks))
+	ids := make([]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		task.Tags = make([]model.Tag, 0)
+		byID[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	query, args, err := r.dialect.From("task_tags").
+		Select("task_id", "tag_name", "tag_color").
+		Where(goqu.C("task_id").In(ids...)).
+		Order(goqu.C("task_id").Asc(), goqu.C("tag_name").Asc()). // 每个任务的标签顺序稳定
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build select tags query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query tags failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		var tag model.Tag
+		if err := rows.Scan(&taskID, &tag.Name, &tag.Color); err != nil {
+			return fmt.Errorf("scan tag failed: %w", err)
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
 // buildWhereConditions 构建 WHERE 条件（使用 goqu）
 func (r *TaskRepositoryImpl) buildWhereConditions(query *goqu.SelectDataset, filter *TaskFilter) *goqu.SelectDataset {
 	// 按用户 ID 筛选（必需）
@@ -398,6 +648,8 @@ func (r *TaskRepositoryImpl) buildWhereConditions(query *goqu.SelectDataset, fil
 	// 按状态筛选
 	if filter.Status != nil {
 		query = query.Where(goqu.C("status").Eq(*filter.Status))
+	} else if filter.ExcludeCompleted {
+		query = query.Where(goqu.C("status").Neq(model.StatusCompleted))
 	}
 
 	// 按优先级筛选
@@ -413,6 +665,16 @@ func (r *TaskRepositoryImpl) buildWhereConditions(query *goqu.SelectDataset, fil
 		query = query.Where(goqu.C("id").In(subQuery))
 	}
 
+	// 按任务 ID 筛选
+	if filter.IDs != nil {
+		query = query.Where(goqu.C("id").In(filter.IDs))
+	}
+
+	// 按父任务筛选（列出子任务）
+	if filter.ParentID != nil {
+		query = query.Where(goqu.C("parent_id").Eq(*filter.ParentID))
+	}
+
 	// 按截止日期范围筛选
 	if filter.DueDateFrom != nil {
 		query = query.Where(goqu.C("due_date").Gte(*filter.DueDateFrom))
@@ -421,6 +683,16 @@ func (r *TaskRepositoryImpl) buildWhereConditions(query *goqu.SelectDataset, fil
 		query = query.Where(goqu.C("due_date").Lte(*filter.DueDateTo))
 	}
 
+	// 默认排除推迟中的任务（hidden_until 在未来）
+	if !filter.IncludeSnoozed {
+		query = query.Where(
+			goqu.Or(
+				goqu.C("hidden_until").IsNull(),
+				goqu.C("hidden_until").Lte(goqu.L("CURRENT_TIMESTAMP")),
+			),
+		)
+	}
+
 	// 关键词搜索（标题或描述）
 	// 注意：ILIKE 是 PostgreSQL 特有，其他数据库使用 LIKE
 	// goqu 会根据 dialect 自动处理，但为了兼容性，我们使用 LIKE
@@ -451,7 +723,7 @@ func (r *TaskRepositoryImpl) CountByStatus(ctx context.Context) (map[model.TaskS
 		return nil, fmt.Errorf("build count by status query failed: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("count by status failed: %w", err)
 	}
@@ -473,8 +745,7 @@ func (r *TaskRepositoryImpl) CountByStatus(ctx context.Context) (map[model.TaskS
 // FindOverdueTasks 查找逾期任务
 func (r *TaskRepositoryImpl) FindOverdueTasks(ctx context.Context) ([]*model.Task, error) {
 	query, args, err := r.dialect.From("tasks").
-		Select("id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at").
+		Select(taskColumns...).
 		Where(goqu.C("status").Neq("completed")).
 		Where(goqu.C("due_date").IsNotNull()).
 		Where(goqu.C("due_date").Lt(goqu.L("CURRENT_TIMESTAMP"))).
@@ -484,7 +755,7 @@ func (r *TaskRepositoryImpl) FindOverdueTasks(ctx context.Context) ([]*model.Tas
 		return nil, fmt.Errorf("build find overdue tasks query failed: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query overdue tasks failed: %w", err)
 	}
@@ -492,32 +763,22 @@ func (r *TaskRepositoryImpl) FindOverdueTasks(ctx context.Context) ([]*model.Tas
 
 	tasks := make([]*model.Task, 0)
 	for rows.Next() {
-		task := &model.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.UserID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.CompletedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan task failed: %w", err)
 		}
+		tasks = append(tasks, task)
+	}
 
-		// 加载标签
-		tags, err := r.loadTags(ctx, task.ID)
-		if err != nil {
-			return nil, fmt.Errorf("load tags failed: %w", err)
-		}
-		task.Tags = tags
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+	rows.Close()
 
-		tasks = append(tasks, task)
+	// 一次查询加载所有逾期任务的标签（避免 N+1）
+	if err := r.loadTagsForTasks(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("load tags failed: %w", err)
 	}
 
-	return tasks, rows.Err()
+	return tasks, nil
 }
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDriver 统计查询次数的假数据库驱动
+//
+// 根据 SQL 返回固定的结果集：COUNT 返回 rows，tasks 查询返回 rows 个任务，
+// task_tags 查询为每个任务返回 2 个标签。用于验证查询次数不随页大小增长。
+type countingDriver struct {
+	rows    int
+	queries atomic.Int64
+}
+
+// countingConnector 每个测试使用独立的驱动实例
+type countingConnector struct{ d *countingDriver }
+
+func (c countingConnector) Connect(context.Context) (driver.Conn, error) {
+	return countingConn{c.d}, nil
+}
+func (c countingConnector) Driver() driver.Driver { return c.d }
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return countingConn{d}, nil }
+
+type countingConn struct{ d *countingDriver }
+
+func (c countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+func (c countingConn) Close() error              { return nil }
+func (c countingConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("tx not supported") }
+
+func (c countingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.queries.Add(1)
+	now := time.Now()
+
+	switch {
+	case strings.Contains(query, "COUNT(*)"):
+		return &staticRows{cols: []string{"count"}, data: [][]driver.Value{{int64(c.d.rows)}}}, nil
+	case strings.Contains(query, `FROM "task_tags"`):
+		data := make([][]driver.Value, 0, c.d.rows*2)
+		for i := 0; i < c.d.rows; i++ {
+			id := fmt.Sprintf("task-%d", i)
+			data = append(data, []driver.Value{id, "work", "#ff0000"}, []driver.Value{id, "home", "#00ff00"})
+		}
+		return &staticRows{cols: []string{"task_id", "tag_name", "tag_color"}, data: data}, nil
+	case strings.Contains(query, `FROM "tasks"`):
+		data := make([][]driver.Value, 0, c.d.rows)
+		for i := 0; i < c.d.rows; i++ {
+			data = append(data, []driver.Value{
+				fmt.Sprintf("task-%d", i), "user-123", "Task", "", "pending", "medium",
+				now.Add(-time.Hour), now, now, nil, nil, nil,
+			})
+		}
+		return &staticRows{cols: taskColumnNames(), data: data}, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+// staticRows 固定结果集
+type staticRows struct {
+	cols []string
+	data [][]driver.Value
+	pos  int
+}
+
+func (r *staticRows) Columns() []string { return r.cols }
+func (r *staticRows) Close() error      { return nil }
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.pos])
+	r.pos++
+	return nil
+}
+
+// taskColumnNames 返回 taskColumns 的列名
+func taskColumnNames() []string {
+	names := make([]string, len(taskColumns))
+	for i, c := range taskColumns {
+		names[i] = c.(string)
+	}
+	return names
+}
+
+// newCountingRepo 创建使用计数驱动的仓储
+func newCountingRepo(rows int) (*TaskRepositoryImpl, *countingDriver, func()) {
+	d := &countingDriver{rows: rows}
+	db := sql.OpenDB(countingConnector{d})
+	return NewTaskRepository(db, "postgres"), d, func() { db.Close() }
+}
+
+// TestTaskRepository_ConstantQueryCount 测试标签批量加载：查询次数与任务数量无关
+func TestTaskRepository_ConstantQueryCount(t *testing.T) {
+	for _, size := range []int{1, 10, 100} {
+		t.Run(fmt.Sprintf("List/%d", size), func(t *testing.T) {
+			repo, d, closeDB := newCountingRepo(size)
+			defer closeDB()
+
+			filter := NewTaskFilter()
+			filter.Limit = size
+			tasks, _, err := repo.List(context.Background(), filter)
+
+			require.NoError(t, err)
+			require.Len(t, tasks, size)
+			assert.Len(t, tasks[size-1].Tags, 2)
+			assert.Equal(t, int64(3), d.queries.Load()) // COUNT + SELECT + 标签
+		})
+
+		t.Run(fmt.Sprintf("FindOverdueTasks/%d", size), func(t *testing.T) {
+			repo, d, closeDB := newCountingRepo(size)
+			defer closeDB()
+
+			tasks, err := repo.FindOverdueTasks(context.Background())
+
+			require.NoError(t, err)
+			require.Len(t, tasks, size)
+			assert.Len(t, tasks[0].Tags, 2)
+			assert.Equal(t, int64(2), d.queries.Load()) // SELECT + 标签
+		})
+	}
+}
+
+// BenchmarkTaskRepository_List 基准测试：列出任务（queries/op 应保持为 3）
+func BenchmarkTaskRepository_List(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("page=%d", size), func(b *testing.B) {
+			repo, d, closeDB := newCountingRepo(size)
+			defer closeDB()
+
+			filter := NewTaskFilter()
+			filter.Limit = size
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.List(ctx, filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			perOp := float64(d.queries.Load()) / float64(b.N)
+			b.ReportMetric(perOp, "queries/op")
+			if perOp != 3 {
+				b.Fatalf("expected 3 queries per List, got %.2f", perOp)
+			}
+		})
+	}
+}
+
+// BenchmarkTaskRepository_FindOverdueTasks 基准测试：查询逾期任务（queries/op 应保持为 2）
+func BenchmarkTaskRepository_FindOverdueTasks(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("tasks=%d", size), func(b *testing.B) {
+			repo, d, closeDB := newCountingRepo(size)
+			defer closeDB()
+
+			ctx := context.Background()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.FindOverdueTasks(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			perOp := float64(d.queries.Load()) / float64(b.N)
+			b.ReportMetric(perOp, "queries/op")
+			if perOp != 2 {
+				b.Fatalf("expected 2 queries per FindOverdueTasks, got %.2f", perOp)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,10 +24,14 @@ func TestTaskRepository_Create(t *testing.T) {
 		repo := NewTaskRepository(db, "postgres")
 		task, _ := model.NewTask("test-user-id", "Test Task", "Description", model.PriorityMedium)
 
+		mock.ExpectBegin()
+
 		// Mock INSERT tasks (goqu 将参数值直接嵌入到 SQL 中，不使用占位符)
 		mock.ExpectExec(`INSERT INTO "tasks"`).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectCommit()
+
 		err = repo.Create(context.Background(), task)
 
 		assert.NoError(t, err)
@@ -43,15 +48,17 @@ func TestTaskRepository_Create(t *testing.T) {
 		task.AddTag(model.Tag{Name: "urgent", Color: "#ff0000"})
 		task.AddTag(model.Tag{Name: "important", Color: "#00ff00"})
 
+		mock.ExpectBegin()
+
 		// Mock INSERT tasks (goqu 使用双引号引用标识符)
 		mock.ExpectExec(`INSERT INTO "tasks"`).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
-		// Mock INSERT tags (2次) (goqu 将参数值直接嵌入到 SQL 中)
-		mock.ExpectExec(`INSERT INTO "task_tags"`).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectExec(`INSERT INTO "task_tags"`).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		// Mock INSERT tags (一条多行 INSERT) (goqu 将参数值直接嵌入到 SQL 中)
+		mock.ExpectExec(`INSERT INTO "task_tags" \("task_id", "tag_name", "tag_color"\) VALUES \(.+'urgent'.+\), \(.+'important'.+\)`).
+			WillReturnResult(sqlmock.NewResult(2, 2))
+
+		mock.ExpectCommit()
 
 		err = repo.Create(context.Background(), task)
 
@@ -67,9 +74,12 @@ func TestTaskRepository_Create(t *testing.T) {
 		repo := NewTaskRepository(db, "postgres")
 		task, _ := model.NewTask("test-user-id", "Test Task", "Description", model.PriorityMedium)
 
+		mock.ExpectBegin()
 		mock.ExpectExec(`INSERT INTO "tasks"`).
 			WillReturnError(fmt.Errorf("database error"))
 
+		mock.ExpectRollback()
+
 		err = repo.Create(context.Background(), task)
 
 		assert.Error(t, err)
@@ -90,10 +100,10 @@ func TestTaskRepository_FindByID(t *testing.T) {
 		// Mock SELECT tasks
 		rows := sqlmock.NewRows([]string{
 			"id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at",
+			"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 		}).AddRow(
 			"task-123", "user-123", "Test Task", "Description", "pending", "medium",
-			nil, now, now, nil,
+			nil, now, now, nil, nil, nil,
 		)
 		// goqu 生成的 SQL 使用双引号引用标识符，WHERE 条件使用括号，参数值直接嵌入
 		mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
@@ -103,7 +113,7 @@ func TestTaskRepository_FindByID(t *testing.T) {
 		tagsRows := sqlmock.NewRows([]string{"tag_name", "tag_color"}).
 			AddRow("urgent", "#ff0000").
 			AddRow("important", "#00ff00")
-		mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags"`).
+		mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" = 'task-123'\) ORDER BY "tag_name" ASC`).
 			WillReturnRows(tagsRows)
 
 		task, err := repo.FindByID(context.Background(), "task-123")
@@ -164,6 +174,8 @@ func TestTaskRepository_Update(t *testing.T) {
 		repo := NewTaskRepository(db, "postgres")
 		task, _ := model.NewTask("test-user-id", "Updated Task", "Updated Desc", model.PriorityHigh)
 
+		mock.ExpectBegin()
+
 		// Mock UPDATE (goqu 将参数值直接嵌入到 SQL 中)
 		mock.ExpectExec(`UPDATE "tasks" SET`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
@@ -172,6 +184,8 @@ func TestTaskRepository_Update(t *testing.T) {
 		mock.ExpectExec(`DELETE FROM "task_tags" WHERE \("task_id"`).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
+		mock.ExpectCommit()
+
 		err = repo.Update(context.Background(), task)
 
 		assert.NoError(t, err)
@@ -187,6 +201,8 @@ func TestTaskRepository_Update(t *testing.T) {
 		task, _ := model.NewTask("test-user-id", "Updated Task", "Updated Desc", model.PriorityHigh)
 		task.AddTag(model.Tag{Name: "new-tag", Color: "#ff0000"})
 
+		mock.ExpectBegin()
+
 		// Mock UPDATE (goqu 使用双引号引用标识符)
 		mock.ExpectExec(`UPDATE "tasks" SET`).
 			WillReturnResult(sqlmock.NewResult(0, 1))
@@ -199,6 +215,8 @@ func TestTaskRepository_Update(t *testing.T) {
 		mock.ExpectExec(`INSERT INTO "task_tags"`).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
+		mock.ExpectCommit()
+
 		err = repo.Update(context.Background(), task)
 
 		assert.NoError(t, err)
@@ -213,10 +231,14 @@ func TestTaskRepository_Update(t *testing.T) {
 		repo := NewTaskRepository(db, "postgres")
 		task, _ := model.NewTask("test-user-id", "Updated Task", "Updated Desc", model.PriorityHigh)
 
+		mock.ExpectBegin()
+
 		// Mock UPDATE returns 0 rows affected (goqu 使用双引号引用标识符)
 		mock.ExpectExec(`UPDATE "tasks" SET`).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
+		mock.ExpectRollback()
+
 		err = repo.Update(context.Background(), task)
 
 		assert.Error(t, err)
@@ -231,14 +253,107 @@ func TestTaskRepository_Update(t *testing.T) {
 		repo := NewTaskRepository(db, "postgres")
 		task, _ := model.NewTask("test-user-id", "Updated Task", "Updated Desc", model.PriorityHigh)
 
+		mock.ExpectBegin()
 		mock.ExpectExec(`UPDATE "tasks" SET`).
 			WillReturnError(fmt.Errorf("database error"))
 
+		mock.ExpectRollback()
+
 		err = repo.Update(context.Background(), task)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "update task failed")
 	})
+
+	t.Run("保存标签失败时回滚", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTaskRepository(db, "postgres")
+		task, _ := model.NewTask("test-user-id", "Updated Task", "Updated Desc", model.PriorityHigh)
+		task.AddTag(model.Tag{Name: "new-tag", Color: "#ff0000"})
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "tasks" SET`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM "task_tags" WHERE \("task_id"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO "task_tags"`).
+			WillReturnError(fmt.Errorf("database error"))
+
+		// 任务行的更新和旧标签的删除一起回滚
+		mock.ExpectRollback()
+
+		err = repo.Update(context.Background(), task)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "save new tags failed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestTaskRepository_UnitOfWork 测试多次仓储调用组合为一个事务
+func TestTaskRepository_UnitOfWork(t *testing.T) {
+	t.Run("加入 ctx 中的事务", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTaskRepository(db, "postgres")
+		txManager := persistence.NewTxManager(db)
+		parent, _ := model.NewTask("test-user-id", "Parent", "", model.PriorityMedium)
+		child, _ := model.NewTask("test-user-id", "Child", "", model.PriorityMedium)
+		child.AddTag(model.Tag{Name: "sub"})
+
+		// 只开启一次事务，两次 Create 都在其中执行
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "tasks"`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO "tasks"`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO "task_tags"`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := repo.Create(ctx, parent); err != nil {
+				return err
+			}
+			return repo.Create(ctx, child)
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("后续调用失败时整体回滚", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTaskRepository(db, "postgres")
+		txManager := persistence.NewTxManager(db)
+		parent, _ := model.NewTask("test-user-id", "Parent", "", model.PriorityMedium)
+		child, _ := model.NewTask("test-user-id", "Child", "", model.PriorityMedium)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "tasks"`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO "tasks"`).
+			WillReturnError(fmt.Errorf("database error"))
+		mock.ExpectRollback()
+
+		err = txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := repo.Create(ctx, parent); err != nil {
+				return err
+			}
+			return repo.Create(ctx, child)
+		})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 // TestTaskRepository_Delete 测试删除任务
@@ -316,24 +431,19 @@ func TestTaskRepository_List(t *testing.T) {
 		// Mock SELECT tasks (goqu 使用双引号引用标识符)
 		rows := sqlmock.NewRows([]string{
 			"id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at",
+			"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 		}).
-			AddRow("task-1", "user-123", "Task 1", "Desc 1", "pending", "medium", nil, now, now, nil).
-			AddRow("task-2", "user-123", "Task 2", "Desc 2", "completed", "high", nil, now, now, &now)
+			AddRow("task-1", "user-123", "Task 1", "Desc 1", "pending", "medium", nil, now, now, nil, nil, nil).
+			AddRow("task-2", "user-123", "Task 2", "Desc 2", "completed", "high", nil, now, now, &now, nil, nil)
 
 		mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 			WillReturnRows(rows)
 
-		// Mock tags for task-1 (goqu 将参数值直接嵌入到 SQL 中)
-		tags1 := sqlmock.NewRows([]string{"tag_name", "tag_color"}).
-			AddRow("urgent", "#ff0000")
-		mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags"`).
-			WillReturnRows(tags1)
-
-		// Mock tags for task-2 (goqu 将参数值直接嵌入到 SQL 中)
-		tags2 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-		mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags"`).
-			WillReturnRows(tags2)
+		// Mock 批量加载 tags（一次 IN 查询，按任务和标签名排序） (goqu 将参数值直接嵌入到 SQL 中)
+		tagRows := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}).
+			AddRow("task-1", "urgent", "#ff0000")
+		mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN \('task-1', 'task-2'\)\) ORDER BY "task_id" ASC, "tag_name" ASC`).
+			WillReturnRows(tagRows)
 
 		tasks, totalCount, err := repo.List(context.Background(), filter)
 
@@ -371,15 +481,15 @@ func TestTaskRepository_List(t *testing.T) {
 		// Mock SELECT with WHERE (goqu 将参数值直接嵌入到 SQL 中)
 		rows := sqlmock.NewRows([]string{
 			"id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at",
-		}).AddRow("task-1", "user-123", "Task 1", "Desc 1", "pending", "high", nil, now, now, nil)
+			"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+		}).AddRow("task-1", "user-123", "Task 1", "Desc 1", "pending", "high", nil, now, now, nil, nil, nil)
 
 		mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE`).
 			WillReturnRows(rows)
 
 		// Mock tags (goqu 将参数值直接嵌入到 SQL 中)
-		tags := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-		mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags"`).
+		tags := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"})
+		mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN`).
 			WillReturnRows(tags)
 
 		tasks, totalCount, err := repo.List(context.Background(), filter)
@@ -391,6 +501,34 @@ func TestTaskRepository_List(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
+	t.Run("按 ID 筛选", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTaskRepository(db, "postgres")
+		filter := NewTaskFilter()
+		userID := "user-123"
+		filter.UserID = &userID
+		filter.IDs = []string{"task-1", "task-2"}
+		filter.IncludeSnoozed = true
+		filter.Limit = 0
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "tasks" WHERE \(\("user_id" = 'user-123'\) AND \("id" IN \('task-1', 'task-2'\)\)\)$`).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE .+"id" IN \('task-1', 'task-2'\).+ORDER BY "created_at" DESC$`).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "user_id", "title", "description", "status", "priority",
+				"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+			}))
+
+		tasks, _, err := repo.List(context.Background(), filter)
+
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("列出空结果", func(t *testing.T) {
 		db, mock, err := sqlmock.New()
 		require.NoError(t, err)
@@ -409,7 +547,7 @@ func TestTaskRepository_List(t *testing.T) {
 		// Mock SELECT (goqu 使用双引号引用标识符)
 		rows := sqlmock.NewRows([]string{
 			"id", "user_id", "title", "description", "status", "priority",
-			"due_date", "created_at", "updated_at", "completed_at",
+			"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 		})
 		mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 			WillReturnRows(rows)
@@ -536,3 +674,81 @@ func TestNewTaskFilter(t *testing.T) {
 	assert.Nil(t, filter.Priority)
 	assert.Nil(t, filter.Tag)
 }
+
+// TestTaskRepository_FindExpiredSnoozes 测试查找推迟已到期的任务
+func TestTaskRepository_FindExpiredSnoozes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTaskRepository(db, "postgres")
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	hiddenUntil := now.Add(-time.Minute)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority",
+		"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-1", "user-123", "Task 1", "", "pending", "medium", nil, now, now, nil, nil, hiddenUntil)
+	mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \(\("hidden_until" IS NOT NULL\) AND \("hidden_until" <= '2026-10-19T09:00:00Z'\)\) ORDER BY "hidden_until" ASC LIMIT 50`).
+		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN \('task-1'\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}).AddRow("task-1", "work", "#808080"))
+
+	tasks, err := repo.FindExpiredSnoozes(context.Background(), now, 50)
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.NotNil(t, tasks[0].HiddenUntil)
+	assert.True(t, hiddenUntil.Equal(*tasks[0].HiddenUntil))
+	assert.Len(t, tasks[0].Tags, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestTaskRepository_ClearSnooze 测试条件清除推迟
+func TestTaskRepository_ClearSnooze(t *testing.T) {
+	hiddenUntil := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{"hidden_until 未变化时清除", 1, true},
+		{"已被重新推迟时不清除", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			repo := NewTaskRepository(db, "postgres")
+			mock.ExpectExec(`UPDATE "tasks" SET "hidden_until"=NULL WHERE \(\("id" = 'task-1'\) AND \("hidden_until" = '2026-10-19T09:00:00Z'\)\)`).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+
+			cleared, err := repo.ClearSnooze(context.Background(), "task-1", hiddenUntil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cleared)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// TestTaskRepository_ListTagNames 测试按使用次数列出用户的标签
+func TestTaskRepository_ListTagNames(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTaskRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT "tt"."tag_name" FROM "task_tags" AS "tt" INNER JOIN "tasks" AS "t" ON \("t"."id" = "tt"."task_id"\) WHERE \("t"."user_id" = 'user-123'\) GROUP BY "tt"."tag_name" ORDER BY COUNT\(\*\) DESC, "tt"."tag_name" ASC LIMIT 50`).
+		WillReturnRows(sqlmock.NewRows([]string{"tag_name"}).AddRow("work").AddRow("home"))
+
+	names, err := repo.ListTagNames(context.Background(), "user-123", 50)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "home"}, names)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
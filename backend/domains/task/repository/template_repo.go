@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// TemplateRepositoryImpl 任务模板仓储实现
+//
+// 标签和子任务定义以 JSON 文本存储在 task_templates 表中，
+// 模板作为一个整体读写，不需要单独的子表。
+type TemplateRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewTemplateRepository 创建任务模板仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewTemplateRepository(db *sql.DB, dbType string) *TemplateRepositoryImpl {
+	var dialect goqu.DialectWrapper
+	switch dbType {
+	case "mysql":
+		dialect = goqu.Dialect("mysql")
+	case "sqlite":
+		dialect = goqu.Dialect("sqlite3")
+	default:
+		dialect = goqu.Dialect("postgres")
+	}
+
+	return &TemplateRepositoryImpl{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *TemplateRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// 错误定义
+var (
+	ErrTemplateNotFound = errors.New("TEMPLATE_NOT_FOUND: 模板不存在")
+)
+
+// templateColumns task_templates 表的查询/插入列（顺序与 scanTemplate 保持一致）
+var templateColumns = []interface{}{
+	"id", "user_id", "name", "title_pattern", "description", "priority",
+	"tags", "due_offset_seconds", "subtasks", "created_at", "updated_at",
+}
+
+// subtaskRecord 子任务定义的存储格式
+type subtaskRecord struct {
+	TitlePattern     string `json:"title_pattern"`
+	Description      string `json:"description,omitempty"`
+	Priority         string `json:"priority,omitempty"`
+	DueOffsetSeconds *int64 `json:"due_offset_seconds,omitempty"`
+}
+
+// Create 创建模板
+func (r *TemplateRepositoryImpl) Create(ctx context.Context, tpl *model.TaskTemplate) error {
+	tagsJSON, subtasksJSON, err := encodeTemplateJSON(tpl)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := r.dialect.Insert("task_templates").
+		Cols(templateColumns...).
+		Vals(goqu.Vals{
+			tpl.ID,
+			tpl.UserID,
+			tpl.Name,
+			tpl.TitlePattern,
+			tpl.Description,
+			tpl.Priority,
+			tagsJSON,
+			durationToSeconds(tpl.DueOffset),
+			subtasksJSON,
+			tpl.CreatedAt,
+			tpl.UpdatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert template query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create template failed: %w", err)
+	}
+	return nil
+}
+
+// FindByID 根据 ID 查找模板
+func (r *TemplateRepositoryImpl) FindByID(ctx context.Context, id string) (*model.TaskTemplate, error) {
+	query, args, err := r.dialect.From("task_templates").
+		Select(templateColumns...).
+		Where(goqu.C("id").Eq(id)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build select template query failed: %w", err)
+	}
+
+	tpl, err := scanTemplate(r.conn(ctx).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("query template failed: %w", err)
+	}
+	return tpl, nil
+}
+
+// Update 更新模板
+func (r *TemplateRepositoryImpl) Update(ctx context.Context, tpl *model.TaskTemplate) error {
+	tagsJSON, subtasksJSON, err := encodeTemplateJSON(tpl)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := r.dialect.Update("task_templates").
+		Set(goqu.Record{
+			"name":               tpl.Name,
+			"title_pattern":      tpl.TitlePattern,
+			"description":        tpl.Description,
+			"priority":           tpl.Priority,
+			"tags":               tagsJSON,
+			"due_offset_seconds": durationToSeconds(tpl.DueOffset),
+			"subtasks":           subtasksJSON,
+			"updated_at":         tpl.UpdatedAt,
+		}).
+		Where(goqu.C("id").Eq(tpl.ID)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build update template query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update template failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// Delete 删除模板
+func (r *TemplateRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query, args, err := r.dialect.Delete("task_templates").
+		Where(goqu.C("id").Eq(id)).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build delete template query failed: %w", err)
+	}
+
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("delete template failed: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// ListByUser 列出用户的所有模板
+func (r *TemplateRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*model.TaskTemplate, error) {
+	query, args, err := r.dialect.From("task_templates").
+		Select(templateColumns...).
+		Where(goqu.C("user_id").Eq(userID)).
+		Order(goqu.C("created_at").Desc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build list templates query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query templates failed: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*model.TaskTemplate, 0)
+	for rows.Next() {
+		tpl, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan template failed: %w", err)
+		}
+		templates = append(templates, tpl)
+	}
+
+	return templates, rows.Err()
+}
+
+// ============================================
+// 私有辅助方法
+// ============================================
+
+// scanTemplate 按 templateColumns 的顺序扫描一行模板数据
+func scanTemplate(row rowScanner) (*model.TaskTemplate, error) {
+	tpl := &model.TaskTemplate{}
+	var (
+		tagsJSON     string
+		subtasksJSON string
+		dueOffset    sql.NullInt64
+	)
+	err := row.Scan(
+		&tpl.ID,
+		&tpl.UserID,
+		&tpl.Name,
+		&tpl.TitlePattern,
+		&tpl.Description,
+		&tpl.Priority,
+		&tagsJSON,
+		&dueOffset,
+		&subtasksJSON,
+		&tpl.CreatedAt,
+		&tpl.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tpl.Tags = []string{}
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &tpl.Tags); err != nil {
+			return nil, fmt.Errorf("decode template tags failed: %w", err)
+		}
+	}
+
+	if dueOffset.Valid {
+		offset := time.Duration(dueOffset.Int64) * time.Second
+		tpl.DueOffset = &offset
+	}
+
+	var records []subtaskRecord
+	if subtasksJSON != "" {
+		if err := json.Unmarshal([]byte(subtasksJSON), &records); err != nil {
+			return nil, fmt.Errorf("decode template subtasks failed: %w", err)
+		}
+	}
+	tpl.Subtasks = make([]model.SubtaskTemplate, len(records))
+	for i, rec := range records {
+		sub := model.SubtaskTemplate{
+			TitlePattern: rec.TitlePattern,
+			Description:  rec.Description,
+			Priority:     model.Priority(rec.Priority),
+		}
+		if rec.DueOffsetSeconds != nil {
+			offset := time.Duration(*rec.DueOffsetSeconds) * time.Second
+			sub.DueOffset = &offset
+		}
+		tpl.Subtasks[i] = sub
+	}
+
+	return tpl, nil
+}
+
+// encodeTemplateJSON 将标签和子任务编码为 JSON 文本
+func encodeTemplateJSON(tpl *model.TaskTemplate) (string, string, error) {
+	tags := tpl.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return "", "", fmt.Errorf("encode template tags failed: %w", err)
+	}
+
+	records := make([]subtaskRecord, len(tpl.Subtasks))
+	for i, sub := range tpl.Subtasks {
+		records[i] = subtaskRecord{
+			TitlePattern:     sub.TitlePattern,
+			Description:      sub.Description,
+			Priority:         string(sub.Priority),
+			DueOffsetSeconds: durationToSeconds(sub.DueOffset),
+		}
+	}
+	subtasksJSON, err := json.Marshal(records)
+	if err != nil {
+		return "", "", fmt.Errorf("encode template subtasks failed: %w", err)
+	}
+
+	return string(tagsJSON), string(subtasksJSON), nil
+}
+
+// durationToSeconds 将可选时长转换为秒（nil 保持 nil，对应数据库 NULL）
+func durationToSeconds(d *time.Duration) *int64 {
+	if d == nil {
+		return nil
+	}
+	seconds := int64(*d / time.Second)
+	return &seconds
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTemplateRepository_Create 测试创建模板
+func TestTemplateRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewTemplateRepository(db, "postgres")
+	tpl, _ := model.NewTaskTemplate("user-123", "Weekly", "Weekly {{date}}", "", model.PriorityLow)
+	offset := 2 * time.Hour
+	require.NoError(t, tpl.SetSubtasks([]model.SubtaskTemplate{{TitlePattern: "Prepare", DueOffset: &offset}}))
+
+	// 子任务以 JSON 文本存储，截止偏移以秒存储
+	mock.ExpectExec(`INSERT INTO "task_templates" .+"due_offset_seconds":7200`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(context.Background(), tpl)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestTemplateRepository_FindByID 测试根据 ID 查找模板
+func TestTemplateRepository_FindByID(t *testing.T) {
+	t.Run("找到模板", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTemplateRepository(db, "postgres")
+		now := time.Now()
+
+		rows := sqlmock.NewRows([]string{
+			"id", "user_id", "name", "title_pattern", "description", "priority",
+			"tags", "due_offset_seconds", "subtasks", "created_at", "updated_at",
+		}).AddRow(
+			"tpl-1", "user-123", "Onboarding", "Onboard {{name}}", "", "high",
+			`["hr"]`, int64(86400), `[{"title_pattern":"Laptop","priority":"low"}]`, now, now,
+		)
+		mock.ExpectQuery(`SELECT .+ FROM "task_templates" WHERE \("id" = 'tpl-1'\)`).
+			WillReturnRows(rows)
+
+		tpl, err := repo.FindByID(context.Background(), "tpl-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hr"}, tpl.Tags)
+		require.NotNil(t, tpl.DueOffset)
+		assert.Equal(t, 24*time.Hour, *tpl.DueOffset)
+		require.Len(t, tpl.Subtasks, 1)
+		assert.Equal(t, model.PriorityLow, tpl.Subtasks[0].Priority)
+		assert.Nil(t, tpl.Subtasks[0].DueOffset)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("模板不存在", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewTemplateRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT .+ FROM "task_templates"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err = repo.FindByID(context.Background(), "missing")
+
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// UrgencySettingsRepositoryImpl 用户紧急度系数仓储实现
+//
+// 每个用户一行，系数以 JSON 文本存储，便于后续增加新的系数而不修改表结构。
+type UrgencySettingsRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewUrgencySettingsRepository 创建紧急度系数仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewUrgencySettingsRepository(db *sql.DB, dbType string) *UrgencySettingsRepositoryImpl {
+	var dialect goqu.DialectWrapper
+	switch dbType {
+	case "mysql":
+		dialect = goqu.Dialect("mysql")
+	case "sqlite":
+		dialect = goqu.Dialect("sqlite3")
+	default:
+		dialect = goqu.Dialect("postgres")
+	}
+
+	return &UrgencySettingsRepositoryImpl{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *UrgencySettingsRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// urgencyRecord 紧急度系数的存储格式
+type urgencyRecord struct {
+	PriorityHigh   float64            `json:"priority_high"`
+	PriorityMedium float64            `json:"priority_medium"`
+	PriorityLow    float64            `json:"priority_low"`
+	Due            float64            `json:"due"`
+	Age            float64            `json:"age"`
+	AgeMaxDays     int                `json:"age_max_days"`
+	Blocked        float64            `json:"blocked"`
+	Active         float64            `json:"active"`
+	Tags           float64            `json:"tags"`
+	TagBoosts      map[string]float64 `json:"tag_boosts,omitempty"`
+}
+
+// Get 获取用户的紧急度系数
+func (r *UrgencySettingsRepositoryImpl) Get(ctx context.Context, userID string) (*model.UrgencyCoefficients, error) {
+	query, args, err := r.dialect.From("urgency_settings").
+		Select("coefficients").
+		Where(goqu.C("user_id").Eq(userID)).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build select urgency settings query failed: %w", err)
+	}
+
+	var raw string
+	if err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&raw); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query urgency settings failed: %w", err)
+	}
+
+	// 先填充默认值，兼容旧数据中缺失的字段
+	defaults := model.DefaultUrgencyCoefficients()
+	rec := urgencyRecord{
+		PriorityHigh:   defaults.PriorityHigh,
+		PriorityMedium: defaults.PriorityMedium,
+		PriorityLow:    defaults.PriorityLow,
+		Due:            defaults.Due,
+		Age:            defaults.Age,
+		AgeMaxDays:     defaults.AgeMaxDays,
+		Blocked:        defaults.Blocked,
+		Active:         defaults.Active,
+		Tags:           defaults.Tags,
+	}
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, fmt.Errorf("decode urgency settings failed: %w", err)
+	}
+
+	return &model.UrgencyCoefficients{
+		PriorityHigh:   rec.PriorityHigh,
+		PriorityMedium: rec.PriorityMedium,
+		PriorityLow:    rec.PriorityLow,
+		Due:            rec.Due,
+		Age:            rec.Age,
+		AgeMaxDays:     rec.AgeMaxDays,
+		Blocked:        rec.Blocked,
+		Active:         rec.Active,
+		Tags:           rec.Tags,
+		TagBoosts:      rec.TagBoosts,
+	}, nil
+}
+
+// Save 保存（覆盖）用户的紧急度系数
+func (r *UrgencySettingsRepositoryImpl) Save(ctx context.Context, userID string, c model.UrgencyCoefficients) error {
+	raw, err := json.Marshal(urgencyRecord{
+		PriorityHigh:   c.PriorityHigh,
+		PriorityMedium: c.PriorityMedium,
+		PriorityLow:    c.PriorityLow,
+		Due:            c.Due,
+		Age:            c.Age,
+		AgeMaxDays:     c.AgeMaxDays,
+		Blocked:        c.Blocked,
+		Active:         c.Active,
+		Tags:           c.Tags,
+		TagBoosts:      c.TagBoosts,
+	})
+	if err != nil {
+		return fmt.Errorf("encode urgency settings failed: %w", err)
+	}
+
+	now := time.Now()
+	query, args, err := r.dialect.Insert("urgency_settings").
+		Rows(goqu.Record{
+			"user_id":      userID,
+			"coefficients": string(raw),
+			"updated_at":   now,
+		}).
+		OnConflict(goqu.DoUpdate("user_id", goqu.Record{
+			"coefficients": string(raw),
+			"updated_at":   now,
+		})).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build upsert urgency settings query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("save urgency settings failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUrgencySettingsRepository_Get 测试获取紧急度系数
+func TestUrgencySettingsRepository_Get(t *testing.T) {
+	t.Run("未自定义时返回 nil", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewUrgencySettingsRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT "coefficients" FROM "urgency_settings" WHERE \("user_id" = 'user-123'\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"coefficients"}))
+
+		c, err := repo.Get(context.Background(), "user-123")
+
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("缺失字段使用默认值", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		repo := NewUrgencySettingsRepository(db, "postgres")
+		mock.ExpectQuery(`SELECT "coefficients" FROM "urgency_settings"`).
+			WillReturnRows(sqlmock.NewRows([]string{"coefficients"}).
+				AddRow(`{"due":20,"tag_boosts":{"focus":3}}`))
+
+		c, err := repo.Get(context.Background(), "user-123")
+
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		defaults := model.DefaultUrgencyCoefficients()
+		assert.Equal(t, 20.0, c.Due)
+		assert.Equal(t, defaults.PriorityHigh, c.PriorityHigh)
+		assert.Equal(t, defaults.AgeMaxDays, c.AgeMaxDays)
+		assert.Equal(t, map[string]float64{"focus": 3}, c.TagBoosts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestUrgencySettingsRepository_Save 测试保存紧急度系数（upsert）
+func TestUrgencySettingsRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUrgencySettingsRepository(db, "postgres")
+	mock.ExpectExec(`INSERT INTO "urgency_settings" .+ ON CONFLICT \(user_id\) DO UPDATE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Save(context.Background(), "user-123", model.DefaultUrgencyCoefficients())
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,137 @@
+package retriever
+
+import (
+	"strings"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+)
+
+// intent 从问题中识别的结构化条件（字段为 nil 表示不限制）
+type intent struct {
+	dueFrom  *time.Time
+	dueTo    *time.Time
+	priority *model.Priority
+	status   *model.TaskStatus
+}
+
+// empty 是否没有识别出任何条件
+func (i intent) empty() bool {
+	return i.dueFrom == nil && i.dueTo == nil && i.priority == nil && i.status == nil
+}
+
+// timeRange 时间范围关键词（from 为 nil 时包括已逾期的任务）
+type timeRange struct {
+	keywords []string
+	bounds   func(now time.Time) (from, to *time.Time)
+}
+
+// timeRanges 按顺序匹配，使用第一个匹配的范围（"下周" 先于 "本周"，"明天" 先于 "今天"）
+var timeRanges = []timeRange{
+	{
+		keywords: []string{"逾期", "过期", "overdue", "past due"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			return nil, &now
+		},
+	},
+	{
+		keywords: []string{"下周", "下个星期", "下星期", "next week"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			from := startOfWeek(now).AddDate(0, 0, 7)
+			to := from.AddDate(0, 0, 7).Add(-time.Second)
+			return &from, &to
+		},
+	},
+	{
+		keywords: []string{"明天", "tomorrow"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			from := startOfDay(now).AddDate(0, 0, 1)
+			to := from.AddDate(0, 0, 1).Add(-time.Second)
+			return &from, &to
+		},
+	},
+	{
+		keywords: []string{"今天", "今日", "today", "tonight"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			to := startOfDay(now).AddDate(0, 0, 1).Add(-time.Second)
+			return nil, &to
+		},
+	},
+	{
+		keywords: []string{"本周", "这周", "这个星期", "这星期", "this week"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			to := startOfWeek(now).AddDate(0, 0, 7).Add(-time.Second)
+			return nil, &to
+		},
+	},
+	{
+		keywords: []string{"本月", "这个月", "this month"},
+		bounds: func(now time.Time) (*time.Time, *time.Time) {
+			day := startOfDay(now)
+			to := day.AddDate(0, 1, 1-day.Day()).Add(-time.Second)
+			return nil, &to
+		},
+	},
+}
+
+// priorityKeywords 优先级关键词（先匹配低优先级："不紧急" 包含 "紧急"）
+var priorityKeywords = map[model.Priority][]string{
+	model.PriorityHigh: {"高优先级", "紧急", "重要", "high priority", "high-priority", "urgent", "important"},
+	model.PriorityLow:  {"低优先级", "不急", "不紧急", "不重要", "low priority", "low-priority"},
+}
+
+// statusKeywords 状态关键词
+var statusKeywords = map[model.TaskStatus][]string{
+	model.StatusCompleted:  {"已完成", "做完了", "完成了哪些", "completed tasks", "finished tasks", "have i finished", "have i completed", "did i finish", "did i complete"},
+	model.StatusInProgress: {"进行中", "正在做", "in progress", "in-progress", "working on"},
+}
+
+// parseIntent 从问题中识别时间范围、优先级和状态（按 now 所在时区计算日期）
+//
+// 只做关键词匹配；识别不出条件时检索以语义相似度为主。
+func parseIntent(query string, now time.Time) intent {
+	text := strings.ToLower(query)
+	var in intent
+
+	for _, tr := range timeRanges {
+		if containsAny(text, tr.keywords) {
+			in.dueFrom, in.dueTo = tr.bounds(now)
+			break
+		}
+	}
+	for _, priority := range []model.Priority{model.PriorityLow, model.PriorityHigh} {
+		if containsAny(text, priorityKeywords[priority]) {
+			in.priority = &priority
+			break
+		}
+	}
+	for _, status := range []model.TaskStatus{model.StatusCompleted, model.StatusInProgress} {
+		if containsAny(text, statusKeywords[status]) {
+			in.status = &status
+			break
+		}
+	}
+	return in
+}
+
+// containsAny text 是否包含任一关键词
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// startOfDay 当天 0 点
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek 本周一 0 点
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // 周一为 0
+	return startOfDay(t).AddDate(0, 0, -offset)
+}
@@ -0,0 +1,73 @@
+package retriever
+
+import (
+	"testing"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntent(t *testing.T) {
+	// 2026-10-14 是周三
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(2026, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	t.Run("本周（包括逾期）", func(t *testing.T) {
+		in := parseIntent("What's on my plate this week?", now)
+		assert.Nil(t, in.dueFrom)
+		require.NotNil(t, in.dueTo)
+		assert.Equal(t, at(10, 18, 23, 59, 59), *in.dueTo)
+		assert.Nil(t, in.status)
+	})
+
+	t.Run("下周", func(t *testing.T) {
+		in := parseIntent("下周有什么要做的", now)
+		require.NotNil(t, in.dueFrom)
+		assert.Equal(t, at(10, 19, 0, 0, 0), *in.dueFrom)
+		assert.Equal(t, at(10, 25, 23, 59, 59), *in.dueTo)
+	})
+
+	t.Run("今天和明天", func(t *testing.T) {
+		today := parseIntent("今天要交什么", now)
+		assert.Nil(t, today.dueFrom)
+		assert.Equal(t, at(10, 14, 23, 59, 59), *today.dueTo)
+
+		tomorrow := parseIntent("anything due tomorrow?", now)
+		assert.Equal(t, at(10, 15, 0, 0, 0), *tomorrow.dueFrom)
+		assert.Equal(t, at(10, 15, 23, 59, 59), *tomorrow.dueTo)
+	})
+
+	t.Run("本月", func(t *testing.T) {
+		in := parseIntent("这个月的任务", now)
+		assert.Equal(t, at(10, 31, 23, 59, 59), *in.dueTo)
+	})
+
+	t.Run("逾期", func(t *testing.T) {
+		in := parseIntent("哪些任务逾期了", now)
+		assert.Equal(t, now, *in.dueTo)
+	})
+
+	t.Run("优先级和状态", func(t *testing.T) {
+		high := parseIntent("有哪些紧急的任务正在做", now)
+		assert.Equal(t, model.PriorityHigh, *high.priority)
+		assert.Equal(t, model.StatusInProgress, *high.status)
+
+		low := parseIntent("不紧急的任务", now)
+		assert.Equal(t, model.PriorityLow, *low.priority)
+
+		done := parseIntent("Which tasks did I finish this week?", now)
+		assert.Equal(t, model.StatusCompleted, *done.status)
+
+		// "done" 不表示已完成
+		todo := parseIntent("What needs to be done?", now)
+		assert.True(t, todo.empty())
+	})
+
+	t.Run("没有条件", func(t *testing.T) {
+		assert.True(t, parseIntent("牙医的预约是哪天", now).empty())
+	})
+}
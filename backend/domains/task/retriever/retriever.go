@@ -0,0 +1,187 @@
+package retriever
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// Source 任务资料的类型（引用标记为 [task:<任务 ID>]）
+const Source = "task"
+
+// maxCandidates 按结构化条件最多加载的任务数（排除已完成的任务前）
+const maxCandidates = 100
+
+// maxDescriptionLength 资料中任务描述的最大长度（字符），避免一个任务占满 Token 预算
+const maxDescriptionLength = 300
+
+// TaskRetriever 任务检索（实现 retrieval.Retriever，供 Chat 领域基于资料回答）
+//
+// 结合两种方式：
+//   - 结构化条件：从问题中识别时间范围（今天、本周、逾期等）、优先级和状态，按截止时间列出任务
+//   - 语义相似度：SemanticSearchService 按问题的含义搜索任务
+//
+// 识别出结构化条件时条件匹配的任务在前；否则语义搜索的结果在前，用未完成的任务（按截止时间）补充。
+// 没有指定状态时不包括已完成的任务（语义搜索的结果除外）。所有查询都经过 TaskService，只返回该用户的任务。
+type TaskRetriever struct {
+	taskService *service.TaskService
+	semantic    *service.SemanticSearchService
+	now         func() time.Time
+}
+
+// New 创建任务检索
+//
+// 参数：
+//   - taskService: 任务领域服务（按结构化条件列出任务）
+//   - semantic: 语义搜索（可为 nil，只使用结构化条件）
+func New(taskService *service.TaskService, semantic *service.SemanticSearchService) *TaskRetriever {
+	return &TaskRetriever{
+		taskService: taskService,
+		semantic:    semantic,
+		now:         time.Now,
+	}
+}
+
+// Retrieve 检索与问题相关的任务（最多 limit 个）
+//
+// 语义搜索失败时只记录日志，使用结构化条件的结果。
+func (r *TaskRetriever) Retrieve(ctx context.Context, userID, query string, limit int) ([]retrieval.Document, error) {
+	in := parseIntent(query, r.now())
+
+	listed, err := r.list(ctx, userID, in, limit)
+	if err != nil {
+		return nil, err
+	}
+	similar := r.search(ctx, userID, query, limit)
+
+	if in.empty() {
+		return merge(limit, similar, listed), nil
+	}
+	return merge(limit, listed, similar), nil
+}
+
+// list 按结构化条件列出任务（按截止时间升序，没有截止时间的在最后）
+func (r *TaskRetriever) list(ctx context.Context, userID string, in intent, limit int) ([]retrieval.Document, error) {
+	filter := repository.NewTaskFilter()
+	filter.UserID = &userID
+	filter.Status = in.status
+	filter.Priority = in.priority
+	if in.dueFrom != nil {
+		from := in.dueFrom.Format(time.RFC3339)
+		filter.DueDateFrom = &from
+	}
+	if in.dueTo != nil {
+		to := in.dueTo.Format(time.RFC3339)
+		filter.DueDateTo = &to
+	}
+	filter.SortBy = "due_date"
+	filter.SortOrder = "asc"
+	filter.Limit = maxCandidates
+
+	output, err := r.taskService.ListTasks(ctx, service.ListTasksInput{Filter: *filter})
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]retrieval.Document, 0, min(limit, len(output.Tasks)))
+	for _, task := range output.Tasks {
+		if len(docs) >= limit {
+			break
+		}
+		if in.status == nil && task.Status == model.StatusCompleted {
+			continue
+		}
+		docs = append(docs, toDocument(task, 1))
+	}
+	return docs, nil
+}
+
+// search 语义搜索（未配置或失败时返回空）
+func (r *TaskRetriever) search(ctx context.Context, userID, query string, limit int) []retrieval.Document {
+	if r.semantic == nil {
+		return nil
+	}
+	if utf8.RuneCountInString(query) > service.MaxSemanticQueryLength {
+		query = string([]rune(query)[:service.MaxSemanticQueryLength])
+	}
+
+	output, err := r.semantic.SemanticSearch(ctx, service.SemanticSearchInput{
+		UserID: userID,
+		Query:  query,
+		Limit:  limit,
+	})
+	if err != nil {
+		logger.Warn("task retrieval semantic search failed", zap.Error(err))
+		return nil
+	}
+	docs := make([]retrieval.Document, 0, len(output.Results))
+	for _, result := range output.Results {
+		docs = append(docs, toDocument(result.Task, result.Score))
+	}
+	return docs
+}
+
+// merge 按顺序合并资料并去重，最多 limit 个
+func merge(limit int, lists ...[]retrieval.Document) []retrieval.Document {
+	merged := make([]retrieval.Document, 0, limit)
+	seen := make(map[string]bool)
+	for _, docs := range lists {
+		for _, doc := range docs {
+			if len(merged) >= limit {
+				return merged
+			}
+			if seen[doc.ID] {
+				continue
+			}
+			seen[doc.ID] = true
+			merged = append(merged, doc)
+		}
+	}
+	return merged
+}
+
+// toDocument 将任务转换为资料（第二行为状态、优先级、截止时间和标签，之后为描述）
+func toDocument(task *model.Task, score float64) retrieval.Document {
+	fields := []string{
+		"状态：" + string(task.Status),
+		"优先级：" + string(task.Priority),
+	}
+	if task.DueDate != nil {
+		fields = append(fields, "截止："+task.DueDate.Format(time.RFC3339))
+	}
+	if len(task.Tags) > 0 {
+		tags := make([]string, 0, len(task.Tags))
+		for _, tag := range task.Tags {
+			tags = append(tags, tag.Name)
+		}
+		fields = append(fields, "标签："+strings.Join(tags, ", "))
+	}
+	if task.ParentID != nil {
+		fields = append(fields, fmt.Sprintf("父任务：[%s:%s]", Source, *task.ParentID))
+	}
+
+	content := strings.Join(fields, "；")
+	if description := strings.TrimSpace(task.Description); description != "" {
+		if utf8.RuneCountInString(description) > maxDescriptionLength {
+			description = string([]rune(description)[:maxDescriptionLength]) + "…"
+		}
+		content += "\n" + description
+	}
+
+	return retrieval.Document{
+		Source:  Source,
+		ID:      task.ID,
+		Title:   task.Title,
+		Content: content,
+		Score:   score,
+	}
+}
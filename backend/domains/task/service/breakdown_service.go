@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// BreakdownPrompt 任务拆解使用的提示词名称（见 prompt/templates）
+const BreakdownPrompt = "task.breakdown"
+
+// BreakdownService 任务拆解领域服务
+//
+// 职责：
+// - 调用 LLM 把任务拆解为子任务建议（结构化输出，不保存）
+// - 用户修改、确认后，通过 TaskService.CreateTask 创建子任务
+//
+// 提示词从提示词注册表渲染（管理员可以发布新版本或回滚），
+// 模型调用经过 LLMService 的路由、额度和用量记录。
+type BreakdownService struct {
+	taskService *TaskService
+	llmService  *llmservice.LLMService
+	prompts     *promptservice.PromptService
+	txManager   persistence.TxManager
+	now         func() time.Time // 当前时间（测试时可替换）
+}
+
+// NewBreakdownService 创建任务拆解领域服务
+//
+// 参数：
+//   - taskService: 任务领域服务（读取任务、创建子任务）
+//   - llmService: LLM 服务（生成拆解建议）
+//   - prompts: 提示词注册表（渲染 task.breakdown）
+//   - txManager: 事务管理器（接受建议时所有子任务在同一事务中创建）
+func NewBreakdownService(taskService *TaskService, llmService *llmservice.LLMService, prompts *promptservice.PromptService, txManager persistence.TxManager) *BreakdownService {
+	return &BreakdownService{
+		taskService: taskService,
+		llmService:  llmService,
+		prompts:     prompts,
+		txManager:   txManager,
+		now:         time.Now,
+	}
+}
+
+// BreakdownTaskInput 拆解任务输入
+type BreakdownTaskInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	TaskID string
+}
+
+// BreakdownTaskOutput 拆解任务输出
+type BreakdownTaskOutput struct {
+	Task        *model.Task
+	Subtasks    []model.SubtaskProposal
+	Model       string         // 生成建议的模型
+	Usage       llmmodel.Usage // 所有调用（包括修正重试）的用量之和
+	GeneratedAt time.Time      // 生成时间（due_in_days 相对的日期）
+}
+
+// breakdownOutput 模型输出格式（同时用于生成 JSON Schema）
+type breakdownOutput struct {
+	Subtasks []breakdownItem `json:"subtasks" jsonschema:"minItems=1,maxItems=10"`
+}
+
+// breakdownItem 模型输出的子任务
+type breakdownItem struct {
+	Title       string `json:"title" description:"子任务标题，以动词开头" jsonschema:"minLength=1,maxLength=200"`
+	Description string `json:"description" description:"完成标准或补充说明，可以为空" jsonschema:"maxLength=1000"`
+	Priority    string `json:"priority" jsonschema:"enum=low|medium|high"`
+	DueInDays   *int   `json:"due_in_days" description:"相对今天的天数，0 表示今天，无法判断时为 null" jsonschema:"minimum=0,maximum=365"`
+}
+
+// BreakdownTask 生成子任务建议（用例实现）
+//
+// 对应 usecases.yaml 中的 BreakdownTask
+//
+// 步骤：
+//  1. GetTask - 获取任务并验证所有权
+//  2. CheckParent - 已完成的任务和子任务不能拆解（R7.4）
+//  3. RenderPrompt - 渲染 task.breakdown 提示词（标题、描述、截止日期、今天）
+//  4. Generate - 结构化输出，校验失败时由 LLMService 要求模型修正
+//  5. BuildProposals - 计算截止日期（不晚于父任务的截止日期）
+//
+// 建议不保存，用户通过 AcceptBreakdown 确认后才创建子任务。
+func (s *BreakdownService) BreakdownTask(ctx context.Context, input BreakdownTaskInput) (*BreakdownTaskOutput, error) {
+	// Step 1: GetTask
+	got, err := s.taskService.GetTask(ctx, GetTaskInput{UserID: input.UserID, TaskID: input.TaskID})
+	if err != nil {
+		return nil, err
+	}
+	task := got.Task
+
+	// Step 2: CheckParent（提前失败，避免无效的模型调用）
+	if err := task.CanBeParent(); err != nil {
+		return nil, err
+	}
+
+	// Step 3: RenderPrompt
+	now := s.now()
+	vars := map[string]string{
+		"title": task.Title,
+		"today": now.Format("2006-01-02"),
+	}
+	if task.Description != "" {
+		vars["description"] = task.Description
+	}
+	if task.DueDate != nil {
+		vars["due_date"] = task.DueDate.In(now.Location()).Format("2006-01-02")
+	}
+	rendered, err := s.prompts.Render(ctx, promptservice.RenderInput{Name: BreakdownPrompt, Variables: vars})
+	if err != nil {
+		return nil, fmt.Errorf("PROMPT_RENDER_FAILED: 渲染提示词失败: %w", err)
+	}
+	req := rendered.ChatRequest()
+	req.User = input.UserID
+
+	// Step 4: Generate
+	out, result, err := llmservice.CompleteAs[breakdownOutput](ctx, s.llmService, req, llmservice.StructuredOptions{Name: "task_breakdown"})
+	if err != nil {
+		if errors.Is(err, llmmodel.ErrQuotaExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("BREAKDOWN_FAILED: 生成子任务失败: %w", err)
+	}
+
+	// Step 5: BuildProposals
+	proposals := make([]model.SubtaskProposal, len(out.Subtasks))
+	for i, item := range out.Subtasks {
+		proposal := model.SubtaskProposal{
+			Title:       item.Title,
+			Description: item.Description,
+			Priority:    model.Priority(item.Priority),
+			DueInDays:   item.DueInDays,
+		}
+		if item.DueInDays != nil {
+			due := model.BreakdownDueDate(now, *item.DueInDays, task.DueDate)
+			proposal.DueDate = &due
+		}
+		proposals[i] = proposal
+	}
+
+	return &BreakdownTaskOutput{
+		Task:        task,
+		Subtasks:    proposals,
+		Model:       result.Response.Model,
+		Usage:       result.Usage,
+		GeneratedAt: now,
+	}, nil
+}
+
+// AcceptBreakdownInput 接受拆解建议输入
+type AcceptBreakdownInput struct {
+	UserID   string // 用户 ID（从 JWT 获取）
+	TaskID   string
+	Subtasks []CreateTaskInput // 用户确认（可能已修改）的子任务，UserID 和 ParentID 由服务设置
+}
+
+// AcceptBreakdownOutput 接受拆解建议输出
+type AcceptBreakdownOutput struct {
+	Task     *model.Task
+	Subtasks []*model.Task
+}
+
+// AcceptBreakdown 保存用户确认的子任务（用例实现）
+//
+// 对应 usecases.yaml 中的 AcceptBreakdown
+//
+// 步骤：
+//  1. ValidateInput - 至少一个、最多 20 个子任务
+//  2. GetTask - 获取父任务并验证所有权
+//  3. CreateSubtasks - 通过 TaskService.CreateTask 创建子任务（同一事务，任一失败全部回滚）
+//
+// 子任务与手动创建的任务遵循相同的业务规则（R1.x、R3.x、R7.4）。
+func (s *BreakdownService) AcceptBreakdown(ctx context.Context, input AcceptBreakdownInput) (*AcceptBreakdownOutput, error) {
+	// Step 1: ValidateInput
+	if len(input.Subtasks) == 0 {
+		return nil, model.ErrNoSubtasks
+	}
+	if len(input.Subtasks) > model.MaxTemplateSubtasks {
+		return nil, model.ErrTooManySubtasks
+	}
+
+	// Step 2: GetTask
+	got, err := s.taskService.GetTask(ctx, GetTaskInput{UserID: input.UserID, TaskID: input.TaskID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: CreateSubtasks
+	output := &AcceptBreakdownOutput{
+		Task:     got.Task,
+		Subtasks: make([]*model.Task, 0, len(input.Subtasks)),
+	}
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, sub := range input.Subtasks {
+			parentID := got.Task.ID
+			sub.UserID = input.UserID
+			sub.ParentID = &parentID
+			created, err := s.taskService.CreateTask(ctx, sub)
+			if err != nil {
+				return err
+			}
+			output.Subtasks = append(output.Subtasks, created.Task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Task breakdown accepted: %s (%d subtasks)", got.Task.ID, len(output.Subtasks))
+	return output, nil
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/schema"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// EnrichPrompt 自动建议使用的提示词名称（见 prompt/templates）
+const EnrichPrompt = "task.enrich"
+
+// DefaultEnrichmentQueueSize 等待生成建议的任务队列长度
+const DefaultEnrichmentQueueSize = 100
+
+// enrichmentTagLimit 提供给模型的候选标签数（按使用次数取前 N 个）
+const enrichmentTagLimit = 50
+
+// EnrichmentService 新任务的标签和优先级建议
+//
+// 职责：
+// - 订阅 task.created，把事件放入队列后立即返回（事件总线是同步的，不能阻塞创建任务）
+// - 后台调用 LLM，从用户已有的标签中选择标签并建议优先级，保存为待处理的建议
+// - 用户接受（全部或部分）或拒绝建议，并记录结果用于统计准确率
+//
+// 建议不会直接修改任务，只有用户接受后才更新标签和优先级（R11.x）。
+type EnrichmentService struct {
+	taskService    *TaskService
+	taskRepo       repository.TaskRepository
+	suggestionRepo repository.SuggestionRepository
+	llmService     *llmservice.LLMService
+	prompts        *promptservice.PromptService
+	txManager      persistence.TxManager
+	metrics        *enrichmentMetrics
+	queue          chan *events.TaskCreatedEvent
+	now            func() time.Time // 当前时间（测试时可替换）
+}
+
+// NewEnrichmentService 创建自动建议领域服务
+//
+// 参数：
+//   - taskService: 任务领域服务（读取和更新任务）
+//   - taskRepo: 任务仓储（读取用户已有的标签）
+//   - suggestionRepo: 建议仓储
+//   - llmService: LLM 服务（生成建议）
+//   - prompts: 提示词注册表（渲染 task.enrich）
+//   - txManager: 事务管理器（接受建议时更新任务和建议在同一事务中）
+//   - m: Prometheus 指标（为 nil 时不采集）
+func NewEnrichmentService(
+	taskService *TaskService,
+	taskRepo repository.TaskRepository,
+	suggestionRepo repository.SuggestionRepository,
+	llmService *llmservice.LLMService,
+	prompts *promptservice.PromptService,
+	txManager persistence.TxManager,
+	m *metrics.Metrics,
+) *EnrichmentService {
+	return &EnrichmentService{
+		taskService:    taskService,
+		taskRepo:       taskRepo,
+		suggestionRepo: suggestionRepo,
+		llmService:     llmService,
+		prompts:        prompts,
+		txManager:      txManager,
+		metrics:        newEnrichmentMetrics(m),
+		queue:          make(chan *events.TaskCreatedEvent, DefaultEnrichmentQueueSize),
+		now:            time.Now,
+	}
+}
+
+// HandleTaskCreated 处理 task.created 事件（订阅事件总线）
+//
+// 只入队不调用模型：CreateTask 可能在事务中，模型调用耗时较长。
+// 队列已满时丢弃事件并记录日志，任务不会有建议，不影响创建任务。
+func (s *EnrichmentService) HandleTaskCreated(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(*events.TaskCreatedEvent)
+	if !ok {
+		return fmt.Errorf("INVALID_EVENT: task.created 负载类型错误: %T", event.Payload())
+	}
+	if payload.ParentID != nil {
+		return nil // 子任务不生成建议
+	}
+
+	select {
+	case s.queue <- payload:
+	default:
+		logger.Warn("enrichment queue full, dropping task", zap.String("task_id", payload.TaskID))
+	}
+	return nil
+}
+
+// Start 在后台处理队列中的任务，ctx 取消时停止
+func (s *EnrichmentService) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-s.queue:
+				if _, err := s.Enrich(ctx, event); err != nil {
+					logger.Error("task enrichment failed",
+						zap.String("task_id", event.TaskID),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// enrichOutput 模型输出格式（tags 的候选值在调用时设置）
+type enrichOutput struct {
+	Tags     []string `json:"tags" description:"从已有标签中选择，没有合适的标签时为空数组" jsonschema:"maxItems=3"`
+	Priority string   `json:"priority" jsonschema:"enum=low|medium|high"`
+}
+
+// Enrich 为新任务生成建议（用例实现）
+//
+// 对应 usecases.yaml 中的 EnrichTask
+//
+// 步骤：
+//  1. GetTask - 读取任务当前状态（已删除、已完成或是子任务时跳过）
+//  2. ListTags - 读取用户已有的标签作为候选（去掉任务已有的标签）
+//  3. RenderPrompt - 渲染 task.enrich 提示词
+//  4. Generate - 结构化输出，tags 只能是候选标签（JSON Schema enum）
+//  5. SaveSuggestion - 保存为 pending（没有新标签且优先级不变时不保存）
+//
+// 返回 nil 表示没有生成建议。
+func (s *EnrichmentService) Enrich(ctx context.Context, event *events.TaskCreatedEvent) (*model.TaskSuggestion, error) {
+	// Step 1: GetTask（事件在创建任务的事务提交后发布，读不到说明任务已被删除）
+	task, err := s.taskRepo.FindByID(ctx, event.TaskID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			logger.Info("task not found, skipping enrichment", zap.String("task_id", event.TaskID))
+			return nil, nil
+		}
+		return nil, err
+	}
+	if task.Status == model.StatusCompleted || task.IsSubtask() {
+		return nil, nil
+	}
+
+	// Step 2: ListTags
+	names, err := s.taskRepo.ListTagNames(ctx, task.UserID, enrichmentTagLimit)
+	if err != nil {
+		return nil, err
+	}
+	own := make(map[string]bool, len(task.Tags))
+	for _, tag := range task.Tags {
+		own[tag.Name] = true
+	}
+	candidates := make([]string, 0, len(names))
+	for _, name := range names {
+		if !own[name] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	// Step 3: RenderPrompt
+	now := s.now()
+	vars := map[string]string{
+		"title":    task.Title,
+		"priority": string(task.Priority),
+		"today":    now.Format("2006-01-02"),
+	}
+	if task.Description != "" {
+		vars["description"] = task.Description
+	}
+	if task.DueDate != nil {
+		vars["due_date"] = task.DueDate.In(now.Location()).Format("2006-01-02")
+	}
+	if len(candidates) > 0 {
+		vars["tags"] = strings.Join(candidates, ", ")
+	}
+	rendered, err := s.prompts.Render(ctx, promptservice.RenderInput{Name: EnrichPrompt, Variables: vars})
+	if err != nil {
+		return nil, fmt.Errorf("PROMPT_RENDER_FAILED: 渲染提示词失败: %w", err)
+	}
+	req := rendered.ChatRequest()
+	req.User = task.UserID
+
+	// Step 4: Generate
+	sch, err := enrichSchema(candidates)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.llmService.CompleteStructured(ctx, req, sch, llmservice.StructuredOptions{Name: "task_enrichment"})
+	if err != nil {
+		if errors.Is(err, llmmodel.ErrQuotaExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ENRICHMENT_FAILED: 生成建议失败: %w", err)
+	}
+	var out enrichOutput
+	if err := json.Unmarshal(result.Output, &out); err != nil {
+		return nil, fmt.Errorf("ENRICHMENT_FAILED: 解析建议失败: %w", err)
+	}
+
+	// Step 5: SaveSuggestion
+	suggestion, err := model.NewTaskSuggestion(task, out.Tags, model.Priority(out.Priority), result.Response.Model)
+	if err != nil || suggestion == nil {
+		return nil, err
+	}
+	created, err := s.suggestionRepo.Create(ctx, suggestion)
+	if err != nil {
+		return nil, fmt.Errorf("SUGGESTION_SAVE_FAILED: 保存建议失败: %w", err)
+	}
+	if !created {
+		return nil, nil // 事件重复投递，已有建议
+	}
+
+	s.metrics.generated()
+	log.Printf("Task suggestion created: %s (%d tags, priority %s)", task.ID, len(suggestion.Tags), suggestion.Priority)
+	return suggestion, nil
+}
+
+// enrichSchema 生成模型输出的 JSON Schema（tags 只能是候选标签，没有候选时必须为空）
+func enrichSchema(candidates []string) (*schema.Schema, error) {
+	sch, err := schema.For[enrichOutput]()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", llmmodel.ErrInvalidSchema, err.Error())
+	}
+	tags := sch.Properties["tags"]
+	if len(candidates) == 0 {
+		zero := 0
+		tags.MaxItems = &zero
+		return sch, nil
+	}
+	tags.Items.Enum = make([]any, len(candidates))
+	for i, name := range candidates {
+		tags.Items.Enum[i] = name
+	}
+	return sch, nil
+}
+
+// GetSuggestionInput 获取建议输入
+type GetSuggestionInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	TaskID string
+}
+
+// GetSuggestion 获取任务的建议（用例实现）
+//
+// 对应 usecases.yaml 中的 GetSuggestion
+//
+// 任务没有建议时返回 SUGGESTION_NOT_FOUND（未开启、还在生成或没有新内容）。
+func (s *EnrichmentService) GetSuggestion(ctx context.Context, input GetSuggestionInput) (*model.TaskSuggestion, error) {
+	if _, err := s.taskService.GetTask(ctx, GetTaskInput{UserID: input.UserID, TaskID: input.TaskID}); err != nil {
+		return nil, err
+	}
+	return s.suggestionRepo.FindByTaskID(ctx, input.TaskID)
+}
+
+// AcceptSuggestionInput 接受建议输入
+type AcceptSuggestionInput struct {
+	UserID        string // 用户 ID（从 JWT 获取）
+	TaskID        string
+	Tags          []string // 接受的标签（nil 表示接受全部建议的标签）
+	ApplyPriority *bool    // 是否接受建议的优先级（nil 表示接受）
+}
+
+// AcceptSuggestionOutput 接受建议输出
+type AcceptSuggestionOutput struct {
+	Task       *model.Task
+	Suggestion *model.TaskSuggestion
+}
+
+// AcceptSuggestion 接受建议（用例实现）
+//
+// 对应 usecases.yaml 中的 AcceptSuggestion
+//
+// 步骤：
+//  1. GetSuggestion - 验证任务所有权并读取建议
+//  2. Accept - 只能接受建议中的标签（R11.2）
+//  3. ApplyToTask - 追加接受的标签、更新优先级，与建议状态在同一事务中保存
+//  4. RecordMetrics - 按标签和优先级分别记录接受/拒绝
+func (s *EnrichmentService) AcceptSuggestion(ctx context.Context, input AcceptSuggestionInput) (*AcceptSuggestionOutput, error) {
+	// Step 1: GetSuggestion
+	got, err := s.taskService.GetTask(ctx, GetTaskInput{UserID: input.UserID, TaskID: input.TaskID})
+	if err != nil {
+		return nil, err
+	}
+	suggestion, err := s.suggestionRepo.FindByTaskID(ctx, input.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: Accept
+	tags := input.Tags
+	if tags == nil {
+		tags = suggestion.Tags
+	}
+	applyPriority := input.ApplyPriority == nil || *input.ApplyPriority
+	if err := suggestion.Accept(tags, applyPriority, s.now()); err != nil {
+		return nil, err
+	}
+
+	// Step 3: ApplyToTask
+	update := UpdateTaskInput{UserID: input.UserID, TaskID: input.TaskID}
+	if len(suggestion.AcceptedTags) > 0 {
+		names := make([]string, 0, len(got.Task.Tags)+len(suggestion.AcceptedTags))
+		seen := make(map[string]bool, cap(names))
+		for _, tag := range got.Task.Tags {
+			seen[tag.Name] = true
+			names = append(names, tag.Name)
+		}
+		for _, name := range suggestion.AcceptedTags {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		update.Tags = names
+	}
+	if suggestion.PriorityAccepted {
+		priority := suggestion.Priority
+		update.Priority = &priority
+	}
+
+	var task *model.Task
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		updated, err := s.taskService.UpdateTask(ctx, update)
+		if err != nil {
+			return err
+		}
+		task = updated.Task
+		return s.suggestionRepo.Resolve(ctx, suggestion)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: RecordMetrics
+	s.metrics.resolved(suggestion)
+	log.Printf("Task suggestion accepted: %s (%d/%d tags, priority %t)",
+		task.ID, len(suggestion.AcceptedTags), len(suggestion.Tags), suggestion.PriorityAccepted)
+
+	return &AcceptSuggestionOutput{Task: task, Suggestion: suggestion}, nil
+}
+
+// RejectSuggestionInput 拒绝建议输入
+type RejectSuggestionInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	TaskID string
+}
+
+// RejectSuggestion 拒绝建议（用例实现）
+//
+// 对应 usecases.yaml 中的 RejectSuggestion
+//
+// 任务不变，建议的标签和优先级都记为拒绝。
+func (s *EnrichmentService) RejectSuggestion(ctx context.Context, input RejectSuggestionInput) (*model.TaskSuggestion, error) {
+	suggestion, err := s.GetSuggestion(ctx, GetSuggestionInput(input))
+	if err != nil {
+		return nil, err
+	}
+	if err := suggestion.Reject(s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.suggestionRepo.Resolve(ctx, suggestion); err != nil {
+		return nil, err
+	}
+
+	s.metrics.resolved(suggestion)
+	log.Printf("Task suggestion rejected: %s", input.TaskID)
+	return suggestion, nil
+}
+
+// enrichmentMetrics 建议相关的 Prometheus 指标
+type enrichmentMetrics struct {
+	suggestions *prometheus.CounterVec // task_suggestions_total{status}
+	decisions   *prometheus.CounterVec // task_suggestion_decisions_total{kind, decision}
+}
+
+// newEnrichmentMetrics 注册建议指标（m 为 nil 时不采集，返回 nil）
+func newEnrichmentMetrics(m *metrics.Metrics) *enrichmentMetrics {
+	if m == nil {
+		return nil
+	}
+	return &enrichmentMetrics{
+		suggestions: m.NewCounterVec("task_suggestions_total", "Total task suggestions by status (pending on creation, accepted, rejected)", []string{"status"}),
+		decisions:   m.NewCounterVec("task_suggestion_decisions_total", "Suggested tags and priorities by user decision", []string{"kind", "decision"}),
+	}
+}
+
+// generated 记录一条新建议
+func (m *enrichmentMetrics) generated() {
+	if m == nil {
+		return
+	}
+	m.suggestions.WithLabelValues(string(model.SuggestionPending)).Inc()
+}
+
+// resolved 记录用户的处理结果（标签按个数，优先级只在建议了不同优先级时记录）
+func (m *enrichmentMetrics) resolved(s *model.TaskSuggestion) {
+	if m == nil {
+		return
+	}
+	m.suggestions.WithLabelValues(string(s.Status)).Inc()
+	m.decisions.WithLabelValues("tag", "accepted").Add(float64(len(s.AcceptedTags)))
+	m.decisions.WithLabelValues("tag", "rejected").Add(float64(len(s.Tags) - len(s.AcceptedTags)))
+	if s.PrioritySuggested() {
+		decision := "rejected"
+		if s.PriorityAccepted {
+			decision = "accepted"
+		}
+		m.decisions.WithLabelValues("priority", decision).Inc()
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/embedding"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/vectorstore"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// TaskEmbeddingCollection 任务向量在向量存储中的集合名称
+const TaskEmbeddingCollection = "tasks"
+
+// DefaultIndexQueueSize 等待生成向量的任务队列长度
+const DefaultIndexQueueSize = 1000
+
+// MaxSemanticQueryLength 语义搜索查询的最大长度（字符）
+const MaxSemanticQueryLength = 500
+
+// 语义搜索的数量限制
+const (
+	defaultSemanticSearchLimit = 10
+	maxSemanticSearchLimit     = 50
+)
+
+// indexJob 向量同步任务（按事件顺序处理）
+type indexJob struct {
+	taskID string
+	userID string
+	text   string // 生成向量的文本（delete 为 true 时为空）
+	delete bool
+}
+
+// SemanticSearchService 任务语义搜索
+//
+// 职责：
+// - 订阅 task.created / task.updated / task.deleted，把事件放入队列后立即返回
+// - 后台为任务的标题、描述和标签生成向量，保存到向量存储（删除任务时删除向量）
+// - 按查询文本的向量搜索用户的任务（余弦相似度降序）
+//
+// 向量由事件中的任务内容生成，不读取数据库（事件可能在创建任务的事务提交前发布）；
+// 事务回滚留下的向量在搜索时因找不到任务而被忽略。
+type SemanticSearchService struct {
+	taskRepo repository.TaskRepository
+	embedder embedding.Embedder
+	store    vectorstore.Store
+	queue    chan indexJob
+}
+
+// NewSemanticSearchService 创建语义搜索领域服务
+//
+// 参数：
+//   - taskRepo: 任务仓储（按搜索结果加载任务）
+//   - embedder: 向量嵌入（任务和查询使用同一个模型）
+//   - store: 向量存储
+func NewSemanticSearchService(taskRepo repository.TaskRepository, embedder embedding.Embedder, store vectorstore.Store) *SemanticSearchService {
+	return &SemanticSearchService{
+		taskRepo: taskRepo,
+		embedder: embedder,
+		store:    store,
+		queue:    make(chan indexJob, DefaultIndexQueueSize),
+	}
+}
+
+// HandleTaskCreated 处理 task.created 事件（订阅事件总线）
+func (s *SemanticSearchService) HandleTaskCreated(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(*events.TaskCreatedEvent)
+	if !ok {
+		return fmt.Errorf("INVALID_EVENT: task.created 负载类型错误: %T", event.Payload())
+	}
+	s.enqueue(indexJob{taskID: payload.TaskID, userID: payload.UserID, text: taskText(payload.Title, payload.Description, payload.Tags)})
+	return nil
+}
+
+// HandleTaskUpdated 处理 task.updated 事件（只有标题、描述或标签变更时重新生成向量）
+func (s *SemanticSearchService) HandleTaskUpdated(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(*events.TaskUpdatedEvent)
+	if !ok {
+		return fmt.Errorf("INVALID_EVENT: task.updated 负载类型错误: %T", event.Payload())
+	}
+	_, title := payload.UpdatedFields["title"]
+	_, description := payload.UpdatedFields["description"]
+	_, tags := payload.UpdatedFields["tags"]
+	if !title && !description && !tags {
+		return nil
+	}
+	s.enqueue(indexJob{taskID: payload.TaskID, userID: payload.UserID, text: taskText(payload.Title, payload.Description, payload.Tags)})
+	return nil
+}
+
+// HandleTaskDeleted 处理 task.deleted 事件（删除向量）
+func (s *SemanticSearchService) HandleTaskDeleted(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(*events.TaskDeletedEvent)
+	if !ok {
+		return fmt.Errorf("INVALID_EVENT: task.deleted 负载类型错误: %T", event.Payload())
+	}
+	s.enqueue(indexJob{taskID: payload.TaskID, userID: payload.UserID, delete: true})
+	return nil
+}
+
+// enqueue 放入队列（已满时丢弃并记录日志，任务在下次更新前搜索不到）
+func (s *SemanticSearchService) enqueue(job indexJob) {
+	select {
+	case s.queue <- job:
+	default:
+		logger.Warn("semantic index queue full, dropping task", zap.String("task_id", job.taskID))
+	}
+}
+
+// Start 在后台按顺序处理队列中的任务，ctx 取消时停止
+func (s *SemanticSearchService) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case job := <-s.queue:
+				if err := s.index(ctx, job); err != nil {
+					logger.Error("semantic index failed",
+						zap.String("task_id", job.taskID),
+						zap.Error(err),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// Drain 同步处理队列中已有的任务（测试和关闭前使用）
+func (s *SemanticSearchService) Drain(ctx context.Context) error {
+	for {
+		select {
+		case job := <-s.queue:
+			if err := s.index(ctx, job); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// index 生成并保存（或删除）任务的向量
+func (s *SemanticSearchService) index(ctx context.Context, job indexJob) error {
+	if job.delete {
+		return s.store.Delete(ctx, TaskEmbeddingCollection, job.taskID)
+	}
+
+	vectors, err := s.embedder.Embed(ctx, []string{job.text})
+	if err != nil {
+		return fmt.Errorf("EMBEDDING_FAILED: 生成向量失败: %w", err)
+	}
+	return s.store.Upsert(ctx, TaskEmbeddingCollection, vectorstore.Record{
+		ID:      job.taskID,
+		OwnerID: job.userID,
+		Model:   s.embedder.Model(),
+		Vector:  vectors[0],
+	})
+}
+
+// taskText 生成向量的任务文本
+func taskText(title, description string, tags []string) string {
+	text := title
+	if description != "" {
+		text += "\n" + description
+	}
+	if len(tags) > 0 {
+		text += "\n" + strings.Join(tags, ", ")
+	}
+	return text
+}
+
+// SemanticSearchInput 语义搜索输入
+type SemanticSearchInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	Query  string // 查询文本
+	Limit  int    // 最多返回的任务数（<= 0 使用默认值 10，最大 50）
+}
+
+// SemanticSearchResult 语义搜索结果
+type SemanticSearchResult struct {
+	Task  *model.Task
+	Score float64 // 余弦相似度
+}
+
+// SemanticSearchOutput 语义搜索输出
+type SemanticSearchOutput struct {
+	Results []SemanticSearchResult // 按相似度降序
+}
+
+// SemanticSearch 语义搜索任务（用例实现）
+//
+// 对应 usecases.yaml 中的 SemanticSearchTasks
+//
+// 步骤：
+//  1. ValidateInput - 查询不能为空，最长 500 个字符
+//  2. EmbedQuery - 生成查询向量
+//  3. SearchVectors - 在用户的任务向量中按余弦相似度搜索
+//  4. LoadTasks - 加载任务（包括已完成和推迟中的任务），忽略已不存在的任务
+//
+// 相似度不大于 0 的任务（没有任何相关性）不返回。
+func (s *SemanticSearchService) SemanticSearch(ctx context.Context, input SemanticSearchInput) (*SemanticSearchOutput, error) {
+	// Step 1: ValidateInput
+	if input.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+	query := strings.TrimSpace(input.Query)
+	if query == "" {
+		return nil, fmt.Errorf("INVALID_QUERY: 查询不能为空")
+	}
+	if utf8.RuneCountInString(query) > MaxSemanticQueryLength {
+		return nil, fmt.Errorf("INVALID_QUERY: 查询最长 %d 个字符", MaxSemanticQueryLength)
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSemanticSearchLimit
+	}
+	limit = min(limit, maxSemanticSearchLimit)
+
+	// Step 2: EmbedQuery
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("EMBEDDING_FAILED: 生成查询向量失败: %w", err)
+	}
+
+	// Step 3: SearchVectors
+	matches, err := s.store.Search(ctx, TaskEmbeddingCollection, vectorstore.Query{
+		OwnerID: input.UserID,
+		Model:   s.embedder.Model(),
+		Vector:  vectors[0],
+		Limit:   limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 搜索向量失败: %w", err)
+	}
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if m.Score > 0 {
+			ids = append(ids, m.ID)
+		}
+	}
+	output := &SemanticSearchOutput{Results: make([]SemanticSearchResult, 0, len(ids))}
+	if len(ids) == 0 {
+		return output, nil
+	}
+
+	// Step 4: LoadTasks（再次按用户筛选）
+	filter := repository.NewTaskFilter()
+	filter.UserID = &input.UserID
+	filter.IDs = ids
+	filter.IncludeSnoozed = true
+	filter.Limit = 0
+	tasks, _, err := s.taskRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询任务失败: %w", err)
+	}
+	byID := make(map[string]*model.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	for _, m := range matches {
+		if task, ok := byID[m.ID]; ok && m.Score > 0 {
+			output.Results = append(output.Results, SemanticSearchResult{Task: task, Score: m.Score})
+		}
+	}
+	return output, nil
+}
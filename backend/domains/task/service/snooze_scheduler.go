@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+	"go.uber.org/zap"
+)
+
+// DefaultSnoozeCheckInterval 默认的推迟到期检查间隔
+const DefaultSnoozeCheckInterval = time.Minute
+
+// snoozeBatchSize 每批处理的到期任务数
+const snoozeBatchSize = 100
+
+// SnoozeScheduler 推迟到期调度器
+//
+// 职责：
+// - 定期查找推迟已到期的任务（hidden_until <= now）
+// - 清除 hidden_until，并为每个任务发布一次 TaskResurfaced 事件
+//
+// 多实例部署时，ClearSnooze 是条件更新，只有清除成功的实例会发布事件，
+// 因此每次推迟到期只发布一次事件。
+//
+// 清除和发布在同一事务中：发布失败时回滚清除，下次检查时重试，事件不会丢失。
+// （发布成功但提交失败时会重复发布，订阅者需要按 task_id + hidden_until 幂等处理。）
+type SnoozeScheduler struct {
+	taskRepo  repository.TaskRepository
+	eventBus  sharedevents.EventBus
+	txManager persistence.TxManager
+	interval  time.Duration
+	now       func() time.Time // 当前时间（测试时可替换）
+}
+
+// NewSnoozeScheduler 创建推迟到期调度器
+//
+// 参数：
+//   - taskRepo: 任务仓储
+//   - eventBus: 事件总线（发布 task.resurfaced）
+//   - txManager: 事务管理器（清除 hidden_until 和发布事件在同一事务中）
+//   - interval: 检查间隔（<= 0 时使用 DefaultSnoozeCheckInterval）
+func NewSnoozeScheduler(taskRepo repository.TaskRepository, eventBus sharedevents.EventBus, txManager persistence.TxManager, interval time.Duration) *SnoozeScheduler {
+	if interval <= 0 {
+		interval = DefaultSnoozeCheckInterval
+	}
+	return &SnoozeScheduler{
+		taskRepo:  taskRepo,
+		eventBus:  eventBus,
+		txManager: txManager,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Start 在后台启动调度器，ctx 取消时停止
+func (s *SnoozeScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.RunOnce(ctx); err != nil {
+					logger.Error("SnoozeScheduler run failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce 处理当前所有推迟已到期的任务
+//
+// 返回发布的 TaskResurfaced 事件数量。
+// 单个事件发布失败只记录日志并回滚该任务的清除（下次检查时重试），不影响其他任务。
+func (s *SnoozeScheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	published := 0
+
+	for {
+		tasks, err := s.taskRepo.FindExpiredSnoozes(ctx, now, snoozeBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		clearedInBatch := 0
+		for _, task := range tasks {
+			hiddenUntil := *task.HiddenUntil
+
+			cleared, err := s.resurface(ctx, task, hiddenUntil, now)
+			if err != nil {
+				if errors.Is(err, errPublishResurfaced) {
+					logger.Error("publish TaskResurfaced failed",
+						zap.String("task_id", task.ID),
+						zap.Error(err),
+					)
+					continue
+				}
+				return published, err
+			}
+			if !cleared {
+				// 已被重新推迟、取消推迟或由其他实例处理
+				continue
+			}
+			clearedInBatch++
+			published++
+		}
+
+		// 最后一批，或者本批一个都没清除（避免重复查到同一批任务）
+		if len(tasks) < snoozeBatchSize || clearedInBatch == 0 {
+			break
+		}
+	}
+
+	if published > 0 {
+		log.Printf("Tasks resurfaced: %d", published)
+	}
+
+	return published, nil
+}
+
+// errPublishResurfaced 发布 TaskResurfaced 失败（事务已回滚）
+var errPublishResurfaced = errors.New("publish TaskResurfaced failed")
+
+// resurface 在同一事务中清除任务的推迟并发布 TaskResurfaced
+//
+// 返回 false 表示条件更新未命中（无需发布）。
+// 发布失败时返回 errPublishResurfaced，清除随事务回滚。
+func (s *SnoozeScheduler) resurface(ctx context.Context, task *model.Task, hiddenUntil, now time.Time) (bool, error) {
+	cleared := false
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		ok, err := s.taskRepo.ClearSnooze(ctx, task.ID, hiddenUntil)
+		if err != nil || !ok {
+			return err
+		}
+
+		task.HiddenUntil = nil
+		event := events.NewTaskResurfacedEvent(task, hiddenUntil, now)
+		if err := s.eventBus.Publish(ctx, events.ToBusEvent(event)); err != nil {
+			task.HiddenUntil = &hiddenUntil
+			return fmt.Errorf("%w: %v", errPublishResurfaced, err)
+		}
+		cleared = true
+		return nil
+	})
+	return cleared, err
+}
@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 	"go.uber.org/zap"
 )
 
@@ -25,8 +30,10 @@ import (
 // - Service：业务逻辑层，厚层，实现领域用例
 type TaskService struct {
 	taskRepo repository.TaskRepository
+	eventBus sharedevents.EventBus // 可选，为 nil 时不发布事件（见 WithEventBus）
+	// 可选，为 nil 时不审核任务文本（见 WithModeration）
+	moderation *moderation.Pipeline
 	// Extension point: 添加更多依赖
-	// eventBus events.EventBus
 	// cache    cache.Cache
 }
 
@@ -43,6 +50,18 @@ func NewTaskService(taskRepo repository.TaskRepository) *TaskService {
 	}
 }
 
+// WithEventBus 设置事件总线（创建、更新和删除任务后发布 task.created、task.updated、task.deleted）
+func (s *TaskService) WithEventBus(eventBus sharedevents.EventBus) *TaskService {
+	s.eventBus = eventBus
+	return s
+}
+
+// WithModeration 设置内容审核（创建和更新任务时检查标题和描述，R1.6）
+func (s *TaskService) WithModeration(p *moderation.Pipeline) *TaskService {
+	s.moderation = p
+	return s
+}
+
 // CreateTaskInput 创建任务输入（领域层 DTO）
 //
 // 与 HTTP DTO 的区别：
@@ -55,6 +74,7 @@ type CreateTaskInput struct {
 	Priority    model.Priority
 	DueDate     *time.Time
 	Tags        []string
+	ParentID    *string // 父任务 ID（可选，创建子任务时使用）
 }
 
 // CreateTaskOutput 创建任务输出
@@ -101,7 +121,7 @@ type GetTaskOutput struct {
 // 对应 usecases.yaml 中的 CreateTask
 //
 // 步骤：
-//  1. ValidateInput - 验证输入参数
+//  1. ValidateInput - 验证输入参数，标题和描述经过内容审核
 //  2. GenerateTaskID - 生成唯一的任务 ID
 //  3. CreateTaskEntity - 创建任务实体
 //  4. SaveTask - 保存任务到数据库
@@ -109,9 +129,11 @@ type GetTaskOutput struct {
 //
 // 业务规则（参考 rules.md）：
 // - 任务标题不能为空
+// - 标题和描述必须通过内容安全检查（个人信息等可能被替换）
 // - 优先级必须是 low/medium/high
 // - 截止日期不能早于当前时间
 // - 标签最多 10 个
+// - 父任务必须存在且属于同一用户，只支持一层子任务
 func (s *TaskService) CreateTask(ctx context.Context, input CreateTaskInput) (*CreateTaskOutput, error) {
 	// Step 1: ValidateInput - 业务规则验证
 	if input.UserID == "" {
@@ -120,6 +142,10 @@ func (s *TaskService) CreateTask(ctx context.Context, input CreateTaskInput) (*C
 	if input.Title == "" {
 		return nil, fmt.Errorf("TASK_TITLE_EMPTY: 任务标题不能为空")
 	}
+	title, description := input.Title, input.Description
+	if err := s.moderateText(ctx, input.UserID, &title, &description); err != nil {
+		return nil, err
+	}
 
 	// Step 2 & 3: CreateTaskEntity - 创建任务实体
 	priority := input.Priority
@@ -127,7 +153,7 @@ func (s *TaskService) CreateTask(ctx context.Context, input CreateTaskInput) (*C
 		priority = model.PriorityMedium // 默认优先级
 	}
 
-	task, err := model.NewTask(input.UserID, input.Title, input.Description, priority)
+	task, err := model.NewTask(input.UserID, title, description, priority)
 	if err != nil {
 		return nil, err // 直接返回 Model 层的错误（已经包含错误码）
 	}
@@ -154,19 +180,29 @@ func (s *TaskService) CreateTask(ctx context.Context, input CreateTaskInput) (*C
 		}
 	}
 
+	// 设置父任务（子任务）
+	if input.ParentID != nil && *input.ParentID != "" {
+		parent, err := s.taskRepo.FindByID(ctx, *input.ParentID)
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			return nil, fmt.Errorf("PARENT_TASK_NOT_FOUND: 父任务不存在")
+		}
+		if err != nil {
+			logger.Error("Find parent task failed", zap.String("parent_id", *input.ParentID), zap.Error(err))
+			return nil, fmt.Errorf("QUERY_FAILED: 查询失败")
+		}
+		// 归属、状态和层级规则由 Task.SetParent 检查（R7.4）
+		if err := task.SetParent(parent); err != nil {
+			return nil, err
+		}
+	}
+
 	// Step 4: SaveTask - 保存任务
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, fmt.Errorf("CREATION_FAILED: 保存任务失败: %w", err)
 	}
 
-	// Step 5: PublishTaskCreatedEvent
-	// Extension point: 发布事件到事件总线
-	// s.eventBus.Publish(ctx, &events.TaskCreatedEvent{
-	//     TaskID:    task.ID,
-	//     Title:     task.Title,
-	//     Priority:  string(task.Priority),
-	//     CreatedAt: task.CreatedAt,
-	// })
+	// Step 5: PublishTaskCreatedEvent（发布失败只记录日志，任务已创建）
+	s.publish(ctx, events.NewTaskCreatedEvent(task))
 	log.Printf("Task created: %s", task.ID)
 
 	return &CreateTaskOutput{Task: task}, nil
@@ -196,7 +232,7 @@ type UpdateTaskOutput struct {
 //  1. ValidateInput
 //  2. GetTask - 获取任务
 //  3. CheckIfCompleted - 检查任务是否已完成
-//  4. UpdateTaskFields - 更新任务字段
+//  4. UpdateTaskFields - 更新任务字段（标题和描述经过内容审核）
 //  5. SaveTask - 保存任务
 //  6. PublishTaskUpdatedEvent
 //
@@ -225,13 +261,27 @@ func (s *TaskService) UpdateTask(ctx context.Context, input UpdateTaskInput) (*U
 		return nil, fmt.Errorf("TASK_ALREADY_COMPLETED: 已完成的任务不能更新")
 	}
 
-	// Step 4: UpdateTaskFields
-	if input.Title != nil && *input.Title != "" {
-		task.Title = *input.Title
+	// Step 4: UpdateTaskFields（先审核文本，被拒绝时不修改任何字段）
+	var title, description string
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+	if err := s.moderateText(ctx, input.UserID, &title, &description); err != nil {
+		return nil, err
+	}
+
+	updatedFields := make(map[string]interface{})
+	if title != "" {
+		task.Title = title
+		updatedFields["title"] = task.Title
 	}
 
 	if input.Description != nil {
-		task.Description = *input.Description
+		task.Description = description
+		updatedFields["description"] = task.Description
 	}
 
 	if input.Priority != nil {
@@ -239,16 +289,19 @@ func (s *TaskService) UpdateTask(ctx context.Context, input UpdateTaskInput) (*U
 			return nil, fmt.Errorf("INVALID_PRIORITY: 优先级无效")
 		}
 		task.Priority = *input.Priority
+		updatedFields["priority"] = string(task.Priority)
 	}
 
 	if input.DueDate != nil {
 		if err := task.SetDueDate(*input.DueDate); err != nil {
 			return nil, fmt.Errorf("设置截止日期失败: %w", err)
 		}
+		updatedFields["due_date"] = task.DueDate.Format(time.RFC3339)
 	}
 
 	// 更新标签（如果提供）
 	if input.Tags != nil {
+		updatedFields["tags"] = input.Tags
 		// 清空现有标签
 		task.Tags = []model.Tag{}
 		// 添加新标签
@@ -269,7 +322,7 @@ func (s *TaskService) UpdateTask(ctx context.Context, input UpdateTaskInput) (*U
 	}
 
 	// Step 6: PublishTaskUpdatedEvent
-	// Extension point: 发布事件
+	s.publish(ctx, events.NewTaskUpdatedEvent(task, updatedFields))
 	log.Printf("Task updated: %s", task.ID)
 
 	return &UpdateTaskOutput{Task: task}, nil
@@ -350,10 +403,10 @@ func (s *TaskService) DeleteTask(ctx context.Context, input DeleteTaskInput) (*D
 	}
 
 	// Step 5: PublishTaskDeletedEvent
-	// Extension point: 发布事件
+	deletedAt := time.Now()
+	s.publish(ctx, events.NewTaskDeletedEvent(task, deletedAt))
 	log.Printf("Task deleted: %s", input.TaskID)
 
-	deletedAt := time.Now()
 	return &DeleteTaskOutput{
 		Success:   true,
 		DeletedAt: deletedAt,
@@ -395,6 +448,8 @@ type ListTasksOutput struct {
 	Page       int
 	Limit      int
 	HasMore    bool
+	Truncated  bool               // 按紧急度排序时任务超过候选上限，只有候选任务参与排序和分页
+	Urgency    map[string]float64 // 任务 ID → 紧急度（仅按紧急度排序时计算）
 }
 
 // ListTasks 列出任务（用例实现）
@@ -423,7 +478,147 @@ func (s *TaskService) ListTasks(ctx context.Context, input ListTasksInput) (*Lis
 	}, nil
 }
 
+// SnoozeTaskInput 推迟任务输入
+type SnoozeTaskInput struct {
+	UserID string    // 用户 ID（从 JWT 获取）
+	TaskID string    // 任务 ID
+	Until  time.Time // 推迟到的时间
+}
+
+// SnoozeTaskOutput 推迟任务输出
+type SnoozeTaskOutput struct {
+	Task *model.Task
+}
+
+// UnsnoozeTaskInput 取消推迟输入
+type UnsnoozeTaskInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	TaskID string // 任务 ID
+}
+
+// UnsnoozeTaskOutput 取消推迟输出
+type UnsnoozeTaskOutput struct {
+	Task *model.Task
+}
+
+// SnoozeTask 推迟任务（用例实现）
+//
+// 对应 usecases.yaml 中的 SnoozeTask
+//
+// 步骤：
+//  1. ValidateUserID
+//  2. GetTask
+//  3. CheckOwnership
+//  4. SetHiddenUntil - 设置 hidden_until（不修改截止日期）
+//  5. SaveTask
+//
+// 业务规则：
+// - 已完成的任务不能推迟
+// - 推迟时间必须晚于当前时间
+func (s *TaskService) SnoozeTask(ctx context.Context, input SnoozeTaskInput) (*SnoozeTaskOutput, error) {
+	// Step 1: ValidateUserID
+	if input.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	// Step 2: GetTask
+	task, err := s.taskRepo.FindByID(ctx, input.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("TASK_NOT_FOUND: 任务不存在")
+	}
+
+	// Step 3: CheckOwnership
+	if task.UserID != input.UserID {
+		return nil, fmt.Errorf("UNAUTHORIZED_ACCESS: 无权访问此任务")
+	}
+
+	// Step 4: SetHiddenUntil
+	if err := task.Snooze(input.Until, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Step 5: SaveTask
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 推迟任务失败")
+	}
+
+	log.Printf("Task snoozed: %s until %s", task.ID, input.Until.Format(time.RFC3339))
+
+	return &SnoozeTaskOutput{Task: task}, nil
+}
+
+// UnsnoozeTask 取消推迟（用例实现）
+//
+// 对应 usecases.yaml 中的 UnsnoozeTask
+//
+// 手动取消推迟不会发布 TaskResurfaced 事件（事件只在推迟自然到期时发布）。
+func (s *TaskService) UnsnoozeTask(ctx context.Context, input UnsnoozeTaskInput) (*UnsnoozeTaskOutput, error) {
+	// Step 1: ValidateUserID
+	if input.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	// Step 2: GetTask
+	task, err := s.taskRepo.FindByID(ctx, input.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("TASK_NOT_FOUND: 任务不存在")
+	}
+
+	// Step 3: CheckOwnership
+	if task.UserID != input.UserID {
+		return nil, fmt.Errorf("UNAUTHORIZED_ACCESS: 无权访问此任务")
+	}
+
+	// Step 4: ClearHiddenUntil
+	if err := task.Unsnooze(time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Step 5: SaveTask
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 取消推迟失败")
+	}
+
+	log.Printf("Task unsnoozed: %s", task.ID)
+
+	return &UnsnoozeTaskOutput{Task: task}, nil
+}
+
 // isValidPriority 验证优先级是否有效
 func isValidPriority(p model.Priority) bool {
 	return p == model.PriorityLow || p == model.PriorityMedium || p == model.PriorityHigh
 }
+
+// moderationSource 审核任务文本时的内容来源（记录在 AlertTriggered 事件中）
+const moderationSource = "task"
+
+// moderateText 审核任务文本（R1.6）：block 时返回 CONTENT_BLOCKED，redact 时替换为审核后的文本
+func (s *TaskService) moderateText(ctx context.Context, userID string, texts ...*string) error {
+	for _, text := range texts {
+		if *text == "" {
+			continue
+		}
+		decision := s.moderation.Moderate(ctx, moderation.Input{Source: moderationSource, UserID: userID, Text: *text})
+		if decision.Blocked() {
+			return moderation.ErrContentBlocked
+		}
+		*text = decision.Text
+	}
+	return nil
+}
+
+// publish 发布领域事件（未设置事件总线时跳过，发布失败只记录日志）
+func (s *TaskService) publish(ctx context.Context, event events.DomainEvent) {
+	if s.eventBus == nil {
+		return
+	}
+	// 在事务中（模板实例化、拆解任务）时等提交后再发布，订阅者才能读到任务
+	persistence.AfterCommit(ctx, func(ctx context.Context) {
+		if err := s.eventBus.Publish(ctx, events.ToBusEvent(event)); err != nil {
+			logger.Error("publish task event failed",
+				zap.String("type", event.Type()),
+				zap.Error(err),
+			)
+		}
+	})
+}
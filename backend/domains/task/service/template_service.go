@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// TemplateService 任务模板领域服务
+//
+// 职责：
+// - 管理用户的任务模板（增删改查）
+// - 将模板实例化为具体任务（含子任务）
+//
+// 实例化不直接写 tasks 表，而是通过 TaskService.CreateTask 创建任务，
+// 保证模板生成的任务与手动创建的任务遵循相同的业务规则。
+// 主任务和子任务在同一个工作单元（事务）中创建，任一失败全部回滚。
+type TemplateService struct {
+	templateRepo repository.TemplateRepository
+	taskService  *TaskService
+	txManager    persistence.TxManager
+	now          func() time.Time // 当前时间（测试时可替换）
+}
+
+// NewTemplateService 创建任务模板领域服务
+//
+// 参数：
+//   - templateRepo: 模板仓储
+//   - taskService: 任务领域服务（用于实例化任务）
+//   - txManager: 事务管理器（实例化时主任务和子任务在同一事务中创建）
+func NewTemplateService(templateRepo repository.TemplateRepository, taskService *TaskService, txManager persistence.TxManager) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+		taskService:  taskService,
+		txManager:    txManager,
+		now:          time.Now,
+	}
+}
+
+// TemplateInput 创建/更新模板输入
+type TemplateInput struct {
+	UserID       string // 用户 ID（从 JWT 获取）
+	TemplateID   string // 更新时使用
+	Name         string
+	TitlePattern string
+	Description  string
+	Priority     model.Priority
+	Tags         []string
+	DueOffset    *time.Duration
+	Subtasks     []model.SubtaskTemplate
+}
+
+// TemplateOutput 模板输出
+type TemplateOutput struct {
+	Template *model.TaskTemplate
+}
+
+// GetTemplateInput 获取/删除模板输入
+type GetTemplateInput struct {
+	UserID     string // 用户 ID（从 JWT 获取）
+	TemplateID string
+}
+
+// ListTemplatesOutput 列出模板输出
+type ListTemplatesOutput struct {
+	Templates []*model.TaskTemplate
+}
+
+// InstantiateTemplateInput 实例化模板输入
+type InstantiateTemplateInput struct {
+	UserID     string            // 用户 ID（从 JWT 获取）
+	TemplateID string            // 模板 ID
+	Variables  map[string]string // 自定义变量（如 name）
+}
+
+// InstantiateTemplateOutput 实例化模板输出
+type InstantiateTemplateOutput struct {
+	Task     *model.Task   // 根据模板创建的任务
+	Subtasks []*model.Task // 根据模板子任务定义创建的子任务
+}
+
+// CreateTemplate 创建模板（用例实现）
+//
+// 对应 usecases.yaml 中的 CreateTemplate
+func (s *TemplateService) CreateTemplate(ctx context.Context, input TemplateInput) (*TemplateOutput, error) {
+	// Step 1: CreateTemplateEntity - 创建模板实体（含验证）
+	tpl, err := model.NewTaskTemplate(input.UserID, input.Name, input.TitlePattern, input.Description, input.Priority)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTemplateDetails(tpl, input); err != nil {
+		return nil, err
+	}
+
+	// Step 2: SaveTemplate
+	if err := s.templateRepo.Create(ctx, tpl); err != nil {
+		return nil, fmt.Errorf("CREATION_FAILED: 保存模板失败: %w", err)
+	}
+
+	log.Printf("Task template created: %s", tpl.ID)
+	return &TemplateOutput{Template: tpl}, nil
+}
+
+// UpdateTemplate 更新模板（用例实现，整体替换）
+//
+// 对应 usecases.yaml 中的 UpdateTemplate
+func (s *TemplateService) UpdateTemplate(ctx context.Context, input TemplateInput) (*TemplateOutput, error) {
+	// Step 1: GetTemplate + CheckOwnership
+	tpl, err := s.getOwnedTemplate(ctx, input.UserID, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: UpdateTemplateFields
+	if err := tpl.Update(input.Name, input.TitlePattern, input.Description, input.Priority); err != nil {
+		return nil, err
+	}
+	if err := applyTemplateDetails(tpl, input); err != nil {
+		return nil, err
+	}
+
+	// Step 3: SaveTemplate
+	if err := s.templateRepo.Update(ctx, tpl); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 更新模板失败")
+	}
+
+	log.Printf("Task template updated: %s", tpl.ID)
+	return &TemplateOutput{Template: tpl}, nil
+}
+
+// GetTemplate 获取模板详情（用例实现）
+func (s *TemplateService) GetTemplate(ctx context.Context, input GetTemplateInput) (*TemplateOutput, error) {
+	tpl, err := s.getOwnedTemplate(ctx, input.UserID, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateOutput{Template: tpl}, nil
+}
+
+// ListTemplates 列出当前用户的模板（用例实现）
+func (s *TemplateService) ListTemplates(ctx context.Context, userID string) (*ListTemplatesOutput, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	templates, err := s.templateRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询模板失败")
+	}
+	return &ListTemplatesOutput{Templates: templates}, nil
+}
+
+// DeleteTemplate 删除模板（用例实现）
+//
+// 删除模板不影响已经由该模板创建的任务。
+func (s *TemplateService) DeleteTemplate(ctx context.Context, input GetTemplateInput) error {
+	if _, err := s.getOwnedTemplate(ctx, input.UserID, input.TemplateID); err != nil {
+		return err
+	}
+
+	if err := s.templateRepo.Delete(ctx, input.TemplateID); err != nil {
+		return fmt.Errorf("DELETION_FAILED: 删除模板失败")
+	}
+
+	log.Printf("Task template deleted: %s", input.TemplateID)
+	return nil
+}
+
+// InstantiateTemplate 根据模板创建任务（用例实现）
+//
+// 对应 usecases.yaml 中的 InstantiateTemplate
+//
+// 步骤：
+//  1. GetTemplate - 获取模板并验证所有权
+//  2. BuildVariables - 合并内置变量（date/time）和自定义变量
+//  3. RenderTemplate - 渲染标题、描述（缺少变量时失败，不创建任何任务）
+//  4. CreateTask - 通过 TaskService 创建主任务
+//  5. CreateSubtasks - 通过 TaskService 创建子任务
+//
+// 截止日期 = 实例化时间 + 截止偏移
+func (s *TemplateService) InstantiateTemplate(ctx context.Context, input InstantiateTemplateInput) (*InstantiateTemplateOutput, error) {
+	// Step 1: GetTemplate
+	tpl, err := s.getOwnedTemplate(ctx, input.UserID, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: BuildVariables
+	now := s.now()
+	vars := model.TemplateVariables(now, input.Variables)
+
+	// Step 3: RenderTemplate（先渲染全部内容，确保变量齐全后再写入）
+	taskInput, err := renderTaskInput(input.UserID, tpl.TitlePattern, tpl.Description, tpl.Priority, tpl.DueOffset, now, vars)
+	if err != nil {
+		return nil, err
+	}
+	taskInput.Tags = tpl.Tags
+
+	subtaskInputs := make([]CreateTaskInput, len(tpl.Subtasks))
+	for i, sub := range tpl.Subtasks {
+		priority := sub.Priority
+		if priority == "" {
+			priority = tpl.Priority
+		}
+		subInput, err := renderTaskInput(input.UserID, sub.TitlePattern, sub.Description, priority, sub.DueOffset, now, vars)
+		if err != nil {
+			return nil, err
+		}
+		subtaskInputs[i] = subInput
+	}
+
+	// Step 4 & 5: CreateTask + CreateSubtasks（同一事务，子任务失败时主任务一并回滚）
+	var output *InstantiateTemplateOutput
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.taskService.CreateTask(ctx, taskInput)
+		if err != nil {
+			return err
+		}
+
+		output = &InstantiateTemplateOutput{
+			Task:     created.Task,
+			Subtasks: make([]*model.Task, 0, len(subtaskInputs)),
+		}
+		for _, subInput := range subtaskInputs {
+			parentID := created.Task.ID
+			subInput.ParentID = &parentID
+			sub, err := s.taskService.CreateTask(ctx, subInput)
+			if err != nil {
+				return err
+			}
+			output.Subtasks = append(output.Subtasks, sub.Task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Task template instantiated: %s -> %s", tpl.ID, output.Task.ID)
+	return output, nil
+}
+
+// getOwnedTemplate 获取模板并验证所有权
+func (s *TemplateService) getOwnedTemplate(ctx context.Context, userID, templateID string) (*model.TaskTemplate, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	tpl, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("TEMPLATE_NOT_FOUND: 模板不存在")
+	}
+	if tpl.UserID != userID {
+		return nil, fmt.Errorf("UNAUTHORIZED_ACCESS: 无权访问此模板")
+	}
+	return tpl, nil
+}
+
+// applyTemplateDetails 设置模板的标签、截止偏移和子任务
+func applyTemplateDetails(tpl *model.TaskTemplate, input TemplateInput) error {
+	if err := tpl.SetTags(input.Tags); err != nil {
+		return err
+	}
+	if err := tpl.SetDueOffset(input.DueOffset); err != nil {
+		return err
+	}
+	return tpl.SetSubtasks(input.Subtasks)
+}
+
+// renderTaskInput 渲染模板内容并构造 CreateTaskInput
+func renderTaskInput(userID, titlePattern, description string, priority model.Priority, dueOffset *time.Duration, now time.Time, vars map[string]string) (CreateTaskInput, error) {
+	title, err := model.RenderPattern(titlePattern, vars)
+	if err != nil {
+		return CreateTaskInput{}, err
+	}
+	desc, err := model.RenderPattern(description, vars)
+	if err != nil {
+		return CreateTaskInput{}, err
+	}
+
+	input := CreateTaskInput{
+		UserID:      userID,
+		Title:       title,
+		Description: desc,
+		Priority:    priority,
+	}
+	if dueOffset != nil {
+		dueDate := now.Add(*dueOffset)
+		input.DueDate = &dueDate
+	}
+	return input, nil
+}
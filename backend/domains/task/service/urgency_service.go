@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// maxUrgencyCandidates 按紧急度排序时最多参与计算的任务数
+//
+// 紧急度无法在 SQL 中排序，需要把候选任务加载到内存计算。
+// 候选任务在 SQL 中按紧急度的主要因子预排序（repository.SortByUrgencyCandidates），
+// 超过上限时舍弃的是最不可能紧急的任务（已完成、无截止日期、低优先级、最新创建）。
+const maxUrgencyCandidates = 1000
+
+// maxNextTasks 推荐任务最多返回的数量
+const maxNextTasks = 50
+
+// UrgencyService 任务紧急度领域服务
+//
+// 职责：
+// - 根据用户的紧急度系数计算任务紧急度
+// - 按紧急度排序列出任务（sort_by=urgency）
+// - 给出"下一步做什么"的推荐（GET /api/tasks/next）
+// - 管理用户的紧急度系数
+type UrgencyService struct {
+	taskRepo     repository.TaskRepository
+	settingsRepo repository.UrgencySettingsRepository
+	now          func() time.Time // 当前时间（测试时可替换）
+}
+
+// NewUrgencyService 创建紧急度领域服务
+//
+// 参数：
+//   - taskRepo: 任务仓储
+//   - settingsRepo: 用户紧急度系数仓储
+func NewUrgencyService(taskRepo repository.TaskRepository, settingsRepo repository.UrgencySettingsRepository) *UrgencyService {
+	return &UrgencyService{
+		taskRepo:     taskRepo,
+		settingsRepo: settingsRepo,
+		now:          time.Now,
+	}
+}
+
+// GetUrgencyCoefficientsOutput 获取紧急度系数输出
+type GetUrgencyCoefficientsOutput struct {
+	Coefficients model.UrgencyCoefficients
+	IsDefault    bool // 用户未自定义，使用默认系数
+}
+
+// UpdateUrgencyCoefficientsInput 更新紧急度系数输入
+//
+// 所有字段可选，只覆盖提供的字段；TagBoosts 提供时整体替换。
+type UpdateUrgencyCoefficientsInput struct {
+	UserID         string // 用户 ID（从 JWT 获取）
+	PriorityHigh   *float64
+	PriorityMedium *float64
+	PriorityLow    *float64
+	Due            *float64
+	Age            *float64
+	AgeMaxDays     *int
+	Blocked        *float64
+	Active         *float64
+	Tags           *float64
+	TagBoosts      map[string]float64
+	Reset          bool // 恢复默认系数（忽略其他字段）
+}
+
+// NextTasksInput 获取推荐任务输入
+type NextTasksInput struct {
+	UserID string // 用户 ID（从 JWT 获取）
+	Limit  int    // 返回数量
+}
+
+// NextTasksOutput 获取推荐任务输出
+type NextTasksOutput struct {
+	Tasks   []*model.Task
+	Urgency map[string]float64 // 任务 ID → 紧急度
+}
+
+// GetUrgencyCoefficients 获取用户的紧急度系数（用例实现）
+//
+// 对应 usecases.yaml 中的 GetUrgencyCoefficients
+func (s *UrgencyService) GetUrgencyCoefficients(ctx context.Context, userID string) (*GetUrgencyCoefficientsOutput, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	coefficients, err := s.settingsRepo.Get(ctx, userID)
+	if err != nil {
+		logger.Error("GetUrgencyCoefficients failed", zap.Error(err))
+		return nil, fmt.Errorf("QUERY_FAILED: 查询紧急度系数失败")
+	}
+	if coefficients == nil {
+		return &GetUrgencyCoefficientsOutput{
+			Coefficients: model.DefaultUrgencyCoefficients(),
+			IsDefault:    true,
+		}, nil
+	}
+	return &GetUrgencyCoefficientsOutput{Coefficients: *coefficients}, nil
+}
+
+// UpdateUrgencyCoefficients 更新用户的紧急度系数（用例实现）
+//
+// 对应 usecases.yaml 中的 UpdateUrgencyCoefficients
+//
+// 步骤：
+//  1. GetCurrentCoefficients - 获取当前系数（未自定义时为默认系数）
+//  2. MergeCoefficients - 合并请求中提供的字段
+//  3. ValidateCoefficients - 验证系数范围
+//  4. SaveCoefficients - 保存系数
+func (s *UrgencyService) UpdateUrgencyCoefficients(ctx context.Context, input UpdateUrgencyCoefficientsInput) (*GetUrgencyCoefficientsOutput, error) {
+	// Step 1: GetCurrentCoefficients
+	current, err := s.GetUrgencyCoefficients(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: MergeCoefficients
+	c := current.Coefficients
+	if input.Reset {
+		c = model.DefaultUrgencyCoefficients()
+	} else {
+		mergeFloat(&c.PriorityHigh, input.PriorityHigh)
+		mergeFloat(&c.PriorityMedium, input.PriorityMedium)
+		mergeFloat(&c.PriorityLow, input.PriorityLow)
+		mergeFloat(&c.Due, input.Due)
+		mergeFloat(&c.Age, input.Age)
+		mergeFloat(&c.Blocked, input.Blocked)
+		mergeFloat(&c.Active, input.Active)
+		mergeFloat(&c.Tags, input.Tags)
+		if input.AgeMaxDays != nil {
+			c.AgeMaxDays = *input.AgeMaxDays
+		}
+		if input.TagBoosts != nil {
+			c.TagBoosts = input.TagBoosts
+		}
+	}
+
+	// Step 3: ValidateCoefficients
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Step 4: SaveCoefficients
+	if err := s.settingsRepo.Save(ctx, input.UserID, c); err != nil {
+		return nil, fmt.Errorf("UPDATE_FAILED: 保存紧急度系数失败")
+	}
+
+	log.Printf("Urgency coefficients updated: %s", input.UserID)
+	return &GetUrgencyCoefficientsOutput{Coefficients: c}, nil
+}
+
+// ListTasksByUrgency 按紧急度排序列出任务（用例实现）
+//
+// 对应 usecases.yaml 中 ListTasks 的 sort_by=urgency
+//
+// 步骤：
+//  1. LoadCoefficients - 加载用户的紧急度系数
+//  2. LoadCandidates - 按筛选条件加载候选任务（不分页）
+//  3. ScoreTasks - 计算紧急度
+//  4. SortAndPaginate - 在内存中排序并分页
+func (s *UrgencyService) ListTasksByUrgency(ctx context.Context, input ListTasksInput) (*ListTasksOutput, error) {
+	if input.Filter.UserID == nil || *input.Filter.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+
+	// Step 1: LoadCoefficients
+	current, err := s.GetUrgencyCoefficients(ctx, *input.Filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 2: LoadCandidates
+	tasks, totalCount, err := s.loadCandidates(ctx, input.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 3: ScoreTasks
+	scores, err := s.scoreTasks(ctx, tasks, current.Coefficients)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: SortAndPaginate
+	sortByUrgency(tasks, scores, input.Filter.SortOrder == "asc")
+
+	page, limit := input.Filter.Page, input.Filter.Limit
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * limit
+	if start > len(tasks) {
+		start = len(tasks)
+	}
+	end := len(tasks)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	pageTasks := tasks[start:end]
+
+	pageScores := make(map[string]float64, len(pageTasks))
+	for _, task := range pageTasks {
+		pageScores[task.ID] = scores[task.ID]
+	}
+
+	// 总数为符合条件的全部任务；超过 maxUrgencyCandidates 时只有候选任务参与排序和分页，
+	// 以 Truncated 标记（HasMore 以候选任务为准）
+	return &ListTasksOutput{
+		Tasks:      pageTasks,
+		TotalCount: totalCount,
+		Page:       page,
+		Limit:      limit,
+		HasMore:    end < len(tasks),
+		Truncated:  totalCount > len(tasks),
+		Urgency:    pageScores,
+	}, nil
+}
+
+// NextTasks 推荐下一步要做的任务（用例实现）
+//
+// 对应 usecases.yaml 中的 NextTasks
+//
+// 返回未完成任务中紧急度最高的 Limit 个。
+func (s *UrgencyService) NextTasks(ctx context.Context, input NextTasksInput) (*NextTasksOutput, error) {
+	if input.UserID == "" {
+		return nil, fmt.Errorf("USER_ID_REQUIRED: 用户 ID 不能为空")
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > maxNextTasks {
+		limit = maxNextTasks
+	}
+
+	current, err := s.GetUrgencyCoefficients(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只推荐未完成的任务（在查询中排除已完成的任务，它们不占用候选数量）
+	filter := repository.NewTaskFilter()
+	filter.UserID = &input.UserID
+	filter.ExcludeCompleted = true
+	open, _, err := s.loadCandidates(ctx, *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.scoreTasks(ctx, open, current.Coefficients)
+	if err != nil {
+		return nil, err
+	}
+	sortByUrgency(open, scores, false)
+
+	if len(open) > limit {
+		open = open[:limit]
+	}
+	top := make(map[string]float64, len(open))
+	for _, task := range open {
+		top[task.ID] = scores[task.ID]
+	}
+
+	return &NextTasksOutput{Tasks: open, Urgency: top}, nil
+}
+
+// loadCandidates 按筛选条件加载参与紧急度计算的任务（预排序后的前 maxUrgencyCandidates 个）
+//
+// 返回候选任务和符合条件的任务总数。
+func (s *UrgencyService) loadCandidates(ctx context.Context, filter repository.TaskFilter) ([]*model.Task, int, error) {
+	filter.SortBy = repository.SortByUrgencyCandidates
+	filter.Page = 1
+	filter.Limit = maxUrgencyCandidates
+
+	tasks, totalCount, err := s.taskRepo.List(ctx, &filter)
+	if err != nil {
+		logger.Error("Load urgency candidates failed", zap.Error(err))
+		return nil, 0, fmt.Errorf("QUERY_FAILED: 查询失败")
+	}
+	if totalCount > len(tasks) {
+		logger.Warn("urgency candidates truncated",
+			zap.Int("total", totalCount),
+			zap.Int("candidates", len(tasks)),
+		)
+	}
+	return tasks, totalCount, nil
+}
+
+// scoreTasks 计算任务紧急度，返回 任务 ID → 紧急度
+func (s *UrgencyService) scoreTasks(ctx context.Context, tasks []*model.Task, c model.UrgencyCoefficients) (map[string]float64, error) {
+	scores := make(map[string]float64, len(tasks))
+	if len(tasks) == 0 {
+		return scores, nil
+	}
+
+	// 阻塞状态：存在未完成的子任务（已完成任务不参与计算）
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status != model.StatusCompleted {
+			ids = append(ids, task.ID)
+		}
+	}
+	openSubtasks, err := s.taskRepo.CountOpenSubtasks(ctx, ids)
+	if err != nil {
+		logger.Error("Count open subtasks failed", zap.Error(err))
+		return nil, fmt.Errorf("QUERY_FAILED: 查询失败")
+	}
+
+	now := s.now()
+	for _, task := range tasks {
+		scores[task.ID] = task.Urgency(c, openSubtasks[task.ID] > 0, now)
+	}
+	return scores, nil
+}
+
+// sortByUrgency 按紧急度排序（紧急度相同时截止日期早的优先，其次创建时间早的优先）
+func sortByUrgency(tasks []*model.Task, scores map[string]float64, ascending bool) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		si, sj := scores[tasks[i].ID], scores[tasks[j].ID]
+		if si != sj {
+			if ascending {
+				return si < sj
+			}
+			return si > sj
+		}
+		di, dj := tasks[i].DueDate, tasks[j].DueDate
+		if di != nil && dj != nil && !di.Equal(*dj) {
+			return di.Before(*dj)
+		}
+		if (di == nil) != (dj == nil) {
+			return di != nil
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
+
+// mergeFloat 当 value 非空时覆盖 target
+func mergeFloat(target *float64, value *float64) {
+	if value != nil {
+		*target = *value
+	}
+}
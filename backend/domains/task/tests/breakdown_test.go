@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// performBreakdown 注册拆解路由并发送请求
+func performBreakdown(helper *TestHelper, taskID string) (int, []byte) {
+	helper.RegisterRoute("POST", "/api/tasks/:id/breakdown", helper.HandlerDeps.BreakdownTaskHandler)
+	w := helper.PerformRequest("POST", "/api/tasks/"+taskID+"/breakdown", nil)
+	return w.Code, w.Body.Bytes()
+}
+
+// performAcceptBreakdown 注册接受拆解路由并发送请求
+func performAcceptBreakdown(helper *TestHelper, taskID string, req dto.AcceptBreakdownRequest) (int, []byte) {
+	helper.RegisterRoute("POST", "/api/tasks/:id/breakdown/accept", helper.HandlerDeps.AcceptBreakdownHandler)
+	reqBody, _ := json.Marshal(req)
+	w := helper.PerformRequest("POST", "/api/tasks/"+taskID+"/breakdown/accept",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+	return w.Code, w.Body.Bytes()
+}
+
+// TestBreakdownTask_Success 测试生成子任务建议
+//
+// 对应 usecases.yaml 中的 BreakdownTask 用例的成功路径：只读取任务，不写入数据库
+func TestBreakdownTask_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID(TestTaskID)
+	task.Title = "发布 v2.0"
+	parentDue := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	task.DueDate = &parentDue
+	MockFindByID(helper.Mock, task)
+
+	// 第一次输出不符合 Schema（优先级无效），修正后通过
+	helper.LLM.Enqueue(
+		mock.Response{Content: `{"subtasks": [{"title": "冻结代码", "description": "", "priority": "urgent", "due_in_days": 0}]}`},
+		mock.Response{Content: "```json\n" + `{"subtasks": [
+			{"title": "冻结代码", "description": "只合并修复", "priority": "high", "due_in_days": 0},
+			{"title": "回归测试", "description": "", "priority": "medium", "due_in_days": 30},
+			{"title": "撰写发布说明", "description": "", "priority": "low", "due_in_days": null}
+		]}` + "\n```"},
+	)
+
+	code, body := performBreakdown(helper, TestTaskID)
+	require.Equal(t, consts.StatusOK, code, string(body))
+
+	var resp dto.BreakdownTaskResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, TestTaskID, resp.TaskID)
+	assert.Equal(t, "mock-model", resp.Model)
+	require.Len(t, resp.Subtasks, 3)
+
+	assert.Equal(t, "冻结代码", resp.Subtasks[0].Title)
+	assert.Equal(t, "high", resp.Subtasks[0].Priority)
+	require.NotNil(t, resp.Subtasks[0].DueDate)
+	today, err := time.Parse(time.RFC3339, *resp.Subtasks[0].DueDate)
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().Format("2006-01-02"), today.Format("2006-01-02"))
+
+	// 超过父任务截止日期的建议被限制为父任务的截止日期
+	require.NotNil(t, resp.Subtasks[1].DueDate)
+	assert.Equal(t, parentDue.Format(time.RFC3339), *resp.Subtasks[1].DueDate)
+	assert.Equal(t, 30, *resp.Subtasks[1].DueInDays)
+
+	assert.Nil(t, resp.Subtasks[2].DueInDays)
+	assert.Nil(t, resp.Subtasks[2].DueDate)
+
+	// 使用 task.breakdown 提示词，按用户计算额度
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, TestUserID, requests[0].User)
+	require.NotNil(t, requests[0].ResponseFormat)
+	assert.Equal(t, "task_breakdown", requests[0].ResponseFormat.Name)
+	last := requests[0].Messages[len(requests[0].Messages)-1]
+	assert.Equal(t, llmmodel.RoleUser, last.Role)
+	assert.Contains(t, last.Content, "任务标题：发布 v2.0")
+	assert.Contains(t, last.Content, "截止日期："+parentDue.Format("2006-01-02"))
+
+	helper.AssertExpectations(t)
+}
+
+// TestBreakdownTask_BREAKDOWN_FAILED 测试模型输出始终无法通过校验
+//
+// 对应 usecases.yaml 中的错误：BREAKDOWN_FAILED
+// HTTP 状态码：502
+func TestBreakdownTask_BREAKDOWN_FAILED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	helper.LLM.SetHandler(func(req *llmmodel.ChatRequest) mock.Response {
+		return mock.Response{Content: "好的，我来帮你拆解这个任务"}
+	})
+
+	code, body := performBreakdown(helper, TestTaskID)
+
+	assert.Equal(t, consts.StatusBadGateway, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "BREAKDOWN_FAILED", resp.Error)
+	assert.Len(t, helper.LLM.Requests(), 3) // 首次调用 + 2 次修正重试
+
+	helper.AssertExpectations(t)
+}
+
+// TestBreakdownTask_INVALID_PARENT_TASK 测试子任务不能再拆解
+//
+// 对应 rules.md 中的 R7.4：不调用模型
+func TestBreakdownTask_INVALID_PARENT_TASK(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID(TestTaskID)
+	parentID := "parent-task-id"
+	task.ParentID = &parentID
+	MockFindByID(helper.Mock, task)
+
+	code, body := performBreakdown(helper, TestTaskID)
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "INVALID_PARENT_TASK", resp.Error)
+	assert.Empty(t, helper.LLM.Requests())
+
+	helper.AssertExpectations(t)
+}
+
+// TestAcceptBreakdown_Success 测试保存用户确认的子任务
+//
+// 对应 usecases.yaml 中的 AcceptBreakdown 用例的成功路径
+func TestAcceptBreakdown_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	parent := CreateTestTaskWithID(TestTaskID)
+	MockFindByID(helper.Mock, parent)
+
+	// 所有子任务在同一事务中创建，每个子任务先查询父任务再插入
+	helper.Mock.ExpectBegin()
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectCommit()
+
+	dueDate := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	code, body := performAcceptBreakdown(helper, TestTaskID, dto.AcceptBreakdownRequest{
+		Subtasks: []dto.AcceptSubtaskItem{
+			{Title: "冻结代码", Priority: "high", DueDate: dueDate},
+			{Title: "回归测试（已修改）", Tags: []string{"qa"}},
+		},
+	})
+	require.Equal(t, consts.StatusOK, code, string(body))
+
+	var resp dto.AcceptBreakdownResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, TestTaskID, resp.Task.TaskID)
+	require.Len(t, resp.Subtasks, 2)
+	for _, sub := range resp.Subtasks {
+		require.NotNil(t, sub.ParentID)
+		assert.Equal(t, TestTaskID, *sub.ParentID)
+	}
+	assert.Equal(t, "high", resp.Subtasks[0].Priority)
+	assert.Equal(t, dueDate, *resp.Subtasks[0].DueDate)
+	assert.Equal(t, "medium", resp.Subtasks[1].Priority) // 未指定时使用默认优先级
+	assert.Equal(t, []string{"qa"}, resp.Subtasks[1].Tags)
+
+	helper.AssertExpectations(t)
+}
+
+// TestAcceptBreakdown_Rollback 测试任一子任务创建失败时全部回滚
+func TestAcceptBreakdown_Rollback(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	parent := CreateTestTaskWithID(TestTaskID)
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectBegin()
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnError(sql.ErrConnDone)
+	helper.Mock.ExpectRollback()
+
+	code, _ := performAcceptBreakdown(helper, TestTaskID, dto.AcceptBreakdownRequest{
+		Subtasks: []dto.AcceptSubtaskItem{{Title: "冻结代码"}, {Title: "回归测试"}},
+	})
+
+	assert.Equal(t, consts.StatusInternalServerError, code)
+	helper.AssertExpectations(t)
+}
+
+// TestAcceptBreakdown_NO_SUBTASKS 测试没有子任务
+//
+// HTTP 状态码：400，不读取数据库
+func TestAcceptBreakdown_NO_SUBTASKS(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	code, _ := performAcceptBreakdown(helper, TestTaskID, dto.AcceptBreakdownRequest{Subtasks: []dto.AcceptSubtaskItem{}})
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	helper.AssertExpectations(t)
+}
@@ -22,8 +22,8 @@ func TestCompleteTask_Success(t *testing.T) {
 
 	// Mock 查询任务
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil, nil, nil)
 
 	// goqu 生成的 SQL 使用双引号引用标识符，参数值直接嵌入
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
@@ -35,12 +35,14 @@ func TestCompleteTask_Success(t *testing.T) {
 		WillReturnRows(tagsRows)
 
 	// Mock 更新任务状态（goqu 将参数值直接嵌入到 SQL 中）
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`UPDATE "tasks" SET`).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Mock 删除旧 tags (goqu 将参数值直接嵌入到 SQL 中)
 	helper.Mock.ExpectExec(`DELETE FROM "task_tags" WHERE \("task_id"`).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	helper.Mock.ExpectCommit()
 
 	c := app.NewContext(0)
 	c.Params = append(c.Params, param.Param{Key: "id", Value: "task-123"})
@@ -97,8 +99,8 @@ func TestCompleteTask_TASK_ALREADY_COMPLETED(t *testing.T) {
 	// Mock 查询任务（已完成状态）
 	completedAt := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Test Task", "Description", "completed", "medium", nil, time.Now(), time.Now(), &completedAt)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Test Task", "Description", "completed", "medium", nil, time.Now(), time.Now(), &completedAt, nil, nil)
 
 	// goqu 生成的 SQL 使用双引号引用标识符，参数值直接嵌入
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
@@ -137,8 +139,8 @@ func TestCompleteTask_COMPLETION_FAILED(t *testing.T) {
 
 	// Mock 查询成功
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil, nil, nil)
 
 	// goqu 生成的 SQL 使用双引号引用标识符，参数值直接嵌入
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
@@ -150,8 +152,10 @@ func TestCompleteTask_COMPLETION_FAILED(t *testing.T) {
 		WillReturnRows(tagsRows)
 
 	// Mock 更新失败（goqu 将参数值直接嵌入到 SQL 中）
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`UPDATE "tasks" SET`).
 		WillReturnError(sql.ErrConnDone)
+	helper.Mock.ExpectRollback()
 
 	c := app.NewContext(0)
 	c.Params = append(c.Params, param.Param{Key: "id", Value: "task-123"})
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/cloudwego/hertz/pkg/app"
@@ -22,14 +23,14 @@ func TestCreateTask_Success(t *testing.T) {
 	defer helper.Close()
 
 	// Mock 数据库操作：插入任务（goqu 将参数值直接嵌入到 SQL 中）
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	// Mock 插入 tags (有 2 个 tags: "test", "unit")（goqu 将参数值直接嵌入到 SQL 中）
-	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Mock 插入 tags (2 个 tags: "test", "unit"，一条多行 INSERT)（goqu 将参数值直接嵌入到 SQL 中）
+	helper.Mock.ExpectExec(`INSERT INTO "task_tags" .+ VALUES .+'test'.+, .+'unit'`).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	helper.Mock.ExpectCommit()
 
 	// 注册路由
 	helper.RegisterRoute("POST", "/api/tasks", func(ctx context.Context, c *app.RequestContext) {
@@ -310,8 +311,10 @@ func TestCreateTask_CREATION_FAILED(t *testing.T) {
 	})
 
 	// Mock 数据库操作失败
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
 		WillReturnError(fmt.Errorf("database connection failed"))
+	helper.Mock.ExpectRollback()
 
 	req := dto.CreateTaskRequest{
 		Title:       "Test Task",
@@ -337,6 +340,81 @@ func TestCreateTask_CREATION_FAILED(t *testing.T) {
 	helper.AssertExpectations(t)
 }
 
+// TestCreateTask_CONTENT_BLOCKED 测试标题命中屏蔽词
+//
+// 对应 usecases.yaml 中的错误：CONTENT_BLOCKED
+// 错误消息："内容未通过安全检查"
+// HTTP 状态码：400
+func TestCreateTask_CONTENT_BLOCKED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 注册路由
+	helper.RegisterRoute("POST", "/api/tasks", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.CreateTaskHandler(ctx, c)
+	})
+
+	req := dto.CreateTaskRequest{
+		Title:       "Clean up this shitty code",
+		Description: "Test Description",
+		Priority:    "medium",
+	}
+	reqBody, _ := json.Marshal(req)
+
+	// 使用 ut.PerformRequest 执行请求
+	w := helper.PerformRequest("POST", "/api/tasks",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	// 验证响应：应返回 400
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+
+	var errResp dto.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errResp)
+	assert.NoError(t, err)
+	assert.Contains(t, errResp.Error, "CONTENT_BLOCKED", "错误码应包含内容未通过安全检查")
+
+	// 不应该有数据库操作
+	helper.AssertExpectations(t)
+}
+
+// TestCreateTask_RedactsPII 测试描述中的个人信息被替换后保存
+func TestCreateTask_RedactsPII(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 注册路由
+	helper.RegisterRoute("POST", "/api/tasks", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.CreateTaskHandler(ctx, c)
+	})
+
+	// Mock 数据库操作：保存的是替换后的描述
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "tasks" .+'Email \[EMAIL\] about the offer'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectCommit()
+
+	req := dto.CreateTaskRequest{
+		Title:       "Follow up",
+		Description: "Email jane@example.com about the offer",
+		Priority:    "medium",
+	}
+	reqBody, _ := json.Marshal(req)
+
+	// 使用 ut.PerformRequest 执行请求
+	w := helper.PerformRequest("POST", "/api/tasks",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	// 验证响应
+	assert.Equal(t, consts.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "jane@example.com")
+
+	helper.AssertExpectations(t)
+}
+
 // TestCreateTask_WithOptionalFields 测试包含所有可选字段的创建
 func TestCreateTask_WithOptionalFields(t *testing.T) {
 	helper := NewTestHelper(t)
@@ -348,21 +426,19 @@ func TestCreateTask_WithOptionalFields(t *testing.T) {
 	})
 
 	// Mock 数据库操作
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	// Mock tags 插入（3个标签）
-	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Mock tags 插入（3个标签，一条多行 INSERT）
 	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+		WillReturnResult(sqlmock.NewResult(3, 3))
+	helper.Mock.ExpectCommit()
 
 	req := dto.CreateTaskRequest{
 		Title:       "Complete Task",
 		Description: "Full description with all fields",
 		Priority:    "high",
-		DueDate:     "2025-12-31T23:59:59Z",
+		DueDate:     time.Now().AddDate(1, 0, 0).UTC().Format(time.RFC3339), // 始终使用未来日期
 		Tags:        []string{"important", "urgent", "project-alpha"},
 	}
 	reqBody, _ := json.Marshal(req)
@@ -385,3 +461,62 @@ func TestCreateTask_WithOptionalFields(t *testing.T) {
 
 	helper.AssertExpectations(t)
 }
+
+// performCreateSubtask 注册创建任务路由，创建 parentID 的子任务
+func performCreateSubtask(helper *TestHelper, parentID string) (int, dto.ErrorResponse) {
+	helper.RegisterRoute("POST", "/api/tasks", helper.HandlerDeps.CreateTaskHandler)
+	reqBody, _ := json.Marshal(dto.CreateTaskRequest{Title: "Subtask", ParentID: parentID})
+	w := helper.PerformRequest("POST", "/api/tasks",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+	var resp dto.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+// TestCreateTask_PARENT_TASK_NOT_FOUND 测试父任务不存在
+func TestCreateTask_PARENT_TASK_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	code, resp := performCreateSubtask(helper, "missing-task")
+
+	assert.Equal(t, consts.StatusNotFound, code)
+	assert.Equal(t, "PARENT_TASK_NOT_FOUND", resp.Error)
+	helper.AssertExpectations(t)
+}
+
+// TestCreateTask_ParentQueryFailed 测试查询父任务出错时返回 500（不是 404）
+func TestCreateTask_ParentQueryFailed(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
+		WillReturnError(fmt.Errorf("connection refused"))
+
+	code, resp := performCreateSubtask(helper, TestTaskID)
+
+	assert.Equal(t, consts.StatusInternalServerError, code)
+	assert.Equal(t, "QUERY_FAILED", resp.Error)
+	helper.AssertExpectations(t)
+}
+
+// TestCreateTask_INVALID_PARENT_TASK 测试父任务属于其他用户（由 Task.SetParent 检查）
+func TestCreateTask_INVALID_PARENT_TASK(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	parent := CreateTestTaskWithID(TestTaskID)
+	parent.UserID = "other-user"
+	MockFindByID(helper.Mock, parent)
+
+	code, resp := performCreateSubtask(helper, TestTaskID)
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	assert.Equal(t, "INVALID_PARENT_TASK", resp.Error)
+	helper.AssertExpectations(t)
+}
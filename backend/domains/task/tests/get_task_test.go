@@ -24,7 +24,7 @@ func TestGetTask_Success(t *testing.T) {
 	createdAt, _ := time.Parse(time.RFC3339, "2025-01-01T00:00:00Z")
 	updatedAt, _ := time.Parse(time.RFC3339, "2025-01-01T00:00:00Z")
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	}).AddRow(
 		"task-123",
 		TestUserID,
@@ -36,6 +36,7 @@ func TestGetTask_Success(t *testing.T) {
 		createdAt,
 		updatedAt,
 		nil,
+		nil, nil,
 	)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
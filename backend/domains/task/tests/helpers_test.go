@@ -13,10 +13,21 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/embedding"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/vectorstore"
+	promptmodel "github.com/erweixin/go-genai-stack/backend/domains/prompt/model"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/templates"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/handlers"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
 	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 )
 
 // ========== 测试常量 ==========
@@ -46,6 +57,12 @@ var TestTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 type TestHelper struct {
 	DB          *sql.DB
 	Mock        sqlmock.Sqlmock
+	TaskService *service.TaskService
+	EventBus    sharedevents.EventBus          // 任务事件（测试可以订阅）
+	LLM         *mock.Provider                 // AI 拆解和自动建议使用的模型（按测试需要设置回复）
+	Enrichment  *service.EnrichmentService     // 自动建议（测试直接调用 Enrich，不启动后台队列）
+	Semantic    *service.SemanticSearchService // 语义搜索（订阅任务事件，测试调用 Drain 同步处理队列）
+	Vectors     *vectorstore.MemoryStore       // 语义搜索的向量存储
 	HandlerDeps *handlers.HandlerDependencies
 	Server      *server.Hertz // 使用完整的 Server 而不是 Engine
 	Ctx         context.Context
@@ -58,8 +75,10 @@ type TestHelper struct {
 //
 // 三层架构：
 // - Repository Layer → Service Layer → Handler Dependencies
+//
+// AI 拆解和自动建议使用 mock 模型提供商和内置的提示词模板（不读取数据库）。
 func NewTestHelper(t *testing.T) *TestHelper {
-	db, mock, err := sqlmock.New()
+	db, sqlMock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("failed to create sqlmock: %v", err)
 	}
@@ -67,12 +86,35 @@ func NewTestHelper(t *testing.T) *TestHelper {
 	// 1. 创建 Repository（基础设施层）
 	// 使用 postgres 作为测试数据库类型（goqu 需要指定数据库类型）
 	taskRepo := repository.NewTaskRepository(db, "postgres")
-
-	// 2. 创建 Domain Service（领域层）
-	taskService := service.NewTaskService(taskRepo)
+	templateRepo := repository.NewTemplateRepository(db, "postgres")
+	urgencySettingsRepo := repository.NewUrgencySettingsRepository(db, "postgres")
+	suggestionRepo := repository.NewSuggestionRepository(db, "postgres")
+
+	// 2. 创建 Domain Service（领域层）：语义搜索订阅任务事件，使用本地嵌入和内存存储
+	eventBus := sharedevents.NewDefaultEventBus()
+	taskService := service.NewTaskService(taskRepo).WithEventBus(eventBus).WithModeration(newTestModeration(t, eventBus))
+	templateService := service.NewTemplateService(templateRepo, taskService, persistence.NewTxManager(db))
+	urgencyService := service.NewUrgencyService(taskRepo, urgencySettingsRepo)
+
+	llm := mock.New()
+	registry := llmprovider.NewRegistry()
+	registry.Register(llm)
+	llmService := llmservice.NewLLMService(registry, mock.Name, "mock-model", nil)
+	embedded, err := promptservice.LoadTemplates(templates.FS)
+	if err != nil {
+		t.Fatalf("failed to load prompt templates: %v", err)
+	}
+	prompts := promptservice.NewPromptService(builtinPromptsOnly{}, embedded, 0)
+	breakdownService := service.NewBreakdownService(taskService, llmService, prompts, persistence.NewTxManager(db))
+	enrichmentService := service.NewEnrichmentService(taskService, taskRepo, suggestionRepo, llmService, prompts, persistence.NewTxManager(db), nil)
+	vectors := vectorstore.NewMemoryStore()
+	semanticSearch := service.NewSemanticSearchService(taskRepo, embedding.NewHashEmbedder(0), vectors)
+	_ = eventBus.Subscribe("task.created", semanticSearch.HandleTaskCreated)
+	_ = eventBus.Subscribe("task.updated", semanticSearch.HandleTaskUpdated)
+	_ = eventBus.Subscribe("task.deleted", semanticSearch.HandleTaskDeleted)
 
 	// 3. 创建 Handler Dependencies（Handler 层）
-	handlerDeps := handlers.NewHandlerDependencies(taskService)
+	handlerDeps := handlers.NewHandlerDependencies(taskService, templateService, urgencyService, breakdownService, enrichmentService, semanticSearch)
 
 	// 创建完整的 Server（包含绑定器初始化）
 	// 使用测试端口，快速退出
@@ -83,13 +125,44 @@ func NewTestHelper(t *testing.T) *TestHelper {
 
 	return &TestHelper{
 		DB:          db,
-		Mock:        mock,
+		Mock:        sqlMock,
+		TaskService: taskService,
+		EventBus:    eventBus,
+		LLM:         llm,
+		Enrichment:  enrichmentService,
+		Semantic:    semanticSearch,
+		Vectors:     vectors,
 		HandlerDeps: handlerDeps,
 		Server:      h,
 		Ctx:         context.Background(),
 	}
 }
 
+// newTestModeration 测试用的内容审核：屏蔽词 "shit*" 拒绝，个人信息替换
+func newTestModeration(t *testing.T, eventBus sharedevents.EventBus) *moderation.Pipeline {
+	wordlist, err := moderation.NewWordlist([]string{"shit*"})
+	if err != nil {
+		t.Fatalf("failed to create wordlist: %v", err)
+	}
+	return moderation.NewPipeline(eventBus,
+		moderation.Rule{Checker: wordlist, Action: moderation.ActionBlock},
+		moderation.Rule{Checker: moderation.PII{}, Action: moderation.ActionRedact},
+	)
+}
+
+// builtinPromptsOnly 没有管理员版本的提示词仓储（只使用内置模板）
+type builtinPromptsOnly struct{}
+
+func (builtinPromptsOnly) Create(ctx context.Context, t *promptmodel.PromptTemplate) error {
+	return nil
+}
+func (builtinPromptsOnly) List(ctx context.Context) ([]*promptmodel.PromptTemplate, error) {
+	return nil, nil
+}
+func (builtinPromptsOnly) ListPins(ctx context.Context) (map[string]string, error) { return nil, nil }
+func (builtinPromptsOnly) SetPin(ctx context.Context, name, version string) error  { return nil }
+func (builtinPromptsOnly) DeletePin(ctx context.Context, name string) error        { return nil }
+
 // Close 清理资源
 func (h *TestHelper) Close() error {
 	return h.DB.Close()
@@ -247,11 +320,11 @@ func CreateCompletedTestTask() *model.Task {
 func MockFindByID(mock sqlmock.Sqlmock, task *model.Task) {
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "title", "description", "status", "priority",
-		"due_date", "created_at", "updated_at", "completed_at",
+		"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	}).AddRow(
 		task.ID, task.UserID, task.Title, task.Description,
 		string(task.Status), string(task.Priority),
-		task.DueDate, task.CreatedAt, task.UpdatedAt, task.CompletedAt,
+		task.DueDate, task.CreatedAt, task.UpdatedAt, task.CompletedAt, task.ParentID, task.HiddenUntil,
 	)
 
 	// goqu 生成的 SQL 使用双引号引用标识符，参数值直接嵌入
@@ -273,6 +346,23 @@ func MockLoadTags(mock sqlmock.Sqlmock, taskID string, tags []model.Tag) {
 		WillReturnRows(tagsRows)
 }
 
+// MockLoadTagsForTasks Mock 批量加载多个任务的标签（一条 IN 查询）
+func MockLoadTagsForTasks(mock sqlmock.Sqlmock, tasks []*model.Task) {
+	if len(tasks) == 0 {
+		return
+	}
+
+	tagsRows := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"})
+	for _, task := range tasks {
+		for _, tag := range task.Tags {
+			tagsRows.AddRow(task.ID, tag.Name, tag.Color)
+		}
+	}
+
+	mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN`).
+		WillReturnRows(tagsRows)
+}
+
 // MockInsertTask Mock 插入任务
 // goqu 将参数值直接嵌入到 SQL 中，不需要 WithArgs
 func MockInsertTask(mock sqlmock.Sqlmock, task *model.Task) {
@@ -282,11 +372,14 @@ func MockInsertTask(mock sqlmock.Sqlmock, task *model.Task) {
 
 // MockInsertTags Mock 插入标签
 // goqu 将参数值直接嵌入到 SQL 中，不需要 WithArgs
+//
+// 所有标签通过一条多行 INSERT 插入
 func MockInsertTags(mock sqlmock.Sqlmock, taskID string, tags []model.Tag) {
-	for range tags {
-		mock.ExpectExec(`INSERT INTO "task_tags"`).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+	if len(tags) == 0 {
+		return
 	}
+	mock.ExpectExec(`INSERT INTO "task_tags"`).
+		WillReturnResult(sqlmock.NewResult(int64(len(tags)), int64(len(tags))))
 }
 
 // MockUpdateTask Mock 更新任务
@@ -304,12 +397,13 @@ func MockDeleteOldTags(mock sqlmock.Sqlmock, taskID string) {
 }
 
 // MockCompleteUpdate Mock 完成任务的完整更新操作
-// 包括：FindByID -> LoadTags -> Update -> DeleteTags
+// 包括：FindByID -> LoadTags -> Begin -> Update -> DeleteTags -> InsertTags -> Commit
 func MockCompleteUpdate(mock sqlmock.Sqlmock, task *model.Task) {
 	// 1. FindByID
 	MockFindByID(mock, task)
 
-	// 2. Update
+	// 2. Update（任务和标签在同一事务中写入）
+	mock.ExpectBegin()
 	MockUpdateTask(mock, task)
 
 	// 3. Delete old tags
@@ -319,6 +413,7 @@ func MockCompleteUpdate(mock sqlmock.Sqlmock, task *model.Task) {
 	if len(task.Tags) > 0 {
 		MockInsertTags(mock, task.ID, task.Tags)
 	}
+	mock.ExpectCommit()
 }
 
 // MockCount Mock 统计总数
@@ -332,15 +427,15 @@ func MockCount(mock sqlmock.Sqlmock, count int) {
 // MockListTasks Mock 列出任务
 func MockListTasks(mock sqlmock.Sqlmock, tasks []*model.Task) {
 	rows := sqlmock.NewRows([]string{
-		"id", "title", "description", "status", "priority",
-		"due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority",
+		"due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	})
 
 	for _, task := range tasks {
 		rows.AddRow(
-			task.ID, task.Title, task.Description,
+			task.ID, task.UserID, task.Title, task.Description,
 			string(task.Status), string(task.Priority),
-			task.DueDate, task.CreatedAt, task.UpdatedAt, task.CompletedAt,
+			task.DueDate, task.CreatedAt, task.UpdatedAt, task.CompletedAt, task.ParentID, task.HiddenUntil,
 		)
 	}
 
@@ -348,8 +443,38 @@ func MockListTasks(mock sqlmock.Sqlmock, tasks []*model.Task) {
 	mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 		WillReturnRows(rows)
 
-	// Mock tags for each task
-	for _, task := range tasks {
-		MockLoadTags(mock, task.ID, task.Tags)
-	}
+	// Mock 批量加载标签
+	MockLoadTagsForTasks(mock, tasks)
+}
+
+// MockFindTemplate Mock 查询模板
+//
+// tagsJSON / subtasksJSON 为存储格式的 JSON 文本，dueOffsetSeconds 为 nil 表示未设置截止偏移
+func MockFindTemplate(mock sqlmock.Sqlmock, templateID, userID, titlePattern, tagsJSON, subtasksJSON string, dueOffsetSeconds interface{}) {
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "name", "title_pattern", "description", "priority",
+		"tags", "due_offset_seconds", "subtasks", "created_at", "updated_at",
+	}).AddRow(
+		templateID, userID, "Test Template", titlePattern, "Created from template on {{date}}", "high",
+		tagsJSON, dueOffsetSeconds, subtasksJSON, TestTime, TestTime,
+	)
+
+	mock.ExpectQuery(`SELECT .+ FROM "task_templates" WHERE \("id"`).
+		WillReturnRows(rows)
+}
+
+// MockFindSuggestion Mock 查询任务的建议
+//
+// tagsJSON / acceptedJSON 为存储格式的 JSON 数组
+func MockFindSuggestion(mock sqlmock.Sqlmock, taskID, tagsJSON, priority, status, acceptedJSON string) {
+	rows := sqlmock.NewRows([]string{
+		"id", "task_id", "user_id", "tags", "priority", "original_priority", "model",
+		"status", "accepted_tags", "priority_accepted", "created_at", "resolved_at",
+	}).AddRow(
+		"suggestion-123", taskID, TestUserID, tagsJSON, priority, TestPriority, "mock-model",
+		status, acceptedJSON, false, TestTime, nil,
+	)
+
+	mock.ExpectQuery(`SELECT .+ FROM "task_suggestions" WHERE \("task_id"`).
+		WillReturnRows(rows)
 }
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const TestTemplateID = "test-template-123"
+
+// TestInstantiateTemplate_Success 测试根据模板创建任务和子任务
+//
+// 对应 usecases.yaml 中的 InstantiateTemplate 用例的成功路径
+func TestInstantiateTemplate_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// Mock 查询模板（1 个标签、1 个子任务、截止偏移 24h）
+	MockFindTemplate(helper.Mock, TestTemplateID, TestUserID, "Onboard {{name}}",
+		`["onboarding"]`, `[{"title_pattern":"Create account for {{name}}","due_offset_seconds":3600}]`, int64(86400))
+
+	// 主任务和子任务在同一事务中创建
+	helper.Mock.ExpectBegin()
+
+	// Mock 创建主任务（含 1 个标签）
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Mock 创建子任务：先查询父任务，再插入
+	parent := CreateTestTaskWithID("parent-task-id")
+	MockFindByID(helper.Mock, parent)
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	helper.Mock.ExpectCommit()
+
+	// task.created 在事务提交后发布（订阅者此时能读到任务）
+	var created int
+	require.NoError(t, helper.EventBus.Subscribe("task.created", func(ctx context.Context, event sharedevents.Event) error {
+		created++
+		assert.NoError(t, helper.Mock.ExpectationsWereMet(), "task.created published before commit")
+		return nil
+	}))
+
+	helper.RegisterRoute("POST", "/api/templates/:id/instantiate", helper.HandlerDeps.InstantiateTemplateHandler)
+
+	reqBody, _ := json.Marshal(dto.InstantiateTemplateRequest{
+		Variables: map[string]string{"name": "Alice"},
+	})
+	before := time.Now()
+	w := helper.PerformRequest("POST", "/api/templates/"+TestTemplateID+"/instantiate",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.InstantiateTemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Onboard Alice", resp.Task.Title)
+	assert.Equal(t, "Created from template on "+before.Format("2006-01-02"), resp.Task.Description)
+	assert.Equal(t, "high", resp.Task.Priority)
+	assert.Equal(t, []string{"onboarding"}, resp.Task.Tags)
+	require.NotNil(t, resp.Task.DueDate)
+
+	dueDate, err := time.Parse(time.RFC3339, *resp.Task.DueDate)
+	require.NoError(t, err)
+	assert.WithinDuration(t, before.Add(24*time.Hour), dueDate, time.Minute)
+
+	require.Len(t, resp.Subtasks, 1)
+	assert.Equal(t, "Create account for Alice", resp.Subtasks[0].Title)
+	assert.Equal(t, "high", resp.Subtasks[0].Priority) // 未指定时继承模板优先级
+	require.NotNil(t, resp.Subtasks[0].ParentID)
+	assert.Equal(t, parent.ID, *resp.Subtasks[0].ParentID)
+	assert.Equal(t, 2, created)
+
+	helper.AssertExpectations(t)
+}
+
+// TestInstantiateTemplate_TEMPLATE_VARIABLE_MISSING 测试缺少自定义变量
+//
+// 对应 usecases.yaml 中的错误：TEMPLATE_VARIABLE_MISSING
+// HTTP 状态码：400，且不创建任何任务
+func TestInstantiateTemplate_TEMPLATE_VARIABLE_MISSING(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindTemplate(helper.Mock, TestTemplateID, TestUserID, "Onboard {{name}}", `[]`, `[]`, nil)
+
+	helper.RegisterRoute("POST", "/api/templates/:id/instantiate", helper.HandlerDeps.InstantiateTemplateHandler)
+
+	w := helper.PerformRequest("POST", "/api/templates/"+TestTemplateID+"/instantiate", nil)
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "TEMPLATE_VARIABLE_MISSING", resp.Error)
+	assert.True(t, strings.HasSuffix(resp.Message, "name"))
+
+	helper.AssertExpectations(t)
+}
+
+// TestInstantiateTemplate_TEMPLATE_NOT_FOUND 测试模板不存在
+//
+// 对应 usecases.yaml 中的错误：TEMPLATE_NOT_FOUND
+// HTTP 状态码：404
+func TestInstantiateTemplate_TEMPLATE_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "task_templates" WHERE \("id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	helper.RegisterRoute("POST", "/api/templates/:id/instantiate", helper.HandlerDeps.InstantiateTemplateHandler)
+
+	w := helper.PerformRequest("POST", "/api/templates/nonexistent/instantiate", nil)
+
+	assert.Equal(t, consts.StatusNotFound, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestInstantiateTemplate_RollbackOnSubtaskFailure 测试子任务创建失败时主任务一并回滚
+func TestInstantiateTemplate_RollbackOnSubtaskFailure(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindTemplate(helper.Mock, TestTemplateID, TestUserID, "Weekly report",
+		`[]`, `[{"title_pattern":"Collect metrics"}]`, nil)
+
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	MockFindByID(helper.Mock, CreateTestTaskWithID("parent-task-id"))
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).
+		WillReturnError(sql.ErrConnDone)
+
+	// 主任务已插入，但整个工作单元回滚（不发布 task.created）
+	helper.Mock.ExpectRollback()
+	require.NoError(t, helper.EventBus.Subscribe("task.created", func(ctx context.Context, event sharedevents.Event) error {
+		t.Errorf("task.created published for a rolled back task")
+		return nil
+	}))
+
+	helper.RegisterRoute("POST", "/api/templates/:id/instantiate", helper.HandlerDeps.InstantiateTemplateHandler)
+
+	w := helper.PerformRequest("POST", "/api/templates/"+TestTemplateID+"/instantiate", nil)
+
+	assert.Equal(t, consts.StatusInternalServerError, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestCreateTemplate_Success 测试创建模板
+//
+// 对应 usecases.yaml 中的 CreateTemplate 用例的成功路径
+func TestCreateTemplate_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectExec(`INSERT INTO "task_templates"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	helper.RegisterRoute("POST", "/api/templates", helper.HandlerDeps.CreateTemplateHandler)
+
+	reqBody, _ := json.Marshal(dto.TemplateRequest{
+		Name:         "Weekly Review",
+		TitlePattern: "Weekly review {{date}}",
+		Tags:         []string{"review"},
+		DueOffset:    "48h",
+		Subtasks: []dto.SubtaskTemplateItem{
+			{TitlePattern: "Collect metrics", Priority: string(model.PriorityLow)},
+		},
+	})
+	w := helper.PerformRequest("POST", "/api/templates",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.TemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(t, resp.TemplateID)
+	assert.Equal(t, "medium", resp.Priority)
+	require.NotNil(t, resp.DueOffset)
+	assert.Equal(t, "48h0m0s", *resp.DueOffset)
+	assert.Len(t, resp.Subtasks, 1)
+
+	helper.AssertExpectations(t)
+}
+
+// TestCreateTemplate_INVALID_DUE_OFFSET 测试截止偏移格式无效
+func TestCreateTemplate_INVALID_DUE_OFFSET(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.RegisterRoute("POST", "/api/templates", helper.HandlerDeps.CreateTemplateHandler)
+
+	reqBody, _ := json.Marshal(dto.TemplateRequest{
+		Name:         "Bad Offset",
+		TitlePattern: "Task",
+		DueOffset:    "3 days",
+	})
+	w := helper.PerformRequest("POST", "/api/templates",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	helper.AssertExpectations(t)
+}
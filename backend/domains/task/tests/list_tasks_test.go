@@ -26,25 +26,20 @@ func TestListTasks_Success(t *testing.T) {
 	// Mock 查询任务列表（需要 10 列）
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	}).
-		AddRow("task-1", TestUserID, "Task 1", "Description 1", "pending", "high", nil, now, now, nil).
-		AddRow("task-2", TestUserID, "Task 2", "Description 2", "in_progress", "medium", nil, now, now, nil).
-		AddRow("task-3", TestUserID, "Task 3", "Description 3", "completed", "low", nil, now, now, &now)
+		AddRow("task-1", TestUserID, "Task 1", "Description 1", "pending", "high", nil, now, now, nil, nil, nil).
+		AddRow("task-2", TestUserID, "Task 2", "Description 2", "in_progress", "medium", nil, now, now, nil, nil, nil).
+		AddRow("task-3", TestUserID, "Task 3", "Description 3", "completed", "low", nil, now, now, &now, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 		WillReturnRows(rows)
 
-	// Mock 加载 tags (为每个任务)
-	tagsRows1 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
-		WillReturnRows(tagsRows1)
-	tagsRows2 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
-		WillReturnRows(tagsRows2)
-	tagsRows3 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
-		WillReturnRows(tagsRows3)
+	// Mock 批量加载 tags（整页任务一次查询）
+	tagsRows := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}).
+		AddRow("task-1", "urgent", "#FF0000")
+	helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN`).
+		WillReturnRows(tagsRows)
 
 	// 创建 HTTP 上下文
 	c := app.NewContext(0)
@@ -84,16 +79,16 @@ func TestListTasks_WithFilters(t *testing.T) {
 	// Mock 查询任务列表（无过滤条件，需要 10 列）
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	}).
-		AddRow("task-1", TestUserID, "High Priority Task", "Description", "pending", "high", nil, now, now, nil)
+		AddRow("task-1", TestUserID, "High Priority Task", "Description", "pending", "high", nil, now, now, nil, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 		WillReturnRows(rows)
 
 	// Mock 加载 tags
-	tagsRows := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
+	tagsRows := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"})
+	helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN`).
 		WillReturnRows(tagsRows)
 
 	// 注册路由
@@ -129,7 +124,7 @@ func TestListTasks_EmptyResult(t *testing.T) {
 
 	// Mock 查询返回空结果（需要 9 列）
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	})
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
@@ -222,22 +217,18 @@ func TestListTasks_Pagination(t *testing.T) {
 	// Mock 第 2 页的数据（需要 10 列）
 	now := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
 	}).
-		AddRow("task-11", TestUserID, "Task 11", "Description 11", "pending", "medium", nil, now, now, nil).
-		AddRow("task-12", TestUserID, "Task 12", "Description 12", "pending", "low", nil, now, now, nil)
+		AddRow("task-11", TestUserID, "Task 11", "Description 11", "pending", "medium", nil, now, now, nil, nil, nil).
+		AddRow("task-12", TestUserID, "Task 12", "Description 12", "pending", "low", nil, now, now, nil, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks"`).
 		WillReturnRows(rows)
 
-	// Mock 加载 tags (2个任务)
-	tagsRows1 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
-		WillReturnRows(tagsRows1)
-
-	tagsRows2 := sqlmock.NewRows([]string{"tag_name", "tag_color"})
-	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
-		WillReturnRows(tagsRows2)
+	// Mock 批量加载 tags (2个任务，一次查询)
+	tagsRows := sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"})
+	helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id" IN`).
+		WillReturnRows(tagsRows)
 
 	// 注册路由
 	helper.RegisterRoute("GET", "/api/tasks", func(ctx context.Context, c *app.RequestContext) {
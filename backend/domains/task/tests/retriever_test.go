@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/retriever"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// documentIDs 返回资料的 ID（按顺序）
+func documentIDs(docs []retrieval.Document) []string {
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+// TestTaskRetriever_StructuredFirst 测试问题包含时间范围时，按截止时间列出的未完成任务在语义搜索结果之前
+func TestTaskRetriever_StructuredFirst(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	teeth := createIndexedTask(t, helper, "Teeth cleaning", "Book an appointment with the dentist", "health")
+	bill := createIndexedTask(t, helper, "Pay electricity bill", "", "finance")
+	due := time.Now().Add(time.Hour)
+	bill.DueDate = &due
+	done := CreateCompletedTestTask()
+	require.NoError(t, helper.Semantic.Drain(context.Background()))
+
+	// 结构化条件：本周截止，按截止时间升序（已完成的任务被排除）
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "tasks" WHERE .+"due_date" <= `).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	MockListTasks(helper.Mock, []*model.Task{bill, done})
+	// 语义搜索
+	MockCount(helper.Mock, 1)
+	MockListTasks(helper.Mock, []*model.Task{teeth})
+
+	docs, err := retriever.New(helper.TaskService, helper.Semantic).
+		Retrieve(context.Background(), TestUserID, "What's due this week? Also the dentist", 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{bill.ID, teeth.ID}, documentIDs(docs))
+	assert.Equal(t, retriever.Source, docs[0].Source)
+	assert.Equal(t, "Pay electricity bill", docs[0].Title)
+	assert.Contains(t, docs[0].Content, "状态：pending；优先级：medium；截止：")
+	assert.Contains(t, docs[0].Content, "标签：finance")
+	assert.Contains(t, docs[1].Content, "Book an appointment with the dentist")
+	helper.AssertExpectations(t)
+}
+
+// TestTaskRetriever_SemanticFirst 测试没有结构化条件时语义搜索结果在前，未完成的任务补充在后，不重复
+func TestTaskRetriever_SemanticFirst(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	teeth := createIndexedTask(t, helper, "Teeth cleaning", "Book an appointment with the dentist", "health")
+	bill := createIndexedTask(t, helper, "Pay electricity bill", "", "finance")
+	require.NoError(t, helper.Semantic.Drain(context.Background()))
+
+	MockCount(helper.Mock, 2)
+	MockListTasks(helper.Mock, []*model.Task{bill, teeth})
+	MockCount(helper.Mock, 1)
+	MockListTasks(helper.Mock, []*model.Task{teeth})
+
+	docs, err := retriever.New(helper.TaskService, helper.Semantic).
+		Retrieve(context.Background(), TestUserID, "When is the dentist?", 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{teeth.ID, bill.ID}, documentIDs(docs))
+	assert.Greater(t, docs[0].Score, 0.0)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	taskhttp "github.com/erweixin/go-genai-stack/backend/domains/task/http"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterRoutes_Generation 测试 generation 中间件（如剩余额度响应头）只用于调用模型的路由
+func TestRegisterRoutes_Generation(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	jwtService := authservice.NewJWTService("test-secret", time.Hour, time.Hour, "test")
+	token, _, err := jwtService.GenerateAccessToken(TestUserID, "test@example.com")
+	require.NoError(t, err)
+
+	// 中间件设置响应头后直接返回，不执行 Handler
+	generation := func(ctx context.Context, c *app.RequestContext) {
+		c.Header("X-Generation", "1")
+		c.AbortWithStatus(consts.StatusNoContent)
+	}
+	taskhttp.RegisterRoutes(helper.Server.Group("/api"), helper.HandlerDeps, middleware.NewAuthMiddleware(jwtService), generation)
+
+	auth := map[string]string{"Authorization": "Bearer " + token}
+	w := helper.PerformRequest("POST", "/api/tasks/"+TestTaskID+"/breakdown", nil, auth)
+	assert.Equal(t, consts.StatusNoContent, w.Code)
+	assert.Equal(t, "1", string(w.Header().Peek("X-Generation")))
+
+	// 不调用模型的路由不使用 generation 中间件
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	w = helper.PerformRequest("GET", "/api/tasks/"+TestTaskID, nil, auth)
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, w.Header().Peek("X-Generation"))
+
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/embedding"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/vectorstore"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createIndexedTask 通过 TaskService 创建任务（发布 task.created）
+func createIndexedTask(t *testing.T, helper *TestHelper, title, description string, tags ...string) *model.Task {
+	t.Helper()
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`INSERT INTO "tasks"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	if len(tags) > 0 {
+		helper.Mock.ExpectExec(`INSERT INTO "task_tags"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	helper.Mock.ExpectCommit()
+
+	output, err := helper.TaskService.CreateTask(context.Background(), service.CreateTaskInput{
+		UserID:      TestUserID,
+		Title:       title,
+		Description: description,
+		Priority:    model.PriorityMedium,
+		Tags:        tags,
+	})
+	require.NoError(t, err)
+	return output.Task
+}
+
+// performSemanticSearch 调用 GET /api/tasks/semantic-search
+func performSemanticSearch(t *testing.T, helper *TestHelper, query string) (int, dto.SemanticSearchResponse) {
+	t.Helper()
+	helper.RegisterRoute("GET", "/api/tasks/semantic-search", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.SemanticSearchHandler(ctx, c)
+	})
+	w := helper.PerformRequest("GET", "/api/tasks/semantic-search?q="+query, nil)
+
+	var resp dto.SemanticSearchResponse
+	if w.Code == consts.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w.Code, resp
+}
+
+// TestSemanticSearch_Success 测试任务事件同步向量后按相似度返回任务
+func TestSemanticSearch_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	teeth := createIndexedTask(t, helper, "Teeth cleaning", "Book an appointment with the dentist", "health")
+	bill := createIndexedTask(t, helper, "Pay electricity bill", "", "finance")
+	require.NoError(t, helper.Semantic.Drain(context.Background()))
+	assert.Equal(t, 2, helper.Vectors.Len(service.TaskEmbeddingCollection))
+
+	// 只加载有相关性的任务（bill 与查询没有共同的词）
+	MockCount(helper.Mock, 1)
+	MockListTasks(helper.Mock, []*model.Task{teeth})
+
+	code, resp := performSemanticSearch(t, helper, "dentist+appointment")
+
+	require.Equal(t, consts.StatusOK, code)
+	require.Len(t, resp.Tasks, 1)
+	assert.Equal(t, teeth.ID, resp.Tasks[0].TaskID)
+	assert.Equal(t, []string{"health"}, resp.Tasks[0].Tags)
+	require.NotNil(t, resp.Tasks[0].Score)
+	assert.Greater(t, *resp.Tasks[0].Score, 0.0)
+	assert.NotEqual(t, bill.ID, resp.Tasks[0].TaskID)
+	helper.AssertExpectations(t)
+}
+
+// TestSemanticSearch_SyncUpdateAndDelete 测试修改标题后重新生成向量，删除任务后删除向量
+func TestSemanticSearch_SyncUpdateAndDelete(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	ctx := context.Background()
+
+	task := CreateTestTaskWithID("task-1")
+	task.Title = "Call plumber"
+
+	// 修改标题
+	MockCompleteUpdate(helper.Mock, task)
+	title := "Schedule dentist visit"
+	_, err := helper.TaskService.UpdateTask(ctx, service.UpdateTaskInput{UserID: TestUserID, TaskID: task.ID, Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, helper.Semantic.Drain(ctx))
+
+	matches := searchVectors(t, helper, "dentist")
+	require.Len(t, matches, 1)
+	assert.Equal(t, "task-1", matches[0].ID)
+	assert.Greater(t, matches[0].Score, 0.0)
+
+	// 只修改优先级不重新生成向量
+	MockCompleteUpdate(helper.Mock, task)
+	priority := model.PriorityHigh
+	_, err = helper.TaskService.UpdateTask(ctx, service.UpdateTaskInput{UserID: TestUserID, TaskID: task.ID, Priority: &priority})
+	require.NoError(t, err)
+	require.NoError(t, helper.Semantic.Drain(ctx))
+	assert.Equal(t, matches, searchVectors(t, helper, "dentist"))
+
+	// 删除任务
+	MockFindByID(helper.Mock, task)
+	helper.Mock.ExpectExec(`DELETE FROM "tasks"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	_, err = helper.TaskService.DeleteTask(ctx, service.DeleteTaskInput{UserID: TestUserID, TaskID: task.ID})
+	require.NoError(t, err)
+	require.NoError(t, helper.Semantic.Drain(ctx))
+	assert.Zero(t, helper.Vectors.Len(service.TaskEmbeddingCollection))
+
+	helper.AssertExpectations(t)
+}
+
+// TestSemanticSearch_FilteredByUser 测试只返回当前用户的任务，已不存在的任务被忽略
+func TestSemanticSearch_FilteredByUser(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	ctx := context.Background()
+
+	embedder := embedding.NewHashEmbedder(0)
+	vectors, err := embedder.Embed(ctx, []string{"dentist"})
+	require.NoError(t, err)
+	require.NoError(t, helper.Vectors.Upsert(ctx, service.TaskEmbeddingCollection,
+		vectorstore.Record{ID: "other-task", OwnerID: "other-user", Model: embedder.Model(), Vector: vectors[0]},
+		vectorstore.Record{ID: "stale-task", OwnerID: TestUserID, Model: embedder.Model(), Vector: vectors[0]},
+	))
+
+	// stale-task 的创建事务已回滚（数据库中不存在）
+	MockCount(helper.Mock, 0)
+	MockListTasks(helper.Mock, nil)
+
+	code, resp := performSemanticSearch(t, helper, "dentist")
+
+	require.Equal(t, consts.StatusOK, code)
+	assert.Empty(t, resp.Tasks)
+	helper.AssertExpectations(t)
+}
+
+// TestSemanticSearch_INVALID_QUERY 测试查询为空
+func TestSemanticSearch_INVALID_QUERY(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	code, _ := performSemanticSearch(t, helper, "+")
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	helper.AssertExpectations(t)
+}
+
+// searchVectors 直接在向量存储中搜索当前用户的任务
+func searchVectors(t *testing.T, helper *TestHelper, query string) []vectorstore.Match {
+	t.Helper()
+	embedder := embedding.NewHashEmbedder(0)
+	vectors, err := embedder.Embed(context.Background(), []string{query})
+	require.NoError(t, err)
+	matches, err := helper.Vectors.Search(context.Background(), service.TaskEmbeddingCollection, vectorstore.Query{
+		OwnerID: TestUserID, Model: embedder.Model(), Vector: vectors[0], Limit: 10,
+	})
+	require.NoError(t, err)
+	return matches
+}
@@ -0,0 +1,297 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// performSnooze 注册推迟路由并发送请求
+func performSnooze(helper *TestHelper, taskID, until string) (int, []byte) {
+	helper.RegisterRoute("POST", "/api/tasks/:id/snooze", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.SnoozeTaskHandler(ctx, c)
+	})
+
+	reqBody, _ := json.Marshal(dto.SnoozeTaskRequest{Until: until})
+	w := helper.PerformRequest("POST", "/api/tasks/"+taskID+"/snooze",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+	return w.Code, w.Body.Bytes()
+}
+
+// TestSnoozeTask_Success 测试成功推迟任务（截止日期不变）
+func TestSnoozeTask_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID("task-123")
+	dueDate := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	task.DueDate = &dueDate
+	MockCompleteUpdate(helper.Mock, task)
+
+	until := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	code, body := performSnooze(helper, "task-123", until)
+
+	assert.Equal(t, consts.StatusOK, code)
+
+	var resp dto.GetTaskResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.NotNil(t, resp.HiddenUntil)
+	assert.Equal(t, until, *resp.HiddenUntil)
+	require.NotNil(t, resp.DueDate)
+	assert.Equal(t, dueDate.Format(time.RFC3339), *resp.DueDate)
+
+	helper.AssertExpectations(t)
+}
+
+// TestSnoozeTask_INVALID_SNOOZE_TIME 测试推迟到过去的时间
+//
+// 对应 usecases.yaml 中的错误：INVALID_SNOOZE_TIME
+// HTTP 状态码：400
+func TestSnoozeTask_INVALID_SNOOZE_TIME(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID("task-123"))
+
+	code, body := performSnooze(helper, "task-123", time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "INVALID_SNOOZE_TIME", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestSnoozeTask_InvalidFormat 测试推迟时间格式错误（不访问数据库）
+func TestSnoozeTask_InvalidFormat(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	code, body := performSnooze(helper, "task-123", "next monday")
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "INVALID_SNOOZE_TIME", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestSnoozeTask_TASK_ALREADY_COMPLETED 测试推迟已完成的任务
+func TestSnoozeTask_TASK_ALREADY_COMPLETED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateCompletedTestTask()
+	task.ID = "task-123"
+	MockFindByID(helper.Mock, task)
+
+	code, _ := performSnooze(helper, "task-123", time.Now().Add(time.Hour).Format(time.RFC3339))
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	helper.AssertExpectations(t)
+}
+
+// TestUnsnoozeTask_Success 测试取消推迟
+func TestUnsnoozeTask_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID("task-123")
+	hiddenUntil := time.Now().Add(48 * time.Hour)
+	task.HiddenUntil = &hiddenUntil
+	MockCompleteUpdate(helper.Mock, task)
+
+	helper.RegisterRoute("POST", "/api/tasks/:id/unsnooze", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.UnsnoozeTaskHandler(ctx, c)
+	})
+	w := helper.PerformRequest("POST", "/api/tasks/task-123/unsnooze", nil)
+
+	assert.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.GetTaskResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Nil(t, resp.HiddenUntil)
+
+	helper.AssertExpectations(t)
+}
+
+// TestUnsnoozeTask_TASK_NOT_SNOOZED 测试取消未推迟的任务
+//
+// 对应 usecases.yaml 中的错误：TASK_NOT_SNOOZED
+// HTTP 状态码：400
+func TestUnsnoozeTask_TASK_NOT_SNOOZED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID("task-123"))
+
+	helper.RegisterRoute("POST", "/api/tasks/:id/unsnooze", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.UnsnoozeTaskHandler(ctx, c)
+	})
+	w := helper.PerformRequest("POST", "/api/tasks/task-123/unsnooze", nil)
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "TASK_NOT_SNOOZED", resp.Error)
+
+	helper.AssertExpectations(t)
+}
+
+// TestListTasks_SnoozeFilter 测试默认排除推迟中的任务，include_snoozed=true 时包含
+func TestListTasks_SnoozeFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		countQuery string
+	}{
+		{
+			name:       "默认排除推迟中的任务",
+			query:      "",
+			countQuery: `^SELECT COUNT\(\*\) FROM "tasks" WHERE \(\("user_id" = 'test-user-123'\) AND \(\("hidden_until" IS NULL\) OR \("hidden_until" <= CURRENT_TIMESTAMP\)\)\)$`,
+		},
+		{
+			name:       "include_snoozed=true 包含推迟中的任务",
+			query:      "?include_snoozed=true",
+			countQuery: `^SELECT COUNT\(\*\) FROM "tasks" WHERE \("user_id" = 'test-user-123'\)$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewTestHelper(t)
+			defer helper.Close()
+
+			helper.Mock.ExpectQuery(tt.countQuery).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			MockListTasks(helper.Mock, nil)
+
+			helper.RegisterRoute("GET", "/api/tasks", func(ctx context.Context, c *app.RequestContext) {
+				SetAuthContext(c, TestUserID)
+				helper.HandlerDeps.ListTasksHandler(ctx, c)
+			})
+			w := helper.PerformRequest("GET", "/api/tasks"+tt.query, nil)
+
+			assert.Equal(t, consts.StatusOK, w.Code)
+			helper.AssertExpectations(t)
+		})
+	}
+}
+
+// TestSnoozeScheduler_RunOnce 测试推迟到期后清除 hidden_until 并发布 TaskResurfaced
+func TestSnoozeScheduler_RunOnce(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	expired := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	// 两个推迟已到期的任务
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).
+		AddRow("task-1", TestUserID, "Task 1", "", "pending", "high", nil, TestTime, TestTime, nil, nil, expired).
+		AddRow("task-2", TestUserID, "Task 2", "", "pending", "low", nil, TestTime, TestTime, nil, nil, expired)
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \(\("hidden_until" IS NOT NULL\) AND \("hidden_until" <= '.+'\)\) ORDER BY "hidden_until" ASC LIMIT 100`).
+		WillReturnRows(rows)
+	helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags"`).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}))
+
+	// task-1 清除成功；task-2 已被用户重新推迟（条件更新影响 0 行）
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`UPDATE "tasks" SET "hidden_until"=NULL WHERE \(\("id" = 'task-1'\) AND \("hidden_until" = '.+'\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+	helper.Mock.ExpectBegin()
+	helper.Mock.ExpectExec(`UPDATE "tasks" SET "hidden_until"=NULL WHERE \(\("id" = 'task-2'\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	helper.Mock.ExpectCommit()
+
+	bus := sharedevents.NewDefaultEventBus()
+	var received []*events.TaskResurfacedEvent
+	require.NoError(t, bus.Subscribe("task.resurfaced", func(ctx context.Context, e sharedevents.Event) error {
+		assert.Equal(t, "task", e.Source())
+		received = append(received, e.Payload().(*events.TaskResurfacedEvent))
+		return nil
+	}))
+
+	scheduler := service.NewSnoozeScheduler(repository.NewTaskRepository(helper.DB, "postgres"), bus, persistence.NewTxManager(helper.DB), time.Minute)
+	published, err := scheduler.RunOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	require.Len(t, received, 1)
+	assert.Equal(t, "task-1", received[0].TaskID)
+	assert.Equal(t, TestUserID, received[0].UserID)
+	assert.True(t, expired.Equal(received[0].HiddenUntil))
+
+	helper.AssertExpectations(t)
+}
+
+// TestSnoozeScheduler_PublishFailed 测试发布失败时回滚清除，下次检查时重新发布
+func TestSnoozeScheduler_PublishFailed(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	expired := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	expectExpired := func() {
+		helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \(\("hidden_until" IS NOT NULL\)`).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+			}).AddRow("task-1", TestUserID, "Task 1", "", "pending", "high", nil, TestTime, TestTime, nil, nil, expired))
+		helper.Mock.ExpectQuery(`SELECT "task_id", "tag_name", "tag_color" FROM "task_tags"`).
+			WillReturnRows(sqlmock.NewRows([]string{"task_id", "tag_name", "tag_color"}))
+		helper.Mock.ExpectBegin()
+		helper.Mock.ExpectExec(`UPDATE "tasks" SET "hidden_until"=NULL WHERE \(\("id" = 'task-1'\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	bus := sharedevents.NewDefaultEventBus()
+	failing := true
+	var received int
+	require.NoError(t, bus.Subscribe("task.resurfaced", func(ctx context.Context, e sharedevents.Event) error {
+		if failing {
+			return errors.New("broker unavailable")
+		}
+		received++
+		return nil
+	}))
+	scheduler := service.NewSnoozeScheduler(repository.NewTaskRepository(helper.DB, "postgres"), bus, persistence.NewTxManager(helper.DB), time.Minute)
+
+	// 第一次：发布失败，清除回滚
+	expectExpired()
+	helper.Mock.ExpectRollback()
+
+	published, err := scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	helper.AssertExpectations(t)
+
+	// 第二次：任务仍然到期，重新清除并发布
+	failing = false
+	expectExpired()
+	helper.Mock.ExpectCommit()
+
+	published, err = scheduler.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 1, received)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockListTagNames Mock 读取用户已有的标签
+func mockListTagNames(mock sqlmock.Sqlmock, names ...string) {
+	rows := sqlmock.NewRows([]string{"tag_name"})
+	for _, name := range names {
+		rows.AddRow(name)
+	}
+	mock.ExpectQuery(`SELECT "tt"."tag_name" FROM "task_tags" AS "tt"`).
+		WillReturnRows(rows)
+}
+
+// performSuggestion 注册建议路由并发送请求（body 为 nil 时不带请求体）
+func performSuggestion(helper *TestHelper, method, action string, body interface{}) (int, []byte) {
+	path := "/api/tasks/:id/suggestion"
+	url := "/api/tasks/" + TestTaskID + "/suggestion"
+	switch action {
+	case "accept":
+		helper.RegisterRoute(method, path+"/accept", helper.HandlerDeps.AcceptSuggestionHandler)
+		url += "/accept"
+	case "reject":
+		helper.RegisterRoute(method, path+"/reject", helper.HandlerDeps.RejectSuggestionHandler)
+		url += "/reject"
+	default:
+		helper.RegisterRoute(method, path, helper.HandlerDeps.GetSuggestionHandler)
+	}
+
+	if body == nil {
+		w := helper.PerformRequest(method, url, nil)
+		return w.Code, w.Body.Bytes()
+	}
+	reqBody, _ := json.Marshal(body)
+	w := helper.PerformRequest(method, url,
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+	return w.Code, w.Body.Bytes()
+}
+
+// TestCreateTask_PublishesTaskCreated 测试设置事件总线后创建任务发布 task.created
+func TestCreateTask_PublishesTaskCreated(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	bus := sharedevents.NewDefaultEventBus()
+	var received []*events.TaskCreatedEvent
+	require.NoError(t, bus.Subscribe("task.created", func(ctx context.Context, event sharedevents.Event) error {
+		received = append(received, event.Payload().(*events.TaskCreatedEvent))
+		return nil
+	}))
+	taskService := service.NewTaskService(repository.NewTaskRepository(helper.DB, "postgres")).WithEventBus(bus)
+
+	helper.Mock.ExpectBegin()
+	MockInsertTask(helper.Mock, nil)
+	MockInsertTags(helper.Mock, "", []model.Tag{{Name: "work"}})
+	helper.Mock.ExpectCommit()
+
+	output, err := taskService.CreateTask(context.Background(), service.CreateTaskInput{
+		UserID:      TestUserID,
+		Title:       "写周报",
+		Description: "本周进展",
+		Tags:        []string{"work"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, output.Task.ID, received[0].TaskID)
+	assert.Equal(t, TestUserID, received[0].UserID)
+	assert.Equal(t, "本周进展", received[0].Description)
+	assert.Equal(t, []string{"work"}, received[0].Tags)
+	assert.Nil(t, received[0].ParentID)
+	helper.AssertExpectations(t)
+}
+
+// TestEnrichTask_Success 测试为新任务生成建议
+//
+// 对应 usecases.yaml 中的 EnrichTask 用例：候选标签不包含任务已有的标签，建议保存为 pending
+func TestEnrichTask_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithTags("work")
+	task.ID = TestTaskID
+	task.Title = "修复线上支付故障"
+	MockFindByID(helper.Mock, task)
+	mockListTagNames(helper.Mock, "work", "bug", "home")
+	helper.LLM.Enqueue(mock.Response{Content: `{"tags": ["bug"], "priority": "high"}`})
+	helper.Mock.ExpectExec(`INSERT INTO "task_suggestions" .+ VALUES \('\[\]', .+, 'mock-model', 'medium', 'high', FALSE, 'pending', '\["bug"\]', 'test-task-123', 'test-user-123'\) ON CONFLICT DO NOTHING`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	suggestion, err := helper.Enrichment.Enrich(context.Background(), events.NewTaskCreatedEvent(task))
+
+	require.NoError(t, err)
+	require.NotNil(t, suggestion)
+	assert.Equal(t, []string{"bug"}, suggestion.Tags)
+	assert.Equal(t, model.PriorityHigh, suggestion.Priority)
+	assert.Equal(t, model.PriorityMedium, suggestion.OriginalPriority)
+	assert.Equal(t, "mock-model", suggestion.Model)
+
+	// 候选标签写入提示词和 JSON Schema（enum），按用户计算额度
+	requests := helper.LLM.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, TestUserID, requests[0].User)
+	require.NotNil(t, requests[0].ResponseFormat)
+	assert.Equal(t, "task_enrichment", requests[0].ResponseFormat.Name)
+	assert.Contains(t, string(requests[0].ResponseFormat.Schema), `"enum":["bug","home"]`)
+	last := requests[0].Messages[len(requests[0].Messages)-1]
+	assert.Contains(t, last.Content, "已有标签：bug, home")
+	assert.Contains(t, last.Content, "任务标题：修复线上支付故障")
+
+	helper.AssertExpectations(t)
+}
+
+// TestEnrichTask_NoSuggestion 测试没有新内容时不保存建议
+func TestEnrichTask_NoSuggestion(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID(TestTaskID)
+	MockFindByID(helper.Mock, task)
+	mockListTagNames(helper.Mock)
+	helper.LLM.Enqueue(mock.Response{Content: `{"tags": [], "priority": "medium"}`})
+
+	suggestion, err := helper.Enrichment.Enrich(context.Background(), events.NewTaskCreatedEvent(task))
+
+	require.NoError(t, err)
+	assert.Nil(t, suggestion)
+	require.Len(t, helper.LLM.Requests(), 1)
+	assert.Contains(t, string(helper.LLM.Requests()[0].ResponseFormat.Schema), `"maxItems":0`)
+	helper.AssertExpectations(t)
+}
+
+// TestEnrichTask_SkipSubtask 测试子任务不生成建议（不调用模型）
+func TestEnrichTask_SkipSubtask(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithID(TestTaskID)
+	parentID := "parent-task-id"
+	task.ParentID = &parentID
+	MockFindByID(helper.Mock, task)
+
+	suggestion, err := helper.Enrichment.Enrich(context.Background(), &events.TaskCreatedEvent{TaskID: TestTaskID})
+
+	require.NoError(t, err)
+	assert.Nil(t, suggestion)
+	assert.Empty(t, helper.LLM.Requests())
+	helper.AssertExpectations(t)
+}
+
+// TestGetSuggestion_NOT_FOUND 测试任务没有建议
+//
+// HTTP 状态码：404
+func TestGetSuggestion_NOT_FOUND(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "task_suggestions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	code, body := performSuggestion(helper, "GET", "", nil)
+
+	assert.Equal(t, consts.StatusNotFound, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "SUGGESTION_NOT_FOUND", resp.Error)
+	helper.AssertExpectations(t)
+}
+
+// TestAcceptSuggestion_Partial 测试只接受部分标签、不接受优先级
+//
+// 对应 usecases.yaml 中的 AcceptSuggestion：任务更新和建议状态在同一事务中保存
+func TestAcceptSuggestion_Partial(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithTags("work")
+	task.ID = TestTaskID
+	MockFindByID(helper.Mock, task)
+	MockFindSuggestion(helper.Mock, TestTaskID, `["bug","home"]`, "high", "pending", `[]`)
+
+	helper.Mock.ExpectBegin()
+	MockFindByID(helper.Mock, task)
+	MockUpdateTask(helper.Mock, task)
+	MockDeleteOldTags(helper.Mock, TestTaskID)
+	helper.Mock.ExpectExec(`INSERT INTO "task_tags" .+'bug'`).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	helper.Mock.ExpectExec(`UPDATE "task_suggestions" SET "accepted_tags"='\["bug"\]',"priority_accepted"=FALSE,.+"status"='accepted' WHERE`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	helper.Mock.ExpectCommit()
+
+	applyPriority := false
+	code, body := performSuggestion(helper, "POST", "accept", dto.AcceptSuggestionRequest{
+		Tags:          []string{"bug"},
+		ApplyPriority: &applyPriority,
+	})
+	require.Equal(t, consts.StatusOK, code, string(body))
+
+	var resp dto.AcceptSuggestionResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, []string{"work", "bug"}, resp.Task.Tags)
+	assert.Equal(t, "medium", resp.Task.Priority)
+	assert.Equal(t, "accepted", resp.Suggestion.Status)
+	assert.Equal(t, []string{"bug"}, resp.Suggestion.AcceptedTags)
+	assert.False(t, resp.Suggestion.PriorityAccepted)
+	assert.NotNil(t, resp.Suggestion.ResolvedAt)
+
+	helper.AssertExpectations(t)
+}
+
+// TestAcceptSuggestion_TAG_NOT_SUGGESTED 测试不能接受建议之外的标签
+//
+// 对应 rules.md 中的 R11.2，HTTP 状态码：400，不修改任务
+func TestAcceptSuggestion_TAG_NOT_SUGGESTED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	MockFindSuggestion(helper.Mock, TestTaskID, `["bug"]`, "high", "pending", `[]`)
+
+	code, body := performSuggestion(helper, "POST", "accept", dto.AcceptSuggestionRequest{Tags: []string{"home"}})
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "TAG_NOT_SUGGESTED", resp.Error)
+	helper.AssertExpectations(t)
+}
+
+// TestRejectSuggestion_Success 测试拒绝建议（请求体为空，任务不变）
+func TestRejectSuggestion_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	MockFindSuggestion(helper.Mock, TestTaskID, `["bug"]`, "high", "pending", `[]`)
+	helper.Mock.ExpectExec(`UPDATE "task_suggestions" SET .+"status"='rejected'`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	code, body := performSuggestion(helper, "POST", "reject", nil)
+	require.Equal(t, consts.StatusOK, code, string(body))
+
+	var resp dto.SuggestionResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "rejected", resp.Status)
+	assert.Empty(t, resp.AcceptedTags)
+	helper.AssertExpectations(t)
+}
+
+// TestRejectSuggestion_ALREADY_RESOLVED 测试已处理的建议不能再次处理
+//
+// 对应 rules.md 中的 R11.3，HTTP 状态码：400
+func TestRejectSuggestion_ALREADY_RESOLVED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockFindByID(helper.Mock, CreateTestTaskWithID(TestTaskID))
+	MockFindSuggestion(helper.Mock, TestTaskID, `["bug"]`, "high", "accepted", `["bug"]`)
+
+	code, body := performSuggestion(helper, "POST", "reject", nil)
+
+	assert.Equal(t, consts.StatusBadRequest, code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &resp))
+	assert.Equal(t, "SUGGESTION_ALREADY_RESOLVED", resp.Error)
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tool"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskTools_Definitions 测试任务工具集的工具和确认要求
+func TestTaskTools_Definitions(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	registry, err := tools.NewRegistry(helper.TaskService, TestUserID)
+	require.NoError(t, err)
+
+	var names []string
+	for _, def := range registry.Definitions() {
+		names = append(names, def.Name)
+	}
+	assert.Equal(t, []string{tools.ListTasks, tools.CreateTask, tools.UpdateTask, tools.CompleteTask}, names)
+
+	// 修改已有任务的工具需要用户确认
+	assert.False(t, registry.RequiresConfirmation(llmmodel.ToolCall{Name: tools.ListTasks}))
+	assert.False(t, registry.RequiresConfirmation(llmmodel.ToolCall{Name: tools.CreateTask}))
+	assert.True(t, registry.RequiresConfirmation(llmmodel.ToolCall{Name: tools.UpdateTask}))
+	assert.True(t, registry.RequiresConfirmation(llmmodel.ToolCall{Name: tools.CompleteTask}))
+}
+
+// TestTaskTools_ListTasks 测试 list_tasks 只查询当前用户的任务
+func TestTaskTools_ListTasks(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTaskWithTags("finance")
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "tasks" WHERE .*"user_id" = 'test-user-123'.*"tag_name" = 'finance'`).
+		WillReturnRows(helper.Mock.NewRows([]string{"count"}).AddRow(1))
+	MockListTasks(helper.Mock, []*model.Task{task})
+
+	registry, err := tools.NewRegistry(helper.TaskService, TestUserID)
+	require.NoError(t, err)
+	result := registry.Call(helper.Ctx, llmmodel.ToolCall{
+		ID:        "call-1",
+		Name:      tools.ListTasks,
+		Arguments: `{"status":null,"priority":null,"tag":"finance","keyword":null,"due_before":null,"limit":10}`,
+	})
+
+	require.Equal(t, tool.StatusOK, result.Status, result.Error)
+	var output struct {
+		Tasks []struct {
+			ID   string   `json:"id"`
+			Tags []string `json:"tags"`
+		} `json:"tasks"`
+		Total int `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(result.Output, &output))
+	assert.Equal(t, 1, output.Total)
+	require.Len(t, output.Tasks, 1)
+	assert.Equal(t, task.ID, output.Tasks[0].ID)
+	assert.Equal(t, []string{"finance"}, output.Tasks[0].Tags)
+
+	helper.AssertExpectations(t)
+}
+
+// TestTaskTools_CompleteTask_OtherUser 测试工具不能操作其他用户的任务
+func TestTaskTools_CompleteTask_OtherUser(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	task := CreateTestTask()
+	task.UserID = "other-user"
+	MockFindByID(helper.Mock, task)
+
+	registry, err := tools.NewRegistry(helper.TaskService, TestUserID)
+	require.NoError(t, err)
+	result := registry.Call(helper.Ctx, llmmodel.ToolCall{
+		ID:        "call-1",
+		Name:      tools.CompleteTask,
+		Arguments: `{"task_id":"` + TestTaskID + `"}`,
+	})
+
+	assert.Equal(t, tool.StatusError, result.Status)
+	assert.Contains(t, result.Error, "UNAUTHORIZED_ACCESS")
+	helper.AssertExpectations(t)
+}
@@ -22,8 +22,8 @@ func TestUpdateTask_Success(t *testing.T) {
 
 	// Mock 查询任务
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Old Title", "Old Description", "pending", "low", nil, time.Now(), time.Now(), nil)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Old Title", "Old Description", "pending", "low", nil, time.Now(), time.Now(), nil, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
 		WillReturnRows(rows)
@@ -34,12 +34,14 @@ func TestUpdateTask_Success(t *testing.T) {
 		WillReturnRows(tagsRows)
 
 	// Mock 更新任务（goqu 将参数值直接嵌入到 SQL 中）
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`UPDATE "tasks" SET`).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Mock 删除旧 tags（在更新任务后）（goqu 将参数值直接嵌入到 SQL 中）
 	helper.Mock.ExpectExec(`DELETE FROM "task_tags" WHERE \("task_id"`).
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	helper.Mock.ExpectCommit()
 
 	// 注册路由
 	helper.RegisterRoute("PUT", "/api/tasks/:id", func(ctx context.Context, c *app.RequestContext) {
@@ -113,8 +115,8 @@ func TestUpdateTask_TASK_ALREADY_COMPLETED(t *testing.T) {
 	// Mock 查询任务（已完成状态）
 	completedAt := time.Now()
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Test Task", "Description", "completed", "medium", nil, time.Now(), time.Now(), &completedAt)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Test Task", "Description", "completed", "medium", nil, time.Now(), time.Now(), &completedAt, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
 		WillReturnRows(rows)
@@ -162,8 +164,8 @@ func TestUpdateTask_INVALID_PRIORITY(t *testing.T) {
 
 	// Mock 查询任务
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Test Task", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
 		WillReturnRows(rows)
@@ -207,8 +209,8 @@ func TestUpdateTask_UPDATE_FAILED(t *testing.T) {
 
 	// Mock 查询成功
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at",
-	}).AddRow("task-123", TestUserID, "Old Title", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil)
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Old Title", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil, nil, nil)
 
 	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
 		WillReturnRows(rows)
@@ -219,8 +221,10 @@ func TestUpdateTask_UPDATE_FAILED(t *testing.T) {
 		WillReturnRows(tagsRows)
 
 	// Mock 更新失败（先尝试更新任务）
+	helper.Mock.ExpectBegin()
 	helper.Mock.ExpectExec(`UPDATE "tasks" SET`).
 		WillReturnError(sql.ErrConnDone)
+	helper.Mock.ExpectRollback()
 
 	// 注册路由
 	helper.RegisterRoute("PUT", "/api/tasks/:id", func(ctx context.Context, c *app.RequestContext) {
@@ -245,3 +249,53 @@ func TestUpdateTask_UPDATE_FAILED(t *testing.T) {
 
 	helper.AssertExpectations(t)
 }
+
+// TestUpdateTask_CONTENT_BLOCKED 测试更新的描述命中屏蔽词
+//
+// 对应 usecases.yaml 中的错误：CONTENT_BLOCKED
+// 错误消息："内容未通过安全检查"
+// HTTP 状态码：400
+func TestUpdateTask_CONTENT_BLOCKED(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// Mock 查询任务
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).AddRow("task-123", TestUserID, "Old Title", "Description", "pending", "medium", nil, time.Now(), time.Now(), nil, nil, nil)
+
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE \("id"`).
+		WillReturnRows(rows)
+
+	// Mock 加载 tags
+	tagsRows := sqlmock.NewRows([]string{"tag_name", "tag_color"})
+	helper.Mock.ExpectQuery(`SELECT "tag_name", "tag_color" FROM "task_tags" WHERE \("task_id"`).
+		WillReturnRows(tagsRows)
+
+	// 注册路由
+	helper.RegisterRoute("PUT", "/api/tasks/:id", func(ctx context.Context, c *app.RequestContext) {
+		helper.HandlerDeps.UpdateTaskHandler(ctx, c)
+	})
+
+	req := dto.UpdateTaskRequest{
+		Title:       "New Title",
+		Description: "Shit, this is late",
+	}
+	reqBody, _ := json.Marshal(req)
+
+	// 使用 ut.PerformRequest 执行请求
+	w := helper.PerformRequest("PUT", "/api/tasks/task-123",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	// 验证响应：应返回 400，任务不更新
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+
+	var errResp dto.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &errResp)
+	assert.NoError(t, err)
+	assert.Contains(t, errResp.Error, "CONTENT_BLOCKED")
+
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUrgencySettings Mock 查询用户紧急度系数（coefficients 为空表示用户未自定义）
+func MockUrgencySettings(mock sqlmock.Sqlmock, coefficients string) {
+	rows := sqlmock.NewRows([]string{"coefficients"})
+	if coefficients != "" {
+		rows.AddRow(coefficients)
+	}
+	mock.ExpectQuery(`SELECT "coefficients" FROM "urgency_settings"`).
+		WillReturnRows(rows)
+}
+
+// MockCountOpenSubtasks Mock 统计未完成子任务
+func MockCountOpenSubtasks(mock sqlmock.Sqlmock, counts map[string]int) {
+	rows := sqlmock.NewRows([]string{"parent_id", "count"})
+	for parentID, count := range counts {
+		rows.AddRow(parentID, count)
+	}
+	mock.ExpectQuery(`SELECT "parent_id", COUNT\(\*\) FROM "tasks"`).
+		WillReturnRows(rows)
+}
+
+// TestListTasks_SortByUrgency 测试按紧急度排序
+//
+// 对应 usecases.yaml 中 ListTasks 的 sort=urgency
+func TestListTasks_SortByUrgency(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	now := time.Now()
+	overdue := now.AddDate(0, 0, -3)
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).
+		AddRow("task-low", TestUserID, "Low", "", "pending", "low", nil, now, now, nil, nil, nil).
+		AddRow("task-overdue", TestUserID, "Overdue", "", "pending", "medium", &overdue, now, now, nil, nil, nil).
+		AddRow("task-blocked", TestUserID, "Blocked", "", "pending", "high", nil, now, now, nil, nil, nil)
+
+	MockUrgencySettings(helper.Mock, "")
+	MockCount(helper.Mock, 3)
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks"`).WillReturnRows(rows)
+	MockLoadTagsForTasks(helper.Mock, []*model.Task{{}})
+	MockCountOpenSubtasks(helper.Mock, map[string]int{"task-blocked": 2})
+
+	helper.RegisterRoute("GET", "/api/tasks", helper.HandlerDeps.ListTasksHandler)
+	w := helper.PerformRequest("GET", "/api/tasks?sort=urgency", nil)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.ListTasksResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Tasks, 3)
+	assert.Equal(t, 3, resp.TotalCount)
+	assert.False(t, resp.Truncated)
+	assert.False(t, resp.HasMore)
+
+	// 逾期任务最紧急；high 优先级任务因存在未完成子任务被降权
+	assert.Equal(t, "task-overdue", resp.Tasks[0].TaskID)
+	assert.Equal(t, "task-low", resp.Tasks[1].TaskID)
+	assert.Equal(t, "task-blocked", resp.Tasks[2].TaskID)
+	require.NotNil(t, resp.Tasks[0].Urgency)
+	assert.Greater(t, *resp.Tasks[0].Urgency, *resp.Tasks[1].Urgency)
+
+	helper.AssertExpectations(t)
+}
+
+// TestListTasks_SortByUrgency_Truncated 测试任务超过候选上限：候选任务按紧急度因子在 SQL 中预排序，
+// total_count 为全部任务数，并标记 truncated
+func TestListTasks_SortByUrgency_Truncated(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).
+		AddRow("task-1", TestUserID, "One", "", "pending", "low", nil, now, now, nil, nil, nil).
+		AddRow("task-2", TestUserID, "Two", "", "pending", "high", nil, now, now, nil, nil, nil)
+
+	MockUrgencySettings(helper.Mock, "")
+	MockCount(helper.Mock, 5000) // 数据库中的任务数超过候选上限
+	// 未完成、逾期和最早创建的任务优先成为候选
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" .+ORDER BY CASE +WHEN \("status" = 'completed'\) THEN 1 ELSE 0 END ASC, ` +
+		`CASE +WHEN \("due_date" IS NULL\) THEN 1 ELSE 0 END ASC, "due_date" ASC, .+"created_at" ASC LIMIT 1000`).
+		WillReturnRows(rows)
+	MockLoadTagsForTasks(helper.Mock, []*model.Task{{}})
+	MockCountOpenSubtasks(helper.Mock, nil)
+
+	helper.RegisterRoute("GET", "/api/tasks", helper.HandlerDeps.ListTasksHandler)
+	w := helper.PerformRequest("GET", "/api/tasks?sort=urgency", nil)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.ListTasksResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Tasks, 2)
+	assert.Equal(t, 5000, resp.TotalCount)
+	assert.True(t, resp.Truncated)
+	assert.False(t, resp.HasMore) // 候选任务之外的任务不参与分页
+
+	helper.AssertExpectations(t)
+}
+
+// TestListTasks_InvalidSort 测试非法排序字段
+func TestListTasks_InvalidSort(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.RegisterRoute("GET", "/api/tasks", helper.HandlerDeps.ListTasksHandler)
+	w := helper.PerformRequest("GET", "/api/tasks?sort=title", nil)
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	helper.AssertExpectations(t)
+}
+
+// TestNextTasks_Success 测试推荐下一步任务
+//
+// 对应 usecases.yaml 中的 NextTasks 用例
+func TestNextTasks_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "title", "description", "status", "priority", "due_date", "created_at", "updated_at", "completed_at", "parent_id", "hidden_until",
+	}).
+		AddRow("task-high", TestUserID, "High", "", "pending", "high", nil, now, now, nil, nil, nil).
+		AddRow("task-low", TestUserID, "Low", "", "pending", "low", nil, now, now, nil, nil, nil)
+
+	// 用户自定义系数：low 优先级比 high 更紧急
+	MockUrgencySettings(helper.Mock, `{"priority_high":1,"priority_low":9}`)
+	// 已完成任务在查询中排除，不占用候选数量（大量已完成任务不会挤掉较早的未完成任务）
+	helper.Mock.ExpectQuery(`SELECT COUNT\(\*\) FROM "tasks" WHERE .*"status" != `).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	helper.Mock.ExpectQuery(`SELECT .+ FROM "tasks" WHERE .*"status" != `).WillReturnRows(rows)
+	MockLoadTagsForTasks(helper.Mock, []*model.Task{{}})
+	MockCountOpenSubtasks(helper.Mock, nil)
+
+	// 同时注册 /:id，验证静态路由 /next 不会被参数路由吞掉
+	helper.RegisterRoute("GET", "/api/tasks/next", helper.HandlerDeps.NextTasksHandler)
+	helper.RegisterRoute("GET", "/api/tasks/:id", helper.HandlerDeps.GetTaskHandler)
+	w := helper.PerformRequest("GET", "/api/tasks/next?limit=5", nil)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.NextTasksResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Tasks, 2)
+	assert.Equal(t, "task-low", resp.Tasks[0].TaskID)
+	assert.Equal(t, "task-high", resp.Tasks[1].TaskID)
+
+	helper.AssertExpectations(t)
+}
+
+// TestUpdateUrgencyCoefficients_Success 测试更新紧急度系数（部分字段）
+func TestUpdateUrgencyCoefficients_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockUrgencySettings(helper.Mock, "")
+	helper.Mock.ExpectExec(`INSERT INTO "urgency_settings" .+ ON CONFLICT`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	helper.RegisterRoute("PUT", "/api/tasks/urgency-coefficients", helper.HandlerDeps.UpdateUrgencyCoefficientsHandler)
+
+	due := 20.0
+	reqBody, _ := json.Marshal(dto.UpdateUrgencyCoefficientsRequest{
+		Due:       &due,
+		TagBoosts: map[string]float64{"focus": 5},
+	})
+	w := helper.PerformRequest("PUT", "/api/tasks/urgency-coefficients",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	assert.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+
+	var resp dto.UrgencyCoefficientsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 20.0, resp.Due)
+	assert.Equal(t, 6.0, resp.PriorityHigh) // 未提供的字段保持默认值
+	assert.Equal(t, map[string]float64{"focus": 5}, resp.TagBoosts)
+	assert.False(t, resp.IsDefault)
+
+	helper.AssertExpectations(t)
+}
+
+// TestUpdateUrgencyCoefficients_INVALID_URGENCY_COEFFICIENT 测试系数超出范围
+func TestUpdateUrgencyCoefficients_INVALID_URGENCY_COEFFICIENT(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockUrgencySettings(helper.Mock, "")
+
+	helper.RegisterRoute("PUT", "/api/tasks/urgency-coefficients", helper.HandlerDeps.UpdateUrgencyCoefficientsHandler)
+
+	blocked := -500.0
+	reqBody, _ := json.Marshal(dto.UpdateUrgencyCoefficientsRequest{Blocked: &blocked})
+	w := helper.PerformRequest("PUT", "/api/tasks/urgency-coefficients",
+		bytes.NewReader(reqBody),
+		map[string]string{"Content-Type": "application/json"},
+	)
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "INVALID_URGENCY_COEFFICIENT", resp.Error)
+
+	helper.AssertExpectations(t)
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tool"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/task/service"
+)
+
+// 工具名称
+const (
+	ListTasks    = "list_tasks"
+	CreateTask   = "create_task"
+	UpdateTask   = "update_task"
+	CompleteTask = "complete_task"
+)
+
+// 列出任务的数量限制
+const (
+	defaultListLimit = 20
+	maxListLimit     = 50
+)
+
+// listTasksArgs list_tasks 参数
+type listTasksArgs struct {
+	Status    *string    `json:"status" description:"按状态筛选" jsonschema:"enum=pending|in_progress|completed"`
+	Priority  *string    `json:"priority" description:"按优先级筛选" jsonschema:"enum=low|medium|high"`
+	Tag       *string    `json:"tag" description:"按标签筛选"`
+	Keyword   *string    `json:"keyword" description:"在标题和描述中搜索的关键词"`
+	DueBefore *time.Time `json:"due_before" description:"只返回截止时间不晚于此时间的任务（RFC 3339）"`
+	Limit     *int       `json:"limit" description:"最多返回的任务数（默认 20）" jsonschema:"minimum=1,maximum=50"`
+}
+
+// createTaskArgs create_task 参数
+type createTaskArgs struct {
+	Title       string     `json:"title" description:"任务标题" jsonschema:"minLength=1,maxLength=200"`
+	Description *string    `json:"description" description:"任务描述"`
+	Priority    *string    `json:"priority" description:"优先级（默认 medium）" jsonschema:"enum=low|medium|high"`
+	DueDate     *time.Time `json:"due_date" description:"截止时间（RFC 3339，不能早于当前时间）"`
+	Tags        []string   `json:"tags" description:"标签（没有时传空数组）" jsonschema:"maxItems=10"`
+}
+
+// updateTaskArgs update_task 参数（null 表示不修改）
+type updateTaskArgs struct {
+	TaskID      string     `json:"task_id" description:"任务 ID（由 list_tasks 获取）" jsonschema:"minLength=1"`
+	Title       *string    `json:"title" description:"新标题"`
+	Description *string    `json:"description" description:"新描述"`
+	Priority    *string    `json:"priority" description:"新优先级" jsonschema:"enum=low|medium|high"`
+	DueDate     *time.Time `json:"due_date" description:"新截止时间（RFC 3339，不能早于当前时间）"`
+	Tags        *[]string  `json:"tags" description:"新标签（替换全部标签）"`
+}
+
+// completeTaskArgs complete_task 参数
+type completeTaskArgs struct {
+	TaskID string `json:"task_id" description:"任务 ID（由 list_tasks 获取）" jsonschema:"minLength=1"`
+}
+
+// taskItem 返回给模型的任务
+type taskItem struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status"`
+	Priority    string   `json:"priority"`
+	DueDate     *string  `json:"due_date"`
+	Tags        []string `json:"tags"`
+	ParentID    *string  `json:"parent_id,omitempty"`
+}
+
+// listTasksResult list_tasks 返回值
+type listTasksResult struct {
+	Tasks []taskItem `json:"tasks"`
+	Total int        `json:"total"` // 符合条件的任务总数（可能多于返回的数量）
+}
+
+// NewRegistry 创建任务工具集（供 Chat 领域的任务助手使用）
+//
+// 工具通过闭包绑定 userID，只能访问该用户的任务；所有操作都经过 TaskService，
+// 与 HTTP 接口执行相同的业务规则。update_task 和 complete_task 修改已有任务，需要用户确认。
+func NewRegistry(taskService *service.TaskService, userID string) (*tool.Registry, error) {
+	list, err := tool.New(ListTasks, "列出当前用户的任务（不包括推迟中的任务），按创建时间倒序",
+		func(ctx context.Context, args listTasksArgs) (any, error) {
+			return listTasks(ctx, taskService, userID, args)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	create, err := tool.New(CreateTask, "为当前用户创建任务",
+		func(ctx context.Context, args createTaskArgs) (any, error) {
+			output, err := taskService.CreateTask(ctx, service.CreateTaskInput{
+				UserID:      userID,
+				Title:       args.Title,
+				Description: deref(args.Description),
+				Priority:    model.Priority(deref(args.Priority)),
+				DueDate:     args.DueDate,
+				Tags:        args.Tags,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return toTaskItem(output.Task), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := tool.New(UpdateTask, "修改任务的标题、描述、优先级、截止时间或标签（null 表示不修改），已完成的任务不能修改",
+		func(ctx context.Context, args updateTaskArgs) (any, error) {
+			input := service.UpdateTaskInput{
+				UserID:      userID,
+				TaskID:      args.TaskID,
+				Title:       args.Title,
+				Description: args.Description,
+				DueDate:     args.DueDate,
+			}
+			if args.Priority != nil {
+				priority := model.Priority(*args.Priority)
+				input.Priority = &priority
+			}
+			if args.Tags != nil {
+				input.Tags = append([]string{}, *args.Tags...)
+			}
+			output, err := taskService.UpdateTask(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			return toTaskItem(output.Task), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	update.Destructive = true
+
+	complete, err := tool.New(CompleteTask, "将任务标记为已完成",
+		func(ctx context.Context, args completeTaskArgs) (any, error) {
+			output, err := taskService.CompleteTask(ctx, service.CompleteTaskInput{
+				UserID: userID,
+				TaskID: args.TaskID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return toTaskItem(output.Task), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	complete.Destructive = true
+
+	registry := tool.NewRegistry()
+	if err := registry.Register(list, create, update, complete); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// listTasks 按参数构造筛选条件并列出任务
+func listTasks(ctx context.Context, taskService *service.TaskService, userID string, args listTasksArgs) (any, error) {
+	filter := repository.NewTaskFilter()
+	filter.UserID = &userID
+	if args.Status != nil {
+		status := model.TaskStatus(*args.Status)
+		filter.Status = &status
+	}
+	if args.Priority != nil {
+		priority := model.Priority(*args.Priority)
+		filter.Priority = &priority
+	}
+	filter.Tag = args.Tag
+	filter.Keyword = args.Keyword
+	if args.DueBefore != nil {
+		dueTo := args.DueBefore.Format(time.RFC3339)
+		filter.DueDateTo = &dueTo
+	}
+	filter.Limit = defaultListLimit
+	if args.Limit != nil && *args.Limit > 0 {
+		filter.Limit = min(*args.Limit, maxListLimit)
+	}
+
+	output, err := taskService.ListTasks(ctx, service.ListTasksInput{Filter: *filter})
+	if err != nil {
+		return nil, err
+	}
+
+	result := listTasksResult{Tasks: make([]taskItem, 0, len(output.Tasks)), Total: output.TotalCount}
+	for _, task := range output.Tasks {
+		result.Tasks = append(result.Tasks, toTaskItem(task))
+	}
+	return result, nil
+}
+
+// toTaskItem 将任务实体转换为返回给模型的结构
+func toTaskItem(task *model.Task) taskItem {
+	item := taskItem{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		Tags:        make([]string, 0, len(task.Tags)),
+		ParentID:    task.ParentID,
+	}
+	if task.DueDate != nil {
+		due := task.DueDate.Format(time.RFC3339)
+		item.DueDate = &due
+	}
+	for _, tag := range task.Tags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+	return item
+}
+
+// deref 返回指针指向的值（nil 时为空字符串）
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/service"
+)
+
+// DTO 转换层
+//
+// 命名规范：
+// - toXxx:         HTTP DTO → Domain
+// - toXxxResponse: Domain → HTTP Response
+
+// toUsageSummaryResponse 将用量汇总转换为 HTTP 响应
+func toUsageSummaryResponse(s model.UsageSummary) dto.UsageSummaryResponse {
+	return dto.UsageSummaryResponse{
+		Requests:     s.Requests,
+		InputTokens:  s.InputTokens,
+		OutputTokens: s.OutputTokens,
+		TotalTokens:  s.TotalTokens(),
+		Cost:         s.Cost,
+	}
+}
+
+// toGetUsageResponse 将用量查询结果转换为 HTTP 响应
+func toGetUsageResponse(output *service.GetUsageOutput) dto.GetUsageResponse {
+	daily := make([]dto.DailyUsageResponse, 0, len(output.Daily))
+	for _, d := range output.Daily {
+		daily = append(daily, dto.DailyUsageResponse{
+			Date:                 d.Date.Format("2006-01-02"),
+			UsageSummaryResponse: toUsageSummaryResponse(d.UsageSummary),
+		})
+	}
+
+	models := make([]dto.ModelUsageResponse, 0, len(output.Models))
+	for _, m := range output.Models {
+		models = append(models, dto.ModelUsageResponse{
+			Provider:             m.Provider,
+			Model:                m.Model,
+			UsageSummaryResponse: toUsageSummaryResponse(m.UsageSummary),
+		})
+	}
+
+	return dto.GetUsageResponse{
+		Today:  toUsageSummaryResponse(output.Today),
+		Month:  toUsageSummaryResponse(output.Month),
+		Daily:  daily,
+		Models: models,
+	}
+}
+
+// toQuotaWindowResponse 将一个窗口的额度转换为 HTTP 响应
+func toQuotaWindowResponse(status *model.QuotaStatus, window model.QuotaWindow) dto.QuotaWindowResponse {
+	limit := status.Plan.Limit(window)
+	used := status.Used.Window(window)
+	remaining := status.Remaining(window)
+
+	response := dto.QuotaWindowResponse{
+		TokensUsed: used.Tokens,
+		TokenLimit: limit.Tokens,
+		CostUsed:   used.Cost,
+		CostLimit:  limit.Cost,
+		ResetsAt:   status.Period.ResetAt(window).Format(time.RFC3339),
+	}
+	if remaining.Tokens >= 0 {
+		response.TokensRemaining = &remaining.Tokens
+	}
+	if remaining.Cost >= 0 {
+		response.CostRemaining = &remaining.Cost
+	}
+	return response
+}
+
+// toGetQuotaResponse 将额度状态转换为 HTTP 响应
+func toGetQuotaResponse(status *model.QuotaStatus) dto.GetQuotaResponse {
+	return dto.GetQuotaResponse{
+		Plan:    status.Plan.Name,
+		Daily:   toQuotaWindowResponse(status, model.WindowDaily),
+		Monthly: toQuotaWindowResponse(status, model.WindowMonthly),
+	}
+}
+
+// toSetPlanInput 将分配套餐请求转换为领域输入
+func toSetPlanInput(userID string, req dto.SetPlanRequest) service.SetPlanInput {
+	return service.SetPlanInput{UserID: userID, Plan: req.Plan}
+}
+
+// toPlanResponse 将套餐转换为 HTTP 响应
+func toPlanResponse(userID string, plan *model.Plan) dto.PlanResponse {
+	return dto.PlanResponse{
+		UserID:        userID,
+		Plan:          plan.Name,
+		DailyTokens:   plan.DailyTokens,
+		MonthlyTokens: plan.MonthlyTokens,
+		DailyCost:     plan.DailyCost,
+		MonthlyCost:   plan.MonthlyCost,
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// GetQuotaHandler 查询当前用户的额度（HTTP 适配层）
+//
+// 用例：GetQuota（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/usage/quota
+//
+// 返回套餐名，今日和本月的已用量、限额、剩余额度和重置时间。
+//
+// 业务逻辑在 service.QuotaService.GetQuota() 中实现
+func (deps *HandlerDependencies) GetQuotaHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 调用 Domain Service
+	status, err := deps.quotaService.GetQuota(ctx, userID)
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 3. 返回成功响应
+	c.JSON(200, toGetQuotaResponse(status))
+}
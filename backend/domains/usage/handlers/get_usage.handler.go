@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/http/dto"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/service"
+)
+
+// GetUsageHandler 查询当前用户的用量（HTTP 适配层）
+//
+// 用例：GetUsage（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: GET
+//   - Path: /api/usage?days=30
+//
+// 返回今天、本月的汇总，最近 days 天的每日明细和本月按模型的汇总。
+//
+// 业务逻辑在 service.UsageService.GetUsage() 中实现
+func (deps *HandlerDependencies) GetUsageHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取用户 ID
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	// 2. 解析并验证查询参数
+	var req dto.GetUsageRequest
+	if err := c.BindQuery(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_QUERY",
+			Message: "查询参数无效",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	output, err := deps.usageService.GetUsage(ctx, service.GetUsageInput{
+		UserID: userID,
+		Days:   req.Days,
+	})
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toGetUsageResponse(output))
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// QuotaHeaders 返回在响应中附加剩余额度响应头的中间件
+//
+// 用于会调用 LLM 的路由（需要在 AuthMiddleware 之后）。响应头反映本次调用之前的额度：
+//
+//	X-Quota-Plan: free
+//	X-Quota-Daily-Tokens-Remaining: 81234
+//	X-Quota-Daily-Cost-Remaining: 0.812345    # 美元
+//	X-Quota-Daily-Reset: 1700006400           # Unix 时间戳
+//	X-Quota-Monthly-Tokens-Remaining: 1904321
+//	X-Quota-Monthly-Cost-Remaining: 19.5
+//	X-Quota-Monthly-Reset: 1701388800
+//
+// 不限制的维度不返回对应的 Remaining 响应头。查询失败时不设置响应头，也不阻塞请求；
+// 额度的检查和预占在 LLM 调用前由 QuotaService.Reserve 完成。
+func (deps *HandlerDependencies) QuotaHeaders() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if userID, ok := middleware.GetUserID(c); ok {
+			if status, err := deps.quotaService.GetQuota(ctx, userID); err == nil {
+				setQuotaHeaders(c, status)
+			}
+		}
+		c.Next(ctx)
+	}
+}
+
+// setQuotaHeaders 设置额度响应头
+func setQuotaHeaders(c *app.RequestContext, status *model.QuotaStatus) {
+	c.Header("X-Quota-Plan", status.Plan.Name)
+
+	windows := []struct {
+		prefix string
+		window model.QuotaWindow
+	}{
+		{"X-Quota-Daily-", model.WindowDaily},
+		{"X-Quota-Monthly-", model.WindowMonthly},
+	}
+	for _, w := range windows {
+		remaining := status.Remaining(w.window)
+		if remaining.Tokens >= 0 {
+			c.Header(w.prefix+"Tokens-Remaining", strconv.FormatInt(remaining.Tokens, 10))
+		}
+		if remaining.Cost >= 0 {
+			c.Header(w.prefix+"Cost-Remaining", strconv.FormatFloat(remaining.Cost, 'f', -1, 64))
+		}
+		c.Header(w.prefix+"Reset", strconv.FormatInt(status.Period.ResetAt(w.window).Unix(), 10))
+	}
+}
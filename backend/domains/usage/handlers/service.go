@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/service"
+)
+
+// HandlerDependencies Handler 依赖容器
+//
+// 只持有 Handler 需要的依赖，不包含业务逻辑；
+// 用量记录与汇总的业务逻辑在 service.UsageService 中实现，
+// 额度检查与套餐分配在 service.QuotaService 中实现。
+type HandlerDependencies struct {
+	usageService *service.UsageService
+	quotaService *service.QuotaService
+}
+
+// NewHandlerDependencies 创建新的依赖容器
+//
+// 参数：
+//   - usageService: 用量领域服务
+//   - quotaService: 额度领域服务
+//
+// 返回：
+//   - *HandlerDependencies: 依赖容器实例
+func NewHandlerDependencies(usageService *service.UsageService, quotaService *service.QuotaService) *HandlerDependencies {
+	return &HandlerDependencies{
+		usageService: usageService,
+		quotaService: quotaService,
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/http/dto"
+)
+
+// SetPlanHandler 为用户分配套餐（HTTP 适配层，仅管理员）
+//
+// 用例：SetPlan（参考 usecases.yaml）
+//
+// HTTP:
+//   - Method: PUT
+//   - Path: /api/admin/users/:user_id/plan
+//   - Request: dto.SetPlanRequest
+//   - Response: dto.PlanResponse
+//
+// 业务逻辑在 service.QuotaService.SetPlan() 中实现
+func (deps *HandlerDependencies) SetPlanHandler(ctx context.Context, c *app.RequestContext) {
+	// 1. 获取目标用户 ID
+	userID := c.Param("user_id")
+	if userID == "" {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "USER_ID_REQUIRED",
+			Message: "用户 ID 不能为空",
+		})
+		return
+	}
+
+	// 2. 解析并验证请求体
+	var req dto.SetPlanRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(400, dto.ErrorResponse{
+			Error:   "INVALID_REQUEST",
+			Message: "请求格式错误",
+			Details: err.Error(),
+		})
+		return
+	}
+	if !validateRequest(c, &req) {
+		return
+	}
+
+	// 3. 调用 Domain Service
+	plan, err := deps.quotaService.SetPlan(ctx, toSetPlanInput(userID, req))
+	if err != nil {
+		handleDomainError(c, err)
+		return
+	}
+
+	// 4. 返回成功响应
+	c.JSON(200, toPlanResponse(userID, plan))
+}
@@ -0,0 +1,80 @@
+package dto
+
+// 验证规则使用 pkg/validator（validate 标签）。
+
+// GetUsageRequest 查询用量请求（查询参数）
+type GetUsageRequest struct {
+	Days int `query:"days" json:"days" validate:"omitempty,min=1,max=90"` // 每日明细天数，默认 30
+}
+
+// UsageSummaryResponse 用量汇总
+type UsageSummaryResponse struct {
+	Requests     int     `json:"requests"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	TotalTokens  int64   `json:"total_tokens"`
+	Cost         float64 `json:"cost"` // 美元
+}
+
+// DailyUsageResponse 每日用量
+type DailyUsageResponse struct {
+	Date string `json:"date"` // YYYY-MM-DD（UTC）
+	UsageSummaryResponse
+}
+
+// ModelUsageResponse 按模型的用量
+type ModelUsageResponse struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	UsageSummaryResponse
+}
+
+// GetUsageResponse 查询用量响应
+type GetUsageResponse struct {
+	Today  UsageSummaryResponse `json:"today"`
+	Month  UsageSummaryResponse `json:"month"`  // 本月（UTC）至今
+	Daily  []DailyUsageResponse `json:"daily"`  // 按日期升序
+	Models []ModelUsageResponse `json:"models"` // 本月按模型汇总，按费用降序
+}
+
+// QuotaWindowResponse 一个窗口（今日或本月）的额度
+//
+// 限额为 0 表示不限制，此时对应的剩余额度为 null。
+type QuotaWindowResponse struct {
+	TokensUsed      int64    `json:"tokens_used"`
+	TokenLimit      int64    `json:"token_limit"`
+	TokensRemaining *int64   `json:"tokens_remaining"`
+	CostUsed        float64  `json:"cost_used"`  // 美元
+	CostLimit       float64  `json:"cost_limit"` // 美元
+	CostRemaining   *float64 `json:"cost_remaining"`
+	ResetsAt        string   `json:"resets_at"` // RFC3339（UTC）
+}
+
+// GetQuotaResponse 查询额度响应
+type GetQuotaResponse struct {
+	Plan    string              `json:"plan"`
+	Daily   QuotaWindowResponse `json:"daily"`
+	Monthly QuotaWindowResponse `json:"monthly"`
+}
+
+// SetPlanRequest 分配套餐请求
+type SetPlanRequest struct {
+	Plan string `json:"plan" validate:"required,max=50"`
+}
+
+// PlanResponse 套餐响应
+type PlanResponse struct {
+	UserID        string  `json:"user_id"`
+	Plan          string  `json:"plan"`
+	DailyTokens   int64   `json:"daily_tokens"`   // 0 表示不限制
+	MonthlyTokens int64   `json:"monthly_tokens"` // 0 表示不限制
+	DailyCost     float64 `json:"daily_cost"`     // 美元，0 表示不限制
+	MonthlyCost   float64 `json:"monthly_cost"`   // 美元，0 表示不限制
+}
+
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Error   string `json:"error"`             // 错误码
+	Message string `json:"message"`           // 错误消息
+	Details string `json:"details,omitempty"` // 详细信息（可选）
+}
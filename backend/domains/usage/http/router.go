@@ -0,0 +1,34 @@
+package http
+
+import (
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/handlers"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// RegisterRoutes 注册用量领域的路由
+//
+// 所有路由都需要认证（使用 AuthMiddleware），用户只能查询自己的用量和额度；
+// 分配套餐还需要管理员权限（AdminMiddleware）。
+//
+// 路由列表：
+//   - GET /api/usage                      - 查询用量（今天、本月、每日明细、按模型）
+//   - GET /api/usage/quota                - 查询额度（套餐、今日和本月的剩余额度）
+//   - PUT /api/admin/users/:user_id/plan  - 为用户分配套餐（管理员）
+func RegisterRoutes(
+	r *route.RouterGroup,
+	deps *handlers.HandlerDependencies,
+	authMiddleware *middleware.AuthMiddleware,
+	adminMiddleware *middleware.AdminMiddleware,
+) {
+	usage := r.Group("/usage", authMiddleware.Handle())
+	{
+		usage.GET("", deps.GetUsageHandler)
+		usage.GET("/quota", deps.GetQuotaHandler)
+	}
+
+	admin := r.Group("/admin/users", authMiddleware.Handle(), adminMiddleware.Handle())
+	{
+		admin.PUT("/:user_id/plan", deps.SetPlanHandler)
+	}
+}
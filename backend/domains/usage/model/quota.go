@@ -0,0 +1,137 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// 额度相关错误定义
+var (
+	ErrInvalidPlan = fmt.Errorf("INVALID_PLAN: 套餐不存在")
+)
+
+// QuotaWindow 额度窗口
+type QuotaWindow string
+
+const (
+	WindowDaily   QuotaWindow = "daily"   // 自然日（UTC）
+	WindowMonthly QuotaWindow = "monthly" // 自然月（UTC）
+)
+
+// Plan 套餐（额度档位）
+//
+// 每个窗口分别限制 Token 数和费用，0 表示不限制。
+type Plan struct {
+	Name          string
+	DailyTokens   int64
+	MonthlyTokens int64
+	DailyCost     float64 // 美元
+	MonthlyCost   float64 // 美元
+}
+
+// Unlimited 是否完全不限制
+func (p Plan) Unlimited() bool {
+	return p.DailyTokens == 0 && p.MonthlyTokens == 0 && p.DailyCost == 0 && p.MonthlyCost == 0
+}
+
+// Limit 返回窗口的限额
+func (p Plan) Limit(window QuotaWindow) QuotaAmount {
+	if window == WindowMonthly {
+		return QuotaAmount{Tokens: p.MonthlyTokens, Cost: p.MonthlyCost}
+	}
+	return QuotaAmount{Tokens: p.DailyTokens, Cost: p.DailyCost}
+}
+
+// Exceeded 判断在已用量基础上再增加 add 是否超出额度
+//
+// 返回第一个被超出的额度描述（例如 "今日 Token 额度"），未超出时返回空字符串。
+func (p Plan) Exceeded(used QuotaUsage, add QuotaAmount) string {
+	checks := []struct {
+		label string
+		limit float64
+		value float64
+	}{
+		{"今日 Token 额度", float64(p.DailyTokens), float64(used.Daily.Tokens + add.Tokens)},
+		{"今日费用额度", p.DailyCost, used.Daily.Cost + add.Cost},
+		{"本月 Token 额度", float64(p.MonthlyTokens), float64(used.Monthly.Tokens + add.Tokens)},
+		{"本月费用额度", p.MonthlyCost, used.Monthly.Cost + add.Cost},
+	}
+	for _, c := range checks {
+		if c.limit > 0 && c.value > c.limit {
+			return c.label
+		}
+	}
+	return ""
+}
+
+// QuotaAmount 一组 Token 数和费用
+type QuotaAmount struct {
+	Tokens int64
+	Cost   float64 // 美元
+}
+
+// Sub 返回差值（结算时实际用量减去预占用量，可为负）
+func (a QuotaAmount) Sub(b QuotaAmount) QuotaAmount {
+	return QuotaAmount{Tokens: a.Tokens - b.Tokens, Cost: a.Cost - b.Cost}
+}
+
+// IsZero 是否为零
+func (a QuotaAmount) IsZero() bool {
+	return a.Tokens == 0 && a.Cost == 0
+}
+
+// QuotaUsage 当前窗口内已计入额度的用量（包括尚未结算的预占）
+type QuotaUsage struct {
+	Daily   QuotaAmount
+	Monthly QuotaAmount
+}
+
+// Window 返回窗口的用量
+func (u QuotaUsage) Window(window QuotaWindow) QuotaAmount {
+	if window == WindowMonthly {
+		return u.Monthly
+	}
+	return u.Daily
+}
+
+// QuotaPeriod 额度计数所在的自然日和自然月（UTC）
+type QuotaPeriod struct {
+	Day   time.Time // 当天 00:00
+	Month time.Time // 当月 1 日 00:00
+}
+
+// PeriodOf 返回 t 所在的额度周期
+func PeriodOf(t time.Time) QuotaPeriod {
+	return QuotaPeriod{Day: StartOfDay(t), Month: StartOfMonth(t)}
+}
+
+// ResetAt 返回窗口的重置时间
+func (p QuotaPeriod) ResetAt(window QuotaWindow) time.Time {
+	if window == WindowMonthly {
+		return p.Month.AddDate(0, 1, 0)
+	}
+	return p.Day.AddDate(0, 0, 1)
+}
+
+// QuotaStatus 用户的额度状态
+type QuotaStatus struct {
+	Plan   Plan
+	Used   QuotaUsage
+	Period QuotaPeriod
+}
+
+// Remaining 返回窗口的剩余额度；不限制的维度返回 -1
+func (s QuotaStatus) Remaining(window QuotaWindow) QuotaAmount {
+	limit := s.Plan.Limit(window)
+	used := s.Used.Window(window)
+
+	remaining := QuotaAmount{Tokens: -1, Cost: -1}
+	if limit.Tokens > 0 {
+		remaining.Tokens = max(limit.Tokens-used.Tokens, 0)
+	}
+	if limit.Cost > 0 {
+		remaining.Cost = math.Max(limit.Cost-used.Cost, 0)
+	}
+	return remaining
+}
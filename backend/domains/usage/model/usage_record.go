@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 用量领域错误定义
+var (
+	ErrInvalidTokens = fmt.Errorf("INVALID_TOKENS: Token 数不能为负数")
+	ErrInvalidCost   = fmt.Errorf("INVALID_COST: 费用不能为负数")
+)
+
+// UsageRecord 一次 LLM 调用的用量记录（账本条目，只追加）
+//
+// 每次调用（包括失败的调用）记录一条。费用在记录时按模型目录中的价格计算，
+// 之后调整价格不影响历史记录。
+type UsageRecord struct {
+	ID           string
+	UserID       string // 终端用户（系统调用时为空）
+	RequestID    string // GenerationCompleted 的 RequestID
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64 // 美元
+	LatencyMs    int64
+	Success      bool
+	CreatedAt    time.Time
+}
+
+// NewUsageRecord 创建用量记录
+func NewUsageRecord(userID, requestID, provider, modelName string, inputTokens, outputTokens int, cost float64, latencyMs int64, success bool) (*UsageRecord, error) {
+	if inputTokens < 0 || outputTokens < 0 {
+		return nil, ErrInvalidTokens
+	}
+	if cost < 0 {
+		return nil, ErrInvalidCost
+	}
+
+	return &UsageRecord{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		RequestID:    requestID,
+		Provider:     provider,
+		Model:        modelName,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         cost,
+		LatencyMs:    latencyMs,
+		Success:      success,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// TotalTokens 输入和输出 Token 之和
+func (r *UsageRecord) TotalTokens() int {
+	return r.InputTokens + r.OutputTokens
+}
+
+// UsageSummary 一段时间内的用量汇总
+type UsageSummary struct {
+	Requests     int
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+}
+
+// TotalTokens 输入和输出 Token 之和
+func (s UsageSummary) TotalTokens() int64 {
+	return s.InputTokens + s.OutputTokens
+}
+
+// DailyUsage 按天汇总的用量（UTC 日期）
+type DailyUsage struct {
+	Date time.Time
+	UsageSummary
+}
+
+// ModelUsage 按模型汇总的用量
+type ModelUsage struct {
+	Provider string
+	Model    string
+	UsageSummary
+}
+
+// StartOfDay 返回 t 所在日期（UTC）的零点
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfMonth 返回 t 所在月份（UTC）的第一天零点
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
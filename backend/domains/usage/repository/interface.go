@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+)
+
+// UsageRepository 用量账本仓储接口
+//
+// 账本只追加，不修改或删除记录。查询的时间范围为 [from, to)。
+type UsageRepository interface {
+	// Create 记录一次调用
+	Create(ctx context.Context, record *model.UsageRecord) error
+
+	// Summarize 汇总用户在时间范围内的用量
+	Summarize(ctx context.Context, userID string, from, to time.Time) (model.UsageSummary, error)
+
+	// DailyTotals 按天（UTC）汇总用户在时间范围内的用量，按日期升序，没有调用的日期不返回
+	DailyTotals(ctx context.Context, userID string, from, to time.Time) ([]model.DailyUsage, error)
+
+	// ModelTotals 按模型汇总用户在时间范围内的用量，按费用降序
+	ModelTotals(ctx context.Context, userID string, from, to time.Time) ([]model.ModelUsage, error)
+}
+
+// PlanRepository 用户套餐仓储接口
+type PlanRepository interface {
+	// GetPlan 获取用户的套餐名（未分配时返回空字符串）
+	GetPlan(ctx context.Context, userID string) (string, error)
+
+	// SetPlan 为用户分配套餐（覆盖）
+	SetPlan(ctx context.Context, userID, plan string) error
+}
+
+// QuotaCounter 额度计数器接口
+//
+// 按用户和周期（自然日、自然月）累计已计入额度的 Token 数和费用。
+// 计数只用于额度检查，精确的用量以用量账本为准。
+type QuotaCounter interface {
+	// Reserve 原子地检查并预占额度
+	//
+	// 任一维度（今日/本月的 Token 数或费用）加上 amount 后会超出 plan 的限额时不累加，
+	// 返回 false 和当前用量；否则累加并返回 true 和累加后的用量。
+	Reserve(ctx context.Context, userID string, period model.QuotaPeriod, amount model.QuotaAmount, plan model.Plan) (bool, model.QuotaUsage, error)
+
+	// Adjust 累加差值（结算时可为负数，不检查限额）
+	Adjust(ctx context.Context, userID string, period model.QuotaPeriod, delta model.QuotaAmount) error
+
+	// Get 读取当前用量
+	Get(ctx context.Context, userID string, period model.QuotaPeriod) (model.QuotaUsage, error)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// PlanRepositoryImpl 用户套餐仓储实现
+type PlanRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewPlanRepository 创建用户套餐仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewPlanRepository(db *sql.DB, dbType string) *PlanRepositoryImpl {
+	return &PlanRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *PlanRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// GetPlan 获取用户的套餐名（未分配时返回空字符串）
+func (r *PlanRepositoryImpl) GetPlan(ctx context.Context, userID string) (string, error) {
+	query, args, err := r.dialect.From("user_plans").
+		Select("plan").
+		Where(goqu.C("user_id").Eq(userID)).
+		ToSQL()
+	if err != nil {
+		return "", fmt.Errorf("build select user plan query failed: %w", err)
+	}
+
+	var plan string
+	if err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("query user plan failed: %w", err)
+	}
+	return plan, nil
+}
+
+// SetPlan 为用户分配套餐（覆盖）
+func (r *PlanRepositoryImpl) SetPlan(ctx context.Context, userID, plan string) error {
+	now := time.Now()
+	query, args, err := r.dialect.Insert("user_plans").
+		Rows(goqu.Record{
+			"user_id":    userID,
+			"plan":       plan,
+			"updated_at": now,
+		}).
+		OnConflict(goqu.DoUpdate("user_id", goqu.Record{
+			"plan":       plan,
+			"updated_at": now,
+		})).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build upsert user plan query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("save user plan failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlanRepository_GetPlan 测试获取用户套餐（未分配时返回空字符串）
+func TestPlanRepository_GetPlan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPlanRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT "plan" FROM "user_plans" WHERE \("user_id" = 'user-1'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"plan"}).AddRow("pro"))
+	mock.ExpectQuery(`SELECT "plan" FROM "user_plans" WHERE \("user_id" = 'user-2'\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"plan"}))
+
+	plan, err := repo.GetPlan(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", plan)
+
+	plan, err = repo.GetPlan(context.Background(), "user-2")
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestPlanRepository_SetPlan 测试分配套餐（存在时覆盖）
+func TestPlanRepository_SetPlan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewPlanRepository(db, "postgres")
+	mock.ExpectExec(`INSERT INTO "user_plans" .+'pro'.+ON CONFLICT \(user_id\) DO UPDATE SET "plan"='pro'`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.SetPlan(context.Background(), "user-1", "pro"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/redis/go-redis/v9"
+)
+
+// microUSD 费用在 Redis 中以微美元（1e-6 USD）整数存储，便于 INCRBY
+const microUSD = 1_000_000
+
+// counterTTLSlack 计数在周期结束后额外保留的时间
+const counterTTLSlack = time.Hour
+
+// reserveScript 原子地检查并预占额度
+//
+// KEYS[1..4]：今日 Token、今日费用、本月 Token、本月费用
+// ARGV[1..2]：预占的 Token 数、费用（微美元）
+// ARGV[3..6]：与 KEYS 对应的限额（0 表示不限制）
+// ARGV[7..8]：今日、本月计数的过期时间（Unix 秒）
+//
+// 返回 {是否预占成功, 今日 Token, 今日费用, 本月 Token, 本月费用}
+var reserveScript = redis.NewScript(`
+local add = {tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[1]), tonumber(ARGV[2])}
+local used = redis.call('MGET', KEYS[1], KEYS[2], KEYS[3], KEYS[4])
+for i = 1, 4 do
+	used[i] = tonumber(used[i] or '0')
+end
+for i = 1, 4 do
+	local limit = tonumber(ARGV[i + 2])
+	if limit > 0 and used[i] + add[i] > limit then
+		return {0, used[1], used[2], used[3], used[4]}
+	end
+end
+for i = 1, 4 do
+	used[i] = redis.call('INCRBY', KEYS[i], add[i])
+	redis.call('EXPIREAT', KEYS[i], i <= 2 and ARGV[7] or ARGV[8])
+end
+return {1, used[1], used[2], used[3], used[4]}
+`)
+
+// RedisQuotaCounter 基于 Redis 的额度计数器（多实例共享）
+//
+// 每个用户每个周期两个计数（Token 数、微美元费用），键使用 {userID} 哈希标签，
+// 保证 Lua 脚本访问的键在集群模式下位于同一个槽。计数在周期结束后自动过期。
+type RedisQuotaCounter struct {
+	client redis.UniversalClient
+}
+
+// NewRedisQuotaCounter 创建基于 Redis 的额度计数器
+func NewRedisQuotaCounter(client redis.UniversalClient) *RedisQuotaCounter {
+	return &RedisQuotaCounter{client: client}
+}
+
+// Reserve 原子地检查并预占额度
+func (c *RedisQuotaCounter) Reserve(ctx context.Context, userID string, period model.QuotaPeriod, amount model.QuotaAmount, plan model.Plan) (bool, model.QuotaUsage, error) {
+	values, err := reserveScript.Run(ctx, c.client, counterKeys(userID, period),
+		amount.Tokens, toMicro(amount.Cost),
+		plan.DailyTokens, toMicro(plan.DailyCost), plan.MonthlyTokens, toMicro(plan.MonthlyCost),
+		expireAt(period, model.WindowDaily), expireAt(period, model.WindowMonthly),
+	).Int64Slice()
+	if err != nil {
+		return false, model.QuotaUsage{}, fmt.Errorf("reserve quota failed: %w", err)
+	}
+	if len(values) != 5 {
+		return false, model.QuotaUsage{}, fmt.Errorf("reserve quota failed: unexpected result %v", values)
+	}
+	return values[0] == 1, toUsage(values[1:]), nil
+}
+
+// Adjust 累加差值（不检查限额）
+func (c *RedisQuotaCounter) Adjust(ctx context.Context, userID string, period model.QuotaPeriod, delta model.QuotaAmount) error {
+	keys := counterKeys(userID, period)
+	deltas := []int64{delta.Tokens, toMicro(delta.Cost), delta.Tokens, toMicro(delta.Cost)}
+
+	pipe := c.client.TxPipeline()
+	for i, key := range keys {
+		if deltas[i] == 0 {
+			continue
+		}
+		window := model.WindowDaily
+		if i >= 2 {
+			window = model.WindowMonthly
+		}
+		pipe.IncrBy(ctx, key, deltas[i])
+		pipe.ExpireAt(ctx, key, time.Unix(expireAt(period, window), 0))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("adjust quota failed: %w", err)
+	}
+	return nil
+}
+
+// Get 读取当前用量
+func (c *RedisQuotaCounter) Get(ctx context.Context, userID string, period model.QuotaPeriod) (model.QuotaUsage, error) {
+	raw, err := c.client.MGet(ctx, counterKeys(userID, period)...).Result()
+	if err != nil {
+		return model.QuotaUsage{}, fmt.Errorf("get quota failed: %w", err)
+	}
+
+	values := make([]int64, len(raw))
+	for i, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			continue // 键不存在
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return model.QuotaUsage{}, fmt.Errorf("get quota failed: invalid counter %q", s)
+		}
+		values[i] = n
+	}
+	return toUsage(values), nil
+}
+
+// counterKeys 返回今日 Token、今日费用、本月 Token、本月费用的键
+func counterKeys(userID string, period model.QuotaPeriod) []string {
+	day := period.Day.Format("20060102")
+	month := period.Month.Format("200601")
+	return []string{
+		fmt.Sprintf("quota:{%s}:d:%s:tokens", userID, day),
+		fmt.Sprintf("quota:{%s}:d:%s:cost", userID, day),
+		fmt.Sprintf("quota:{%s}:m:%s:tokens", userID, month),
+		fmt.Sprintf("quota:{%s}:m:%s:cost", userID, month),
+	}
+}
+
+// expireAt 返回窗口计数的过期时间（Unix 秒）
+func expireAt(period model.QuotaPeriod, window model.QuotaWindow) int64 {
+	return period.ResetAt(window).Add(counterTTLSlack).Unix()
+}
+
+// toMicro 美元转换为微美元
+func toMicro(usd float64) int64 {
+	return int64(math.Round(usd * microUSD))
+}
+
+// toUsage 将 {今日 Token, 今日费用, 本月 Token, 本月费用} 转换为用量
+func toUsage(values []int64) model.QuotaUsage {
+	return model.QuotaUsage{
+		Daily:   model.QuotaAmount{Tokens: values[0], Cost: float64(values[1]) / microUSD},
+		Monthly: model.QuotaAmount{Tokens: values[2], Cost: float64(values[3]) / microUSD},
+	}
+}
+
+// MemoryQuotaCounter 进程内额度计数器
+//
+// 用于测试和未配置 Redis 的单实例部署：多实例部署时每个实例单独计数。
+// 每个用户只保留当前周期的计数：更早周期的窗口（如跨过零点后结算前一天的预占）不再计数，
+// 与 Redis 中按周期分键的效果一致。
+type MemoryQuotaCounter struct {
+	mu      sync.Mutex
+	entries map[string]*memoryQuotaEntry
+}
+
+// memoryQuotaEntry 用户当前周期的计数
+type memoryQuotaEntry struct {
+	period model.QuotaPeriod
+	usage  model.QuotaUsage
+}
+
+// NewMemoryQuotaCounter 创建进程内额度计数器
+func NewMemoryQuotaCounter() *MemoryQuotaCounter {
+	return &MemoryQuotaCounter{entries: make(map[string]*memoryQuotaEntry)}
+}
+
+// Reserve 原子地检查并预占额度
+func (c *MemoryQuotaCounter) Reserve(ctx context.Context, userID string, period model.QuotaPeriod, amount model.QuotaAmount, plan model.Plan) (bool, model.QuotaUsage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entry(userID, period)
+	used := entry.usageOf(period)
+	if plan.Exceeded(used, amount) != "" {
+		return false, used, nil
+	}
+	entry.add(period, amount)
+	return true, entry.usageOf(period), nil
+}
+
+// Adjust 累加差值（不检查限额）
+func (c *MemoryQuotaCounter) Adjust(ctx context.Context, userID string, period model.QuotaPeriod, delta model.QuotaAmount) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entry(userID, period).add(period, delta)
+	return nil
+}
+
+// Get 读取当前用量
+func (c *MemoryQuotaCounter) Get(ctx context.Context, userID string, period model.QuotaPeriod) (model.QuotaUsage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entry(userID, period).usageOf(period), nil
+}
+
+// entry 返回用户的计数，period 晚于当前周期时前进并清零对应窗口（调用方持有锁）
+//
+// period 早于当前周期时不清零：新周期的计数不受之前周期的结算影响。
+func (c *MemoryQuotaCounter) entry(userID string, period model.QuotaPeriod) *memoryQuotaEntry {
+	entry, ok := c.entries[userID]
+	if !ok {
+		entry = &memoryQuotaEntry{period: period}
+		c.entries[userID] = entry
+	}
+	if period.Month.After(entry.period.Month) {
+		entry.period.Month = period.Month
+		entry.usage.Monthly = model.QuotaAmount{}
+	}
+	if period.Day.After(entry.period.Day) {
+		entry.period.Day = period.Day
+		entry.usage.Daily = model.QuotaAmount{}
+	}
+	return entry
+}
+
+// usageOf 返回 period 的用量（早于当前周期的窗口已不再计数，为 0）
+func (e *memoryQuotaEntry) usageOf(period model.QuotaPeriod) model.QuotaUsage {
+	var usage model.QuotaUsage
+	if e.period.Day.Equal(period.Day) {
+		usage.Daily = e.usage.Daily
+	}
+	if e.period.Month.Equal(period.Month) {
+		usage.Monthly = e.usage.Monthly
+	}
+	return usage
+}
+
+// add 累加到 period 的今日和本月（早于当前周期的窗口忽略）
+func (e *memoryQuotaEntry) add(period model.QuotaPeriod, amount model.QuotaAmount) {
+	if e.period.Day.Equal(period.Day) {
+		e.usage.Daily.Tokens += amount.Tokens
+		e.usage.Daily.Cost += amount.Cost
+	}
+	if e.period.Month.Equal(period.Month) {
+		e.usage.Monthly.Tokens += amount.Tokens
+		e.usage.Monthly.Cost += amount.Cost
+	}
+}
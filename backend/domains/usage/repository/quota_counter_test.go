@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNow 测试时间（月中，今日和本月的计数过期时间不同）
+var testNow = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// testPlan 每个维度都有限额的套餐
+var testPlan = model.Plan{Name: "test", DailyTokens: 1000, DailyCost: 1, MonthlyTokens: 5000, MonthlyCost: 5}
+
+// newTestRedisCounter 创建基于 miniredis 的额度计数器（miniredis 的时间为 testNow）
+func newTestRedisCounter(t *testing.T) (*RedisQuotaCounter, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	mr.SetTime(testNow)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisQuotaCounter(client), mr
+}
+
+// quotaCounters Redis 和进程内计数器（行为应一致）
+func quotaCounters(t *testing.T) map[string]QuotaCounter {
+	redisCounter, _ := newTestRedisCounter(t)
+	return map[string]QuotaCounter{
+		"redis":  redisCounter,
+		"memory": NewMemoryQuotaCounter(),
+	}
+}
+
+// TestQuotaCounter_Reserve 测试限额内预占成功并累加今日和本月
+func TestQuotaCounter_Reserve(t *testing.T) {
+	ctx := context.Background()
+	period := model.PeriodOf(testNow)
+
+	for name, counter := range quotaCounters(t) {
+		t.Run(name, func(t *testing.T) {
+			ok, used, err := counter.Reserve(ctx, "user-1", period, model.QuotaAmount{Tokens: 400, Cost: 0.25}, testPlan)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, model.QuotaAmount{Tokens: 400, Cost: 0.25}, used.Daily)
+
+			ok, used, err = counter.Reserve(ctx, "user-1", period, model.QuotaAmount{Tokens: 600, Cost: 0.75}, testPlan)
+			require.NoError(t, err)
+			assert.True(t, ok) // 恰好达到限额
+			assert.Equal(t, model.QuotaUsage{
+				Daily:   model.QuotaAmount{Tokens: 1000, Cost: 1},
+				Monthly: model.QuotaAmount{Tokens: 1000, Cost: 1},
+			}, used)
+
+			// 其他用户单独计数
+			other, err := counter.Get(ctx, "user-2", period)
+			require.NoError(t, err)
+			assert.Equal(t, model.QuotaUsage{}, other)
+		})
+	}
+}
+
+// TestQuotaCounter_ReserveExceeded 测试任一维度超出限额时拒绝，且不累加任何计数
+func TestQuotaCounter_ReserveExceeded(t *testing.T) {
+	ctx := context.Background()
+	period := model.PeriodOf(testNow)
+	initial := model.QuotaAmount{Tokens: 500, Cost: 0.5}
+
+	tests := []struct {
+		name   string
+		plan   model.Plan
+		amount model.QuotaAmount
+	}{
+		{"今日 Token", testPlan, model.QuotaAmount{Tokens: 501, Cost: 0.1}},
+		{"今日费用", testPlan, model.QuotaAmount{Tokens: 10, Cost: 0.51}},
+		{"本月 Token", model.Plan{MonthlyTokens: 600}, model.QuotaAmount{Tokens: 101}},
+		{"本月费用", model.Plan{MonthlyCost: 0.6}, model.QuotaAmount{Tokens: 10, Cost: 0.11}},
+	}
+	for _, tt := range tests {
+		for name, counter := range quotaCounters(t) {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				ok, _, err := counter.Reserve(ctx, "user-1", period, initial, model.Plan{})
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				ok, used, err := counter.Reserve(ctx, "user-1", period, tt.amount, tt.plan)
+				require.NoError(t, err)
+				assert.False(t, ok)
+				want := model.QuotaUsage{Daily: initial, Monthly: initial}
+				assert.Equal(t, want, used)
+
+				current, err := counter.Get(ctx, "user-1", period)
+				require.NoError(t, err)
+				assert.Equal(t, want, current)
+			})
+		}
+	}
+}
+
+// TestQuotaCounter_Adjust 测试结算时累加差值（多退少补，不检查限额）
+func TestQuotaCounter_Adjust(t *testing.T) {
+	ctx := context.Background()
+	period := model.PeriodOf(testNow)
+
+	for name, counter := range quotaCounters(t) {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := counter.Reserve(ctx, "user-1", period, model.QuotaAmount{Tokens: 800, Cost: 0.75}, testPlan)
+			require.NoError(t, err)
+
+			// 实际用量少于预占：退还
+			require.NoError(t, counter.Adjust(ctx, "user-1", period, model.QuotaAmount{Tokens: -500, Cost: -0.5}))
+			used, err := counter.Get(ctx, "user-1", period)
+			require.NoError(t, err)
+			assert.Equal(t, model.QuotaAmount{Tokens: 300, Cost: 0.25}, used.Daily)
+			assert.Equal(t, model.QuotaAmount{Tokens: 300, Cost: 0.25}, used.Monthly)
+
+			// 实际用量多于预占：补扣，可以超出限额
+			require.NoError(t, counter.Adjust(ctx, "user-1", period, model.QuotaAmount{Tokens: 900, Cost: 0.875}))
+			used, err = counter.Get(ctx, "user-1", period)
+			require.NoError(t, err)
+			assert.Equal(t, model.QuotaAmount{Tokens: 1200, Cost: 1.125}, used.Daily)
+		})
+	}
+}
+
+// TestQuotaCounter_SettleAcrossMidnight 测试零点前预占、零点后结算：差值只计入预占的那天，不影响新一天的计数
+func TestQuotaCounter_SettleAcrossMidnight(t *testing.T) {
+	ctx := context.Background()
+	before := model.PeriodOf(time.Date(2025, 1, 15, 23, 59, 0, 0, time.UTC))
+	after := model.PeriodOf(time.Date(2025, 1, 16, 0, 1, 0, 0, time.UTC))
+
+	for name, counter := range quotaCounters(t) {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := counter.Reserve(ctx, "user-1", before, model.QuotaAmount{Tokens: 800}, testPlan)
+			require.NoError(t, err)
+			_, _, err = counter.Reserve(ctx, "user-1", after, model.QuotaAmount{Tokens: 300}, testPlan)
+			require.NoError(t, err)
+
+			// 零点前的调用完成，实际用量少于预占
+			require.NoError(t, counter.Adjust(ctx, "user-1", before, model.QuotaAmount{Tokens: -600}))
+
+			used, err := counter.Get(ctx, "user-1", after)
+			require.NoError(t, err)
+			assert.Equal(t, int64(300), used.Daily.Tokens)   // 新一天的计数不变
+			assert.Equal(t, int64(500), used.Monthly.Tokens) // 同一个月：800 + 300 - 600
+		})
+	}
+}
+
+// TestRedisQuotaCounter_Expire 测试计数在周期结束（加 counterTTLSlack）后过期
+func TestRedisQuotaCounter_Expire(t *testing.T) {
+	ctx := context.Background()
+	counter, mr := newTestRedisCounter(t)
+	period := model.PeriodOf(testNow)
+
+	_, _, err := counter.Reserve(ctx, "user-1", period, model.QuotaAmount{Tokens: 100, Cost: 0.1}, testPlan)
+	require.NoError(t, err)
+
+	keys := counterKeys("user-1", period)
+	assert.Equal(t, 13*time.Hour, mr.TTL(keys[0])) // 12 小时后到明天，再加 1 小时
+	assert.Equal(t, 13*time.Hour, mr.TTL(keys[1]))
+	assert.Equal(t, 16*24*time.Hour+13*time.Hour, mr.TTL(keys[2])) // 到 2 月 1 日，再加 1 小时
+	assert.Equal(t, 16*24*time.Hour+13*time.Hour, mr.TTL(keys[3]))
+
+	// 结算刷新过期时间（不会变成永久的键）
+	require.NoError(t, counter.Adjust(ctx, "user-1", period, model.QuotaAmount{Tokens: -50}))
+	assert.Equal(t, 13*time.Hour, mr.TTL(keys[0]))
+
+	// 今日的计数过期，本月的计数保留
+	mr.FastForward(13*time.Hour + time.Second)
+	assert.False(t, mr.Exists(keys[0]))
+	assert.False(t, mr.Exists(keys[1]))
+	used, err := counter.Get(ctx, "user-1", period)
+	require.NoError(t, err)
+	assert.Equal(t, model.QuotaAmount{}, used.Daily)
+	assert.Equal(t, model.QuotaAmount{Tokens: 50, Cost: 0.1}, used.Monthly)
+}
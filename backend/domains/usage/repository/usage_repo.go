@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
+)
+
+// newDialect 根据数据库类型选择 goqu 方言
+func newDialect(dbType string) goqu.DialectWrapper {
+	switch dbType {
+	case "mysql":
+		return goqu.Dialect("mysql")
+	case "sqlite":
+		return goqu.Dialect("sqlite3")
+	default:
+		return goqu.Dialect("postgres")
+	}
+}
+
+// nullString 空字符串存储为 NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// UsageRepositoryImpl 用量账本仓储实现
+type UsageRepositoryImpl struct {
+	db      *sql.DB
+	dialect goqu.DialectWrapper
+}
+
+// NewUsageRepository 创建用量账本仓储实例
+//
+// 参数：
+//   - db: 数据库连接
+//   - dbType: 数据库类型（postgres, mysql, sqlite），用于选择 SQL 方言
+func NewUsageRepository(db *sql.DB, dbType string) *UsageRepositoryImpl {
+	return &UsageRepositoryImpl{
+		db:      db,
+		dialect: newDialect(dbType),
+	}
+}
+
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *UsageRepositoryImpl) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
+// summaryColumns 汇总列（顺序与 scanSummary 保持一致）
+var summaryColumns = []interface{}{
+	goqu.COUNT("*").As("requests"),
+	goqu.COALESCE(goqu.SUM("input_tokens"), 0).As("input_tokens"),
+	goqu.COALESCE(goqu.SUM("output_tokens"), 0).As("output_tokens"),
+	goqu.COALESCE(goqu.SUM("cost"), 0).As("cost"),
+}
+
+// Create 记录一次调用
+func (r *UsageRepositoryImpl) Create(ctx context.Context, record *model.UsageRecord) error {
+	query, args, err := r.dialect.Insert("llm_usage").
+		Cols("id", "user_id", "request_id", "provider", "model",
+			"input_tokens", "output_tokens", "cost", "latency_ms", "success", "created_at").
+		Vals(goqu.Vals{
+			record.ID,
+			nullString(record.UserID),
+			record.RequestID,
+			record.Provider,
+			record.Model,
+			record.InputTokens,
+			record.OutputTokens,
+			record.Cost,
+			record.LatencyMs,
+			record.Success,
+			record.CreatedAt,
+		}).
+		ToSQL()
+	if err != nil {
+		return fmt.Errorf("build insert usage query failed: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("create usage record failed: %w", err)
+	}
+	return nil
+}
+
+// Summarize 汇总用户在时间范围内的用量
+func (r *UsageRepositoryImpl) Summarize(ctx context.Context, userID string, from, to time.Time) (model.UsageSummary, error) {
+	query, args, err := r.dialect.From("llm_usage").
+		Select(summaryColumns...).
+		Where(userRange(userID, from, to)...).
+		ToSQL()
+	if err != nil {
+		return model.UsageSummary{}, fmt.Errorf("build summarize usage query failed: %w", err)
+	}
+
+	var summary model.UsageSummary
+	if err := scanSummary(r.conn(ctx).QueryRowContext(ctx, query, args...), &summary); err != nil {
+		return model.UsageSummary{}, fmt.Errorf("summarize usage failed: %w", err)
+	}
+	return summary, nil
+}
+
+// DailyTotals 按天（UTC）汇总用户在时间范围内的用量
+func (r *UsageRepositoryImpl) DailyTotals(ctx context.Context, userID string, from, to time.Time) ([]model.DailyUsage, error) {
+	day := goqu.L("DATE(?)", goqu.C("created_at"))
+	query, args, err := r.dialect.From("llm_usage").
+		Select(append([]interface{}{day.As("day")}, summaryColumns...)...).
+		Where(userRange(userID, from, to)...).
+		GroupBy(day).
+		Order(day.Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build daily usage query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query daily usage failed: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]model.DailyUsage, 0)
+	for rows.Next() {
+		var d model.DailyUsage
+		if err := scanSummary(rows, &d.UsageSummary, &d.Date); err != nil {
+			return nil, fmt.Errorf("scan daily usage failed: %w", err)
+		}
+		d.Date = model.StartOfDay(d.Date)
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// ModelTotals 按模型汇总用户在时间范围内的用量
+func (r *UsageRepositoryImpl) ModelTotals(ctx context.Context, userID string, from, to time.Time) ([]model.ModelUsage, error) {
+	query, args, err := r.dialect.From("llm_usage").
+		Select(append([]interface{}{"provider", "model"}, summaryColumns...)...).
+		Where(userRange(userID, from, to)...).
+		GroupBy("provider", "model").
+		Order(goqu.I("cost").Desc(), goqu.C("provider").Asc(), goqu.C("model").Asc()).
+		ToSQL()
+	if err != nil {
+		return nil, fmt.Errorf("build model usage query failed: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query model usage failed: %w", err)
+	}
+	defer rows.Close()
+
+	models := make([]model.ModelUsage, 0)
+	for rows.Next() {
+		var m model.ModelUsage
+		if err := scanSummary(rows, &m.UsageSummary, &m.Provider, &m.Model); err != nil {
+			return nil, fmt.Errorf("scan model usage failed: %w", err)
+		}
+		models = append(models, m)
+	}
+	return models, rows.Err()
+}
+
+// userRange 用户和时间范围 [from, to) 条件
+func userRange(userID string, from, to time.Time) []exp.Expression {
+	return []exp.Expression{
+		goqu.C("user_id").Eq(userID),
+		goqu.C("created_at").Gte(from),
+		goqu.C("created_at").Lt(to),
+	}
+}
+
+// rowScanner 抽象 *sql.Row 和 *sql.Rows 的 Scan 方法
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSummary 扫描分组列（prefix）和 summaryColumns
+func scanSummary(row rowScanner, summary *model.UsageSummary, prefix ...interface{}) error {
+	dest := append(prefix, &summary.Requests, &summary.InputTokens, &summary.OutputTokens, &summary.Cost)
+	return row.Scan(dest...)
+}
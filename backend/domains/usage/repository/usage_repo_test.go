@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testFrom = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// TestUsageRepository_Create 测试记录调用（系统调用的 user_id 存储为 NULL）
+func TestUsageRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUsageRepository(db, "postgres")
+	record, _ := model.NewUsageRecord("", "req-1", "openai", "gpt-4o", 100, 50, 0.00075, 1200, true)
+
+	mock.ExpectExec(`INSERT INTO "llm_usage" .+NULL, 'req-1', 'openai', 'gpt-4o', 100, 50, 0.00075, 1200, TRUE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	require.NoError(t, repo.Create(context.Background(), record))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestUsageRepository_Summarize 测试汇总用户在时间范围内的用量
+func TestUsageRepository_Summarize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUsageRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS "requests", COALESCE\(SUM\("input_tokens"\), 0\).+FROM "llm_usage" WHERE \(\("user_id" = 'user-1'\) AND \("created_at" >= '2025-01-01.+AND \("created_at" < '2025-02-01`).
+		WillReturnRows(sqlmock.NewRows([]string{"requests", "input_tokens", "output_tokens", "cost"}).AddRow(3, 300, 150, 0.5))
+
+	summary, err := repo.Summarize(context.Background(), "user-1", testFrom, testTo)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Requests)
+	assert.Equal(t, int64(450), summary.TotalTokens())
+	assert.Equal(t, 0.5, summary.Cost)
+}
+
+// TestUsageRepository_DailyTotals 测试按天汇总
+func TestUsageRepository_DailyTotals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUsageRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT DATE\("created_at"\) AS "day", COUNT.+GROUP BY DATE\("created_at"\) ORDER BY DATE\("created_at"\) ASC`).
+		WillReturnRows(sqlmock.NewRows([]string{"day", "requests", "input_tokens", "output_tokens", "cost"}).
+			AddRow(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), 2, 200, 100, 0.25))
+
+	days, err := repo.DailyTotals(context.Background(), "user-1", testFrom, testTo)
+
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), days[0].Date)
+	assert.Equal(t, 2, days[0].Requests)
+}
+
+// TestUsageRepository_ModelTotals 测试按模型汇总（按费用降序）
+func TestUsageRepository_ModelTotals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := NewUsageRepository(db, "postgres")
+	mock.ExpectQuery(`SELECT "provider", "model", COUNT.+GROUP BY "provider", "model" ORDER BY "cost" DESC`).
+		WillReturnRows(sqlmock.NewRows([]string{"provider", "model", "requests", "input_tokens", "output_tokens", "cost"}).
+			AddRow("openai", "gpt-4o", 2, 200, 100, 0.25).
+			AddRow("mock", "mock-model", 5, 50, 50, 0))
+
+	models, err := repo.ModelTotals(context.Background(), "user-1", testFrom, testTo)
+
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.Equal(t, "gpt-4o", models[0].Model)
+	assert.Equal(t, 5, models[1].Requests)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// usageMetrics 用量相关的 Prometheus 指标
+type usageMetrics struct {
+	tokens *prometheus.CounterVec // llm_tokens_total{provider, model, type}
+	cost   *prometheus.CounterVec // llm_cost_usd_total{provider, model}
+}
+
+// newUsageMetrics 注册用量指标（m 为 nil 时不采集，返回 nil）
+func newUsageMetrics(m *metrics.Metrics) *usageMetrics {
+	if m == nil {
+		return nil
+	}
+	return &usageMetrics{
+		tokens: m.NewCounterVec("llm_tokens_total", "Total LLM tokens by model and type (input/output)", []string{"provider", "model", "type"}),
+		cost:   m.NewCounterVec("llm_cost_usd_total", "Total LLM cost in USD by model", []string{"provider", "model"}),
+	}
+}
+
+// record 累加一次调用的 Token 和费用
+func (m *usageMetrics) record(provider, model string, inputTokens, outputTokens int, cost float64) {
+	if m == nil {
+		return
+	}
+	m.tokens.WithLabelValues(provider, model, "input").Add(float64(inputTokens))
+	m.tokens.WithLabelValues(provider, model, "output").Add(float64(outputTokens))
+	m.cost.WithLabelValues(provider, model).Add(cost)
+}
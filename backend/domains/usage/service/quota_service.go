@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultReservedOutputTokens 请求未设置 MaxTokens 时预占的输出 Token 数
+const DefaultReservedOutputTokens = 512
+
+// QuotaService 额度领域服务（实现 llmservice.QuotaGuard）
+//
+// 职责：
+// - 按用户的套餐（未分配时使用默认套餐）限制每日、每月的 Token 数和费用
+// - LLM 调用前按预估用量原子地预占额度，超出时返回 QUOTA_EXCEEDED
+// - 调用结束后按实际用量结算（多退少补）
+// - 查询用户的额度状态、为用户分配套餐
+//
+// 计数器或套餐查询失败时放行请求（只记录日志），避免额度组件成为单点故障。
+type QuotaService struct {
+	counter     repository.QuotaCounter
+	planRepo    repository.PlanRepository
+	prices      PriceLookup
+	plans       map[string]model.Plan
+	defaultPlan string
+	now         func() time.Time
+}
+
+// NewQuotaService 创建额度服务
+//
+// 参数：
+//   - counter: 额度计数器（Redis 或进程内）
+//   - planRepo: 用户套餐仓储
+//   - prices: 价格查询（可为 nil，费用记为 0）
+//   - plans: 可用的套餐
+//   - defaultPlan: 未分配套餐的用户使用的套餐名
+func NewQuotaService(counter repository.QuotaCounter, planRepo repository.PlanRepository, prices PriceLookup, plans []model.Plan, defaultPlan string) *QuotaService {
+	byName := make(map[string]model.Plan, len(plans))
+	for _, p := range plans {
+		byName[p.Name] = p
+	}
+	return &QuotaService{
+		counter:     counter,
+		planRepo:    planRepo,
+		prices:      prices,
+		plans:       byName,
+		defaultPlan: defaultPlan,
+		now:         time.Now,
+	}
+}
+
+// WithClock 替换时间源（用于测试）
+func (s *QuotaService) WithClock(now func() time.Time) *QuotaService {
+	s.now = now
+	return s
+}
+
+// Reserve 调用前预占额度（用例实现）
+//
+// 步骤：
+//  1. ResolvePlan - 查询用户的套餐（不限制的套餐直接放行）
+//  2. Estimate - 按消息长度和 MaxTokens 预估 Token 数和费用
+//  3. Reserve - 原子地检查并预占今日、本月额度
+func (s *QuotaService) Reserve(ctx context.Context, req *llmmodel.ChatRequest) (llmservice.QuotaReservation, error) {
+	// Step 1: ResolvePlan
+	plan := s.resolvePlan(ctx, req.User)
+	if plan.Unlimited() {
+		return nil, nil
+	}
+
+	// Step 2: Estimate
+	inputTokens, outputTokens := tokenizer.CountRequest(tokenizer.Estimator{}, req), reservedOutputTokens(req)
+	amount := model.QuotaAmount{
+		Tokens: int64(inputTokens + outputTokens),
+		Cost:   s.cost(req.Provider, req.Model, inputTokens, outputTokens),
+	}
+
+	// Step 3: Reserve
+	period := model.PeriodOf(s.now())
+	ok, used, err := s.counter.Reserve(ctx, req.User, period, amount, plan)
+	if err != nil {
+		logger.Error("reserve quota failed, request allowed",
+			zap.String("user_id", req.User),
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+	if !ok {
+		exceeded := plan.Exceeded(used, amount)
+		if exceeded == "" {
+			exceeded = "额度" // Redis 中的费用按微美元取整，边界上可能与本地判断不一致
+		}
+		return nil, fmt.Errorf("%w: %s不足（%s 套餐）", llmmodel.ErrQuotaExceeded, exceeded, plan.Name)
+	}
+
+	return &quotaReservation{
+		service:  s,
+		userID:   req.User,
+		period:   period,
+		reserved: amount,
+	}, nil
+}
+
+// GetQuota 查询用户的额度状态（用例实现）
+func (s *QuotaService) GetQuota(ctx context.Context, userID string) (*model.QuotaStatus, error) {
+	plan := s.resolvePlan(ctx, userID)
+	period := model.PeriodOf(s.now())
+
+	used, err := s.counter.Get(ctx, userID, period)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询额度失败: %w", err)
+	}
+	return &model.QuotaStatus{Plan: plan, Used: used, Period: period}, nil
+}
+
+// SetPlanInput 分配套餐输入
+type SetPlanInput struct {
+	UserID string
+	Plan   string
+}
+
+// SetPlan 为用户分配套餐（用例实现，管理员操作）
+func (s *QuotaService) SetPlan(ctx context.Context, input SetPlanInput) (*model.Plan, error) {
+	plan, ok := s.plans[input.Plan]
+	if !ok {
+		return nil, model.ErrInvalidPlan
+	}
+	if err := s.planRepo.SetPlan(ctx, input.UserID, plan.Name); err != nil {
+		return nil, fmt.Errorf("SAVE_FAILED: 保存套餐失败: %w", err)
+	}
+	return &plan, nil
+}
+
+// resolvePlan 返回用户的套餐（未分配、套餐已下线或查询失败时使用默认套餐）
+func (s *QuotaService) resolvePlan(ctx context.Context, userID string) model.Plan {
+	if s.planRepo != nil {
+		name, err := s.planRepo.GetPlan(ctx, userID)
+		if err != nil {
+			logger.Error("query user plan failed, using default plan",
+				zap.String("user_id", userID),
+				zap.Error(err),
+			)
+		} else if plan, ok := s.plans[name]; ok {
+			return plan
+		}
+	}
+	return s.plans[s.defaultPlan]
+}
+
+// cost 按模型目录中的价格计算费用
+func (s *QuotaService) cost(provider, name string, inputTokens, outputTokens int) float64 {
+	if s.prices == nil {
+		return 0
+	}
+	spec, ok := s.prices.ModelSpec(provider, name)
+	if !ok {
+		return 0
+	}
+	return spec.EstimateCost(inputTokens, outputTokens)
+}
+
+// reservedOutputTokens 预占的输出 Token 数
+func reservedOutputTokens(req *llmmodel.ChatRequest) int {
+	if req.MaxTokens > 0 {
+		return req.MaxTokens
+	}
+	return DefaultReservedOutputTokens
+}
+
+// quotaReservation 一次预占的额度（实现 llmservice.QuotaReservation）
+type quotaReservation struct {
+	service  *QuotaService
+	userID   string
+	period   model.QuotaPeriod // 预占时的周期（跨天的调用计入预占的那天）
+	reserved model.QuotaAmount
+}
+
+// Settle 按实际用量结算：实际用量与预占用量的差值计入额度
+//
+// 费用按实际完成请求的模型计算（回退后与预占时的模型不同）。
+func (r *quotaReservation) Settle(ctx context.Context, providerName, modelName string, usage llmmodel.Usage) {
+	actual := model.QuotaAmount{
+		Tokens: int64(usage.TotalTokens()),
+		Cost:   r.service.cost(providerName, modelName, usage.InputTokens, usage.OutputTokens),
+	}
+	delta := actual.Sub(r.reserved)
+	if delta.IsZero() {
+		return
+	}
+	if err := r.service.counter.Adjust(ctx, r.userID, r.period, delta); err != nil {
+		logger.Error("settle quota failed",
+			zap.String("user_id", r.userID),
+			zap.Int64("delta_tokens", delta.Tokens),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+)
+
+// 查询参数默认值与限制
+const (
+	DefaultUsageDays = 30
+	MaxUsageDays     = 90
+)
+
+// PriceLookup 模型价格查询（由 catalog 领域的 CatalogService 实现）
+type PriceLookup interface {
+	ModelSpec(provider, name string) (llmmodel.ModelSpec, bool)
+}
+
+// UsageService 用量领域服务
+//
+// 职责：
+// - 订阅 GenerationCompleted，为每次 LLM 调用记录用量和费用（用量账本）
+// - 累加 Prometheus 指标（按模型的 Token 数和费用）
+// - 查询用户的每日、每月和按模型的用量汇总
+type UsageService struct {
+	repo    repository.UsageRepository
+	prices  PriceLookup
+	metrics *usageMetrics
+	now     func() time.Time
+}
+
+// NewUsageService 创建用量服务
+//
+// 参数：
+//   - repo: 用量账本仓储
+//   - prices: 价格查询（可为 nil，费用记为 0）
+//   - m: Prometheus 指标（可为 nil，不采集指标）
+func NewUsageService(repo repository.UsageRepository, prices PriceLookup, m *metrics.Metrics) *UsageService {
+	return &UsageService{
+		repo:    repo,
+		prices:  prices,
+		metrics: newUsageMetrics(m),
+		now:     time.Now,
+	}
+}
+
+// WithClock 替换时间源（用于测试）
+func (s *UsageService) WithClock(now func() time.Time) *UsageService {
+	s.now = now
+	return s
+}
+
+// HandleGenerationCompleted 处理 GenerationCompleted 事件（订阅到事件总线）
+func (s *UsageService) HandleGenerationCompleted(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(sharedevents.GenerationCompletedPayload)
+	if !ok {
+		return fmt.Errorf("INVALID_EVENT: GenerationCompleted 负载类型错误: %T", event.Payload())
+	}
+	_, err := s.RecordGeneration(ctx, payload)
+	return err
+}
+
+// RecordGeneration 记录一次 LLM 调用（用例实现）
+//
+// 步骤：
+//  1. CalculateCost - 按模型目录中的价格计算费用（模型不在目录中时为 0）
+//  2. SaveRecord - 写入用量账本
+//  3. RecordMetrics - 累加 Prometheus 指标
+func (s *UsageService) RecordGeneration(ctx context.Context, payload sharedevents.GenerationCompletedPayload) (*model.UsageRecord, error) {
+	// Step 1: CalculateCost
+	cost := s.cost(payload.Provider, payload.Model, payload.InputTokens, payload.OutputTokens)
+
+	// Step 2: SaveRecord
+	record, err := model.NewUsageRecord(
+		payload.UserID, payload.RequestID, payload.Provider, payload.Model,
+		payload.InputTokens, payload.OutputTokens, cost, payload.Latency, payload.Success,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("RECORD_FAILED: 记录用量失败: %w", err)
+	}
+
+	// Step 3: RecordMetrics
+	s.metrics.record(record.Provider, record.Model, record.InputTokens, record.OutputTokens, record.Cost)
+
+	return record, nil
+}
+
+// GetUsageInput 查询用量输入
+type GetUsageInput struct {
+	UserID string
+	Days   int // 每日明细的天数（包括今天，1-90，0 表示默认 30）
+}
+
+// GetUsageOutput 查询用量输出
+type GetUsageOutput struct {
+	Today  model.UsageSummary
+	Month  model.UsageSummary // 本月（UTC）至今
+	Daily  []model.DailyUsage // 最近 Days 天，每天一条（没有调用的日期为 0）
+	Models []model.ModelUsage // 本月按模型汇总
+}
+
+// GetUsage 查询用户的用量（用例实现）
+//
+// 步骤：
+//  1. ValidateInput - 校验天数
+//  2. SummarizeToday / SummarizeMonth - 今天和本月的汇总
+//  3. LoadDaily - 最近 Days 天的每日明细（补齐没有调用的日期）
+//  4. LoadModels - 本月按模型汇总
+func (s *UsageService) GetUsage(ctx context.Context, input GetUsageInput) (*GetUsageOutput, error) {
+	// Step 1: ValidateInput
+	days := input.Days
+	if days == 0 {
+		days = DefaultUsageDays
+	}
+	if days < 1 || days > MaxUsageDays {
+		return nil, fmt.Errorf("INVALID_DAYS: 天数必须在 1-%d 之间", MaxUsageDays)
+	}
+
+	now := s.now()
+	today := model.StartOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	month := model.StartOfMonth(now)
+
+	// Step 2: SummarizeToday / SummarizeMonth
+	todaySummary, err := s.repo.Summarize(ctx, input.UserID, today, tomorrow)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询用量失败: %w", err)
+	}
+	monthSummary, err := s.repo.Summarize(ctx, input.UserID, month, tomorrow)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询用量失败: %w", err)
+	}
+
+	// Step 3: LoadDaily
+	from := today.AddDate(0, 0, -(days - 1))
+	daily, err := s.repo.DailyTotals(ctx, input.UserID, from, tomorrow)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询用量失败: %w", err)
+	}
+
+	// Step 4: LoadModels
+	models, err := s.repo.ModelTotals(ctx, input.UserID, month, tomorrow)
+	if err != nil {
+		return nil, fmt.Errorf("QUERY_FAILED: 查询用量失败: %w", err)
+	}
+
+	return &GetUsageOutput{
+		Today:  todaySummary,
+		Month:  monthSummary,
+		Daily:  fillDays(daily, from, days),
+		Models: models,
+	}, nil
+}
+
+// cost 按模型目录中的价格计算费用
+func (s *UsageService) cost(provider, name string, inputTokens, outputTokens int) float64 {
+	if s.prices == nil {
+		return 0
+	}
+	spec, ok := s.prices.ModelSpec(provider, name)
+	if !ok {
+		return 0
+	}
+	return spec.EstimateCost(inputTokens, outputTokens)
+}
+
+// fillDays 补齐没有调用的日期，返回从 from 开始的连续 days 天
+func fillDays(daily []model.DailyUsage, from time.Time, days int) []model.DailyUsage {
+	byDate := make(map[time.Time]model.UsageSummary, len(daily))
+	for _, d := range daily {
+		byDate[d.Date] = d.UsageSummary
+	}
+
+	result := make([]model.DailyUsage, days)
+	for i := range result {
+		date := from.AddDate(0, 0, i)
+		result[i] = model.DailyUsage{Date: date, UsageSummary: byDate[date]}
+	}
+	return result
+}
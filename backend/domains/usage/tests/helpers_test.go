@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/handlers"
+	usagehttp "github.com/erweixin/go-genai-stack/backend/domains/usage/http"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/repository"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+)
+
+// ========== 测试常量 ==========
+
+const (
+	TestUserID = "test-user-123"
+	TestEmail  = "test@example.com" // 同时是管理员
+	TestModel  = "mock-model"
+
+	BackupProvider = "backup"       // 回退链中的备用提供商
+	BackupModel    = "backup-model" // 备用模型（输入 $20 / 输出 $100 每百万 Token）
+)
+
+// TestPlans 测试套餐：free 每天 2000 Token / $0.01，每月 10000 Token；unlimited 不限制
+var TestPlans = []model.Plan{
+	{Name: "free", DailyTokens: 2000, MonthlyTokens: 10000, DailyCost: 0.01},
+	{Name: "unlimited"},
+}
+
+// TestNow 测试时间（2025-01-15 12:00 UTC）
+var TestNow = time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+// summaryColumns 汇总查询的列（与 repository 保持一致）
+var summaryColumns = []string{"requests", "input_tokens", "output_tokens", "cost"}
+
+// priceTable 测试用价格表（实现 service.PriceLookup）
+type priceTable map[string]llmmodel.ModelSpec
+
+func (p priceTable) ModelSpec(provider, name string) (llmmodel.ModelSpec, bool) {
+	spec, ok := p[provider+"/"+name]
+	return spec, ok
+}
+
+// planTable 测试用套餐分配（实现 repository.PlanRepository）
+type planTable map[string]string
+
+func (p planTable) GetPlan(ctx context.Context, userID string) (string, error) {
+	return p[userID], nil
+}
+
+func (p planTable) SetPlan(ctx context.Context, userID, plan string) error {
+	p[userID] = plan
+	return nil
+}
+
+// TestHelper 提供测试辅助方法
+//
+// 数据库使用 sqlmock，LLM 使用 mock 提供商（输入 $2 / 输出 $10 每百万 Token），
+// 主提供商返回可重试错误时回退到 Backup（BackupModel），
+// UsageService 订阅事件总线上的 GenerationCompleted，并向独立的 Prometheus 注册表写入指标。
+// LLMService 通过 QuotaService 检查额度（进程内计数器，默认 free 套餐）。
+// 请求经过真实的路由和认证中间件（使用测试用户的 Token）。
+type TestHelper struct {
+	DB      *sql.DB
+	Mock    sqlmock.Sqlmock
+	LLM     *llmservice.LLMService
+	Mocked  *mock.Provider // LLMService 使用的 mock 提供商（可 Enqueue 脚本化响应）
+	Backup  *mock.Provider // 回退链中的备用提供商
+	Quota   *service.QuotaService
+	Plans   planTable // 用户套餐分配
+	Metrics *metrics.Metrics
+	Server  *server.Hertz
+
+	token string
+}
+
+// NewTestHelper 创建测试辅助工具
+func NewTestHelper(t *testing.T) *TestHelper {
+	db, sqlMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+
+	h := &TestHelper{
+		DB:      db,
+		Mock:    sqlMock,
+		Mocked:  mock.New(),
+		Backup:  mock.NewNamed(BackupProvider),
+		Plans:   planTable{},
+		Metrics: metrics.NewMetrics(config.MonitoringConfig{MetricsEnabled: true}),
+	}
+
+	prices := priceTable{
+		mock.Name + "/" + TestModel:        {Provider: mock.Name, Model: TestModel, InputPrice: 2, OutputPrice: 10},
+		BackupProvider + "/" + BackupModel: {Provider: BackupProvider, Model: BackupModel, InputPrice: 20, OutputPrice: 100},
+	}
+	usageService := service.NewUsageService(repository.NewUsageRepository(db, "postgres"), prices, h.Metrics).
+		WithClock(func() time.Time { return TestNow })
+
+	eventBus := sharedevents.NewDefaultEventBus()
+	if err := eventBus.Subscribe("GenerationCompleted", usageService.HandleGenerationCompleted); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	registry := llmprovider.NewRegistry()
+	registry.Register(h.Mocked)
+	registry.Register(h.Backup)
+	h.Quota = service.NewQuotaService(repository.NewMemoryQuotaCounter(), h.Plans, prices, TestPlans, "free").
+		WithClock(func() time.Time { return TestNow })
+	h.LLM = llmservice.NewLLMService(registry, mock.Name, TestModel, eventBus).WithQuota(h.Quota).
+		WithFallback([]llmservice.FallbackTarget{{Provider: mock.Name}, {Provider: BackupProvider, Model: BackupModel}}, h.Metrics)
+
+	h.Server = server.Default(
+		server.WithHostPorts("127.0.0.1:0"),
+		server.WithExitWaitTime(0),
+	)
+	jwtService := authservice.NewJWTService("test-secret", time.Hour, time.Hour, "test")
+	token, _, err := jwtService.GenerateAccessToken(TestUserID, TestEmail)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	h.token = token
+
+	deps := handlers.NewHandlerDependencies(usageService, h.Quota)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	usagehttp.RegisterRoutes(h.Server.Group("/api"), deps, authMiddleware, middleware.NewAdminMiddleware([]string{TestEmail}, verifiedUser))
+
+	// 模拟一个调用 LLM 的路由，用于验证额度响应头
+	h.Server.POST("/api/generate", authMiddleware.Handle(), deps.QuotaHeaders(), func(ctx context.Context, c *app.RequestContext) {
+		c.JSON(200, map[string]string{"status": "ok"})
+	})
+	return h
+}
+
+// Close 清理资源
+func (h *TestHelper) Close() error {
+	return h.DB.Close()
+}
+
+// AssertExpectations 验证所有 mock 期望都被满足
+func (h *TestHelper) AssertExpectations(t *testing.T) {
+	if err := h.Mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// PerformRequest 执行 HTTP GET 请求
+func (h *TestHelper) PerformRequest(path string) *ut.ResponseRecorder {
+	return ut.PerformRequest(h.Server.Engine, "GET", path, nil,
+		ut.Header{Key: "Authorization", Value: "Bearer " + h.token})
+}
+
+// PerformJSONRequest 执行带 JSON 请求体的 HTTP 请求
+func (h *TestHelper) PerformJSONRequest(method, path string, body interface{}) *ut.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	return ut.PerformRequest(h.Server.Engine, method, path, &ut.Body{Body: bytes.NewReader(raw), Len: len(raw)},
+		ut.Header{Key: "Authorization", Value: "Bearer " + h.token},
+		ut.Header{Key: "Content-Type", Value: "application/json"})
+}
+
+// Generate 以测试用户身份调用一次 LLM
+func (h *TestHelper) Generate(content string) error {
+	_, err := h.LLM.Complete(context.Background(), &llmmodel.ChatRequest{
+		User:     TestUserID,
+		Messages: []llmmodel.Message{{Role: llmmodel.RoleUser, Content: content}},
+	})
+	return err
+}
+
+// DecodeResponse 解析 JSON 响应
+func DecodeResponse(t *testing.T, w *ut.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v (body: %s)", err, w.Body.String())
+	}
+}
+
+// ========== Mock 辅助函数 ==========
+
+// ExpectRecord Mock 写入一条用量记录
+func ExpectRecord(m sqlmock.Sqlmock) {
+	m.ExpectExec(`INSERT INTO "llm_usage"`).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+// MockSummarize Mock 汇总查询（today / month）
+func MockSummarize(m sqlmock.Sqlmock, requests int, inputTokens, outputTokens int64, cost float64) {
+	m.ExpectQuery(`SELECT COUNT\(\*\) AS "requests".+FROM "llm_usage" WHERE`).
+		WillReturnRows(sqlmock.NewRows(summaryColumns).AddRow(requests, inputTokens, outputTokens, cost))
+}
+
+// verifiedUser 管理员中间件的用户查询（测试用户的邮箱已验证）
+var verifiedUser = middleware.UserLookupFunc(func(_ context.Context, userID string) (*middleware.UserInfo, error) {
+	return &middleware.UserInfo{Email: TestEmail, EmailVerified: true, Active: true}, nil
+})
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/http/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReserve_QuotaExceeded 测试超出每日 Token 额度后拒绝调用（不调用提供商）
+func TestReserve_QuotaExceeded(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 第一次调用实际用量 1500（free 套餐每天 2000）
+	helper.Mocked.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 1000, OutputTokens: 500}})
+	ExpectRecord(helper.Mock)
+	require.NoError(t, helper.Generate("hello"))
+
+	// 第二次调用预估 521（tokenizer.CountRequest 为 9 + 预留输出 512），超出剩余额度
+	err := helper.Generate("hello")
+
+	require.ErrorIs(t, err, llmmodel.ErrQuotaExceeded)
+	assert.Contains(t, err.Error(), "今日 Token 额度不足（free 套餐）")
+	assert.Len(t, helper.Mocked.Requests(), 1, "超出额度时不调用提供商")
+	helper.AssertExpectations(t)
+}
+
+// TestReserve_Settle 测试调用结束后按实际用量结算（多退少补，失败的调用退回额度）
+func TestReserve_Settle(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 成功：计入实际用量 150 Token，费用 (100 × 2 + 50 × 10) / 1M
+	helper.Mocked.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 100, OutputTokens: 50}})
+	ExpectRecord(helper.Mock)
+	require.NoError(t, helper.Generate("hello"))
+
+	// 失败：不计入
+	helper.Mocked.Enqueue(mock.Response{Err: assert.AnError})
+	ExpectRecord(helper.Mock)
+	require.Error(t, helper.Generate("hello"))
+
+	status, err := helper.Quota.GetQuota(context.Background(), TestUserID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "free", status.Plan.Name)
+	assert.Equal(t, int64(150), status.Used.Daily.Tokens)
+	assert.Equal(t, int64(150), status.Used.Monthly.Tokens)
+	assert.InDelta(t, 0.0007, status.Used.Daily.Cost, 1e-9)
+	helper.AssertExpectations(t)
+}
+
+// TestReserve_SettleAfterFallback 测试回退后按实际完成请求的模型计费
+func TestReserve_SettleAfterFallback(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 主提供商不可用（可重试错误），回退到备用模型：100 × 20 + 50 × 100 = $0.007
+	helper.Mocked.Enqueue(mock.Response{Err: &llmprovider.Error{StatusCode: 503, Retryable: true}})
+	helper.Backup.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 100, OutputTokens: 50}})
+	ExpectRecord(helper.Mock) // 失败的调用
+	ExpectRecord(helper.Mock) // 回退后成功的调用
+	require.NoError(t, helper.Generate("hello"))
+
+	status, err := helper.Quota.GetQuota(context.Background(), TestUserID)
+
+	require.NoError(t, err)
+	require.Len(t, helper.Backup.Requests(), 1)
+	assert.Equal(t, BackupModel, helper.Backup.Requests()[0].Model)
+	assert.Equal(t, int64(150), status.Used.Daily.Tokens)
+	assert.InDelta(t, 0.007, status.Used.Daily.Cost, 1e-9)
+	helper.AssertExpectations(t)
+}
+
+// TestReserve_UnlimitedPlan 测试不限制的套餐不计数
+func TestReserve_UnlimitedPlan(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+	helper.Plans[TestUserID] = "unlimited"
+
+	for i := 0; i < 3; i++ {
+		helper.Mocked.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 1000, OutputTokens: 500}})
+		ExpectRecord(helper.Mock)
+		require.NoError(t, helper.Generate("hello"))
+	}
+
+	status, err := helper.Quota.GetQuota(context.Background(), TestUserID)
+	require.NoError(t, err)
+	assert.Equal(t, "unlimited", status.Plan.Name)
+	assert.Zero(t, status.Used.Daily.Tokens)
+}
+
+// TestGetQuota_Success 测试查询额度（不限制的维度剩余额度为 null）
+func TestGetQuota_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mocked.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 300, OutputTokens: 200}})
+	ExpectRecord(helper.Mock)
+	require.NoError(t, helper.Generate("hello"))
+
+	w := helper.PerformRequest("/api/usage/quota")
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.GetQuotaResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "free", resp.Plan)
+	assert.Equal(t, int64(500), resp.Daily.TokensUsed)
+	require.NotNil(t, resp.Daily.TokensRemaining)
+	assert.Equal(t, int64(1500), *resp.Daily.TokensRemaining)
+	assert.Equal(t, "2025-01-16T00:00:00Z", resp.Daily.ResetsAt)
+	require.NotNil(t, resp.Monthly.TokensRemaining)
+	assert.Equal(t, int64(9500), *resp.Monthly.TokensRemaining)
+	assert.Nil(t, resp.Monthly.CostRemaining, "本月费用不限制")
+	assert.Equal(t, "2025-02-01T00:00:00Z", resp.Monthly.ResetsAt)
+}
+
+// TestQuotaHeaders 测试调用 LLM 的路由返回剩余额度响应头
+func TestQuotaHeaders(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	w := helper.PerformJSONRequest("POST", "/api/generate", nil)
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	header := &w.Result().Header
+	assert.Equal(t, "free", string(header.Peek("X-Quota-Plan")))
+	assert.Equal(t, "2000", string(header.Peek("X-Quota-Daily-Tokens-Remaining")))
+	assert.Equal(t, "0.01", string(header.Peek("X-Quota-Daily-Cost-Remaining")))
+	assert.Equal(t, "10000", string(header.Peek("X-Quota-Monthly-Tokens-Remaining")))
+	assert.Empty(t, header.Peek("X-Quota-Monthly-Cost-Remaining"), "不限制的维度不返回")
+	assert.Equal(t, "1736985600", string(header.Peek("X-Quota-Daily-Reset")))
+}
+
+// TestSetPlan_Success 测试管理员为用户分配套餐
+func TestSetPlan_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	w := helper.PerformJSONRequest("PUT", "/api/admin/users/user-456/plan", dto.SetPlanRequest{Plan: "unlimited"})
+
+	require.Equal(t, consts.StatusOK, w.Code)
+	var resp dto.PlanResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "user-456", resp.UserID)
+	assert.Equal(t, "unlimited", resp.Plan)
+	assert.Equal(t, "unlimited", helper.Plans["user-456"])
+}
+
+// TestSetPlan_INVALID_PLAN 测试分配不存在的套餐
+func TestSetPlan_INVALID_PLAN(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	w := helper.PerformJSONRequest("PUT", "/api/admin/users/user-456/plan", dto.SetPlanRequest{Plan: "platinum"})
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	var resp dto.ErrorResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, "INVALID_PLAN", resp.Error)
+	assert.Empty(t, helper.Plans)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	llmmodel "github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/usage/http/dto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordGeneration 测试每次 LLM 调用都写入用量账本并累加指标
+func TestRecordGeneration(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	// 费用 = (1000 × 2 + 500 × 10) / 1M = 0.007
+	helper.Mocked.Enqueue(mock.Response{Content: "ok", Usage: &llmmodel.Usage{InputTokens: 1000, OutputTokens: 500}})
+	helper.Mock.ExpectExec(`INSERT INTO "llm_usage" .+'test-user-123', '[0-9a-f-]+', 'mock', 'mock-model', 1000, 500, 0\.007, \d+, TRUE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	_, err := helper.LLM.Complete(context.Background(), &llmmodel.ChatRequest{
+		User:     TestUserID,
+		Messages: []llmmodel.Message{{Role: llmmodel.RoleUser, Content: "hello"}},
+	})
+	require.NoError(t, err)
+
+	helper.AssertExpectations(t)
+	registry := helper.Metrics.GetRegistry()
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP llm_tokens_total Total LLM tokens by model and type (input/output)
+# TYPE llm_tokens_total counter
+llm_tokens_total{model="mock-model",provider="mock",type="input"} 1000
+llm_tokens_total{model="mock-model",provider="mock",type="output"} 500
+# HELP llm_cost_usd_total Total LLM cost in USD by model
+# TYPE llm_cost_usd_total counter
+llm_cost_usd_total{model="mock-model",provider="mock"} 0.007
+`), "llm_tokens_total", "llm_cost_usd_total"))
+}
+
+// TestRecordGeneration_FailedCall 测试失败的调用也记录（Token 和费用为 0）
+func TestRecordGeneration_FailedCall(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	helper.Mock.ExpectExec(`INSERT INTO "llm_usage" .+'mock', 'unknown-model', 0, 0, 0, \d+, FALSE`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	helper.Mocked.Enqueue(mock.Response{Err: errors.New("upstream unavailable")})
+
+	_, err := helper.LLM.Complete(context.Background(), &llmmodel.ChatRequest{
+		User:     TestUserID,
+		Model:    "unknown-model",
+		Messages: []llmmodel.Message{{Role: llmmodel.RoleUser, Content: "hi"}},
+	})
+	require.Error(t, err)
+
+	helper.AssertExpectations(t)
+}
+
+// TestGetUsage_Success 测试查询用量（补齐没有调用的日期）
+func TestGetUsage_Success(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	MockSummarize(helper.Mock, 2, 200, 100, 0.25)
+	MockSummarize(helper.Mock, 5, 500, 300, 1.5)
+	helper.Mock.ExpectQuery(`SELECT DATE\("created_at"\).+"created_at" >= '2025-01-13T00:00:00Z'.+"created_at" < '2025-01-16T00:00:00Z'`).
+		WillReturnRows(sqlmock.NewRows(append([]string{"day"}, summaryColumns...)).
+			AddRow(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), 2, 200, 100, 0.25))
+	helper.Mock.ExpectQuery(`SELECT "provider", "model".+"created_at" >= '2025-01-01T00:00:00Z'`).
+		WillReturnRows(sqlmock.NewRows(append([]string{"provider", "model"}, summaryColumns...)).
+			AddRow("openai", "gpt-4o", 5, 500, 300, 1.5))
+
+	w := helper.PerformRequest("/api/usage?days=3")
+
+	require.Equal(t, consts.StatusOK, w.Code, w.Body.String())
+	var resp dto.GetUsageResponse
+	DecodeResponse(t, w, &resp)
+	assert.Equal(t, 2, resp.Today.Requests)
+	assert.Equal(t, int64(300), resp.Today.TotalTokens)
+	assert.Equal(t, 1.5, resp.Month.Cost)
+	require.Len(t, resp.Daily, 3)
+	assert.Equal(t, "2025-01-13", resp.Daily[0].Date)
+	assert.Equal(t, 0, resp.Daily[0].Requests)
+	assert.Equal(t, "2025-01-15", resp.Daily[2].Date)
+	assert.Equal(t, 2, resp.Daily[2].Requests)
+	require.Len(t, resp.Models, 1)
+	assert.Equal(t, "gpt-4o", resp.Models[0].Model)
+
+	helper.AssertExpectations(t)
+}
+
+// TestGetUsage_INVALID_INPUT 测试天数超出范围
+func TestGetUsage_INVALID_INPUT(t *testing.T) {
+	helper := NewTestHelper(t)
+	defer helper.Close()
+
+	w := helper.PerformRequest("/api/usage?days=91")
+
+	assert.Equal(t, consts.StatusBadRequest, w.Code)
+	helper.AssertExpectations(t)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/erweixin/go-genai-stack/backend/domains/user/model"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 	"github.com/lib/pq"
 )
 
@@ -50,6 +51,11 @@ func NewUserRepository(db *sql.DB, dbType string) UserRepository {
 	}
 }
 
+// conn 返回执行 SQL 的连接（ctx 中有事务时使用事务）
+func (r *userRepository) conn(ctx context.Context) persistence.DBTX {
+	return persistence.Conn(ctx, r.db)
+}
+
 // Create 创建用户
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	// 使用 goqu 构建 INSERT 语句
@@ -74,7 +80,7 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 		return fmt.Errorf("构建插入查询失败: %w", err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	_, err = r.conn(ctx).ExecContext(ctx, query, args...)
 
 	if err != nil {
 		// 检查是否是唯一性约束冲突（PostgreSQL 特有）
@@ -110,7 +116,7 @@ func (r *userRepository) GetByID(ctx context.Context, userID string) (*model.Use
 	var username, fullName, avatarURL sql.NullString
 	var lastLoginAt sql.NullTime
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(
 		&user.ID,
 		&user.Email,
 		&username,
@@ -158,7 +164,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.U
 	var username, fullName, avatarURL sql.NullString
 	var lastLoginAt sql.NullTime
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(
 		&user.ID,
 		&user.Email,
 		&username,
@@ -206,7 +212,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*m
 	var usernameVal, fullName, avatarURL sql.NullString
 	var lastLoginAt sql.NullTime
 
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(
 		&user.ID,
 		&user.Email,
 		&usernameVal,
@@ -259,7 +265,7 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 		return fmt.Errorf("构建更新查询失败: %w", err)
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
 
 	if err != nil {
 		// 检查是否是唯一性约束冲突（PostgreSQL 特有）
@@ -298,7 +304,7 @@ func (r *userRepository) Delete(ctx context.Context, userID string) error {
 		return fmt.Errorf("构建删除查询失败: %w", err)
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := r.conn(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("删除用户失败: %w", err)
 	}
@@ -327,7 +333,7 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 	}
 
 	var exists bool
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exists)
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("检查邮箱是否存在失败: %w", err)
 	}
@@ -347,7 +353,7 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string)
 	}
 
 	var exists bool
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exists)
+	err = r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("检查用户名是否存在失败: %w", err)
 	}
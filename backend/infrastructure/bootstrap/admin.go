@@ -0,0 +1,28 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+
+	usermodel "github.com/erweixin/go-genai-stack/backend/domains/user/model"
+	userrepo "github.com/erweixin/go-genai-stack/backend/domains/user/repository"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
+)
+
+// AdminUserLookup 管理员中间件使用的用户查询：把 User 仓储的用户转换为 middleware.UserInfo
+func AdminUserLookup(users userrepo.UserRepository) middleware.UserLookup {
+	return middleware.UserLookupFunc(func(ctx context.Context, userID string) (*middleware.UserInfo, error) {
+		user, err := users.GetByID(ctx, userID)
+		if errors.Is(err, usermodel.ErrUserNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &middleware.UserInfo{
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Active:        user.CanLogin() == nil,
+		}, nil
+	})
+}
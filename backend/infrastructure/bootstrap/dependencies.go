@@ -1,21 +1,43 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
+	"log"
 
 	authhandlers "github.com/erweixin/go-genai-stack/backend/domains/auth/handlers"
 	authservice "github.com/erweixin/go-genai-stack/backend/domains/auth/service"
+	cataloghandlers "github.com/erweixin/go-genai-stack/backend/domains/catalog/handlers"
+	catalogmodel "github.com/erweixin/go-genai-stack/backend/domains/catalog/model"
+	catalogrepo "github.com/erweixin/go-genai-stack/backend/domains/catalog/repository"
+	catalogservice "github.com/erweixin/go-genai-stack/backend/domains/catalog/service"
+	chathandlers "github.com/erweixin/go-genai-stack/backend/domains/chat/handlers"
+	chatrepo "github.com/erweixin/go-genai-stack/backend/domains/chat/repository"
+	chatservice "github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+	llmprovider "github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	llmrouter "github.com/erweixin/go-genai-stack/backend/domains/llm/router"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tokenizer"
+	prompthandlers "github.com/erweixin/go-genai-stack/backend/domains/prompt/handlers"
+	promptrepo "github.com/erweixin/go-genai-stack/backend/domains/prompt/repository"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
 	taskhandlers "github.com/erweixin/go-genai-stack/backend/domains/task/handlers"
 	taskrepo "github.com/erweixin/go-genai-stack/backend/domains/task/repository"
 	taskservice "github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	usagehandlers "github.com/erweixin/go-genai-stack/backend/domains/usage/handlers"
+	usagerepo "github.com/erweixin/go-genai-stack/backend/domains/usage/repository"
+	usageservice "github.com/erweixin/go-genai-stack/backend/domains/usage/service"
 	userhandlers "github.com/erweixin/go-genai-stack/backend/domains/user/handlers"
 	userrepo "github.com/erweixin/go-genai-stack/backend/domains/user/repository"
 	userservice "github.com/erweixin/go-genai-stack/backend/domains/user/service"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/middleware"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/health"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence/redis"
+	pkgvalidator "github.com/erweixin/go-genai-stack/backend/pkg/validator"
 	redisv9 "github.com/redis/go-redis/v9"
 
 	// 导入数据库提供者（自动注册）
@@ -35,15 +57,41 @@ type AppContainer struct {
 	// Auth 领域
 	AuthHandlerDeps *authhandlers.HandlerDependencies
 	AuthMiddleware  *middleware.AuthMiddleware
+	AdminMiddleware *middleware.AdminMiddleware // 管理员接口（APP_ADMIN_EMAILS 白名单，要求邮箱已验证）
 
 	// User 领域
 	UserHandlerDeps *userhandlers.HandlerDependencies
 
 	// Task 领域
 	TaskHandlerDeps *taskhandlers.HandlerDependencies
+	SnoozeScheduler *taskservice.SnoozeScheduler       // 推迟到期调度器（由 StartBackgroundJobs 启动）
+	TaskEnrichment  *taskservice.EnrichmentService     // 新任务的标签和优先级建议（APP_LLM_TASK_ENRICHMENT=false 时为 nil）
+	SemanticSearch  *taskservice.SemanticSearchService // 任务向量同步和语义搜索
+
+	// Catalog 领域
+	CatalogService     *catalogservice.CatalogService // 模型目录（路由器和参数校验共用）
+	CatalogHandlerDeps *cataloghandlers.HandlerDependencies
+
+	// LLM 领域
+	LLMRegistry *llmprovider.Registry // 已注册的模型提供商（mock 始终注册）
+	LLMRouter   *llmrouter.Router     // 模型路由器（从模型目录中选择）
+	LLMService  *llmservice.LLMService
+
+	// Prompt 领域
+	PromptService     *promptservice.PromptService // 提示词注册表（其他领域通过 Render 获取提示词）
+	PromptHandlerDeps *prompthandlers.HandlerDependencies
+
+	// Chat 领域
+	ChatHandlerDeps *chathandlers.HandlerDependencies
+
+	// Usage 领域
+	UsageHandlerDeps *usagehandlers.HandlerDependencies
+	QuotaService     *usageservice.QuotaService // LLM 调用额度（APP_QUOTA_ENABLED=false 时为 nil）
+
+	// 事件总线（跨领域共享）
+	EventBus sharedevents.EventBus
 
 	// Extension points: 添加更多领域
-	// LLMHandlerDeps  *llmhandlers.HandlerDependencies
 	// MonitoringDeps  *monitoring.HandlerDependencies
 }
 
@@ -108,6 +156,61 @@ func InitDependencies(
 	// 2. User Handler Dependencies
 	userHandlerDeps := userhandlers.NewHandlerDependencies(userService)
 
+	// ============================================
+	// 事件总线（跨领域共享）
+	// ============================================
+	eventBus := sharedevents.NewDefaultEventBus()
+
+	// ============================================
+	// Catalog 领域依赖注入（三层架构）
+	// ============================================
+
+	// 1. Repository Layer（基础设施层）
+	modelRepo := catalogrepo.NewModelRepository(db, dbProvider.Type())
+
+	// 2. Domain Service Layer（领域层）：读取带缓存，同时用于模型和提供商参数校验
+	catalogService := catalogservice.NewCatalogService(modelRepo, cfg.LLM.CatalogCacheTTL)
+	pkgvalidator.SetModelCatalog(catalogService)
+
+	// 3. Handler Dependencies（Handler 层）：仅管理员可访问
+	catalogHandlerDeps := cataloghandlers.NewHandlerDependencies(catalogService)
+	adminMiddleware := middleware.NewAdminMiddleware(cfg.Admin.Emails, AdminUserLookup(userRepo))
+
+	// ============================================
+	// LLM 领域依赖注入
+	// ============================================
+
+	// 1. Provider Registry（基础设施层）：按配置注册 OpenAI 兼容客户端，mock 始终可用
+	llmRegistry, defaultProvider := InitLLMProviders(cfg.LLM)
+
+	// 2. Model Router（领域层）：按策略从模型目录中选择模型
+	llmRouter := InitLLMRouter(cfg.LLM, catalogService, llmRegistry, eventBus)
+
+	// 3. LLM Service（领域层）
+	llmService := llmservice.NewLLMService(llmRegistry, defaultProvider, cfg.LLM.DefaultModel, eventBus).
+		WithRouter(llmRouter)
+
+	// 4. 响应缓存（Redis）：只缓存确定性请求或显式开启的请求
+	if store := InitLLMCache(cfg.LLM, redisConn); store != nil {
+		llmService.WithCache(store, cfg.LLM.CacheTTL, metrics.GetGlobalMetrics())
+	}
+
+	// 5. 熔断器和回退链：提供商不可用时按 APP_LLM_FALLBACK_CHAIN 改用下一个提供商
+	InitLLMResilience(cfg.LLM, llmRegistry, llmService, metrics.GetGlobalMetrics())
+
+	// ============================================
+	// Prompt 领域依赖注入（三层架构）
+	// ============================================
+
+	// 1. Repository Layer（基础设施层）
+	promptRepo := promptrepo.NewPromptRepository(db, dbProvider.Type())
+
+	// 2. Domain Service Layer（领域层）：合并内置模板和管理员创建的版本，渲染带缓存
+	promptService := InitPromptService(cfg.LLM, promptRepo)
+
+	// 3. Handler Dependencies（Handler 层）：仅管理员可访问
+	promptHandlerDeps := prompthandlers.NewHandlerDependencies(promptService)
+
 	// ============================================
 	// Task 领域依赖注入（三层架构）
 	// ============================================
@@ -115,27 +218,97 @@ func InitDependencies(
 	// 1. Repository Layer（基础设施层）
 	// 传递数据库类型给 Repository，用于 goqu 方言选择
 	taskRepo := taskrepo.NewTaskRepository(db, dbProvider.Type())
+	templateRepo := taskrepo.NewTemplateRepository(db, dbProvider.Type())
+	urgencySettingsRepo := taskrepo.NewUrgencySettingsRepository(db, dbProvider.Type())
+	suggestionRepo := taskrepo.NewSuggestionRepository(db, dbProvider.Type())
+
+	// 事务管理器：与数据库类型无关，事务通过 ctx 传递给 Repository
+	txManager := persistence.NewTxManager(db)
+
+	// 内容审核：任务文本和对话的输入输出共用（APP_MODERATION_*），每次决定发布 AlertTriggered
+	moderationPipeline := InitModeration(cfg.Moderation, eventBus)
 
 	// 2. Domain Service Layer（领域层）
-	taskService := taskservice.NewTaskService(taskRepo)
+	taskService := taskservice.NewTaskService(taskRepo).WithEventBus(eventBus).WithModeration(moderationPipeline)
+	templateService := taskservice.NewTemplateService(templateRepo, taskService, txManager)
+	urgencyService := taskservice.NewUrgencyService(taskRepo, urgencySettingsRepo)
+	snoozeScheduler := taskservice.NewSnoozeScheduler(taskRepo, eventBus, txManager, taskservice.DefaultSnoozeCheckInterval)
+	// AI 拆解：提示词来自注册表（task.breakdown），子任务通过 TaskService 创建
+	breakdownService := taskservice.NewBreakdownService(taskService, llmService, promptService, txManager)
+	// 自动建议：订阅 task.created 后在后台生成（task.enrich），用户接受后才修改任务
+	enrichmentService := taskservice.NewEnrichmentService(taskService, taskRepo, suggestionRepo, llmService, promptService, txManager, metrics.GetGlobalMetrics())
+	taskEnrichment := InitTaskEnrichment(cfg.LLM, eventBus, enrichmentService)
+	// 语义搜索：订阅任务事件，在后台生成向量（APP_LLM_EMBEDDING_PROVIDER、APP_LLM_VECTOR_STORE）
+	semanticSearch := InitSemanticSearch(eventBus, taskservice.NewSemanticSearchService(
+		taskRepo, InitEmbedder(cfg.LLM, llmRegistry, llmService), InitVectorStore(cfg.LLM, db)))
+
+	// 3. Handler Dependencies（Handler 层）
+	taskHandlerDeps := taskhandlers.NewHandlerDependencies(taskService, templateService, urgencyService, breakdownService, enrichmentService, semanticSearch)
+
+	// ============================================
+	// Chat 领域依赖注入（三层架构）
+	// ============================================
+
+	// 1. Repository Layer（基础设施层）
+	conversationRepo := chatrepo.NewConversationRepository(db, dbProvider.Type())
+	messageRepo := chatrepo.NewMessageRepository(db, dbProvider.Type())
+
+	// 2. Domain Service Layer（领域层）：回复通过 LLMService 生成，任务助手通过工具调用 TaskService，
+	//    基于资料的回答检索用户的任务，历史消息按模型目录中的上下文窗口裁剪或摘要，用户消息和回复经过内容审核
+	chatService := chatservice.NewChatService(conversationRepo, messageRepo, llmService, txManager, eventBus).
+		WithAgent(TaskAgentTools(taskService), cfg.LLM.AgentMaxSteps).
+		WithRetriever(TaskRetriever(taskService, semanticSearch), cfg.LLM.RetrievalTokenBudget).
+		WithContextManager(chatservice.NewContextManager(tokenizer.Estimator{}, catalogService, cfg.LLM.ContextReserveTokens)).
+		WithModeration(moderationPipeline)
 
 	// 3. Handler Dependencies（Handler 层）
-	taskHandlerDeps := taskhandlers.NewHandlerDependencies(taskService)
+	chatHandlerDeps := chathandlers.NewHandlerDependencies(chatService)
 
 	// ============================================
-	// Extension point: 其他领域依赖注入
+	// Usage 领域依赖注入（三层架构）
 	// ============================================
-	// 示例：添加 LLM 领域
-	//
-	// llmRepo := llmrepo.NewModelRepository(db)
-	// llmService := llmservice.NewLLMService(llmRepo)
-	// llmHandlerDeps := llmhandlers.NewHandlerDependencies(llmService)
+
+	// 1. Repository Layer（基础设施层）
+	usageRepo := usagerepo.NewUsageRepository(db, dbProvider.Type())
+
+	// 2. Domain Service Layer（领域层）：订阅 GenerationCompleted 记录每次调用，价格来自模型目录
+	usageService := usageservice.NewUsageService(usageRepo, catalogService, metrics.GetGlobalMetrics())
+	if err := eventBus.Subscribe("GenerationCompleted", usageService.HandleGenerationCompleted); err != nil {
+		log.Printf("[Usage] ⚠️  订阅 GenerationCompleted 失败: %v", err)
+	}
+
+	// 3. 额度：调用 LLM 前预占、调用后结算，计数优先使用 Redis
+	planRepo := usagerepo.NewPlanRepository(db, dbProvider.Type())
+	quotaService := InitQuotaService(cfg.Quota, redisConn, planRepo, catalogService)
+	var enabledQuota *usageservice.QuotaService
+	if cfg.Quota.Enabled {
+		llmService.WithQuota(quotaService)
+		enabledQuota = quotaService
+	}
+
+	// 4. Handler Dependencies（Handler 层）
+	usageHandlerDeps := usagehandlers.NewHandlerDependencies(usageService, quotaService)
 
 	return &AppContainer{
-		AuthHandlerDeps: authHandlerDeps,
-		AuthMiddleware:  authMiddleware,
-		UserHandlerDeps: userHandlerDeps,
-		TaskHandlerDeps: taskHandlerDeps,
+		AuthHandlerDeps:    authHandlerDeps,
+		AuthMiddleware:     authMiddleware,
+		AdminMiddleware:    adminMiddleware,
+		UserHandlerDeps:    userHandlerDeps,
+		TaskHandlerDeps:    taskHandlerDeps,
+		SnoozeScheduler:    snoozeScheduler,
+		TaskEnrichment:     taskEnrichment,
+		SemanticSearch:     semanticSearch,
+		CatalogService:     catalogService,
+		CatalogHandlerDeps: catalogHandlerDeps,
+		LLMRegistry:        llmRegistry,
+		LLMRouter:          llmRouter,
+		LLMService:         llmService,
+		PromptService:      promptService,
+		PromptHandlerDeps:  promptHandlerDeps,
+		ChatHandlerDeps:    chatHandlerDeps,
+		UsageHandlerDeps:   usageHandlerDeps,
+		QuotaService:       enabledQuota,
+		EventBus:           eventBus,
 	}
 }
 
@@ -163,15 +336,128 @@ func InitDependenciesFromDB(db *sql.DB, redisConn *redis.Connection, cfg *config
 	authHandlerDeps := authhandlers.NewHandlerDependencies(authService)
 	authMiddleware := middleware.NewAuthMiddleware(jwtService)
 
+	// 事件总线
+	eventBus := sharedevents.NewDefaultEventBus()
+
+	// Catalog 领域（三层架构）
+	modelRepo := catalogrepo.NewModelRepository(db, "postgres")
+	catalogService := catalogservice.NewCatalogService(modelRepo, cfg.LLM.CatalogCacheTTL)
+	pkgvalidator.SetModelCatalog(catalogService)
+	catalogHandlerDeps := cataloghandlers.NewHandlerDependencies(catalogService)
+	adminMiddleware := middleware.NewAdminMiddleware(cfg.Admin.Emails, AdminUserLookup(userRepo))
+
+	// LLM 领域（测试配置未设置 API Key 时默认使用 mock）
+	llmRegistry, defaultProvider := InitLLMProviders(cfg.LLM)
+	llmRouter := InitLLMRouter(cfg.LLM, catalogService, llmRegistry, eventBus)
+	llmService := llmservice.NewLLMService(llmRegistry, defaultProvider, cfg.LLM.DefaultModel, eventBus).
+		WithRouter(llmRouter)
+	if store := InitLLMCache(cfg.LLM, redisConn); store != nil {
+		llmService.WithCache(store, cfg.LLM.CacheTTL, nil)
+	}
+	InitLLMResilience(cfg.LLM, llmRegistry, llmService, nil)
+
+	// Prompt 领域（三层架构）
+	promptRepo := promptrepo.NewPromptRepository(db, "postgres")
+	promptService := InitPromptService(cfg.LLM, promptRepo)
+	promptHandlerDeps := prompthandlers.NewHandlerDependencies(promptService)
+
 	// Task 领域（三层架构）
 	taskRepo := taskrepo.NewTaskRepository(db, "postgres")
-	taskService := taskservice.NewTaskService(taskRepo)
-	taskHandlerDeps := taskhandlers.NewHandlerDependencies(taskService)
+	templateRepo := taskrepo.NewTemplateRepository(db, "postgres")
+	urgencySettingsRepo := taskrepo.NewUrgencySettingsRepository(db, "postgres")
+	suggestionRepo := taskrepo.NewSuggestionRepository(db, "postgres")
+	txManager := persistence.NewTxManager(db)
+	moderationPipeline := InitModeration(cfg.Moderation, eventBus)
+	taskService := taskservice.NewTaskService(taskRepo).WithEventBus(eventBus).WithModeration(moderationPipeline)
+	templateService := taskservice.NewTemplateService(templateRepo, taskService, txManager)
+	urgencyService := taskservice.NewUrgencyService(taskRepo, urgencySettingsRepo)
+	snoozeScheduler := taskservice.NewSnoozeScheduler(taskRepo, eventBus, txManager, taskservice.DefaultSnoozeCheckInterval)
+	breakdownService := taskservice.NewBreakdownService(taskService, llmService, promptService, txManager)
+	enrichmentService := taskservice.NewEnrichmentService(taskService, taskRepo, suggestionRepo, llmService, promptService, txManager, nil)
+	taskEnrichment := InitTaskEnrichment(cfg.LLM, eventBus, enrichmentService)
+	semanticSearch := InitSemanticSearch(eventBus, taskservice.NewSemanticSearchService(
+		taskRepo, InitEmbedder(cfg.LLM, llmRegistry, llmService), InitVectorStore(cfg.LLM, db)))
+	taskHandlerDeps := taskhandlers.NewHandlerDependencies(taskService, templateService, urgencyService, breakdownService, enrichmentService, semanticSearch)
+
+	// Chat 领域（三层架构）
+	conversationRepo := chatrepo.NewConversationRepository(db, "postgres")
+	messageRepo := chatrepo.NewMessageRepository(db, "postgres")
+	chatService := chatservice.NewChatService(conversationRepo, messageRepo, llmService, txManager, eventBus).
+		WithAgent(TaskAgentTools(taskService), cfg.LLM.AgentMaxSteps).
+		WithRetriever(TaskRetriever(taskService, semanticSearch), cfg.LLM.RetrievalTokenBudget).
+		WithContextManager(chatservice.NewContextManager(tokenizer.Estimator{}, catalogService, cfg.LLM.ContextReserveTokens)).
+		WithModeration(moderationPipeline)
+	chatHandlerDeps := chathandlers.NewHandlerDependencies(chatService)
+
+	// Usage 领域（三层架构，测试中不采集 Prometheus 指标）
+	usageRepo := usagerepo.NewUsageRepository(db, "postgres")
+	usageService := usageservice.NewUsageService(usageRepo, catalogService, nil)
+	if err := eventBus.Subscribe("GenerationCompleted", usageService.HandleGenerationCompleted); err != nil {
+		log.Printf("[Usage] ⚠️  订阅 GenerationCompleted 失败: %v", err)
+	}
+	planRepo := usagerepo.NewPlanRepository(db, "postgres")
+	quotaService := InitQuotaService(cfg.Quota, redisConn, planRepo, catalogService)
+	var enabledQuota *usageservice.QuotaService
+	if cfg.Quota.Enabled {
+		llmService.WithQuota(quotaService)
+		enabledQuota = quotaService
+	}
+	usageHandlerDeps := usagehandlers.NewHandlerDependencies(usageService, quotaService)
 
 	return &AppContainer{
-		AuthHandlerDeps: authHandlerDeps,
-		AuthMiddleware:  authMiddleware,
-		UserHandlerDeps: userHandlerDeps,
-		TaskHandlerDeps: taskHandlerDeps,
+		AuthHandlerDeps:    authHandlerDeps,
+		AuthMiddleware:     authMiddleware,
+		AdminMiddleware:    adminMiddleware,
+		UserHandlerDeps:    userHandlerDeps,
+		TaskHandlerDeps:    taskHandlerDeps,
+		SnoozeScheduler:    snoozeScheduler,
+		TaskEnrichment:     taskEnrichment,
+		SemanticSearch:     semanticSearch,
+		CatalogService:     catalogService,
+		CatalogHandlerDeps: catalogHandlerDeps,
+		LLMRegistry:        llmRegistry,
+		LLMRouter:          llmRouter,
+		LLMService:         llmService,
+		PromptService:      promptService,
+		PromptHandlerDeps:  promptHandlerDeps,
+		ChatHandlerDeps:    chatHandlerDeps,
+		UsageHandlerDeps:   usageHandlerDeps,
+		QuotaService:       enabledQuota,
+		EventBus:           eventBus,
+	}
+}
+
+// SeedModelCatalog 模型目录为空时写入内置模型（启动时调用）
+//
+// 失败只记录日志：目录为空时路由器没有候选，请求会使用默认模型或返回 NO_MODEL_AVAILABLE。
+func (c *AppContainer) SeedModelCatalog(ctx context.Context) {
+	if c.CatalogService == nil {
+		return
+	}
+	n, err := c.CatalogService.SeedDefaults(ctx, catalogmodel.DefaultModels())
+	if err != nil {
+		log.Printf("[Catalog] ⚠️  写入内置模型失败: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[Catalog] 模型目录为空，已写入 %d 个内置模型", n)
+	}
+}
+
+// StartBackgroundJobs 启动后台任务（ctx 取消时停止）
+//
+// 当前包括：
+//   - SnoozeScheduler：推迟到期后清除 hidden_until 并发布 task.resurfaced
+//   - TaskEnrichment：为新任务生成标签和优先级建议（APP_LLM_TASK_ENRICHMENT=true 时）
+//   - SemanticSearch：为创建和修改的任务生成向量
+func (c *AppContainer) StartBackgroundJobs(ctx context.Context) {
+	if c.SnoozeScheduler != nil {
+		c.SnoozeScheduler.Start(ctx)
+	}
+	if c.TaskEnrichment != nil {
+		c.TaskEnrichment.Start(ctx)
+	}
+	if c.SemanticSearch != nil {
+		c.SemanticSearch.Start(ctx)
 	}
 }
@@ -0,0 +1,208 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	llmcache "github.com/erweixin/go-genai-stack/backend/domains/llm/cache"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/embedding"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/model"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/mock"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/provider/openai"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/router"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/vectorstore"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/health"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence/redis"
+)
+
+// knownBaseURLs 已知提供商的 OpenAI 兼容接口地址（未配置 APP_LLM_BASE_URLS_<NAME> 时使用）
+var knownBaseURLs = map[string]string{
+	"openai":    openai.DefaultBaseURL,
+	"anthropic": "https://api.anthropic.com/v1",
+}
+
+// InitLLMProviders 根据配置注册 LLM 提供商
+//
+// 注册规则：
+//   - mock 始终注册（离线开发和测试）
+//   - 配置了 API Key（APP_LLM_PROVIDERS_<NAME>）或地址（APP_LLM_BASE_URLS_<NAME>）的
+//     提供商使用 OpenAI 兼容客户端注册
+//
+// 返回注册表和实际使用的默认提供商：配置的默认提供商未注册时回退到 mock。
+func InitLLMProviders(cfg config.LLMConfig) (*provider.Registry, string) {
+	registry := provider.NewRegistry()
+	registry.Register(mock.New())
+
+	names := make(map[string]struct{})
+	for name := range cfg.Providers {
+		names[name] = struct{}{}
+	}
+	for name := range cfg.BaseURLs {
+		names[name] = struct{}{}
+	}
+	delete(names, mock.Name)
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		baseURL := cfg.BaseURLs[name]
+		if baseURL == "" {
+			baseURL = knownBaseURLs[name]
+		}
+		if baseURL == "" {
+			log.Printf("[LLM] ⚠️  Provider %q 缺少 APP_LLM_BASE_URLS_%s，跳过", name, strings.ToUpper(name))
+			continue
+		}
+		registry.Register(openai.New(openai.Config{
+			Name:           name,
+			BaseURL:        baseURL,
+			APIKey:         cfg.Providers[name],
+			Timeout:        cfg.Timeout,
+			MaxRetries:     cfg.MaxRetries,
+			RetryBaseDelay: cfg.RetryBaseDelay,
+			RetryMaxDelay:  cfg.RetryMaxDelay,
+		}))
+	}
+
+	defaultProvider := cfg.DefaultProvider
+	if !registry.Has(defaultProvider) {
+		log.Printf("[LLM] ⚠️  Provider %q 未配置 API Key 或地址，回退到 mock", defaultProvider)
+		defaultProvider = mock.Name
+	}
+	log.Printf("[LLM] Providers: %v（默认 %s）", registry.Names(), defaultProvider)
+
+	return registry, defaultProvider
+}
+
+// breakerStateValues llm_circuit_breaker_state 指标的取值
+var breakerStateValues = map[provider.BreakerState]float64{
+	provider.BreakerClosed:   0,
+	provider.BreakerHalfOpen: 1,
+	provider.BreakerOpen:     2,
+}
+
+// InitLLMResilience 为 LLM 服务设置熔断器和回退链
+//
+// 每个已注册的提供商一个熔断器（APP_LLM_BREAKER_THRESHOLD=0 时不熔断），
+// 状态显示在 /health 的 llm:<provider> 检查项中（不影响总体状态）并导出为
+// llm_circuit_breaker_state{provider} 指标（0 closed、1 half_open、2 open）。
+// 回退链（APP_LLM_FALLBACK_CHAIN）中无效或未注册的提供商会被跳过。m 为 nil 时不采集指标。
+func InitLLMResilience(cfg config.LLMConfig, registry *provider.Registry, llmService *llmservice.LLMService, m *metrics.Metrics) {
+	breakers := provider.NewBreakers(provider.BreakerConfig{
+		FailureThreshold: cfg.BreakerThreshold,
+		Cooldown:         cfg.BreakerCooldown,
+	})
+	if m != nil {
+		gauge := m.NewGaugeVec("llm_circuit_breaker_state", "LLM provider circuit breaker state (0 closed, 1 half_open, 2 open)", []string{"provider"})
+		for _, name := range registry.Names() {
+			gauge.WithLabelValues(name).Set(breakerStateValues[provider.BreakerClosed])
+		}
+		breakers.OnStateChange(func(name string, state provider.BreakerState) {
+			gauge.WithLabelValues(name).Set(breakerStateValues[state])
+		})
+	}
+	if checker := health.GetGlobalChecker(); checker != nil {
+		for _, name := range registry.Names() {
+			checker.AddCheck("llm:"+name, breakerCheck(breakers, name))
+		}
+	}
+
+	chain := make([]llmservice.FallbackTarget, 0, len(cfg.FallbackChain))
+	for _, item := range cfg.FallbackChain {
+		target, err := llmservice.ParseFallbackTarget(item)
+		if err != nil {
+			log.Printf("[LLM] ⚠️  %v，跳过", err)
+			continue
+		}
+		if !registry.Has(target.Provider) {
+			log.Printf("[LLM] ⚠️  Fallback provider %q 未注册，跳过", target.Provider)
+			continue
+		}
+		chain = append(chain, target)
+	}
+	if len(chain) > 0 {
+		log.Printf("[LLM] Fallback chain: %v", chain)
+	}
+
+	llmService.WithBreakers(breakers).WithFallback(chain, m)
+}
+
+// breakerCheck 把提供商的熔断器状态转换为健康检查项（熔断时为 down）
+func breakerCheck(breakers *provider.Breakers, name string) health.CheckFunc {
+	return func(ctx context.Context) health.CheckItem {
+		status := breakers.Status(name)
+		item := health.CheckItem{Status: health.StatusUp, Message: string(status.State)}
+		if status.State == provider.BreakerOpen {
+			item.Status = health.StatusDown
+			item.Message = fmt.Sprintf("open: %d consecutive failures, retry at %s", status.Failures, status.RetryAt.Format(time.RFC3339))
+		}
+		return item
+	}
+}
+
+// InitLLMRouter 创建模型路由器
+//
+// 模型来自 catalog 领域的模型目录（管理员通过 /api/admin/models 维护），
+// 只有已注册的提供商的模型参与路由。
+// APP_LLM_ROUTING_STRATEGY 为空时只路由指定了策略的请求，其余请求使用默认模型。
+func InitLLMRouter(cfg config.LLMConfig, catalog router.Catalog, registry *provider.Registry, eventBus sharedevents.EventBus) *router.Router {
+	strategy := model.Strategy(cfg.RoutingStrategy)
+	if strategy != "" {
+		log.Printf("[LLM] Routing strategy: %s", strategy)
+	}
+	return router.NewRouter(catalog, registry, strategy, eventBus)
+}
+
+// InitLLMCache 根据配置创建 LLM 响应缓存存储
+//
+// 未启用（APP_LLM_CACHE_ENABLED=false）或 Redis 不可用时返回 nil（不缓存）。
+// 缓存需要在多个实例间共享，因此不回退到进程内缓存。
+func InitLLMCache(cfg config.LLMConfig, redisConn *redis.Connection) llmservice.ResponseCache {
+	if !cfg.CacheEnabled {
+		return nil
+	}
+	if redisConn == nil {
+		log.Printf("[LLM] ⚠️  Redis 不可用，LLM 响应缓存已禁用")
+		return nil
+	}
+	return llmcache.NewRedisStore(redis.NewCache(redisConn.Client()))
+}
+
+// InitEmbedder 根据配置创建向量嵌入
+//
+// APP_LLM_EMBEDDING_PROVIDER=local（默认）使用本地确定性嵌入（不理解语义，适合开发和测试）；
+// 其他值通过 LLMService 调用该提供商的嵌入接口，提供商未注册时回退到本地嵌入。
+func InitEmbedder(cfg config.LLMConfig, registry *provider.Registry, llmService *llmservice.LLMService) embedding.Embedder {
+	if cfg.EmbeddingProvider != "local" {
+		if registry.Has(cfg.EmbeddingProvider) {
+			log.Printf("[LLM] Embedding: %s/%s", cfg.EmbeddingProvider, cfg.EmbeddingModel)
+			return embedding.NewServiceEmbedder(llmService, cfg.EmbeddingProvider, cfg.EmbeddingModel)
+		}
+		log.Printf("[LLM] ⚠️  Embedding provider %q 未注册，回退到本地嵌入", cfg.EmbeddingProvider)
+	}
+	return embedding.NewHashEmbedder(embedding.DefaultHashDimensions)
+}
+
+// InitVectorStore 根据配置创建向量存储
+//
+// memory（默认）重启后为空，只有之后创建或修改的数据可以搜索；pgvector 需要先执行 database/pgvector.sql。
+func InitVectorStore(cfg config.LLMConfig, db *sql.DB) vectorstore.Store {
+	if cfg.VectorStore == "pgvector" {
+		return vectorstore.NewPGStore(db)
+	}
+	return vectorstore.NewMemoryStore()
+}
@@ -0,0 +1,91 @@
+package bootstrap
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/moderation"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/logger"
+	"go.uber.org/zap"
+)
+
+// InitModeration 根据配置创建内容审核流水线（对话的输入输出和任务文本共用）
+//
+// 检查顺序：屏蔽词（APP_MODERATION_WORDLIST、APP_MODERATION_WORDLIST_FILE）、个人信息、提示词注入，
+// 处理方式为 off 的检查不运行。未启用（APP_MODERATION_ENABLED=false）时返回 nil（不审核）。
+// 屏蔽词文件读取失败时只使用 APP_MODERATION_WORDLIST。
+//
+// 每次审核决定都发布 AlertTriggered，这里订阅后写入日志。
+func InitModeration(cfg config.ModerationConfig, eventBus sharedevents.EventBus) *moderation.Pipeline {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	var rules []moderation.Rule
+	if cfg.WordlistAction != "off" {
+		words := cfg.Wordlist
+		if cfg.WordlistFile != "" {
+			if fileWords, err := readWordlistFile(cfg.WordlistFile); err != nil {
+				log.Printf("[Moderation] ⚠️  读取屏蔽词文件失败，只使用 APP_MODERATION_WORDLIST: %v", err)
+			} else {
+				words = append(append([]string{}, words...), fileWords...)
+			}
+		}
+		if wordlist, err := moderation.NewWordlist(words); err != nil {
+			log.Printf("[Moderation] ⚠️  屏蔽词无效，屏蔽词检查已禁用: %v", err)
+		} else {
+			rules = appendRule(rules, wordlist, cfg.WordlistAction)
+		}
+	}
+	if cfg.PIIAction != "off" {
+		rules = appendRule(rules, moderation.PII{}, cfg.PIIAction)
+	}
+	if cfg.InjectionAction != "off" {
+		rules = appendRule(rules, moderation.Injection{}, cfg.InjectionAction)
+	}
+
+	if err := eventBus.Subscribe("AlertTriggered", logModerationAlert); err != nil {
+		log.Printf("[Moderation] ⚠️  订阅 AlertTriggered 失败: %v", err)
+	}
+	return moderation.NewPipeline(eventBus, rules...)
+}
+
+// appendRule 添加检查（处理方式已由配置加载校验）
+func appendRule(rules []moderation.Rule, checker moderation.Checker, action string) []moderation.Rule {
+	a, err := moderation.ParseAction(action)
+	if err != nil {
+		log.Printf("[Moderation] ⚠️  %s 检查已禁用: %v", checker.Name(), err)
+		return rules
+	}
+	return append(rules, moderation.Rule{Checker: checker, Action: a})
+}
+
+// readWordlistFile 读取屏蔽词文件
+func readWordlistFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return moderation.ReadWordlist(f)
+}
+
+// logModerationAlert 把审核决定写入日志（事件中不包含命中的原文）
+func logModerationAlert(ctx context.Context, event sharedevents.Event) error {
+	payload, ok := event.Payload().(sharedevents.AlertTriggeredPayload)
+	if !ok || payload.AlertName != moderation.AlertName {
+		return nil
+	}
+	logger.Warn("content moderation",
+		zap.String("severity", payload.Severity),
+		zap.String("source", payload.Labels["source"]),
+		zap.String("user_id", payload.Labels["user_id"]),
+		zap.String("action", payload.Labels["action"]),
+		zap.String("categories", payload.Labels["categories"]),
+		zap.String("message", payload.Message),
+	)
+	return nil
+}
@@ -0,0 +1,22 @@
+package bootstrap
+
+import (
+	"log"
+
+	promptrepo "github.com/erweixin/go-genai-stack/backend/domains/prompt/repository"
+	promptservice "github.com/erweixin/go-genai-stack/backend/domains/prompt/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/prompt/templates"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+)
+
+// InitPromptService 创建提示词服务（加载内置模板）
+//
+// 内置模板加载失败只记录日志（此时只有管理员创建的版本可用）；
+// 模板文件随程序发布，由 prompt 领域的测试保证可以加载。
+func InitPromptService(cfg config.LLMConfig, repo promptrepo.PromptRepository) *promptservice.PromptService {
+	embedded, err := promptservice.LoadTemplates(templates.FS)
+	if err != nil {
+		log.Printf("[Prompt] ⚠️  加载内置提示词模板失败: %v", err)
+	}
+	return promptservice.NewPromptService(repo, embedded, cfg.PromptCacheTTL)
+}
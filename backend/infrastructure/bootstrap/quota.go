@@ -0,0 +1,58 @@
+package bootstrap
+
+import (
+	"log"
+	"sort"
+
+	usagemodel "github.com/erweixin/go-genai-stack/backend/domains/usage/model"
+	usagerepo "github.com/erweixin/go-genai-stack/backend/domains/usage/repository"
+	usageservice "github.com/erweixin/go-genai-stack/backend/domains/usage/service"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/persistence/redis"
+)
+
+// InitQuotaService 根据配置创建额度服务
+//
+// 计数器选择：
+//   - Redis 可用时使用 Redis（多实例共享，Lua 脚本原子地检查并预占）
+//   - 否则使用进程内计数（每个实例单独计数）
+func InitQuotaService(
+	cfg config.QuotaConfig,
+	redisConn *redis.Connection,
+	planRepo usagerepo.PlanRepository,
+	prices usageservice.PriceLookup,
+) *usageservice.QuotaService {
+	var counter usagerepo.QuotaCounter
+	if redisConn != nil {
+		counter = usagerepo.NewRedisQuotaCounter(redisConn.Client())
+	} else {
+		counter = usagerepo.NewMemoryQuotaCounter()
+		if cfg.Enabled {
+			log.Printf("[Quota] ⚠️  Redis 不可用，额度使用进程内计数（多实例部署时各实例单独计数）")
+		}
+	}
+
+	return usageservice.NewQuotaService(counter, planRepo, prices, quotaPlans(cfg), cfg.DefaultPlan)
+}
+
+// quotaPlans 将配置中的套餐转换为领域模型（按名称排序）
+func quotaPlans(cfg config.QuotaConfig) []usagemodel.Plan {
+	names := make([]string, 0, len(cfg.Plans))
+	for name := range cfg.Plans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	plans := make([]usagemodel.Plan, 0, len(names))
+	for _, name := range names {
+		p := cfg.Plans[name]
+		plans = append(plans, usagemodel.Plan{
+			Name:          name,
+			DailyTokens:   p.DailyTokens,
+			MonthlyTokens: p.MonthlyTokens,
+			DailyCost:     p.DailyCost,
+			MonthlyCost:   p.MonthlyCost,
+		})
+	}
+	return plans
+}
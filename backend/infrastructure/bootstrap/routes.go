@@ -7,7 +7,11 @@ import (
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
 	authhttp "github.com/erweixin/go-genai-stack/backend/domains/auth/http"
+	cataloghttp "github.com/erweixin/go-genai-stack/backend/domains/catalog/http"
+	chathttp "github.com/erweixin/go-genai-stack/backend/domains/chat/http"
+	prompthttp "github.com/erweixin/go-genai-stack/backend/domains/prompt/http"
 	taskhttp "github.com/erweixin/go-genai-stack/backend/domains/task/http"
+	usagehttp "github.com/erweixin/go-genai-stack/backend/domains/usage/http"
 	userhttp "github.com/erweixin/go-genai-stack/backend/domains/user/http"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/health"
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/monitoring/metrics"
@@ -30,11 +34,28 @@ func RegisterRoutes(h *server.Hertz, container *AppContainer) {
 		// 注册 User 领域路由（需要认证）
 		userhttp.RegisterRoutes(api, container.UserHandlerDeps, container.AuthMiddleware)
 
-		// 注册 Task 领域路由（需要认证）
-		taskhttp.RegisterRoutes(api, container.TaskHandlerDeps, container.AuthMiddleware)
+		// 调用模型的路由在启用额度时返回剩余额度响应头
+		var generation []app.HandlerFunc
+		if container.QuotaService != nil {
+			generation = append(generation, container.UsageHandlerDeps.QuotaHeaders())
+		}
+
+		// 注册 Task 领域路由（需要认证，AI 拆解子任务使用 generation 中间件）
+		taskhttp.RegisterRoutes(api, container.TaskHandlerDeps, container.AuthMiddleware, generation...)
+
+		// 注册 Chat 领域路由（需要认证，发送消息使用 generation 中间件）
+		chathttp.RegisterRoutes(api, container.ChatHandlerDeps, container.AuthMiddleware, generation...)
+
+		// 注册 Usage 领域路由（需要认证，分配套餐需要管理员）
+		usagehttp.RegisterRoutes(api, container.UsageHandlerDeps, container.AuthMiddleware, container.AdminMiddleware)
+
+		// 注册 Catalog 领域路由（需要认证 + 管理员）
+		cataloghttp.RegisterRoutes(api, container.CatalogHandlerDeps, container.AuthMiddleware, container.AdminMiddleware)
+
+		// 注册 Prompt 领域路由（需要认证 + 管理员）
+		prompthttp.RegisterRoutes(api, container.PromptHandlerDeps, container.AuthMiddleware, container.AdminMiddleware)
 
 		// Extension point: 注册其他领域路由
-		// llmhttp.RegisterRoutes(api, container.LLMHandlerDeps)
 		// monitoringhttp.RegisterRoutes(api, container.MonitoringDeps)
 	}
 }
@@ -42,6 +42,9 @@ func RegisterMiddleware(h *server.Hertz) {
 	// 5. ErrorHandler（统一错误处理）
 	h.Use(middleware.ErrorHandler())
 
+	// 6. LLMCacheBypass（X-LLM-Cache: bypass 时跳过 LLM 响应缓存）
+	h.Use(middleware.LLMCacheBypass())
+
 	// Extension point: 添加更多中间件
 	// h.Use(middleware.Auth())
 	// h.Use(middleware.RateLimit())
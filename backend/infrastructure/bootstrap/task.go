@@ -0,0 +1,63 @@
+package bootstrap
+
+import (
+	"log"
+
+	chatservice "github.com/erweixin/go-genai-stack/backend/domains/chat/service"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/retrieval"
+	"github.com/erweixin/go-genai-stack/backend/domains/llm/tool"
+	sharedevents "github.com/erweixin/go-genai-stack/backend/domains/shared/events"
+	taskretriever "github.com/erweixin/go-genai-stack/backend/domains/task/retriever"
+	taskservice "github.com/erweixin/go-genai-stack/backend/domains/task/service"
+	tasktools "github.com/erweixin/go-genai-stack/backend/domains/task/tools"
+	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
+)
+
+// InitTaskEnrichment 根据配置订阅 task.created，为新任务生成标签和优先级建议
+//
+// 未启用（APP_LLM_TASK_ENRICHMENT=false）或订阅失败时返回 nil（不生成建议，
+// 建议相关的接口仍然可用，只是没有建议）。返回的服务由 StartBackgroundJobs 启动。
+func InitTaskEnrichment(cfg config.LLMConfig, eventBus sharedevents.EventBus, svc *taskservice.EnrichmentService) *taskservice.EnrichmentService {
+	if !cfg.TaskEnrichment {
+		return nil
+	}
+	if err := eventBus.Subscribe("task.created", svc.HandleTaskCreated); err != nil {
+		log.Printf("[Task] ⚠️  订阅 task.created 失败，自动建议已禁用: %v", err)
+		return nil
+	}
+	return svc
+}
+
+// InitSemanticSearch 订阅任务事件，在后台同步任务向量
+//
+// 订阅失败时只记录日志（对应的变更不会同步，搜索结果可能过期）。返回的服务由 StartBackgroundJobs 启动。
+func InitSemanticSearch(eventBus sharedevents.EventBus, svc *taskservice.SemanticSearchService) *taskservice.SemanticSearchService {
+	subscriptions := []struct {
+		eventType string
+		handler   sharedevents.EventHandler
+	}{
+		{"task.created", svc.HandleTaskCreated},
+		{"task.updated", svc.HandleTaskUpdated},
+		{"task.deleted", svc.HandleTaskDeleted},
+	}
+	for _, sub := range subscriptions {
+		if err := eventBus.Subscribe(sub.eventType, sub.handler); err != nil {
+			log.Printf("[Task] ⚠️  订阅 %s 失败，语义搜索不会同步此类变更: %v", sub.eventType, err)
+		}
+	}
+	return svc
+}
+
+// TaskRetriever 基于资料回答（Chat 领域）使用的任务检索：结构化条件 + 语义搜索
+func TaskRetriever(svc *taskservice.TaskService, semantic *taskservice.SemanticSearchService) retrieval.Retriever {
+	return taskretriever.New(svc, semantic)
+}
+
+// TaskAgentTools 任务助手（Chat 领域）使用的工具集：TaskService 的列出、创建、修改和完成任务
+//
+// 每次请求按当前用户创建，工具只能访问该用户的任务。
+func TaskAgentTools(svc *taskservice.TaskService) chatservice.ToolsetFactory {
+	return func(userID string) (*tool.Registry, error) {
+		return tasktools.NewRegistry(svc, userID)
+	}
+}
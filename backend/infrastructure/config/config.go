@@ -15,7 +15,10 @@ type Config struct {
 	Database   DatabaseConfig
 	Redis      RedisConfig
 	LLM        LLMConfig
+	Quota      QuotaConfig
+	Moderation ModerationConfig
 	JWT        JWTConfig
+	Admin      AdminConfig
 	Logging    LoggingConfig
 	Monitoring MonitoringConfig
 }
@@ -67,6 +70,54 @@ type LLMConfig struct {
 	Timeout         time.Duration
 	MaxRetries      int
 	Providers       map[string]string // provider -> API key
+	BaseURLs        map[string]string // provider -> API 地址（OpenAI 兼容接口，可选）
+	RoutingStrategy string            // 默认路由策略：latency、cost、quality、random（为空时不路由）
+	CatalogCacheTTL time.Duration     // 模型目录内存缓存有效期
+	PromptCacheTTL  time.Duration     // 提示词模板内存缓存有效期
+	CacheEnabled    bool              // 是否启用 LLM 响应缓存（需要 Redis）
+	CacheTTL        time.Duration     // LLM 响应缓存的默认有效期（提示词模板可以单独设置）
+	TaskEnrichment  bool              // 是否为新任务自动生成标签和优先级建议（需要用户接受才生效）
+	AgentMaxSteps   int               // 任务助手每次运行最多调用模型的次数
+
+	RetrievalTokenBudget int // 基于资料回答时资料部分最多占用的 Token 数
+	ContextReserveTokens int // 对话的上下文窗口中为回复预留的 Token 数
+
+	EmbeddingProvider string // 向量嵌入提供商（local 为本地确定性嵌入，不理解语义）
+	EmbeddingModel    string // 向量嵌入模型（provider 不是 local 时必需）
+	VectorStore       string // 向量存储：memory（进程内）或 pgvector（需要 database/pgvector.sql）
+
+	RetryBaseDelay   time.Duration // 第一次重试的退避上限（指数退避加随机抖动）
+	RetryMaxDelay    time.Duration // 单次重试等待的上限（Retry-After 超过时不再重试，直接回退）
+	BreakerThreshold int           // 提供商连续失败多少次后熔断（0 不熔断）
+	BreakerCooldown  time.Duration // 熔断多久后放行试探请求
+	FallbackChain    []string      // 有序的回退链，每项为 provider 或 provider:model（为空时不回退）
+}
+
+// QuotaConfig LLM 用量额度配置
+type QuotaConfig struct {
+	Enabled     bool                       // 是否启用额度检查
+	DefaultPlan string                     // 未分配套餐的用户使用的套餐
+	Plans       map[string]QuotaPlanConfig // 套餐名 -> 限额
+}
+
+// QuotaPlanConfig 套餐限额（0 表示不限制）
+type QuotaPlanConfig struct {
+	DailyTokens   int64
+	MonthlyTokens int64
+	DailyCost     float64 // 美元
+	MonthlyCost   float64 // 美元
+}
+
+// ModerationConfig 内容安全配置（对话的输入输出和任务文本）
+//
+// 每个检查的处理方式：block（拒绝）、redact（替换后放行）、flag（只记录）或 off（不检查）。
+type ModerationConfig struct {
+	Enabled         bool     // 是否启用内容审核
+	Wordlist        []string // 屏蔽词（按词边界匹配，以 * 结尾时匹配前缀）
+	WordlistFile    string   // 屏蔽词文件（每行一个，# 开头为注释），与 Wordlist 合并
+	WordlistAction  string   // 屏蔽词的处理方式
+	PIIAction       string   // 个人信息（邮箱、电话号码、银行卡号）的处理方式
+	InjectionAction string   // 提示词注入的处理方式
 }
 
 // JWTConfig JWT 配置
@@ -77,6 +128,11 @@ type JWTConfig struct {
 	Issuer             string        // 签发者
 }
 
+// AdminConfig 管理员配置
+type AdminConfig struct {
+	Emails []string // 管理员邮箱（可访问 /api/admin/*），为空时没有管理员
+}
+
 // LoggingConfig 日志配置
 type LoggingConfig struct {
 	Enabled    bool   // 是否启用结构化日志（false 时使用标准 log）
@@ -153,6 +209,39 @@ func DefaultConfig() *Config {
 			Timeout:         30 * time.Second,
 			MaxRetries:      3,
 			Providers:       make(map[string]string),
+			BaseURLs:        make(map[string]string),
+			CatalogCacheTTL: time.Minute,
+			PromptCacheTTL:  time.Minute,
+			CacheEnabled:    true,
+			CacheTTL:        time.Hour,
+			AgentMaxSteps:   8,
+
+			RetrievalTokenBudget: 2000,
+			ContextReserveTokens: 1024,
+
+			EmbeddingProvider: "local",
+			VectorStore:       "memory",
+
+			RetryBaseDelay:   500 * time.Millisecond,
+			RetryMaxDelay:    30 * time.Second,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Quota: QuotaConfig{
+			Enabled:     true,
+			DefaultPlan: "free",
+			Plans: map[string]QuotaPlanConfig{
+				"free":       {DailyTokens: 100_000, MonthlyTokens: 2_000_000, DailyCost: 1, MonthlyCost: 20},
+				"pro":        {DailyTokens: 1_000_000, MonthlyTokens: 20_000_000, DailyCost: 10, MonthlyCost: 200},
+				"enterprise": {}, // 不限制
+			},
+		},
+		Moderation: ModerationConfig{
+			Enabled:         true,
+			Wordlist:        []string{"fuck*", "shit*", "damn"},
+			WordlistAction:  "block",
+			PIIAction:       "redact",
+			InjectionAction: "flag",
 		},
 		JWT: JWTConfig{
 			Secret:             "change-this-secret-in-production",
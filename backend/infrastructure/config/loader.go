@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -47,11 +48,24 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to load llm config: %w", err)
 	}
 
+	// 加载 Quota 配置
+	if err := loadQuotaConfig(&cfg.Quota); err != nil {
+		return nil, fmt.Errorf("failed to load quota config: %w", err)
+	}
+
+	// 加载 Moderation 配置
+	if err := loadModerationConfig(&cfg.Moderation); err != nil {
+		return nil, fmt.Errorf("failed to load moderation config: %w", err)
+	}
+
 	// 加载 JWT 配置
 	if err := loadJWTConfig(&cfg.JWT); err != nil {
 		return nil, fmt.Errorf("failed to load jwt config: %w", err)
 	}
 
+	// 加载 Admin 配置
+	loadAdminConfig(&cfg.Admin)
+
 	// 加载 Logging 配置
 	if err := loadLoggingConfig(&cfg.Logging); err != nil {
 		return nil, fmt.Errorf("failed to load logging config: %w", err)
@@ -209,6 +223,7 @@ func loadRedisConfig(cfg *RedisConfig) error {
 func loadLLMConfig(cfg *LLMConfig) error {
 	cfg.DefaultModel = getEnvString("APP_LLM_DEFAULT_MODEL", cfg.DefaultModel)
 	cfg.DefaultProvider = getEnvString("APP_LLM_DEFAULT_PROVIDER", cfg.DefaultProvider)
+	cfg.RoutingStrategy = getEnvString("APP_LLM_ROUTING_STRATEGY", cfg.RoutingStrategy)
 
 	if timeout, err := getEnvDuration("APP_LLM_TIMEOUT", cfg.Timeout); err != nil {
 		return fmt.Errorf("invalid APP_LLM_TIMEOUT: %w", err)
@@ -222,6 +237,196 @@ func loadLLMConfig(cfg *LLMConfig) error {
 		cfg.MaxRetries = retries
 	}
 
+	if ttl, err := getEnvDuration("APP_LLM_CATALOG_CACHE_TTL", cfg.CatalogCacheTTL); err != nil {
+		return fmt.Errorf("invalid APP_LLM_CATALOG_CACHE_TTL: %w", err)
+	} else {
+		cfg.CatalogCacheTTL = ttl
+	}
+
+	if ttl, err := getEnvDuration("APP_LLM_PROMPT_CACHE_TTL", cfg.PromptCacheTTL); err != nil {
+		return fmt.Errorf("invalid APP_LLM_PROMPT_CACHE_TTL: %w", err)
+	} else {
+		cfg.PromptCacheTTL = ttl
+	}
+
+	if enabled, err := getEnvBool("APP_LLM_CACHE_ENABLED", cfg.CacheEnabled); err != nil {
+		return fmt.Errorf("invalid APP_LLM_CACHE_ENABLED: %w", err)
+	} else {
+		cfg.CacheEnabled = enabled
+	}
+
+	if ttl, err := getEnvDuration("APP_LLM_CACHE_TTL", cfg.CacheTTL); err != nil {
+		return fmt.Errorf("invalid APP_LLM_CACHE_TTL: %w", err)
+	} else {
+		cfg.CacheTTL = ttl
+	}
+
+	if enabled, err := getEnvBool("APP_LLM_TASK_ENRICHMENT", cfg.TaskEnrichment); err != nil {
+		return fmt.Errorf("invalid APP_LLM_TASK_ENRICHMENT: %w", err)
+	} else {
+		cfg.TaskEnrichment = enabled
+	}
+
+	if steps, err := getEnvInt("APP_LLM_AGENT_MAX_STEPS", cfg.AgentMaxSteps); err != nil {
+		return fmt.Errorf("invalid APP_LLM_AGENT_MAX_STEPS: %w", err)
+	} else if steps < 1 {
+		return fmt.Errorf("invalid APP_LLM_AGENT_MAX_STEPS: must be at least 1, got %d", steps)
+	} else {
+		cfg.AgentMaxSteps = steps
+	}
+
+	if budget, err := getEnvInt("APP_LLM_RETRIEVAL_TOKEN_BUDGET", cfg.RetrievalTokenBudget); err != nil {
+		return fmt.Errorf("invalid APP_LLM_RETRIEVAL_TOKEN_BUDGET: %w", err)
+	} else if budget < 1 {
+		return fmt.Errorf("invalid APP_LLM_RETRIEVAL_TOKEN_BUDGET: must be at least 1, got %d", budget)
+	} else {
+		cfg.RetrievalTokenBudget = budget
+	}
+
+	if reserve, err := getEnvInt("APP_LLM_CONTEXT_RESERVE_TOKENS", cfg.ContextReserveTokens); err != nil {
+		return fmt.Errorf("invalid APP_LLM_CONTEXT_RESERVE_TOKENS: %w", err)
+	} else if reserve < 1 {
+		return fmt.Errorf("invalid APP_LLM_CONTEXT_RESERVE_TOKENS: must be at least 1, got %d", reserve)
+	} else {
+		cfg.ContextReserveTokens = reserve
+	}
+
+	cfg.EmbeddingProvider = getEnvString("APP_LLM_EMBEDDING_PROVIDER", cfg.EmbeddingProvider)
+	cfg.EmbeddingModel = getEnvString("APP_LLM_EMBEDDING_MODEL", cfg.EmbeddingModel)
+	if cfg.EmbeddingProvider != "local" && cfg.EmbeddingModel == "" {
+		return fmt.Errorf("invalid APP_LLM_EMBEDDING_MODEL: required when APP_LLM_EMBEDDING_PROVIDER is %q", cfg.EmbeddingProvider)
+	}
+
+	cfg.VectorStore = getEnvString("APP_LLM_VECTOR_STORE", cfg.VectorStore)
+	if cfg.VectorStore != "memory" && cfg.VectorStore != "pgvector" {
+		return fmt.Errorf("invalid APP_LLM_VECTOR_STORE: must be memory or pgvector, got %q", cfg.VectorStore)
+	}
+
+	if delay, err := getEnvDuration("APP_LLM_RETRY_BASE_DELAY", cfg.RetryBaseDelay); err != nil {
+		return fmt.Errorf("invalid APP_LLM_RETRY_BASE_DELAY: %w", err)
+	} else {
+		cfg.RetryBaseDelay = delay
+	}
+
+	if delay, err := getEnvDuration("APP_LLM_RETRY_MAX_DELAY", cfg.RetryMaxDelay); err != nil {
+		return fmt.Errorf("invalid APP_LLM_RETRY_MAX_DELAY: %w", err)
+	} else {
+		cfg.RetryMaxDelay = delay
+	}
+
+	if threshold, err := getEnvInt("APP_LLM_BREAKER_THRESHOLD", cfg.BreakerThreshold); err != nil {
+		return fmt.Errorf("invalid APP_LLM_BREAKER_THRESHOLD: %w", err)
+	} else {
+		cfg.BreakerThreshold = threshold
+	}
+
+	if cooldown, err := getEnvDuration("APP_LLM_BREAKER_COOLDOWN", cfg.BreakerCooldown); err != nil {
+		return fmt.Errorf("invalid APP_LLM_BREAKER_COOLDOWN: %w", err)
+	} else {
+		cfg.BreakerCooldown = cooldown
+	}
+
+	// 回退链：APP_LLM_FALLBACK_CHAIN=openai,anthropic:claude-3-5-sonnet,local:llama3
+	cfg.FallbackChain = getEnvStringSlice("APP_LLM_FALLBACK_CHAIN", cfg.FallbackChain)
+	for _, target := range cfg.FallbackChain {
+		if name, _, _ := strings.Cut(target, ":"); strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid APP_LLM_FALLBACK_CHAIN: %q must be provider or provider:model", target)
+		}
+	}
+
+	// 提供商 API Key 和地址：APP_LLM_PROVIDERS_<NAME>=sk-...，APP_LLM_BASE_URLS_<NAME>=http://...
+	loadEnvMap("APP_LLM_PROVIDERS_", cfg.Providers)
+	loadEnvMap("APP_LLM_BASE_URLS_", cfg.BaseURLs)
+
+	return nil
+}
+
+// loadQuotaConfig 加载额度配置
+//
+// 套餐：APP_QUOTA_PLANS_<NAME>=daily_tokens=100000,monthly_tokens=2000000,daily_cost=1,monthly_cost=20
+// 只覆盖给出的字段，未知套餐会被新建（未给出的字段为 0，即不限制）。
+func loadQuotaConfig(cfg *QuotaConfig) error {
+	if enabled, err := getEnvBool("APP_QUOTA_ENABLED", cfg.Enabled); err != nil {
+		return fmt.Errorf("invalid APP_QUOTA_ENABLED: %w", err)
+	} else {
+		cfg.Enabled = enabled
+	}
+	cfg.DefaultPlan = getEnvString("APP_QUOTA_DEFAULT_PLAN", cfg.DefaultPlan)
+
+	specs := make(map[string]string)
+	loadEnvMap("APP_QUOTA_PLANS_", specs)
+	for name, spec := range specs {
+		plan, err := parseQuotaPlan(spec, cfg.Plans[name])
+		if err != nil {
+			return fmt.Errorf("invalid APP_QUOTA_PLANS_%s: %w", strings.ToUpper(name), err)
+		}
+		cfg.Plans[name] = plan
+	}
+
+	return nil
+}
+
+// parseQuotaPlan 解析 "key=value,key=value" 格式的套餐限额
+func parseQuotaPlan(spec string, plan QuotaPlanConfig) (QuotaPlanConfig, error) {
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return plan, fmt.Errorf("expected key=value, got '%s'", item)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "daily_tokens":
+			plan.DailyTokens, err = strconv.ParseInt(value, 10, 64)
+		case "monthly_tokens":
+			plan.MonthlyTokens, err = strconv.ParseInt(value, 10, 64)
+		case "daily_cost":
+			plan.DailyCost, err = strconv.ParseFloat(value, 64)
+		case "monthly_cost":
+			plan.MonthlyCost, err = strconv.ParseFloat(value, 64)
+		default:
+			return plan, fmt.Errorf("unknown key '%s'", key)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return plan, nil
+}
+
+// loadModerationConfig 加载内容安全配置
+func loadModerationConfig(cfg *ModerationConfig) error {
+	if enabled, err := getEnvBool("APP_MODERATION_ENABLED", cfg.Enabled); err != nil {
+		return fmt.Errorf("invalid APP_MODERATION_ENABLED: %w", err)
+	} else {
+		cfg.Enabled = enabled
+	}
+
+	cfg.Wordlist = getEnvStringSlice("APP_MODERATION_WORDLIST", cfg.Wordlist)
+	cfg.WordlistFile = getEnvString("APP_MODERATION_WORDLIST_FILE", cfg.WordlistFile)
+
+	actions := []struct {
+		key   string
+		value *string
+	}{
+		{"APP_MODERATION_WORDLIST_ACTION", &cfg.WordlistAction},
+		{"APP_MODERATION_PII_ACTION", &cfg.PIIAction},
+		{"APP_MODERATION_INJECTION_ACTION", &cfg.InjectionAction},
+	}
+	for _, a := range actions {
+		*a.value = getEnvString(a.key, *a.value)
+		switch *a.value {
+		case "block", "redact", "flag", "off":
+		default:
+			return fmt.Errorf("invalid %s: must be block, redact, flag or off, got %q", a.key, *a.value)
+		}
+	}
+
 	return nil
 }
 
@@ -245,6 +450,11 @@ func loadJWTConfig(cfg *JWTConfig) error {
 	return nil
 }
 
+// loadAdminConfig 加载管理员配置
+func loadAdminConfig(cfg *AdminConfig) {
+	cfg.Emails = getEnvStringSlice("APP_ADMIN_EMAILS", cfg.Emails)
+}
+
 // loadLoggingConfig 加载日志配置
 func loadLoggingConfig(cfg *LoggingConfig) error {
 	// 是否启用结构化日志
@@ -352,6 +562,21 @@ func getEnvString(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvStringSlice 读取逗号分隔的字符串列表环境变量，如果未设置则返回默认值
+func getEnvStringSlice(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getEnvInt 读取整数环境变量，如果未设置则返回默认值
 func getEnvInt(key string, defaultValue int) (int, error) {
 	if value := os.Getenv(key); value != "" {
@@ -411,3 +636,19 @@ func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, erro
 	}
 	return defaultValue, nil
 }
+
+// loadEnvMap 读取带前缀的环境变量到 map
+//
+// 键为去掉前缀后的小写名称，例如 APP_LLM_PROVIDERS_OPENAI=sk-... → "openai": "sk-..."
+func loadEnvMap(prefix string, dst map[string]string) {
+	for _, entry := range os.Environ() {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, prefix) || value == "" {
+			continue
+		}
+		name := strings.ToLower(strings.TrimPrefix(key, prefix))
+		if name != "" {
+			dst[name] = value
+		}
+	}
+}
@@ -208,6 +208,192 @@ func TestLoad_LoggingConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_LLMProviders(t *testing.T) {
+	// 设置 LLM 提供商相关环境变量
+	os.Setenv("APP_LLM_DEFAULT_PROVIDER", "local")
+	os.Setenv("APP_LLM_PROVIDERS_OPENAI", "sk-test")
+	os.Setenv("APP_LLM_BASE_URLS_LOCAL", "http://localhost:11434/v1")
+	defer func() {
+		os.Unsetenv("APP_LLM_DEFAULT_PROVIDER")
+		os.Unsetenv("APP_LLM_PROVIDERS_OPENAI")
+		os.Unsetenv("APP_LLM_BASE_URLS_LOCAL")
+	}()
+
+	// 加载配置
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	// 验证 LLM 配置
+	if cfg.LLM.DefaultProvider != "local" {
+		t.Errorf("Expected llm.default_provider = local, got %s", cfg.LLM.DefaultProvider)
+	}
+	if cfg.LLM.Providers["openai"] != "sk-test" {
+		t.Errorf("Expected llm.providers[openai] = sk-test, got %s", cfg.LLM.Providers["openai"])
+	}
+	if cfg.LLM.BaseURLs["local"] != "http://localhost:11434/v1" {
+		t.Errorf("Expected llm.base_urls[local] = http://localhost:11434/v1, got %s", cfg.LLM.BaseURLs["local"])
+	}
+}
+
+func TestLoad_LLMCache(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.LLM.CacheEnabled || cfg.LLM.CacheTTL != time.Hour {
+		t.Errorf("Expected llm cache enabled with ttl 1h, got %v %v", cfg.LLM.CacheEnabled, cfg.LLM.CacheTTL)
+	}
+
+	os.Setenv("APP_LLM_CACHE_ENABLED", "false")
+	os.Setenv("APP_LLM_CACHE_TTL", "24h")
+	defer func() {
+		os.Unsetenv("APP_LLM_CACHE_ENABLED")
+		os.Unsetenv("APP_LLM_CACHE_TTL")
+	}()
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.CacheEnabled {
+		t.Error("Expected llm.cache_enabled = false, got true")
+	}
+	if cfg.LLM.CacheTTL != 24*time.Hour {
+		t.Errorf("Expected llm.cache_ttl = 24h, got %v", cfg.LLM.CacheTTL)
+	}
+
+	os.Setenv("APP_LLM_CACHE_TTL", "0s")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with zero llm.cache_ttl")
+	}
+}
+
+func TestLoad_LLMTaskEnrichment(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.TaskEnrichment {
+		t.Error("Expected llm.task_enrichment disabled by default")
+	}
+
+	os.Setenv("APP_LLM_TASK_ENRICHMENT", "true")
+	defer os.Unsetenv("APP_LLM_TASK_ENRICHMENT")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.LLM.TaskEnrichment {
+		t.Error("Expected llm.task_enrichment = true, got false")
+	}
+
+	os.Setenv("APP_LLM_TASK_ENRICHMENT", "maybe")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with invalid APP_LLM_TASK_ENRICHMENT")
+	}
+}
+
+func TestLoad_QuotaPlans(t *testing.T) {
+	// 覆盖已有套餐的部分字段，并新增一个套餐
+	os.Setenv("APP_QUOTA_DEFAULT_PLAN", "team")
+	os.Setenv("APP_QUOTA_PLANS_FREE", "daily_tokens=5000")
+	os.Setenv("APP_QUOTA_PLANS_TEAM", "monthly_tokens=5000000, monthly_cost=50")
+	defer func() {
+		os.Unsetenv("APP_QUOTA_DEFAULT_PLAN")
+		os.Unsetenv("APP_QUOTA_PLANS_FREE")
+		os.Unsetenv("APP_QUOTA_PLANS_TEAM")
+	}()
+
+	// 加载配置
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	// 验证额度配置
+	if cfg.Quota.DefaultPlan != "team" {
+		t.Errorf("Expected quota.default_plan = team, got %s", cfg.Quota.DefaultPlan)
+	}
+	free := cfg.Quota.Plans["free"]
+	if free.DailyTokens != 5000 {
+		t.Errorf("Expected quota.plans.free.daily_tokens = 5000, got %d", free.DailyTokens)
+	}
+	if free.MonthlyTokens != 2_000_000 {
+		t.Errorf("Expected quota.plans.free.monthly_tokens to keep default, got %d", free.MonthlyTokens)
+	}
+	team := cfg.Quota.Plans["team"]
+	if team.MonthlyTokens != 5_000_000 || team.MonthlyCost != 50 || team.DailyTokens != 0 {
+		t.Errorf("Unexpected quota.plans.team: %+v", team)
+	}
+}
+
+func TestLoad_InvalidQuotaConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"unknown default plan", "APP_QUOTA_DEFAULT_PLAN", "platinum"},
+		{"malformed plan", "APP_QUOTA_PLANS_FREE", "daily_tokens"},
+		{"unknown plan key", "APP_QUOTA_PLANS_FREE", "hourly_tokens=10"},
+		{"negative limit", "APP_QUOTA_PLANS_FREE", "daily_cost=-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv(tt.key, tt.value)
+			defer os.Unsetenv(tt.key)
+
+			if _, err := Load(); err == nil {
+				t.Errorf("Expected error for %s=%s", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestLoad_ModerationConfig(t *testing.T) {
+	// 默认值
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !cfg.Moderation.Enabled || cfg.Moderation.WordlistAction != "block" ||
+		cfg.Moderation.PIIAction != "redact" || cfg.Moderation.InjectionAction != "flag" {
+		t.Errorf("Unexpected default moderation config: %+v", cfg.Moderation)
+	}
+
+	os.Setenv("APP_MODERATION_WORDLIST", "foo, bar* ,")
+	os.Setenv("APP_MODERATION_PII_ACTION", "off")
+	defer func() {
+		os.Unsetenv("APP_MODERATION_WORDLIST")
+		os.Unsetenv("APP_MODERATION_PII_ACTION")
+	}()
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(cfg.Moderation.Wordlist) != 2 || cfg.Moderation.Wordlist[1] != "bar*" {
+		t.Errorf("Expected moderation.wordlist = [foo bar*], got %v", cfg.Moderation.Wordlist)
+	}
+	if cfg.Moderation.PIIAction != "off" {
+		t.Errorf("Expected moderation.pii_action = off, got %s", cfg.Moderation.PIIAction)
+	}
+
+	os.Setenv("APP_MODERATION_INJECTION_ACTION", "warn")
+	defer os.Unsetenv("APP_MODERATION_INJECTION_ACTION")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with invalid APP_MODERATION_INJECTION_ACTION")
+	}
+}
+
 func TestLoad_MonitoringConfig(t *testing.T) {
 	// 设置 Monitoring 相关环境变量
 	os.Setenv("APP_MONITORING_METRICS_ENABLED", "true")
@@ -322,3 +508,182 @@ func TestLoad_InvalidMonitoringConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_LLMAgentMaxSteps(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.AgentMaxSteps != 8 {
+		t.Errorf("Expected llm.agent_max_steps = 8, got %d", cfg.LLM.AgentMaxSteps)
+	}
+
+	os.Setenv("APP_LLM_AGENT_MAX_STEPS", "3")
+	defer os.Unsetenv("APP_LLM_AGENT_MAX_STEPS")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.AgentMaxSteps != 3 {
+		t.Errorf("Expected llm.agent_max_steps = 3, got %d", cfg.LLM.AgentMaxSteps)
+	}
+
+	os.Setenv("APP_LLM_AGENT_MAX_STEPS", "0")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with APP_LLM_AGENT_MAX_STEPS=0")
+	}
+}
+
+func TestLoad_LLMEmbedding(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.EmbeddingProvider != "local" || cfg.LLM.VectorStore != "memory" {
+		t.Errorf("Expected local embeddings in memory store, got %q/%q", cfg.LLM.EmbeddingProvider, cfg.LLM.VectorStore)
+	}
+
+	os.Setenv("APP_LLM_EMBEDDING_PROVIDER", "openai")
+	os.Setenv("APP_LLM_EMBEDDING_MODEL", "text-embedding-3-small")
+	os.Setenv("APP_LLM_VECTOR_STORE", "pgvector")
+	defer os.Clearenv()
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.EmbeddingModel != "text-embedding-3-small" || cfg.LLM.VectorStore != "pgvector" {
+		t.Errorf("Unexpected embedding config: %+v", cfg.LLM)
+	}
+
+	os.Setenv("APP_LLM_EMBEDDING_MODEL", "")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail without APP_LLM_EMBEDDING_MODEL")
+	}
+
+	os.Setenv("APP_LLM_EMBEDDING_MODEL", "text-embedding-3-small")
+	os.Setenv("APP_LLM_VECTOR_STORE", "faiss")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with APP_LLM_VECTOR_STORE=faiss")
+	}
+}
+
+func TestLoad_LLMRetrievalTokenBudget(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.RetrievalTokenBudget != 2000 {
+		t.Errorf("Expected llm.retrieval_token_budget = 2000, got %d", cfg.LLM.RetrievalTokenBudget)
+	}
+
+	os.Setenv("APP_LLM_RETRIEVAL_TOKEN_BUDGET", "500")
+	defer os.Unsetenv("APP_LLM_RETRIEVAL_TOKEN_BUDGET")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.RetrievalTokenBudget != 500 {
+		t.Errorf("Expected llm.retrieval_token_budget = 500, got %d", cfg.LLM.RetrievalTokenBudget)
+	}
+
+	os.Setenv("APP_LLM_RETRIEVAL_TOKEN_BUDGET", "0")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with APP_LLM_RETRIEVAL_TOKEN_BUDGET=0")
+	}
+}
+
+func TestLoad_LLMContextReserveTokens(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.ContextReserveTokens != 1024 {
+		t.Errorf("Expected llm.context_reserve_tokens = 1024, got %d", cfg.LLM.ContextReserveTokens)
+	}
+
+	os.Setenv("APP_LLM_CONTEXT_RESERVE_TOKENS", "2048")
+	defer os.Unsetenv("APP_LLM_CONTEXT_RESERVE_TOKENS")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.ContextReserveTokens != 2048 {
+		t.Errorf("Expected llm.context_reserve_tokens = 2048, got %d", cfg.LLM.ContextReserveTokens)
+	}
+
+	os.Setenv("APP_LLM_CONTEXT_RESERVE_TOKENS", "0")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with APP_LLM_CONTEXT_RESERVE_TOKENS=0")
+	}
+}
+
+func TestLoad_LLMResilience(t *testing.T) {
+	os.Clearenv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.BreakerThreshold != 5 || cfg.LLM.BreakerCooldown != 30*time.Second {
+		t.Errorf("Expected llm breaker 5 failures / 30s, got %d / %v", cfg.LLM.BreakerThreshold, cfg.LLM.BreakerCooldown)
+	}
+	if len(cfg.LLM.FallbackChain) != 0 {
+		t.Errorf("Expected empty llm.fallback_chain, got %v", cfg.LLM.FallbackChain)
+	}
+
+	os.Setenv("APP_LLM_RETRY_BASE_DELAY", "200ms")
+	os.Setenv("APP_LLM_RETRY_MAX_DELAY", "10s")
+	os.Setenv("APP_LLM_BREAKER_THRESHOLD", "3")
+	os.Setenv("APP_LLM_BREAKER_COOLDOWN", "1m")
+	os.Setenv("APP_LLM_FALLBACK_CHAIN", "openai, anthropic:claude-3-5-sonnet ,local:llama3")
+	defer func() {
+		os.Unsetenv("APP_LLM_RETRY_BASE_DELAY")
+		os.Unsetenv("APP_LLM_RETRY_MAX_DELAY")
+		os.Unsetenv("APP_LLM_BREAKER_THRESHOLD")
+		os.Unsetenv("APP_LLM_BREAKER_COOLDOWN")
+		os.Unsetenv("APP_LLM_FALLBACK_CHAIN")
+	}()
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.LLM.RetryBaseDelay != 200*time.Millisecond || cfg.LLM.RetryMaxDelay != 10*time.Second {
+		t.Errorf("Expected llm retry delays 200ms / 10s, got %v / %v", cfg.LLM.RetryBaseDelay, cfg.LLM.RetryMaxDelay)
+	}
+	if cfg.LLM.BreakerThreshold != 3 || cfg.LLM.BreakerCooldown != time.Minute {
+		t.Errorf("Expected llm breaker 3 failures / 1m, got %d / %v", cfg.LLM.BreakerThreshold, cfg.LLM.BreakerCooldown)
+	}
+	expected := []string{"openai", "anthropic:claude-3-5-sonnet", "local:llama3"}
+	if len(cfg.LLM.FallbackChain) != len(expected) {
+		t.Fatalf("Expected llm.fallback_chain = %v, got %v", expected, cfg.LLM.FallbackChain)
+	}
+	for i := range expected {
+		if cfg.LLM.FallbackChain[i] != expected[i] {
+			t.Errorf("Expected llm.fallback_chain[%d] = %s, got %s", i, expected[i], cfg.LLM.FallbackChain[i])
+		}
+	}
+
+	os.Setenv("APP_LLM_FALLBACK_CHAIN", "openai,:gpt-4o")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with invalid APP_LLM_FALLBACK_CHAIN")
+	}
+
+	os.Setenv("APP_LLM_FALLBACK_CHAIN", "openai")
+	os.Setenv("APP_LLM_RETRY_MAX_DELAY", "100ms")
+	if _, err := Load(); err == nil {
+		t.Error("Expected Load() to fail with llm.retry_max_delay below llm.retry_base_delay")
+	}
+}
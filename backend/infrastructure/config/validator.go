@@ -38,6 +38,9 @@ func (v *Validator) Validate(config *Config) error {
 	// 验证 LLM 配置
 	v.validateLLM(&config.LLM)
 
+	// 验证额度配置
+	v.validateQuota(&config.Quota)
+
 	// 验证日志配置
 	v.validateLogging(&config.Logging)
 
@@ -142,6 +145,63 @@ func (v *Validator) validateLLM(config *LLMConfig) {
 	if config.MaxRetries < 0 {
 		v.addError("llm.max_retries cannot be negative")
 	}
+
+	if config.CatalogCacheTTL <= 0 {
+		v.addError("llm.catalog_cache_ttl must be positive")
+	}
+
+	if config.PromptCacheTTL <= 0 {
+		v.addError("llm.prompt_cache_ttl must be positive")
+	}
+
+	if config.CacheTTL <= 0 {
+		v.addError("llm.cache_ttl must be positive")
+	}
+
+	validStrategies := map[string]bool{
+		"":        true,
+		"latency": true,
+		"cost":    true,
+		"quality": true,
+		"random":  true,
+	}
+
+	if !validStrategies[config.RoutingStrategy] {
+		v.addError("llm.routing_strategy must be one of: latency, cost, quality, random")
+	}
+
+	if config.RetryBaseDelay <= 0 {
+		v.addError("llm.retry_base_delay must be positive")
+	}
+
+	if config.RetryMaxDelay < config.RetryBaseDelay {
+		v.addError("llm.retry_max_delay must be at least llm.retry_base_delay")
+	}
+
+	if config.BreakerThreshold < 0 {
+		v.addError("llm.breaker_threshold cannot be negative")
+	}
+
+	if config.BreakerThreshold > 0 && config.BreakerCooldown <= 0 {
+		v.addError("llm.breaker_cooldown must be positive when the circuit breaker is enabled")
+	}
+}
+
+// validateQuota 验证额度配置
+func (v *Validator) validateQuota(config *QuotaConfig) {
+	if !config.Enabled {
+		return
+	}
+
+	if _, ok := config.Plans[config.DefaultPlan]; !ok {
+		v.addError(fmt.Sprintf("quota.default_plan '%s' is not a configured plan", config.DefaultPlan))
+	}
+
+	for name, plan := range config.Plans {
+		if plan.DailyTokens < 0 || plan.MonthlyTokens < 0 || plan.DailyCost < 0 || plan.MonthlyCost < 0 {
+			v.addError(fmt.Sprintf("quota.plans.%s limits cannot be negative", name))
+		}
+	}
 }
 
 // validateLogging 验证日志配置
@@ -176,7 +176,8 @@ func getHTTPStatusCode(code string) int {
 
 	// 429 Too Many Requests
 	case strings.Contains(code, "RATE_LIMIT"),
-		strings.Contains(code, "TOO_FREQUENT"):
+		strings.Contains(code, "TOO_FREQUENT"),
+		code == "QUOTA_EXCEEDED":
 		return 429
 
 	// 500 Internal Server Error
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+)
+
+// UserInfo 管理员校验需要的用户信息
+type UserInfo struct {
+	Email         string
+	EmailVerified bool
+	Active        bool // 账号可用（未被封禁）
+}
+
+// UserLookup 按 ID 查询用户信息（由 bootstrap 适配 User 领域的仓储）
+//
+// 用户不存在时返回 nil, nil。
+type UserLookup interface {
+	LookupUser(ctx context.Context, userID string) (*UserInfo, error)
+}
+
+// UserLookupFunc 函数形式的 UserLookup
+type UserLookupFunc func(ctx context.Context, userID string) (*UserInfo, error)
+
+// LookupUser 实现 UserLookup
+func (f UserLookupFunc) LookupUser(ctx context.Context, userID string) (*UserInfo, error) {
+	return f(ctx, userID)
+}
+
+// AdminMiddleware 管理员中间件
+//
+// 必须在 AuthMiddleware 之后使用。管理员邮箱通过 APP_ADMIN_EMAILS 配置
+// （逗号分隔，不区分大小写）；未配置时所有请求都会被拒绝。
+//
+// JWT 中的邮箱只用于快速排除非管理员：命中白名单后按用户 ID 查询用户，
+// 要求当前邮箱仍在白名单中、邮箱已验证且用户未被封禁。
+// 否则任何人都可以用白名单中的邮箱注册（未验证）获得管理员权限。
+type AdminMiddleware struct {
+	emails map[string]struct{}
+	users  UserLookup
+}
+
+// NewAdminMiddleware 创建管理员中间件
+//
+// 参数：
+//   - emails: 管理员邮箱列表
+//   - users: 用户查询（User 仓储的适配）
+func NewAdminMiddleware(emails []string, users UserLookup) *AdminMiddleware {
+	set := make(map[string]struct{}, len(emails))
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" {
+			set[email] = struct{}{}
+		}
+	}
+	return &AdminMiddleware{emails: set, users: users}
+}
+
+// IsAdmin 判断邮箱是否在管理员白名单中
+func (m *AdminMiddleware) IsAdmin(email string) bool {
+	_, ok := m.emails[strings.ToLower(strings.TrimSpace(email))]
+	return ok
+}
+
+// Handle 处理管理员权限校验
+//
+// Example:
+//
+//	admin := r.Group("/admin", authMW.Handle(), adminMW.Handle())
+func (m *AdminMiddleware) Handle() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		email, _ := c.Get("email")
+		emailStr, _ := email.(string)
+		userID, _ := GetUserID(c)
+
+		if !m.IsAdmin(emailStr) || userID == "" {
+			forbidden(c)
+			return
+		}
+
+		user, err := m.users.LookupUser(ctx, userID)
+		if err != nil {
+			c.JSON(500, utils.H{
+				"error":   "INTERNAL_ERROR",
+				"message": "查询用户失败",
+			})
+			c.Abort()
+			return
+		}
+		if user == nil || !user.EmailVerified || !user.Active || !m.IsAdmin(user.Email) {
+			forbidden(c)
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
+
+func forbidden(c *app.RequestContext) {
+	c.JSON(403, utils.H{
+		"error":   "FORBIDDEN",
+		"message": "需要管理员权限",
+	})
+	c.Abort()
+}
@@ -11,7 +11,10 @@ func CORS() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-User-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-User-ID, X-LLM-Cache")
+		c.Header("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, "+
+			"X-Quota-Plan, X-Quota-Daily-Tokens-Remaining, X-Quota-Daily-Cost-Remaining, X-Quota-Daily-Reset, "+
+			"X-Quota-Monthly-Tokens-Remaining, X-Quota-Monthly-Cost-Remaining, X-Quota-Monthly-Reset")
 		c.Header("Access-Control-Max-Age", "3600")
 
 		if string(c.Method()) == "OPTIONS" {
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	llmservice "github.com/erweixin/go-genai-stack/backend/domains/llm/service"
+)
+
+// LLMCacheHeader 控制 LLM 响应缓存的请求头
+const LLMCacheHeader = "X-LLM-Cache"
+
+// LLMCacheBypass LLM 响应缓存跳过中间件
+//
+// 请求头 X-LLM-Cache: bypass 时，本次请求中的 LLM 调用不读也不写响应缓存
+// （例如用户点击"重新生成"）。其他值被忽略。
+func LLMCacheBypass() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if strings.EqualFold(strings.TrimSpace(string(c.GetHeader(LLMCacheHeader))), "bypass") {
+			ctx = llmservice.WithCacheBypass(ctx)
+		}
+		c.Next(ctx)
+	}
+}
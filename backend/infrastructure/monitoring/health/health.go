@@ -3,6 +3,7 @@ package health
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"time"
 
 	"github.com/erweixin/go-genai-stack/backend/infrastructure/config"
@@ -33,6 +34,9 @@ type CheckItem struct {
 	Latency time.Duration `json:"latency,omitempty"` // 延迟
 }
 
+// CheckFunc 自定义检查项
+type CheckFunc func(ctx context.Context) CheckItem
+
 // Checker 健康检查器
 type Checker struct {
 	startTime time.Time
@@ -40,6 +44,9 @@ type Checker struct {
 	db        *sql.DB
 	redis     *redis.Client
 	enabled   bool
+
+	mu     sync.RWMutex
+	custom map[string]CheckFunc // 非关键检查项（不影响总体状态）
 }
 
 // NewChecker 创建健康检查器
@@ -58,11 +65,24 @@ func NewChecker(cfg config.MonitoringConfig, db *sql.DB, redisClient *redis.Clie
 	}
 }
 
+// AddCheck 注册非关键检查项（同名覆盖）
+//
+// 非关键检查项显示在 checks 中，但 down 不影响总体状态（例如某个 LLM 提供商熔断时服务仍可回退）。
+func (c *Checker) AddCheck(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.custom == nil {
+		c.custom = make(map[string]CheckFunc)
+	}
+	c.custom[name] = fn
+}
+
 // Check 执行健康检查
 //
 // 检查项：
 //   - 数据库连接
 //   - Redis 连接
+//   - AddCheck 注册的非关键检查项
 //   - 系统运行时间
 func (c *Checker) Check(ctx context.Context) HealthCheck {
 	if !c.enabled {
@@ -96,6 +116,11 @@ func (c *Checker) Check(ctx context.Context) HealthCheck {
 		}
 	}
 
+	// 3. 非关键检查项
+	for name, fn := range c.customChecks() {
+		checks[name] = fn(ctx)
+	}
+
 	return HealthCheck{
 		Status:    overallStatus,
 		Timestamp: time.Now(),
@@ -105,6 +130,17 @@ func (c *Checker) Check(ctx context.Context) HealthCheck {
 	}
 }
 
+// customChecks 返回已注册检查项的副本（执行检查时不持有锁）
+func (c *Checker) customChecks() map[string]CheckFunc {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	checks := make(map[string]CheckFunc, len(c.custom))
+	for name, fn := range c.custom {
+		checks[name] = fn
+	}
+	return checks
+}
+
 // checkDatabase 检查数据库连接
 func (c *Checker) checkDatabase(ctx context.Context) CheckItem {
 	start := time.Now()
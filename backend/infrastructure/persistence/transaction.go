@@ -0,0 +1,144 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// DBTX *sql.DB 和 *sql.Tx 的公共接口
+//
+// Repository 通过 Conn(ctx, db) 获取 DBTX 执行 SQL：
+// ctx 中存在事务时使用事务，否则直接使用连接池。
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// TxManager 事务管理器接口（Unit of Work）
+//
+// 与数据库类型无关，事务通过 context.Context 传递：
+// fn 收到的 ctx 中携带事务，传给任何 Repository 都会自动在该事务中执行。
+type TxManager interface {
+	// WithinTx 在事务中执行 fn
+	//
+	// fn 返回错误或 panic 时回滚，否则提交。
+	// 如果 ctx 中已经存在事务，直接加入该事务（不会开启嵌套事务），
+	// 由最外层的 WithinTx 负责提交或回滚。
+	// 提交成功后执行 fn 中通过 AfterCommit 注册的回调。
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey context 中存放事务的 key
+type txKey struct{}
+
+// afterCommitKey context 中存放提交后回调的 key
+type afterCommitKey struct{}
+
+// SQLTxManager 基于 database/sql 的事务管理器
+//
+// 只依赖 *sql.DB，适用于 PostgreSQL、MySQL、SQLite 等所有驱动。
+type SQLTxManager struct {
+	db   *sql.DB
+	opts *sql.TxOptions
+}
+
+// NewTxManager 创建事务管理器
+//
+// Example:
+//
+//	txManager := persistence.NewTxManager(db)
+//	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+//	    if err := taskRepo.Create(ctx, parent); err != nil {
+//	        return err // 自动回滚
+//	    }
+//	    return taskRepo.Create(ctx, child) // 同一事务
+//	})
+func NewTxManager(db *sql.DB) *SQLTxManager {
+	return &SQLTxManager{db: db}
+}
+
+// NewTxManagerWithOptions 创建使用指定事务选项（隔离级别、只读）的事务管理器
+func NewTxManagerWithOptions(db *sql.DB, opts *sql.TxOptions) *SQLTxManager {
+	return &SQLTxManager{db: db, opts: opts}
+}
+
+// WithinTx 在事务中执行 fn
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	// 已在事务中：加入外层事务
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, m.opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// 确保事务被提交或回滚
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p) // 重新抛出 panic
+		}
+	}()
+
+	var hooks []func(ctx context.Context)
+	txCtx := context.WithValue(ContextWithTx(ctx, tx), afterCommitKey{}, &hooks)
+	if err := fn(txCtx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("failed to rollback transaction: %v (original error: %w)", rbErr, err)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// 回调收到不带事务的 ctx（事务已结束）
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	return nil
+}
+
+// AfterCommit 在 ctx 中的事务提交后执行 fn；不在 WithinTx 中时立即执行
+//
+// 用于发布领域事件：事务中发布的事件，同步的订阅者（或它启动的后台任务）
+// 可能在提交前读取数据而读不到；事务回滚时 fn 不执行。
+//
+// Example:
+//
+//	persistence.AfterCommit(ctx, func(ctx context.Context) {
+//	    eventBus.Publish(ctx, event)
+//	})
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(ctx context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// ContextWithTx 返回携带事务的 context
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext 从 context 中获取事务
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+// Conn 返回执行 SQL 的连接
+//
+// ctx 中存在事务时返回事务，否则返回 db。
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
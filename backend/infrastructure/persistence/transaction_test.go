@@ -0,0 +1,154 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLTxManager_WithinTx 测试事务的提交、回滚和嵌套加入
+func TestSQLTxManager_WithinTx(t *testing.T) {
+	t.Run("成功时提交，ctx 中携带事务", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO a`).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+			_, ok := TxFromContext(ctx)
+			assert.True(t, ok)
+			_, err := Conn(ctx, db).ExecContext(ctx, "INSERT INTO a VALUES (1)")
+			return err
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("返回错误时回滚", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		boom := errors.New("boom")
+		err = NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+			return boom
+		})
+
+		assert.ErrorIs(t, err, boom)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("panic 时回滚并重新抛出", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			_ = NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("嵌套调用加入外层事务", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		// 只开启和提交一次
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		m := NewTxManager(db)
+		err = m.WithinTx(context.Background(), func(outer context.Context) error {
+			outerTx, _ := TxFromContext(outer)
+			return m.WithinTx(outer, func(inner context.Context) error {
+				innerTx, _ := TxFromContext(inner)
+				assert.Same(t, outerTx, innerTx)
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("没有事务时使用连接池", func(t *testing.T) {
+		db, _, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		assert.Equal(t, DBTX(db), Conn(context.Background(), db))
+	})
+}
+
+// TestAfterCommit 测试提交后回调：提交后执行、回滚时不执行、不在事务中时立即执行
+func TestAfterCommit(t *testing.T) {
+	t.Run("提交后按注册顺序执行，ctx 不带事务", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var calls []string
+		m := NewTxManager(db)
+		err = m.WithinTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				_, inTx := TxFromContext(ctx)
+				assert.False(t, inTx)
+				assert.NoError(t, mock.ExpectationsWereMet()) // 已提交
+				calls = append(calls, "outer")
+			})
+			return m.WithinTx(ctx, func(ctx context.Context) error {
+				AfterCommit(ctx, func(context.Context) { calls = append(calls, "inner") })
+				assert.Empty(t, calls)
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"outer", "inner"}, calls)
+	})
+
+	t.Run("回滚时不执行", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		called := false
+		err = NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(context.Context) { called = true })
+			return errors.New("boom")
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("不在事务中时立即执行", func(t *testing.T) {
+		called := false
+		AfterCommit(context.Background(), func(context.Context) { called = true })
+		assert.True(t, called)
+	})
+}
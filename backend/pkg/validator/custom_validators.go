@@ -3,6 +3,7 @@ package validator
 import (
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -14,20 +15,44 @@ func IsValidUUID(fl validator.FieldLevel) bool {
 	return matched
 }
 
-// IsValidModelName 验证模型名称
+// ModelCatalog 模型目录（model_name、provider 规则使用）
+//
+// 由 catalog 领域的 CatalogService 实现，启动时通过 SetModelCatalog 设置。
+type ModelCatalog interface {
+	HasModel(name string) bool
+	HasProvider(name string) bool
+}
+
+// modelCatalogHolder 包装 ModelCatalog（atomic.Value 要求存储的具体类型一致）
+type modelCatalogHolder struct {
+	catalog ModelCatalog
+}
+
+var modelCatalog atomic.Value // modelCatalogHolder
+
+// providerNamePattern 未设置模型目录时提供商名称的格式
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9_-]{1,50}$`)
+
+// SetModelCatalog 设置 model_name、provider 规则使用的模型目录（传 nil 取消）
+func SetModelCatalog(c ModelCatalog) {
+	modelCatalog.Store(modelCatalogHolder{catalog: c})
+}
+
+// getModelCatalog 返回当前的模型目录（未设置时为 nil）
+func getModelCatalog() ModelCatalog {
+	holder, _ := modelCatalog.Load().(modelCatalogHolder)
+	return holder.catalog
+}
+
+// IsValidModelName 验证模型名称（模型目录中存在且已启用）
+//
+// 未设置模型目录时只检查格式（非空，最多 100 字符）。
 func IsValidModelName(fl validator.FieldLevel) bool {
-	model := fl.Field().String()
-	validModels := []string{
-		"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo",
-		"claude-3-opus", "claude-3-sonnet", "claude-3-haiku",
-		"claude-3.5-sonnet",
+	name := fl.Field().String()
+	if catalog := getModelCatalog(); catalog != nil {
+		return catalog.HasModel(name)
 	}
-	for _, valid := range validModels {
-		if model == valid {
-			return true
-		}
-	}
-	return false
+	return strings.TrimSpace(name) != "" && len(name) <= 100
 }
 
 // IsValidMessageRole 验证消息角色
@@ -42,21 +67,6 @@ func IsValidMessageRole(fl validator.FieldLevel) bool {
 	return false
 }
 
-// IsNotProfanity 验证是否包含敏感词
-func IsNotProfanity(fl validator.FieldLevel) bool {
-	content := fl.Field().String()
-	// 简单的敏感词检测（实际应用中应该使用更完善的敏感词库）
-	profanityWords := []string{"fuck", "shit", "damn"}
-	lowerContent := strings.ToLower(content)
-
-	for _, word := range profanityWords {
-		if strings.Contains(lowerContent, word) {
-			return false
-		}
-	}
-	return true
-}
-
 // IsValidTokenCount 验证 Token 数量
 func IsValidTokenCount(fl validator.FieldLevel) bool {
 	count := fl.Field().Int()
@@ -87,18 +97,29 @@ func IsValidStrategy(fl validator.FieldLevel) bool {
 	return false
 }
 
-// IsValidProvider 验证提供商名称
-func IsValidProvider(fl validator.FieldLevel) bool {
-	provider := fl.Field().String()
-	validProviders := []string{"openai", "anthropic", "local", "eino"}
-	for _, valid := range validProviders {
-		if provider == valid {
+// IsValidContextStrategy 验证对话的上下文策略
+func IsValidContextStrategy(fl validator.FieldLevel) bool {
+	strategy := fl.Field().String()
+	validStrategies := []string{"truncate", "summarize", "sliding_window"}
+	for _, valid := range validStrategies {
+		if strategy == valid {
 			return true
 		}
 	}
 	return false
 }
 
+// IsValidProvider 验证提供商名称（模型目录中有该提供商已启用的模型）
+//
+// 未设置模型目录时只检查格式（小写字母、数字、-、_）。
+func IsValidProvider(fl validator.FieldLevel) bool {
+	provider := fl.Field().String()
+	if catalog := getModelCatalog(); catalog != nil {
+		return catalog.HasProvider(provider)
+	}
+	return providerNamePattern.MatchString(provider)
+}
+
 // IsValidConversationTitle 验证对话标题
 func IsValidConversationTitle(fl validator.FieldLevel) bool {
 	title := fl.Field().String()
@@ -130,11 +151,11 @@ func RegisterAllCustomValidators(v *validator.Validate) {
 	v.RegisterValidation("uuid", IsValidUUID)
 	v.RegisterValidation("model_name", IsValidModelName)
 	v.RegisterValidation("message_role", IsValidMessageRole)
-	v.RegisterValidation("not_profanity", IsNotProfanity)
 	v.RegisterValidation("token_count", IsValidTokenCount)
 	v.RegisterValidation("temperature", IsValidTemperature)
 	v.RegisterValidation("top_p", IsValidTopP)
 	v.RegisterValidation("strategy", IsValidStrategy)
+	v.RegisterValidation("context_strategy", IsValidContextStrategy)
 	v.RegisterValidation("provider", IsValidProvider)
 	v.RegisterValidation("conversation_title", IsValidConversationTitle)
 	v.RegisterValidation("pagination_limit", IsValidPaginationLimit)
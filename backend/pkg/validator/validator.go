@@ -58,7 +58,9 @@ func New() Validator {
 
 	cv := &customValidator{validate: v}
 
-	// 注册自定义验证规则
+	// 注册领域通用验证规则（conversation_title、pagination_limit 等），
+	// 再注册下面的自定义规则（同名时以后者为准）
+	RegisterAllCustomValidators(v)
 	cv.registerCustomValidations()
 
 	return cv
@@ -98,22 +100,6 @@ func (c *customValidator) RegisterValidation(tag string, fn ValidationFunc) erro
 func (c *customValidator) registerCustomValidations() {
 	// 注册自定义验证规则
 
-	// model_name: 验证 LLM 模型名称
-	c.validate.RegisterValidation("model_name", func(fl validator.FieldLevel) bool {
-		model := fl.Field().String()
-		validModels := []string{
-			"gpt-4o", "gpt-4o-mini", "gpt-4-turbo", "gpt-3.5-turbo",
-			"claude-3-opus", "claude-3-sonnet", "claude-3-haiku",
-			"claude-3.5-sonnet",
-		}
-		for _, valid := range validModels {
-			if model == valid {
-				return true
-			}
-		}
-		return false
-	})
-
 	// conversation_id: 验证对话 ID 格式
 	c.validate.RegisterValidation("conversation_id", func(fl validator.FieldLevel) bool {
 		id := fl.Field().String()
@@ -188,11 +174,23 @@ func getErrorMessage(err validator.FieldError) string {
 	case "oneof":
 		return fmt.Sprintf("%s must be one of: %s", field, param)
 	case "model_name":
-		return fmt.Sprintf("%s is not a valid model name", field)
+		return fmt.Sprintf("%s is not an enabled model in the catalog", field)
+	case "provider":
+		return fmt.Sprintf("%s is not a provider in the model catalog", field)
 	case "message_role":
 		return fmt.Sprintf("%s must be one of: user, assistant, system", field)
 	case "token_count":
 		return fmt.Sprintf("%s must be between 0 and 1000000", field)
+	case "conversation_title":
+		return fmt.Sprintf("%s must be non-blank and at most 200 characters", field)
+	case "strategy":
+		return fmt.Sprintf("%s must be one of: latency, cost, quality, random", field)
+	case "context_strategy":
+		return fmt.Sprintf("%s must be one of: truncate, summarize, sliding_window", field)
+	case "pagination_limit":
+		return fmt.Sprintf("%s must be between 1 and 100", field)
+	case "pagination_offset":
+		return fmt.Sprintf("%s must be greater than or equal to 0", field)
 	default:
 		return fmt.Sprintf("%s failed validation for '%s'", field, tag)
 	}
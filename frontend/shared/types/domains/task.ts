@@ -25,6 +25,7 @@ export interface CreateTaskRequest {
   priority?: TaskPriority
   due_date?: string // ISO 8601 格式
   tags?: string[]
+  parent_id?: string // 父任务 ID（创建子任务）
 }
 
 /**
@@ -89,6 +90,8 @@ export interface GetTaskResponse {
   created_at: string // ISO 8601 格式
   updated_at: string // ISO 8601 格式
   completed_at?: string // ISO 8601 格式
+  parent_id?: string // 父任务 ID（仅子任务）
+  hidden_until?: string // 推迟到的时间（ISO 8601 格式，仅推迟中的任务）
 }
 
 /**
@@ -102,9 +105,11 @@ export interface ListTasksRequest {
   due_date_from?: string // YYYY-MM-DD 格式
   due_date_to?: string // YYYY-MM-DD 格式
   keyword?: string
+  include_snoozed?: boolean // 是否包含推迟中的任务（默认 false）
 
   // 排序参数
-  sort_by?: 'created_at' | 'due_date' | 'priority'
+  sort_by?: TaskSortField
+  sort?: TaskSortField // sort_by 的简写
   sort_order?: 'asc' | 'desc'
 
   // 分页参数
@@ -125,6 +130,8 @@ export interface TaskItem {
   tags: string[]
   created_at: string // ISO 8601 格式
   completed_at?: string // ISO 8601 格式（仅完成的任务）
+  urgency?: number // 紧急度（仅按紧急度排序或推荐任务时返回）
+  hidden_until?: string // 推迟到的时间（ISO 8601 格式，仅推迟中的任务）
 }
 
 /**
@@ -138,6 +145,146 @@ export interface ListTasksResponse {
   has_more: boolean
 }
 
+/**
+ * 任务排序字段（urgency 按紧急度排序）
+ */
+export type TaskSortField = 'created_at' | 'due_date' | 'priority' | 'urgency'
+
+// ============================================
+// Urgency Types
+// ============================================
+
+/**
+ * 获取推荐任务请求
+ */
+export interface NextTasksRequest {
+  limit?: number // 默认 5，最大 50
+}
+
+/**
+ * 获取推荐任务响应（按紧急度从高到低）
+ */
+export interface NextTasksResponse {
+  tasks: TaskItem[]
+}
+
+/**
+ * 紧急度系数响应
+ */
+export interface UrgencyCoefficientsResponse {
+  priority_high: number
+  priority_medium: number
+  priority_low: number
+  due: number
+  age: number
+  age_max_days: number
+  blocked: number
+  active: number
+  tags: number
+  tag_boosts: Record<string, number>
+  is_default: boolean
+}
+
+/**
+ * 更新紧急度系数请求（所有字段可选）
+ */
+export interface UpdateUrgencyCoefficientsRequest {
+  priority_high?: number
+  priority_medium?: number
+  priority_low?: number
+  due?: number
+  age?: number
+  age_max_days?: number
+  blocked?: number
+  active?: number
+  tags?: number
+  tag_boosts?: Record<string, number> // 提供时整体替换
+  reset?: boolean // 恢复默认系数
+}
+
+// ============================================
+// Snooze Types
+// ============================================
+
+/**
+ * 推迟任务请求（响应同 GetTaskResponse）
+ */
+export interface SnoozeTaskRequest {
+  until: string // 推迟到的时间（ISO 8601 格式，必须晚于当前时间）
+}
+
+// ============================================
+// Task Template Types
+// ============================================
+
+/**
+ * 模板子任务定义
+ */
+export interface SubtaskTemplateItem {
+  title_pattern: string
+  description?: string
+  priority?: TaskPriority // 为空时继承模板优先级
+  due_offset?: string // Go duration 格式，如 "24h"
+}
+
+/**
+ * 创建/更新模板请求
+ */
+export interface TemplateRequest {
+  name: string
+  title_pattern: string // 支持 {{date}}、{{time}} 和自定义变量
+  description?: string
+  priority?: TaskPriority
+  tags?: string[]
+  due_offset?: string // Go duration 格式，如 "72h"
+  subtasks?: SubtaskTemplateItem[]
+}
+
+/**
+ * 模板详情响应
+ */
+export interface TemplateResponse {
+  template_id: string
+  name: string
+  title_pattern: string
+  description: string
+  priority: TaskPriority
+  tags: string[]
+  due_offset?: string
+  subtasks: SubtaskTemplateItem[]
+  created_at: string // ISO 8601 格式
+  updated_at: string // ISO 8601 格式
+}
+
+/**
+ * 列出模板响应
+ */
+export interface ListTemplatesResponse {
+  templates: TemplateResponse[]
+}
+
+/**
+ * 删除模板响应
+ */
+export interface DeleteTemplateResponse {
+  success: boolean
+}
+
+/**
+ * 实例化模板请求
+ */
+export interface InstantiateTemplateRequest {
+  variables?: Record<string, string>
+}
+
+/**
+ * 实例化模板响应
+ */
+export interface InstantiateTemplateResponse {
+  task: GetTaskResponse
+  subtasks: GetTaskResponse[]
+}
+
 /**
  * 错误响应
  */